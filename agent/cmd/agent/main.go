@@ -26,7 +26,9 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/lease"
 	agentscheme "github.com/stolostron/multicluster-global-hub/agent/pkg/scheme"
 	specController "github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
 	statusController "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
 	"github.com/stolostron/multicluster-global-hub/pkg/jobs"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -137,9 +139,23 @@ func parseFlags() *config.AgentConfig {
 		"The goroutine number to propagate the bundles on managed cluster.")
 	pflag.BoolVar(&agentConfig.SpecEnforceHohRbac, "enforce-hoh-rbac", false,
 		"enable hoh RBAC or not, default false")
+	pflag.DurationVar(&agentConfig.SpecDriftDetectionInterval, "spec-drift-detection-interval", 0,
+		"The interval to detect drift of the global resources on the hub, 0 to disable the drift detection")
+	pflag.BoolVar(&agentConfig.SpecDriftCorrection, "spec-drift-correction", false,
+		"correct the drift of the global resources on the hub or only report it, default false")
 	pflag.StringVar(&agentConfig.TransportConfig.MessageCompressionType,
 		"transport-message-compression-type", "gzip",
 		"The message compression type for transport layer, 'gzip' or 'no-op'.")
+	pflag.StringVar(&agentConfig.TransportBundleEncoding, "transport-bundle-encoding", encoding.JSON,
+		"The encoding of the status bundles, 'json' or 'protobuf', the bundles which can't be encoded as protobuf "+
+			"fall back to json, and so do all the bundles until the manager advertises protobuf")
+	pflag.IntVar(&agentConfig.TransportOutboxSize, "transport-outbox-size", 1000,
+		"The max number of the status messages queued when the transport is unavailable, 0 to disable the outbox")
+	pflag.IntVar(&agentConfig.TransportOutboxMaxBytes, "transport-outbox-max-bytes", 64*1024*1024,
+		"The max size in bytes of the payload of the queued status messages, 0 to only limit the number of them")
+	pflag.StringVar(&agentConfig.TransportOutboxDir, "transport-outbox-dir", "",
+		"The directory to persist the queued status messages, e.g. a mounted volume, the messages are kept in "+
+			"memory if it's empty")
 	pflag.IntVar(&agentConfig.StatusDeltaCountSwitchFactor,
 		"status-delta-count-switch-factor", 100,
 		"default with 100.")
@@ -175,6 +191,18 @@ func completeConfig(agentConfig *config.AgentConfig) error {
 		return fmt.Errorf("flag consumer-worker-pool-size should be in the scope [1, 100]")
 	}
 
+	if _, err := encoding.ContentType(agentConfig.TransportBundleEncoding); err != nil {
+		return fmt.Errorf("flag transport-bundle-encoding is invalid - %w", err)
+	}
+
+	if agentConfig.TransportOutboxSize < 0 {
+		return fmt.Errorf("flag transport-outbox-size %d must not be negative", agentConfig.TransportOutboxSize)
+	}
+	if agentConfig.TransportOutboxMaxBytes < 0 {
+		return fmt.Errorf("flag transport-outbox-max-bytes %d must not be negative",
+			agentConfig.TransportOutboxMaxBytes)
+	}
+
 	if agentConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB > producer.MaxMessageSizeLimit {
 		return fmt.Errorf("flag kafka-message-size-limit %d must not exceed %d",
 			agentConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, producer.MaxMessageSizeLimit)
@@ -253,13 +281,18 @@ func createManager(ctx context.Context, restConfig *rest.Config, agentConfig *co
 		}
 		setupLog.Info("start agent with incarnation version", "version", incarnation)
 
+		// the spec syncers report the applied status of the received resources into the bundle,
+		// and the status controller sends it back to the global hub
+		appliedStatusBundle := appliedstatus.NewBundle(agentConfig.LeafHubName, incarnation)
+
 		// add spec controllers
-		if err := specController.AddToManager(mgr, agentConfig); err != nil {
+		if err := specController.AddToManager(mgr, agentConfig, appliedStatusBundle); err != nil {
 			return nil, fmt.Errorf("failed to add spec syncer: %w", err)
 		}
 		setupLog.Info("add spec controllers to manager")
 
-		if err := statusController.AddControllers(ctx, mgr, agentConfig, incarnation); err != nil {
+		if err := statusController.AddControllers(ctx, mgr, agentConfig, incarnation,
+			appliedStatusBundle); err != nil {
 			return nil, fmt.Errorf("failed to add status syncer: %w", err)
 		}
 
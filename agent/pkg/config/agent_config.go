@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -10,10 +12,16 @@ type AgentConfig struct {
 	PodNameSpace                 string
 	SpecWorkPoolSize             int
 	SpecEnforceHohRbac           bool
+	SpecDriftDetectionInterval   time.Duration
+	SpecDriftCorrection          bool
 	StatusDeltaCountSwitchFactor int
 	TransportConfig              *transport.TransportConfig
 	ElectionConfig               *commonobjects.LeaderElectionConfig
 	Terminating                  bool
 	KubeEventExporterConfigPath  string
 	MetricsAddress               string
+	TransportBundleEncoding      string
+	TransportOutboxSize          int
+	TransportOutboxMaxBytes      int
+	TransportOutboxDir           string
 }
@@ -0,0 +1,74 @@
+package enhancers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// GlobalResourceEventEnhancer adds the id of the resource on the global hub to the events of the resources which
+// are propagated from the global hub, e.g. Placement, PlacementDecision, Subscription and Application events.
+type GlobalResourceEventEnhancer struct {
+	runtimeClient client.Client
+	log           logr.Logger
+}
+
+func NewGlobalResourceEventEnhancer(runtimeClient client.Client) *GlobalResourceEventEnhancer {
+	return &GlobalResourceEventEnhancer{
+		runtimeClient: runtimeClient,
+		log:           ctrl.Log.WithName("global-resource-event-enhancer"),
+	}
+}
+
+func (g *GlobalResourceEventEnhancer) Enhance(ctx context.Context, event *kube.EnhancedEvent) {
+	if event.InvolvedObject.Labels == nil {
+		event.InvolvedObject.Labels = make(map[string]string)
+	}
+
+	objectReference := event.InvolvedObject.ObjectReference
+	// the placement decision is created for the placement, which is the resource propagated from the global hub
+	if objectReference.Kind == constants.PlacementDecisionKind {
+		placementName, ok := event.InvolvedObject.Labels[clusterv1beta1.PlacementLabel]
+		if !ok {
+			return
+		}
+		objectReference = corev1.ObjectReference{
+			APIVersion: clusterv1beta1.GroupVersion.String(),
+			Kind:       constants.PlacementKind,
+			Namespace:  objectReference.Namespace,
+			Name:       placementName,
+		}
+	} else if id, ok := event.InvolvedObject.Annotations[constants.OriginOwnerReferenceAnnotation]; ok {
+		event.InvolvedObject.Labels[constants.EventGlobalResourceIdLabelKey] = id
+		return
+	}
+
+	// the unstructured objects are read from the api server, the agent cluster role grants get on the placements,
+	// subscriptions and applications
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(objectReference.APIVersion)
+	obj.SetKind(objectReference.Kind)
+	if err := g.runtimeClient.Get(ctx, client.ObjectKey{
+		Namespace: objectReference.Namespace,
+		Name:      objectReference.Name,
+	}, obj); err != nil {
+		g.log.Error(err, "failed to get the involved object", "kind", objectReference.Kind,
+			"namespace", objectReference.Namespace, "name", objectReference.Name)
+		return
+	}
+
+	// the resource is created on the regional hub
+	id, ok := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+	if !ok {
+		return
+	}
+	event.InvolvedObject.Labels[constants.EventGlobalResourceIdLabelKey] = id
+}
@@ -0,0 +1,41 @@
+package enhancers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// ManagedClusterEventEnhancer adds the cluster id to the lifecycle events of the managed clusters,
+// e.g. joined, unavailable and upgrade events.
+type ManagedClusterEventEnhancer struct {
+	runtimeClient client.Client
+	log           logr.Logger
+}
+
+func NewManagedClusterEventEnhancer(runtimeClient client.Client) *ManagedClusterEventEnhancer {
+	return &ManagedClusterEventEnhancer{
+		runtimeClient: runtimeClient,
+		log:           ctrl.Log.WithName("managed-cluster-event-enhancer"),
+	}
+}
+
+func (m *ManagedClusterEventEnhancer) Enhance(ctx context.Context, event *kube.EnhancedEvent) {
+	if event.InvolvedObject.Labels == nil {
+		event.InvolvedObject.Labels = make(map[string]string)
+	}
+
+	clusterName := event.InvolvedObject.Name
+	clusterId, err := helper.GetClusterId(ctx, m.runtimeClient, clusterName)
+	if err != nil {
+		m.log.Error(err, "failed to get cluster id", "clusterName", clusterName)
+		return
+	}
+	event.InvolvedObject.Labels[constants.ManagedClusterEventClusterIdLabelKey] = clusterId
+}
@@ -11,12 +11,18 @@ import (
 	"github.com/resmoio/kubernetes-event-exporter/pkg/metrics"
 	"github.com/rs/zerolog"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/event/enhancers"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -28,8 +34,8 @@ type EventEnhancer interface {
 type EventExporter interface {
 	// Start starts the event exporter and make sure it can be added to the manager
 	Start(ctx context.Context) error
-	// RegisterEnhancer registers an event enhancer
-	RegisterEnhancer(enhancer EventEnhancer)
+	// RegisterEnhancer registers an event enhancer for the events of the given apiVersion and kind
+	RegisterEnhancer(gvk schema.GroupVersionKind, enhancer EventEnhancer)
 }
 
 type eventExporter struct {
@@ -38,7 +44,7 @@ type eventExporter struct {
 	runtimeClient   client.Client
 	leafHubName     string
 	log             logr.Logger
-	enhancers       map[string]EventEnhancer
+	enhancers       map[schema.GroupVersionKind]EventEnhancer
 }
 
 func AddEventExporter(mgr ctrl.Manager, eventConfigFile string, leafHubName string) error {
@@ -48,18 +54,39 @@ func AddEventExporter(mgr ctrl.Manager, eventConfigFile string, leafHubName stri
 		leafHubName:     leafHubName,
 		eventConfigFile: eventConfigFile,
 		log:             ctrl.Log.WithName("event-exporter"),
-		enhancers:       make(map[string]EventEnhancer),
+		enhancers:       make(map[schema.GroupVersionKind]EventEnhancer),
 	}
 
 	// add policy event enhancer
-	eventExporter.RegisterEnhancer(policyv1.Kind,
+	eventExporter.RegisterEnhancer(policyv1.SchemeGroupVersion.WithKind(policyv1.Kind),
 		enhancers.NewPolicyEventEnhancer(eventExporter.runtimeClient))
 
+	// add managed cluster event enhancer
+	eventExporter.RegisterEnhancer(clusterv1.GroupVersion.WithKind(constants.ManagedClusterKind),
+		enhancers.NewManagedClusterEventEnhancer(eventExporter.runtimeClient))
+
+	// add placement and application event enhancers
+	globalResourceEventEnhancer := enhancers.NewGlobalResourceEventEnhancer(eventExporter.runtimeClient)
+	for _, gvk := range []schema.GroupVersionKind{
+		clusterv1beta1.GroupVersion.WithKind(constants.PlacementKind),
+		clusterv1beta1.GroupVersion.WithKind(constants.PlacementDecisionKind),
+		appsubv1.SchemeGroupVersion.WithKind(constants.SubscriptionKind),
+		applicationv1beta1.GroupVersion.WithKind(constants.ApplicationKind),
+	} {
+		eventExporter.RegisterEnhancer(gvk, globalResourceEventEnhancer)
+	}
+
 	return mgr.Add(eventExporter)
 }
 
-func (e *eventExporter) RegisterEnhancer(eventKey string, enhancer EventEnhancer) {
-	e.enhancers[eventKey] = enhancer
+func (e *eventExporter) RegisterEnhancer(gvk schema.GroupVersionKind, enhancer EventEnhancer) {
+	e.enhancers[gvk] = enhancer
+}
+
+// enhancerOf returns the enhancer registered for the apiVersion and kind of the involved object of the event, the
+// kinds of the other groups with the same name, e.g. the argo application, aren't enhanced.
+func (e *eventExporter) enhancerOf(event *kube.EnhancedEvent) EventEnhancer {
+	return e.enhancers[schema.FromAPIVersionAndKind(event.InvolvedObject.APIVersion, event.InvolvedObject.Kind)]
 }
 
 func (e *eventExporter) Start(ctx context.Context) error {
@@ -93,7 +120,7 @@ func (e *eventExporter) Start(ctx context.Context) error {
 	engine := exporter.NewEngine(&cfg, &exporter.ChannelBasedReceiverRegistry{MetricsStore: metricsStore})
 	onEvent := func(event *kube.EnhancedEvent) {
 		// note that per code this value is not set anywhere on the kubernetes side
-		enhancer := e.enhancers[event.InvolvedObject.Kind]
+		enhancer := e.enhancerOf(event)
 		if enhancer != nil {
 			enhancer.Enhance(ctx, event)
 		}
@@ -4,9 +4,15 @@ import (
 	"testing"
 
 	"github.com/resmoio/kubernetes-event-exporter/pkg/exporter"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
 	"github.com/resmoio/kubernetes-event-exporter/pkg/sinks"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/event/enhancers"
 )
 
 func TestEventExporter_ValidateEventConfig(t *testing.T) {
@@ -38,3 +44,20 @@ func TestEventExporter_ValidateEventConfig(t *testing.T) {
 	assert.Equal(t, "", cfg.Receivers[0].Kafka.TLS.CertFile)
 	assert.Equal(t, "", cfg.Receivers[0].Kafka.TLS.KeyFile)
 }
+
+func TestEventExporter_EnhancerOf(t *testing.T) {
+	applicationEnhancer := enhancers.NewGlobalResourceEventEnhancer(nil)
+	eventExporter := &eventExporter{enhancers: map[schema.GroupVersionKind]EventEnhancer{
+		applicationv1beta1.GroupVersion.WithKind("Application"): applicationEnhancer,
+	}}
+
+	newEvent := func(apiVersion, kind string) *kube.EnhancedEvent {
+		return &kube.EnhancedEvent{InvolvedObject: kube.EnhancedObjectReference{
+			ObjectReference: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind},
+		}}
+	}
+	assert.Equal(t, applicationEnhancer, eventExporter.enhancerOf(newEvent("app.k8s.io/v1beta1", "Application")))
+	// the argo application has the same kind in another group
+	assert.Nil(t, eventExporter.enhancerOf(newEvent("argoproj.io/v1alpha1", "Application")))
+	assert.Nil(t, eventExporter.enhancerOf(newEvent("app.k8s.io/v1beta1", "ApplicationSet")))
+}
@@ -4,14 +4,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// LabelsField presents a "f:labels" field subfield of metadataField.
-type LabelsField struct {
-	Labels map[string]struct{} `json:"f:labels"`
+// MetadataField presents a "f:metadata" field subfield of v1.FieldsV1 with its "f:labels" and "f:annotations"
+// subfields.
+type MetadataField struct {
+	Labels      map[string]struct{} `json:"f:labels"`
+	Annotations map[string]struct{} `json:"f:annotations,omitempty"`
 }
 
-// MetadataField presents a "f:metadata" field subfield of v1.FieldsV1.
-type MetadataField struct {
-	LabelsField `json:"f:metadata"`
+// SpecField presents a "f:spec" field subfield of v1.FieldsV1 with its "f:taints" subfield.
+type SpecField struct {
+	Taints struct{} `json:"f:taints"`
+}
+
+// ObjectFields presents the metadata and spec fields of v1.FieldsV1.
+type ObjectFields struct {
+	Metadata MetadataField `json:"f:metadata"`
+	Spec     *SpecField    `json:"f:spec,omitempty"`
 }
 
 // HasAnnotation returns a bool if the given annotation exists in annotations.
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
@@ -36,6 +39,72 @@ func UpdateObject(ctx context.Context, k8sClient client.Client, obj *unstructure
 	return nil
 }
 
+// DryRunUpdateObject performs a server-side dry-run apply of the given object and returns the would-be changes of the
+// live object as a json merge patch, or empty string if applying the object wouldn't change anything.
+func DryRunUpdateObject(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) (string, error) {
+	liveObject := &unstructured.Unstructured{}
+	liveObject.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), liveObject); err != nil &&
+		!apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get object - %w", err)
+	}
+
+	objectBytes, err := obj.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to dry-run update object - %w", err)
+	}
+
+	forceChanges := true
+	dryRunObject := obj.DeepCopy()
+	if err := k8sClient.Patch(ctx, dryRunObject, client.RawPatch(types.ApplyPatchType, objectBytes),
+		&client.PatchOptions{
+			FieldManager: controllerName,
+			Force:        &forceChanges,
+			DryRun:       []string{metav1.DryRunAll},
+		}); err != nil {
+		return "", fmt.Errorf("failed to dry-run update object - %w", err)
+	}
+
+	return diffObjects(liveObject, dryRunObject)
+}
+
+// diffObjects returns the json merge patch from the original object to the modified object, ignoring the status
+// and the metadata fields maintained by the api server.
+func diffObjects(original, modified *unstructured.Unstructured) (string, error) {
+	originalBytes, err := cleanObject(original).MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	modifiedBytes, err := cleanObject(modified).MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	patch, err := jsonpatch.CreateMergePatch(originalBytes, modifiedBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff objects - %w", err)
+	}
+	if string(patch) == "{}" {
+		return "", nil
+	}
+
+	return string(patch), nil
+}
+
+func cleanObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	clone := obj.DeepCopy()
+	if len(clone.Object) == 0 {
+		return clone // the object doesn't exist
+	}
+	unstructured.RemoveNestedField(clone.Object, "status")
+	clone.SetManagedFields(nil)
+	clone.SetResourceVersion("")
+	clone.SetGeneration(0)
+	clone.SetUID("")
+	clone.SetCreationTimestamp(metav1.Time{})
+	return clone
+}
+
 // DeleteObject tries to delete the given object from k8s. returns error and true/false if object was deleted or not.
 func DeleteObject(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) (bool, error) {
 	if err := k8sClient.Delete(ctx, obj); err != nil {
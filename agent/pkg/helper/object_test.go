@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiffObjects(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy.open-cluster-management.io/v1",
+		"kind":       "Policy",
+		"metadata": map[string]interface{}{
+			"name":            "policy-limitrange",
+			"namespace":       "default",
+			"resourceVersion": "1234",
+			"uid":             "a789c8c1-137b-4b78-9412-9f101b08cc91",
+		},
+		"spec": map[string]interface{}{
+			"disabled":          false,
+			"remediationAction": "inform",
+		},
+		"status": map[string]interface{}{
+			"compliant": "Compliant",
+		},
+	}}
+
+	// only the server maintained fields are different
+	modified := live.DeepCopy()
+	modified.SetResourceVersion("1235")
+	unstructured.RemoveNestedField(modified.Object, "status")
+	diff, err := diffObjects(live, modified)
+	assert.NoError(t, err)
+	assert.Equal(t, "", diff)
+
+	// the spec is changed
+	assert.NoError(t, unstructured.SetNestedField(modified.Object, "enforce", "spec", "remediationAction"))
+	diff, err = diffObjects(live, modified)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"spec":{"remediationAction":"enforce"}}`, diff)
+
+	// the object doesn't exist yet
+	diff, err = diffObjects(&unstructured.Unstructured{}, modified)
+	assert.NoError(t, err)
+	assert.Contains(t, diff, `"remediationAction":"enforce"`)
+}
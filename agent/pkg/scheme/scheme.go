@@ -10,6 +10,7 @@ import (
 	mchv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
@@ -22,6 +23,8 @@ import (
 	appsubv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	appv1beta1 "sigs.k8s.io/application/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 )
 
 // AddToScheme adds all the resources to be processed to the Scheme.
@@ -34,6 +37,7 @@ func AddToScheme(runtimeScheme *runtime.Scheme) error {
 		operatorv1.AddToScheme,
 		apiregistrationv1.AddToScheme,
 		routev1.AddToScheme,
+		addonv1alpha1.AddToScheme,
 	}
 
 	schemeBuilders := []*scheme.Builder{
@@ -44,6 +48,7 @@ func AddToScheme(runtimeScheme *runtime.Scheme) error {
 		channelv1.SchemeBuilder,
 		appsubv1.SchemeBuilder,
 		appv1beta1.SchemeBuilder,
+		globalhubv1alpha3.SchemeBuilder,
 	}
 
 	for _, addToSchemeFunc := range addToSchemeFuncs {
@@ -8,11 +8,14 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
-func AddToManager(mgr ctrl.Manager, agentConfig *config.AgentConfig) error {
+func AddToManager(mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	appliedStatusBundle *appliedstatus.Bundle,
+) error {
 	// add consumer to manager
 	consumer, err := consumer.NewGenericConsumer(agentConfig.TransportConfig)
 	if err != nil {
@@ -35,9 +38,21 @@ func AddToManager(mgr ctrl.Manager, agentConfig *config.AgentConfig) error {
 	}
 
 	// register syncer to the dispatcher
-	dispatcher.RegisterSyncer(syncers.GenericMessageKey,
-		syncers.NewGenericSyncer(workers, agentConfig))
+	genericSyncer := syncers.NewGenericSyncer(workers, agentConfig, appliedStatusBundle)
+	dispatcher.RegisterSyncer(syncers.GenericMessageKey, genericSyncer)
 	dispatcher.RegisterSyncer(constants.ManagedClustersLabelsMsgKey,
-		syncers.NewManagedClusterLabelSyncer(workers))
+		syncers.NewManagedClusterLabelSyncer(workers, mgr.GetEventRecorderFor("multicluster-global-hub-agent")))
+	dispatcher.RegisterSyncer(constants.AgentConfigMsgKey, syncers.NewAgentConfigSyncer(workers))
+	dispatcher.RegisterSyncer(constants.GlobalPlacementDecisionsMsgKey, syncers.NewPlacementDecisionSyncer(workers))
+	dispatcher.RegisterSyncer(constants.ManagerCapabilitiesMsgKey, syncers.NewManagerCapabilitiesSyncer())
+
+	// add drift detector of the global resources to manager
+	if agentConfig.SpecDriftDetectionInterval > 0 {
+		driftDetector := syncers.NewDriftDetector(workers, genericSyncer,
+			agentConfig.SpecDriftDetectionInterval, agentConfig.SpecDriftCorrection)
+		if err := mgr.Add(driftDetector); err != nil {
+			return fmt.Errorf("failed to add drift detector to runtime manager: %w", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,105 @@
+package syncers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// agentConfigSyncer applies the effective agent config resolved by the manager to the GlobalHubAgentConfig of the
+// leaf hub, the config controller of the agent picks it up from there.
+type agentConfigSyncer struct {
+	log        logr.Logger
+	workerPool *workers.WorkerPool
+}
+
+func NewAgentConfigSyncer(workers *workers.WorkerPool) *agentConfigSyncer {
+	return &agentConfigSyncer{
+		log:        ctrl.Log.WithName("agent-config-syncer"),
+		workerPool: workers,
+	}
+}
+
+func (syncer *agentConfigSyncer) Sync(message *transport.Message) error {
+	bundle := &specbundle.AgentConfigSpecBundle{}
+	if err := json.Unmarshal(message.Payload, bundle); err != nil {
+		return err
+	}
+	if bundle.AgentConfig == nil {
+		return errors.New("the agent config bundle has no agent config")
+	}
+
+	syncer.workerPool.Submit(workers.NewJob(bundle, func(ctx context.Context, k8sClient client.Client,
+		obj interface{},
+	) {
+		agentConfigBundle, ok := obj.(*specbundle.AgentConfigSpecBundle)
+		if !ok {
+			syncer.log.Error(errors.New("job obj is not an AgentConfigSpecBundle type"), "invalid obj type")
+			return
+		}
+		if err := syncer.applyAgentConfig(ctx, k8sClient, agentConfigBundle); err != nil {
+			syncer.log.Error(err, "failed to apply the agent config", "source", agentConfigBundle.Source)
+			return
+		}
+	}))
+
+	return nil
+}
+
+func (syncer *agentConfigSyncer) applyAgentConfig(ctx context.Context, k8sClient client.Client,
+	agentConfigBundle *specbundle.AgentConfigSpecBundle,
+) error {
+	agentConfig := &globalhubv1alpha3.GlobalHubAgentConfig{}
+	err := k8sClient.Get(ctx, client.ObjectKey{
+		Namespace: constants.GHSystemNamespace,
+		Name:      constants.GHAgentConfigName,
+	}, agentConfig)
+	if meta.IsNoMatchError(err) {
+		// the crd is optional, the agent keeps using the config of the configmap rendered by the operator
+		syncer.log.Info("the GlobalHubAgentConfig crd isn't installed, skip applying the agent config",
+			"source", agentConfigBundle.Source)
+		return nil
+	} else if k8serrors.IsNotFound(err) {
+		agentConfig = &globalhubv1alpha3.GlobalHubAgentConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: constants.GHSystemNamespace,
+				Name:      constants.GHAgentConfigName,
+				Annotations: map[string]string{
+					constants.AgentConfigSourceAnnotation: agentConfigBundle.Source,
+				},
+			},
+			Spec: *agentConfigBundle.AgentConfig,
+		}
+		syncer.log.Info("creating the agent config", "source", agentConfigBundle.Source)
+		return k8sClient.Create(ctx, agentConfig)
+	} else if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(agentConfig.Spec, *agentConfigBundle.AgentConfig) &&
+		agentConfig.GetAnnotations()[constants.AgentConfigSourceAnnotation] == agentConfigBundle.Source {
+		return nil
+	}
+
+	agentConfig.Spec = *agentConfigBundle.AgentConfig
+	if agentConfig.Annotations == nil {
+		agentConfig.Annotations = map[string]string{}
+	}
+	agentConfig.Annotations[constants.AgentConfigSourceAnnotation] = agentConfigBundle.Source
+	syncer.log.Info("updating the agent config", "source", agentConfigBundle.Source)
+	return k8sClient.Update(ctx, agentConfig)
+}
@@ -59,7 +59,7 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 				d.log.Info("dispatching to the default generic syncer", "messageID", message.ID)
 				syncer = d.syncers[GenericMessageKey]
 			}
-			if err := syncer.Sync(message.Payload); err != nil {
+			if err := syncer.Sync(message); err != nil {
 				d.log.Error(err, "submit to syncer error", "messageID", message.ID)
 			}
 		}
@@ -0,0 +1,106 @@
+package syncers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+)
+
+// driftDetector periodically compares the live global resources on the hub against the last received spec,
+// and reports or corrects the drift.
+type driftDetector struct {
+	log          logr.Logger
+	workerPool   *workers.WorkerPool
+	syncer       *genericBundleSyncer
+	interval     time.Duration
+	correctDrift bool
+}
+
+// NewDriftDetector creates a drift detector for the global resources applied by the given generic syncer.
+func NewDriftDetector(workerPool *workers.WorkerPool, syncer *genericBundleSyncer, interval time.Duration,
+	correctDrift bool,
+) *driftDetector {
+	return &driftDetector{
+		log:          ctrl.Log.WithName("drift-detector"),
+		workerPool:   workerPool,
+		syncer:       syncer,
+		interval:     interval,
+		correctDrift: correctDrift,
+	}
+}
+
+// Start function starts the drift detector.
+func (detector *driftDetector) Start(ctx context.Context) error {
+	detector.log.Info("started drift detector", "interval", detector.interval,
+		"correctDrift", detector.correctDrift)
+
+	ticker := time.NewTicker(detector.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			detector.log.Info("stopped drift detector")
+			return nil
+		case <-ticker.C:
+			detector.detectDrift()
+		}
+	}
+}
+
+func (detector *driftDetector) detectDrift() {
+	desiredObjects := detector.syncer.getDesiredObjects()
+
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(len(desiredObjects))
+	for resourceID, desired := range desiredObjects {
+		resourceID, desired := resourceID, desired
+		detector.workerPool.Submit(workers.NewJob(desired.object.DeepCopy(), func(ctx context.Context,
+			k8sClient client.Client, obj interface{},
+		) {
+			defer waitGroup.Done()
+			detector.checkObject(ctx, k8sClient, obj.(*unstructured.Unstructured), resourceID, desired.bundleVersion)
+		}))
+	}
+	waitGroup.Wait()
+}
+
+func (detector *driftDetector) checkObject(ctx context.Context, k8sClient client.Client,
+	obj *unstructured.Unstructured, resourceID, bundleVersion string,
+) {
+	diff, err := helper.DryRunUpdateObject(ctx, k8sClient, obj)
+	if err != nil {
+		detector.log.Error(err, "failed to detect drift", "name", obj.GetName(),
+			"namespace", obj.GetNamespace(), "kind", obj.GetKind())
+		return
+	}
+
+	if diff != "" && detector.correctDrift {
+		detector.log.Info("correcting drift", "name", obj.GetName(), "namespace", obj.GetNamespace(),
+			"kind", obj.GetKind(), "diff", diff)
+		err := helper.UpdateObject(ctx, k8sClient, obj)
+		detector.syncer.reportAppliedStatus(newAppliedStatus(obj, resourceID, bundleVersion, err))
+		if err != nil {
+			detector.log.Error(err, "failed to correct drift", "name", obj.GetName(),
+				"namespace", obj.GetNamespace(), "kind", obj.GetKind())
+		}
+		return
+	}
+
+	if diff != "" {
+		detector.log.Info("drift detected", "name", obj.GetName(), "namespace", obj.GetNamespace(),
+			"kind", obj.GetKind(), "diff", diff)
+	}
+	appliedStatus := newAppliedStatus(obj, resourceID, bundleVersion, nil)
+	appliedStatus.Drifted = diff != ""
+	appliedStatus.Diff = diff
+	detector.syncer.reportAppliedStatus(appliedStatus)
+}
@@ -14,7 +14,11 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/rbac"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 // genericBundleSyncer syncs objects spec from received bundles.
@@ -23,31 +27,74 @@ type genericBundleSyncer struct {
 	workerPool                   *workers.WorkerPool
 	bundleProcessingWaitingGroup sync.WaitGroup
 	enforceHohRbac               bool
+	appliedStatusBundle          *appliedstatus.Bundle
+	// lastSyncedVersions holds the version of the last bundle synced per message id
+	lastSyncedVersions map[string]string
+	// desiredObjects holds the last applied spec of the global resources by resource id
+	desiredObjects     map[string]*desiredObject
+	desiredObjectsLock sync.Mutex
 }
 
-func NewGenericSyncer(workerPool *workers.WorkerPool, config *config.AgentConfig) *genericBundleSyncer {
+// desiredObject is the last spec of a global resource applied on the hub.
+type desiredObject struct {
+	object        *unstructured.Unstructured
+	bundleVersion string
+}
+
+func NewGenericSyncer(workerPool *workers.WorkerPool, config *config.AgentConfig,
+	appliedStatusBundle *appliedstatus.Bundle,
+) *genericBundleSyncer {
 	return &genericBundleSyncer{
 		log:                          ctrl.Log.WithName("generic-bundle-syncer"),
 		workerPool:                   workerPool,
 		bundleProcessingWaitingGroup: sync.WaitGroup{},
 		enforceHohRbac:               config.SpecEnforceHohRbac,
+		appliedStatusBundle:          appliedStatusBundle,
+		lastSyncedVersions:           make(map[string]string),
+		desiredObjects:               make(map[string]*desiredObject),
 	}
 }
 
-func (syncer *genericBundleSyncer) Sync(payload []byte) error {
+func (syncer *genericBundleSyncer) Sync(message *transport.Message) error {
 	genericBundle := &bundle.GenericBundle{}
-	if err := json.Unmarshal(payload, genericBundle); err != nil {
+	if err := json.Unmarshal(message.Payload, genericBundle); err != nil {
 		return err
 	}
 
+	if !syncer.shouldSync(message, genericBundle) {
+		return nil
+	}
+
 	syncer.bundleProcessingWaitingGroup.Add(len(genericBundle.Objects) + len(genericBundle.DeletedObjects))
-	syncer.syncObjects(genericBundle.Objects)
-	syncer.syncDeletedObjects(genericBundle.DeletedObjects)
+	syncer.syncObjects(genericBundle.Objects, message.Version)
+	syncer.syncDeletedObjects(genericBundle.DeletedObjects, message.Version)
 	syncer.bundleProcessingWaitingGroup.Wait()
+
+	syncer.lastSyncedVersions[message.ID] = message.Version
 	return nil
 }
 
-func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstructured) {
+// shouldSync returns false for the bundles which are older than the last synced bundle, and for the delta bundles
+// which aren't based on the last synced bundle. the manager doesn't know which bundles the leaf hub synced, so the
+// delta bundle after a missed bundle is dropped, and the leaf hub converges on the next complete bundle.
+func (syncer *genericBundleSyncer) shouldSync(message *transport.Message, genericBundle *bundle.GenericBundle) bool {
+	lastSyncedVersion, found := syncer.lastSyncedVersions[message.ID]
+	// the versions are formatted timestamps, so they can be compared as strings
+	if found && message.Version <= lastSyncedVersion && (genericBundle.IsDelta() ||
+		message.Version < lastSyncedVersion) {
+		syncer.log.Info("skipping stale bundle", "messageID", message.ID, "version", message.Version,
+			"lastSyncedVersion", lastSyncedVersion)
+		return false
+	}
+	if genericBundle.IsDelta() && (!found || genericBundle.BaseVersion != lastSyncedVersion) {
+		syncer.log.Info("dropping the delta bundle, waiting for the complete bundle", "messageID", message.ID,
+			"baseVersion", genericBundle.BaseVersion, "lastSyncedVersion", lastSyncedVersion)
+		return false
+	}
+	return true
+}
+
+func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstructured, bundleVersion string) {
 	for _, bundleObject := range bundleObjects {
 		if !syncer.enforceHohRbac { // if rbac not enforced, use controller's identity.
 			bundleObject = syncer.anonymize(bundleObject) // anonymize removes the user identity from the obj if exists
@@ -59,29 +106,64 @@ func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Uns
 			defer syncer.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
+			resourceID := getResourceID(unstructuredObject)
+
+			// the dry-run doesn't change the hub, so the missing namespace isn't created either
+			if isDryRun(unstructuredObject) {
+				syncer.dryRunObject(ctx, k8sClient, unstructuredObject, resourceID, bundleVersion)
+				return
+			}
 
 			if !syncer.enforceHohRbac { // if rbac not enforced, create missing namespaces.
 				if err := helper.CreateNamespaceIfNotExist(ctx, k8sClient,
 					unstructuredObject.GetNamespace()); err != nil {
 					syncer.log.Error(err, "failed to create namespace",
 						"namespace", unstructuredObject.GetNamespace())
+					syncer.reportAppliedStatus(newAppliedStatus(unstructuredObject, resourceID, bundleVersion, err))
 					return
 				}
 			}
 
+			// the update mutates the object with the live state, so the desired spec is copied before it
+			desired := unstructuredObject.DeepCopy()
 			err := helper.UpdateObject(ctx, k8sClient, unstructuredObject)
+			syncer.reportAppliedStatus(newAppliedStatus(unstructuredObject, resourceID, bundleVersion, err))
 			if err != nil {
 				syncer.log.Error(err, "failed to update object", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 				return
 			}
+			syncer.storeDesiredObject(resourceID, desired, bundleVersion)
 			syncer.log.Info("object updated", "name", unstructuredObject.GetName(), "namespace",
 				unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 		}))
 	}
 }
 
-func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.Unstructured) {
+// dryRunObject previews the changes of the object on the hub with server-side dry-run, without applying it.
+func (syncer *genericBundleSyncer) dryRunObject(ctx context.Context, k8sClient client.Client,
+	obj *unstructured.Unstructured, resourceID, bundleVersion string,
+) {
+	// the resource isn't enforced on the hub anymore, so it shouldn't be checked for drift
+	syncer.deleteDesiredObject(resourceID)
+
+	diff, err := helper.DryRunUpdateObject(ctx, k8sClient, obj)
+	appliedStatus := newAppliedStatus(obj, resourceID, bundleVersion, err)
+	appliedStatus.DryRun = true
+	appliedStatus.Diff = diff
+	syncer.reportAppliedStatus(appliedStatus)
+	if err != nil {
+		syncer.log.Error(err, "failed to dry-run object", "name", obj.GetName(),
+			"namespace", obj.GetNamespace(), "kind", obj.GetKind())
+		return
+	}
+	syncer.log.Info("object dry-run", "name", obj.GetName(), "namespace", obj.GetNamespace(),
+		"kind", obj.GetKind(), "diff", diff)
+}
+
+func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.Unstructured,
+	bundleVersion string,
+) {
 	for _, deletedBundleObj := range deletedObjects {
 		if !syncer.enforceHohRbac { // if rbac not enforced, use controller's identity.
 			deletedBundleObj = syncer.anonymize(deletedBundleObj) // anonymize removes the user identity from the obj if exists
@@ -93,13 +175,22 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 			defer syncer.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
+			resourceID := string(unstructuredObject.GetUID())
+			syncer.deleteDesiredObject(resourceID)
 
 			// syncer.deleteObject(ctx, k8sClient, obj.(*unstructured.Unstructured))
-			if deleted, err := helper.DeleteObject(ctx, k8sClient, unstructuredObject); err != nil {
+			deleted, err := helper.DeleteObject(ctx, k8sClient, unstructuredObject)
+			if err != nil {
+				syncer.reportAppliedStatus(newAppliedStatus(unstructuredObject, resourceID, bundleVersion, err))
 				syncer.log.Error(err, "failed to delete object", "name",
 					unstructuredObject.GetName(), "namespace",
 					unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
-			} else if deleted {
+				return
+			}
+
+			// the resource is removed from the hub, no need to report its status anymore
+			syncer.appliedStatusBundle.DeleteAppliedStatus(resourceID)
+			if deleted {
 				syncer.log.Info("object deleted", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 			}
@@ -107,6 +198,74 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 	}
 }
 
+// storeDesiredObject keeps the last spec applied for the global resource, to detect drift of the live object. the
+// object is kept as is, so it must be copied before the update mutates it with the live state.
+func (syncer *genericBundleSyncer) storeDesiredObject(resourceID string, obj *unstructured.Unstructured,
+	bundleVersion string,
+) {
+	if resourceID == "" {
+		return // not a global resource
+	}
+
+	syncer.desiredObjectsLock.Lock()
+	defer syncer.desiredObjectsLock.Unlock()
+	syncer.desiredObjects[resourceID] = &desiredObject{object: obj, bundleVersion: bundleVersion}
+}
+
+func (syncer *genericBundleSyncer) deleteDesiredObject(resourceID string) {
+	syncer.desiredObjectsLock.Lock()
+	defer syncer.desiredObjectsLock.Unlock()
+	delete(syncer.desiredObjects, resourceID)
+}
+
+// getDesiredObjects returns a snapshot of the desired objects by resource id.
+func (syncer *genericBundleSyncer) getDesiredObjects() map[string]*desiredObject {
+	syncer.desiredObjectsLock.Lock()
+	defer syncer.desiredObjectsLock.Unlock()
+
+	desiredObjects := make(map[string]*desiredObject, len(syncer.desiredObjects))
+	for resourceID, desired := range syncer.desiredObjects {
+		desiredObjects[resourceID] = desired
+	}
+	return desiredObjects
+}
+
+// reportAppliedStatus records the result of applying the object, so that the global hub knows whether the global
+// resource has landed on this hub.
+func (syncer *genericBundleSyncer) reportAppliedStatus(appliedStatus *status.AppliedStatus) {
+	if appliedStatus.ResourceID == "" {
+		return // not a global resource
+	}
+	syncer.appliedStatusBundle.UpdateAppliedStatus(appliedStatus)
+}
+
+func newAppliedStatus(obj *unstructured.Unstructured, resourceID, bundleVersion string,
+	err error,
+) *status.AppliedStatus {
+	appliedStatus := &status.AppliedStatus{
+		ResourceID:    resourceID,
+		Kind:          obj.GetKind(),
+		Name:          obj.GetName(),
+		Namespace:     obj.GetNamespace(),
+		BundleVersion: bundleVersion,
+		Applied:       err == nil,
+	}
+	if err != nil {
+		appliedStatus.Error = err.Error()
+	}
+	return appliedStatus
+}
+
+// getResourceID returns the uid of the original resource on the global hub.
+func getResourceID(obj *unstructured.Unstructured) string {
+	return obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+}
+
+// isDryRun returns true if the object should only be previewed on the hub with server-side dry-run.
+func isDryRun(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[constants.DryRunAnnotation] == "true"
+}
+
 func (syncer *genericBundleSyncer) anonymize(obj *unstructured.Unstructured) *unstructured.Unstructured {
 	annotations := obj.GetAnnotations()
 	delete(annotations, rbac.UserIdentityAnnotation)
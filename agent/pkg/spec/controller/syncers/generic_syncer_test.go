@@ -0,0 +1,54 @@
+package syncers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestShouldSync(t *testing.T) {
+	cases := []struct {
+		name              string
+		lastSyncedVersion string
+		version           string
+		baseVersion       string
+		expected          bool
+	}{
+		{name: "first complete bundle", version: "v2", expected: true},
+		{name: "first delta bundle", version: "v2", baseVersion: "v1", expected: false},
+		{name: "newer complete bundle", lastSyncedVersion: "v1", version: "v2", expected: true},
+		{name: "resynced complete bundle", lastSyncedVersion: "v2", version: "v2", expected: true},
+		{name: "stale complete bundle", lastSyncedVersion: "v2", version: "v1", expected: false},
+		{
+			name: "delta bundle based on the last synced bundle", lastSyncedVersion: "v1", version: "v2",
+			baseVersion: "v1", expected: true,
+		},
+		{
+			name: "delta bundle after a missed bundle", lastSyncedVersion: "v1", version: "v3",
+			baseVersion: "v2", expected: false,
+		},
+		{
+			name: "redelivered delta bundle", lastSyncedVersion: "v2", version: "v2", baseVersion: "v1",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			syncer := &genericBundleSyncer{
+				log:                ctrl.Log.WithName("test"),
+				lastSyncedVersions: map[string]string{},
+			}
+			if c.lastSyncedVersion != "" {
+				syncer.lastSyncedVersions["Policies"] = c.lastSyncedVersion
+			}
+			genericBundle := &bundle.GenericBundle{BaseVersion: c.baseVersion}
+			message := &transport.Message{ID: "Policies", Version: c.version}
+			assert.Equal(t, c.expected, syncer.shouldSync(message, genericBundle))
+		})
+	}
+}
@@ -2,12 +2,14 @@ package syncers
 
 import (
 	"context"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 const GenericMessageKey = "Generic"
 
 type Syncer interface {
-	Sync(payload []byte) error
+	Sync(message *transport.Message) error
 }
 
 type Dispatcher interface {
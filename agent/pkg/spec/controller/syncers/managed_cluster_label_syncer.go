@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,6 +22,8 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
 	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 const (
@@ -25,9 +31,10 @@ const (
 	hohFieldManager = "mgh-agent"
 )
 
-// managedClusterLabelsBundleSyncer syncs managed clusters metadata from received bundles.
+// managedClusterLabelsBundleSyncer syncs managed clusters labels, annotations and taints from received bundles.
 type managedClusterLabelsBundleSyncer struct {
 	log                          logr.Logger
+	eventRecorder                record.EventRecorder
 	latestBundle                 *specbundle.ManagedClusterLabelsSpecBundle
 	managedClusterToTimestampMap map[string]*time.Time
 	workerPool                   *workers.WorkerPool
@@ -35,9 +42,12 @@ type managedClusterLabelsBundleSyncer struct {
 	latestBundleLock             sync.Mutex
 }
 
-func NewManagedClusterLabelSyncer(workers *workers.WorkerPool) *managedClusterLabelsBundleSyncer {
+func NewManagedClusterLabelSyncer(workers *workers.WorkerPool,
+	eventRecorder record.EventRecorder,
+) *managedClusterLabelsBundleSyncer {
 	return &managedClusterLabelsBundleSyncer{
 		log:                          ctrl.Log.WithName("managed-clusters-labels-syncer"),
+		eventRecorder:                eventRecorder,
 		latestBundle:                 nil,
 		managedClusterToTimestampMap: make(map[string]*time.Time),
 		workerPool:                   workers,
@@ -46,9 +56,9 @@ func NewManagedClusterLabelSyncer(workers *workers.WorkerPool) *managedClusterLa
 	}
 }
 
-func (syncer *managedClusterLabelsBundleSyncer) Sync(payload []byte) error {
+func (syncer *managedClusterLabelsBundleSyncer) Sync(message *transport.Message) error {
 	bundle := &specbundle.ManagedClusterLabelsSpecBundle{}
-	if err := json.Unmarshal(payload, bundle); err != nil {
+	if err := json.Unmarshal(message.Payload, bundle); err != nil {
 		return err
 	}
 	syncer.setLatestBundle(bundle) // uses latestBundle
@@ -107,15 +117,11 @@ func (syncer *managedClusterLabelsBundleSyncer) updateManagedClusterAsync(
 			return
 		}
 
-		// enforce received labels state (overwrite if exists)
-		for key, value := range labelsSpec.Labels {
-			managedCluster.Labels[key] = value
-		}
+		// the values set on the regional hub are overwritten by the global hub, so that the scheduling of the
+		// managed clusters can be driven centrally, the conflicts are recorded as an event of the managed cluster
+		conflicts := detectConflicts(managedCluster, labelsSpec)
 
-		// delete labels by key
-		for _, labelKey := range labelsSpec.DeletedLabelKeys {
-			delete(managedCluster.Labels, labelKey)
-		}
+		applyManagedClusterLabelsSpec(managedCluster, labelsSpec)
 
 		if err := syncer.updateManagedFieldEntry(managedCluster, labelsSpec); err != nil {
 			syncer.log.Error(err, "failed to update managed cluster", "name", labelsSpec.ClusterName)
@@ -129,11 +135,35 @@ func (syncer *managedClusterLabelsBundleSyncer) updateManagedClusterAsync(
 			return
 		}
 
+		if len(conflicts) > 0 {
+			syncer.log.Info("managed cluster values set on the regional hub are overwritten", "name",
+				labelsSpec.ClusterName, "conflicts", conflicts)
+			// the event is sent to the global hub by the event exporter, the conflicts in the annotation are
+			// recorded in the audit log there
+			if syncer.eventRecorder != nil {
+				syncer.recordConflicts(managedCluster, conflicts)
+			}
+		}
+
 		syncer.log.Info("managed cluster updated", "name", labelsSpec.ClusterName)
 		syncer.managedClusterMarkUpdated(labelsSpec, lastProcessedTimestampPtr)
 	}))
 }
 
+func (syncer *managedClusterLabelsBundleSyncer) recordConflicts(managedCluster *clusterv1.ManagedCluster,
+	conflicts []string,
+) {
+	conflictsBytes, err := json.Marshal(conflicts)
+	if err != nil {
+		syncer.log.Error(err, "failed to marshal the conflicts", "name", managedCluster.Name)
+		return
+	}
+	syncer.eventRecorder.AnnotatedEventf(managedCluster,
+		map[string]string{constants.ManagedClusterMetadataConflictsAnnotation: string(conflictsBytes)},
+		corev1.EventTypeWarning, constants.ManagedClusterMetadataConflictReason,
+		"the values set on the regional hub are overwritten by the global hub: %s", strings.Join(conflicts, ", "))
+}
+
 func (syncer *managedClusterLabelsBundleSyncer) managedClusterMarkUpdated(
 	labelsSpec *specbundle.ManagedClusterLabelsSpec, lastProcessedTimestampPtr *time.Time,
 ) {
@@ -156,15 +186,23 @@ func (syncer *managedClusterLabelsBundleSyncer) getManagedClusterLastProcessedTi
 func (syncer *managedClusterLabelsBundleSyncer) updateManagedFieldEntry(managedCluster *clusterv1.ManagedCluster,
 	managedClusterLabelsSpec *specbundle.ManagedClusterLabelsSpec,
 ) error {
-	// create label fields
-	labelFields := helper.LabelsField{Labels: map[string]struct{}{}}
+	// create label and annotation fields
+	objectFields := helper.ObjectFields{Metadata: helper.MetadataField{Labels: map[string]struct{}{}}}
 	for key := range managedClusterLabelsSpec.Labels {
-		labelFields.Labels[fmt.Sprintf("f:%s", key)] = struct{}{}
+		objectFields.Metadata.Labels[fmt.Sprintf("f:%s", key)] = struct{}{}
+	}
+	if len(managedClusterLabelsSpec.Annotations) > 0 {
+		objectFields.Metadata.Annotations = map[string]struct{}{}
+		for key := range managedClusterLabelsSpec.Annotations {
+			objectFields.Metadata.Annotations[fmt.Sprintf("f:%s", key)] = struct{}{}
+		}
+	}
+	// create taints field, the taints are an atomic list so that it's owned as a whole
+	if len(managedClusterLabelsSpec.Taints) > 0 {
+		objectFields.Spec = &helper.SpecField{}
 	}
-	// create metadata field
-	metadataField := helper.MetadataField{LabelsField: labelFields}
 
-	metadataFieldRaw, err := json.Marshal(metadataField)
+	metadataFieldRaw, err := json.Marshal(objectFields)
 	if err != nil {
 		return fmt.Errorf("failed to create ManagedFieldsEntry - %w", err)
 	}
@@ -194,3 +232,160 @@ func (syncer *managedClusterLabelsBundleSyncer) updateManagedFieldEntry(managedC
 
 	return nil
 }
+
+// applyManagedClusterLabelsSpec enforces the received labels, annotations and taints state on the managed cluster.
+func applyManagedClusterLabelsSpec(managedCluster *clusterv1.ManagedCluster,
+	labelsSpec *specbundle.ManagedClusterLabelsSpec,
+) {
+	// enforce received labels state (overwrite if exists)
+	if managedCluster.Labels == nil && len(labelsSpec.Labels) > 0 {
+		managedCluster.Labels = map[string]string{}
+	}
+	for key, value := range labelsSpec.Labels {
+		managedCluster.Labels[key] = value
+	}
+
+	// delete labels by key
+	for _, labelKey := range labelsSpec.DeletedLabelKeys {
+		delete(managedCluster.Labels, labelKey)
+	}
+
+	// enforce received annotations state (overwrite if exists)
+	if managedCluster.Annotations == nil && len(labelsSpec.Annotations) > 0 {
+		managedCluster.Annotations = map[string]string{}
+	}
+	for key, value := range labelsSpec.Annotations {
+		managedCluster.Annotations[key] = value
+	}
+
+	// delete annotations by key
+	for _, annotationKey := range labelsSpec.DeletedAnnotationKeys {
+		delete(managedCluster.Annotations, annotationKey)
+	}
+
+	// enforce received taints state, the taint with the same key and effect is replaced
+	for _, taint := range labelsSpec.Taints {
+		index := taintIndex(managedCluster.Spec.Taints, specbundle.TaintKey(taint.Key, taint.Effect))
+		if index < 0 {
+			taint.TimeAdded = v1.Now()
+			managedCluster.Spec.Taints = append(managedCluster.Spec.Taints, taint)
+			continue
+		}
+		current := managedCluster.Spec.Taints[index]
+		if current.Value != taint.Value {
+			taint.TimeAdded = v1.Now()
+			managedCluster.Spec.Taints[index] = taint
+		}
+	}
+
+	// delete taints by key and effect
+	for _, taintKey := range labelsSpec.DeletedTaintKeys {
+		if index := taintIndex(managedCluster.Spec.Taints, taintKey); index >= 0 {
+			managedCluster.Spec.Taints = append(managedCluster.Spec.Taints[:index],
+				managedCluster.Spec.Taints[index+1:]...)
+		}
+	}
+}
+
+// detectConflicts returns the labels, annotations and taints set on the regional hub, by a field manager other than
+// the global hub agent, which are overwritten or deleted by the received state.
+func detectConflicts(managedCluster *clusterv1.ManagedCluster,
+	labelsSpec *specbundle.ManagedClusterLabelsSpec,
+) []string {
+	localFields := localManagedFields(managedCluster)
+	conflicts := []string{}
+
+	detectMapConflicts := func(kind string, current, received map[string]string, deletedKeys []string) {
+		for key, value := range received {
+			if currentValue, found := current[key]; found && currentValue != value &&
+				hasField(localFields, "f:metadata", "f:"+kind+"s", "f:"+key) {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s=%s", kind, key, currentValue))
+			}
+		}
+		for _, key := range deletedKeys {
+			if currentValue, found := current[key]; found &&
+				hasField(localFields, "f:metadata", "f:"+kind+"s", "f:"+key) {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s=%s", kind, key, currentValue))
+			}
+		}
+	}
+	detectMapConflicts("label", managedCluster.Labels, labelsSpec.Labels, labelsSpec.DeletedLabelKeys)
+	detectMapConflicts("annotation", managedCluster.Annotations, labelsSpec.Annotations,
+		labelsSpec.DeletedAnnotationKeys)
+
+	if hasField(localFields, "f:spec", "f:taints") {
+		for _, taint := range labelsSpec.Taints {
+			index := taintIndex(managedCluster.Spec.Taints, specbundle.TaintKey(taint.Key, taint.Effect))
+			if index < 0 {
+				continue
+			}
+			current := managedCluster.Spec.Taints[index]
+			if current.Value != taint.Value {
+				conflicts = append(conflicts, fmt.Sprintf("taint %s=%s:%s", current.Key, current.Value,
+					current.Effect))
+			}
+		}
+		for _, taintKey := range labelsSpec.DeletedTaintKeys {
+			if index := taintIndex(managedCluster.Spec.Taints, taintKey); index >= 0 {
+				current := managedCluster.Spec.Taints[index]
+				conflicts = append(conflicts, fmt.Sprintf("taint %s=%s:%s", current.Key, current.Value,
+					current.Effect))
+			}
+		}
+	}
+
+	sort.Strings(conflicts)
+
+	return conflicts
+}
+
+// localManagedFields returns the fields of the managed cluster owned by the field managers other than the global hub
+// agent, the fields of the status subresource are skipped.
+func localManagedFields(managedCluster *clusterv1.ManagedCluster) []map[string]interface{} {
+	fields := []map[string]interface{}{}
+
+	for _, entry := range managedCluster.ManagedFields {
+		if entry.Manager == hohFieldManager || entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		entryFields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &entryFields); err != nil {
+			continue
+		}
+		fields = append(fields, entryFields)
+	}
+
+	return fields
+}
+
+// hasField returns true if the field of the path is in any of the given fields.
+func hasField(fields []map[string]interface{}, path ...string) bool {
+	for _, entryFields := range fields {
+		current := entryFields
+		found := true
+		for _, key := range path {
+			next, ok := current[key].(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			current = next
+		}
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// taintIndex returns the index of the taint with the given "<key>:<effect>", or -1 if it's not found.
+func taintIndex(taints []clusterv1.Taint, taintKey string) int {
+	for i, taint := range taints {
+		if specbundle.TaintKey(taint.Key, taint.Effect) == taintKey {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,167 @@
+package syncers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newManagedCluster() *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mc1",
+			Labels:      map[string]string{"environment": "dev", "vendor": "OpenShift", "tier": "1"},
+			Annotations: map[string]string{"owner": "team-a"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-edit",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:environment":{},` +
+						`"f:vendor":{}},"f:annotations":{"f:owner":{}}},"f:spec":{"f:taints":{}}}`)},
+				},
+				{
+					Manager:   hohFieldManager,
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:tier":{}}}}`)},
+				},
+			},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			Taints: []clusterv1.Taint{
+				{Key: "maintenance", Value: "false", Effect: clusterv1.TaintEffectNoSelect},
+				{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+			},
+		},
+	}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	managedCluster := newManagedCluster()
+	conflicts := detectConflicts(managedCluster, &specbundle.ManagedClusterLabelsSpec{
+		Labels: map[string]string{
+			"environment": "prod", // set locally with a different value
+			"vendor":      "OpenShift",
+			"tier":        "2", // owned by the global hub agent
+		},
+		DeletedAnnotationKeys: []string{"owner"},
+		Taints: []clusterv1.Taint{
+			{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+		},
+	})
+	assert.Equal(t, []string{
+		"annotation owner=team-a",
+		"label environment=dev",
+		"taint maintenance=false:NoSelect",
+	}, conflicts)
+
+	// no conflict once the fields are owned by the global hub agent
+	managedCluster.ManagedFields = managedCluster.ManagedFields[1:]
+	assert.Empty(t, detectConflicts(managedCluster, &specbundle.ManagedClusterLabelsSpec{
+		Labels: map[string]string{"environment": "prod", "tier": "2"},
+	}))
+}
+
+func TestApplyManagedClusterLabelsSpec(t *testing.T) {
+	managedCluster := newManagedCluster()
+	applyManagedClusterLabelsSpec(managedCluster, &specbundle.ManagedClusterLabelsSpec{
+		Labels:                map[string]string{"environment": "prod"},
+		DeletedLabelKeys:      []string{"tier"},
+		Annotations:           map[string]string{"scheduling": "disabled"},
+		DeletedAnnotationKeys: []string{"owner"},
+		Taints: []clusterv1.Taint{
+			{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+			{Key: "drain", Effect: clusterv1.TaintEffectNoSelectIfNew},
+		},
+		DeletedTaintKeys: []string{"gpu:PreferNoSelect"},
+	})
+
+	assert.Equal(t, map[string]string{"environment": "prod", "vendor": "OpenShift"}, managedCluster.Labels)
+	assert.Equal(t, map[string]string{"scheduling": "disabled"}, managedCluster.Annotations)
+	assert.Len(t, managedCluster.Spec.Taints, 2)
+	assert.Equal(t, "maintenance", managedCluster.Spec.Taints[0].Key)
+	assert.Equal(t, "true", managedCluster.Spec.Taints[0].Value)
+	assert.False(t, managedCluster.Spec.Taints[0].TimeAdded.IsZero())
+	assert.Equal(t, "drain", managedCluster.Spec.Taints[1].Key)
+
+	// the labels and annotations are created if they're missing
+	managedCluster = &clusterv1.ManagedCluster{}
+	applyManagedClusterLabelsSpec(managedCluster, &specbundle.ManagedClusterLabelsSpec{
+		Labels:      map[string]string{"environment": "prod"},
+		Annotations: map[string]string{"scheduling": "disabled"},
+	})
+	assert.Equal(t, map[string]string{"environment": "prod"}, managedCluster.Labels)
+	assert.Equal(t, map[string]string{"scheduling": "disabled"}, managedCluster.Annotations)
+}
+
+func TestApplyManagedClusterTaintsOfSameKey(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		Spec: clusterv1.ManagedClusterSpec{
+			Taints: []clusterv1.Taint{
+				{Key: "maintenance", Value: "false", Effect: clusterv1.TaintEffectNoSelect},
+				{Key: "maintenance", Value: "false", Effect: clusterv1.TaintEffectPreferNoSelect},
+				{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+				{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectNoSelectIfNew},
+			},
+		},
+	}
+
+	// the taints of the same key with the different effects are updated and deleted on their own
+	applyManagedClusterLabelsSpec(managedCluster, &specbundle.ManagedClusterLabelsSpec{
+		Taints: []clusterv1.Taint{
+			{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+		},
+		DeletedTaintKeys: []string{"gpu:NoSelectIfNew"},
+	})
+	assert.Equal(t, []clusterv1.Taint{
+		{Key: "maintenance", Value: "false", Effect: clusterv1.TaintEffectNoSelect},
+		{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect,
+			TimeAdded: managedCluster.Spec.Taints[1].TimeAdded},
+		{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+	}, managedCluster.Spec.Taints)
+	assert.True(t, managedCluster.Spec.Taints[0].TimeAdded.IsZero())
+	assert.False(t, managedCluster.Spec.Taints[1].TimeAdded.IsZero())
+}
+
+// annotatedEventRecorder keeps the annotations and the message of the recorded events
+type annotatedEventRecorder struct {
+	annotations []map[string]string
+	messages    []string
+}
+
+func (r *annotatedEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *annotatedEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *annotatedEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string,
+	eventtype, reason, messageFmt string, args ...interface{},
+) {
+	r.annotations = append(r.annotations, annotations)
+	r.messages = append(r.messages, fmt.Sprintf(eventtype+" "+reason+" "+messageFmt, args...))
+}
+
+func TestRecordConflicts(t *testing.T) {
+	recorder := &annotatedEventRecorder{}
+	syncer := &managedClusterLabelsBundleSyncer{log: ctrl.Log.WithName("test"), eventRecorder: recorder}
+
+	syncer.recordConflicts(newManagedCluster(), []string{"annotation owner=team-a", "label environment=dev"})
+
+	assert.Equal(t, []map[string]string{{
+		constants.ManagedClusterMetadataConflictsAnnotation: `["annotation owner=team-a","label environment=dev"]`,
+	}}, recorder.annotations)
+	assert.Equal(t, []string{"Warning GlobalHubMetadataConflict the values set on the regional hub are " +
+		"overwritten by the global hub: annotation owner=team-a, label environment=dev"}, recorder.messages)
+}
@@ -0,0 +1,26 @@
+package syncers
+
+import (
+	"encoding/json"
+
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// managerCapabilitiesSyncer negotiates the schema version of the status bundles with the capabilities advertised by
+// the manager.
+type managerCapabilitiesSyncer struct{}
+
+func NewManagerCapabilitiesSyncer() *managerCapabilitiesSyncer {
+	return &managerCapabilitiesSyncer{}
+}
+
+func (syncer *managerCapabilitiesSyncer) Sync(message *transport.Message) error {
+	capabilities := &status.Capabilities{}
+	if err := json.Unmarshal(message.Payload, capabilities); err != nil {
+		return err
+	}
+	statusconfig.SetManagerCapabilities(capabilities)
+	return nil
+}
@@ -0,0 +1,210 @@
+package syncers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// globalDecisionReason is the reason of the cluster decisions made by the global scheduler.
+	globalDecisionReason = "GlobalHubScheduled"
+	// globalDecisionNameFormat is the name of the placement decision of the global scheduler, the placement controller
+	// of the leaf hub names its placement decisions as <placement>-decision-<index>, so it never owns this one.
+	globalDecisionNameFormat = "%s-global-hub-decision"
+)
+
+// placementDecisionSyncer applies the decisions of the global scheduler to the global placements and placement rules
+// of the leaf hub, the decisions of a placement are kept in its placement decision, and the decisions of a placement
+// rule are kept in its status.
+type placementDecisionSyncer struct {
+	log        logr.Logger
+	workerPool *workers.WorkerPool
+}
+
+func NewPlacementDecisionSyncer(workers *workers.WorkerPool) *placementDecisionSyncer {
+	return &placementDecisionSyncer{
+		log:        ctrl.Log.WithName("placement-decision-syncer"),
+		workerPool: workers,
+	}
+}
+
+func (syncer *placementDecisionSyncer) Sync(message *transport.Message) error {
+	bundle := &specbundle.PlacementDecisionsSpecBundle{}
+	if err := json.Unmarshal(message.Payload, bundle); err != nil {
+		return err
+	}
+
+	syncer.workerPool.Submit(workers.NewJob(bundle, func(ctx context.Context, k8sClient client.Client,
+		obj interface{},
+	) {
+		decisionsBundle, ok := obj.(*specbundle.PlacementDecisionsSpecBundle)
+		if !ok {
+			syncer.log.Error(errors.New("job obj is not a PlacementDecisionsSpecBundle type"), "invalid obj type")
+			return
+		}
+		if err := syncer.applyDecisions(ctx, k8sClient, decisionsBundle); err != nil {
+			syncer.log.Error(err, "failed to apply the global placement decisions")
+			return
+		}
+	}))
+
+	return nil
+}
+
+// applyDecisions applies the decisions to all the global placements and placement rules of the leaf hub which the
+// schedulers of the leaf hub ignore, the ones which aren't in the bundle select no cluster. the global placements
+// created after the bundle get their decisions when the bundle is resent.
+func (syncer *placementDecisionSyncer) applyDecisions(ctx context.Context, k8sClient client.Client,
+	decisionsBundle *specbundle.PlacementDecisionsSpecBundle,
+) error {
+	clusters := map[specbundle.PlacementKind]map[types.NamespacedName][]string{
+		specbundle.PlacementKindPlacement:     {},
+		specbundle.PlacementKindPlacementRule: {},
+	}
+	for _, decision := range decisionsBundle.Decisions {
+		if _, found := clusters[decision.Kind]; !found {
+			syncer.log.Info("skip the decision of unknown kind", "kind", decision.Kind)
+			continue
+		}
+		clusters[decision.Kind][types.NamespacedName{Namespace: decision.Namespace, Name: decision.Name}] =
+			decision.Clusters
+	}
+
+	var errs []error
+
+	placementList := &clusterv1beta1.PlacementList{}
+	if err := k8sClient.List(ctx, placementList); err != nil {
+		return fmt.Errorf("failed to list the placements: %w", err)
+	}
+	for i := range placementList.Items {
+		placement := &placementList.Items[i]
+		// the placement scheduled by the placement controller of the leaf hub is left to it
+		if !helper.HasAnnotation(placement, constants.OriginOwnerReferenceAnnotation) ||
+			placement.GetAnnotations()[clusterv1beta1.PlacementDisableAnnotation] != "true" {
+			continue
+		}
+		if err := syncer.applyPlacementDecision(ctx, k8sClient, placement,
+			clusters[specbundle.PlacementKindPlacement][client.ObjectKeyFromObject(placement)]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	placementRuleList := &placementrulev1.PlacementRuleList{}
+	if err := k8sClient.List(ctx, placementRuleList); err != nil {
+		return fmt.Errorf("failed to list the placement rules: %w", err)
+	}
+	for i := range placementRuleList.Items {
+		placementRule := &placementRuleList.Items[i]
+		if !helper.HasAnnotation(placementRule, constants.OriginOwnerReferenceAnnotation) ||
+			placementRule.Spec.SchedulerName != constants.GlobalHubSchedulerName {
+			continue
+		}
+		if err := syncer.applyPlacementRuleDecision(ctx, k8sClient, placementRule,
+			clusters[specbundle.PlacementKindPlacementRule][client.ObjectKeyFromObject(placementRule)]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyPlacementDecision creates or updates the placement decision owned by the placement with the clusters.
+func (syncer *placementDecisionSyncer) applyPlacementDecision(ctx context.Context, k8sClient client.Client,
+	placement *clusterv1beta1.Placement, clusters []string,
+) error {
+	decisions := []clusterv1beta1.ClusterDecision{}
+	for _, cluster := range clusters {
+		decisions = append(decisions, clusterv1beta1.ClusterDecision{
+			ClusterName: cluster,
+			Reason:      globalDecisionReason,
+		})
+	}
+
+	placementDecisionName := fmt.Sprintf(globalDecisionNameFormat, placement.GetName())
+	placementDecision := &clusterv1beta1.PlacementDecision{}
+	err := k8sClient.Get(ctx, types.NamespacedName{
+		Namespace: placement.GetNamespace(),
+		Name:      placementDecisionName,
+	}, placementDecision)
+	if k8serrors.IsNotFound(err) {
+		placementDecision = &clusterv1beta1.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: placement.GetNamespace(),
+				Name:      placementDecisionName,
+				Labels: map[string]string{
+					clusterv1beta1.PlacementLabel:    placement.GetName(),
+					constants.GlobalHubOwnerLabelKey: constants.GlobalHubOwnerLabelVal,
+				},
+			},
+		}
+		if err := controllerutil.SetControllerReference(placement, placementDecision,
+			k8sClient.Scheme()); err != nil {
+			return err
+		}
+		syncer.log.Info("creating the placement decision", "namespace", placement.GetNamespace(),
+			"name", placementDecision.GetName())
+		if err := k8sClient.Create(ctx, placementDecision); err != nil {
+			return fmt.Errorf("failed to create the placement decision of placement %s/%s: %w",
+				placement.GetNamespace(), placement.GetName(), err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(placementDecision.Status.Decisions, decisions) {
+		return nil
+	}
+	placementDecision.Status.Decisions = decisions
+	syncer.log.Info("updating the placement decision", "namespace", placement.GetNamespace(),
+		"name", placementDecision.GetName(), "clusters", len(clusters))
+	if err := k8sClient.Status().Update(ctx, placementDecision); err != nil {
+		return fmt.Errorf("failed to update the placement decision of placement %s/%s: %w",
+			placement.GetNamespace(), placement.GetName(), err)
+	}
+	return nil
+}
+
+// applyPlacementRuleDecision updates the decisions in the status of the placement rule with the clusters.
+func (syncer *placementDecisionSyncer) applyPlacementRuleDecision(ctx context.Context, k8sClient client.Client,
+	placementRule *placementrulev1.PlacementRule, clusters []string,
+) error {
+	var decisions []placementrulev1.PlacementDecision
+	for _, cluster := range clusters {
+		decisions = append(decisions, placementrulev1.PlacementDecision{
+			ClusterName:      cluster,
+			ClusterNamespace: cluster,
+		})
+	}
+
+	if equality.Semantic.DeepEqual(placementRule.Status.Decisions, decisions) {
+		return nil
+	}
+	placementRule.Status.Decisions = decisions
+	syncer.log.Info("updating the placement rule decisions", "namespace", placementRule.GetNamespace(),
+		"name", placementRule.GetName(), "clusters", len(clusters))
+	if err := k8sClient.Status().Update(ctx, placementRule); err != nil {
+		return fmt.Errorf("failed to update the decisions of placement rule %s/%s: %w",
+			placementRule.GetNamespace(), placementRule.GetName(), err)
+	}
+	return nil
+}
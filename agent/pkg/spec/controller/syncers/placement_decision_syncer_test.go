@@ -0,0 +1,92 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestApplyDecisions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clusterv1beta1.AddToScheme(scheme))
+	assert.NoError(t, placementrulev1.AddToScheme(scheme))
+
+	globalAnnotations := map[string]string{
+		constants.OriginOwnerReferenceAnnotation:  "uid",
+		clusterv1beta1.PlacementDisableAnnotation: "true",
+	}
+	globalPlacement := &clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{
+		Name: "global", Namespace: "default", UID: "global-uid", Annotations: globalAnnotations,
+	}}
+	// the placement scheduled by the placement controller of the leaf hub
+	localPlacement := &clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{
+		Name: "local", Namespace: "default", UID: "local-uid",
+		Annotations: map[string]string{constants.OriginOwnerReferenceAnnotation: "uid"},
+	}}
+	localDecision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: metav1.ObjectMeta{Name: "global-decision-1", Namespace: "default"},
+		Status: clusterv1beta1.PlacementDecisionStatus{
+			Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: "local-cluster"}},
+		},
+	}
+	globalPlacementRule := &placementrulev1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "default", Annotations: globalAnnotations},
+		Spec:       placementrulev1.PlacementRuleSpec{SchedulerName: constants.GlobalHubSchedulerName},
+	}
+	localPlacementRule := &placementrulev1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "default", Annotations: globalAnnotations},
+		Status: placementrulev1.PlacementRuleStatus{
+			Decisions: []placementrulev1.PlacementDecision{{ClusterName: "local-cluster"}},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(globalPlacement, localPlacement,
+		localDecision, globalPlacementRule, localPlacementRule).Build()
+	syncer := &placementDecisionSyncer{log: ctrl.Log.WithName("test")}
+
+	ctx := context.Background()
+	assert.NoError(t, syncer.applyDecisions(ctx, k8sClient, &specbundle.PlacementDecisionsSpecBundle{
+		Decisions: []*specbundle.GlobalPlacementDecision{
+			{Kind: specbundle.PlacementKindPlacement, Name: "global", Namespace: "default", Clusters: []string{"c1"}},
+			{Kind: specbundle.PlacementKindPlacement, Name: "local", Namespace: "default", Clusters: []string{"c2"}},
+			{
+				Kind: specbundle.PlacementKindPlacementRule, Name: "global", Namespace: "default",
+				Clusters: []string{"c3"},
+			},
+			{
+				Kind: specbundle.PlacementKindPlacementRule, Name: "local", Namespace: "default",
+				Clusters: []string{"c4"},
+			},
+		},
+	}))
+
+	// the decision of the global scheduler doesn't take over the one of the placement controller
+	decision := &clusterv1beta1.PlacementDecision{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "global-global-hub-decision"},
+		decision))
+	assert.Equal(t, "global", decision.GetLabels()[clusterv1beta1.PlacementLabel])
+	assert.Equal(t, []clusterv1beta1.ClusterDecision{{ClusterName: "c1", Reason: globalDecisionReason}},
+		decision.Status.Decisions)
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(localDecision), decision))
+	assert.Equal(t, localDecision.Status.Decisions, decision.Status.Decisions)
+	assert.Error(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "local-global-hub-decision"},
+		decision))
+
+	placementRule := &placementrulev1.PlacementRule{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(globalPlacementRule), placementRule))
+	assert.Equal(t, []placementrulev1.PlacementDecision{{ClusterName: "c3", ClusterNamespace: "c3"}},
+		placementRule.Status.Decisions)
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(localPlacementRule), placementRule))
+	assert.Equal(t, localPlacementRule.Status.Decisions, placementRule.Status.Decisions)
+}
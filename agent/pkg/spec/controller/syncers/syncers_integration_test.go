@@ -9,11 +9,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -89,6 +91,56 @@ var _ = Describe("Syncers Integration", func() {
 		}, 5*time.Second, 100*time.Microsecond).ShouldNot(HaveOccurred())
 	})
 
+	It("sync agent config bundle", func() {
+		By("Create the global hub system namespace on the regional hub")
+		Expect(runtimeclient.IgnoreAlreadyExists(client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.GHSystemNamespace},
+		}))).NotTo(HaveOccurred())
+
+		By("Send AgentConfigBundle by transport")
+		enableLocalPolicies := true
+		agentConfigBundle := &spec.AgentConfigSpecBundle{
+			LeafHubName: agentConfig.LeafHubName,
+			Source:      "edge",
+			AgentConfig: &globalhubv1alpha3.AgentConfigSpec{
+				AggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+				EnableLocalPolicies: &enableLocalPolicies,
+				SyncIntervals: &globalhubv1alpha3.SyncIntervalsConfig{
+					ManagedClusters: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		}
+		payloadBytes, err := json.Marshal(agentConfigBundle)
+		Expect(err).NotTo(HaveOccurred())
+		err = producer.Send(ctx, &transport.Message{
+			Destination: agentConfig.LeafHubName,
+			ID:          constants.AgentConfigMsgKey,
+			MsgType:     constants.SpecBundle,
+			Version:     time.Now().Format(timeFormat),
+			Payload:     payloadBytes,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Check the agent config is created from the bundle")
+		Eventually(func() error {
+			ghAgentConfig := &globalhubv1alpha3.GlobalHubAgentConfig{}
+			if err := client.Get(ctx, runtimeclient.ObjectKey{
+				Namespace: constants.GHSystemNamespace,
+				Name:      constants.GHAgentConfigName,
+			}, ghAgentConfig); err != nil {
+				return err
+			}
+			if ghAgentConfig.Spec.AggregationLevel != globalhubv1alpha3.MinimalAggregation ||
+				ghAgentConfig.Spec.SyncIntervals.GetManagedClustersInterval() != time.Minute {
+				return fmt.Errorf("the agent config isn't synced: %v", ghAgentConfig.Spec)
+			}
+			if source := ghAgentConfig.GetAnnotations()[constants.AgentConfigSourceAnnotation]; source != "edge" {
+				return fmt.Errorf("the agent config source %s isn't synced", source)
+			}
+			return nil
+		}, 5*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
 	It("sync configmap bundle", func() {
 		By("Create Config Bundle")
 		baseBundle := bundle.NewBaseObjectsBundle()
@@ -125,4 +177,45 @@ var _ = Describe("Syncers Integration", func() {
 			return client.Get(ctx, runtimeclient.ObjectKeyFromObject(cm), syncedConfigMap)
 		}, 5*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
 	})
+
+	It("dry-run configmap bundle without creating the namespace", func() {
+		By("Create Config Bundle with a dry-run configmap in a missing namespace")
+		baseBundle := bundle.NewBaseObjectsBundle()
+		cm := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "hello-dry-run",
+				Namespace:   "dry-run",
+				Annotations: map[string]string{constants.DryRunAnnotation: "true"},
+			},
+			Data: map[string]string{
+				"hello": "world",
+			},
+		}
+		baseBundle.AddObject(cm, uuid.New().String())
+
+		By("Send Config Bundle by transport")
+		payloadBytes, err := json.Marshal(baseBundle)
+		Expect(err).NotTo(HaveOccurred())
+		err = producer.Send(ctx, &transport.Message{
+			Destination: transport.Broadcast,
+			ID:          "Config",
+			MsgType:     constants.SpecBundle,
+			Version:     time.Now().Format(timeFormat),
+			Payload:     payloadBytes,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Check neither the namespace nor the configmap is created")
+		Consistently(func() bool {
+			namespace := &corev1.Namespace{}
+			err := client.Get(ctx, runtimeclient.ObjectKey{Name: cm.Namespace}, namespace)
+			return apierrors.IsNotFound(err)
+		}, 3*time.Second, 100*time.Millisecond).Should(BeTrue())
+		Expect(apierrors.IsNotFound(client.Get(ctx, runtimeclient.ObjectKeyFromObject(cm),
+			&corev1.ConfigMap{}))).To(BeTrue())
+	})
 })
@@ -19,6 +19,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
 	agentscheme "github.com/stolostron/multicluster-global-hub/agent/pkg/scheme"
 	speccontroller "github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	genericproducer "github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 )
@@ -71,7 +72,7 @@ var _ = BeforeSuite(func() {
 		SpecEnforceHohRbac: true,
 	}
 
-	err = speccontroller.AddToManager(mgr, agentConfig)
+	err = speccontroller.AddToManager(mgr, agentConfig, appliedstatus.NewBundle(agentConfig.LeafHubName, 0))
 	Expect(err).NotTo(HaveOccurred())
 
 	go func() {
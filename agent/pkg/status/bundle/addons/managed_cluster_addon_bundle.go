@@ -0,0 +1,142 @@
+package addons
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	statusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// conditionProgressing isn't defined by the addon api of the current version
+const conditionProgressing = "Progressing"
+
+// ResolveClusterIDFunc returns the id of the managed cluster, or empty string if the id can't be resolved.
+type ResolveClusterIDFunc func(clusterName string) string
+
+// NewManagedClusterAddOnStatusBundle creates a new instance of ManagedClusterAddOnStatusBundle.
+func NewManagedClusterAddOnStatusBundle(leafHubName string, incarnation uint64,
+	resolveClusterIDFunc ResolveClusterIDFunc,
+) statusbundle.Bundle {
+	return &ManagedClusterAddOnStatusBundle{
+		BaseManagedClusterAddOnStatusBundle: status.BaseManagedClusterAddOnStatusBundle{
+			Objects:       make([]*status.ManagedClusterAddOnStatus, 0),
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(incarnation, 0),
+		},
+		resolveClusterIDFunc: resolveClusterIDFunc,
+		lock:                 sync.Mutex{},
+	}
+}
+
+// ManagedClusterAddOnStatusBundle holds the addon health of the managed clusters on the leaf hub. the addons are
+// reported with the name and the id of their managed cluster, the id is left empty if it can't be resolved and then
+// the manager resolves it by the cluster name.
+type ManagedClusterAddOnStatusBundle struct {
+	status.BaseManagedClusterAddOnStatusBundle
+	resolveClusterIDFunc ResolveClusterIDFunc
+	lock                 sync.Mutex
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *ManagedClusterAddOnStatusBundle) UpdateObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	addon, ok := object.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return
+	}
+
+	index := bundle.getObjectIndex(addon)
+	addonStatus := toManagedClusterAddOnStatus(addon, bundle.resolveClusterIDFunc(addon.GetNamespace()))
+	if index < 0 { // object not found, need to add it to the bundle
+		bundle.Objects = append(bundle.Objects, addonStatus)
+		bundle.BundleVersion.Generation++
+		return
+	}
+
+	if bundle.Objects[index].ResourceVersion == addonStatus.ResourceVersion &&
+		bundle.Objects[index].ClusterID == addonStatus.ClusterID {
+		return // update in bundle only if object or its cluster id changed
+	}
+
+	bundle.Objects[index] = addonStatus
+	bundle.BundleVersion.Generation++
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *ManagedClusterAddOnStatusBundle) DeleteObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	index := bundle.getObjectIndex(object)
+	if index < 0 { // trying to delete object which doesn't exist - return with no error
+		return
+	}
+
+	bundle.Objects = append(bundle.Objects[:index], bundle.Objects[index+1:]...)
+	bundle.BundleVersion.Generation++
+}
+
+// GetBundleVersion function to get bundle version.
+func (bundle *ManagedClusterAddOnStatusBundle) GetBundleVersion() *status.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.BundleVersion
+}
+
+func (bundle *ManagedClusterAddOnStatusBundle) getObjectIndex(object statusbundle.Object) int {
+	for i, obj := range bundle.Objects {
+		if len(object.GetUID()) > 0 && obj.AddonUID == string(object.GetUID()) {
+			return i
+		}
+		if len(object.GetUID()) == 0 && obj.ClusterName == object.GetNamespace() && obj.AddonName == object.GetName() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func toManagedClusterAddOnStatus(addon *addonv1alpha1.ManagedClusterAddOn,
+	clusterID string,
+) *status.ManagedClusterAddOnStatus {
+	addonStatus := &status.ManagedClusterAddOnStatus{
+		ClusterID:       clusterID,
+		ClusterName:     addon.GetNamespace(),
+		AddonName:       addon.GetName(),
+		Available:       conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable),
+		Degraded:        conditionStatus(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded),
+		Progressing:     conditionStatus(addon.Status.Conditions, conditionProgressing),
+		AddonUID:        string(addon.GetUID()),
+		ResourceVersion: addon.GetResourceVersion(),
+	}
+
+	// report why the addon isn't healthy
+	if condition := meta.FindStatusCondition(addon.Status.Conditions,
+		addonv1alpha1.ManagedClusterAddOnConditionDegraded); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		addonStatus.Message = condition.Message
+	} else if condition := meta.FindStatusCondition(addon.Status.Conditions,
+		addonv1alpha1.ManagedClusterAddOnConditionAvailable); condition != nil &&
+		condition.Status != metav1.ConditionTrue {
+		addonStatus.Message = condition.Message
+	}
+
+	return addonStatus
+}
+
+// conditionStatus returns the status of the condition, a missing condition is unknown.
+func conditionStatus(conditions []metav1.Condition, conditionType string) string {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		return string(metav1.ConditionUnknown)
+	}
+
+	return string(condition.Status)
+}
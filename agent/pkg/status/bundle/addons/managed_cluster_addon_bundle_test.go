@@ -0,0 +1,86 @@
+package addons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestManagedClusterAddOnStatusBundle(t *testing.T) {
+	clusterIDs := map[string]string{"cluster1": "3f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11"}
+	resolveClusterIDFunc := func(clusterName string) string { return clusterIDs[clusterName] }
+
+	addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "observability-controller",
+			Namespace:       "cluster1",
+			UID:             "8d1f0a5c-6a3e-4d1b-9c0e-1f2a3b4c5d6e",
+			ResourceVersion: "1",
+		},
+		Status: addonv1alpha1.ManagedClusterAddOnStatus{
+			Conditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionTrue},
+				{Type: "Degraded", Status: metav1.ConditionTrue, Message: "metrics collector failed"},
+			},
+		},
+	}
+
+	bundle := NewManagedClusterAddOnStatusBundle("hub1", 0, resolveClusterIDFunc).(*ManagedClusterAddOnStatusBundle)
+	bundle.UpdateObject(addon)
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, status.ManagedClusterAddOnStatus{
+		ClusterID:       "3f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11",
+		ClusterName:     "cluster1",
+		AddonName:       "observability-controller",
+		Available:       "True",
+		Degraded:        "True",
+		Progressing:     "Unknown",
+		Message:         "metrics collector failed",
+		AddonUID:        "8d1f0a5c-6a3e-4d1b-9c0e-1f2a3b4c5d6e",
+		ResourceVersion: "1",
+	}, *bundle.Objects[0])
+
+	// the same resource version doesn't change the bundle
+	bundle.UpdateObject(addon.DeepCopy())
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+
+	// the addons of the cluster without id are reported with the cluster name only
+	cluster2Addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "observability-controller", Namespace: "cluster2", UID: "2", ResourceVersion: "1",
+		},
+	}
+	bundle.UpdateObject(cluster2Addon)
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 2)
+	assert.Equal(t, "", bundle.Objects[1].ClusterID)
+	assert.Equal(t, "cluster2", bundle.Objects[1].ClusterName)
+
+	// the cluster id is resolved later, the addon is updated even if the addon itself isn't changed
+	clusterIDs["cluster2"] = "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d"
+	bundle.UpdateObject(cluster2Addon.DeepCopy())
+	assert.Equal(t, uint64(3), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d", bundle.Objects[1].ClusterID)
+
+	unavailableAddon := addon.DeepCopy()
+	unavailableAddon.ResourceVersion = "2"
+	unavailableAddon.Status.Conditions = []metav1.Condition{
+		{Type: "Available", Status: metav1.ConditionFalse, Message: "lease not updated"},
+	}
+	bundle.UpdateObject(unavailableAddon)
+	assert.Equal(t, uint64(4), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, "False", bundle.Objects[0].Available)
+	assert.Equal(t, "Unknown", bundle.Objects[0].Degraded)
+	assert.Equal(t, "lease not updated", bundle.Objects[0].Message)
+
+	// deleted without uid, the addon is resolved from the cluster and addon name
+	bundle.DeleteObject(&addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "observability-controller", Namespace: "cluster1"},
+	})
+	assert.Equal(t, uint64(5), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 1)
+}
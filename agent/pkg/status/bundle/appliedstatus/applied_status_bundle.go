@@ -0,0 +1,91 @@
+package appliedstatus
+
+import (
+	"encoding/json"
+	"sync"
+
+	statusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// NewBundle creates a new instance of Bundle.
+func NewBundle(leafHubName string, incarnation uint64) *Bundle {
+	return &Bundle{
+		BaseAppliedStatusBundle: status.BaseAppliedStatusBundle{
+			Objects:       make([]*status.AppliedStatus, 0),
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(incarnation, 0),
+		},
+		lock: sync.Mutex{},
+	}
+}
+
+// Bundle holds the applied status of the global resources received from the global hub.
+// the objects aren't watched from the cluster, they are reported by the spec syncers once applied.
+type Bundle struct {
+	status.BaseAppliedStatusBundle
+	lock sync.Mutex
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *Bundle) UpdateObject(statusbundle.Object) {
+	// do nothing, the applied status is reported by UpdateAppliedStatus
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *Bundle) DeleteObject(statusbundle.Object) {
+	// do nothing, the applied status is removed by DeleteAppliedStatus
+}
+
+// GetBundleVersion function to get bundle version, it returns a copy since the version is updated by the spec syncer
+// workers while the copy is read by the status syncer.
+func (bundle *Bundle) GetBundleVersion() *status.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	version := *bundle.BundleVersion
+	return &version
+}
+
+// UpdateAppliedStatus adds or updates the applied status of a global resource.
+func (bundle *Bundle) UpdateAppliedStatus(appliedStatus *status.AppliedStatus) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	for i, object := range bundle.Objects {
+		if object.ResourceID != appliedStatus.ResourceID {
+			continue
+		}
+		if *object == *appliedStatus {
+			return // nothing changed
+		}
+		bundle.Objects[i] = appliedStatus
+		bundle.BundleVersion.Generation++
+		return
+	}
+
+	bundle.Objects = append(bundle.Objects, appliedStatus)
+	bundle.BundleVersion.Generation++
+}
+
+// DeleteAppliedStatus removes the applied status of a global resource which was deleted from the leaf hub.
+func (bundle *Bundle) DeleteAppliedStatus(resourceID string) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	for i, object := range bundle.Objects {
+		if object.ResourceID == resourceID {
+			bundle.Objects = append(bundle.Objects[:i], bundle.Objects[i+1:]...)
+			bundle.BundleVersion.Generation++
+			return
+		}
+	}
+}
+
+// MarshalJSON marshals the bundle while holding the lock, the objects are updated by the spec syncer workers.
+func (bundle *Bundle) MarshalJSON() ([]byte, error) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return json.Marshal(&bundle.BaseAppliedStatusBundle)
+}
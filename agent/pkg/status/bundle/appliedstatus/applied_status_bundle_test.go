@@ -0,0 +1,57 @@
+package appliedstatus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestAppliedStatusBundle(t *testing.T) {
+	bundle := NewBundle("hub1", 1)
+
+	appliedStatus := &status.AppliedStatus{
+		ResourceID:    "a789c8c1-137b-4b78-9412-9f101b08cc91",
+		Kind:          "Policy",
+		Name:          "policy-limitrange",
+		Namespace:     "default",
+		BundleVersion: "2022-10-26_08-32-00.739891",
+		Applied:       true,
+	}
+
+	bundle.UpdateAppliedStatus(appliedStatus)
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+
+	// the same status shouldn't increase the generation
+	sameStatus := *appliedStatus
+	bundle.UpdateAppliedStatus(&sameStatus)
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+
+	// the returned version is a copy, it isn't changed by the later updates
+	version := bundle.GetBundleVersion()
+	failedStatus := *appliedStatus
+	failedStatus.Applied = false
+	failedStatus.Error = "admission webhook denied the request"
+	bundle.UpdateAppliedStatus(&failedStatus)
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, uint64(1), version.Generation)
+	assert.Equal(t, 1, len(bundle.Objects))
+
+	payload, err := json.Marshal(bundle)
+	assert.NoError(t, err)
+	received := status.NewAppliedStatusBundle()
+	assert.NoError(t, json.Unmarshal(payload, received))
+	assert.Equal(t, "hub1", received.GetLeafHubName())
+	assert.Equal(t, 1, len(received.GetObjects()))
+	assert.Equal(t, failedStatus.Error, received.GetObjects()[0].(*status.AppliedStatus).Error)
+
+	bundle.DeleteAppliedStatus(appliedStatus.ResourceID)
+	assert.Equal(t, uint64(3), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, 0, len(bundle.Objects))
+
+	// deleting an unknown resource shouldn't increase the generation
+	bundle.DeleteAppliedStatus(appliedStatus.ResourceID)
+	assert.Equal(t, uint64(3), bundle.GetBundleVersion().Generation)
+}
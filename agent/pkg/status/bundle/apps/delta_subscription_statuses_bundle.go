@@ -0,0 +1,175 @@
+package apps
+
+import (
+	"fmt"
+	"sync"
+
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+
+	bundlepkg "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	statusbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// NewDeltaSubscriptionStatusesBundle creates a new instance of DeltaSubscriptionStatusesBundle.
+func NewDeltaSubscriptionStatusesBundle(leafHubName string, baseBundle bundlepkg.Bundle, incarnation uint64,
+	extractObjIDFunc bundlepkg.ExtractObjIDFunc,
+) bundlepkg.DeltaStateBundle {
+	return &DeltaSubscriptionStatusesBundle{
+		BaseDeltaSubscriptionStatusesBundle: statusbundle.BaseDeltaSubscriptionStatusesBundle{
+			Objects:          make([]*appsv1alpha1.SubscriptionStatus, 0),
+			DeletedObjectIDs: make([]string, 0),
+			LeafHubName:      leafHubName,
+			BaseBundleVersion: statusbundle.NewBundleVersion(incarnation,
+				baseBundle.GetBundleVersion().Generation),
+			BundleVersion: statusbundle.NewBundleVersion(incarnation, 0),
+		},
+		cyclicTransportationBundleID: 0,
+		baseBundle:                   baseBundle,
+		objectIDsCache:               make(map[string]string),
+		extractObjIDFunc:             extractObjIDFunc,
+		lock:                         sync.Mutex{},
+	}
+}
+
+// DeltaSubscriptionStatusesBundle abstracts management of delta subscription statuses bundle. it only carries the
+// subscription statuses that changed since the last delivered bundle, which keeps the bundles of the leaf hubs with
+// large application fleets small.
+type DeltaSubscriptionStatusesBundle struct {
+	statusbundle.BaseDeltaSubscriptionStatusesBundle
+	cyclicTransportationBundleID int
+	baseBundle                   bundlepkg.Bundle
+	// objectIDsCache maps the namespaced name of every known subscription status to its id, it's needed since the
+	// objects deleted without finalizer are received without their annotations.
+	objectIDsCache   map[string]string
+	extractObjIDFunc bundlepkg.ExtractObjIDFunc
+	lock             sync.Mutex
+}
+
+// GetTransportationID function to get bundle transportation ID to be attached to message-key during transportation.
+func (bundle *DeltaSubscriptionStatusesBundle) GetTransportationID() int {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.cyclicTransportationBundleID
+}
+
+// GetDependencyVersion returns the version of the complete state bundle the delta state bundle is based on.
+func (bundle *DeltaSubscriptionStatusesBundle) GetDependencyVersion() *statusbundle.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return statusbundle.NewBundleVersion(bundle.BaseBundleVersion.Incarnation, bundle.BaseBundleVersion.Generation)
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *DeltaSubscriptionStatusesBundle) UpdateObject(object bundlepkg.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	subscriptionStatus, isSubscriptionStatus := object.(*appsv1alpha1.SubscriptionStatus)
+	if !isSubscriptionStatus {
+		return // do not handle objects other than subscription status
+	}
+
+	objID, ok := bundle.extractObjIDFunc(object)
+	if !ok {
+		return // can't update the object without finding its id
+	}
+
+	bundle.objectIDsCache[getNamespacedName(object)] = objID
+	bundle.removeDeletedObjectID(objID)
+
+	index := bundle.getObjectIndexByID(objID)
+	if index < 0 { // object not found, need to add it to the bundle
+		bundle.Objects = append(bundle.Objects, subscriptionStatus)
+		bundle.BundleVersion.Generation++
+
+		return
+	}
+
+	if subscriptionStatus.GetResourceVersion() == bundle.Objects[index].GetResourceVersion() {
+		return // update in bundle only if object changed. check for changes using resourceVersion field
+	}
+
+	bundle.Objects[index] = subscriptionStatus
+	bundle.BundleVersion.Generation++
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *DeltaSubscriptionStatusesBundle) DeleteObject(object bundlepkg.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	if _, isSubscriptionStatus := object.(*appsv1alpha1.SubscriptionStatus); !isSubscriptionStatus {
+		return // do not handle objects other than subscription status
+	}
+
+	namespacedName := getNamespacedName(object)
+	objID, ok := bundle.extractObjIDFunc(object)
+	if !ok {
+		if objID, ok = bundle.objectIDsCache[namespacedName]; !ok {
+			return // trying to delete object which doesn't exist - return with no error
+		}
+	}
+	delete(bundle.objectIDsCache, namespacedName)
+
+	if index := bundle.getObjectIndexByID(objID); index >= 0 {
+		bundle.Objects = append(bundle.Objects[:index], bundle.Objects[index+1:]...) // remove from objects
+	}
+
+	bundle.DeletedObjectIDs = append(bundle.DeletedObjectIDs, objID)
+	bundle.BundleVersion.Generation++
+}
+
+// GetBundleVersion function to get bundle version.
+func (bundle *DeltaSubscriptionStatusesBundle) GetBundleVersion() *statusbundle.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.BundleVersion
+}
+
+// SyncState syncs the state of the delta-bundle with the full-state.
+func (bundle *DeltaSubscriptionStatusesBundle) SyncState() {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	// update version, the following delta bundles are on top of the complete state bundle that was just sent
+	bundle.BaseBundleVersion.Generation = bundle.baseBundle.GetBundleVersion().Generation
+
+	// reset ID since state-sync means base has changed and a new line is starting
+	bundle.cyclicTransportationBundleID = 0
+}
+
+// Reset flushes the objects in the bundle (after delivery).
+func (bundle *DeltaSubscriptionStatusesBundle) Reset() {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	bundle.Objects = nil                  // safe since go1.0
+	bundle.DeletedObjectIDs = nil         // safe since go1.0
+	bundle.cyclicTransportationBundleID++ // increment ID since a reset means a new bundle is starting
+}
+
+func (bundle *DeltaSubscriptionStatusesBundle) getObjectIndexByID(objID string) int {
+	for i, object := range bundle.Objects {
+		if id, ok := bundle.extractObjIDFunc(object); ok && id == objID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (bundle *DeltaSubscriptionStatusesBundle) removeDeletedObjectID(objID string) {
+	for i, deletedObjID := range bundle.DeletedObjectIDs {
+		if deletedObjID == objID {
+			bundle.DeletedObjectIDs = append(bundle.DeletedObjectIDs[:i], bundle.DeletedObjectIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+func getNamespacedName(object bundlepkg.Object) string {
+	return fmt.Sprintf("%s/%s", object.GetNamespace(), object.GetName())
+}
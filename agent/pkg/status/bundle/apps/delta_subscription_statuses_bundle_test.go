@@ -0,0 +1,83 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestDeltaSubscriptionStatusesBundle(t *testing.T) {
+	extractObjIDFunc := func(obj bundle.Object) (string, bool) {
+		val, ok := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+		return val, ok
+	}
+	baseBundle := bundle.NewGenericStatusBundle("hub1", 1, nil)
+	deltaStateBundle := NewDeltaSubscriptionStatusesBundle("hub1", baseBundle, 1, extractObjIDFunc)
+	deltaBundle := deltaStateBundle.(*DeltaSubscriptionStatusesBundle)
+
+	appsubStatus := &appsv1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "appsub",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			Annotations: map[string]string{
+				constants.OriginOwnerReferenceAnnotation: "2aa5547c-c172-47ed-b70b-db468c84d327",
+			},
+		},
+	}
+
+	// objects without the origin id are not tracked
+	deltaBundle.UpdateObject(&appsv1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-appsub", Namespace: "default"},
+	})
+	assert.Equal(t, uint64(0), deltaBundle.GetBundleVersion().Generation)
+
+	deltaBundle.UpdateObject(appsubStatus)
+	assert.Equal(t, uint64(1), deltaBundle.GetBundleVersion().Generation)
+	assert.Len(t, deltaBundle.Objects, 1)
+
+	// the same resource version doesn't change the bundle
+	deltaBundle.UpdateObject(appsubStatus.DeepCopy())
+	assert.Equal(t, uint64(1), deltaBundle.GetBundleVersion().Generation)
+
+	// the delivered objects are flushed, the new line starts on top of the base bundle
+	baseBundle.UpdateObject(appsubStatus)
+	deltaBundle.Reset()
+	deltaBundle.SyncState()
+	assert.Empty(t, deltaBundle.Objects)
+	assert.Equal(t, 0, deltaBundle.GetTransportationID())
+	assert.Equal(t, baseBundle.GetBundleVersion().Generation, deltaBundle.BaseBundleVersion.Generation)
+
+	updatedStatus := appsubStatus.DeepCopy()
+	updatedStatus.ResourceVersion = "2"
+	deltaBundle.UpdateObject(updatedStatus)
+	assert.Equal(t, uint64(2), deltaBundle.GetBundleVersion().Generation)
+	assert.Len(t, deltaBundle.Objects, 1)
+
+	// deleted without finalizer, the id is resolved from the namespaced name
+	deltaBundle.DeleteObject(&appsv1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "appsub", Namespace: "default"},
+	})
+	assert.Equal(t, uint64(3), deltaBundle.GetBundleVersion().Generation)
+	assert.Empty(t, deltaBundle.Objects)
+	assert.Equal(t, []string{"2aa5547c-c172-47ed-b70b-db468c84d327"}, deltaBundle.DeletedObjectIDs)
+
+	// deleting an unknown object is ignored
+	deltaBundle.DeleteObject(&appsv1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "default"},
+	})
+	assert.Equal(t, uint64(3), deltaBundle.GetBundleVersion().Generation)
+
+	// recreating the object drops it from the deleted ids
+	deltaBundle.UpdateObject(appsubStatus)
+	assert.Empty(t, deltaBundle.DeletedObjectIDs)
+	assert.Len(t, deltaBundle.Objects, 1)
+
+	deltaBundle.Reset()
+	assert.Equal(t, 1, deltaBundle.GetTransportationID())
+}
@@ -0,0 +1,117 @@
+package argocd
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	statusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// NewApplicationStatusBundle creates a new instance of ApplicationStatusBundle.
+func NewApplicationStatusBundle(leafHubName string, incarnation uint64) statusbundle.Bundle {
+	return &ApplicationStatusBundle{
+		BaseArgoCDApplicationStatusBundle: status.BaseArgoCDApplicationStatusBundle{
+			Objects:       make([]*status.ArgoCDApplicationStatus, 0),
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(incarnation, 0),
+		},
+		lock: sync.Mutex{},
+	}
+}
+
+// ApplicationStatusBundle holds the sync and health status of the Argo CD Applications on the leaf hub, the
+// applications are watched as unstructured objects so the agent doesn't depend on the Argo CD APIs.
+type ApplicationStatusBundle struct {
+	status.BaseArgoCDApplicationStatusBundle
+	lock sync.Mutex
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *ApplicationStatusBundle) UpdateObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	application, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	applicationStatus := toApplicationStatus(application)
+	for i, obj := range bundle.Objects {
+		if obj.ApplicationID != applicationStatus.ApplicationID {
+			continue
+		}
+		if obj.ResourceVersion == applicationStatus.ResourceVersion {
+			return // update in bundle only if object changed. check for changes using resourceVersion field
+		}
+		bundle.Objects[i] = applicationStatus
+		bundle.BundleVersion.Generation++
+		return
+	}
+
+	bundle.Objects = append(bundle.Objects, applicationStatus)
+	bundle.BundleVersion.Generation++
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *ApplicationStatusBundle) DeleteObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	for i, obj := range bundle.Objects {
+		if (len(object.GetUID()) > 0 && obj.ApplicationID == string(object.GetUID())) ||
+			(len(object.GetUID()) == 0 && obj.Namespace == object.GetNamespace() && obj.Name == object.GetName()) {
+			bundle.Objects = append(bundle.Objects[:i], bundle.Objects[i+1:]...)
+			bundle.BundleVersion.Generation++
+			return
+		}
+	}
+}
+
+// GetBundleVersion function to get bundle version.
+func (bundle *ApplicationStatusBundle) GetBundleVersion() *status.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.BundleVersion
+}
+
+func toApplicationStatus(application *unstructured.Unstructured) *status.ArgoCDApplicationStatus {
+	applicationStatus := &status.ArgoCDApplicationStatus{
+		ApplicationID:        string(application.GetUID()),
+		Name:                 application.GetName(),
+		Namespace:            application.GetNamespace(),
+		Project:              nestedString(application, "spec", "project"),
+		RepoURL:              nestedString(application, "spec", "source", "repoURL"),
+		TargetRevision:       nestedString(application, "spec", "source", "targetRevision"),
+		Revision:             nestedString(application, "status", "sync", "revision"),
+		DestinationServer:    nestedString(application, "spec", "destination", "server"),
+		DestinationName:      nestedString(application, "spec", "destination", "name"),
+		DestinationNamespace: nestedString(application, "spec", "destination", "namespace"),
+		SyncStatus:           nestedString(application, "status", "sync", "status"),
+		HealthStatus:         nestedString(application, "status", "health", "status"),
+		HealthMessage:        nestedString(application, "status", "health", "message"),
+		OperationPhase:       nestedString(application, "status", "operationState", "phase"),
+		ReconciledAt:         nestedString(application, "status", "reconciledAt"),
+		ResourceVersion:      application.GetResourceVersion(),
+	}
+
+	// multi-source applications have no spec.source, report the first of the sources instead
+	if sources, found, _ := unstructured.NestedSlice(application.Object, "spec", "sources"); found && len(sources) > 0 &&
+		applicationStatus.RepoURL == "" {
+		if source, ok := sources[0].(map[string]interface{}); ok {
+			applicationStatus.RepoURL, _, _ = unstructured.NestedString(source, "repoURL")
+			applicationStatus.TargetRevision, _, _ = unstructured.NestedString(source, "targetRevision")
+		}
+	}
+
+	return applicationStatus
+}
+
+// nestedString returns the string value of the field, or empty string if the field is missing or isn't a string.
+func nestedString(obj *unstructured.Unstructured, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj.Object, fields...)
+	return value
+}
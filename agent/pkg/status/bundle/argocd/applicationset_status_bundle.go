@@ -0,0 +1,124 @@
+package argocd
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	statusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+const (
+	conditionErrorOccurred     = "ErrorOccurred"
+	conditionResourcesUpToDate = "ResourcesUpToDate"
+)
+
+// NewApplicationSetStatusBundle creates a new instance of ApplicationSetStatusBundle.
+func NewApplicationSetStatusBundle(leafHubName string, incarnation uint64) statusbundle.Bundle {
+	return &ApplicationSetStatusBundle{
+		BaseArgoCDApplicationSetStatusBundle: status.BaseArgoCDApplicationSetStatusBundle{
+			Objects:       make([]*status.ArgoCDApplicationSetStatus, 0),
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(incarnation, 0),
+		},
+		lock: sync.Mutex{},
+	}
+}
+
+// ApplicationSetStatusBundle holds the status of the Argo CD ApplicationSets on the leaf hub. the generated
+// applications are reported with their applicationset, so the global hub is able to aggregate them.
+type ApplicationSetStatusBundle struct {
+	status.BaseArgoCDApplicationSetStatusBundle
+	lock sync.Mutex
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *ApplicationSetStatusBundle) UpdateObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	applicationSet, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	applicationSetStatus := toApplicationSetStatus(applicationSet)
+	for i, obj := range bundle.Objects {
+		if obj.ApplicationSetID != applicationSetStatus.ApplicationSetID {
+			continue
+		}
+		if obj.ResourceVersion == applicationSetStatus.ResourceVersion {
+			return // update in bundle only if object changed. check for changes using resourceVersion field
+		}
+		bundle.Objects[i] = applicationSetStatus
+		bundle.BundleVersion.Generation++
+		return
+	}
+
+	bundle.Objects = append(bundle.Objects, applicationSetStatus)
+	bundle.BundleVersion.Generation++
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *ApplicationSetStatusBundle) DeleteObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	for i, obj := range bundle.Objects {
+		if (len(object.GetUID()) > 0 && obj.ApplicationSetID == string(object.GetUID())) ||
+			(len(object.GetUID()) == 0 && obj.Namespace == object.GetNamespace() && obj.Name == object.GetName()) {
+			bundle.Objects = append(bundle.Objects[:i], bundle.Objects[i+1:]...)
+			bundle.BundleVersion.Generation++
+			return
+		}
+	}
+}
+
+// GetBundleVersion function to get bundle version.
+func (bundle *ApplicationSetStatusBundle) GetBundleVersion() *status.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.BundleVersion
+}
+
+func toApplicationSetStatus(applicationSet *unstructured.Unstructured) *status.ArgoCDApplicationSetStatus {
+	applicationSetStatus := &status.ArgoCDApplicationSetStatus{
+		ApplicationSetID: string(applicationSet.GetUID()),
+		Name:             applicationSet.GetName(),
+		Namespace:        applicationSet.GetNamespace(),
+		Applications:     make([]string, 0),
+		ResourceVersion:  applicationSet.GetResourceVersion(),
+	}
+
+	// the generated applications are listed in the status resources
+	resources, _, _ := unstructured.NestedSlice(applicationSet.Object, "status", "resources")
+	for _, resource := range resources {
+		resourceMap, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(resourceMap, "name"); name != "" {
+			applicationSetStatus.Applications = append(applicationSetStatus.Applications, name)
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(applicationSet.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(conditionMap, "type")
+		conditionStatus, _, _ := unstructured.NestedString(conditionMap, "status")
+		switch {
+		case conditionType == conditionResourcesUpToDate:
+			applicationSetStatus.ResourcesUpToDate = conditionStatus == "True"
+		case conditionType == conditionErrorOccurred && conditionStatus == "True":
+			applicationSetStatus.ErrorMessage, _, _ = unstructured.NestedString(conditionMap, "message")
+		}
+	}
+
+	return applicationSetStatus
+}
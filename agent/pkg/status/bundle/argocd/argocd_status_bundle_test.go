@@ -0,0 +1,124 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestApplicationStatusBundle(t *testing.T) {
+	application := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":            "guestbook",
+			"namespace":       "openshift-gitops",
+			"uid":             "6f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11",
+			"resourceVersion": "1",
+		},
+		"spec": map[string]interface{}{
+			"project": "default",
+			"sources": []interface{}{
+				map[string]interface{}{
+					"repoURL":        "https://github.com/argoproj/argocd-example-apps.git",
+					"targetRevision": "HEAD",
+				},
+			},
+			"destination": map[string]interface{}{
+				"server":    "https://kubernetes.default.svc",
+				"namespace": "guestbook",
+			},
+		},
+		"status": map[string]interface{}{
+			"sync":   map[string]interface{}{"status": "Synced", "revision": "53e28ff"},
+			"health": map[string]interface{}{"status": "Degraded", "message": "Deployment exceeded its progress deadline"},
+			"operationState": map[string]interface{}{
+				"phase": "Succeeded",
+			},
+		},
+	}}
+
+	bundle := NewApplicationStatusBundle("hub1", 0).(*ApplicationStatusBundle)
+	bundle.UpdateObject(application)
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 1)
+	assert.Equal(t, status.ArgoCDApplicationStatus{
+		ApplicationID:        "6f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11",
+		Name:                 "guestbook",
+		Namespace:            "openshift-gitops",
+		Project:              "default",
+		RepoURL:              "https://github.com/argoproj/argocd-example-apps.git",
+		TargetRevision:       "HEAD",
+		Revision:             "53e28ff",
+		DestinationServer:    "https://kubernetes.default.svc",
+		DestinationNamespace: "guestbook",
+		SyncStatus:           "Synced",
+		HealthStatus:         "Degraded",
+		HealthMessage:        "Deployment exceeded its progress deadline",
+		OperationPhase:       "Succeeded",
+		ResourceVersion:      "1",
+	}, *bundle.Objects[0])
+
+	// the same resource version doesn't change the bundle
+	bundle.UpdateObject(application.DeepCopy())
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+
+	updatedApplication := application.DeepCopy()
+	updatedApplication.SetResourceVersion("2")
+	assert.NoError(t, unstructured.SetNestedField(updatedApplication.Object, "Healthy", "status", "health", "status"))
+	bundle.UpdateObject(updatedApplication)
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 1)
+	assert.Equal(t, "Healthy", bundle.Objects[0].HealthStatus)
+
+	// deleted without uid, the application is resolved from the namespaced name
+	deletedApplication := &unstructured.Unstructured{}
+	deletedApplication.SetNamespace("openshift-gitops")
+	deletedApplication.SetName("guestbook")
+	bundle.DeleteObject(deletedApplication)
+	assert.Equal(t, uint64(3), bundle.GetBundleVersion().Generation)
+	assert.Empty(t, bundle.Objects)
+}
+
+func TestApplicationSetStatusBundle(t *testing.T) {
+	applicationSet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "ApplicationSet",
+		"metadata": map[string]interface{}{
+			"name":            "guestbook",
+			"namespace":       "openshift-gitops",
+			"uid":             "0b8a0a2c-9a4c-4a44-8f4e-2b8b9a3c6d21",
+			"resourceVersion": "1",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "ErrorOccurred", "status": "True", "message": "cluster not found"},
+				map[string]interface{}{"type": "ResourcesUpToDate", "status": "False"},
+			},
+			"resources": []interface{}{
+				map[string]interface{}{"name": "cluster1-guestbook", "kind": "Application"},
+				map[string]interface{}{"name": "cluster2-guestbook", "kind": "Application"},
+			},
+		},
+	}}
+
+	bundle := NewApplicationSetStatusBundle("hub1", 0).(*ApplicationSetStatusBundle)
+	bundle.UpdateObject(applicationSet)
+	assert.Equal(t, uint64(1), bundle.GetBundleVersion().Generation)
+	assert.Equal(t, status.ArgoCDApplicationSetStatus{
+		ApplicationSetID:  "0b8a0a2c-9a4c-4a44-8f4e-2b8b9a3c6d21",
+		Name:              "guestbook",
+		Namespace:         "openshift-gitops",
+		Applications:      []string{"cluster1-guestbook", "cluster2-guestbook"},
+		ResourcesUpToDate: false,
+		ErrorMessage:      "cluster not found",
+		ResourceVersion:   "1",
+	}, *bundle.Objects[0])
+
+	bundle.DeleteObject(applicationSet)
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+	assert.Empty(t, bundle.Objects)
+}
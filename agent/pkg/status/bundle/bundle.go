@@ -36,6 +36,8 @@ type DeltaStateBundle interface {
 	Bundle
 	// GetTransportationID function to get bundle transportation ID to be attached to message-key during transportation.
 	GetTransportationID() int
+	// GetDependencyVersion returns the version of the complete state bundle the delta state bundle is based on.
+	GetDependencyVersion() *status.BundleVersion
 	// SyncState syncs the state of the delta-bundle with the full-state.
 	SyncState()
 	// Reset flushes the delta-state bundle's objects.
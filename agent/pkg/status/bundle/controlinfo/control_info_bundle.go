@@ -12,14 +12,16 @@ func NewBundle(leafHubName string, incarnation uint64) *Bundle {
 	return &Bundle{
 		LeafHubName:   leafHubName,
 		BundleVersion: status.NewBundleVersion(incarnation, 0),
+		Capabilities:  status.NewCapabilities(),
 		lock:          sync.Mutex{},
 	}
 }
 
-// Bundle holds control info passed from LH to HoH.
+// Bundle holds control info passed from LH to HoH, it advertises the capabilities of the agent to the manager.
 type Bundle struct {
 	LeafHubName   string                `json:"leafHubName"`
 	BundleVersion *status.BundleVersion `json:"bundleVersion"`
+	Capabilities  *status.Capabilities  `json:"capabilities"`
 	lock          sync.Mutex
 }
 
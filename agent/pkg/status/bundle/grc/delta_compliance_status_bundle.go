@@ -65,6 +65,14 @@ func (bundle *DeltaComplianceStatusBundle) GetTransportationID() int {
 	return bundle.cyclicTransportationBundleID
 }
 
+// GetDependencyVersion returns the version of the complete state bundle the delta state bundle is based on.
+func (bundle *DeltaComplianceStatusBundle) GetDependencyVersion() *statusbundle.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return statusbundle.NewBundleVersion(bundle.BaseBundleVersion.Incarnation, bundle.BaseBundleVersion.Generation)
+}
+
 // UpdateObject function to update a single object inside a bundle.
 func (bundle *DeltaComplianceStatusBundle) UpdateObject(object bundlepkg.Object) {
 	bundle.lock.Lock()
@@ -0,0 +1,40 @@
+package bundle
+
+import (
+	"fmt"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// NewManagedClustersStatusBundle creates a new instance of ManagedClustersStatusBundle.
+func NewManagedClustersStatusBundle(leafHubName string, incarnation uint64, manipulateObjFunc func(obj Object),
+) Bundle {
+	return &ManagedClustersStatusBundle{
+		GenericStatusBundle: NewGenericStatusBundle(leafHubName, incarnation, manipulateObjFunc).(*GenericStatusBundle),
+	}
+}
+
+// ManagedClustersStatusBundle is the generic status bundle of the managed clusters, it's able to be encoded as
+// protobuf.
+type ManagedClustersStatusBundle struct {
+	*GenericStatusBundle
+}
+
+// MarshalProto encodes the bundle as the ManagedClustersBundle message.
+func (bundle *ManagedClustersStatusBundle) MarshalProto() ([]byte, error) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	clusters := make([]*clusterv1.ManagedCluster, 0, len(bundle.Objects))
+	for _, object := range bundle.Objects {
+		cluster, ok := object.(*clusterv1.ManagedCluster)
+		if !ok {
+			return nil, fmt.Errorf("the object %T of the bundle isn't a managed cluster", object)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return status.MarshalManagedClustersProto(bundle.LeafHubName, bundle.BundleVersion, clusters)
+}
@@ -0,0 +1,269 @@
+package policyreports
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	statusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+const (
+	// KyvernoReportGroup is the api group of the kyverno PolicyReport and ClusterPolicyReport.
+	KyvernoReportGroup = "wgpolicyk8s.io"
+	// GatekeeperConstraintGroup is the api group of the gatekeeper constraints.
+	GatekeeperConstraintGroup = "constraints.gatekeeper.sh"
+
+	// maxViolationsPerReport limits the violations of a report in the bundle, the counts of the report are still the
+	// total counts. gatekeeper reports up to 20 violations per constraint by default.
+	maxViolationsPerReport = 100
+)
+
+// ResolveClusterNameFunc returns the managed cluster name of the report namespace, or empty string if the namespace
+// doesn't belong to a managed cluster.
+type ResolveClusterNameFunc func(namespace string) string
+
+// NewPolicyReportBundle creates a new instance of PolicyReportBundle.
+func NewPolicyReportBundle(leafHubName string, incarnation uint64,
+	resolveClusterNameFunc ResolveClusterNameFunc,
+) statusbundle.Bundle {
+	return &PolicyReportBundle{
+		BasePolicyReportBundle: status.BasePolicyReportBundle{
+			Objects:       make([]*status.PolicyReport, 0),
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(incarnation, 0),
+		},
+		resolveClusterNameFunc: resolveClusterNameFunc,
+		lock:                   sync.Mutex{},
+	}
+}
+
+// PolicyReportBundle holds the normalized kyverno policy reports and gatekeeper constraints of the leaf hub.
+type PolicyReportBundle struct {
+	status.BasePolicyReportBundle
+	resolveClusterNameFunc ResolveClusterNameFunc
+	lock                   sync.Mutex
+}
+
+// UpdateObject function to update a single object inside a bundle.
+func (bundle *PolicyReportBundle) UpdateObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	bundle.updateObject(object)
+}
+
+// DeleteObject function to delete a single object inside a bundle.
+func (bundle *PolicyReportBundle) DeleteObject(object statusbundle.Object) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	index := bundle.getObjectIndex(string(object.GetUID()))
+	if index < 0 { // trying to delete object which doesn't exist - return with no error
+		return
+	}
+
+	bundle.Objects = append(bundle.Objects[:index], bundle.Objects[index+1:]...)
+	bundle.BundleVersion.Generation++
+}
+
+// SyncObjects updates the bundle with the listed reports, the reports that aren't listed anymore are deleted.
+func (bundle *PolicyReportBundle) SyncObjects(objects []*unstructured.Unstructured) {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	listed := make(map[string]struct{}, len(objects))
+	for _, object := range objects {
+		listed[string(object.GetUID())] = struct{}{}
+		bundle.updateObject(object)
+	}
+
+	reports := make([]*status.PolicyReport, 0, len(bundle.Objects))
+	for _, report := range bundle.Objects {
+		if _, found := listed[report.ReportID]; found {
+			reports = append(reports, report)
+		}
+	}
+
+	if len(reports) != len(bundle.Objects) {
+		bundle.Objects = reports
+		bundle.BundleVersion.Generation++
+	}
+}
+
+// GetBundleVersion function to get bundle version.
+func (bundle *PolicyReportBundle) GetBundleVersion() *status.BundleVersion {
+	bundle.lock.Lock()
+	defer bundle.lock.Unlock()
+
+	return bundle.BundleVersion
+}
+
+func (bundle *PolicyReportBundle) updateObject(object statusbundle.Object) {
+	unstructuredObj, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	report := toPolicyReport(unstructuredObj)
+	if report == nil {
+		return
+	}
+	if report.Namespace != "" {
+		report.ClusterName = bundle.resolveClusterNameFunc(report.Namespace)
+	}
+
+	index := bundle.getObjectIndex(report.ReportID)
+	if index < 0 { // object not found, need to add it to the bundle
+		bundle.Objects = append(bundle.Objects, report)
+		bundle.BundleVersion.Generation++
+		return
+	}
+
+	if bundle.Objects[index].ResourceVersion == report.ResourceVersion {
+		return // update in bundle only if object changed. check for changes using resourceVersion field
+	}
+
+	bundle.Objects[index] = report
+	bundle.BundleVersion.Generation++
+}
+
+func (bundle *PolicyReportBundle) getObjectIndex(reportID string) int {
+	for i, obj := range bundle.Objects {
+		if obj.ReportID == reportID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// toPolicyReport normalizes the report of the policy engine, nil is returned for an unknown object.
+func toPolicyReport(object *unstructured.Unstructured) *status.PolicyReport {
+	var report *status.PolicyReport
+
+	switch object.GroupVersionKind().Group {
+	case KyvernoReportGroup:
+		report = fromKyvernoReport(object)
+	case GatekeeperConstraintGroup:
+		report = fromGatekeeperConstraint(object)
+	default:
+		return nil
+	}
+
+	report.ReportID = string(object.GetUID())
+	report.Kind = object.GetKind()
+	report.Name = object.GetName()
+	report.Namespace = object.GetNamespace()
+	report.ResourceVersion = object.GetResourceVersion()
+
+	return report
+}
+
+// fromKyvernoReport converts the results of the kyverno PolicyReport or ClusterPolicyReport, each resource of the
+// failed results is a violation.
+func fromKyvernoReport(object *unstructured.Unstructured) *status.PolicyReport {
+	report := &status.PolicyReport{Engine: status.PolicyEngineKyverno}
+	report.Pass, _, _ = unstructured.NestedInt64(object.Object, "summary", "pass")
+	report.Fail, _, _ = unstructured.NestedInt64(object.Object, "summary", "fail")
+	report.Warn, _, _ = unstructured.NestedInt64(object.Object, "summary", "warn")
+	report.Error, _, _ = unstructured.NestedInt64(object.Object, "summary", "error")
+	report.Skip, _, _ = unstructured.NestedInt64(object.Object, "summary", "skip")
+
+	// the per resource reports of the newer kyverno releases set the resource as the report scope
+	scope, _, _ := unstructured.NestedMap(object.Object, "scope")
+
+	results, _, _ := unstructured.NestedSlice(object.Object, "results")
+	for _, item := range results {
+		result, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		resultValue := nestedString(result, "result")
+		if resultValue != status.PolicyResultFail && resultValue != status.PolicyResultWarn &&
+			resultValue != status.PolicyResultError {
+			continue
+		}
+
+		resources, _, _ := unstructured.NestedSlice(result, "resources")
+		if len(resources) == 0 && scope != nil {
+			resources = []interface{}{scope}
+		}
+		if len(resources) == 0 {
+			resources = []interface{}{map[string]interface{}{}}
+		}
+
+		for _, resourceItem := range resources {
+			resource, ok := resourceItem.(map[string]interface{})
+			if !ok || len(report.Violations) >= maxViolationsPerReport {
+				continue
+			}
+			report.Violations = append(report.Violations, &status.PolicyViolation{
+				Policy:            nestedString(result, "policy"),
+				Rule:              nestedString(result, "rule"),
+				Severity:          nestedString(result, "severity"),
+				Category:          nestedString(result, "category"),
+				Result:            resultValue,
+				Message:           nestedString(result, "message"),
+				ResourceKind:      nestedString(resource, "kind"),
+				ResourceName:      nestedString(resource, "name"),
+				ResourceNamespace: nestedString(resource, "namespace"),
+			})
+		}
+	}
+
+	return report
+}
+
+// fromGatekeeperConstraint converts the audit results of the gatekeeper constraint, the violations of a deny
+// constraint fail and the others, dryrun or warn, are warnings.
+func fromGatekeeperConstraint(object *unstructured.Unstructured) *status.PolicyReport {
+	report := &status.PolicyReport{Engine: status.PolicyEngineGatekeeper}
+
+	totalViolations, _, _ := unstructured.NestedInt64(object.Object, "status", "totalViolations")
+	enforcementAction := nestedString(object.Object, "spec", "enforcementAction")
+	constraintResult := enforcementResult(enforcementAction)
+	if constraintResult == status.PolicyResultFail {
+		report.Fail = totalViolations
+	} else {
+		report.Warn = totalViolations
+	}
+
+	violations, _, _ := unstructured.NestedSlice(object.Object, "status", "violations")
+	for _, item := range violations {
+		violation, ok := item.(map[string]interface{})
+		if !ok || len(report.Violations) >= maxViolationsPerReport {
+			continue
+		}
+		result := constraintResult
+		if action := nestedString(violation, "enforcementAction"); action != "" {
+			result = enforcementResult(action)
+		}
+		report.Violations = append(report.Violations, &status.PolicyViolation{
+			Policy:            object.GetName(),
+			Rule:              object.GetKind(),
+			Result:            result,
+			Message:           nestedString(violation, "message"),
+			ResourceKind:      nestedString(violation, "kind"),
+			ResourceName:      nestedString(violation, "name"),
+			ResourceNamespace: nestedString(violation, "namespace"),
+		})
+	}
+
+	return report
+}
+
+// enforcementResult returns the result of the gatekeeper enforcement action, deny is the default action.
+func enforcementResult(enforcementAction string) string {
+	if enforcementAction == "" || enforcementAction == "deny" {
+		return status.PolicyResultFail
+	}
+
+	return status.PolicyResultWarn
+}
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
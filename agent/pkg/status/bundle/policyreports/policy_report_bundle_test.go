@@ -0,0 +1,107 @@
+package policyreports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestPolicyReportBundle(t *testing.T) {
+	resolveClusterNameFunc := func(namespace string) string {
+		if namespace == "cluster1" {
+			return "cluster1"
+		}
+		return ""
+	}
+
+	kyvernoReport := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata": map[string]interface{}{
+			"name":            "cpol-require-labels",
+			"namespace":       "cluster1",
+			"uid":             "5c1d8b2a-1f0e-4a7b-9d3c-2e4f6a8b0c11",
+			"resourceVersion": "1",
+		},
+		"summary": map[string]interface{}{"pass": int64(3), "fail": int64(1)},
+		"results": []interface{}{
+			map[string]interface{}{"policy": "require-labels", "rule": "check-team", "result": "pass"},
+			map[string]interface{}{
+				"policy":   "require-labels",
+				"rule":     "check-team",
+				"result":   "fail",
+				"severity": "medium",
+				"category": "Best Practices",
+				"message":  "label 'team' is required",
+				"resources": []interface{}{
+					map[string]interface{}{"kind": "Pod", "name": "nginx", "namespace": "default"},
+				},
+			},
+		},
+	}}
+
+	gatekeeperConstraint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       "K8sRequiredLabels",
+		"metadata": map[string]interface{}{
+			"name":            "ns-must-have-owner",
+			"uid":             "7a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c22",
+			"resourceVersion": "1",
+		},
+		"spec": map[string]interface{}{"enforcementAction": "dryrun"},
+		"status": map[string]interface{}{
+			"totalViolations": int64(2),
+			"violations": []interface{}{
+				map[string]interface{}{"kind": "Namespace", "name": "dev", "message": "you must provide owner"},
+				map[string]interface{}{
+					"kind": "Namespace", "name": "test", "message": "you must provide owner", "enforcementAction": "deny",
+				},
+			},
+		},
+	}}
+
+	bundle := NewPolicyReportBundle("hub1", 0, resolveClusterNameFunc).(*PolicyReportBundle)
+	bundle.SyncObjects([]*unstructured.Unstructured{kyvernoReport, gatekeeperConstraint})
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 2)
+
+	report := bundle.Objects[0]
+	assert.Equal(t, status.PolicyEngineKyverno, report.Engine)
+	assert.Equal(t, "cluster1", report.ClusterName)
+	assert.Equal(t, int64(3), report.Pass)
+	assert.Equal(t, int64(1), report.Fail)
+	assert.Equal(t, []*status.PolicyViolation{{
+		Policy:            "require-labels",
+		Rule:              "check-team",
+		Severity:          "medium",
+		Category:          "Best Practices",
+		Result:            status.PolicyResultFail,
+		Message:           "label 'team' is required",
+		ResourceKind:      "Pod",
+		ResourceName:      "nginx",
+		ResourceNamespace: "default",
+	}}, report.Violations)
+
+	constraint := bundle.Objects[1]
+	assert.Equal(t, status.PolicyEngineGatekeeper, constraint.Engine)
+	assert.Equal(t, "K8sRequiredLabels", constraint.Kind)
+	assert.Empty(t, constraint.ClusterName)
+	assert.Equal(t, int64(2), constraint.Warn)
+	assert.Len(t, constraint.Violations, 2)
+	assert.Equal(t, "ns-must-have-owner", constraint.Violations[0].Policy)
+	assert.Equal(t, status.PolicyResultWarn, constraint.Violations[0].Result)
+	assert.Equal(t, status.PolicyResultFail, constraint.Violations[1].Result)
+
+	// the unchanged reports don't change the bundle
+	bundle.SyncObjects([]*unstructured.Unstructured{kyvernoReport.DeepCopy(), gatekeeperConstraint.DeepCopy()})
+	assert.Equal(t, uint64(2), bundle.GetBundleVersion().Generation)
+
+	// the reports which aren't listed anymore are removed
+	bundle.SyncObjects([]*unstructured.Unstructured{gatekeeperConstraint})
+	assert.Equal(t, uint64(3), bundle.GetBundleVersion().Generation)
+	assert.Len(t, bundle.Objects, 1)
+	assert.Equal(t, "ns-must-have-owner", bundle.Objects[0].Name)
+}
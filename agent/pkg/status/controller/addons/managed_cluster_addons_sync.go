@@ -0,0 +1,50 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	addonsbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/addons"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const managedClusterAddOnsSyncLog = "managed-cluster-addons-status-sync"
+
+// AddManagedClusterAddOnsStatusController adds the managed cluster addons status controller to the manager.
+func AddManagedClusterAddOnsStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
+	incarnation uint64, _ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervals *config.SyncIntervals,
+) error {
+	createObjFunction := func() bundle.Object { return &addonv1alpha1.ManagedClusterAddOn{} }
+
+	k8sClient := mgr.GetClient()
+	// the addon is reported without the cluster id if the cluster can't be found, the manager resolves it by name
+	resolveClusterIDFunc := func(clusterName string) string {
+		clusterID, err := helper.GetClusterId(context.TODO(), k8sClient, clusterName)
+		if err != nil {
+			return ""
+		}
+		return clusterID
+	}
+
+	bundleCollection := []*generic.BundleCollectionEntry{
+		generic.NewBundleCollectionEntry(fmt.Sprintf("%s.%s", leafHubName, constants.ManagedClusterAddOnsMsgKey),
+			addonsbundle.NewManagedClusterAddOnStatusBundle(leafHubName, incarnation, resolveClusterIDFunc),
+			func() bool { return true }),
+	} // bundle predicate - always send the addons status.
+
+	if err := generic.NewGenericStatusSyncController(mgr, managedClusterAddOnsSyncLog, producer, bundleCollection,
+		createObjFunction, nil, syncIntervals.GetManagerClusters); err != nil {
+		return fmt.Errorf("failed to add managed cluster addons controller to the manager - %w", err)
+	}
+
+	return nil
+}
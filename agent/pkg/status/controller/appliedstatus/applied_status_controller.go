@@ -0,0 +1,118 @@
+package appliedstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	appliedStatusLogName = "applied-status"
+)
+
+// AppliedStatusController sends the applied status of the global resources back to the global hub.
+type AppliedStatusController struct {
+	log                     logr.Logger
+	bundle                  *appliedstatus.Bundle
+	lastSentBundleVersion   status.BundleVersion
+	transportBundleKey      string
+	transport               transport.Producer
+	resolveSyncIntervalFunc config.ResolveSyncIntervalFunc
+}
+
+// AddAppliedStatusController creates a new instance of applied status controller and adds it to the manager.
+// the bundle is shared with the spec syncers, which report the result of applying each received object.
+func AddAppliedStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
+	appliedStatusBundle *appliedstatus.Bundle, syncIntervalsData *config.SyncIntervals,
+) error {
+	appliedStatusCtrl := &AppliedStatusController{
+		log:                     ctrl.Log.WithName(appliedStatusLogName),
+		bundle:                  appliedStatusBundle,
+		lastSentBundleVersion:   *appliedStatusBundle.GetBundleVersion(),
+		transportBundleKey:      fmt.Sprintf("%s.%s", leafHubName, constants.AppliedStatusMsgKey),
+		transport:               producer,
+		resolveSyncIntervalFunc: syncIntervalsData.GetAppliedStatus,
+	}
+
+	if err := mgr.Add(appliedStatusCtrl); err != nil {
+		return fmt.Errorf("failed to add controller to the manager - %w", err)
+	}
+
+	return nil
+}
+
+// Start function starts applied status controller.
+func (c *AppliedStatusController) Start(ctx context.Context) error {
+	c.log.Info("Starting Controller")
+
+	go c.periodicSync(ctx)
+
+	<-ctx.Done() // blocking wait for stop event
+	c.log.Info("Stopping Controller")
+
+	return nil
+}
+
+func (c *AppliedStatusController) periodicSync(ctx context.Context) {
+	currentSyncInterval := c.resolveSyncIntervalFunc()
+	ticker := time.NewTicker(currentSyncInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+
+		case <-ticker.C: // wait for next time interval
+			c.syncBundle(ctx)
+
+			resolvedInterval := c.resolveSyncIntervalFunc()
+
+			// reset ticker if sync interval has changed
+			if resolvedInterval != currentSyncInterval {
+				currentSyncInterval = resolvedInterval
+				ticker.Reset(currentSyncInterval)
+				c.log.Info(fmt.Sprintf("sync interval has been reset to %s", currentSyncInterval.String()))
+			}
+		}
+	}
+}
+
+func (c *AppliedStatusController) syncBundle(ctx context.Context) {
+	bundleVersion := *c.bundle.GetBundleVersion()
+
+	// send to transport only if bundle has changed.
+	if !bundleVersion.NewerThan(&c.lastSentBundleVersion) {
+		return
+	}
+
+	payloadBytes, err := json.Marshal(c.bundle)
+	if err != nil {
+		c.log.Error(err, "marshal applied status bundle error", "transportBundleKey", c.transportBundleKey)
+		return
+	}
+
+	if err := c.transport.Send(ctx, &transport.Message{
+		Key:           c.transportBundleKey,
+		ID:            c.transportBundleKey,
+		MsgType:       constants.StatusBundle,
+		Version:       bundleVersion.String(),
+		SchemaVersion: config.GetSchemaVersion(),
+		Payload:       payloadBytes,
+	}); err != nil {
+		c.log.Error(err, "send applied status error", "messageId", c.transportBundleKey)
+		return
+	}
+
+	c.lastSentBundleVersion = bundleVersion
+}
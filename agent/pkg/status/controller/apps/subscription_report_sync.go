@@ -3,13 +3,13 @@ package apps
 import (
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -20,7 +20,7 @@ const (
 
 // AddSubscriptionReportsController adds subscription-report controller to the manager.
 func AddSubscriptionReportsController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, _ *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	incarnation uint64, _ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervalsData *config.SyncIntervals,
 ) error {
 	createObjFunction := func() bundle.Object { return &appsv1alpha1.SubscriptionReport{} }
 
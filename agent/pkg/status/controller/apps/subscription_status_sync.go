@@ -1,15 +1,23 @@
 package apps
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
+	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	appsbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/apps"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -18,22 +26,82 @@ const (
 	subscriptionStatusSyncLog = "subscriptions-statuses-sync"
 )
 
-// AddSubscriptionStatusesController adds subscription-status controller to the manager.
+// AddSubscriptionStatusesController adds subscription-status controller to the manager. only the statuses of the
+// globally authored subscriptions are synced, the statuses are sent in hybrid mode (complete and delta state bundles)
+// so the leaf hubs with large application fleets don't resend all the statuses on every change.
 func AddSubscriptionStatusesController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, _ *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
-) error {
+	incarnation uint64, _ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervalsData *config.SyncIntervals,
+) (*generic.HybridSyncManager, error) {
+	k8sClient := mgr.GetClient()
 	createObjFunction := func() bundle.Object { return &appsv1alpha1.SubscriptionStatus{} }
 
-	bundleCollection := []*generic.BundleCollectionEntry{
+	// the status has the same namespaced name as its subscription, stamp the id of the global subscription on it
+	manipulateObjFunc := func(object bundle.Object) {
+		if subscriptionID, ok := getGlobalSubscriptionID(k8sClient, object); ok {
+			annotations := object.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[constants.OriginOwnerReferenceAnnotation] = subscriptionID
+			object.SetAnnotations(annotations)
+		}
+	}
+
+	completeStatusBundle := bundle.NewGenericStatusBundle(leafHubName, incarnation, manipulateObjFunc)
+	deltaStatusBundle := appsbundle.NewDeltaSubscriptionStatusesBundle(leafHubName, completeStatusBundle,
+		incarnation, extractSubscriptionID)
+
+	hybridSyncManager, err := generic.NewHybridSyncManager(
+		ctrl.Log.WithName("subscription-status-hybrid-sync-manager"),
 		generic.NewBundleCollectionEntry(fmt.Sprintf("%s.%s", leafHubName, constants.SubscriptionStatusMsgKey),
-			bundle.NewGenericStatusBundle(leafHubName, incarnation, nil),
-			func() bool { return true }),
+			completeStatusBundle, func() bool { return true }),
+		generic.NewBundleCollectionEntry(fmt.Sprintf("%s.%s", leafHubName, constants.SubscriptionDeltaStatusMsgKey),
+			deltaStatusBundle, func() bool { return true }))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", err, errors.New("failed to create hybrid sync manager"))
+	}
+
+	bundleCollection := []*generic.BundleCollectionEntry{
+		hybridSyncManager.GetBundleCollectionEntry(genericbundle.CompleteStateMode),
+		hybridSyncManager.GetBundleCollectionEntry(genericbundle.DeltaStateMode),
 	} // bundle predicate - always send subscription status.
 
+	// the deleted statuses are always handled, the subscription might be removed before its status
+	globalSubscriptionPredicate := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			_, ok := getGlobalSubscriptionID(k8sClient, e.Object)
+			return ok
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			_, ok := getGlobalSubscriptionID(k8sClient, e.ObjectNew)
+			return ok
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			_, ok := getGlobalSubscriptionID(k8sClient, e.Object)
+			return ok
+		},
+	}
+
 	if err := generic.NewGenericStatusSyncController(mgr, subscriptionStatusSyncLog, producer, bundleCollection,
-		createObjFunction, nil, syncIntervalsData.GetPolicies); err != nil {
-		return fmt.Errorf("failed to add subscription statuses controller to the manager - %w", err)
+		createObjFunction, globalSubscriptionPredicate, syncIntervalsData.GetPolicies); err != nil {
+		return nil, fmt.Errorf("failed to add subscription statuses controller to the manager - %w", err)
 	}
 
-	return nil
+	return hybridSyncManager, nil
+}
+
+// getGlobalSubscriptionID returns the id of the global subscription that the status belongs to, and false if the
+// status doesn't belong to a subscription that was created by the global hub.
+func getGlobalSubscriptionID(k8sClient client.Client, object client.Object) (string, bool) {
+	subscription := &appsv1.Subscription{}
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), subscription); err != nil {
+		return "", false
+	}
+	subscriptionID, ok := subscription.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+	return subscriptionID, ok
+}
+
+func extractSubscriptionID(obj bundle.Object) (string, bool) {
+	val, ok := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+	return val, ok
 }
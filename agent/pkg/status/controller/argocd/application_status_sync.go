@@ -0,0 +1,83 @@
+package argocd
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
+	argocdbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/argocd"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	applicationsStatusSyncLog    = "argocd-applications-status-sync"
+	applicationSetsStatusSyncLog = "argocd-applicationsets-status-sync"
+)
+
+var (
+	applicationGVK    = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+	applicationSetGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}
+)
+
+// AddArgoCDApplicationsStatusController adds the Argo CD Application and ApplicationSet status controllers to the
+// manager. the leaf hubs without Argo CD (OpenShift GitOps) installed are skipped.
+func AddArgoCDApplicationsStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
+	incarnation uint64, _ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervalsData *config.SyncIntervals,
+) error {
+	log := ctrl.Log.WithName(applicationsStatusSyncLog)
+	if _, err := mgr.GetRESTMapper().RESTMapping(applicationGVK.GroupKind(), applicationGVK.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.Info("the Argo CD Application CRD isn't installed, skip syncing the application status")
+			return nil
+		}
+		return fmt.Errorf("failed to get the rest mapping of the Argo CD Application - %w", err)
+	}
+
+	bundleCollection := []*generic.BundleCollectionEntry{
+		generic.NewBundleCollectionEntry(fmt.Sprintf("%s.%s", leafHubName, constants.ArgoCDApplicationMsgKey),
+			argocdbundle.NewApplicationStatusBundle(leafHubName, incarnation),
+			func() bool { return true }),
+	} // bundle predicate - always send the application status.
+
+	if err := generic.NewGenericStatusSyncController(mgr, applicationsStatusSyncLog, producer, bundleCollection,
+		createObjFunc(applicationGVK), nil, syncIntervalsData.GetPolicies); err != nil {
+		return fmt.Errorf("failed to add argocd applications status controller to the manager - %w", err)
+	}
+
+	if _, err := mgr.GetRESTMapper().RESTMapping(applicationSetGVK.GroupKind(), applicationSetGVK.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.Info("the Argo CD ApplicationSet CRD isn't installed, skip syncing the applicationset status")
+			return nil
+		}
+		return fmt.Errorf("failed to get the rest mapping of the Argo CD ApplicationSet - %w", err)
+	}
+
+	bundleCollection = []*generic.BundleCollectionEntry{
+		generic.NewBundleCollectionEntry(fmt.Sprintf("%s.%s", leafHubName, constants.ArgoCDApplicationSetMsgKey),
+			argocdbundle.NewApplicationSetStatusBundle(leafHubName, incarnation),
+			func() bool { return true }),
+	} // bundle predicate - always send the applicationset status.
+
+	if err := generic.NewGenericStatusSyncController(mgr, applicationSetsStatusSyncLog, producer, bundleCollection,
+		createObjFunc(applicationSetGVK), nil, syncIntervalsData.GetPolicies); err != nil {
+		return fmt.Errorf("failed to add argocd applicationsets status controller to the manager - %w", err)
+	}
+
+	return nil
+}
+
+func createObjFunc(gvk schema.GroupVersionKind) generic.CreateObjectFunction {
+	return func() bundle.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj
+	}
+}
@@ -3,16 +3,22 @@ package config
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
@@ -25,12 +31,19 @@ const (
 type hubOfHubsConfigController struct {
 	client            client.Client
 	log               logr.Logger
-	configObject      *corev1.ConfigMap
+	configObject      *globalhubv1alpha3.GlobalHubAgentConfig
 	syncIntervalsData *SyncIntervals
+	// typedConfig is false if the GlobalHubAgentConfig crd isn't installed on the hub, only the configmap is used
+	typedConfig bool
 }
 
 // AddConfigController creates a new instance of config controller and adds it to the manager.
-func AddConfigController(mgr ctrl.Manager, configObject *corev1.ConfigMap, syncIntervals *SyncIntervals) error {
+// the controller watches the typed GlobalHubAgentConfig, the legacy configmap with the same name is only used
+// when the typed config doesn't exist on the hub. the GlobalHubAgentConfig crd is optional, the controller only
+// watches the configmap if the crd isn't installed when the agent starts.
+func AddConfigController(mgr ctrl.Manager, configObject *globalhubv1alpha3.GlobalHubAgentConfig,
+	syncIntervals *SyncIntervals,
+) error {
 	hubOfHubsConfigCtrl := &hubOfHubsConfigController{
 		client:            mgr.GetClient(),
 		log:               ctrl.Log.WithName("multicluster-global-hub-agent-config"),
@@ -38,14 +51,30 @@ func AddConfigController(mgr ctrl.Manager, configObject *corev1.ConfigMap, syncI
 		syncIntervalsData: syncIntervals,
 	}
 
-	configMapPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+	_, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{
+		Group: globalhubv1alpha3.GroupVersion.Group,
+		Kind:  "GlobalHubAgentConfig",
+	}, globalhubv1alpha3.GroupVersion.Version)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to get the rest mapping of the GlobalHubAgentConfig - %w", err)
+	}
+	hubOfHubsConfigCtrl.typedConfig = err == nil
+
+	configPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
 		return object.GetNamespace() == constants.GHSystemNamespace &&
-			object.GetName() == constants.GHAgentConfigCMName
+			(object.GetName() == constants.GHAgentConfigName || object.GetName() == constants.GHAgentConfigCMName)
 	})
-	if err := ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.ConfigMap{}).
-		WithEventFilter(configMapPredicate).
-		Complete(hubOfHubsConfigCtrl); err != nil {
+	// the configmap and the typed config share the same name, so both of them are enqueued by the object key
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		Named("multicluster-global-hub-agent-config").
+		For(&corev1.ConfigMap{})
+	if hubOfHubsConfigCtrl.typedConfig {
+		controllerBuilder = controllerBuilder.Watches(&source.Kind{Type: &globalhubv1alpha3.GlobalHubAgentConfig{}},
+			&handler.EnqueueRequestForObject{})
+	} else {
+		hubOfHubsConfigCtrl.log.Info("the GlobalHubAgentConfig crd isn't installed, only the configmap is watched")
+	}
+	if err := controllerBuilder.WithEventFilter(configPredicate).Complete(hubOfHubsConfigCtrl); err != nil {
 		return fmt.Errorf("failed to add hub of hubs config controller to the manager - %w", err)
 	}
 
@@ -55,7 +84,29 @@ func AddConfigController(mgr ctrl.Manager, configObject *corev1.ConfigMap, syncI
 func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	reqLogger := c.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 
-	if err := c.client.Get(ctx, request.NamespacedName, c.configObject); apierrors.IsNotFound(err) {
+	if c.typedConfig {
+		agentConfig := &globalhubv1alpha3.GlobalHubAgentConfig{}
+		err := c.client.Get(ctx, request.NamespacedName, agentConfig)
+		if err == nil {
+			applyErr := c.applyAgentConfig(agentConfig)
+			if err := c.updateAgentConfigStatus(ctx, agentConfig, applyErr); err != nil {
+				reqLogger.Info(fmt.Sprintf("Reconciliation failed: %s", err))
+				return ctrl.Result{Requeue: true, RequeueAfter: RequeuePeriod},
+					fmt.Errorf("reconciliation failed: %w", err)
+			}
+			reqLogger.Info("Reconciliation complete.")
+			return ctrl.Result{}, nil
+		} else if !apierrors.IsNotFound(err) {
+			reqLogger.Info(fmt.Sprintf("Reconciliation failed: %s", err))
+			return ctrl.Result{Requeue: true, RequeueAfter: RequeuePeriod},
+				fmt.Errorf("reconciliation failed: %w", err)
+		}
+	}
+
+	// fall back to the legacy configmap, e.g. the agent is running before the operator renders the typed config,
+	// or the GlobalHubAgentConfig crd is not installed
+	configMap := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, request.NamespacedName, configMap); apierrors.IsNotFound(err) {
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		reqLogger.Info(fmt.Sprintf("Reconciliation failed: %s", err))
@@ -63,26 +114,123 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 			fmt.Errorf("reconciliation failed: %w", err)
 	}
 
-	c.setSyncInterval(c.configObject, "managedClusters", &c.syncIntervalsData.managedClusters)
-	c.setSyncInterval(c.configObject, "policies", &c.syncIntervalsData.policies)
-	c.setSyncInterval(c.configObject, "controlInfo", &c.syncIntervalsData.controlInfo)
+	agentConfig := c.configObject.DeepCopy()
+	c.setAgentConfigFromConfigMap(configMap, &agentConfig.Spec)
+	if err := c.applyAgentConfig(agentConfig); err != nil {
+		reqLogger.Info(fmt.Sprintf("invalid agent config: %s", err))
+	}
 
 	reqLogger.Info("Reconciliation complete.")
 	return ctrl.Result{}, nil
 }
 
-func (c *hubOfHubsConfigController) setSyncInterval(configMap *v1.ConfigMap, key string, syncInterval *time.Duration) {
+// applyAgentConfig updates the shared config object and the sync intervals, the invalid intervals fall back to the
+// defaults and are reported by the returned error.
+func (c *hubOfHubsConfigController) applyAgentConfig(agentConfig *globalhubv1alpha3.GlobalHubAgentConfig) error {
+	agentConfig.DeepCopyInto(c.configObject)
+
+	syncIntervals := agentConfig.Spec.SyncIntervals
+	c.syncIntervalsData.managedClusters = syncIntervals.GetManagedClustersInterval()
+	c.syncIntervalsData.policies = syncIntervals.GetPoliciesInterval()
+	c.syncIntervalsData.controlInfo = syncIntervals.GetControlInfoInterval()
+	c.syncIntervalsData.appliedStatus = syncIntervals.GetAppliedStatusInterval()
+
+	if syncIntervals == nil {
+		return nil
+	}
+	intervals := []struct {
+		key      string
+		interval *metav1.Duration
+	}{
+		{"managedClusters", syncIntervals.ManagedClusters},
+		{"policies", syncIntervals.Policies},
+		{"controlInfo", syncIntervals.ControlInfo},
+		{"appliedStatus", syncIntervals.AppliedStatus},
+	}
+	for _, interval := range intervals {
+		if interval.interval != nil && interval.interval.Duration <= 0 {
+			return fmt.Errorf("%s sync interval must be positive, using the default interval", interval.key)
+		}
+	}
+	return nil
+}
+
+func (c *hubOfHubsConfigController) updateAgentConfigStatus(ctx context.Context,
+	agentConfig *globalhubv1alpha3.GlobalHubAgentConfig, applyErr error,
+) error {
+	condition := metav1.Condition{
+		Type:               globalhubv1alpha3.AgentConfigApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AgentConfigApplied",
+		Message:            "the agent config is applied",
+		ObservedGeneration: agentConfig.GetGeneration(),
+	}
+	if applyErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidAgentConfig"
+		condition.Message = applyErr.Error()
+	}
+
+	existingCondition := meta.FindStatusCondition(agentConfig.Status.Conditions, condition.Type)
+	if agentConfig.Status.ObservedGeneration == agentConfig.GetGeneration() && existingCondition != nil &&
+		existingCondition.Status == condition.Status && existingCondition.Message == condition.Message &&
+		existingCondition.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+
+	agentConfig.Status.ObservedGeneration = agentConfig.GetGeneration()
+	meta.SetStatusCondition(&agentConfig.Status.Conditions, condition)
+	return c.client.Status().Update(ctx, agentConfig)
+}
+
+// setAgentConfigFromConfigMap converts the legacy configmap data into the typed spec, the keys which are missing
+// or invalid keep the current values.
+func (c *hubOfHubsConfigController) setAgentConfigFromConfigMap(configMap *corev1.ConfigMap,
+	spec *globalhubv1alpha3.AgentConfigSpec,
+) {
+	if aggregationLevel, found := configMap.Data["aggregationLevel"]; found {
+		switch level := globalhubv1alpha3.AggregationLevelType(aggregationLevel); level {
+		case globalhubv1alpha3.FullAggregation, globalhubv1alpha3.MinimalAggregation:
+			spec.AggregationLevel = level
+		default:
+			c.log.Info(fmt.Sprintf("aggregationLevel has invalid value %q, using %q", aggregationLevel,
+				spec.AggregationLevel))
+		}
+	}
+
+	if enableLocalPolicies, found := configMap.Data["enableLocalPolicies"]; found {
+		enabled, err := strconv.ParseBool(enableLocalPolicies)
+		if err != nil {
+			c.log.Info(fmt.Sprintf("enableLocalPolicies has invalid value %q, using %t", enableLocalPolicies,
+				spec.LocalPoliciesEnabled()))
+		} else {
+			spec.EnableLocalPolicies = &enabled
+		}
+	}
+
+	if spec.SyncIntervals == nil {
+		spec.SyncIntervals = &globalhubv1alpha3.SyncIntervalsConfig{}
+	}
+	c.setSyncInterval(configMap, "managedClusters", &spec.SyncIntervals.ManagedClusters)
+	c.setSyncInterval(configMap, "policies", &spec.SyncIntervals.Policies)
+	c.setSyncInterval(configMap, "controlInfo", &spec.SyncIntervals.ControlInfo)
+	c.setSyncInterval(configMap, "appliedStatus", &spec.SyncIntervals.AppliedStatus)
+}
+
+func (c *hubOfHubsConfigController) setSyncInterval(configMap *corev1.ConfigMap, key string,
+	syncInterval **metav1.Duration,
+) {
 	intervalStr, found := configMap.Data[key]
 	if !found {
-		c.log.Info(fmt.Sprintf("%s sync interval not defined, using %s", key, syncInterval.String()))
+		c.log.Info(fmt.Sprintf("%s sync interval not defined, using the current interval", key))
 		return
 	}
 
 	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
-		c.log.Info(fmt.Sprintf("%s sync interval has invalid format, using %s", key, syncInterval.String()))
+	if err != nil || interval <= 0 {
+		c.log.Info(fmt.Sprintf("%s sync interval has invalid format, using the current interval", key))
 		return
 	}
 
-	*syncInterval = interval
+	*syncInterval = &metav1.Duration{Duration: interval}
 }
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newTestConfigController(t *testing.T, objects ...client.Object) *hubOfHubsConfigController {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, globalhubv1alpha3.AddToScheme(scheme))
+
+	return &hubOfHubsConfigController{
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		log:               ctrl.Log.WithName("config-controller-test"),
+		configObject:      &globalhubv1alpha3.GlobalHubAgentConfig{},
+		syncIntervalsData: NewSyncIntervals(),
+		typedConfig:       true,
+	}
+}
+
+var configRequest = ctrl.Request{NamespacedName: types.NamespacedName{
+	Namespace: constants.GHSystemNamespace,
+	Name:      constants.GHAgentConfigName,
+}}
+
+func TestReconcileAgentConfig(t *testing.T) {
+	enableLocalPolicies := true
+	agentConfig := &globalhubv1alpha3.GlobalHubAgentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  constants.GHSystemNamespace,
+			Name:       constants.GHAgentConfigName,
+			Generation: 2,
+		},
+		Spec: globalhubv1alpha3.AgentConfigSpec{
+			AggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+			EnableLocalPolicies: &enableLocalPolicies,
+			SyncIntervals: &globalhubv1alpha3.SyncIntervalsConfig{
+				Policies: &metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+	}
+	// the typed config takes precedence over the legacy configmap
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.GHSystemNamespace,
+			Name:      constants.GHAgentConfigCMName,
+		},
+		Data: map[string]string{"aggregationLevel": "full", "policies": "1m"},
+	}
+	controller := newTestConfigController(t, agentConfig, configMap)
+
+	_, err := controller.Reconcile(context.TODO(), configRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, globalhubv1alpha3.MinimalAggregation, controller.configObject.Spec.AggregationLevel)
+	assert.True(t, controller.configObject.Spec.LocalPoliciesEnabled())
+	assert.Equal(t, 30*time.Second, controller.syncIntervalsData.GetPolicies())
+	assert.Equal(t, globalhubv1alpha3.DefaultStatusSyncInterval, controller.syncIntervalsData.GetManagerClusters())
+
+	updated := &globalhubv1alpha3.GlobalHubAgentConfig{}
+	assert.NoError(t, controller.client.Get(context.TODO(), configRequest.NamespacedName, updated))
+	assert.Equal(t, int64(2), updated.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, globalhubv1alpha3.AgentConfigApplied))
+
+	// the non-positive interval falls back to the default one and is reported in the status
+	updated.Spec.SyncIntervals.Policies = &metav1.Duration{Duration: 0}
+	assert.NoError(t, controller.client.Update(context.TODO(), updated))
+	_, err = controller.Reconcile(context.TODO(), configRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, globalhubv1alpha3.DefaultStatusSyncInterval, controller.syncIntervalsData.GetPolicies())
+	assert.NoError(t, controller.client.Get(context.TODO(), configRequest.NamespacedName, updated))
+	assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, globalhubv1alpha3.AgentConfigApplied))
+}
+
+func TestReconcileLegacyConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.GHSystemNamespace,
+			Name:      constants.GHAgentConfigCMName,
+		},
+		Data: map[string]string{
+			"aggregationLevel":    "full",
+			"enableLocalPolicies": "true",
+			"managedClusters":     "10s",
+			"policies":            "invalid",
+		},
+	}
+	controller := newTestConfigController(t, configMap)
+
+	_, err := controller.Reconcile(context.TODO(), configRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, globalhubv1alpha3.FullAggregation, controller.configObject.Spec.AggregationLevel)
+	assert.True(t, controller.configObject.Spec.LocalPoliciesEnabled())
+	assert.Equal(t, 10*time.Second, controller.syncIntervalsData.GetManagerClusters())
+	assert.Equal(t, globalhubv1alpha3.DefaultStatusSyncInterval, controller.syncIntervalsData.GetPolicies())
+
+	// the invalid values keep the current config
+	configMap.Data = map[string]string{"aggregationLevel": "none", "enableLocalPolicies": "yes"}
+	assert.NoError(t, controller.client.Update(context.TODO(), configMap))
+	_, err = controller.Reconcile(context.TODO(), configRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, globalhubv1alpha3.FullAggregation, controller.configObject.Spec.AggregationLevel)
+	assert.True(t, controller.configObject.Spec.LocalPoliciesEnabled())
+	assert.Equal(t, 10*time.Second, controller.syncIntervalsData.GetManagerClusters())
+}
+
+func TestReconcileWithoutAgentConfigCRD(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.GHSystemNamespace,
+			Name:      constants.GHAgentConfigCMName,
+		},
+		Data: map[string]string{"aggregationLevel": "minimal", "policies": "1m"},
+	}
+	// the scheme of the client doesn't know the GlobalHubAgentConfig, like a hub without the crd
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	controller := &hubOfHubsConfigController{
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		log:               ctrl.Log.WithName("config-controller-test"),
+		configObject:      &globalhubv1alpha3.GlobalHubAgentConfig{},
+		syncIntervalsData: NewSyncIntervals(),
+	}
+
+	_, err := controller.Reconcile(context.TODO(), configRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, globalhubv1alpha3.MinimalAggregation, controller.configObject.Spec.AggregationLevel)
+	assert.Equal(t, time.Minute, controller.syncIntervalsData.GetPolicies())
+}
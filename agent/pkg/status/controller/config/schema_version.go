@@ -0,0 +1,56 @@
+package config
+
+import (
+	"sync"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+var negotiated = &negotiatedCapabilities{schemaVersion: status.SchemaVersion}
+
+// negotiatedCapabilities holds the capabilities advertised by the manager and the schema version of the status
+// bundles negotiated with them.
+type negotiatedCapabilities struct {
+	lock          sync.RWMutex
+	manager       *status.Capabilities
+	schemaVersion int
+}
+
+// SetManagerCapabilities negotiates the schema version of the status bundles with the capabilities advertised by the
+// manager, the schema version of the agent is kept if the manager doesn't support it.
+func SetManagerCapabilities(capabilities *status.Capabilities) {
+	schemaVersion, err := status.NewCapabilities().NegotiateSchemaVersion(capabilities)
+	if err != nil {
+		ctrl.Log.WithName("schema-version").Error(err, "the manager doesn't support the schema version of the agent")
+	}
+
+	negotiated.lock.Lock()
+	defer negotiated.lock.Unlock()
+	negotiated.manager = capabilities
+	if negotiated.schemaVersion != schemaVersion {
+		ctrl.Log.WithName("schema-version").Info("the schema version of the status bundles is negotiated",
+			"schemaVersion", schemaVersion)
+		negotiated.schemaVersion = schemaVersion
+	}
+}
+
+// GetSchemaVersion returns the schema version the status bundles are sent with.
+func GetSchemaVersion() int {
+	negotiated.lock.RLock()
+	defer negotiated.lock.RUnlock()
+	return negotiated.schemaVersion
+}
+
+// ResolveContentType returns the content type if the manager decodes it, otherwise json. the manager which doesn't
+// advertise the capabilities only decodes json.
+func ResolveContentType(contentType string) string {
+	negotiated.lock.RLock()
+	defer negotiated.lock.RUnlock()
+	if negotiated.manager == nil || !negotiated.manager.SupportsContentType(contentType) {
+		return encoding.ContentTypeJSON
+	}
+	return contentType
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestManagerCapabilities(t *testing.T) {
+	// the bundles are sent as json until the manager advertises protobuf
+	assert.Equal(t, status.SchemaVersion, GetSchemaVersion())
+	assert.Equal(t, encoding.ContentTypeJSON, ResolveContentType(encoding.ContentTypeProtobuf))
+
+	SetManagerCapabilities(&status.Capabilities{
+		SchemaVersion:    status.SchemaVersion - 1,
+		MinSchemaVersion: status.MinSchemaVersion,
+	})
+	assert.Equal(t, status.SchemaVersion-1, GetSchemaVersion())
+	assert.Equal(t, encoding.ContentTypeJSON, ResolveContentType(encoding.ContentTypeProtobuf))
+
+	SetManagerCapabilities(status.NewCapabilities())
+	assert.Equal(t, status.SchemaVersion, GetSchemaVersion())
+	assert.Equal(t, encoding.ContentTypeProtobuf, ResolveContentType(encoding.ContentTypeProtobuf))
+}
@@ -17,6 +17,7 @@ type SyncIntervals struct {
 	managedClusters time.Duration
 	policies        time.Duration
 	controlInfo     time.Duration
+	appliedStatus   time.Duration
 }
 
 // NewSyncIntervals returns new HohConfigMapData object initialized with default periodic sync intervals.
@@ -25,6 +26,7 @@ func NewSyncIntervals() *SyncIntervals {
 		managedClusters: DEFAULT_STATUS_SYNC_INTERVAL,
 		policies:        DEFAULT_STATUS_SYNC_INTERVAL,
 		controlInfo:     DEFAULT_CONTROL_INFO_SYNC_INTERVAL,
+		appliedStatus:   DEFAULT_STATUS_SYNC_INTERVAL,
 	}
 }
 
@@ -42,3 +44,8 @@ func (syncIntervals *SyncIntervals) GetPolicies() time.Duration {
 func (syncIntervals *SyncIntervals) GetControlInfo() time.Duration {
 	return syncIntervals.controlInfo
 }
+
+// GetAppliedStatus returns applied status sync interval.
+func (syncIntervals *SyncIntervals) GetAppliedStatus() time.Duration {
+	return syncIntervals.appliedStatus
+}
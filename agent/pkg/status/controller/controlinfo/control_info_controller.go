@@ -7,12 +7,12 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
-	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/controlinfo"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -32,7 +32,7 @@ type LeafHubControlInfoController struct {
 
 // AddControlInfoController creates a new instance of control info controller and adds it to the manager.
 func AddControlInfoController(mgr ctrl.Manager, producer transport.Producer, leafHubName string, incarnation uint64,
-	_ *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	_ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervalsData *config.SyncIntervals,
 ) error {
 	transportBundleKey := fmt.Sprintf("%s.%s", leafHubName, constants.ControlInfoMsgKey)
 
@@ -103,11 +103,12 @@ func (c *LeafHubControlInfoController) syncBundle() {
 	}
 
 	if err := c.transport.Send(context.TODO(), &transport.Message{
-		Key:     transportMessageKey,
-		ID:      c.transportBundleKey,
-		MsgType: constants.StatusBundle,
-		Version: c.bundle.GetBundleVersion().String(),
-		Payload: payloadBytes,
+		Key:           transportMessageKey,
+		ID:            c.transportBundleKey,
+		MsgType:       constants.StatusBundle,
+		Version:       c.bundle.GetBundleVersion().String(),
+		SchemaVersion: config.GetSchemaVersion(),
+		Payload:       payloadBytes,
 	}); err != nil {
 		c.log.Error(err, "send control info error", "messageId", c.transportBundleKey)
 	}
@@ -7,33 +7,46 @@ import (
 	"context"
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	appliedstatusbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/addons"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/appliedstatus"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/apps"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/argocd"
 	globalhubagentconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/controlinfo"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/hubcluster"
 	localpolicies "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/local_policies"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/localplacement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/managedclusters"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/placement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/policies"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/policyreports"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/outbox"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	transportproducer "github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 )
 
 // AddControllers adds all the controllers to the Manager.
-func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig, incarnation uint64) error {
-	config := &corev1.ConfigMap{}
+func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig, incarnation uint64,
+	appliedStatusBundle *appliedstatusbundle.Bundle,
+) error {
+	config := &globalhubv1alpha3.GlobalHubAgentConfig{}
 	syncIntervals := globalhubagentconfig.NewSyncIntervals()
 	if err := globalhubagentconfig.AddConfigController(mgr, config, syncIntervals); err != nil {
-		return fmt.Errorf("failed to add ConfigMap controller: %w", err)
+		return fmt.Errorf("failed to add GlobalHubAgentConfig controller: %w", err)
 	}
 
-	producer, isAsync, err := getProducer(mgr, agentConfig)
+	if err := generic.SetBundleEncoding(agentConfig.TransportBundleEncoding); err != nil {
+		return fmt.Errorf("failed to set the bundle encoding: %w", err)
+	}
+
+	producer, kafkaProducer, err := getProducer(mgr, agentConfig)
 	if err != nil {
 		return fmt.Errorf("failed to get producer: %w", err)
 	}
@@ -44,28 +57,39 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 		return fmt.Errorf("failed to add PoliciesStatusController controller: %w", err)
 	}
 
+	subscriptionStatusSyncManager, err := apps.AddSubscriptionStatusesController(mgr, producer,
+		agentConfig.LeafHubName, incarnation, config, syncIntervals)
+	if err != nil {
+		return fmt.Errorf("failed to add SubscriptionStatusesController controller: %w", err)
+	}
+
 	err = hubcluster.AddHubClusterController(mgr, producer, agentConfig.LeafHubName)
 	if err != nil {
 		return fmt.Errorf("failed to add HubClusterController controller: %w", err)
 	}
 
+	err = appliedstatus.AddAppliedStatusController(mgr, producer, agentConfig.LeafHubName, appliedStatusBundle,
+		syncIntervals)
+	if err != nil {
+		return fmt.Errorf("failed to add AppliedStatusController controller: %w", err)
+	}
+
 	// support delta bundle sync mode
-	if isAsync {
-		kafkaProducer, ok := producer.(*transportproducer.KafkaProducer)
-		if !ok {
-			return fmt.Errorf("failed to set the kafka message producer callback() which is to switch the sync mode")
-		}
+	if kafkaProducer != nil {
 		hybirdSyncManger.SetHybridModeCallBack(agentConfig.StatusDeltaCountSwitchFactor, kafkaProducer)
+		subscriptionStatusSyncManager.SetHybridModeCallBack(agentConfig.StatusDeltaCountSwitchFactor, kafkaProducer)
 	}
 
 	addControllerFunctions := []func(ctrl.Manager, transport.Producer, string, uint64,
-		*corev1.ConfigMap, *globalhubagentconfig.SyncIntervals) error{
+		*globalhubv1alpha3.GlobalHubAgentConfig, *globalhubagentconfig.SyncIntervals) error{
 		managedclusters.AddClustersStatusController,
+		addons.AddManagedClusterAddOnsStatusController,
 		placement.AddPlacementRulesController,
 		placement.AddPlacementsController,
 		placement.AddPlacementDecisionsController,
-		// apps.AddSubscriptionStatusesController,
 		apps.AddSubscriptionReportsController,
+		argocd.AddArgoCDApplicationsStatusController,
+		policyreports.AddPolicyReportsStatusController,
 		localpolicies.AddLocalPoliciesController,
 		localplacement.AddLocalPlacementRulesController,
 		controlinfo.AddControlInfoController,
@@ -86,31 +110,69 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 	return nil
 }
 
-func getProducer(mgr ctrl.Manager, agentConfig *config.AgentConfig) (transport.Producer, bool, error) {
+// getProducer returns the producer of the status messages, and the kafka producer if the kafka message format is
+// used, which supports the delta bundles. the status messages are queued in the outbox unless it's disabled.
+func getProducer(mgr ctrl.Manager, agentConfig *config.AgentConfig,
+) (transport.Producer, *transportproducer.KafkaProducer, error) {
+	var producer transport.Producer
+	var kafkaProducer *transportproducer.KafkaProducer
 	if agentConfig.TransportConfig.TransportFormat == string(transport.KafkaMessageFormat) {
 		// support kafka
-		isAsync := true
 		messageCompressor, err := compressor.NewCompressor(
 			compressor.CompressionType(agentConfig.TransportConfig.MessageCompressionType))
 		if err != nil {
-			return nil, isAsync, fmt.Errorf("failed to create kafka message-compressor: %w", err)
+			return nil, nil, fmt.Errorf("failed to create kafka message-compressor: %w", err)
 		}
-		kafkaProducer, err := transportproducer.NewKafkaProducer(messageCompressor,
+		kafkaProducer, err = transportproducer.NewKafkaProducer(messageCompressor,
 			agentConfig.TransportConfig.KafkaConfig,
 			ctrl.Log.WithName("kafka-message-producer"))
 		if err != nil {
-			return nil, isAsync, fmt.Errorf("failed to create kafka-message-producer: %w", err)
+			return nil, nil, fmt.Errorf("failed to create kafka-message-producer: %w", err)
 		}
 		if err := mgr.Add(kafkaProducer); err != nil {
-			return nil, isAsync, fmt.Errorf("failed to initialize kafka message producer: %w", err)
+			return nil, nil, fmt.Errorf("failed to initialize kafka message producer: %w", err)
 		}
-		return kafkaProducer, isAsync, nil
+		if agentConfig.TransportOutboxSize == 0 {
+			return kafkaProducer, kafkaProducer, nil
+		}
+		// the outbox keeps the message until it's delivered, instead of sending it asynchronously
+		producer = &kafkaSyncProducer{kafkaProducer}
 	} else {
-		isAsync := false
 		genericProducer, err := transportproducer.NewGenericProducer(agentConfig.TransportConfig)
 		if err != nil {
-			return nil, isAsync, fmt.Errorf("failed to init status transport producer: %w", err)
+			return nil, nil, fmt.Errorf("failed to init status transport producer: %w", err)
+		}
+		if agentConfig.TransportOutboxSize == 0 {
+			return genericProducer, nil, nil
+		}
+		producer = genericProducer
+	}
+
+	// queue the status messages in the outbox, so they aren't lost when the transport is unavailable
+	store := outbox.NewMemoryStore()
+	if agentConfig.TransportOutboxDir != "" {
+		var err error
+		store, err = outbox.NewFileStore(agentConfig.TransportOutboxDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to init status transport outbox store: %w", err)
 		}
-		return genericProducer, isAsync, nil
 	}
+	outboxProducer, err := outbox.NewProducer(ctrl.Log.WithName("transport-outbox"), producer, store,
+		agentConfig.TransportOutboxSize, agentConfig.TransportOutboxMaxBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to init status transport outbox: %w", err)
+	}
+	if err := mgr.Add(outboxProducer); err != nil {
+		return nil, nil, fmt.Errorf("failed to add status transport outbox: %w", err)
+	}
+	return outboxProducer, kafkaProducer, nil
+}
+
+// kafkaSyncProducer sends the queued messages of the outbox and waits for the delivery reports.
+type kafkaSyncProducer struct {
+	*transportproducer.KafkaProducer
+}
+
+func (p *kafkaSyncProducer) Send(ctx context.Context, msg *transport.Message) error {
+	return p.SendSync(ctx, msg)
 }
@@ -19,6 +19,7 @@ import (
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -561,6 +562,78 @@ var _ = Describe("Agent Status Controller", Ordered, func() {
 			return nil
 		}, 30*time.Second, 1*time.Second).Should(Succeed())
 	})
+
+	It("should be able to sync the subscriptionstatuses of the global subscriptions", func() {
+		By("Create global subscription and its subscriptionstatus in testing regional hub")
+		testGlobalSubscriptionOriginUID := "test-globalsubscription-uid"
+		testGlobalSubscription := &appsv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-globalsubscription-1",
+				Namespace: "default",
+				Annotations: map[string]string{
+					constants.OriginOwnerReferenceAnnotation: testGlobalSubscriptionOriginUID,
+				},
+			},
+			Spec: appsv1.SubscriptionSpec{
+				Channel: "default/test-channel",
+			},
+		}
+		Expect(kubeClient.Create(ctx, testGlobalSubscription)).ToNot(HaveOccurred())
+
+		testSubscriptionStatus := &appsv1alpha1.SubscriptionStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testGlobalSubscription.GetName(),
+				Namespace: testGlobalSubscription.GetNamespace(),
+				Labels: map[string]string{
+					"apps.open-cluster-management.io/cluster": "hub1-mc1",
+				},
+			},
+			Statuses: appsv1alpha1.SubscriptionClusterStatusMap{
+				SubscriptionStatus: []appsv1alpha1.SubscriptionUnitStatus{
+					{
+						Name:           "nginx-sample",
+						Namespace:      "default",
+						Kind:           "Deployment",
+						APIVersion:     "apps/v1",
+						Phase:          appsv1alpha1.PackageDeployed,
+						LastUpdateTime: metav1.Now(),
+					},
+				},
+			},
+		}
+		Expect(kubeClient.Create(ctx, testSubscriptionStatus)).ToNot(HaveOccurred())
+
+		By("Check the subscriptionstatus bundle can be read from kafka consumer")
+		Eventually(func() error {
+			message := <-consumer.MessageChan()
+			statusBundle, err := getStatusBundle(message, constants.SubscriptionStatusMsgKey)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("========== received %s with statusBundle: %v\n", message.ID, statusBundle)
+
+			subscriptionStatusesBundle, ok := statusBundle.(*statusbundle.SubscriptionStatusesBundle)
+			if !ok {
+				return errors.New("unexpected received bundle type, want SubscriptionStatusesBundle")
+			}
+			if len(subscriptionStatusesBundle.Objects) != 1 {
+				return fmt.Errorf("unexpected object number in received bundle, want 1, got %d\n",
+					len(subscriptionStatusesBundle.Objects))
+			}
+
+			subscriptionStatus := subscriptionStatusesBundle.Objects[0]
+			if subscriptionStatus.GetName() != testSubscriptionStatus.GetName() ||
+				len(subscriptionStatus.Statuses.SubscriptionStatus) != 1 ||
+				subscriptionStatus.GetAnnotations()[constants.OriginOwnerReferenceAnnotation] !=
+					testGlobalSubscriptionOriginUID ||
+				!apiequality.Semantic.DeepDerivative(testSubscriptionStatus.Statuses.SubscriptionStatus[0].Phase,
+					subscriptionStatus.Statuses.SubscriptionStatus[0].Phase) {
+				return fmt.Errorf("object not equal, want %v, got %v\n", testSubscriptionStatus, subscriptionStatus)
+			}
+
+			return nil
+		}, 30*time.Second, 1*time.Second).Should(Succeed())
+	})
 })
 
 func getStatusBundle(message *transport.Message, key string) (status.Bundle, error) {
@@ -24,6 +24,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/incarnation"
 	agentscheme "github.com/stolostron/multicluster-global-hub/agent/pkg/scheme"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
 	statusController "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -98,7 +99,8 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	By("Add controllers to manager")
-	err = statusController.AddControllers(ctx, mgr, agentConfig, incarnation)
+	err = statusController.AddControllers(ctx, mgr, agentConfig, incarnation,
+		appliedstatus.NewBundle(agentConfig.LeafHubName, incarnation))
 	Expect(err).NotTo(HaveOccurred())
 
 	By("Mock the consumer receive message from global hub manager")
@@ -23,4 +23,6 @@ type BundleCollectionEntry struct {
 	bundle                statusbundle.Bundle
 	predicate             func() bool
 	lastSentBundleVersion status.BundleVersion // not pointer so it does not point to the bundle's internal version
+	// dependencyBundleKey is the transport key of the complete state bundle a delta state bundle is based on
+	dependencyBundleKey string
 }
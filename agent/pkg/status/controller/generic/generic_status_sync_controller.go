@@ -2,7 +2,6 @@ package generic
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,6 +18,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/helper"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -40,6 +40,20 @@ type genericStatusSyncController struct {
 	lock                    sync.Mutex
 }
 
+// bundleContentType is the content type of the bundles sent by the generic status sync controllers.
+var bundleContentType = encoding.ContentTypeJSON
+
+// SetBundleEncoding sets the encoding of the bundles sent by the generic status sync controllers, the bundles which
+// can't be encoded as protobuf are sent as json, and so are all the bundles until the manager advertises protobuf.
+func SetBundleEncoding(bundleEncoding string) error {
+	contentType, err := encoding.ContentType(bundleEncoding)
+	if err != nil {
+		return err
+	}
+	bundleContentType = contentType
+	return nil
+}
+
 // NewGenericStatusSyncController creates a new instance of genericStatusSyncController and adds it to the manager.
 func NewGenericStatusSyncController(mgr ctrl.Manager, logName string, producer transport.Producer,
 	orderedBundleCollection []*BundleCollectionEntry, createObjFunc CreateObjectFunction, predicate predicate.Predicate,
@@ -182,7 +196,8 @@ func (c *genericStatusSyncController) syncBundles() {
 		// send to transport only if bundle has changed.
 		if bundleVersion.NewerThan(&entry.lastSentBundleVersion) {
 
-			payloadBytes, err := json.Marshal(entry.bundle)
+			payloadBytes, contentType, err := encoding.Marshal(entry.bundle,
+				config.ResolveContentType(bundleContentType))
 			if err != nil {
 				c.log.Error(err, "marshal entry.bundle error", "entry.bundleKey", entry.transportBundleKey)
 				continue
@@ -190,16 +205,24 @@ func (c *genericStatusSyncController) syncBundles() {
 
 			messageId := entry.transportBundleKey
 			transportMessageKey := entry.transportBundleKey
+			var dependency *transport.MessageDependency
 			if deltaStateBundle, ok := entry.bundle.(bundle.DeltaStateBundle); ok {
 				transportMessageKey = fmt.Sprintf("%s@%d", entry.transportBundleKey, deltaStateBundle.GetTransportationID())
+				dependency = &transport.MessageDependency{
+					ID:      entry.dependencyBundleKey,
+					Version: deltaStateBundle.GetDependencyVersion().String(),
+				}
 			}
 
 			if err := c.transport.Send(context.TODO(), &transport.Message{
-				Key:     transportMessageKey,
-				ID:      messageId,
-				MsgType: constants.StatusBundle,
-				Version: entry.bundle.GetBundleVersion().String(),
-				Payload: payloadBytes,
+				Key:           transportMessageKey,
+				ID:            messageId,
+				MsgType:       constants.StatusBundle,
+				Version:       entry.bundle.GetBundleVersion().String(),
+				SchemaVersion: config.GetSchemaVersion(),
+				ContentType:   contentType,
+				Dependency:    dependency,
+				Payload:       payloadBytes,
 			}); err != nil {
 				c.log.Error(err, "send transport message error", "id", messageId)
 				continue
@@ -36,6 +36,8 @@ func NewHybridSyncManager(log logr.Logger, completeStateBundleCollectionEntry *B
 		return nil, errExpectingDeltaStateBundle
 	}
 
+	deltaStateBundleCollectionEntry.dependencyBundleKey = completeStateBundleCollectionEntry.transportBundleKey
+
 	hybridSyncManager := &HybridSyncManager{
 		log:            log,
 		activeSyncMode: bundle.CompleteStateMode,
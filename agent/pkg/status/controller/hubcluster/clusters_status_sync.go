@@ -15,6 +15,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/hubcluster"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -85,11 +86,12 @@ func (c *hubClusterController) syncBundle(ctx context.Context, route *routev1.Ro
 	}
 
 	if err := c.transport.Send(ctx, &transport.Message{
-		Key:     c.transportBundleKey,
-		ID:      c.transportBundleKey,
-		MsgType: constants.StatusBundle,
-		Version: c.bundle.GetBundleVersion().String(),
-		Payload: payloadBytes,
+		Key:           c.transportBundleKey,
+		ID:            c.transportBundleKey,
+		MsgType:       constants.StatusBundle,
+		Version:       c.bundle.GetBundleVersion().String(),
+		SchemaVersion: config.GetSchemaVersion(),
+		Payload:       payloadBytes,
 	}); err != nil {
 		c.log.Error(err, "send hub cluster info error", "messageId", c.transportBundleKey)
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,13 +14,15 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/grc"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 // AddLocalPoliciesController this function adds a new local policies sync controller.
 func AddLocalClusterPoliciesController(ctx context.Context, mgr ctrl.Manager, producer transport.Producer,
-	leafHubName string, incarnation uint64, hubOfHubsConfig *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	leafHubName string, incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
+	syncIntervalsData *config.SyncIntervals,
 ) error {
 	createObjFunc := func() bundle.Object { return &policiesv1.Policy{} }
 
@@ -32,7 +33,7 @@ func AddLocalClusterPoliciesController(ctx context.Context, mgr ctrl.Manager, pr
 
 	localClusterPolicyBundleEntryCollection := []*generic.BundleCollectionEntry{
 		generic.NewBundleCollectionEntry(localClusterPolicyHistoryEventTransportKey, clusterPolicyHistoryEventBundle,
-			func() bool { return hubOfHubsConfig.Data["enableLocalPolicies"] == "true" }),
+			func() bool { return hubOfHubsConfig.Spec.LocalPoliciesEnabled() }),
 	}
 
 	localClusterPolicyPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
@@ -3,7 +3,6 @@ package localpolicies
 import (
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,6 +13,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/grc"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -25,7 +25,8 @@ const (
 
 // AddLocalPoliciesController this function adds a new local policies sync controller.
 func AddLocalPoliciesController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, hubOfHubsConfig *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
+	syncIntervalsData *config.SyncIntervals,
 ) error {
 	createObjFunc := func() bundle.Object { return &policiesv1.Policy{} }
 	bundleCollection := createBundleCollection(leafHubName, incarnation, hubOfHubsConfig)
@@ -44,7 +45,7 @@ func AddLocalPoliciesController(mgr ctrl.Manager, producer transport.Producer, l
 }
 
 func createBundleCollection(leafHubName string, incarnation uint64,
-	hubOfHubsConfig *corev1.ConfigMap,
+	hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
 ) []*generic.BundleCollectionEntry {
 	extractLocalPolicyIDFunc := func(obj bundle.Object) (string, bool) { return string(obj.GetUID()), true }
 
@@ -65,8 +66,8 @@ func createBundleCollection(leafHubName string, incarnation uint64,
 
 	// check for full information
 	localPolicyStatusPredicate := func() bool {
-		return hubOfHubsConfig.Data["aggregationLevel"] == "full" &&
-			hubOfHubsConfig.Data["enableLocalPolicies"] == "true"
+		return hubOfHubsConfig.Spec.AggregationLevel == globalhubv1alpha3.FullAggregation &&
+			hubOfHubsConfig.Spec.LocalPoliciesEnabled()
 	}
 	// multiple bundles for local policies
 	return []*generic.BundleCollectionEntry{
@@ -75,7 +76,7 @@ func createBundleCollection(leafHubName string, incarnation uint64,
 		generic.NewBundleCollectionEntry(localCompleteComplianceStatusTransportKey,
 			localCompleteComplianceStatusBundle, localPolicyStatusPredicate),
 		generic.NewBundleCollectionEntry(localPolicySpecTransportKey, localPolicySpecBundle,
-			func() bool { return hubOfHubsConfig.Data["enableLocalPolicies"] == "true" }),
+			func() bool { return hubOfHubsConfig.Spec.LocalPoliciesEnabled() }),
 	}
 }
 
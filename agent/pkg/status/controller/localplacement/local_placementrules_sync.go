@@ -3,7 +3,6 @@ package localplacement
 import (
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	placementrulesv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,6 +12,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -23,7 +23,8 @@ const (
 
 // AddLocalPlacementRulesController adds a new local placement rules controller.
 func AddLocalPlacementRulesController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, hubOfHubsConfig *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
+	syncIntervalsData *config.SyncIntervals,
 ) error {
 	createObjFunc := func() bundle.Object { return &placementrulesv1.PlacementRule{} }
 
@@ -33,7 +34,7 @@ func AddLocalPlacementRulesController(mgr ctrl.Manager, producer transport.Produ
 		generic.NewBundleCollectionEntry(localPlacementRuleTransportKey,
 			bundle.NewGenericStatusBundle(leafHubName, incarnation, cleanPlacementRule),
 			func() bool { // bundle predicate
-				return hubOfHubsConfig.Data["enableLocalPolicies"] == "true"
+				return hubOfHubsConfig.Spec.LocalPoliciesEnabled()
 			}),
 	}
 	// controller predicate
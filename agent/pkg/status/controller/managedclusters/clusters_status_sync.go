@@ -3,7 +3,6 @@ package managedclusters
 import (
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	clusterV1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -22,7 +22,7 @@ const (
 // mgr, pro, env.LeafHubID, incarnation, config, syncIntervals
 // AddClustersStatusController adds managed clusters status controller to the manager.
 func AddClustersStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, hubOfHubsConfig *corev1.ConfigMap, syncIntervals *config.SyncIntervals,
+	incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervals *config.SyncIntervals,
 ) error {
 	createObjFunction := func() bundle.Object { return &clusterV1.ManagedCluster{} }
 	transportBundleKey := fmt.Sprintf("%s.%s", leafHubName, constants.ManagedClustersMsgKey)
@@ -35,15 +35,15 @@ func AddClustersStatusController(mgr ctrl.Manager, producer transport.Producer,
 	}
 
 	predicateFunc := func() bool {
-		// return hubOfHubsConfig.Data["aggregationLevel"] == "full" ||
-		// 	hubOfHubsConfig.Data["aggregationLevel"] == "minimal"
+		// return hubOfHubsConfig.Spec.AggregationLevel == globalhubv1alpha3.FullAggregation ||
+		// 	hubOfHubsConfig.Spec.AggregationLevel == globalhubv1alpha3.MinimalAggregation
 		// at this point send all managed clusters even if aggregation level is minimal
 		return true
 	}
 
 	bundleCollection := []*generic.BundleCollectionEntry{ // single bundle for managed clusters
 		generic.NewBundleCollectionEntry(transportBundleKey,
-			bundle.NewGenericStatusBundle(leafHubName, incarnation, manipulateObjFunc),
+			bundle.NewManagedClustersStatusBundle(leafHubName, incarnation, manipulateObjFunc),
 			predicateFunc),
 	}
 
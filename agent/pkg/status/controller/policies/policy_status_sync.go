@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	policiesV1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +14,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/grc"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -27,7 +27,8 @@ const (
 
 // AddPoliciesStatusController adds policies status controller to the manager.
 func AddPoliciesStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
-	incarnation uint64, hubOfHubsConfig *corev1.ConfigMap, syncIntervalsData *config.SyncIntervals,
+	incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
+	syncIntervalsData *config.SyncIntervals,
 ) (*generic.HybridSyncManager, error) {
 	bundleCollection, hybridSyncManager, err :=
 		createBundleCollection(producer, leafHubName, incarnation, hubOfHubsConfig)
@@ -55,7 +56,7 @@ func AddPoliciesStatusController(mgr ctrl.Manager, producer transport.Producer,
 }
 
 func createBundleCollection(pro transport.Producer, leafHubName string,
-	incarnation uint64, hubOfHubsConfig *corev1.ConfigMap,
+	incarnation uint64, hubOfHubsConfig *globalhubv1alpha3.GlobalHubAgentConfig,
 ) ([]*generic.BundleCollectionEntry, *generic.HybridSyncManager, error) {
 	// clusters per policy (base bundle)
 	clustersPerPolicyTransportKey := fmt.Sprintf("%s.%s", leafHubName, constants.ClustersPerPolicyMsgKey)
@@ -66,9 +67,11 @@ func createBundleCollection(pro transport.Producer, leafHubName string,
 		constants.MinimalPolicyComplianceMsgKey)
 	minimalComplianceStatusBundle := grc.NewMinimalComplianceStatusBundle(leafHubName, incarnation)
 
-	fullStatusPredicate := func() bool { return hubOfHubsConfig.Data["aggregationLevel"] == "full" }
+	fullStatusPredicate := func() bool {
+		return hubOfHubsConfig.Spec.AggregationLevel == globalhubv1alpha3.FullAggregation
+	}
 	minimalStatusPredicate := func() bool {
-		return hubOfHubsConfig.Data["aggregationLevel"] == "minimal"
+		return hubOfHubsConfig.Spec.AggregationLevel == globalhubv1alpha3.MinimalAggregation
 	}
 
 	// apply a hybrid sync manager on the (full aggregation) compliance bundles
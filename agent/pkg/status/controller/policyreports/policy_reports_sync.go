@@ -0,0 +1,193 @@
+package policyreports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policyreportsbundle "github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/policyreports"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const policyReportsSyncLog = "policy-reports-status-sync"
+
+// reportGroupVersions are the api versions of the collected policy reports, the kinds are discovered since every
+// gatekeeper constraint template creates its own constraint kind.
+var reportGroupVersions = []schema.GroupVersion{
+	{Group: policyreportsbundle.KyvernoReportGroup, Version: "v1alpha2"},
+	{Group: policyreportsbundle.GatekeeperConstraintGroup, Version: "v1beta1"},
+}
+
+// PolicyReportsStatusController collects the kyverno policy reports and the gatekeeper constraints periodically.
+// the reports are listed instead of watched since the constraint kinds come and go with the constraint templates.
+type PolicyReportsStatusController struct {
+	log                     logr.Logger
+	client                  client.Reader
+	discoveryClient         discovery.DiscoveryInterface
+	bundle                  *policyreportsbundle.PolicyReportBundle
+	transportBundleKey      string
+	transport               transport.Producer
+	resolveSyncIntervalFunc config.ResolveSyncIntervalFunc
+	lastSentBundleVersion   status.BundleVersion
+}
+
+// AddPolicyReportsStatusController creates a new instance of policy reports controller and adds it to the manager.
+func AddPolicyReportsStatusController(mgr ctrl.Manager, producer transport.Producer, leafHubName string,
+	incarnation uint64, _ *globalhubv1alpha3.GlobalHubAgentConfig, syncIntervals *config.SyncIntervals,
+) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client - %w", err)
+	}
+
+	// the reports in the namespace of a managed cluster are about the managed cluster
+	k8sClient := mgr.GetClient()
+	resolveClusterNameFunc := func(namespace string) string {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: namespace}, cluster); err != nil {
+			return ""
+		}
+		return cluster.GetName()
+	}
+
+	reportBundle := policyreportsbundle.NewPolicyReportBundle(leafHubName, incarnation, resolveClusterNameFunc)
+	policyReportsCtrl := &PolicyReportsStatusController{
+		log:                     ctrl.Log.WithName(policyReportsSyncLog),
+		client:                  mgr.GetAPIReader(),
+		discoveryClient:         discoveryClient,
+		bundle:                  reportBundle.(*policyreportsbundle.PolicyReportBundle),
+		transportBundleKey:      fmt.Sprintf("%s.%s", leafHubName, constants.PolicyReportMsgKey),
+		transport:               producer,
+		resolveSyncIntervalFunc: syncIntervals.GetPolicies,
+		lastSentBundleVersion:   *reportBundle.GetBundleVersion(),
+	}
+
+	if err := mgr.Add(policyReportsCtrl); err != nil {
+		return fmt.Errorf("failed to add policy reports controller to the manager - %w", err)
+	}
+
+	return nil
+}
+
+// Start function starts policy reports controller.
+func (c *PolicyReportsStatusController) Start(ctx context.Context) error {
+	c.log.Info("Starting Controller")
+
+	go c.periodicSync(ctx)
+
+	<-ctx.Done() // blocking wait for stop event
+	c.log.Info("Stopping Controller")
+
+	return nil
+}
+
+func (c *PolicyReportsStatusController) periodicSync(ctx context.Context) {
+	currentSyncInterval := c.resolveSyncIntervalFunc()
+	ticker := time.NewTicker(currentSyncInterval)
+	c.syncBundle(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+
+		case <-ticker.C: // wait for next time interval
+			c.syncBundle(ctx)
+
+			resolvedInterval := c.resolveSyncIntervalFunc()
+
+			// reset ticker if sync interval has changed
+			if resolvedInterval != currentSyncInterval {
+				currentSyncInterval = resolvedInterval
+				ticker.Reset(currentSyncInterval)
+				c.log.Info(fmt.Sprintf("sync interval has been reset to %s", currentSyncInterval.String()))
+			}
+		}
+	}
+}
+
+func (c *PolicyReportsStatusController) syncBundle(ctx context.Context) {
+	objects, err := c.listReports(ctx)
+	if err != nil {
+		// keep the reports of the last sync, otherwise the reports are deleted from the global hub
+		c.log.Error(err, "failed to list the policy reports")
+		return
+	}
+	c.bundle.SyncObjects(objects)
+
+	// send to transport only if bundle has changed.
+	bundleVersion := c.bundle.GetBundleVersion()
+	if !bundleVersion.NewerThan(&c.lastSentBundleVersion) {
+		return
+	}
+
+	payloadBytes, err := json.Marshal(c.bundle)
+	if err != nil {
+		c.log.Error(err, "marshal policy reports bundle error", "transportBundleKey", c.transportBundleKey)
+		return
+	}
+
+	if err := c.transport.Send(context.TODO(), &transport.Message{
+		Key:           c.transportBundleKey,
+		ID:            c.transportBundleKey,
+		MsgType:       constants.StatusBundle,
+		Version:       bundleVersion.String(),
+		SchemaVersion: config.GetSchemaVersion(),
+		Payload:       payloadBytes,
+	}); err != nil {
+		c.log.Error(err, "send policy reports error", "messageId", c.transportBundleKey)
+		return
+	}
+
+	c.lastSentBundleVersion = *bundleVersion
+}
+
+// listReports lists the reports of the installed policy engines.
+func (c *PolicyReportsStatusController) listReports(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	objects := make([]*unstructured.Unstructured, 0)
+
+	for _, groupVersion := range reportGroupVersions {
+		resources, err := c.discoveryClient.ServerResourcesForGroupVersion(groupVersion.String())
+		if apierrors.IsNotFound(err) {
+			continue // the policy engine isn't installed on the leaf hub
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the resources of %s - %w", groupVersion.String(), err)
+		}
+
+		for _, resource := range resources.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue // skip the subresources
+			}
+
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(groupVersion.WithKind(resource.Kind + "List"))
+			if err := c.client.List(ctx, list); err != nil {
+				return nil, fmt.Errorf("failed to list %s - %w", resource.Kind, err)
+			}
+
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+		}
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	queuedMessages = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_agent_outbox_messages",
+		Help: "The number of the status messages queued in the outbox to be sent to the global hub.",
+	})
+	queuedBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_agent_outbox_bytes",
+		Help: "The payload size of the status messages queued in the outbox.",
+	})
+	coalescedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_outbox_coalesced_total",
+		Help: "The number of the queued messages superseded by a newer complete state message.",
+	})
+	droppedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_outbox_dropped_total",
+		Help: "The number of the queued messages dropped since the outbox is full, by the number or the bytes.",
+	})
+	undeliverableMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_outbox_undeliverable_total",
+		Help: "The number of the queued messages dropped since they fail to be sent with a non-retriable error or " +
+			"after the max attempts, including the delta state messages depending on them.",
+	})
+	sendFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_outbox_send_failures_total",
+		Help: "The number of the failed attempts to send the queued messages to the transport.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(queuedMessages, queuedBytes, coalescedMessages, droppedMessages,
+		undeliverableMessages, sendFailures)
+}
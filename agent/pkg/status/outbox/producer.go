@@ -0,0 +1,283 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	initialRetryDelay = time.Second
+	maxRetryDelay     = 5 * time.Minute
+	// the message is dropped after about an hour of the failed attempts with the backoff
+	maxSendAttempts = 20
+)
+
+// Producer is the outbox of the status messages. the messages are queued in order and sent by the wrapped producer,
+// the failed message is retried with backoff until the transport is available again, so the delta state bundles
+// aren't lost when the transport is down. the message is dropped if it fails with a non-retriable error, e.g. it's
+// too large for the transport, or if it still fails after the max attempts, so it doesn't block the messages queued
+// after it. a queued complete state message is superseded by the newer message of the
+// same bundle, and the oldest message is dropped when the outbox is full. the delta state messages depending on a
+// superseded or dropped complete state message are dropped with it, since they can't be applied without it.
+type Producer struct {
+	log          logr.Logger
+	producer     transport.Producer
+	store        Store
+	maxMessages  int
+	maxBytes     int
+	entries      []*Entry
+	bytes        int
+	sending      *Entry
+	nextSequence uint64
+	lock         sync.Mutex
+	notifyChan   chan struct{}
+	// the delay of the first retry, it's doubled by each failure up to the max delay
+	initialRetryDelay time.Duration
+	maxRetryDelay     time.Duration
+	maxSendAttempts   int
+}
+
+// NewProducer creates the outbox with the queued messages of the store, the outbox holds up to maxMessages messages
+// and up to maxBytes bytes of payload, the size in bytes isn't limited if maxBytes is 0.
+func NewProducer(log logr.Logger, producer transport.Producer, store Store, maxMessages, maxBytes int,
+) (*Producer, error) {
+	if maxMessages < 1 {
+		return nil, fmt.Errorf("the outbox size %d must be positive", maxMessages)
+	}
+	if maxBytes < 0 {
+		return nil, fmt.Errorf("the outbox size in bytes %d must not be negative", maxBytes)
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the outbox - %w", err)
+	}
+
+	outbox := &Producer{
+		log:         log,
+		producer:    producer,
+		store:       store,
+		maxMessages: maxMessages,
+		maxBytes:    maxBytes,
+		entries:     entries,
+		notifyChan:  make(chan struct{}, 1),
+
+		initialRetryDelay: initialRetryDelay,
+		maxRetryDelay:     maxRetryDelay,
+		maxSendAttempts:   maxSendAttempts,
+	}
+	for _, entry := range entries {
+		outbox.bytes += len(entry.Message.Payload)
+	}
+	if len(entries) > 0 {
+		outbox.nextSequence = entries[len(entries)-1].Sequence + 1
+		log.Info("loaded the queued messages", "messages", len(entries), "bytes", outbox.bytes)
+	}
+	// the limits might be lowered since the messages were queued
+	outbox.evict()
+	outbox.updateMetrics()
+
+	return outbox, nil
+}
+
+// Send queues the message, it only fails if the message can't be persisted.
+func (p *Producer) Send(ctx context.Context, msg *transport.Message) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	entry := &Entry{Sequence: p.nextSequence, Message: msg}
+	if err := p.store.Put(entry); err != nil {
+		return err
+	}
+	p.nextSequence++
+
+	// the complete state message supersedes the queued one of the same bundle along with the delta state messages
+	// depending on it, the other delta state messages are kept since each of them only holds the changes since the
+	// previous message
+	if msg.Dependency == nil {
+		superseded := p.removeEntries(func(queued *Entry) bool {
+			return queued.Message.Dependency == nil && queued.Message.ID == msg.ID
+		})
+		coalesced := len(superseded)
+		for _, completeEntry := range superseded {
+			coalesced += len(p.removeEntries(dependsOn(completeEntry)))
+		}
+		coalescedMessages.Add(float64(coalesced))
+	}
+
+	p.entries = append(p.entries, entry)
+	p.bytes += len(msg.Payload)
+	p.evict()
+	p.updateMetrics()
+
+	select {
+	case p.notifyChan <- struct{}{}:
+	default: // the sender is already notified
+	}
+
+	return nil
+}
+
+// Start sends the queued messages until the context is done.
+func (p *Producer) Start(ctx context.Context) error {
+	p.log.Info("Starting Controller")
+
+	retryDelay := p.initialRetryDelay
+	attempts := 0
+	for {
+		entry := p.head()
+		if entry == nil {
+			select {
+			case <-ctx.Done():
+				p.log.Info("Stopping Controller")
+				return nil
+			case <-p.notifyChan:
+				continue
+			}
+		}
+
+		err := p.producer.Send(ctx, entry.Message)
+		if err == nil {
+			retryDelay, attempts = p.initialRetryDelay, 0
+			p.remove(entry)
+			continue
+		}
+
+		sendFailures.Inc()
+		attempts++
+		if transport.IsNonRetriable(err) || attempts >= p.maxSendAttempts {
+			p.log.Error(err, "failed to send the queued message, dropping it", "id", entry.Message.ID,
+				"version", entry.Message.Version, "attempts", attempts)
+			retryDelay, attempts = p.initialRetryDelay, 0
+			p.drop(entry)
+			continue
+		}
+
+		p.log.Error(err, "failed to send the queued message, retrying", "id", entry.Message.ID,
+			"version", entry.Message.Version, "attempts", attempts, "delay", retryDelay.String())
+		select {
+		case <-ctx.Done():
+			p.log.Info("Stopping Controller")
+			return nil
+		case <-time.After(retryDelay):
+		}
+
+		retryDelay *= 2
+		if retryDelay > p.maxRetryDelay {
+			retryDelay = p.maxRetryDelay
+		}
+	}
+}
+
+// Len returns the number of the queued messages.
+func (p *Producer) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return len(p.entries)
+}
+
+// head returns the oldest entry, it's kept in the outbox while it's being sent.
+func (p *Producer) head() *Entry {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.entries) == 0 {
+		return nil
+	}
+
+	p.sending = p.entries[0]
+	return p.sending
+}
+
+// remove deletes the sent entry.
+func (p *Producer) remove(entry *Entry) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sending = nil
+	p.removeEntries(func(queued *Entry) bool { return queued == entry })
+	p.updateMetrics()
+}
+
+// drop deletes the entry which can't be sent, a complete state message is dropped along with the delta state
+// messages depending on it.
+func (p *Producer) drop(entry *Entry) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.sending = nil
+	dropped := p.removeEntries(func(queued *Entry) bool { return queued == entry })
+	if entry.Message.Dependency == nil {
+		dropped = append(dropped, p.removeEntries(dependsOn(entry))...)
+	}
+	undeliverableMessages.Add(float64(len(dropped)))
+	p.updateMetrics()
+}
+
+// evict drops the oldest messages until the outbox is within the limits, a complete state message is dropped along
+// with the delta state messages depending on it.
+func (p *Producer) evict() {
+	for len(p.entries) > p.maxMessages || (p.maxBytes > 0 && p.bytes > p.maxBytes) {
+		var oldest *Entry
+		for _, entry := range p.entries {
+			if entry != p.sending {
+				oldest = entry
+				break
+			}
+		}
+		if oldest == nil {
+			return
+		}
+
+		p.log.Info("the outbox is full, dropping the oldest message", "id", oldest.Message.ID,
+			"version", oldest.Message.Version)
+		dropped := p.removeEntries(func(queued *Entry) bool { return queued == oldest })
+		if oldest.Message.Dependency == nil {
+			dropped = append(dropped, p.removeEntries(dependsOn(oldest))...)
+		}
+		droppedMessages.Add(float64(len(dropped)))
+	}
+}
+
+// removeEntries deletes the matching entries except the one being sent, and returns the deleted entries.
+func (p *Producer) removeEntries(match func(*Entry) bool) []*Entry {
+	var removed []*Entry
+	entries := p.entries[:0]
+	for _, entry := range p.entries {
+		if entry == p.sending || !match(entry) {
+			entries = append(entries, entry)
+			continue
+		}
+		if err := p.store.Delete(entry); err != nil {
+			p.log.Error(err, "failed to delete the outbox entry", "sequence", entry.Sequence)
+		}
+		p.bytes -= len(entry.Message.Payload)
+		removed = append(removed, entry)
+	}
+	p.entries = entries
+	return removed
+}
+
+func (p *Producer) updateMetrics() {
+	queuedMessages.Set(float64(len(p.entries)))
+	queuedBytes.Set(float64(p.bytes))
+}
+
+// dependsOn matches the delta state messages depending on the given complete state message.
+func dependsOn(completeEntry *Entry) func(*Entry) bool {
+	return func(entry *Entry) bool {
+		dependency := entry.Message.Dependency
+		return dependency != nil && dependency.ID == completeEntry.Message.ID &&
+			dependency.Version == completeEntry.Message.Version
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// fakeProducer fails until it's available, and always fails the messages with the given errors.
+type fakeProducer struct {
+	lock      sync.Mutex
+	available bool
+	failures  map[string]error
+	attempts  int
+	sent      []*transport.Message
+}
+
+func (p *fakeProducer) Send(ctx context.Context, msg *transport.Message) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.attempts++
+	if err, found := p.failures[msg.ID]; found {
+		return err
+	}
+	if !p.available {
+		return errors.New("transport is unavailable")
+	}
+	p.sent = append(p.sent, msg)
+	return nil
+}
+
+func (p *fakeProducer) setAvailable() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.available = true
+}
+
+func (p *fakeProducer) sendAttempts() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.attempts
+}
+
+func (p *fakeProducer) sentMessages() []*transport.Message {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]*transport.Message{}, p.sent...)
+}
+
+func completeMessage(id, version string) *transport.Message {
+	return &transport.Message{Key: id, ID: id, Version: version, Payload: []byte(version)}
+}
+
+func deltaMessage(id, version, dependencyID, dependencyVersion string) *transport.Message {
+	return &transport.Message{
+		Key: id + "@" + version, ID: id, Version: version, Payload: []byte(version),
+		Dependency: &transport.MessageDependency{ID: dependencyID, Version: dependencyVersion},
+	}
+}
+
+func TestOutboxProducer(t *testing.T) {
+	fake := &fakeProducer{}
+	outbox, err := NewProducer(ctrl.Log.WithName("outbox"), fake, NewMemoryStore(), 3, 0)
+	assert.NoError(t, err)
+	outbox.initialRetryDelay = 10 * time.Millisecond
+	outbox.maxRetryDelay = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the messages are queued while the transport is unavailable
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, deltaMessage("hub1.DeltaCompliance", "0.1", "hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, deltaMessage("hub1.DeltaCompliance", "0.2", "hub1.Compliance", "0.1")))
+	assert.Equal(t, 3, outbox.Len())
+
+	// the complete state message supersedes the queued one of the same bundle and the delta state messages of it
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Compliance", "0.2")))
+	assert.Equal(t, 1, outbox.Len())
+	assert.NoError(t, outbox.Send(ctx, deltaMessage("hub1.DeltaCompliance", "0.3", "hub1.Compliance", "0.2")))
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.ManagedClusters", "0.1")))
+	assert.Equal(t, 3, outbox.Len())
+
+	// the oldest complete state message is dropped along with its delta state messages when the outbox is full
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.ControlInfo", "0.1")))
+	assert.Equal(t, 2, outbox.Len())
+
+	// the queued messages are sent in order once the transport is available
+	go func() { _ = outbox.Start(ctx) }()
+	fake.setAvailable()
+	assert.Eventually(t, func() bool { return outbox.Len() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	sent := fake.sentMessages()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "hub1.ManagedClusters", sent[0].Key)
+	assert.Equal(t, "hub1.ControlInfo", sent[1].Key)
+}
+
+func TestOutboxProducerSending(t *testing.T) {
+	fake := &fakeProducer{}
+	outbox, err := NewProducer(ctrl.Log.WithName("outbox"), fake, NewMemoryStore(), 2, 0)
+	assert.NoError(t, err)
+	outbox.initialRetryDelay = 10 * time.Millisecond
+	outbox.maxRetryDelay = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = outbox.Start(ctx) }()
+
+	// the message being sent is neither superseded nor dropped, so the delta state messages of it stay valid
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Compliance", "0.1")))
+	assert.Eventually(t, func() bool { return fake.sendAttempts() > 0 }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, outbox.Send(ctx, deltaMessage("hub1.DeltaCompliance", "0.1", "hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Compliance", "0.2")))
+	assert.Equal(t, 2, outbox.Len())
+
+	fake.setAvailable()
+	assert.Eventually(t, func() bool { return outbox.Len() == 0 }, 5*time.Second, 10*time.Millisecond)
+	sent := fake.sentMessages()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "0.1", sent[0].Version)
+	assert.Equal(t, "0.2", sent[1].Version)
+}
+
+func TestOutboxProducerUndeliverable(t *testing.T) {
+	fake := &fakeProducer{available: true, failures: map[string]error{
+		"hub1.Oversized":  transport.NewNonRetriableError(errors.New("message size too large")),
+		"hub1.Compliance": errors.New("leader not available"),
+	}}
+	outbox, err := NewProducer(ctrl.Log.WithName("outbox"), fake, NewMemoryStore(), 10, 0)
+	assert.NoError(t, err)
+	outbox.initialRetryDelay = 10 * time.Millisecond
+	outbox.maxRetryDelay = 20 * time.Millisecond
+	outbox.maxSendAttempts = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Oversized", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, deltaMessage("hub1.DeltaCompliance", "0.1", "hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(ctx, completeMessage("hub1.ManagedClusters", "0.1")))
+
+	// the non-retriable message is dropped at once, the retriable one after the max attempts along with the delta
+	// state message depending on it, so the messages queued after them are still sent
+	go func() { _ = outbox.Start(ctx) }()
+	assert.Eventually(t, func() bool { return outbox.Len() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	sent := fake.sentMessages()
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "hub1.ManagedClusters", sent[0].Key)
+	assert.Equal(t, 1+3+1, fake.sendAttempts())
+}
+
+func TestOutboxProducerMaxBytes(t *testing.T) {
+	outbox, err := NewProducer(ctrl.Log.WithName("outbox"), &fakeProducer{}, NewMemoryStore(), 10, 6)
+	assert.NoError(t, err)
+
+	assert.NoError(t, outbox.Send(context.Background(), completeMessage("hub1.ManagedClusters", "0.1")))
+	assert.NoError(t, outbox.Send(context.Background(), completeMessage("hub1.Compliance", "0.1")))
+	assert.Equal(t, 2, outbox.Len())
+
+	// the oldest message is dropped once the payload exceeds the max bytes
+	assert.NoError(t, outbox.Send(context.Background(), completeMessage("hub1.ControlInfo", "0.1")))
+	assert.Equal(t, 2, outbox.Len())
+	assert.Equal(t, "hub1.Compliance", outbox.entries[0].Message.ID)
+	assert.Equal(t, 6, outbox.bytes)
+
+	_, err = NewProducer(ctrl.Log.WithName("outbox"), &fakeProducer{}, NewMemoryStore(), 10, -1)
+	assert.Error(t, err)
+}
+
+func TestOutboxFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	assert.NoError(t, err)
+
+	outbox, err := NewProducer(ctrl.Log.WithName("outbox"), &fakeProducer{}, store, 10, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, outbox.Send(context.Background(), completeMessage("hub1.ManagedClusters", "0.1")))
+	assert.NoError(t, outbox.Send(context.Background(),
+		deltaMessage("hub1.DeltaCompliance", "0.1", "hub1.Compliance", "0.1")))
+	assert.NoError(t, outbox.Send(context.Background(), completeMessage("hub1.ManagedClusters", "0.2")))
+
+	// the queued messages are loaded by the restarted agent
+	restartedStore, err := NewFileStore(dir)
+	assert.NoError(t, err)
+	restarted, err := NewProducer(ctrl.Log.WithName("outbox"), &fakeProducer{}, restartedStore, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restarted.Len())
+	assert.Equal(t, "hub1.DeltaCompliance@0.1", restarted.entries[0].Message.Key)
+	assert.Equal(t, "hub1.Compliance", restarted.entries[0].Message.Dependency.ID)
+	assert.Equal(t, "0.2", restarted.entries[1].Message.Version)
+	assert.Equal(t, []byte("0.2"), restarted.entries[1].Message.Payload)
+
+	// the new messages are queued after the loaded ones
+	assert.NoError(t, restarted.Send(context.Background(), completeMessage("hub1.ControlInfo", "0.1")))
+	assert.Greater(t, restarted.entries[2].Sequence, restarted.entries[1].Sequence)
+}
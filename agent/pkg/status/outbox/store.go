@@ -0,0 +1,136 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	entryFileSuffix    = ".json"
+	entryTmpFileSuffix = ".tmp"
+)
+
+// Entry is a message queued in the outbox, the sequence keeps the order of the messages.
+type Entry struct {
+	Sequence uint64
+	Message  *transport.Message
+}
+
+// Store persists the queued messages of the outbox, so the messages survive the restart of the agent.
+type Store interface {
+	// Load returns the persisted entries ordered by the sequence.
+	Load() ([]*Entry, error)
+	// Put persists the entry.
+	Put(entry *Entry) error
+	// Delete removes the entry, it's called once the entry is sent or superseded.
+	Delete(entry *Entry) error
+}
+
+// NewMemoryStore creates a store which doesn't persist the entries, the queued messages are lost on restart.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+type memoryStore struct{}
+
+func (s *memoryStore) Load() ([]*Entry, error) { return nil, nil }
+
+func (s *memoryStore) Put(entry *Entry) error { return nil }
+
+func (s *memoryStore) Delete(entry *Entry) error { return nil }
+
+// NewFileStore creates a store which persists each entry as a file of the directory, e.g. a mounted volume.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox directory %s - %w", dir, err)
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+type fileStore struct {
+	dir string
+}
+
+// Load reads the entries of the directory, the files which can't be parsed are removed.
+func (s *fileStore) Load() ([]*Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the outbox directory %s - %w", s.dir, err)
+	}
+
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(s.dir, file.Name())
+		if strings.HasSuffix(file.Name(), entryTmpFileSuffix) {
+			_ = os.Remove(path) // the agent stopped while writing the entry
+			continue
+		}
+		if file.IsDir() || !strings.HasSuffix(file.Name(), entryFileSuffix) {
+			continue
+		}
+
+		sequence, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), entryFileSuffix), 10, 64)
+		if err != nil {
+			_ = os.Remove(path)
+			continue
+		}
+		data, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the outbox entry %s - %w", path, err)
+		}
+		message := &transport.Message{}
+		if err := json.Unmarshal(data, message); err != nil {
+			// the entry was written partially, e.g. the disk was full
+			_ = os.Remove(path)
+			continue
+		}
+
+		entries = append(entries, &Entry{Sequence: sequence, Message: message})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Sequence < entries[j].Sequence })
+
+	return entries, nil
+}
+
+// Put writes the entry to a temporary file and renames it, so a loaded entry is always complete.
+func (s *fileStore) Put(entry *Entry) error {
+	data, err := json.Marshal(entry.Message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the outbox entry - %w", err)
+	}
+
+	path := s.entryPath(entry)
+	tmpPath := path + entryTmpFileSuffix
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write the outbox entry %s - %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename the outbox entry %s - %w", tmpPath, err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) Delete(entry *Entry) error {
+	if err := os.Remove(s.entryPath(entry)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete the outbox entry %d - %w", entry.Sequence, err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) entryPath(entry *Entry) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", entry.Sequence, entryFileSuffix))
+}
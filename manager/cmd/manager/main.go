@@ -26,10 +26,12 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/eventcollector"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/scheme"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db/postgresql"
 	statussyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statuswriter"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
@@ -57,8 +59,9 @@ var (
 
 func parseFlags() *managerconfig.ManagerConfig {
 	managerConfig := &managerconfig.ManagerConfig{
-		SyncerConfig:   &managerconfig.SyncerConfig{},
-		DatabaseConfig: &managerconfig.DatabaseConfig{},
+		SyncerConfig:        &managerconfig.SyncerConfig{},
+		HubManagementConfig: &managerconfig.HubManagementConfig{},
+		DatabaseConfig:      &managerconfig.DatabaseConfig{},
 		TransportConfig: &transport.TransportConfig{
 			KafkaConfig: &transport.KafkaConfig{
 				EnableTLS:      true,
@@ -69,6 +72,7 @@ func parseFlags() *managerconfig.ManagerConfig {
 		StatisticsConfig:      &statistics.StatisticsConfig{},
 		NonK8sAPIServerConfig: &nonk8sapi.NonK8sAPIServerConfig{},
 		ElectionConfig:        &commonobjects.LeaderElectionConfig{},
+		ReportStorageConfig:   &report.StorageConfig{},
 	}
 
 	// add zap flags
@@ -86,10 +90,26 @@ func parseFlags() *managerconfig.ManagerConfig {
 			"can be 'month', 'week', 'day', 'hour', 'minute' or 'second', default value is 'day'.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.SpecSyncInterval, "spec-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in spec.")
+	pflag.DurationVar(&managerConfig.SyncerConfig.SpecFullResyncInterval, "spec-full-resync-interval", 5*time.Minute,
+		"The interval of sending the complete bundles of the resources in spec after delta bundles were sent, the "+
+			"leaf hubs which missed a delta bundle converge on the complete bundle.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.StatusSyncInterval, "status-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in status.")
+	pflag.DurationVar(&managerConfig.SyncerConfig.StatusWriteInterval, "status-write-interval", 5*time.Second,
+		"The interval of writing the status aggregated from the leaf hubs back onto the global resources.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.DeletedLabelsTrimmingInterval, "deleted-labels-trimming-interval",
 		5*time.Second, "The trimming interval of deleted labels.")
+	pflag.DurationVar(&managerConfig.SyncerConfig.GlobalSchedulingInterval, "global-scheduling-interval",
+		10*time.Second, "The interval of scheduling the global placements across the leaf hubs, 0 disables the "+
+			"global scheduler.")
+	pflag.DurationVar(&managerConfig.SyncerConfig.RolloutInterval, "rollout-interval", 10*time.Second,
+		"The interval of moving the rollouts of the global resources through their waves and checking the health "+
+			"gates.")
+	pflag.StringVar(&managerConfig.HubManagementConfig.OffboardingRetention, "hub-offboarding-retention",
+		database.RetentionArchive, "The retention of the data of the offboarded leaf hubs, 'archive' moves the rows "+
+			"into the history schema, 'delete' removes them.")
+	pflag.DurationVar(&managerConfig.HubManagementConfig.OffboardingInterval, "hub-offboarding-interval",
+		30*time.Second, "The interval of cleaning up the data of the offboarding leaf hubs.")
 	pflag.IntVar(&managerConfig.DatabaseConfig.MaxOpenConns, "database-pool-size", 10,
 		"The size of database connection pool for the process user.")
 	pflag.StringVar(&managerConfig.DatabaseConfig.ProcessDatabaseURL, "process-database-url", "",
@@ -133,6 +153,16 @@ func parseFlags() *managerconfig.ManagerConfig {
 		"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "The CA bundle path for cluster API.")
 	pflag.StringVar(&managerConfig.NonK8sAPIServerConfig.ServerBasePath, "server-base-path",
 		"/global-hub-api/v1", "The base path for nonK8s API server.")
+	pflag.StringSliceVar(&managerConfig.NonK8sAPIServerConfig.AdminGroups, "admin-groups",
+		[]string{"system:cluster-admins"}, "The groups of the users allowed to decommission the leaf hubs.")
+	pflag.StringVar(&managerConfig.ReportStorageConfig.Endpoint, "report-storage-endpoint", "",
+		"The url of the s3 compatible object storage of the scheduled compliance reports, AWS S3 if it's empty.")
+	pflag.StringVar(&managerConfig.ReportStorageConfig.Region, "report-storage-region", "us-east-1",
+		"The region of the object storage of the scheduled compliance reports.")
+	pflag.StringVar(&managerConfig.ReportStorageConfig.Bucket, "report-storage-bucket", "",
+		"The bucket of the scheduled compliance reports, the reports aren't scheduled if it's empty.")
+	pflag.StringVar(&managerConfig.ReportStorageConfig.Prefix, "report-storage-prefix", "compliance-reports",
+		"The prefix of the object keys of the scheduled compliance reports.")
 	pflag.IntVar(&managerConfig.ElectionConfig.LeaseDuration, "lease-duration", 137, "controller leader lease duration")
 	pflag.IntVar(&managerConfig.ElectionConfig.RenewDeadline, "renew-deadline", 107, "controller leader renew deadline")
 	pflag.IntVar(&managerConfig.ElectionConfig.RetryPeriod, "retry-period", 26, "controller leader retry period")
@@ -154,6 +184,11 @@ func completeConfig(managerConfig *managerconfig.ManagerConfig) error {
 	if managerConfig.DatabaseConfig.ProcessDatabaseURL == "" {
 		return fmt.Errorf("database url for process user: %w", errFlagParameterEmpty)
 	}
+	if retention := managerConfig.HubManagementConfig.OffboardingRetention; retention != database.RetentionArchive &&
+		retention != database.RetentionDelete {
+		return fmt.Errorf("%w - retention must be %q or %q : %s", errFlagParameterIllegalValue,
+			database.RetentionArchive, database.RetentionDelete, "hub-offboarding-retention")
+	}
 	if managerConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB > producer.MaxMessageSizeLimit {
 		return fmt.Errorf("%w - size must not exceed %d : %s", errFlagParameterIllegalValue,
 			managerConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, "kafka-message-size-limit")
@@ -217,8 +252,12 @@ func createManager(ctx context.Context, restConfig *rest.Config, managerConfig *
 		return nil, fmt.Errorf("failed to add transport-to-db syncers: %w", err)
 	}
 
+	if err := statuswriter.AddStatusWriters(mgr, managerConfig.SyncerConfig.StatusWriteInterval); err != nil {
+		return nil, fmt.Errorf("failed to add status writers: %w", err)
+	}
+
 	if err := cronjob.AddSchedulerToManager(ctx, mgr, processPostgreSQL.GetConn(),
-		managerConfig.SchedulerInterval, enableSimulation); err != nil {
+		managerConfig.SchedulerInterval, enableSimulation, managerConfig.ReportStorageConfig); err != nil {
 		return nil, fmt.Errorf("failed to add scheduler to manager: %w", err)
 	}
 
@@ -268,7 +307,10 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 	hookServer := mgr.GetWebhookServer()
 	setupLog.Info("registering webhooks to the webhook server")
 	hookServer.Register("/mutating", &webhook.Admission{
-		Handler: &mgrwebhook.AdmissionHandler{Client: mgr.GetClient()},
+		Handler: &mgrwebhook.AdmissionHandler{
+			Client:           mgr.GetClient(),
+			ManagerNamespace: managerConfig.ManagerNamespace,
+		},
 	})
 
 	setupLog.Info("Starting the Manager")
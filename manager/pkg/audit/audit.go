@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	// UnknownUser is the user of the changes whose requester isn't found.
+	UnknownUser = "unknown"
+	// SystemUser is the user of the changes made by the global hub itself, like the scheduling and the rollouts.
+	SystemUser = "system:multicluster-global-hub"
+
+	// specVersionFormat is the format of the bundle versions, so that the audit log can be matched with the bundles
+	specVersionFormat = "2006-01-02_15-04-05.000000"
+)
+
+const (
+	insertAuditLogQuery = `INSERT INTO history.audit_log (user_name, source, operation, kind, name, namespace,
+		resource_id, target_hubs, spec_version, details) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''),
+		$8::jsonb, NULLIF($9, ''), $10::jsonb)`
+	leafHubNamesQuery = `SELECT COALESCE(jsonb_agg(leaf_hub_name ORDER BY leaf_hub_name), '[]'::jsonb)
+		FROM status.leaf_hub_heartbeats`
+)
+
+// Record appends the change into the audit log within the transaction of the change, so that the change isn't
+// committed without its audit log.
+func Record(ctx context.Context, tx pgx.Tx, auditLog *models.AuditLog) error {
+	setDefaults(auditLog)
+	if _, err := tx.Exec(ctx, insertAuditLogQuery, auditLog.UserName, auditLog.Source, auditLog.Operation,
+		auditLog.Kind, auditLog.Name, auditLog.Namespace, auditLog.ResourceID, []byte(auditLog.TargetHubs),
+		auditLog.SpecVersion, []byte(auditLog.Details)); err != nil {
+		return fmt.Errorf("failed to record the audit log of %s %s - %w", auditLog.Operation, auditLog.Kind, err)
+	}
+	return nil
+}
+
+// RecordWithGorm appends the change into the audit log within the gorm transaction of the change.
+func RecordWithGorm(tx *gorm.DB, auditLog *models.AuditLog) error {
+	setDefaults(auditLog)
+	if err := tx.Create(auditLog).Error; err != nil {
+		return fmt.Errorf("failed to record the audit log of %s %s - %w", auditLog.Operation, auditLog.Kind, err)
+	}
+	return nil
+}
+
+func setDefaults(auditLog *models.AuditLog) {
+	if auditLog.UserName == "" {
+		auditLog.UserName = UnknownUser
+	}
+	if auditLog.TargetHubs == nil {
+		auditLog.TargetHubs = []byte("[]")
+	}
+}
+
+// Requester is the user requesting a change, and where the user is found. the field manager of the latest change is
+// kept if the user isn't found, it's the name of the client rather than the user.
+type Requester struct {
+	User         string
+	Source       string
+	FieldManager string
+}
+
+// NewSpecChange returns the audit log of the change of a global resource, it's recorded by the spec db within the
+// transaction of the change with RecordSpecChange.
+func NewSpecChange(operation string, requester Requester, instance client.Object) *models.AuditLog {
+	details := map[string]string{"userSource": requester.Source}
+	if requester.FieldManager != "" {
+		details["fieldManager"] = requester.FieldManager
+	}
+	detailsBytes, _ := json.Marshal(details)
+
+	return &models.AuditLog{
+		UserName:   requester.User,
+		Source:     database.AuditSourceController,
+		Operation:  operation,
+		Kind:       instance.GetObjectKind().GroupVersionKind().Kind,
+		Name:       instance.GetName(),
+		Namespace:  instance.GetNamespace(),
+		ResourceID: string(instance.GetUID()),
+		Details:    detailsBytes,
+	}
+}
+
+// RecordSpecChange appends the change of a global resource in the spec table into the audit log within the
+// transaction of the change. the resource is sent to all the leaf hubs, except the resources rolled out in waves,
+// whose leaf hubs are recorded as the rollout reaches them, and the spec version is the version of the bundle
+// carrying the change.
+func RecordSpecChange(ctx context.Context, tx pgx.Tx, tableName string, rolledOut bool, updatedAt time.Time,
+	auditLog *models.AuditLog,
+) error {
+	auditLog.TargetHubs = []byte("[]")
+	if !rolledOut {
+		if err := tx.QueryRow(ctx, leafHubNamesQuery).Scan(&auditLog.TargetHubs); err != nil {
+			return fmt.Errorf("failed to list the leaf hubs - %w", err)
+		}
+	}
+	auditLog.SpecVersion = FormatSpecVersion(updatedAt)
+
+	details := map[string]interface{}{}
+	if len(auditLog.Details) > 0 {
+		if err := json.Unmarshal(auditLog.Details, &details); err != nil {
+			return err
+		}
+	}
+	details["table"] = tableName
+	if rolledOut {
+		details["rolledOut"] = true
+	}
+	detailsBytes, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	auditLog.Details = detailsBytes
+
+	return Record(ctx, tx, auditLog)
+}
+
+// FormatSpecVersion returns the version of the bundles carrying the change made at the given time.
+func FormatSpecVersion(updatedAt time.Time) string {
+	return updatedAt.Format(specVersionFormat)
+}
+
+// RequesterOf returns the user requesting the change of the object. the kubernetes objects don't record their users,
+// so the user is taken from the requested-by annotation, which is set by the admission webhook of the global hub.
+// the field manager of the latest change in the managed fields is kept otherwise, the changes of only the finalizers
+// or the status are skipped since they're made by the controllers.
+func RequesterOf(obj metav1.Object) Requester {
+	if user := obj.GetAnnotations()[constants.AuditRequestedByAnnotation]; user != "" {
+		return Requester{User: user, Source: "annotation"}
+	}
+
+	var latest *metav1.ManagedFieldsEntry
+	managedFields := obj.GetManagedFields()
+	for i := range managedFields {
+		entry := &managedFields[i]
+		if entry.Subresource != "" || entry.Manager == "" || ownsOnlyFinalizers(entry.FieldsV1) {
+			continue
+		}
+		if latest == nil || (entry.Time != nil && (latest.Time == nil || latest.Time.Before(entry.Time))) {
+			latest = entry
+		}
+	}
+	if latest != nil {
+		return Requester{User: UnknownUser, Source: "managedFields", FieldManager: latest.Manager}
+	}
+	return Requester{User: UnknownUser}
+}
+
+// DeleterOf returns the user deleting the object, which is taken from the deleted-by annotation set by the admission
+// webhook of the global hub when the deletion is requested, the last user changing the object isn't the deleter.
+func DeleterOf(obj metav1.Object) Requester {
+	if user := obj.GetAnnotations()[constants.AuditDeletedByAnnotation]; user != "" {
+		return Requester{User: user, Source: "annotation"}
+	}
+	return Requester{User: UnknownUser}
+}
+
+func ownsOnlyFinalizers(fields *metav1.FieldsV1) bool {
+	if fields == nil {
+		return false
+	}
+	ownedFields := map[string]map[string]interface{}{}
+	if err := json.Unmarshal(fields.Raw, &ownedFields); err != nil {
+		return false
+	}
+	if len(ownedFields) != 1 || len(ownedFields["f:metadata"]) != 1 {
+		return false
+	}
+	_, found := ownedFields["f:metadata"]["f:finalizers"]
+	return found
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestRequesterOf(t *testing.T) {
+	created := metav1.NewTime(time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC))
+	updated := metav1.NewTime(created.Add(time.Minute))
+	finalized := metav1.NewTime(created.Add(2 * time.Minute))
+	managedFields := []metav1.ManagedFieldsEntry{
+		{
+			Manager: "kubectl-create", Operation: metav1.ManagedFieldsOperationUpdate, Time: &created,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:foo":{}}}`)},
+		},
+		{
+			Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &updated,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:foo":{}}}`)},
+		},
+		{
+			Manager: "multicluster-global-hub-manager", Operation: metav1.ManagedFieldsOperationUpdate,
+			Time:     &finalized,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:finalizers":{".":{}}}}`)},
+		},
+		{
+			Manager: "status-controller", Operation: metav1.ManagedFieldsOperationUpdate, Time: &finalized,
+			Subresource: "status", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		obj      *corev1.ConfigMap
+		expected Requester
+	}{
+		{
+			name: "requested-by annotation",
+			obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Annotations:   map[string]string{constants.AuditRequestedByAnnotation: "admin"},
+				ManagedFields: managedFields,
+			}},
+			expected: Requester{User: "admin", Source: "annotation"},
+		},
+		{
+			name:     "latest field manager",
+			obj:      &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ManagedFields: managedFields}},
+			expected: Requester{User: UnknownUser, Source: "managedFields", FieldManager: "kubectl-edit"},
+		},
+		{
+			name:     "unknown",
+			obj:      &corev1.ConfigMap{},
+			expected: Requester{User: UnknownUser},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, RequesterOf(c.obj))
+		})
+	}
+}
+
+func TestDeleterOf(t *testing.T) {
+	deleted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		constants.AuditRequestedByAnnotation: "creator",
+		constants.AuditDeletedByAnnotation:   "deleter",
+	}}}
+	assert.Equal(t, Requester{User: "deleter", Source: "annotation"}, DeleterOf(deleted))
+
+	created := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		constants.AuditRequestedByAnnotation: "creator",
+	}}}
+	assert.Equal(t, Requester{User: UnknownUser}, DeleterOf(created))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -18,17 +19,35 @@ type ManagerConfig struct {
 	SchedulerInterval     string
 	EventExporterTopic    string
 	SyncerConfig          *SyncerConfig
+	HubManagementConfig   *HubManagementConfig
 	DatabaseConfig        *DatabaseConfig
 	TransportConfig       *transport.TransportConfig
 	StatisticsConfig      *statistics.StatisticsConfig
 	NonK8sAPIServerConfig *nonk8sapi.NonK8sAPIServerConfig
 	ElectionConfig        *commonobjects.LeaderElectionConfig
+	ReportStorageConfig   *report.StorageConfig
 }
 
 type SyncerConfig struct {
 	SpecSyncInterval              time.Duration
 	StatusSyncInterval            time.Duration
+	StatusWriteInterval           time.Duration
 	DeletedLabelsTrimmingInterval time.Duration
+	// SpecFullResyncInterval is the interval of sending a complete bundle after delta bundles were sent
+	SpecFullResyncInterval time.Duration
+	// GlobalSchedulingInterval is the interval of scheduling the global placements across the leaf hubs, the global
+	// scheduler is disabled if it's zero
+	GlobalSchedulingInterval time.Duration
+	// RolloutInterval is the interval of moving the rollouts of the global resources through their waves
+	RolloutInterval time.Duration
+}
+
+// HubManagementConfig configures the cleanup of the data of the offboarded leaf hubs.
+type HubManagementConfig struct {
+	// OffboardingRetention is "archive" or "delete", the rows of the offboarded leaf hubs are archived or deleted
+	OffboardingRetention string
+	// OffboardingInterval is the interval of cleaning up the data of the offboarding leaf hubs
+	OffboardingInterval time.Duration
 }
 
 type DatabaseConfig struct {
@@ -42,12 +42,12 @@ func TestScheduler(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "month", false))
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "week", false))
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "day", false))
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "hour", false))
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "minute", false))
-	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "second", false))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "month", false, nil))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "week", false, nil))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "day", false, nil))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "hour", false, nil))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "minute", false, nil))
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, pool, "second", false, nil))
 
 	cancel()
 	err = testenv.Stop()
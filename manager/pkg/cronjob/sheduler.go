@@ -10,6 +10,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
 )
 
 const (
@@ -19,6 +20,9 @@ const (
 	EveryHour   string = "hour"
 	EveryMinute string = "minute"
 	EverySecond string = "second"
+
+	// complianceReportTime is the time of the day to write the scheduled compliance reports to the object storage
+	complianceReportTime = "01:00"
 )
 
 type GlobalHubJobScheduler struct {
@@ -27,7 +31,7 @@ type GlobalHubJobScheduler struct {
 }
 
 func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager, pool *pgxpool.Pool, interval string,
-	enableSimulation bool,
+	enableSimulation bool, reportStorage *report.StorageConfig,
 ) error {
 	log := ctrl.Log.WithName("cronjob-scheduler")
 	// Scheduler timezone:
@@ -56,6 +60,29 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager, pool *pgxpool.
 	}
 	log.Info("set local compliance job", "scheduleAt", complianceJob.ScheduledAtTime())
 
+	// the compliance of the global policies is recorded every hour, so the last compliance of a day is recorded
+	globalComplianceJob, err := scheduler.Every(1).Hour().Tag("GlobalComplianceHistory").DoWithJobDetails(
+		task.SyncGlobalCompliance, ctx, pool)
+	if err != nil {
+		return err
+	}
+	log.Info("set global compliance job", "scheduleAt", globalComplianceJob.ScheduledAtTime())
+
+	// the scheduled compliance reports are written to the object storage only if it's configured
+	if reportStorage.Enabled() {
+		uploader, err := report.NewUploader(reportStorage)
+		if err != nil {
+			return err
+		}
+		reportJob, err := scheduler.Every(1).Day().At(complianceReportTime).Tag("ComplianceReport").
+			DoWithJobDetails(task.ExportComplianceReports, ctx, pool, uploader)
+		if err != nil {
+			return err
+		}
+		log.Info("set compliance report job", "scheduleAt", reportJob.ScheduledAtTime(),
+			"bucket", reportStorage.Bucket)
+	}
+
 	return mgr.Add(&GlobalHubJobScheduler{
 		log:       log,
 		scheduler: scheduler,
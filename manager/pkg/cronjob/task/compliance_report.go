@@ -0,0 +1,64 @@
+package task
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/jackc/pgx/v4/pgxpool"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+var complianceReportTaskName = "compliance-report"
+
+// ExportComplianceReports writes the reports of the scheduled definitions due today to the object storage, each
+// report covers the days of its definition up to today.
+func ExportComplianceReports(ctx context.Context, pool *pgxpool.Pool, uploader report.Uploader, job gocron.Job) {
+	reportLog := ctrl.Log.WithName(complianceReportTaskName)
+	now := time.Now()
+	reportLog.Info("start running", "currentRun", job.LastRun().Format(timeFormat))
+
+	definitions, err := report.ListDefinitions(ctx, pool, true)
+	if err != nil {
+		reportLog.Error(err, "failed to list the scheduled compliance reports")
+		return
+	}
+
+	for i := range definitions {
+		definition := &definitions[i].Definition
+		if !definition.IsDue(now) {
+			continue
+		}
+		since, until := definition.DateRange(now)
+		if err := exportComplianceReport(ctx, pool, uploader, definition, since, until); err != nil {
+			reportLog.Error(err, "failed to export the compliance report", "name", definition.Name)
+			continue
+		}
+		if err := report.MarkExported(ctx, pool, definition.Name, now); err != nil {
+			reportLog.Error(err, "failed to mark the compliance report as exported", "name", definition.Name)
+		}
+		reportLog.Info("exported the compliance report", "name", definition.Name,
+			"since", since.Format(dateFormat), "until", until.Format(dateFormat))
+	}
+
+	reportLog.Info("finish running", "nextRun", job.NextRun().Format(timeFormat))
+}
+
+// exportComplianceReport streams the report from the database to the object storage through a pipe, so that the
+// report isn't loaded into the memory at once.
+func exportComplianceReport(ctx context.Context, pool *pgxpool.Pool, uploader report.Uploader,
+	definition *report.Definition, since, until time.Time,
+) error {
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(report.Export(ctx, pool, definition, since, until, writer))
+	}()
+
+	err := uploader.Upload(ctx, definition, definition.FileName(since, until), reader)
+	// unblock the export if the upload is stopped before the whole report is read
+	_ = reader.CloseWithError(err)
+	return err
+}
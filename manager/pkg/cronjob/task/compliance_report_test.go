@@ -0,0 +1,220 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+// fakeUploader keeps the uploaded reports in the memory
+type fakeUploader struct {
+	mutex   sync.Mutex
+	objects map[string]string
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, definition *report.Definition, fileName string,
+	body io.Reader,
+) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.objects[report.ObjectKey("reports", definition, fileName)] = string(content)
+	return nil
+}
+
+func (u *fakeUploader) get(key string) (string, bool) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	content, found := u.objects[key]
+	return content, found
+}
+
+var _ = Describe("export the scheduled compliance reports", Ordered, func() {
+	BeforeAll(func() {
+		By("Creating the tables of the compliance reports in the database")
+		_, err := pool.Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS local_spec;
+			CREATE SCHEMA IF NOT EXISTS local_status;
+			CREATE SCHEMA IF NOT EXISTS history;
+			CREATE SCHEMA IF NOT EXISTS spec;
+			CREATE SCHEMA IF NOT EXISTS report;
+			CREATE SCHEMA IF NOT EXISTS status;
+			DO $$ BEGIN
+				CREATE TYPE local_status.compliance_type AS ENUM ('compliant', 'non_compliant', 'unknown');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			DO $$ BEGIN
+				CREATE TYPE status.compliance_type AS ENUM ('compliant', 'non_compliant', 'unknown');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			DO $$ BEGIN
+				CREATE TYPE status.error_type AS ENUM ('disconnected', 'none');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			CREATE TABLE IF NOT EXISTS history.local_compliance (
+				policy_id uuid NOT NULL,
+				cluster_id uuid,
+				leaf_hub_name character varying(63) NOT NULL,
+				compliance_date DATE DEFAULT (CURRENT_DATE - INTERVAL '1 day') NOT NULL,
+				compliance local_status.compliance_type NOT NULL,
+				compliance_changed_frequency integer NOT NULL DEFAULT 0,
+				CONSTRAINT local_policies_unique_constraint UNIQUE (policy_id, cluster_id, compliance_date)
+			);
+			CREATE TABLE IF NOT EXISTS history.compliance (
+				policy_id uuid NOT NULL,
+				cluster_id uuid,
+				cluster_name character varying(63) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				compliance_date DATE DEFAULT CURRENT_DATE NOT NULL,
+				compliance status.compliance_type NOT NULL,
+				CONSTRAINT compliance_unique_constraint UNIQUE (policy_id, leaf_hub_name, cluster_name, compliance_date)
+			);
+			CREATE TABLE IF NOT EXISTS local_spec.policies (
+				leaf_hub_name character varying(63) NOT NULL,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted_at timestamp without time zone,
+				policy_id uuid PRIMARY KEY generated always as (uuid(payload->'metadata'->>'uid')) stored,
+				policy_name character varying(255) generated always as (payload -> 'metadata' ->> 'name') stored,
+				policy_standard character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/standards') stored,
+				policy_category character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/categories') stored,
+				policy_control character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/controls') stored
+			);
+			CREATE TABLE IF NOT EXISTS spec.policies (
+				id uuid PRIMARY KEY,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted boolean DEFAULT false NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS report.compliance_reports (
+				name character varying(63) NOT NULL PRIMARY KEY,
+				definition jsonb NOT NULL,
+				created_by character varying(253),
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				last_exported_at timestamp without time zone
+			);
+			CREATE TABLE IF NOT EXISTS status.compliance (
+				policy_id uuid NOT NULL,
+				cluster_name character varying(63) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				error status.error_type NOT NULL,
+				compliance status.compliance_type NOT NULL,
+				cluster_id uuid
+			);
+			CREATE TABLE IF NOT EXISTS status.managed_clusters (
+				leaf_hub_name character varying(63) NOT NULL,
+				cluster_name character varying(63) generated always as (payload -> 'metadata' ->> 'name') stored,
+				cluster_id uuid NOT NULL,
+				payload jsonb NOT NULL,
+				error status.error_type NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted_at timestamp without time zone
+			);`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the compliance of the production and the development clusters")
+		_, err = pool.Exec(ctx, `
+			INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+				('hub1', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a01',
+					'{"metadata": {"name": "prod1", "labels": {"environment": "production"}}}', 'none'),
+				('hub1', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a02',
+					'{"metadata": {"name": "dev1", "labels": {"environment": "development"}}}', 'none');
+			INSERT INTO local_spec.policies (leaf_hub_name, payload) VALUES
+				('hub1', '{"metadata": {"uid": "7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c01", "name": "policy-access",
+					"annotations": {"policy.open-cluster-management.io/standards": "NIST SP 800-53",
+					"policy.open-cluster-management.io/controls": "AC-3, CM-2"}}}'),
+				('hub1', '{"metadata": {"uid": "7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c02", "name": "policy-audit",
+					"annotations": {"policy.open-cluster-management.io/controls": "AU-2"}}}');
+			INSERT INTO history.local_compliance (policy_id, cluster_id, leaf_hub_name, compliance_date,
+				compliance) VALUES
+				('7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c01', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a01', 'hub1',
+					CURRENT_DATE - 2, 'non_compliant'),
+				('7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c01', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a01', 'hub1',
+					CURRENT_DATE - 1, 'compliant'),
+				('7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c01', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a02', 'hub1',
+					CURRENT_DATE - 1, 'non_compliant'),
+				('7d4c2b1a-5e6f-4a3b-8c9d-0e1f2a3b4c02', '3f5b1c2e-6d1a-4a8e-9c3b-2a1d4e5f6a01', 'hub1',
+					CURRENT_DATE - 1, 'non_compliant');`)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("write the report of the production clusters against the control to the object storage", func() {
+		definition := &report.Definition{
+			Name:          "production-ac-3",
+			Controls:      []string{"AC-3"},
+			LabelSelector: "environment=production",
+			Days:          7,
+			Format:        report.FormatCSV,
+			View:          report.ViewDetail,
+			Schedule:      report.ScheduleDaily,
+		}
+		Expect(report.SaveDefinition(ctx, pool, definition, "auditor")).To(Succeed())
+
+		By("Run the compliance report job")
+		uploader := &fakeUploader{objects: map[string]string{}}
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Day().Tag("ComplianceReport").DoWithJobDetails(
+			task.ExportComplianceReports, ctx, pool, uploader)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		since, until := definition.DateRange(time.Now())
+		key := report.ObjectKey("reports", definition, definition.FileName(since, until))
+		Eventually(func() error {
+			content, found := uploader.get(key)
+			if !found {
+				return fmt.Errorf("the report %s is not uploaded", key)
+			}
+			lines := strings.Split(strings.TrimSpace(content), "\n")
+			// the header and the compliance of the policy-access on the prod1 in the last two days
+			if len(lines) != 3 {
+				return fmt.Errorf("unexpected report %s", content)
+			}
+			if !strings.HasSuffix(lines[1], "non_compliant") || !strings.HasSuffix(lines[2], ",compliant") {
+				return fmt.Errorf("unexpected report %s", content)
+			}
+			for _, line := range lines[1:] {
+				if !strings.Contains(line, "prod1") || !strings.Contains(line, "policy-access") {
+					return fmt.Errorf("unexpected report line %s", line)
+				}
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+
+		By("Check the export time is recorded")
+		Eventually(func() error {
+			storedDefinition, err := report.GetDefinition(ctx, pool, definition.Name)
+			if err != nil {
+				return err
+			}
+			if storedDefinition.LastExportedAt == nil {
+				return fmt.Errorf("the export time of the report %s is not recorded", definition.Name)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+})
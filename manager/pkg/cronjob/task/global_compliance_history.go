@@ -0,0 +1,46 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/jackc/pgx/v4/pgxpool"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var globalComplianceTaskName = "global-compliance-history"
+
+// the compliance of the global policies in a day is the last compliance of the day, it's updated by the runs of the
+// day and kept after the day
+const upsertGlobalComplianceSQL = `
+	INSERT INTO history.compliance (policy_id, cluster_id, cluster_name, leaf_hub_name, compliance_date, compliance)
+		SELECT c.policy_id, c.cluster_id, c.cluster_name, c.leaf_hub_name, CURRENT_DATE, c.compliance
+		FROM status.compliance c
+		JOIN spec.policies p ON p.id = c.policy_id AND NOT p.deleted
+	ON CONFLICT (policy_id, leaf_hub_name, cluster_name, compliance_date)
+	DO UPDATE SET
+		cluster_id = EXCLUDED.cluster_id,
+		compliance = EXCLUDED.compliance
+`
+
+// SyncGlobalCompliance records the current compliance of the global policies as the compliance of today in the
+// history.compliance, so that the compliance reports cover the global policies in the past days.
+func SyncGlobalCompliance(ctx context.Context, pool *pgxpool.Pool, job gocron.Job) {
+	start := time.Now()
+	taskLog := ctrl.Log.WithName(globalComplianceTaskName).WithValues("date", start.Format(dateFormat))
+	taskLog.Info("start running", "currentRun", job.LastRun().Format(timeFormat))
+
+	result, err := pool.Exec(ctx, upsertGlobalComplianceSQL)
+	if e := traceComplianceHistory(ctx, pool, globalComplianceTaskName, result.RowsAffected(), 0,
+		result.RowsAffected(), start, err); e != nil {
+		taskLog.Info("trace compliance job failed", "error", e)
+	}
+	if err != nil {
+		taskLog.Error(err, "sync from status.compliance to history.compliance failed")
+		return
+	}
+
+	taskLog.Info("finish running", "upsertedCount", result.RowsAffected(), "nextRun",
+		job.NextRun().Format(timeFormat))
+}
@@ -0,0 +1,158 @@
+package task_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+var _ = Describe("sync the compliance of the global policies to the history", Ordered, func() {
+	BeforeAll(func() {
+		By("Creating the tables of the global compliance in the database")
+		_, err := pool.Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS local_spec;
+			CREATE SCHEMA IF NOT EXISTS local_status;
+			CREATE SCHEMA IF NOT EXISTS history;
+			CREATE SCHEMA IF NOT EXISTS spec;
+			CREATE SCHEMA IF NOT EXISTS status;
+			DO $$ BEGIN
+				CREATE TYPE local_status.compliance_type AS ENUM ('compliant', 'non_compliant', 'unknown');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			DO $$ BEGIN
+				CREATE TYPE status.compliance_type AS ENUM ('compliant', 'non_compliant', 'unknown');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			DO $$ BEGIN
+				CREATE TYPE status.error_type AS ENUM ('disconnected', 'none');
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			CREATE TABLE IF NOT EXISTS history.local_compliance (
+				policy_id uuid NOT NULL,
+				cluster_id uuid,
+				leaf_hub_name character varying(63) NOT NULL,
+				compliance_date DATE DEFAULT (CURRENT_DATE - INTERVAL '1 day') NOT NULL,
+				compliance local_status.compliance_type NOT NULL,
+				compliance_changed_frequency integer NOT NULL DEFAULT 0,
+				CONSTRAINT local_policies_unique_constraint UNIQUE (policy_id, cluster_id, compliance_date)
+			);
+			CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
+				name varchar(63) NOT NULL,
+				start_at timestamp NOT NULL DEFAULT now(),
+				end_at timestamp NOT NULL DEFAULT now(),
+				total int8,
+				inserted int8,
+				offsets int8,
+				error TEXT
+			);
+			CREATE TABLE IF NOT EXISTS history.compliance (
+				policy_id uuid NOT NULL,
+				cluster_id uuid,
+				cluster_name character varying(63) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				compliance_date DATE DEFAULT CURRENT_DATE NOT NULL,
+				compliance status.compliance_type NOT NULL,
+				CONSTRAINT compliance_unique_constraint UNIQUE (policy_id, leaf_hub_name, cluster_name, compliance_date)
+			);
+			CREATE TABLE IF NOT EXISTS local_spec.policies (
+				leaf_hub_name character varying(63) NOT NULL,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted_at timestamp without time zone,
+				policy_id uuid PRIMARY KEY generated always as (uuid(payload->'metadata'->>'uid')) stored,
+				policy_name character varying(255) generated always as (payload -> 'metadata' ->> 'name') stored,
+				policy_standard character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/standards') stored,
+				policy_category character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/categories') stored,
+				policy_control character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/controls') stored
+			);
+			CREATE TABLE IF NOT EXISTS spec.policies (
+				id uuid PRIMARY KEY,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted boolean DEFAULT false NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS status.compliance (
+				policy_id uuid NOT NULL,
+				cluster_name character varying(63) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				error status.error_type NOT NULL,
+				compliance status.compliance_type NOT NULL,
+				cluster_id uuid
+			);
+			CREATE TABLE IF NOT EXISTS status.managed_clusters (
+				leaf_hub_name character varying(63) NOT NULL,
+				cluster_name character varying(63) generated always as (payload -> 'metadata' ->> 'name') stored,
+				cluster_id uuid NOT NULL,
+				payload jsonb NOT NULL,
+				error status.error_type NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted_at timestamp without time zone
+			);`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the compliance of the global policy on the staging cluster")
+		_, err = pool.Exec(ctx, `
+			INSERT INTO spec.policies (id, payload) VALUES
+				('9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c01', '{"metadata": {"name": "policy-global-config",
+					"annotations": {"policy.open-cluster-management.io/controls": "CM-6"}}}');
+			INSERT INTO status.compliance (policy_id, cluster_name, leaf_hub_name, error, compliance) VALUES
+				('9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c01', 'stage1', 'hub2', 'none', 'non_compliant');
+			INSERT INTO history.compliance (policy_id, cluster_name, leaf_hub_name, compliance_date,
+				compliance) VALUES
+				('9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c01', 'stage1', 'hub2', CURRENT_DATE - 1, 'compliant');`)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("record the current compliance of the global policies as the compliance of today", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Hour().Tag("GlobalComplianceHistory").DoWithJobDetails(
+			task.SyncGlobalCompliance, ctx, pool)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() error {
+			var compliance string
+			if err := pool.QueryRow(ctx, `SELECT compliance::text FROM history.compliance
+				WHERE cluster_name = 'stage1' AND compliance_date = CURRENT_DATE`).Scan(&compliance); err != nil {
+				return err
+			}
+			if compliance != "non_compliant" {
+				return fmt.Errorf("unexpected compliance %s of today", compliance)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("report the compliance of the global policies in the past days", func() {
+		definition := &report.Definition{Controls: []string{"CM-6"}, Days: 2}
+		definition.Default()
+		since, until := definition.DateRange(time.Now())
+
+		buffer := &bytes.Buffer{}
+		Expect(report.Export(ctx, pool, definition, since, until, buffer)).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		// the header, the compliance of yesterday from the history and the current compliance of today
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[1]).To(ContainSubstring("policy-global-config"))
+		Expect(lines[1]).To(HaveSuffix(",compliant"))
+		Expect(lines[2]).To(HaveSuffix("non_compliant"))
+	})
+})
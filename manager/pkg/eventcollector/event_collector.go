@@ -8,10 +8,16 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/go-logr/logr"
 	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	applicationv1beta1 "sigs.k8s.io/application/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	eventprocessor "github.com/stolostron/multicluster-global-hub/manager/pkg/eventcollector/processor"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
@@ -30,8 +36,22 @@ func AddEventCollector(ctx context.Context, mgr ctrl.Manager, kafkaConfig *trans
 	eventDispatcher := newEventDispatcher(eventConsumer.MessageChan())
 
 	// register event processors with the event dispatcher
-	eventDispatcher.RegisterProcessor(policyv1.Kind,
+	eventDispatcher.RegisterProcessor(policyv1.SchemeGroupVersion.WithKind(policyv1.Kind),
 		eventprocessor.NewPolicyProcessor(ctx, eventConsumer))
+	eventDispatcher.RegisterProcessor(clusterv1.GroupVersion.WithKind(constants.ManagedClusterKind),
+		eventprocessor.NewManagedClusterProcessor(ctx, eventConsumer))
+
+	placementProcessor := eventprocessor.NewPlacementProcessor(ctx, eventConsumer)
+	eventDispatcher.RegisterProcessor(clusterv1beta1.GroupVersion.WithKind(constants.PlacementKind),
+		placementProcessor)
+	eventDispatcher.RegisterProcessor(clusterv1beta1.GroupVersion.WithKind(constants.PlacementDecisionKind),
+		placementProcessor)
+
+	applicationProcessor := eventprocessor.NewApplicationProcessor(ctx, eventConsumer)
+	eventDispatcher.RegisterProcessor(appsubv1.SchemeGroupVersion.WithKind(constants.SubscriptionKind),
+		applicationProcessor)
+	eventDispatcher.RegisterProcessor(applicationv1beta1.GroupVersion.WithKind(constants.ApplicationKind),
+		applicationProcessor)
 
 	// add the event dispatcher to manager
 	if err := mgr.Add(eventDispatcher); err != nil {
@@ -44,14 +64,14 @@ func AddEventCollector(ctx context.Context, mgr ctrl.Manager, kafkaConfig *trans
 type eventDispatcher struct {
 	log         logr.Logger
 	messageChan <-chan *sarama.ConsumerMessage
-	processors  map[string]eventprocessor.EventProcessor
+	processors  map[schema.GroupVersionKind]eventprocessor.EventProcessor
 }
 
 func newEventDispatcher(messageChan <-chan *sarama.ConsumerMessage) *eventDispatcher {
 	return &eventDispatcher{
 		log:         ctrl.Log.WithName("event-dispatcher"),
 		messageChan: messageChan,
-		processors:  make(map[string]eventprocessor.EventProcessor),
+		processors:  make(map[schema.GroupVersionKind]eventprocessor.EventProcessor),
 	}
 }
 
@@ -69,10 +89,11 @@ func (e *eventDispatcher) Start(ctx context.Context) error {
 				e.log.Error(err, "failed to unmarshal message to EnhancedEvent", "message", message)
 				continue
 			}
-			processor, ok := e.processors[event.InvolvedObject.Kind]
+			processor, ok := e.processors[schema.FromAPIVersionAndKind(event.InvolvedObject.APIVersion,
+				event.InvolvedObject.Kind)]
 			if !ok {
-				e.log.Info("no event processor registered for object kind",
-					"objectKind", event.InvolvedObject.Kind)
+				e.log.Info("no event processor registered for object kind", "objectAPIVersion",
+					event.InvolvedObject.APIVersion, "objectKind", event.InvolvedObject.Kind)
 				continue
 			}
 			processor.Process(event, &eventprocessor.EventOffset{
@@ -84,6 +105,6 @@ func (e *eventDispatcher) Start(ctx context.Context) error {
 	}
 }
 
-func (e *eventDispatcher) RegisterProcessor(objectKind string, processor eventprocessor.EventProcessor) {
-	e.processors[objectKind] = processor
+func (e *eventDispatcher) RegisterProcessor(gvk schema.GroupVersionKind, processor eventprocessor.EventProcessor) {
+	e.processors[gvk] = processor
 }
@@ -121,7 +121,7 @@ func TestEventCollector(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	messageChan := make(chan *sarama.ConsumerMessage)
 	eventDispatcher := newEventDispatcher(messageChan)
-	eventDispatcher.RegisterProcessor(policyv1.Kind,
+	eventDispatcher.RegisterProcessor(policyv1.SchemeGroupVersion.WithKind(policyv1.Kind),
 		eventprocessor.NewPolicyProcessor(ctx, &offsetManagerMock{}))
 	go func() {
 		_ = eventDispatcher.Start(ctx)
@@ -161,9 +161,10 @@ func getEvent() *kube.EnhancedEvent {
 		},
 		InvolvedObject: kube.EnhancedObjectReference{
 			ObjectReference: corev1.ObjectReference{
-				Kind:      string(policyv1.Kind),
-				Name:      "managed-cluster-policy",
-				Namespace: "cluster1",
+				APIVersion: policyv1.SchemeGroupVersion.String(),
+				Kind:       string(policyv1.Kind),
+				Name:       "managed-cluster-policy",
+				Namespace:  "cluster1",
 			},
 			Labels: map[string]string{
 				constants.PolicyEventRootPolicyIdLabelKey: "37c9a640-af05-4bea-9dcc-1873e86bebcd",
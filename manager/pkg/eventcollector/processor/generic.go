@@ -1,6 +1,15 @@
 package processor
 
-import "github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
 
 type EventOffset struct {
 	Topic     string
@@ -15,3 +24,23 @@ type EventProcessor interface {
 type OffsetManager interface {
 	MarkOffset(topic string, partition int32, offset int64)
 }
+
+// upsertEvent inserts the event into the database or updates it if the conflict columns already exist,
+// retrying until the context is timeout.
+func upsertEvent(ctx context.Context, log logr.Logger, db *gorm.DB, conflictColumns []clause.Column,
+	insertEvent interface{},
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+	return wait.PollUntilWithContext(ctx, 10*time.Second, func(ctx context.Context) (bool, error) {
+		result := db.Clauses(clause.OnConflict{
+			Columns:   conflictColumns,
+			UpdateAll: true,
+		}).Create(insertEvent)
+		if result.Error != nil {
+			log.Error(result.Error, "insert or update event failed, retrying...")
+			return false, nil
+		}
+		return true, nil
+	})
+}
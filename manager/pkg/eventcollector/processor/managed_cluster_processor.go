@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type managedClusterProcessor struct {
+	log           logr.Logger
+	ctx           context.Context
+	db            *gorm.DB
+	offsetManager OffsetManager
+}
+
+func NewManagedClusterProcessor(ctx context.Context, offsetManager OffsetManager) *managedClusterProcessor {
+	return &managedClusterProcessor{
+		log:           ctrl.Log.WithName("managed-cluster-event-processor"),
+		ctx:           ctx,
+		db:            database.GetGorm(),
+		offsetManager: offsetManager,
+	}
+}
+
+func (p *managedClusterProcessor) Process(event *kube.EnhancedEvent, eventOffset *EventOffset) {
+	p.log.Info(event.ClusterName, "name", event.Name, "count", fmt.Sprintf("%d", event.Count),
+		"offset", fmt.Sprintf("%d", eventOffset.Offset))
+
+	source, err := json.Marshal(event.Source)
+	if err != nil {
+		p.log.Error(err, "failed to marshal event")
+		return
+	}
+	managedClusterEvent := &models.ManagedClusterEvent{
+		EventName:   event.Name,
+		ClusterName: event.InvolvedObject.Name,
+		LeafHubName: event.ClusterName,
+		Message:     event.Message,
+		Reason:      event.Reason,
+		Count:       int(event.Count),
+		Source:      source,
+		CreatedAt:   event.LastTimestamp.Time,
+	}
+	if clusterId, ok := event.InvolvedObject.Labels[constants.ManagedClusterEventClusterIdLabelKey]; ok {
+		managedClusterEvent.ClusterID = &clusterId
+	}
+
+	conflictColumns := []clause.Column{{Name: "leaf_hub_name"}, {Name: "event_name"}, {Name: "count"}}
+	if event.Reason == constants.ManagedClusterMetadataConflictReason {
+		err = p.insertMetadataConflictEvent(event, managedClusterEvent, conflictColumns)
+	} else {
+		err = upsertEvent(p.ctx, p.log, p.db, conflictColumns, managedClusterEvent)
+	}
+	if err != nil {
+		p.log.Error(err, "insert or update managed cluster event failed")
+		return
+	}
+	p.offsetManager.MarkOffset(eventOffset.Topic, eventOffset.Partition, eventOffset.Offset)
+}
+
+// insertMetadataConflictEvent inserts the event of the values set on the regional hub that are overwritten by the
+// global hub, and records the overwritten values in the audit log within the same transaction. the redelivered event
+// isn't recorded again.
+func (p *managedClusterProcessor) insertMetadataConflictEvent(event *kube.EnhancedEvent,
+	managedClusterEvent *models.ManagedClusterEvent, conflictColumns []clause.Column,
+) error {
+	auditLog, err := newMetadataConflictAuditLog(event, managedClusterEvent)
+	if err != nil {
+		return err
+	}
+	return p.db.WithContext(p.ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{Columns: conflictColumns, DoNothing: true}).Create(managedClusterEvent)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return audit.RecordWithGorm(tx, auditLog)
+	})
+}
+
+// newMetadataConflictAuditLog returns the audit log of the values of the managed cluster overwritten by the global hub
+// agent, the values are taken from the annotation of the event, or else the message of the event.
+func newMetadataConflictAuditLog(event *kube.EnhancedEvent, managedClusterEvent *models.ManagedClusterEvent,
+) (*models.AuditLog, error) {
+	details := map[string]interface{}{"event": event.Name}
+	conflicts := []string{}
+	if err := json.Unmarshal([]byte(event.Annotations[constants.ManagedClusterMetadataConflictsAnnotation]),
+		&conflicts); err == nil {
+		details["conflicts"] = conflicts
+	} else {
+		details["message"] = event.Message
+	}
+	detailsBytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	targetHubs, err := json.Marshal([]string{managedClusterEvent.LeafHubName})
+	if err != nil {
+		return nil, err
+	}
+
+	auditLog := &models.AuditLog{
+		UserName:   audit.SystemUser,
+		Source:     database.AuditSourceAgent,
+		Operation:  database.AuditOperationOverwrite,
+		Kind:       constants.ManagedClusterKind,
+		Name:       managedClusterEvent.ClusterName,
+		TargetHubs: targetHubs,
+		Details:    detailsBytes,
+	}
+	if managedClusterEvent.ClusterID != nil {
+		auditLog.ResourceID = *managedClusterEvent.ClusterID
+	}
+	return auditLog, nil
+}
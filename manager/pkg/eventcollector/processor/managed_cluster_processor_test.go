@@ -0,0 +1,222 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var _ = Describe("managed cluster and placement events to database", func() {
+	const testSchema = "event"
+
+	BeforeEach(func() {
+		By("Creating test tables in the database")
+		_, err := pool.Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS event;
+			CREATE TABLE IF NOT EXISTS event.managed_clusters (
+				event_name character varying(253) NOT NULL,
+				cluster_id uuid,
+				cluster_name character varying(256) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				message text,
+				reason text,
+				count integer NOT NULL DEFAULT 0,
+				source jsonb,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				CONSTRAINT managed_clusters_unique_constraint UNIQUE (leaf_hub_name, event_name, count)
+			);
+			CREATE TABLE IF NOT EXISTS event.placements (
+				event_name character varying(253) NOT NULL,
+				resource_id uuid,
+				kind character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63),
+				leaf_hub_name character varying(63) NOT NULL,
+				message text,
+				reason text,
+				count integer NOT NULL DEFAULT 0,
+				source jsonb,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				CONSTRAINT placements_unique_constraint UNIQUE (leaf_hub_name, event_name, count)
+			);
+			CREATE SCHEMA IF NOT EXISTS history;
+			CREATE TABLE IF NOT EXISTS history.audit_log (
+				id bigserial PRIMARY KEY,
+				user_name character varying(253) NOT NULL,
+				source character varying(63) NOT NULL,
+				operation character varying(63) NOT NULL,
+				kind character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63),
+				resource_id character varying(63),
+				target_hubs jsonb DEFAULT '[]'::jsonb NOT NULL,
+				spec_version character varying(63),
+				details jsonb,
+				created_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check whether the tables are created")
+		Eventually(func() error {
+			var tables []PGTable
+			if err := g2.Table("pg_tables").Find(&tables).Error; err != nil {
+				return err
+			}
+
+			found := 0
+			for _, table := range tables {
+				if table.Schemaname == testSchema &&
+					(table.Tablename == "managed_clusters" || table.Tablename == "placements") {
+					found++
+				}
+			}
+			if found == 2 {
+				return nil
+			}
+			return fmt.Errorf("failed to create test tables in schema %s", testSchema)
+		}, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("sync the managed cluster events to database", func() {
+		By("Create a managed cluster processor")
+		managedClusterProcessor := NewManagedClusterProcessor(ctx, &offsetManagerMock{})
+
+		By("Process the event")
+		managedClusterProcessor.Process(&kube.EnhancedEvent{
+			Event: corev1.Event{
+				Message:       "The managed cluster cluster1 is unavailable",
+				ObjectMeta:    metav1.ObjectMeta{Name: "cluster1.17a2a8a5b9e3a1d0"},
+				Reason:        "AvailableUnknown",
+				LastTimestamp: metav1.NewTime(time.Now()),
+			},
+			ClusterName: "hub1",
+			InvolvedObject: kube.EnhancedObjectReference{
+				ObjectReference: corev1.ObjectReference{
+					Kind: constants.ManagedClusterKind,
+					Name: "cluster1",
+				},
+				Labels: map[string]string{
+					constants.ManagedClusterEventClusterIdLabelKey: "57c9a640-af05-4bea-9dcc-1873e86bebcd",
+				},
+			},
+		}, &EventOffset{Topic: "event", Offset: 1, Partition: 0})
+
+		By("Check whether the event is synced to the database")
+		Eventually(func() error {
+			var events []models.ManagedClusterEvent
+			if err := g2.Find(&events).Error; err != nil {
+				return err
+			}
+			for _, event := range events {
+				if event.ClusterName == "cluster1" && event.ClusterID != nil &&
+					*event.ClusterID == "57c9a640-af05-4bea-9dcc-1873e86bebcd" && event.LeafHubName == "hub1" {
+					return nil
+				}
+			}
+			return fmt.Errorf("not find managed cluster event in database")
+		}, 10*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("record the metadata conflict events in the audit log", func() {
+		By("Create a managed cluster processor")
+		managedClusterProcessor := NewManagedClusterProcessor(ctx, &offsetManagerMock{})
+
+		By("Process the event twice")
+		conflictEvent := &kube.EnhancedEvent{
+			Event: corev1.Event{
+				Message: "the values set on the regional hub are overwritten by the global hub: label env=dev",
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster2.17a2a8a5b9e3a1d1",
+					Annotations: map[string]string{
+						constants.ManagedClusterMetadataConflictsAnnotation: `["label env=dev"]`,
+					},
+				},
+				Reason:        constants.ManagedClusterMetadataConflictReason,
+				Count:         1,
+				LastTimestamp: metav1.NewTime(time.Now()),
+			},
+			ClusterName: "hub1",
+			InvolvedObject: kube.EnhancedObjectReference{
+				ObjectReference: corev1.ObjectReference{
+					Kind: constants.ManagedClusterKind,
+					Name: "cluster2",
+				},
+				Labels: map[string]string{
+					constants.ManagedClusterEventClusterIdLabelKey: "77c9a640-af05-4bea-9dcc-1873e86bebcd",
+				},
+			},
+		}
+		managedClusterProcessor.Process(conflictEvent, &EventOffset{Topic: "event", Offset: 3, Partition: 0})
+		managedClusterProcessor.Process(conflictEvent, &EventOffset{Topic: "event", Offset: 3, Partition: 0})
+
+		By("Check the overwritten values are recorded once in the audit log")
+		Eventually(func() error {
+			var auditLogs []models.AuditLog
+			if err := g2.Where("operation = ? AND name = ?", database.AuditOperationOverwrite, "cluster2").
+				Find(&auditLogs).Error; err != nil {
+				return err
+			}
+			if len(auditLogs) != 1 {
+				return fmt.Errorf("expected 1 audit log of cluster2, got %d", len(auditLogs))
+			}
+			if auditLogs[0].ResourceID != "77c9a640-af05-4bea-9dcc-1873e86bebcd" ||
+				string(auditLogs[0].TargetHubs) != `["hub1"]` ||
+				!strings.Contains(string(auditLogs[0].Details), "label env=dev") {
+				return fmt.Errorf("unexpected audit log of cluster2: %+v", auditLogs[0])
+			}
+			return nil
+		}, 10*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("sync the placement decision events to database", func() {
+		By("Create a placement processor")
+		placementProcessor := NewPlacementProcessor(ctx, &offsetManagerMock{})
+
+		By("Process the event")
+		placementProcessor.Process(&kube.EnhancedEvent{
+			Event: corev1.Event{
+				Message:       "Scheduled to 2 clusters",
+				ObjectMeta:    metav1.ObjectMeta{Name: "placement1-decision-1.17a2a8a5b9e3a1d0", Namespace: "default"},
+				Reason:        "DecisionUpdate",
+				LastTimestamp: metav1.NewTime(time.Now()),
+			},
+			ClusterName: "hub1",
+			InvolvedObject: kube.EnhancedObjectReference{
+				ObjectReference: corev1.ObjectReference{
+					Kind:      constants.PlacementDecisionKind,
+					Name:      "placement1-decision-1",
+					Namespace: "default",
+				},
+				Labels: map[string]string{
+					constants.EventGlobalResourceIdLabelKey: "67c9a640-af05-4bea-9dcc-1873e86bebcd",
+				},
+			},
+		}, &EventOffset{Topic: "event", Offset: 2, Partition: 0})
+
+		By("Check whether the event is synced to the database")
+		Eventually(func() error {
+			var events []models.PlacementEvent
+			if err := g2.Find(&events).Error; err != nil {
+				return err
+			}
+			for _, event := range events {
+				if event.Kind == constants.PlacementDecisionKind && event.ResourceID != nil &&
+					*event.ResourceID == "67c9a640-af05-4bea-9dcc-1873e86bebcd" {
+					return nil
+				}
+			}
+			return fmt.Errorf("not find placement event in database")
+		}, 10*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -4,13 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -79,19 +77,7 @@ func (p *policyProcessor) Process(event *kube.EnhancedEvent, eventOffset *EventO
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(p.ctx, 1*time.Minute)
-	defer cancel()
-	err = wait.PollUntilWithContext(ctx, 10*time.Second, func(ctx context.Context) (bool, error) {
-		result := p.db.Clauses(clause.OnConflict{
-			Columns:   conflictColumns,
-			UpdateAll: true,
-		}).Create(insertEvent)
-		if result.Error != nil {
-			p.log.Error(result.Error, "insert or update local (root) policy event failed, retrying...")
-			return false, nil
-		}
-		return true, nil
-	})
+	err = upsertEvent(p.ctx, p.log, p.db, conflictColumns, insertEvent)
 	if err != nil {
 		p.log.Error(err, "insert or update local (root) policy event failed")
 		return
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/resmoio/kubernetes-event-exporter/pkg/kube"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// resourceProcessor processes the events of the resources which might be propagated from the global hub,
+// e.g. the placement events and the application events.
+type resourceProcessor struct {
+	log             logr.Logger
+	ctx             context.Context
+	db              *gorm.DB
+	offsetManager   OffsetManager
+	createEventFunc func(baseEvent models.BaseResourceEvent) interface{}
+}
+
+// NewPlacementProcessor creates the processor of the Placement and PlacementDecision events.
+func NewPlacementProcessor(ctx context.Context, offsetManager OffsetManager) *resourceProcessor {
+	return &resourceProcessor{
+		log:           ctrl.Log.WithName("placement-event-processor"),
+		ctx:           ctx,
+		db:            database.GetGorm(),
+		offsetManager: offsetManager,
+		createEventFunc: func(baseEvent models.BaseResourceEvent) interface{} {
+			return &models.PlacementEvent{BaseResourceEvent: baseEvent}
+		},
+	}
+}
+
+// NewApplicationProcessor creates the processor of the Subscription and Application events.
+func NewApplicationProcessor(ctx context.Context, offsetManager OffsetManager) *resourceProcessor {
+	return &resourceProcessor{
+		log:           ctrl.Log.WithName("application-event-processor"),
+		ctx:           ctx,
+		db:            database.GetGorm(),
+		offsetManager: offsetManager,
+		createEventFunc: func(baseEvent models.BaseResourceEvent) interface{} {
+			return &models.ApplicationEvent{BaseResourceEvent: baseEvent}
+		},
+	}
+}
+
+func (p *resourceProcessor) Process(event *kube.EnhancedEvent, eventOffset *EventOffset) {
+	p.log.Info(event.ClusterName, "namespace", event.Namespace, "name", event.Name, "count",
+		fmt.Sprintf("%d", event.Count), "offset", fmt.Sprintf("%d", eventOffset.Offset))
+
+	source, err := json.Marshal(event.Source)
+	if err != nil {
+		p.log.Error(err, "failed to marshal event")
+		return
+	}
+	baseEvent := models.BaseResourceEvent{
+		EventName:   event.Name,
+		Kind:        event.InvolvedObject.Kind,
+		Name:        event.InvolvedObject.Name,
+		Namespace:   event.InvolvedObject.Namespace,
+		LeafHubName: event.ClusterName,
+		Message:     event.Message,
+		Reason:      event.Reason,
+		Count:       int(event.Count),
+		Source:      source,
+		CreatedAt:   event.LastTimestamp.Time,
+	}
+	if resourceId, ok := event.InvolvedObject.Labels[constants.EventGlobalResourceIdLabelKey]; ok {
+		baseEvent.ResourceID = &resourceId
+	}
+
+	conflictColumns := []clause.Column{{Name: "leaf_hub_name"}, {Name: "event_name"}, {Name: "count"}}
+	if err := upsertEvent(p.ctx, p.log, p.db, conflictColumns, p.createEventFunc(baseEvent)); err != nil {
+		p.log.Error(err, "insert or update event failed", "kind", event.InvolvedObject.Kind)
+		return
+	}
+	p.offsetManager.MarkOffset(eventOffset.Topic, eventOffset.Partition, eventOffset.Offset)
+}
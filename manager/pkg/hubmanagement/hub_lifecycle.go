@@ -0,0 +1,236 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// ErrLeafHubNotFound is returned when offboarding a leaf hub that is unknown to the global hub database.
+	ErrLeafHubNotFound = errors.New("leaf hub not found")
+	// errLeafHubOffboarding is returned when onboarding a leaf hub whose data isn't cleaned up yet.
+	errLeafHubOffboarding = errors.New("leaf hub is offboarding")
+)
+
+// leafHubTables are the tables holding the rows of the leaf hubs, they're cleaned up when offboarding a leaf hub.
+var leafHubTables = []string{
+	"spec.leaf_hub_agent_configs",
+	"spec.leaf_hub_placement_decisions",
+	"spec.leaf_hub_rollouts",
+	"spec.managed_cluster_sets_tracking",
+	"spec.managed_clusters_labels",
+	"status.managed_clusters",
+	"status.managed_cluster_addons",
+	"status.compliance",
+	"status.aggregated_compliance",
+	"status.placementdecisions",
+	"status.placementrules",
+	"status.placements",
+	"status.subscription_reports",
+	"status.subscription_statuses",
+	"status.applied_statuses",
+	"status.argocd_applications",
+	"status.argocd_applicationsets",
+	"status.policy_violations",
+	"status.policy_reports",
+	"status.leaf_hub_capabilities",
+	"status.leaf_hub_heartbeats",
+	"status.leaf_hubs",
+	"local_spec.placementrules",
+	"local_spec.policies",
+	"local_status.compliance",
+	"event.local_policies",
+	"event.local_root_policies",
+	"event.managed_clusters",
+	"event.placements",
+	"event.applications",
+}
+
+// retainedLeafHubTables are the tables holding the rows of the leaf hubs that are kept when offboarding a leaf hub, the
+// history tables are trimmed by their own retention, and the lifecycle of the leaf hub is its offboarding state.
+var retainedLeafHubTables = []string{
+	"history.compliance",
+	"history.local_compliance",
+	"history.leaf_hub_archives",
+	"history.leaf_hub_lifecycle_actions",
+	"status.leaf_hub_lifecycles",
+}
+
+const archiveLeafHubRowsSQL = `INSERT INTO history.leaf_hub_archives (leaf_hub_name, table_name, payload)
+	SELECT t.leaf_hub_name, '%s', to_jsonb(t) FROM %s t WHERE t.leaf_hub_name = ?`
+
+// RequestOffboarding marks the leaf hub as offboarding, its data is cleaned up by the hub management with the given
+// retention, or with the retention of the manager if it's empty. the request is recorded with the given audit log, if
+// any, within the same transaction. returns false if the leaf hub is already offboarding or offboarded.
+func RequestOffboarding(ctx context.Context, leafHubName, reason, retention string, auditLog *models.AuditLog,
+) (bool, error) {
+	requested := false
+	err := database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lifecycle, found, err := getLifecycle(tx, leafHubName)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			// the leaf hub is attached before the lifecycle is recorded, offboard it only if it has reported
+			var heartbeats int64
+			if err := tx.Table(fmt.Sprintf("%s.%s", database.StatusSchema, database.LeafHubHeartbeatsTableName)).
+				Where("leaf_hub_name = ?", leafHubName).Count(&heartbeats).Error; err != nil {
+				return err
+			}
+			if heartbeats == 0 {
+				return ErrLeafHubNotFound
+			}
+		} else if lifecycle.State != string(database.LeafHubOnboarded) {
+			// the decommissioned leaf hub is detached afterwards, it's onboarded again once it's attached back
+			if lifecycle.State == string(database.LeafHubOffboarded) &&
+				lifecycle.Reason == database.OffboardingReasonDecommissioned &&
+				reason != database.OffboardingReasonDecommissioned {
+				return tx.Model(lifecycle).Update("reason", reason).Error
+			}
+			return nil
+		}
+
+		requested = true
+		if err := upsertLifecycle(tx, &models.LeafHubLifecycle{
+			LeafHubName: leafHubName,
+			State:       string(database.LeafHubOffboarding),
+			Reason:      reason,
+			Retention:   retention,
+		}, nil); err != nil || auditLog == nil {
+			return err
+		}
+		return audit.RecordWithGorm(tx, auditLog)
+	})
+	return requested, err
+}
+
+// onboard marks the leaf hub as onboarded. returns false if the leaf hub is already onboarded, or it's decommissioned
+// and still attached.
+func onboard(ctx context.Context, leafHubName string) (bool, error) {
+	onboarded := false
+	err := database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lifecycle, found, err := getLifecycle(tx, leafHubName)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			switch lifecycle.State {
+			case string(database.LeafHubOnboarded):
+				return nil
+			case string(database.LeafHubOffboarding):
+				return errLeafHubOffboarding
+			case string(database.LeafHubOffboarded):
+				if lifecycle.Reason == database.OffboardingReasonDecommissioned {
+					return nil
+				}
+			}
+		}
+
+		onboarded = true
+		return upsertLifecycle(tx, &models.LeafHubLifecycle{
+			LeafHubName: leafHubName,
+			State:       string(database.LeafHubOnboarded),
+		}, nil)
+	})
+	return onboarded, err
+}
+
+// offboard archives or deletes the rows of the offboarding leaf hub, and marks it as offboarded. returns the number of
+// the removed rows of each table.
+func offboard(ctx context.Context, leafHubName, defaultRetention string) (map[string]int64, error) {
+	affectedRows := map[string]int64{}
+	err := database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lifecycle, found, err := getLifecycle(tx, leafHubName)
+		if err != nil {
+			return err
+		}
+		if !found || lifecycle.State != string(database.LeafHubOffboarding) {
+			return fmt.Errorf("leaf hub %s isn't offboarding", leafHubName)
+		}
+
+		retention := lifecycle.Retention
+		if retention == "" {
+			retention = defaultRetention
+		}
+		for _, table := range leafHubTables {
+			if retention == database.RetentionArchive {
+				if err := tx.Exec(fmt.Sprintf(archiveLeafHubRowsSQL, table, table), leafHubName).Error; err != nil {
+					return fmt.Errorf("failed to archive %s: %w", table, err)
+				}
+			}
+			result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE leaf_hub_name = ?", table), leafHubName)
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete from %s: %w", table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				affectedRows[table] = result.RowsAffected
+			}
+		}
+
+		return upsertLifecycle(tx, &models.LeafHubLifecycle{
+			LeafHubName: leafHubName,
+			State:       string(database.LeafHubOffboarded),
+			Reason:      lifecycle.Reason,
+			Retention:   retention,
+		}, affectedRows)
+	})
+	return affectedRows, err
+}
+
+// listLeafHubs returns the names of the leaf hubs in the lifecycle state.
+func listLeafHubs(ctx context.Context, state database.LeafHubLifecycleState) ([]string, error) {
+	var leafHubNames []string
+	err := database.GetGorm().WithContext(ctx).Model(&models.LeafHubLifecycle{}).
+		Where("state = ?", string(state)).Pluck("leaf_hub_name", &leafHubNames).Error
+	return leafHubNames, err
+}
+
+// getLifecycle locks the lifecycle of the leaf hub in the transaction.
+func getLifecycle(tx *gorm.DB, leafHubName string) (*models.LeafHubLifecycle, bool, error) {
+	lifecycle := &models.LeafHubLifecycle{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("leaf_hub_name = ?", leafHubName).Limit(1).Find(lifecycle)
+	return lifecycle, result.RowsAffected > 0, result.Error
+}
+
+// upsertLifecycle updates the lifecycle of the leaf hub, and records the action of entering the state.
+func upsertLifecycle(tx *gorm.DB, lifecycle *models.LeafHubLifecycle, affectedRows map[string]int64) error {
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leaf_hub_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"state", "reason", "retention"}),
+	}).Create(lifecycle).Error; err != nil {
+		return fmt.Errorf("failed to update the lifecycle of leaf hub %s: %w", lifecycle.LeafHubName, err)
+	}
+
+	action := &models.LeafHubLifecycleAction{
+		LeafHubName: lifecycle.LeafHubName,
+		Action:      lifecycle.State,
+		Reason:      lifecycle.Reason,
+		Retention:   lifecycle.Retention,
+	}
+	if affectedRows != nil {
+		payload, err := json.Marshal(affectedRows)
+		if err != nil {
+			return err
+		}
+		action.AffectedRows = payload
+	}
+	if err := tx.Create(action).Error; err != nil {
+		return fmt.Errorf("failed to record the lifecycle action of leaf hub %s: %w", lifecycle.LeafHubName, err)
+	}
+	return nil
+}
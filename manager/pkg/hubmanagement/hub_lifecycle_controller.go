@@ -0,0 +1,130 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// hubLifecycleController onboards the leaf hubs with the global hub addon, and requests offboarding the leaf hubs
+// once their managed cluster or the addon is removed.
+type hubLifecycleController struct {
+	client        client.Client
+	log           logr.Logger
+	hubManagement *hubManagement
+}
+
+func addHubLifecycleController(mgr ctrl.Manager, hubManagement *hubManagement) error {
+	// only the leaf hubs joining or leaving change the lifecycle
+	deletionPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() != e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+	addonPredicate := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == constants.GHManagedClusterAddonName
+	})
+	// the addon is in the namespace of the managed cluster
+	enqueueLeafHub := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: obj.GetNamespace()}}}
+	})
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("hub-lifecycle-controller").
+		For(&clusterv1.ManagedCluster{}, builder.WithPredicates(deletionPredicate)).
+		Watches(&source.Kind{Type: &addonv1alpha1.ManagedClusterAddOn{}}, enqueueLeafHub,
+			builder.WithPredicates(addonPredicate, deletionPredicate)).
+		Complete(&hubLifecycleController{
+			client:        mgr.GetClient(),
+			log:           ctrl.Log.WithName("hub-lifecycle-controller"),
+			hubManagement: hubManagement,
+		}); err != nil {
+		return fmt.Errorf("failed to add hub lifecycle controller to the manager: %w", err)
+	}
+	return nil
+}
+
+func (r *hubLifecycleController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	leafHubName := request.Name
+	if leafHubName == constants.LocalClusterName {
+		return ctrl.Result{}, nil
+	}
+
+	reason, err := r.offboardingReason(ctx, leafHubName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reason != "" {
+		requested, err := RequestOffboarding(ctx, leafHubName, reason, "", nil)
+		if errors.Is(err, ErrLeafHubNotFound) {
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if requested {
+			r.log.Info("requested offboarding the leaf hub", "leafHub", leafHubName, "reason", reason)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if _, err := r.hubManagement.onboard(ctx, leafHubName); err != nil {
+		// the leaf hub is attached back before its data is cleaned up, onboard it after the cleanup
+		if errors.Is(err, errLeafHubOffboarding) {
+			return ctrl.Result{RequeueAfter: r.hubManagement.interval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// offboardingReason returns the reason of offboarding the leaf hub, or empty if the leaf hub is attached with the
+// global hub addon.
+func (r *hubLifecycleController) offboardingReason(ctx context.Context, leafHubName string) (string, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: leafHubName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return database.OffboardingReasonClusterDeleted, nil
+		}
+		return "", err
+	}
+	if !cluster.GetDeletionTimestamp().IsZero() {
+		return database.OffboardingReasonClusterDeleted, nil
+	}
+
+	addon := &addonv1alpha1.ManagedClusterAddOn{}
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: leafHubName,
+		Name:      constants.GHManagedClusterAddonName,
+	}, addon); err != nil {
+		if apierrors.IsNotFound(err) {
+			return database.OffboardingReasonAddonDeleted, nil
+		}
+		return "", err
+	}
+	if !addon.GetDeletionTimestamp().IsZero() {
+		return database.OffboardingReasonAddonDeleted, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,201 @@
+package hubmanagement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type fakeConflation struct {
+	offboarded map[string]bool
+}
+
+func (c *fakeConflation) OffboardLeafHub(ctx context.Context, leafHubName string) error {
+	c.offboarded[leafHubName] = true
+	return nil
+}
+
+func (c *fakeConflation) OnboardLeafHub(leafHubName string) { delete(c.offboarded, leafHubName) }
+
+var _ = Describe("leaf hub lifecycle", Ordered, func() {
+	const leafHubName = "hub1"
+	var conflation *fakeConflation
+	var hubs *hubManagement
+
+	getLifecycle := func() *models.LeafHubLifecycle {
+		lifecycle := &models.LeafHubLifecycle{}
+		Expect(database.GetGorm().Where("leaf_hub_name = ?", leafHubName).First(lifecycle).Error).To(Succeed())
+		return lifecycle
+	}
+	count := func(table string) int64 {
+		var rows int64
+		Expect(database.GetGorm().Table(table).Where("leaf_hub_name = ?", leafHubName).Count(&rows).Error).
+			To(Succeed())
+		return rows
+	}
+
+	BeforeAll(func() {
+		By("Create the lifecycle tables and the tables of the leaf hub rows")
+		db := database.GetGorm()
+		Expect(db.Exec(`
+			CREATE SCHEMA IF NOT EXISTS spec;
+			CREATE SCHEMA IF NOT EXISTS status;
+			CREATE SCHEMA IF NOT EXISTS local_spec;
+			CREATE SCHEMA IF NOT EXISTS local_status;
+			CREATE SCHEMA IF NOT EXISTS event;
+			CREATE SCHEMA IF NOT EXISTS history;
+			CREATE TABLE IF NOT EXISTS status.leaf_hub_lifecycles (
+				leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+				state character varying(63) NOT NULL,
+				reason text,
+				retention character varying(63),
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS history.leaf_hub_lifecycle_actions (
+				leaf_hub_name character varying(63) NOT NULL,
+				action character varying(63) NOT NULL,
+				reason text,
+				retention character varying(63),
+				affected_rows jsonb,
+				created_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS history.leaf_hub_archives (
+				leaf_hub_name character varying(63) NOT NULL,
+				table_name character varying(127) NOT NULL,
+				payload jsonb NOT NULL,
+				archived_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`).Error).To(Succeed())
+		for _, table := range leafHubTables {
+			Expect(db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+				leaf_hub_name character varying(63) NOT NULL,
+				payload jsonb
+			)`, table)).Error).To(Succeed())
+		}
+
+		By("Insert the rows of the leaf hub")
+		leafHubRowTables := []string{
+			"spec.leaf_hub_rollouts", "status.leaf_hub_heartbeats", "status.managed_clusters", "event.local_policies",
+		}
+		for _, table := range leafHubRowTables {
+			Expect(db.Exec(fmt.Sprintf(`INSERT INTO %s (leaf_hub_name, payload) VALUES (?, '{"name": "test"}')`,
+				table), leafHubName).Error).To(Succeed())
+		}
+
+		conflation = &fakeConflation{offboarded: map[string]bool{}}
+		hubs = &hubManagement{
+			log:        ctrl.Log.WithName("hub-management"),
+			conflation: conflation,
+			retention:  database.RetentionArchive,
+			interval:   defaultOffboardingInterval,
+		}
+	})
+
+	It("onboard the leaf hub", func() {
+		onboarded, err := hubs.onboard(ctx, leafHubName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onboarded).To(BeTrue())
+		Expect(getLifecycle().State).To(Equal(string(database.LeafHubOnboarded)))
+
+		By("Onboard the onboarded leaf hub")
+		onboarded, err = hubs.onboard(ctx, leafHubName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onboarded).To(BeFalse())
+	})
+
+	It("request offboarding the leaf hub", func() {
+		requested, err := RequestOffboarding(ctx, leafHubName, database.OffboardingReasonClusterDeleted, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requested).To(BeTrue())
+		Expect(getLifecycle().State).To(Equal(string(database.LeafHubOffboarding)))
+
+		By("Request offboarding the offboarding leaf hub")
+		requested, err = RequestOffboarding(ctx, leafHubName, database.OffboardingReasonDecommissioned, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requested).To(BeFalse())
+
+		By("Request offboarding the unknown leaf hub")
+		_, err = RequestOffboarding(ctx, "unknown", database.OffboardingReasonDecommissioned, "", nil)
+		Expect(err).To(MatchError(ErrLeafHubNotFound))
+
+		By("Onboard the leaf hub before its data is cleaned up")
+		_, err = hubs.onboard(ctx, leafHubName)
+		Expect(err).To(MatchError(errLeafHubOffboarding))
+	})
+
+	It("archive the data of the offboarding leaf hub", func() {
+		hubs.offboardLeafHubs(ctx)
+		Expect(conflation.offboarded[leafHubName]).To(BeTrue())
+
+		lifecycle := getLifecycle()
+		Expect(lifecycle.State).To(Equal(string(database.LeafHubOffboarded)))
+		Expect(lifecycle.Retention).To(Equal(database.RetentionArchive))
+		Expect(count("spec.leaf_hub_rollouts")).To(BeZero())
+		Expect(count("status.managed_clusters")).To(BeZero())
+		Expect(count("status.leaf_hub_heartbeats")).To(BeZero())
+		Expect(count("event.local_policies")).To(BeZero())
+		Expect(count("history.leaf_hub_archives")).To(Equal(int64(4)))
+
+		By("Check the offboarded action is recorded with the affected rows")
+		action := &models.LeafHubLifecycleAction{}
+		Expect(database.GetGorm().Where("leaf_hub_name = ? AND action = ?", leafHubName,
+			string(database.LeafHubOffboarded)).First(action).Error).To(Succeed())
+		Expect(action.Reason).To(Equal(database.OffboardingReasonClusterDeleted))
+		affectedRows := map[string]int64{}
+		Expect(json.Unmarshal(action.AffectedRows, &affectedRows)).To(Succeed())
+		Expect(affectedRows).To(Equal(map[string]int64{
+			"spec.leaf_hub_rollouts":     1,
+			"status.leaf_hub_heartbeats": 1,
+			"status.managed_clusters":    1,
+			"event.local_policies":       1,
+		}))
+	})
+
+	It("onboard the leaf hub again", func() {
+		onboarded, err := hubs.onboard(ctx, leafHubName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onboarded).To(BeTrue())
+		Expect(conflation.offboarded).NotTo(HaveKey(leafHubName))
+		Expect(getLifecycle().State).To(Equal(string(database.LeafHubOnboarded)))
+	})
+
+	It("delete the data of the decommissioned leaf hub", func() {
+		Expect(database.GetGorm().Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, payload)
+			VALUES (?, '{"name": "test"}')`, leafHubName).Error).To(Succeed())
+
+		requested, err := RequestOffboarding(ctx, leafHubName, database.OffboardingReasonDecommissioned,
+			database.RetentionDelete, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requested).To(BeTrue())
+
+		hubs.offboardLeafHubs(ctx)
+		Expect(getLifecycle().State).To(Equal(string(database.LeafHubOffboarded)))
+		Expect(count("status.managed_clusters")).To(BeZero())
+		Expect(count("history.leaf_hub_archives")).To(Equal(int64(4)))
+
+		By("The decommissioned leaf hub isn't onboarded while it's attached")
+		onboarded, err := hubs.onboard(ctx, leafHubName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onboarded).To(BeFalse())
+		Expect(conflation.offboarded[leafHubName]).To(BeTrue())
+
+		By("The decommissioned leaf hub is onboarded once it's detached and attached back")
+		requested, err = RequestOffboarding(ctx, leafHubName, database.OffboardingReasonClusterDeleted, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requested).To(BeFalse())
+		Expect(getLifecycle().Reason).To(Equal(database.OffboardingReasonClusterDeleted))
+
+		onboarded, err = hubs.onboard(ctx, leafHubName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onboarded).To(BeTrue())
+		Expect(conflation.offboarded).NotTo(HaveKey(leafHubName))
+	})
+})
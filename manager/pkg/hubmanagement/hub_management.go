@@ -0,0 +1,125 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const defaultOffboardingInterval = 30 * time.Second
+
+// LeafHubConflation stops and resumes processing the status bundles of a leaf hub, it's implemented by the conflation
+// manager. OffboardLeafHub returns once the bundles of the leaf hub in process are finished.
+type LeafHubConflation interface {
+	OffboardLeafHub(ctx context.Context, leafHubName string) error
+	OnboardLeafHub(leafHubName string)
+}
+
+// hubManagement cleans up the data of the offboarding leaf hubs in the global hub database.
+type hubManagement struct {
+	log        logr.Logger
+	conflation LeafHubConflation
+	retention  string
+	interval   time.Duration
+}
+
+// AddHubManagement adds the lifecycle controller of the leaf hubs and the cleanup of the offboarding leaf hubs to the
+// manager, the data of the offboarding leaf hubs is cleaned up every interval with the retention.
+func AddHubManagement(mgr ctrl.Manager, conflation LeafHubConflation, retention string, interval time.Duration) error {
+	if retention == "" {
+		retention = database.RetentionArchive
+	}
+	if retention != database.RetentionArchive && retention != database.RetentionDelete {
+		return fmt.Errorf("unsupported offboarding retention %q, must be %q or %q", retention,
+			database.RetentionArchive, database.RetentionDelete)
+	}
+	if interval <= 0 {
+		interval = defaultOffboardingInterval
+	}
+
+	hubManagement := &hubManagement{
+		log:        ctrl.Log.WithName("hub-management"),
+		conflation: conflation,
+		retention:  retention,
+		interval:   interval,
+	}
+	if err := mgr.Add(hubManagement); err != nil {
+		return fmt.Errorf("failed to add hub management to the manager: %w", err)
+	}
+
+	return addHubLifecycleController(mgr, hubManagement)
+}
+
+// Start discards the bundles of the offboarded leaf hubs, and periodically cleans up the offboarding leaf hubs.
+func (h *hubManagement) Start(ctx context.Context) error {
+	offboardedLeafHubs, err := listLeafHubs(ctx, database.LeafHubOffboarded)
+	if err != nil {
+		return fmt.Errorf("failed to list the offboarded leaf hubs: %w", err)
+	}
+	for _, leafHubName := range offboardedLeafHubs {
+		if err := h.conflation.OffboardLeafHub(ctx, leafHubName); err != nil {
+			return fmt.Errorf("failed to discard the bundles of the offboarded leaf hub %s: %w", leafHubName, err)
+		}
+	}
+	h.log.Info("start hub management", "offboardedLeafHubs", len(offboardedLeafHubs))
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			h.log.Info("stop hub management")
+			return nil
+		case <-ticker.C:
+			h.offboardLeafHubs(ctx)
+		}
+	}
+}
+
+func (h *hubManagement) offboardLeafHubs(ctx context.Context) {
+	offboardingLeafHubs, err := listLeafHubs(ctx, database.LeafHubOffboarding)
+	if err != nil {
+		h.log.Error(err, "failed to list the offboarding leaf hubs")
+		return
+	}
+	for _, leafHubName := range offboardingLeafHubs {
+		// discard the bundles of the leaf hub and wait for the ones in process before cleaning up, otherwise they add
+		// the rows back. the leaf hub stays offboarding and is retried in the next interval if they aren't finished.
+		if err := h.drain(ctx, leafHubName); err != nil {
+			h.log.Error(err, "failed to offboard the leaf hub", "leafHub", leafHubName)
+			continue
+		}
+		affectedRows, err := offboard(ctx, leafHubName, h.retention)
+		if err != nil {
+			h.log.Error(err, "failed to offboard the leaf hub", "leafHub", leafHubName)
+			continue
+		}
+		h.log.Info("offboarded the leaf hub", "leafHub", leafHubName, "affectedRows", affectedRows)
+	}
+}
+
+// drain discards the bundles of the leaf hub, and waits up to the interval for the ones in process.
+func (h *hubManagement) drain(ctx context.Context, leafHubName string) error {
+	drainCtx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+	return h.conflation.OffboardLeafHub(drainCtx, leafHubName)
+}
+
+// onboard marks the leaf hub as onboarded, and resumes processing its bundles from scratch if it was offboarded.
+func (h *hubManagement) onboard(ctx context.Context, leafHubName string) (bool, error) {
+	onboarded, err := onboard(ctx, leafHubName)
+	if err != nil || !onboarded {
+		return onboarded, err
+	}
+	h.conflation.OnboardLeafHub(leafHubName)
+	h.log.Info("onboarded the leaf hub", "leafHub", leafHubName)
+	return true, nil
+}
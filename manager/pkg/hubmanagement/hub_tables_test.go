@@ -0,0 +1,44 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tablesSQLPath = "../../../operator/pkg/controllers/hubofhubs/database/2.tables.sql"
+
+var (
+	createTableRegexp   = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\S+) \((.*?)\n\);`)
+	leafHubColumnRegexp = regexp.MustCompile(`(?m)^\s*leaf_hub_name\s`)
+)
+
+// TestLeafHubTables checks every table with the leaf_hub_name column is either cleaned up or retained on purpose when
+// offboarding a leaf hub.
+func TestLeafHubTables(t *testing.T) {
+	tablesSQL, err := os.ReadFile(tablesSQLPath)
+	assert.NoError(t, err)
+
+	tables := map[string]bool{}
+	for _, match := range createTableRegexp.FindAllStringSubmatch(string(tablesSQL), -1) {
+		tables[match[1]] = leafHubColumnRegexp.MatchString(match[2])
+	}
+	assert.NotEmpty(t, tables)
+
+	handledTables := map[string]bool{}
+	for _, table := range append(append([]string{}, leafHubTables...), retainedLeafHubTables...) {
+		assert.False(t, handledTables[table], "table %s is listed more than once", table)
+		handledTables[table] = true
+		assert.True(t, tables[table], "table %s doesn't exist or has no leaf_hub_name column", table)
+	}
+	for table, hasLeafHubName := range tables {
+		if hasLeafHubName {
+			assert.True(t, handledTables[table], "table %s is neither cleaned up nor retained", table)
+		}
+	}
+}
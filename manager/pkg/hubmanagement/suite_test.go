@@ -0,0 +1,49 @@
+package hubmanagement
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/test/pkg/testpostgres"
+)
+
+var (
+	ctx          context.Context
+	cancel       context.CancelFunc
+	testPostgres *testpostgres.TestPostgres
+)
+
+func TestHubManagement(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Hub Management Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+	ctx, cancel = context.WithCancel(context.Background())
+
+	By("Create test postgres")
+	var err error
+	testPostgres, err = testpostgres.NewTestPostgres()
+	Expect(err).NotTo(HaveOccurred())
+
+	By("Connect to the database")
+	err = database.InitGormInstance(&database.DatabaseConfig{
+		URL:      testPostgres.URI,
+		Dialect:  database.PostgresDialect,
+		PoolSize: 1,
+	})
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	cancel()
+	database.CloseGorm()
+	Expect(testPostgres.Stop()).NotTo(HaveOccurred())
+})
@@ -0,0 +1,286 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package appliedstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+
+	// LeafHubsQuery lists the connected leaf hubs and the leaf hubs reporting applied statuses.
+	LeafHubsQuery = `SELECT leaf_hub_name FROM status.leaf_hub_heartbeats
+		UNION SELECT DISTINCT leaf_hub_name FROM status.applied_statuses`
+	// TargetedCondition is true if the global resource with the id %[1]s is sent to the leaf hub %[2]s, the global
+	// resources are sent to all the leaf hubs, except the resources rolled out in waves, which are only sent to the
+	// leaf hubs the rollout has reached.
+	TargetedCondition = `(NOT EXISTS (SELECT 1 FROM spec.rollouts r WHERE r.resource_id = %[1]s) OR
+		EXISTS (SELECT 1 FROM spec.leaf_hub_rollouts lr, jsonb_array_elements(lr.payload -> 'objects') o
+			WHERE lr.leaf_hub_name = %[2]s AND
+			o -> 'metadata' -> 'annotations' ->> '` + constants.OriginOwnerReferenceAnnotation + `' = %[1]s::text))`
+
+	// AppliedConditionType is the condition type that reports the rollout of a global resource across leaf hubs.
+	AppliedConditionType = "Applied"
+	// DriftedConditionType is the condition type that reports the drift of a global resource on the leaf hubs.
+	DriftedConditionType = "Drifted"
+
+	reasonApplied      = "Applied"
+	reasonApplyFailed  = "ApplyFailed"
+	reasonApplyPending = "ApplyPending"
+	reasonDryRun       = "DryRun"
+	reasonDrifted      = "Drifted"
+	reasonNotDrifted   = "NotDrifted"
+)
+
+var (
+	// the leaf hubs reporting the resource, and the leaf hubs the resource is sent to but not reported by yet
+	appliedStatusQuery = `SELECT hb.leaf_hub_name, a.kind, a.name, a.namespace, a.bundle_version, a.applied,
+		a.error, a.dry_run, a.drifted, a.diff, a.updated_at FROM (` + LeafHubsQuery + `) hb
+		LEFT JOIN status.applied_statuses a ON hb.leaf_hub_name = a.leaf_hub_name AND a.resource_id = $1
+		WHERE a.resource_id IS NOT NULL OR ` + fmt.Sprintf(TargetedCondition, "$1::uuid", "hb.leaf_hub_name") + `
+		ORDER BY hb.leaf_hub_name`
+	resourceExistsQuery = buildResourceExistsQuery()
+
+	// GlobalResourceTables are the spec tables of the global resources sent to the leaf hubs.
+	GlobalResourceTables = []string{
+		"policies", "placementrules", "placementbindings", "applications", "subscriptions", "channels", "placements",
+		"managedclustersets", "managedclustersetbindings", "resources",
+	}
+)
+
+// LeafHubAppliedStatus is the applied status of a global resource on a single leaf hub.
+type LeafHubAppliedStatus struct {
+	LeafHubName   string       `json:"leafHubName"`
+	Kind          string       `json:"kind,omitempty"`
+	Name          string       `json:"name,omitempty"`
+	Namespace     string       `json:"namespace,omitempty"`
+	BundleVersion string       `json:"bundleVersion,omitempty"`
+	Applied       bool         `json:"applied"`
+	Pending       bool         `json:"pending"`
+	Error         string       `json:"error,omitempty"`
+	DryRun        bool         `json:"dryRun,omitempty"`
+	Drifted       bool         `json:"drifted,omitempty"`
+	Diff          string       `json:"diff,omitempty"`
+	UpdatedAt     *metav1.Time `json:"updatedAt,omitempty"`
+}
+
+// AppliedStatus is the applied status of a global resource across all the leaf hubs.
+type AppliedStatus struct {
+	ResourceID string                 `json:"resourceId"`
+	Conditions []metav1.Condition     `json:"conditions"`
+	LeafHubs   []LeafHubAppliedStatus `json:"leafHubs"`
+}
+
+// GetAppliedStatus godoc
+// @summary get applied status of a global resource
+// @description get the applied status of a global resource (policy, placement, subscription...) on every leaf hub
+// @accept json
+// @produce json
+// @param        resourceID    path    string    true    "Global Resource ID"
+// @success      200  {object}  AppliedStatus
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /appliedstatus/{resourceID} [get]
+func GetAppliedStatus(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		resourceID := ginCtx.Param("resourceID")
+		fmt.Fprintf(gin.DefaultWriter, "getting applied status for resource: %s\n", resourceID)
+		if _, err := uuid.Parse(resourceID); err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid resource id %s", resourceID)
+			return
+		}
+
+		var found bool
+		if err := dbConnectionPool.QueryRow(ginCtx, resourceExistsQuery, resourceID).Scan(&found); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying resource: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if !found {
+			ginCtx.String(http.StatusNotFound, "global resource %s not found", resourceID)
+			return
+		}
+
+		fmt.Fprintf(gin.DefaultWriter, "applied status query: %s\n", appliedStatusQuery)
+		appliedStatus, err := getAppliedStatus(dbConnectionPool, resourceID)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying applied status: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, appliedStatus)
+	}
+}
+
+// buildResourceExistsQuery returns the query checking whether the global resource is in any spec table or is still
+// reported by the leaf hubs.
+func buildResourceExistsQuery() string {
+	conditions := []string{"EXISTS (SELECT 1 FROM status.applied_statuses WHERE resource_id = $1)"}
+	for _, tableName := range GlobalResourceTables {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM spec.%s WHERE id = $1 AND deleted = FALSE)", tableName))
+	}
+	return "SELECT " + strings.Join(conditions, " OR ")
+}
+
+func getAppliedStatus(dbConnectionPool *pgxpool.Pool, resourceID string) (*AppliedStatus, error) {
+	rows, err := dbConnectionPool.Query(context.TODO(), appliedStatusQuery, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error in querying applied status for resource(%s): %w", resourceID, err)
+	}
+	defer rows.Close()
+
+	appliedStatus := &AppliedStatus{
+		ResourceID: resourceID,
+		LeafHubs:   []LeafHubAppliedStatus{},
+	}
+	for rows.Next() {
+		var leafHubName string
+		var kind, name, namespace, bundleVersion, applyError, diff *string
+		var applied, dryRun, drifted *bool
+		var updatedAt *time.Time
+		if err := rows.Scan(&leafHubName, &kind, &name, &namespace, &bundleVersion, &applied, &applyError,
+			&dryRun, &drifted, &diff, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error in scanning applied status: %w", err)
+		}
+
+		leafHubStatus := LeafHubAppliedStatus{
+			LeafHubName:   leafHubName,
+			Kind:          stringValue(kind),
+			Name:          stringValue(name),
+			Namespace:     stringValue(namespace),
+			BundleVersion: stringValue(bundleVersion),
+			Error:         stringValue(applyError),
+			DryRun:        boolValue(dryRun),
+			Drifted:       boolValue(drifted),
+			Diff:          stringValue(diff),
+			Applied:       boolValue(applied),
+			// the resource is sent to the leaf hub, but it's not reported by the leaf hub yet
+			Pending: applied == nil,
+		}
+		if updatedAt != nil {
+			leafHubStatus.UpdatedAt = &metav1.Time{Time: *updatedAt}
+		}
+		appliedStatus.LeafHubs = append(appliedStatus.LeafHubs, leafHubStatus)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in querying applied status for resource(%s): %w", resourceID, err)
+	}
+
+	appliedStatus.Conditions = Conditions(appliedStatus.LeafHubs)
+	return appliedStatus, nil
+}
+
+// Conditions returns the Applied and Drifted conditions aggregated from the applied status of the leaf hubs, they're
+// returned by the api and written onto the global resources.
+func Conditions(leafHubs []LeafHubAppliedStatus) []metav1.Condition {
+	return []metav1.Condition{
+		getAppliedCondition(leafHubs),
+		getDriftedCondition(leafHubs),
+	}
+}
+
+// getAppliedCondition aggregates the applied status of the leaf hubs into a single condition.
+func getAppliedCondition(leafHubs []LeafHubAppliedStatus) metav1.Condition {
+	failed, pending, dryRun := []string{}, []string{}, []string{}
+	var lastTransitionTime metav1.Time
+	for _, leafHub := range leafHubs {
+		if leafHub.Pending {
+			pending = append(pending, leafHub.LeafHubName)
+			continue
+		}
+		if !leafHub.Applied {
+			failed = append(failed, leafHub.LeafHubName)
+		} else if leafHub.DryRun {
+			dryRun = append(dryRun, leafHub.LeafHubName)
+		}
+		if leafHub.UpdatedAt != nil && lastTransitionTime.Before(leafHub.UpdatedAt) {
+			lastTransitionTime = *leafHub.UpdatedAt
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               AppliedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonApplied,
+		Message:            fmt.Sprintf("applied on %d leaf hubs", len(leafHubs)),
+		LastTransitionTime: lastTransitionTime,
+	}
+	switch {
+	case len(failed) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonApplyFailed
+		condition.Message = fmt.Sprintf("failed to apply on leaf hubs: %v", failed)
+	case len(pending) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonApplyPending
+		condition.Message = fmt.Sprintf("waiting for leaf hubs: %v", pending)
+	case len(dryRun) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonDryRun
+		condition.Message = fmt.Sprintf("dry-run only on leaf hubs: %v", dryRun)
+	}
+
+	return condition
+}
+
+// getDriftedCondition reports whether the live resource on any of the leaf hubs drifted from the global spec.
+func getDriftedCondition(leafHubs []LeafHubAppliedStatus) metav1.Condition {
+	drifted := []string{}
+	var lastTransitionTime metav1.Time
+	for _, leafHub := range leafHubs {
+		if leafHub.Drifted {
+			drifted = append(drifted, leafHub.LeafHubName)
+		}
+		if leafHub.UpdatedAt != nil && lastTransitionTime.Before(leafHub.UpdatedAt) {
+			lastTransitionTime = *leafHub.UpdatedAt
+		}
+	}
+
+	if len(drifted) > 0 {
+		return metav1.Condition{
+			Type:               DriftedConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             reasonDrifted,
+			Message:            fmt.Sprintf("drifted on leaf hubs: %v", drifted),
+			LastTransitionTime: lastTransitionTime,
+		}
+	}
+	return metav1.Condition{
+		Type:               DriftedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonNotDrifted,
+		Message:            "no drift detected on the leaf hubs",
+		LastTransitionTime: lastTransitionTime,
+	}
+}
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
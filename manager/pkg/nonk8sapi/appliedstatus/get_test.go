@@ -0,0 +1,92 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package appliedstatus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAppliedCondition(t *testing.T) {
+	now := metav1.Now()
+	cases := []struct {
+		name     string
+		leafHubs []LeafHubAppliedStatus
+		status   metav1.ConditionStatus
+		reason   string
+	}{
+		{
+			name: "applied on all leaf hubs",
+			leafHubs: []LeafHubAppliedStatus{
+				{LeafHubName: "hub1", Applied: true, UpdatedAt: &now},
+				{LeafHubName: "hub2", Applied: true, UpdatedAt: &now},
+			},
+			status: metav1.ConditionTrue,
+			reason: reasonApplied,
+		},
+		{
+			name: "pending on a leaf hub",
+			leafHubs: []LeafHubAppliedStatus{
+				{LeafHubName: "hub1", Applied: true, UpdatedAt: &now},
+				{LeafHubName: "hub2", Pending: true},
+			},
+			status: metav1.ConditionFalse,
+			reason: reasonApplyPending,
+		},
+		{
+			name: "dry-run on a leaf hub",
+			leafHubs: []LeafHubAppliedStatus{
+				{LeafHubName: "hub1", Applied: true, UpdatedAt: &now},
+				{LeafHubName: "hub2", Applied: true, DryRun: true, Diff: `{"spec":{"disabled":true}}`},
+			},
+			status: metav1.ConditionFalse,
+			reason: reasonDryRun,
+		},
+		{
+			name: "failed on a leaf hub",
+			leafHubs: []LeafHubAppliedStatus{
+				{LeafHubName: "hub1", Applied: false, Error: "denied", UpdatedAt: &now},
+				{LeafHubName: "hub2", Pending: true},
+			},
+			status: metav1.ConditionFalse,
+			reason: reasonApplyFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			condition := getAppliedCondition(tc.leafHubs)
+			assert.Equal(t, AppliedConditionType, condition.Type)
+			assert.Equal(t, tc.status, condition.Status)
+			assert.Equal(t, tc.reason, condition.Reason)
+		})
+	}
+}
+
+func TestGetDriftedCondition(t *testing.T) {
+	condition := getDriftedCondition([]LeafHubAppliedStatus{
+		{LeafHubName: "hub1", Applied: true},
+		{LeafHubName: "hub2", Applied: true, Drifted: true, Diff: `{"spec":{"remediationAction":"inform"}}`},
+	})
+	assert.Equal(t, DriftedConditionType, condition.Type)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Contains(t, condition.Message, "hub2")
+
+	condition = getDriftedCondition([]LeafHubAppliedStatus{{LeafHubName: "hub1", Applied: true}})
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+}
+
+func TestBuildResourceExistsQuery(t *testing.T) {
+	query := buildResourceExistsQuery()
+	assert.Contains(t, query, "EXISTS (SELECT 1 FROM status.applied_statuses WHERE resource_id = $1)")
+	for _, tableName := range GlobalResourceTables {
+		assert.Contains(t, query, fmt.Sprintf("EXISTS (SELECT 1 FROM spec.%s WHERE id = $1 AND deleted = FALSE)",
+			tableName))
+	}
+	// only the leaf hubs reporting the resource or the resource is sent to are listed
+	assert.Contains(t, appliedStatusQuery, "WHERE a.resource_id IS NOT NULL OR (NOT EXISTS")
+}
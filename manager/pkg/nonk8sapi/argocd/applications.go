@@ -0,0 +1,227 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	unknownStatus          = "Unknown"
+
+	// the applications generated by an applicationset are listed in the status of the applicationset
+	applicationsQuery = `SELECT a.leaf_hub_name, a.id, a.name, a.namespace, s.name, a.project, a.repo_url,
+		a.target_revision, a.revision, a.destination_server, a.destination_name, a.destination_namespace,
+		a.sync_status, a.health_status, a.health_message, a.operation_phase, a.reconciled_at, a.updated_at
+		FROM status.argocd_applications a LEFT JOIN status.argocd_applicationsets s
+		ON s.leaf_hub_name = a.leaf_hub_name AND s.namespace = a.namespace AND s.applications ? a.name`
+	applicationsOrder = " ORDER BY a.leaf_hub_name, a.namespace, a.name"
+)
+
+// Application is the sync and health status of an Argo CD Application on a leaf hub.
+type Application struct {
+	LeafHubName          string       `json:"leafHubName"`
+	ApplicationID        string       `json:"applicationId"`
+	Name                 string       `json:"name"`
+	Namespace            string       `json:"namespace"`
+	ApplicationSet       string       `json:"applicationSet,omitempty"`
+	Project              string       `json:"project,omitempty"`
+	RepoURL              string       `json:"repoURL,omitempty"`
+	TargetRevision       string       `json:"targetRevision,omitempty"`
+	Revision             string       `json:"revision,omitempty"`
+	DestinationServer    string       `json:"destinationServer,omitempty"`
+	DestinationName      string       `json:"destinationName,omitempty"`
+	DestinationNamespace string       `json:"destinationNamespace,omitempty"`
+	SyncStatus           string       `json:"syncStatus"`
+	HealthStatus         string       `json:"healthStatus"`
+	HealthMessage        string       `json:"healthMessage,omitempty"`
+	OperationPhase       string       `json:"operationPhase,omitempty"`
+	ReconciledAt         string       `json:"reconciledAt,omitempty"`
+	UpdatedAt            *metav1.Time `json:"updatedAt,omitempty"`
+}
+
+// ApplicationSummary counts the applications by their health and sync status.
+type ApplicationSummary struct {
+	Applications int            `json:"applications"`
+	Health       map[string]int `json:"health"`
+	Sync         map[string]int `json:"sync"`
+}
+
+// ApplicationList is the Argo CD Applications of all the leaf hubs.
+type ApplicationList struct {
+	Summary ApplicationSummary `json:"summary"`
+	Items   []Application      `json:"items"`
+}
+
+// applicationFilter holds the filters of the applications query.
+type applicationFilter struct {
+	leafHubName    string
+	project        string
+	applicationSet string
+	healthStatus   string
+	syncStatus     string
+}
+
+// ListApplications godoc
+// @summary list argo cd applications
+// @description list the health and sync status of the Argo CD applications across all the leaf hubs
+// @accept json
+// @produce json
+// @param        leafHubName      query     string  false  "list applications of the leaf hub"
+// @param        project          query     string  false  "list applications of the Argo CD project"
+// @param        applicationSet   query     string  false  "list applications generated by the applicationset"
+// @param        healthStatus     query     string  false  "health status: Healthy, Progressing, Degraded..."
+// @param        syncStatus       query     string  false  "sync status: Synced, OutOfSync or Unknown"
+// @success      200  {object}    ApplicationList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /argocdapplications [get]
+func ListApplications(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		filter := &applicationFilter{
+			leafHubName:    ginCtx.Query("leafHubName"),
+			project:        ginCtx.Query("project"),
+			applicationSet: ginCtx.Query("applicationSet"),
+			healthStatus:   ginCtx.Query("healthStatus"),
+			syncStatus:     ginCtx.Query("syncStatus"),
+		}
+
+		applications, err := queryApplications(ginCtx.Request.Context(), dbConnectionPool, filter)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying argocd applications: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		applicationList := &ApplicationList{
+			Summary: newApplicationSummary(),
+			Items:   applications,
+		}
+		for _, application := range applications {
+			updateApplicationSummary(&applicationList.Summary, application)
+		}
+
+		ginCtx.JSON(http.StatusOK, applicationList)
+	}
+}
+
+func queryApplications(ctx context.Context, dbConnectionPool *pgxpool.Pool, filter *applicationFilter,
+) ([]Application, error) {
+	query, args := buildApplicationsQuery(filter)
+	fmt.Fprintf(gin.DefaultWriter, "argocd applications query: %s\n", query)
+
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query argocd applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []Application{}
+	for rows.Next() {
+		var application Application
+		var applicationSet, project, repoURL, targetRevision, revision, destinationServer, destinationName,
+			destinationNamespace, syncStatus, healthStatus, healthMessage, operationPhase, reconciledAt *string
+		var updatedAt time.Time
+		if err := rows.Scan(&application.LeafHubName, &application.ApplicationID, &application.Name,
+			&application.Namespace, &applicationSet, &project, &repoURL, &targetRevision, &revision,
+			&destinationServer, &destinationName, &destinationNamespace, &syncStatus, &healthStatus, &healthMessage,
+			&operationPhase, &reconciledAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan argocd application: %w", err)
+		}
+
+		application.ApplicationSet = stringValue(applicationSet)
+		application.Project = stringValue(project)
+		application.RepoURL = stringValue(repoURL)
+		application.TargetRevision = stringValue(targetRevision)
+		application.Revision = stringValue(revision)
+		application.DestinationServer = stringValue(destinationServer)
+		application.DestinationName = stringValue(destinationName)
+		application.DestinationNamespace = stringValue(destinationNamespace)
+		application.SyncStatus = statusValue(syncStatus)
+		application.HealthStatus = statusValue(healthStatus)
+		application.HealthMessage = stringValue(healthMessage)
+		application.OperationPhase = stringValue(operationPhase)
+		application.ReconciledAt = stringValue(reconciledAt)
+		application.UpdatedAt = &metav1.Time{Time: updatedAt}
+		applications = append(applications, application)
+	}
+
+	return applications, rows.Err()
+}
+
+// buildApplicationsQuery returns the applications query with the positional arguments of the filter.
+func buildApplicationsQuery(filter *applicationFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.leafHubName != "" {
+		addCondition("a.leaf_hub_name = $%d", filter.leafHubName)
+	}
+	if filter.project != "" {
+		addCondition("a.project = $%d", filter.project)
+	}
+	if filter.applicationSet != "" {
+		addCondition("s.name = $%d", filter.applicationSet)
+	}
+	if filter.healthStatus != "" {
+		addCondition("a.health_status = $%d", filter.healthStatus)
+	}
+	if filter.syncStatus != "" {
+		addCondition("a.sync_status = $%d", filter.syncStatus)
+	}
+
+	query := applicationsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query + applicationsOrder, args
+}
+
+func newApplicationSummary() ApplicationSummary {
+	return ApplicationSummary{
+		Health: map[string]int{},
+		Sync:   map[string]int{},
+	}
+}
+
+// updateApplicationSummary counts the application by its health and sync status.
+func updateApplicationSummary(summary *ApplicationSummary, application Application) {
+	summary.Applications++
+	summary.Health[application.HealthStatus]++
+	summary.Sync[application.SyncStatus]++
+}
+
+// statusValue returns the status, the applications which aren't reconciled yet have the unknown status.
+func statusValue(value *string) string {
+	if value == nil || *value == "" {
+		return unknownStatus
+	}
+	return *value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
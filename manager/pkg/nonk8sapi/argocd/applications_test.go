@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildApplicationsQuery(t *testing.T) {
+	query, args := buildApplicationsQuery(&applicationFilter{})
+	assert.Equal(t, applicationsQuery+applicationsOrder, query)
+	assert.Empty(t, args)
+
+	query, args = buildApplicationsQuery(&applicationFilter{
+		leafHubName:    "hub1",
+		applicationSet: "guestbook",
+		healthStatus:   "Degraded",
+	})
+	assert.Equal(t, applicationsQuery+
+		" WHERE a.leaf_hub_name = $1 AND s.name = $2 AND a.health_status = $3"+applicationsOrder, query)
+	assert.Equal(t, []interface{}{"hub1", "guestbook", "Degraded"}, args)
+}
+
+func TestSummarizeApplicationSets(t *testing.T) {
+	applicationSets := []ApplicationSet{
+		{Name: "guestbook", Namespace: "openshift-gitops", Summary: newApplicationSummary()},
+		{Name: "helm-guestbook", Namespace: "openshift-gitops", Summary: newApplicationSummary()},
+	}
+	applications := []Application{
+		{
+			LeafHubName: "hub1", Name: "cluster1-guestbook", Namespace: "openshift-gitops",
+			ApplicationSet: "guestbook", HealthStatus: "Healthy", SyncStatus: "Synced",
+		},
+		{
+			LeafHubName: "hub2", Name: "cluster2-guestbook", Namespace: "openshift-gitops",
+			ApplicationSet: "guestbook", HealthStatus: "Degraded", SyncStatus: "Synced",
+		},
+		{
+			LeafHubName: "hub2", Name: "cluster3-guestbook", Namespace: "openshift-gitops",
+			ApplicationSet: "guestbook", HealthStatus: unknownStatus, SyncStatus: "OutOfSync",
+		},
+		{
+			// the application isn't generated by an applicationset
+			LeafHubName: "hub1", Name: "guestbook", Namespace: "openshift-gitops",
+			HealthStatus: "Healthy", SyncStatus: "Synced",
+		},
+	}
+
+	summarizeApplicationSets(applicationSets, applications)
+
+	assert.Equal(t, ApplicationSummary{
+		Applications: 3,
+		Health:       map[string]int{"Healthy": 1, "Degraded": 1, unknownStatus: 1},
+		Sync:         map[string]int{"Synced": 2, "OutOfSync": 1},
+	}, applicationSets[0].Summary)
+	assert.Equal(t, newApplicationSummary(), applicationSets[1].Summary)
+}
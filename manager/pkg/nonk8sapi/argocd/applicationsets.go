@@ -0,0 +1,146 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const applicationSetsQuery = `SELECT leaf_hub_name, name, namespace, applications, resources_up_to_date,
+	error_message, updated_at FROM status.argocd_applicationsets`
+
+// LeafHubApplicationSet is the status of an Argo CD ApplicationSet on a single leaf hub.
+type LeafHubApplicationSet struct {
+	LeafHubName       string       `json:"leafHubName"`
+	Applications      []string     `json:"applications"`
+	ResourcesUpToDate bool         `json:"resourcesUpToDate"`
+	ErrorMessage      string       `json:"errorMessage,omitempty"`
+	UpdatedAt         *metav1.Time `json:"updatedAt,omitempty"`
+}
+
+// ApplicationSet is an Argo CD ApplicationSet across the leaf hubs, the applicationsets of the leaf hubs are
+// aggregated by namespace and name.
+type ApplicationSet struct {
+	Name      string                  `json:"name"`
+	Namespace string                  `json:"namespace"`
+	Summary   ApplicationSummary      `json:"summary"`
+	LeafHubs  []LeafHubApplicationSet `json:"leafHubs"`
+}
+
+// ApplicationSetList is the Argo CD ApplicationSets of all the leaf hubs.
+type ApplicationSetList struct {
+	Items []ApplicationSet `json:"items"`
+}
+
+// ListApplicationSets godoc
+// @summary list argo cd applicationsets
+// @description list the Argo CD applicationsets across all the leaf hubs with the status of generated applications
+// @accept json
+// @produce json
+// @param        leafHubName      query     string  false  "list applicationsets of the leaf hub"
+// @success      200  {object}    ApplicationSetList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /argocdapplicationsets [get]
+func ListApplicationSets(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		leafHubName := ginCtx.Query("leafHubName")
+
+		applicationSetList, err := listApplicationSets(ginCtx.Request.Context(), dbConnectionPool, leafHubName)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying argocd applicationsets: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, applicationSetList)
+	}
+}
+
+func listApplicationSets(ctx context.Context, dbConnectionPool *pgxpool.Pool, leafHubName string,
+) (*ApplicationSetList, error) {
+	query, args := applicationSetsQuery, []interface{}{}
+	if leafHubName != "" {
+		query += " WHERE leaf_hub_name = $1"
+		args = append(args, leafHubName)
+	}
+	query += " ORDER BY namespace, name, leaf_hub_name"
+	fmt.Fprintf(gin.DefaultWriter, "argocd applicationsets query: %s\n", query)
+
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query argocd applicationsets: %w", err)
+	}
+	defer rows.Close()
+
+	applicationSetList := &ApplicationSetList{Items: []ApplicationSet{}}
+	for rows.Next() {
+		var name, namespace string
+		var leafHubApplicationSet LeafHubApplicationSet
+		var errorMessage *string
+		var updatedAt time.Time
+		if err := rows.Scan(&leafHubApplicationSet.LeafHubName, &name, &namespace,
+			&leafHubApplicationSet.Applications, &leafHubApplicationSet.ResourcesUpToDate, &errorMessage,
+			&updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan argocd applicationset: %w", err)
+		}
+		leafHubApplicationSet.ErrorMessage = stringValue(errorMessage)
+		leafHubApplicationSet.UpdatedAt = &metav1.Time{Time: updatedAt}
+
+		// the rows are ordered by namespace and name, so the leaf hubs of an applicationset are adjacent
+		items := applicationSetList.Items
+		if len(items) == 0 || items[len(items)-1].Namespace != namespace || items[len(items)-1].Name != name {
+			applicationSetList.Items = append(applicationSetList.Items, ApplicationSet{
+				Name:      name,
+				Namespace: namespace,
+				Summary:   newApplicationSummary(),
+				LeafHubs:  []LeafHubApplicationSet{},
+			})
+		}
+		applicationSet := &applicationSetList.Items[len(applicationSetList.Items)-1]
+		applicationSet.LeafHubs = append(applicationSet.LeafHubs, leafHubApplicationSet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query argocd applicationsets: %w", err)
+	}
+
+	applications, err := queryApplications(ctx, dbConnectionPool, &applicationFilter{leafHubName: leafHubName})
+	if err != nil {
+		return nil, err
+	}
+	summarizeApplicationSets(applicationSetList.Items, applications)
+
+	return applicationSetList, nil
+}
+
+// summarizeApplicationSets counts the generated applications of the applicationsets on all the leaf hubs.
+func summarizeApplicationSets(applicationSets []ApplicationSet, applications []Application) {
+	indexes := map[string]int{}
+	for i, applicationSet := range applicationSets {
+		indexes[fmt.Sprintf("%s/%s", applicationSet.Namespace, applicationSet.Name)] = i
+	}
+
+	for _, application := range applications {
+		if application.ApplicationSet == "" {
+			continue
+		}
+		index, found := indexes[fmt.Sprintf("%s/%s", application.Namespace, application.ApplicationSet)]
+		if !found {
+			continue
+		}
+		updateApplicationSummary(&applicationSets[index].Summary, application)
+	}
+}
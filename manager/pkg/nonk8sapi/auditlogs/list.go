@@ -0,0 +1,360 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package auditlogs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultLimit           = 100
+	maxLimit               = 1000
+
+	auditLogsQuery = `SELECT id, user_name, source, operation, kind, name, namespace, resource_id, target_hubs,
+		spec_version, details, created_at FROM history.audit_log`
+	auditLogsOrder = " ORDER BY id"
+)
+
+// AuditLog is a change made by the global hub on the leaf hubs.
+type AuditLog struct {
+	ID          int64           `json:"id"`
+	UserName    string          `json:"userName"`
+	Source      string          `json:"source"`
+	Operation   string          `json:"operation"`
+	Kind        string          `json:"kind"`
+	Name        string          `json:"name"`
+	Namespace   string          `json:"namespace,omitempty"`
+	ResourceID  string          `json:"resourceId,omitempty"`
+	TargetHubs  []string        `json:"targetHubs"`
+	SpecVersion string          `json:"specVersion,omitempty"`
+	Details     json.RawMessage `json:"details,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// AuditLogList is a page of audit logs, the continue token is set if there are more audit logs.
+type AuditLogList struct {
+	Items    []AuditLog `json:"items"`
+	Continue string     `json:"continue,omitempty"`
+}
+
+// auditLogFilter holds the filters of the audit logs query.
+type auditLogFilter struct {
+	userName    string
+	source      string
+	operation   string
+	kind        string
+	name        string
+	namespace   string
+	leafHubName string
+	since       *time.Time
+	until       *time.Time
+}
+
+// auditLogCursor is the position of the last returned audit log, audit logs are ordered by id.
+type auditLogCursor struct {
+	ID int64 `json:"id"`
+}
+
+// ListAuditLogs godoc
+// @summary list audit logs
+// @description list the changes made by the global hub on the leaf hubs, ordered by the time they're made
+// @accept json
+// @produce json
+// @param        user             query     string  false  "list changes requested by the user"
+// @param        source           query     string  false  "source: api or controller"
+// @param        operation        query     string  false  "create, update, delete, patch-labels or decommission"
+// @param        kind             query     string  false  "list changes of the kind"
+// @param        name             query     string  false  "list changes of the resource name"
+// @param        namespace        query     string  false  "list changes of the resource namespace"
+// @param        leafHubName      query     string  false  "list changes targeting the leaf hub"
+// @param        since            query     string  false  "list changes made since the time (RFC3339)"
+// @param        until            query     string  false  "list changes made before the time (RFC3339)"
+// @param        limit            query     int     false  "maximum audit log number to receive"
+// @param        continue         query     string  false  "continue token to request next request"
+// @success      200  {object}    AuditLogList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /auditlogs [get]
+func ListAuditLogs(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		filter, err := parseAuditLogFilter(ginCtx)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var cursor *auditLogCursor
+		if continueToken := ginCtx.Query("continue"); continueToken != "" {
+			if cursor, err = decodeContinue(continueToken); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid continue token: %v", err))
+				return
+			}
+		}
+
+		limit := defaultLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > maxLimit {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("limit should be in the scope [1, %d]", maxLimit))
+				return
+			}
+		}
+
+		auditLogs, err := queryAuditLogs(ginCtx.Request.Context(), dbConnectionPool, filter, cursor, limit+1)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying audit logs: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		auditLogList := &AuditLogList{Items: auditLogs}
+		// there are more audit logs than the limit, return the continue token for the next page
+		if len(auditLogs) > limit {
+			auditLogList.Items = auditLogs[:limit]
+			auditLogList.Continue, err = encodeContinue(&auditLogCursor{ID: auditLogList.Items[limit-1].ID})
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+		}
+
+		ginCtx.JSON(http.StatusOK, auditLogList)
+	}
+}
+
+// ExportAuditLogs godoc
+// @summary export audit logs
+// @description export all the changes made by the global hub on the leaf hubs matching the filters as csv
+// @produce text/csv
+// @param        user             query     string  false  "export changes requested by the user"
+// @param        source           query     string  false  "source: api or controller"
+// @param        operation        query     string  false  "create, update, delete, patch-labels or decommission"
+// @param        kind             query     string  false  "export changes of the kind"
+// @param        name             query     string  false  "export changes of the resource name"
+// @param        namespace        query     string  false  "export changes of the resource namespace"
+// @param        leafHubName      query     string  false  "export changes targeting the leaf hub"
+// @param        since            query     string  false  "export changes made since the time (RFC3339)"
+// @param        until            query     string  false  "export changes made before the time (RFC3339)"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /auditlogs/export [get]
+func ExportAuditLogs(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		filter, err := parseAuditLogFilter(ginCtx)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ginCtx.Header("Content-Type", "text/csv")
+		ginCtx.Header("Content-Disposition",
+			fmt.Sprintf("attachment; filename=audit-log-%s.csv", time.Now().UTC().Format("20060102T150405Z")))
+		ginCtx.Status(http.StatusOK)
+
+		writer := csv.NewWriter(ginCtx.Writer)
+		if err := writer.Write(csvHeader); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in exporting audit logs: %v\n", err)
+			return
+		}
+
+		// the audit logs are exported page by page, so that they aren't loaded into the memory at once
+		var cursor *auditLogCursor
+		for {
+			auditLogs, err := queryAuditLogs(ginCtx.Request.Context(), dbConnectionPool, filter, cursor, maxLimit)
+			if err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "error in exporting audit logs: %v\n", err)
+				return
+			}
+			for i := range auditLogs {
+				if err := writer.Write(toCSVRecord(&auditLogs[i])); err != nil {
+					fmt.Fprintf(gin.DefaultWriter, "error in exporting audit logs: %v\n", err)
+					return
+				}
+			}
+			writer.Flush()
+			if len(auditLogs) < maxLimit {
+				return
+			}
+			cursor = &auditLogCursor{ID: auditLogs[len(auditLogs)-1].ID}
+		}
+	}
+}
+
+var csvHeader = []string{
+	"id", "createdAt", "userName", "source", "operation", "kind", "namespace", "name", "resourceId", "targetHubs",
+	"specVersion", "details",
+}
+
+func toCSVRecord(auditLog *AuditLog) []string {
+	return []string{
+		strconv.FormatInt(auditLog.ID, 10),
+		auditLog.CreatedAt.Format(time.RFC3339Nano),
+		auditLog.UserName,
+		auditLog.Source,
+		auditLog.Operation,
+		auditLog.Kind,
+		auditLog.Namespace,
+		auditLog.Name,
+		auditLog.ResourceID,
+		strings.Join(auditLog.TargetHubs, ";"),
+		auditLog.SpecVersion,
+		string(auditLog.Details),
+	}
+}
+
+func queryAuditLogs(ctx context.Context, dbConnectionPool *pgxpool.Pool, filter *auditLogFilter,
+	cursor *auditLogCursor, limit int,
+) ([]AuditLog, error) {
+	query, args := buildAuditLogsQuery(filter, cursor, limit)
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	auditLogs := []AuditLog{}
+	for rows.Next() {
+		var auditLog AuditLog
+		var namespace, resourceID, specVersion *string
+		var details []byte
+		if err := rows.Scan(&auditLog.ID, &auditLog.UserName, &auditLog.Source, &auditLog.Operation,
+			&auditLog.Kind, &auditLog.Name, &namespace, &resourceID, &auditLog.TargetHubs, &specVersion,
+			&details, &auditLog.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if namespace != nil {
+			auditLog.Namespace = *namespace
+		}
+		if resourceID != nil {
+			auditLog.ResourceID = *resourceID
+		}
+		if specVersion != nil {
+			auditLog.SpecVersion = *specVersion
+		}
+		if len(details) > 0 {
+			auditLog.Details = details
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs, rows.Err()
+}
+
+// buildAuditLogsQuery returns the audit logs query with the positional arguments of the filter and cursor.
+func buildAuditLogsQuery(filter *auditLogFilter, cursor *auditLogCursor, limit int) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.userName != "" {
+		addCondition("user_name = $%d", filter.userName)
+	}
+	if filter.source != "" {
+		addCondition("source = $%d", filter.source)
+	}
+	if filter.operation != "" {
+		addCondition("operation = $%d", filter.operation)
+	}
+	if filter.kind != "" {
+		addCondition("kind = $%d", filter.kind)
+	}
+	if filter.name != "" {
+		addCondition("name = $%d", filter.name)
+	}
+	if filter.namespace != "" {
+		addCondition("namespace = $%d", filter.namespace)
+	}
+	if filter.leafHubName != "" {
+		addCondition("target_hubs ? $%d", filter.leafHubName)
+	}
+	if filter.since != nil {
+		addCondition("created_at >= $%d", *filter.since)
+	}
+	if filter.until != nil {
+		addCondition("created_at < $%d", *filter.until)
+	}
+	if cursor != nil {
+		addCondition("id > $%d", cursor.ID)
+	}
+
+	query := auditLogsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += auditLogsOrder + fmt.Sprintf(" LIMIT %d", limit)
+
+	return query, args
+}
+
+func parseAuditLogFilter(ginCtx *gin.Context) (*auditLogFilter, error) {
+	filter := &auditLogFilter{
+		userName:    ginCtx.Query("user"),
+		source:      ginCtx.Query("source"),
+		operation:   ginCtx.Query("operation"),
+		kind:        ginCtx.Query("kind"),
+		name:        ginCtx.Query("name"),
+		namespace:   ginCtx.Query("namespace"),
+		leafHubName: ginCtx.Query("leafHubName"),
+	}
+
+	for param, timePtr := range map[string]**time.Time{"since": &filter.since, "until": &filter.until} {
+		value := ginCtx.Query(param)
+		if value == "" {
+			continue
+		}
+		parsedTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s time %s, should be in RFC3339 format", param, value)
+		}
+		// the created_at column is a timestamp without time zone in UTC
+		parsedTime = parsedTime.UTC()
+		*timePtr = &parsedTime
+	}
+
+	return filter, nil
+}
+
+func encodeContinue(cursor *auditLogCursor) (string, error) {
+	cursorBytes, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorBytes), nil
+}
+
+func decodeContinue(continueToken string) (*auditLogCursor, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(continueToken)
+	if err != nil {
+		return nil, err
+	}
+	cursor := &auditLogCursor{}
+	if err := json.Unmarshal(cursorBytes, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package auditlogs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAuditLogsQuery(t *testing.T) {
+	query, args := buildAuditLogsQuery(&auditLogFilter{}, nil, 10)
+	assert.NotContains(t, query, "WHERE")
+	assert.True(t, strings.HasSuffix(query, auditLogsOrder+" LIMIT 10"))
+	assert.Empty(t, args)
+
+	since := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	query, args = buildAuditLogsQuery(&auditLogFilter{
+		userName:    "admin",
+		operation:   "patch-labels",
+		leafHubName: "hub1",
+		since:       &since,
+	}, &auditLogCursor{ID: 42}, 100)
+	assert.Contains(t, query,
+		"WHERE user_name = $1 AND operation = $2 AND target_hubs ? $3 AND created_at >= $4 AND id > $5")
+	assert.Equal(t, []interface{}{"admin", "patch-labels", "hub1", since, int64(42)}, args)
+}
+
+func TestContinueToken(t *testing.T) {
+	token, err := encodeContinue(&auditLogCursor{ID: 42})
+	assert.NoError(t, err)
+
+	decoded, err := decodeContinue(token)
+	assert.NoError(t, err)
+	assert.Equal(t, &auditLogCursor{ID: 42}, decoded)
+
+	_, err = decodeContinue("invalid")
+	assert.Error(t, err)
+}
+
+func TestToCSVRecord(t *testing.T) {
+	record := toCSVRecord(&AuditLog{
+		ID:          7,
+		UserName:    "admin",
+		Source:      "api",
+		Operation:   "patch-labels",
+		Kind:        "ManagedCluster",
+		Name:        "cluster1",
+		ResourceID:  "47c9a640-af05-4bea-9dcc-1873e86bebcd",
+		TargetHubs:  []string{"hub1", "hub2"},
+		SpecVersion: "3",
+		Details:     []byte(`{"labelsToAdd":{"env":"prod"}}`),
+		CreatedAt:   time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	assert.Len(t, record, len(csvHeader))
+	assert.Equal(t, []string{
+		"7", "2023-03-01T10:00:00Z", "admin", "api", "patch-labels", "ManagedCluster", "", "cluster1",
+		"47c9a640-af05-4bea-9dcc-1873e86bebcd", "hub1;hub2", "3", `{"labelsToAdd":{"env":"prod"}}`,
+	}, record)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliancereports
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListComplianceReports godoc
+// @summary list compliance report definitions
+// @description list the stored compliance report definitions, ordered by their names
+// @accept json
+// @produce json
+// @success      200  {array}     report.StoredDefinition
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereports [get]
+func ListComplianceReports(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		definitions, err := report.ListDefinitions(ginCtx.Request.Context(), dbConnectionPool, false)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in listing compliance reports: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, definitions)
+	}
+}
+
+// SaveComplianceReport godoc
+// @summary create or replace a compliance report definition
+// @description create the compliance report definition, or replace the definition of the same name, the reports of
+// @description the scheduled definitions are written to the object storage daily, weekly or monthly
+// @accept json
+// @produce json
+// @param        definition       body     report.Definition  true  "the compliance report definition"
+// @success      200  {object}    report.Definition
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereports [post]
+func SaveComplianceReport(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		definition := &report.Definition{}
+		if err := ginCtx.BindJSON(definition); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to bind: %s\n", err.Error())
+			return
+		}
+		definition.Default()
+		if err := definition.Validate(true); err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := report.SaveDefinition(ginCtx.Request.Context(), dbConnectionPool, definition,
+			ginCtx.GetString(authentication.UserKey)); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in saving compliance report: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, definition)
+	}
+}
+
+// DeleteComplianceReport godoc
+// @summary delete a compliance report definition
+// @description delete the compliance report definition, the reports written to the object storage are kept
+// @accept json
+// @produce json
+// @param        name             path     string  true  "the name of the compliance report"
+// @success      200
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereport/{name} [delete]
+func DeleteComplianceReport(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		deleted, err := report.DeleteDefinition(ginCtx.Request.Context(), dbConnectionPool, name)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in deleting compliance report: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if !deleted {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("compliance report %s is not found", name))
+			return
+		}
+
+		ginCtx.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliancereports
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+// currentLabelsWarning is the warning of the reports with the label selectors, the clusters are selected by their
+// current labels, so the compliance in the past days of the clusters relabeled since then is selected by the new labels
+const currentLabelsWarning = `299 - "the clusters are selected by their current labels"`
+
+var contentTypes = map[string]string{
+	report.FormatCSV:       "text/csv",
+	report.FormatJSONLines: "application/x-ndjson",
+}
+
+// ExportComplianceReport godoc
+// @summary export a compliance report
+// @description export the report of the compliance report definition, the format, view and days of the definition
+// @description can be overridden by the query parameters
+// @accept json
+// @produce text/csv,application/x-ndjson
+// @param        name             path     string  true   "the name of the compliance report"
+// @param        format           query    string  false  "csv or jsonl"
+// @param        view             query    string  false  "detail or summary"
+// @param        since            query    string  false  "the first day of the report (2006-01-02)"
+// @param        until            query    string  false  "the last day of the report (2006-01-02), today by default"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereport/{name}/export [get]
+func ExportComplianceReport(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		storedDefinition, err := report.GetDefinition(ginCtx.Request.Context(), dbConnectionPool, name)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in getting compliance report: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if storedDefinition == nil {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("compliance report %s is not found", name))
+			return
+		}
+
+		definition := storedDefinition.Definition
+		definition.Format = ginCtx.DefaultQuery("format", definition.Format)
+		definition.View = ginCtx.DefaultQuery("view", definition.View)
+		exportReport(ginCtx, dbConnectionPool, &definition)
+	}
+}
+
+// ExportComplianceReports godoc
+// @summary export an ad hoc compliance report
+// @description export the compliance of the policies with the standards, categories or controls on the clusters of
+// @description the leaf hubs and labels, the daily compliance of the policies is taken from the history, and the
+// @description current compliance of the global policies is reported on today, the clusters are selected by their
+// @description current labels in all the days of the report
+// @accept json
+// @produce text/csv,application/x-ndjson
+// @param        standard         query    []string  false  "the standards of the policies"  collectionFormat(multi)
+// @param        category         query    []string  false  "the categories of the policies"  collectionFormat(multi)
+// @param        control          query    []string  false  "the controls of the policies"  collectionFormat(multi)
+// @param        leafHubName      query    []string  false  "the leaf hubs of the clusters"  collectionFormat(multi)
+// @param        labelSelector    query    string    false  "the label selector of the clusters"
+// @param        days             query    int       false  "the number of the days up to today, 30 by default"
+// @param        format           query    string    false  "csv or jsonl"
+// @param        view             query    string    false  "detail or summary"
+// @param        since            query    string    false  "the first day of the report (2006-01-02)"
+// @param        until            query    string    false  "the last day of the report (2006-01-02), today by default"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereports/export [get]
+func ExportComplianceReports(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		definition := &report.Definition{
+			Standards:     ginCtx.QueryArray("standard"),
+			Categories:    ginCtx.QueryArray("category"),
+			Controls:      ginCtx.QueryArray("control"),
+			LeafHubNames:  ginCtx.QueryArray("leafHubName"),
+			LabelSelector: ginCtx.Query("labelSelector"),
+			Format:        ginCtx.Query("format"),
+			View:          ginCtx.Query("view"),
+		}
+		if days := ginCtx.Query("days"); days != "" {
+			var err error
+			if definition.Days, err = strconv.Atoi(days); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid days %s", days))
+				return
+			}
+		}
+		exportReport(ginCtx, dbConnectionPool, definition)
+	}
+}
+
+// exportReport streams the report of the definition in the days of the since and until query parameters.
+func exportReport(ginCtx *gin.Context, dbConnectionPool *pgxpool.Pool, definition *report.Definition) {
+	definition.Default()
+	if err := definition.Validate(false); err != nil {
+		ginCtx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	since, until, err := parseDateRange(ginCtx, definition)
+	if err != nil {
+		ginCtx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if definition.LabelSelector != "" {
+		ginCtx.Header("Warning", currentLabelsWarning)
+	}
+	ginCtx.Header("Content-Type", contentTypes[definition.Format])
+	ginCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", definition.FileName(since, until)))
+	ginCtx.Status(http.StatusOK)
+
+	if err := report.Export(ginCtx.Request.Context(), dbConnectionPool, definition, since, until,
+		ginCtx.Writer); err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "error in exporting compliance report: %v\n", err)
+	}
+}
+
+// parseDateRange returns the first and the last days of the report, the last day is today and the first day is
+// decided by the days of the definition by default.
+func parseDateRange(ginCtx *gin.Context, definition *report.Definition) (time.Time, time.Time, error) {
+	since, until := definition.DateRange(time.Now().UTC())
+	if untilStr := ginCtx.Query("until"); untilStr != "" {
+		var err error
+		if until, err = report.ParseDate(untilStr); err != nil {
+			return since, until, err
+		}
+		since, _ = definition.DateRange(until)
+	}
+	if sinceStr := ginCtx.Query("since"); sinceStr != "" {
+		var err error
+		if since, err = report.ParseDate(sinceStr); err != nil {
+			return since, until, err
+		}
+	}
+
+	if until.Before(since) {
+		return since, until, fmt.Errorf("since %s should not be after until %s", since.Format("2006-01-02"),
+			until.Format("2006-01-02"))
+	}
+	if until.Sub(since) >= report.MaxDays*24*time.Hour {
+		return since, until, fmt.Errorf("the report should cover no more than %d days", report.MaxDays)
+	}
+	return since, until, nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package compliancereports
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/report"
+)
+
+func TestParseDateRange(t *testing.T) {
+	definition := &report.Definition{Days: 30}
+	ginContext := func(query string) *gin.Context {
+		ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginCtx.Request = httptest.NewRequest("GET", "/compliancereports/export"+query, nil)
+		return ginCtx
+	}
+
+	since, until, err := parseDateRange(ginContext(""), definition)
+	assert.NoError(t, err)
+	assert.Equal(t, 29*24*time.Hour, until.Sub(since))
+
+	since, until, err = parseDateRange(ginContext("?until=2023-04-30"), definition)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC), until)
+
+	since, until, err = parseDateRange(ginContext("?since=2023-04-10&until=2023-04-20"), definition)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC), until)
+
+	_, _, err = parseDateRange(ginContext("?since=2023-04-21&until=2023-04-20"), definition)
+	assert.Error(t, err)
+	_, _, err = parseDateRange(ginContext("?since=2022-01-01&until=2023-04-20"), definition)
+	assert.Error(t, err)
+	_, _, err = parseDateRange(ginContext("?since=04/10/2023"), definition)
+	assert.Error(t, err)
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultLimit           = 100
+	maxLimit               = 1000
+	syncIntervalInSeconds  = 4
+
+	// the events of the local policies, the managed clusters, the placements and the applications. the sequence is
+	// shared by the event tables and assigned when the event is received, so the events reported late by the leaf
+	// hubs are still streamed to the watchers.
+	eventsQuery = `SELECT seq, event_name, kind, policy_id, resource_id, cluster_id, cluster_name, name, namespace,
+		leaf_hub_name, message, reason, count, compliance, created_at FROM (
+			SELECT seq, event_name, 'Policy' AS kind, policy_id::text, NULL::text AS resource_id,
+				cluster_id::text, NULL::text AS cluster_name, NULL::text AS name, NULL::text AS namespace,
+				leaf_hub_name, message, reason, count, compliance::text, created_at FROM event.local_policies
+			UNION ALL
+			SELECT seq, event_name, 'Policy' AS kind, policy_id::text, NULL::text AS resource_id,
+				NULL::text AS cluster_id, NULL::text AS cluster_name, NULL::text AS name, NULL::text AS namespace,
+				leaf_hub_name, message, reason, count, compliance::text, created_at FROM event.local_root_policies
+			UNION ALL
+			SELECT seq, event_name, 'ManagedCluster' AS kind, NULL::text AS policy_id, NULL::text AS resource_id,
+				cluster_id::text, cluster_name::text, cluster_name::text AS name, NULL::text AS namespace,
+				leaf_hub_name, message, reason, count, NULL::text AS compliance, created_at
+				FROM event.managed_clusters
+			UNION ALL
+			SELECT seq, event_name, kind::text, NULL::text AS policy_id, resource_id::text,
+				NULL::text AS cluster_id, NULL::text AS cluster_name, name::text, namespace::text, leaf_hub_name,
+				message, reason, count, NULL::text AS compliance, created_at FROM event.placements
+			UNION ALL
+			SELECT seq, event_name, kind::text, NULL::text AS policy_id, resource_id::text,
+				NULL::text AS cluster_id, NULL::text AS cluster_name, name::text, namespace::text, leaf_hub_name,
+				message, reason, count, NULL::text AS compliance, created_at FROM event.applications
+		) AS events`
+	eventsOrder = " ORDER BY seq"
+
+	clusterNameCondition = `(cluster_name = $%[1]d OR cluster_id IN (
+		SELECT cluster_id::text FROM status.managed_clusters WHERE cluster_name = $%[1]d))`
+
+	// the last sequence assigned to the events, the watchers without a cursor start from it
+	lastSequenceQuery = "SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM event.events_seq"
+)
+
+// Event is an event reported by the leaf hubs, of a policy, a managed cluster, a placement or an application. the
+// sequence is the order in which the events are received.
+type Event struct {
+	Sequence    int64     `json:"sequence"`
+	EventName   string    `json:"eventName"`
+	Kind        string    `json:"kind"`
+	PolicyID    string    `json:"policyId,omitempty"`
+	ResourceID  string    `json:"resourceId,omitempty"`
+	ClusterID   string    `json:"clusterId,omitempty"`
+	ClusterName string    `json:"clusterName,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	LeafHubName string    `json:"leafHubName"`
+	Message     string    `json:"message,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Count       int       `json:"count"`
+	Compliance  string    `json:"compliance,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// EventList is a page of events, the continue token is set if there are more events.
+type EventList struct {
+	Items    []Event `json:"items"`
+	Continue string  `json:"continue,omitempty"`
+}
+
+// eventFilter holds the filters of the events query.
+type eventFilter struct {
+	leafHubName string
+	kind        string
+	clusterID   string
+	clusterName string
+	policyID    string
+	resourceID  string
+	reason      string
+	compliance  string
+	since       *time.Time
+	until       *time.Time
+}
+
+// eventCursor is the position of the last returned event, events are ordered by the sequence.
+type eventCursor struct {
+	Sequence int64 `json:"sequence"`
+}
+
+// ListEvents godoc
+// @summary list events
+// @description list the events of the policies, managed clusters, placements and applications of all the leaf hubs,
+// @description ordered by the time they're received
+// @accept json
+// @produce json
+// @param        leafHubName      query     string  false  "list events of the leaf hub"
+// @param        kind             query     string  false  "list events of the kind, e.g. Policy, ManagedCluster"
+// @param        clusterID        query     string  false  "list events of the managed cluster"
+// @param        clusterName      query     string  false  "list events of the managed cluster with the name"
+// @param        policyID         query     string  false  "list events of the policy"
+// @param        resourceID       query     string  false  "list events of the global placement or application"
+// @param        reason           query     string  false  "list events with the reason"
+// @param        compliance       query     string  false  "compliance: compliant, non_compliant or unknown"
+// @param        since            query     string  false  "list events created since the time (RFC3339)"
+// @param        until            query     string  false  "list events created before the time (RFC3339)"
+// @param        limit            query     int     false  "maximum event number to receive"
+// @param        continue         query     string  false  "continue token to request next request"
+// @param        watch            query     bool    false  "stream the new events as server-sent events"
+// @success      200  {object}    EventList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /events [get]
+func ListEvents(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		filter, err := parseEventFilter(ginCtx)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var cursor *eventCursor
+		if continueToken := ginCtx.Query("continue"); continueToken != "" {
+			if cursor, err = decodeContinue(continueToken); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid continue token: %v", err))
+				return
+			}
+		}
+
+		limit := defaultLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > maxLimit {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("limit should be in the scope [1, %d]", maxLimit))
+				return
+			}
+		}
+
+		if _, watch := ginCtx.GetQuery("watch"); watch {
+			handleEventsForWatch(ginCtx, dbConnectionPool, filter, cursor)
+			return
+		}
+
+		events, err := queryEvents(ginCtx.Request.Context(), dbConnectionPool, filter, cursor, limit+1)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying events: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		eventList := &EventList{Items: events}
+		// there are more events than the limit, return the continue token for the next page
+		if len(events) > limit {
+			eventList.Items = events[:limit]
+			lastEvent := eventList.Items[limit-1]
+			eventList.Continue, err = encodeContinue(&eventCursor{Sequence: lastEvent.Sequence})
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+		}
+
+		ginCtx.JSON(http.StatusOK, eventList)
+	}
+}
+
+// handleEventsForWatch streams the events created after the cursor as server-sent events.
+func handleEventsForWatch(ginCtx *gin.Context, dbConnectionPool *pgxpool.Pool, filter *eventFilter,
+	cursor *eventCursor,
+) {
+	// only stream the new events if neither continue token nor since is specified
+	if cursor == nil && filter.since == nil {
+		cursor = &eventCursor{}
+		if err := dbConnectionPool.QueryRow(ginCtx.Request.Context(), lastSequenceQuery).Scan(
+			&cursor.Sequence); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying the last event sequence: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+	}
+
+	ginCtx.Header("Content-Type", "text/event-stream")
+	ginCtx.Header("Cache-Control", "no-cache")
+	ginCtx.Header("Connection", "keep-alive")
+
+	ticker := time.NewTicker(syncIntervalInSeconds * time.Second)
+	defer ticker.Stop()
+
+	ginCtx.Stream(func(writer io.Writer) bool {
+		events, err := queryEvents(ginCtx.Request.Context(), dbConnectionPool, filter, cursor, maxLimit)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying events: %v\n", err)
+			return false
+		}
+		for _, event := range events {
+			ginCtx.SSEvent("event", event)
+		}
+		if len(events) > 0 {
+			lastEvent := events[len(events)-1]
+			cursor = &eventCursor{Sequence: lastEvent.Sequence}
+			// more events are pending, query them immediately
+			if len(events) == maxLimit {
+				return true
+			}
+		}
+
+		select {
+		case <-ginCtx.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			return true
+		}
+	})
+}
+
+func queryEvents(ctx context.Context, dbConnectionPool *pgxpool.Pool, filter *eventFilter,
+	cursor *eventCursor, limit int,
+) ([]Event, error) {
+	query, args := buildEventsQuery(filter, cursor, limit)
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		var policyID, resourceID, clusterID, clusterName, name, namespace, message, reason, compliance *string
+		if err := rows.Scan(&event.Sequence, &event.EventName, &event.Kind, &policyID, &resourceID, &clusterID,
+			&clusterName, &name, &namespace, &event.LeafHubName, &message, &reason, &event.Count, &compliance,
+			&event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		event.PolicyID, event.ResourceID = stringValue(policyID), stringValue(resourceID)
+		event.ClusterID, event.ClusterName = stringValue(clusterID), stringValue(clusterName)
+		event.Name, event.Namespace = stringValue(name), stringValue(namespace)
+		event.Message, event.Reason = stringValue(message), stringValue(reason)
+		event.Compliance = stringValue(compliance)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// stringValue returns the value of the nullable column, or empty string if it's null.
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// buildEventsQuery returns the events query with the positional arguments of the filter and cursor.
+func buildEventsQuery(filter *eventFilter, cursor *eventCursor, limit int) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.leafHubName != "" {
+		addCondition("leaf_hub_name = $%d", filter.leafHubName)
+	}
+	if filter.kind != "" {
+		addCondition("kind = $%d", filter.kind)
+	}
+	if filter.clusterID != "" {
+		addCondition("cluster_id = $%d", filter.clusterID)
+	}
+	if filter.clusterName != "" {
+		// the events of the local policies only have the cluster id, so the name is resolved to the ids of the
+		// clusters with the name
+		addCondition(clusterNameCondition, filter.clusterName)
+	}
+	if filter.policyID != "" {
+		addCondition("policy_id = $%d", filter.policyID)
+	}
+	if filter.resourceID != "" {
+		addCondition("resource_id = $%d", filter.resourceID)
+	}
+	if filter.reason != "" {
+		addCondition("reason = $%d", filter.reason)
+	}
+	if filter.compliance != "" {
+		addCondition("compliance = $%d", filter.compliance)
+	}
+	if filter.since != nil {
+		addCondition("created_at >= $%d", *filter.since)
+	}
+	if filter.until != nil {
+		addCondition("created_at < $%d", *filter.until)
+	}
+	if cursor != nil {
+		addCondition("seq > $%d", cursor.Sequence)
+	}
+
+	query := eventsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += eventsOrder + fmt.Sprintf(" LIMIT %d", limit)
+
+	return query, args
+}
+
+func parseEventFilter(ginCtx *gin.Context) (*eventFilter, error) {
+	filter := &eventFilter{
+		leafHubName: ginCtx.Query("leafHubName"),
+		kind:        ginCtx.Query("kind"),
+		clusterID:   ginCtx.Query("clusterID"),
+		clusterName: ginCtx.Query("clusterName"),
+		policyID:    ginCtx.Query("policyID"),
+		resourceID:  ginCtx.Query("resourceID"),
+		reason:      ginCtx.Query("reason"),
+		compliance:  ginCtx.Query("compliance"),
+	}
+
+	for param, timePtr := range map[string]**time.Time{"since": &filter.since, "until": &filter.until} {
+		value := ginCtx.Query(param)
+		if value == "" {
+			continue
+		}
+		parsedTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s time %s, should be in RFC3339 format", param, value)
+		}
+		// the created_at column is a timestamp without time zone in UTC
+		parsedTime = parsedTime.UTC()
+		*timePtr = &parsedTime
+	}
+
+	return filter, nil
+}
+
+func encodeContinue(cursor *eventCursor) (string, error) {
+	cursorBytes, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorBytes), nil
+}
+
+func decodeContinue(continueToken string) (*eventCursor, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(continueToken)
+	if err != nil {
+		return nil, err
+	}
+	cursor := &eventCursor{}
+	if err := json.Unmarshal(cursorBytes, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
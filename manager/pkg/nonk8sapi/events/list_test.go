@@ -0,0 +1,64 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEventsQuery(t *testing.T) {
+	query, args := buildEventsQuery(&eventFilter{}, nil, 10)
+	assert.NotContains(t, query, "WHERE")
+	assert.True(t, strings.HasSuffix(query, eventsOrder+" LIMIT 10"))
+	assert.Empty(t, args)
+
+	since := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	cursor := &eventCursor{Sequence: 42}
+	query, args = buildEventsQuery(&eventFilter{
+		leafHubName: "hub1",
+		kind:        "ManagedCluster",
+		clusterID:   "47c9a640-af05-4bea-9dcc-1873e86bebcd",
+		since:       &since,
+	}, cursor, 100)
+	assert.Contains(t, query,
+		"WHERE leaf_hub_name = $1 AND kind = $2 AND cluster_id = $3 AND created_at >= $4 AND seq > $5")
+	assert.Equal(t, []interface{}{
+		"hub1", "ManagedCluster", "47c9a640-af05-4bea-9dcc-1873e86bebcd", since, int64(42),
+	}, args)
+
+	// the events of all the event tables are listed
+	for _, table := range []string{
+		"event.local_policies", "event.local_root_policies", "event.managed_clusters", "event.placements",
+		"event.applications",
+	} {
+		assert.Contains(t, query, table)
+	}
+
+	// the cluster name matches the events with the name or the id of the clusters with the name
+	query, args = buildEventsQuery(&eventFilter{clusterName: "cluster1"}, nil, 10)
+	assert.Contains(t, query, "WHERE (cluster_name = $1 OR cluster_id IN (")
+	assert.Contains(t, query, "FROM status.managed_clusters WHERE cluster_name = $1))")
+	assert.Equal(t, []interface{}{"cluster1"}, args)
+
+	query, args = buildEventsQuery(&eventFilter{resourceID: "37c9a640-af05-4bea-9dcc-1873e86bebcd"}, nil, 10)
+	assert.Contains(t, query, "WHERE resource_id = $1")
+	assert.Equal(t, []interface{}{"37c9a640-af05-4bea-9dcc-1873e86bebcd"}, args)
+}
+
+func TestContinueToken(t *testing.T) {
+	cursor := &eventCursor{Sequence: 1024}
+	token, err := encodeContinue(cursor)
+	assert.NoError(t, err)
+
+	decoded, err := decodeContinue(token)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = decodeContinue("invalid")
+	assert.Error(t, err)
+}
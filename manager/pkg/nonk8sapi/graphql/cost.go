@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// maxQueryDepth is the maximum nesting of the object fields in a query
+	maxQueryDepth = 5
+	// maxQueryCost is the maximum number of rows a query may fetch, the list fields fetch up to their limits of
+	// rows for each of their parent rows.
+	maxQueryCost = 50000
+)
+
+// costAnalyzer estimates the rows fetched by a query before it's executed
+type costAnalyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkQueryCost returns an error if the operation of the validated document is too deep or may fetch too many rows.
+func checkQueryCost(schema gql.Schema, document *ast.Document, operationName string,
+	variables map[string]interface{},
+) error {
+	analyzer := &costAnalyzer{
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			analyzer.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		}
+	}
+	if operation == nil {
+		return fmt.Errorf("unknown operation %s", operationName)
+	}
+
+	cost, err := analyzer.selectionSetCost(schema.QueryType(), operation.SelectionSet, 0)
+	if err != nil {
+		return err
+	}
+	if cost > maxQueryCost {
+		return fmt.Errorf("the query may fetch %d rows, which exceeds the limit %d, reduce the limits of the lists",
+			cost, maxQueryCost)
+	}
+	return nil
+}
+
+// selectionSetCost returns the rows fetched by the selection set of the object, the depth is the nesting of the
+// object.
+func (a *costAnalyzer) selectionSetCost(object *gql.Object, selectionSet *ast.SelectionSet, depth int) (int, error) {
+	if selectionSet == nil {
+		return 0, nil
+	}
+
+	cost := 0
+	for _, selection := range selectionSet.Selections {
+		var selectionCost int
+		var err error
+		switch s := selection.(type) {
+		case *ast.Field:
+			selectionCost, err = a.fieldCost(object, s, depth)
+		case *ast.InlineFragment:
+			selectionCost, err = a.selectionSetCost(object, s.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if fragment, found := a.fragments[s.Name.Value]; found {
+				selectionCost, err = a.selectionSetCost(object, fragment.SelectionSet, depth)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		cost += selectionCost
+		// stop early to not overflow
+		if cost > maxQueryCost {
+			return cost, nil
+		}
+	}
+	return cost, nil
+}
+
+// fieldCost returns the rows fetched by the field, the list fields fetch up to their limits of rows and the other
+// object fields fetch one row.
+func (a *costAnalyzer) fieldCost(object *gql.Object, field *ast.Field, depth int) (int, error) {
+	if field.SelectionSet == nil || strings.HasPrefix(field.Name.Value, "__") {
+		return 0, nil
+	}
+	fieldDefinition, found := object.Fields()[field.Name.Value]
+	if !found {
+		return 0, nil
+	}
+	if depth+1 > maxQueryDepth {
+		return 0, fmt.Errorf("the query exceeds the maximum depth %d", maxQueryDepth)
+	}
+
+	rows := 1
+	fieldType := unwrapNonNull(fieldDefinition.Type)
+	if list, ok := fieldType.(*gql.List); ok {
+		rows = a.limit(field)
+		fieldType = unwrapNonNull(list.OfType)
+	}
+	child, ok := fieldType.(*gql.Object)
+	if !ok {
+		return 0, nil
+	}
+
+	childCost, err := a.selectionSetCost(child, field.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+	return rows * (1 + childCost), nil
+}
+
+// limit returns the limit argument of the list field, the values out of the scope are rejected by the resolvers.
+func (a *costAnalyzer) limit(field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if limit, err := strconv.Atoi(value.Value); err == nil && limit > 0 && limit <= maxLimit {
+				return limit
+			}
+		case *ast.Variable:
+			switch limit := a.variables[value.Name.Value].(type) {
+			case float64:
+				if limit > 0 && limit <= maxLimit {
+					return int(limit)
+				}
+			case int:
+				if limit > 0 && limit <= maxLimit {
+					return limit
+				}
+			}
+		}
+		return maxLimit
+	}
+	return defaultLimit
+}
+
+func unwrapNonNull(fieldType gql.Type) gql.Type {
+	if nonNull, ok := fieldType.(*gql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return fieldType
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"testing"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTables() []*table {
+	return []*table{
+		{schema: "spec", name: "policies", columns: []*column{
+			{name: "id", sqlType: "uuid", scalar: gql.String},
+			{name: "payload", sqlType: "jsonb", scalar: jsonScalar},
+			{name: "deleted", sqlType: "boolean", scalar: gql.Boolean},
+		}},
+		{schema: "status", name: "compliance", columns: []*column{
+			{name: "policy_id", sqlType: "uuid", scalar: gql.String},
+			{name: "cluster_name", sqlType: "character varying", scalar: gql.String},
+			{name: "leaf_hub_name", sqlType: "character varying", scalar: gql.String},
+			{name: "compliance", sqlType: "status.compliance_type", scalar: gql.String},
+			{name: "cluster_id", sqlType: "uuid", scalar: gql.String},
+		}},
+		{schema: "status", name: "leaf_hubs", columns: []*column{
+			{name: "leaf_hub_name", sqlType: "character varying", scalar: gql.String},
+			{name: "console_url", sqlType: "text", scalar: gql.String},
+		}},
+		{schema: "status", name: "managed_clusters", columns: []*column{
+			{name: "leaf_hub_name", sqlType: "character varying", scalar: gql.String},
+			{name: "cluster_id", sqlType: "uuid", scalar: gql.String},
+		}},
+		{schema: "local_status", name: "compliance", columns: []*column{
+			{name: "policy_id", sqlType: "uuid", scalar: gql.String},
+		}},
+		{schema: "history", name: "audit_log", columns: []*column{
+			{name: "id", sqlType: "bigint", scalar: gql.Float},
+		}},
+	}
+}
+
+func TestGetRelations(t *testing.T) {
+	relations := getRelations(testTables())
+
+	fieldNames := func(typeName string) map[string]string {
+		names := map[string]string{}
+		for _, r := range relations[typeName] {
+			names[r.fieldName] = r.target.typeName()
+		}
+		return names
+	}
+	assert.Equal(t, map[string]string{
+		"policy":          "spec_policies",
+		"leaf_hub":        "status_leaf_hubs",
+		"managed_cluster": "status_managed_clusters",
+	}, fieldNames("status_compliance"))
+	assert.Equal(t, map[string]string{"status_compliance": "status_compliance"}, fieldNames("spec_policies"))
+	assert.Equal(t, map[string]string{
+		"status_compliance":       "status_compliance",
+		"status_managed_clusters": "status_managed_clusters",
+	}, fieldNames("status_leaf_hubs"))
+	// there is no local_spec.policies table to refer to
+	assert.Empty(t, fieldNames("local_status_compliance"))
+	assert.Empty(t, fieldNames("history_audit_log"))
+}
+
+func TestNewRowsQuery(t *testing.T) {
+	tables := testTables()
+
+	query, err := newRowsQuery(tables[1], map[string]interface{}{})
+	require.NoError(t, err)
+	sql, args := query.sql()
+	assert.Equal(t, `SELECT json_build_object('policy_id', t."policy_id", 'cluster_name', t."cluster_name", `+
+		`'leaf_hub_name', t."leaf_hub_name", 'compliance', t."compliance", 'cluster_id', t."cluster_id") `+
+		`FROM "status"."compliance" AS t ORDER BY t."policy_id" LIMIT 100 OFFSET 0`, sql)
+	assert.Empty(t, args)
+
+	query, err = newRowsQuery(tables[1], map[string]interface{}{
+		"where": map[string]interface{}{
+			"compliance":    map[string]interface{}{"eq": "non_compliant"},
+			"leaf_hub_name": map[string]interface{}{"in": []interface{}{"hub1", "hub2"}, "like": "hub%"},
+			"cluster_id":    map[string]interface{}{"isNull": false},
+		},
+		"orderBy": "cluster_name",
+		"desc":    true,
+		"limit":   10,
+		"offset":  20,
+	})
+	require.NoError(t, err)
+	sql, args = query.sql()
+	assert.Contains(t, sql, `FROM "status"."compliance" AS t WHERE `+
+		`t."leaf_hub_name" = ANY(CAST($1::text[] AS character varying[])) AND t."leaf_hub_name"::text LIKE $2 AND `+
+		`t."compliance" = CAST($3::text AS status.compliance_type) AND t."cluster_id" IS NOT NULL `+
+		`ORDER BY t."cluster_name" DESC LIMIT 10 OFFSET 20`)
+	assert.Equal(t, []interface{}{[]string{"hub1", "hub2"}, "hub%", "non_compliant"}, args)
+
+	query, err = newRowsQuery(tables[0], map[string]interface{}{
+		"where": map[string]interface{}{
+			"payload": map[string]interface{}{"contains": map[string]interface{}{"kind": "Policy"}, "hasKey": "spec"},
+		},
+	})
+	require.NoError(t, err)
+	sql, args = query.sql()
+	assert.Contains(t, sql, `WHERE to_jsonb(t."payload") @> $1::jsonb AND to_jsonb(t."payload") ? $2`)
+	assert.Equal(t, []interface{}{`{"kind":"Policy"}`, "spec"}, args)
+
+	query, err = newRowsQuery(tables[5], map[string]interface{}{
+		"where": map[string]interface{}{"id": map[string]interface{}{"gt": float64(1000000)}},
+	})
+	require.NoError(t, err)
+	sql, args = query.sql()
+	assert.Contains(t, sql, `WHERE t."id" > CAST($1::text AS bigint)`)
+	assert.Equal(t, []interface{}{"1000000"}, args)
+
+	_, err = newRowsQuery(tables[1], map[string]interface{}{"limit": maxLimit + 1})
+	assert.Error(t, err)
+	_, err = newRowsQuery(tables[1], map[string]interface{}{"offset": -1})
+	assert.Error(t, err)
+	_, err = newRowsQuery(tables[1], map[string]interface{}{"offset": maxOffset + 1})
+	assert.Error(t, err)
+}
+
+func TestRelatedRowsBatch(t *testing.T) {
+	tables := testTables()
+	query, err := newRowsQuery(tables[1], map[string]interface{}{
+		"where":   map[string]interface{}{"compliance": map[string]interface{}{"eq": "non_compliant"}},
+		"orderBy": "cluster_name",
+		"limit":   10,
+	})
+	require.NoError(t, err)
+
+	batch := &relatedRowsBatch{
+		query:      query,
+		column:     tables[1].column("policy_id"),
+		pendingSet: map[string]struct{}{},
+		rows:       map[string][]interface{}{"p0": {}},
+	}
+	// the loaded and the pending values aren't added again
+	batch.add("p1")
+	batch.add("p2")
+	batch.add("p1")
+	batch.add("p0")
+	assert.Equal(t, []string{"p1", "p2"}, batch.pending)
+
+	sql, args := batch.sql(batch.pending)
+	assert.Equal(t, `SELECT k.key, row_to_json(t) FROM unnest($2::text[]) WITH ORDINALITY AS k(key, ord) `+
+		`CROSS JOIN LATERAL (SELECT t."policy_id", t."cluster_name", t."leaf_hub_name", t."compliance", `+
+		`t."cluster_id" FROM "status"."compliance" AS t WHERE `+
+		`t."compliance" = CAST($1::text AS status.compliance_type) AND t."policy_id" = CAST(k.key AS uuid) `+
+		`ORDER BY t."cluster_name" LIMIT 10 OFFSET 0) AS t ORDER BY k.ord, t."cluster_name"`, sql)
+	assert.Equal(t, []interface{}{"non_compliant", []string{"p1", "p2"}}, args)
+	// the conditions of the query shared by the batches aren't changed
+	assert.Len(t, query.conditions, 1)
+}
+
+func TestExposedTables(t *testing.T) {
+	resources := &table{schema: "spec", name: "resources"}
+	for _, name := range []string{"id", "api_group", "api_version", "kind", "payload", "created_at", "updated_at",
+		"deleted"} {
+		if isColumnExposed(resources.schema, resources.name, name) {
+			resources.columns = append(resources.columns, &column{name: name, sqlType: "text", scalar: gql.String})
+		}
+	}
+	assert.Nil(t, resources.column("payload"))
+	assert.NotNil(t, resources.column("kind"))
+	assert.False(t, isColumnExposed("history", "resources", "payload"))
+	assert.False(t, isColumnExposed("spec", "rollouts", "stable_payload"))
+	assert.True(t, isColumnExposed("spec", "policies", "payload"))
+	// the tables which aren't listed aren't exposed
+	assert.False(t, isColumnExposed("report", "compliance_reports", "name"))
+
+	schema, err := buildSchema(append(testTables(), resources))
+	require.NoError(t, err)
+
+	// the payload of the spec resources can't be selected, filtered or ordered by
+	for _, query := range []string{
+		`{ spec_resources { payload } }`,
+		`{ spec_resources(where: {payload: {hasKey: "data"}}) { id } }`,
+		`{ spec_resources(orderBy: payload) { id } }`,
+	} {
+		document, err := parser.Parse(parser.ParseParams{Source: query})
+		require.NoError(t, err)
+		assert.False(t, gql.ValidateDocument(&schema, document, nil).IsValid, query)
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: `{ spec_resources { id kind } }`})
+	require.NoError(t, err)
+	assert.True(t, gql.ValidateDocument(&schema, document, nil).IsValid)
+
+	// the rows are selected by the exposed columns only
+	query, err := newRowsQuery(resources, map[string]interface{}{})
+	require.NoError(t, err)
+	sql, _ := query.sql()
+	assert.NotContains(t, sql, "payload")
+}
+
+func TestCheckQueryCost(t *testing.T) {
+	schema, err := buildSchema(testTables())
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		valid     bool
+	}{
+		{
+			name: "policies with their non compliant clusters",
+			query: `{
+				spec_policies(limit: 50) {
+					id
+					status_compliance(where: {compliance: {eq: "non_compliant"}}) {
+						cluster_name
+						leaf_hub { console_url }
+					}
+				}
+			}`,
+			valid: true,
+		},
+		{
+			name:  "too many rows",
+			query: `{ spec_policies(limit: 1000) { status_compliance(limit: 1000) { cluster_name } } }`,
+			valid: false,
+		},
+		{
+			name: "too many rows by the variables",
+			query: `query compliance($limit: Int) {
+				spec_policies(limit: $limit) { status_compliance(limit: $limit) { cluster_name } }
+			}`,
+			variables: map[string]interface{}{"limit": float64(500)},
+			valid:     false,
+		},
+		{
+			name: "too many rows by the fragments",
+			query: `{ spec_policies(limit: 1000) { ...compliance } }
+			fragment compliance on spec_policies { status_compliance(limit: 1000) { cluster_name } }`,
+			valid: false,
+		},
+		{
+			name: "too deep",
+			query: `{ status_leaf_hubs(limit: 1) { status_compliance(limit: 1) { leaf_hub {
+				status_compliance(limit: 1) { leaf_hub { status_compliance(limit: 1) { cluster_name } } } } } } }`,
+			valid: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			document, err := parser.Parse(parser.ParseParams{Source: c.query})
+			require.NoError(t, err)
+			require.True(t, gql.ValidateDocument(&schema, document, nil).IsValid)
+
+			err = checkQueryCost(schema, document, "", c.variables)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
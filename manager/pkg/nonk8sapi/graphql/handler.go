@@ -0,0 +1,179 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	schemaUnavailableMsg   = "the graphql schema is not available"
+	statementTimeoutQuery  = "SET LOCAL statement_timeout = 30000"
+	sourceName             = "GraphQL request"
+	// requestTimeout is the time limit of all the statements of a request, each of them is limited by the statement
+	// timeout
+	requestTimeout = 60 * time.Second
+	// schemaRefreshInterval is the interval to rebuild the schema, so that the tables and columns added since are
+	// exposed
+	schemaRefreshInterval = 5 * time.Minute
+)
+
+// Request is the graphql request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// schemaLoader builds the graphql schema from the tables on the first request, it retries on the next request if the
+// tables are not available yet. the schema is rebuilt on the first request after the refresh interval, the previous
+// schema is kept if it fails.
+type schemaLoader struct {
+	mutex            sync.Mutex
+	dbConnectionPool *pgxpool.Pool
+	schema           *gql.Schema
+	loadedAt         time.Time
+}
+
+func (l *schemaLoader) getSchema(ctx context.Context) (*gql.Schema, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.schema != nil && time.Since(l.loadedAt) < schemaRefreshInterval {
+		return l.schema, nil
+	}
+	schema, err := l.loadSchema(ctx)
+	if err != nil {
+		if l.schema == nil {
+			return nil, err
+		}
+		fmt.Fprintf(gin.DefaultWriter, "error in refreshing the graphql schema, keep the previous one: %v\n", err)
+		l.loadedAt = time.Now()
+		return l.schema, nil
+	}
+	l.schema = schema
+	l.loadedAt = time.Now()
+	return l.schema, nil
+}
+
+func (l *schemaLoader) loadSchema(ctx context.Context) (*gql.Schema, error) {
+	tables, err := loadTables(ctx, l.dbConnectionPool)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := buildSchema(tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the graphql schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// Query godoc
+// @summary query the global hub data model
+// @description query the tables of the status, spec, local_spec, local_status, event and history schemas with
+// @description graphql, each table is a "<schema>_<table>" list field with the where, orderBy, desc, limit and offset
+// @description arguments, the rows refer to the leaf_hub, managed_cluster and policy rows by the leaf_hub_name,
+// @description cluster_id and policy_id columns, and the referred rows list the rows referring to them.
+// @description the queries are read only, deeper than 5 levels or fetching more than 50000 rows are rejected, and
+// @description the queries running longer than 60 seconds are canceled.
+// @accept json
+// @produce json
+// @param        request          body     Request  true  "the graphql query"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /graphql [post]
+func Query(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	loader := &schemaLoader{dbConnectionPool: dbConnectionPool}
+
+	return func(ginCtx *gin.Context) {
+		request := &Request{}
+		if err := ginCtx.BindJSON(request); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to bind: %s\n", err.Error())
+			return
+		}
+		if request.Query == "" {
+			ginCtx.String(http.StatusBadRequest, "query is required")
+			return
+		}
+
+		schema, err := loader.getSchema(ginCtx.Request.Context())
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in loading the graphql schema: %v\n", err)
+			ginCtx.String(http.StatusServiceUnavailable, schemaUnavailableMsg)
+			return
+		}
+
+		document, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{
+				Body: []byte(request.Query),
+				Name: sourceName,
+			}),
+		})
+		if err != nil {
+			ginCtx.JSON(http.StatusBadRequest, &gql.Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+		if validation := gql.ValidateDocument(schema, document, nil); !validation.IsValid {
+			ginCtx.JSON(http.StatusBadRequest, &gql.Result{Errors: validation.Errors})
+			return
+		}
+		if err := checkQueryCost(*schema, document, request.OperationName, request.Variables); err != nil {
+			ginCtx.JSON(http.StatusBadRequest, &gql.Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), requestTimeout)
+		defer cancel()
+
+		tx, err := dbConnectionPool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in beginning the graphql transaction: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback(context.Background())
+		}()
+		if _, err := tx.Exec(ctx, statementTimeoutQuery); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in setting the graphql statement timeout: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		// the resolvers are executed serially, so they share the transaction
+		result := gql.Execute(gql.ExecuteParams{
+			Schema:        *schema,
+			AST:           document,
+			OperationName: request.OperationName,
+			Args:          request.Variables,
+			Context:       withQueryContext(ctx, tx),
+		})
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			ginCtx.JSON(http.StatusServiceUnavailable, &gql.Result{Errors: gqlerrors.FormatErrors(
+				fmt.Errorf("the query exceeds the time limit %s, reduce the limits of the lists", requestTimeout))})
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// relatedRowsBatch is the query of the rows of a relation target by the values of the source rows, the fields of the
+// source rows return thunks that are called once the fields of all the source rows in the same level are resolved,
+// so the first of them fetches the rows of all the pending values by a single query.
+type relatedRowsBatch struct {
+	query      *rowsQuery
+	column     *column
+	pending    []string
+	pendingSet map[string]struct{}
+	rows       map[string][]interface{}
+	err        error
+}
+
+// loadRelatedRows adds the value of the source row to the batch of the relation target query, it returns the function
+// to get the rows referring to the value, which loads the pending values of the batch on the first call.
+func loadRelatedRows(ctx context.Context, r *relation, query *rowsQuery, value interface{},
+) (func() ([]interface{}, error), error) {
+	queryCtx, err := getQueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := toText(value)
+	if err != nil {
+		return nil, err
+	}
+	targetColumn := r.target.column(r.targetColumn)
+	if targetColumn == nil {
+		return nil, fmt.Errorf("unknown column %s of %s", r.targetColumn, r.target.typeName())
+	}
+
+	// the same field of the source rows shares the batch if the arguments of the field are the same
+	sql, args := query.sql()
+	batchKey := fmt.Sprintf("%s/%s/%s/%v", r.target.typeName(), r.targetColumn, sql, args)
+	batch, found := queryCtx.batches[batchKey]
+	if !found {
+		batch = &relatedRowsBatch{
+			query:      query,
+			column:     targetColumn,
+			pendingSet: map[string]struct{}{},
+			rows:       map[string][]interface{}{},
+		}
+		queryCtx.batches[batchKey] = batch
+	}
+	batch.add(key)
+
+	return func() ([]interface{}, error) {
+		if _, loaded := batch.rows[key]; !loaded && batch.err == nil {
+			batch.err = batch.load(ctx, queryCtx.tx)
+		}
+		if batch.err != nil {
+			return nil, batch.err
+		}
+		return batch.rows[key], nil
+	}, nil
+}
+
+func (b *relatedRowsBatch) add(key string) {
+	if _, loaded := b.rows[key]; loaded {
+		return
+	}
+	if _, found := b.pendingSet[key]; found {
+		return
+	}
+	b.pending = append(b.pending, key)
+	b.pendingSet[key] = struct{}{}
+}
+
+// load fetches the rows of the pending values
+func (b *relatedRowsBatch) load(ctx context.Context, tx pgx.Tx) error {
+	keys := b.pending
+	b.pending = nil
+	b.pendingSet = map[string]struct{}{}
+
+	sql, args := b.sql(keys)
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", b.query.table.typeName(), err)
+	}
+	defer rows.Close()
+
+	for _, key := range keys {
+		b.rows[key] = []interface{}{}
+	}
+	for rows.Next() {
+		var key string
+		var row map[string]interface{}
+		if err := rows.Scan(&key, &row); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", b.query.table.typeName(), err)
+		}
+		b.rows[key] = append(b.rows[key], row)
+	}
+
+	return rows.Err()
+}
+
+// sql returns the query of the rows referring to each of the values, the conditions, the order, the limit and the
+// offset of the query are applied to the rows of each value.
+func (b *relatedRowsBatch) sql(keys []string) (string, []interface{}) {
+	query := *b.query
+	query.conditions = append(append([]string{}, b.query.conditions...),
+		fmt.Sprintf("%s = CAST(k.key AS %s)", columnIdentifier(b.column), b.column.sqlType))
+	args := append(append([]interface{}{}, b.query.args...), keys)
+
+	return fmt.Sprintf("SELECT k.key, row_to_json(t) FROM unnest($%d::text[]) WITH ORDINALITY AS k(key, ord) "+
+		"CROSS JOIN LATERAL (%s) AS t ORDER BY k.ord, %s", len(args), query.selectSQL(columnsSQL(query.table)),
+		query.orderBySQL()), args
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+	maxOffset    = 10000
+)
+
+// the sql operators of the comparisons, the "%s" is the column and the "$%d" is the value cast to the column type
+var comparisonOperators = []struct {
+	name      string
+	condition string
+}{
+	{name: "eq", condition: "%s = CAST($%d::text AS %s)"},
+	{name: "neq", condition: "%s <> CAST($%d::text AS %s)"},
+	{name: "in", condition: "%s = ANY(CAST($%d::text[] AS %s[]))"},
+	{name: "like", condition: "%s::text LIKE $%d"},
+	{name: "gt", condition: "%s > CAST($%d::text AS %s)"},
+	{name: "gte", condition: "%s >= CAST($%d::text AS %s)"},
+	{name: "lt", condition: "%s < CAST($%d::text AS %s)"},
+	{name: "lte", condition: "%s <= CAST($%d::text AS %s)"},
+}
+
+type contextKey struct{}
+
+// queryContext is the read only transaction of the graphql request and the batches of the rows related to the
+// queried rows.
+type queryContext struct {
+	tx      pgx.Tx
+	batches map[string]*relatedRowsBatch
+}
+
+// rowsQuery is the query of the rows of a table
+type rowsQuery struct {
+	table      *table
+	conditions []string
+	args       []interface{}
+	orderBy    string
+	desc       bool
+	limit      int
+	offset     int
+}
+
+func withQueryContext(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, contextKey{}, &queryContext{tx: tx, batches: map[string]*relatedRowsBatch{}})
+}
+
+func getQueryContext(ctx context.Context) (*queryContext, error) {
+	queryCtx, ok := ctx.Value(contextKey{}).(*queryContext)
+	if !ok {
+		return nil, fmt.Errorf("no transaction in the context")
+	}
+	return queryCtx, nil
+}
+
+// resolveRows returns the rows of the table matching the list arguments
+func resolveRows(t *table) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		query, err := newRowsQuery(t, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		return queryRows(p.Context, query)
+	}
+}
+
+// resolveRelatedRows returns the rows of the relation target referring to the source row, the rows of all the source
+// rows are fetched by a single query once the field of all the source rows are resolved.
+func resolveRelatedRows(r *relation) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		value := getSourceValue(p.Source, r.column)
+		if value == nil {
+			return []interface{}{}, nil
+		}
+		query, err := newRowsQuery(r.target, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		loadRows, err := loadRelatedRows(p.Context, r, query, value)
+		if err != nil {
+			return nil, err
+		}
+		return func() (interface{}, error) {
+			rows, err := loadRows()
+			if err != nil {
+				return nil, err
+			}
+			return rows, nil
+		}, nil
+	}
+}
+
+// resolveRelatedRow returns the row of the relation target referred by the source row, the rows referred by all the
+// source rows are fetched by a single query, and each of them once since many source rows may refer to the same row.
+func resolveRelatedRow(r *relation) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		value := getSourceValue(p.Source, r.column)
+		if value == nil {
+			return nil, nil
+		}
+		loadRows, err := loadRelatedRows(p.Context, r, &rowsQuery{table: r.target, limit: 1}, value)
+		if err != nil {
+			return nil, err
+		}
+		return func() (interface{}, error) {
+			rows, err := loadRows()
+			if err != nil || len(rows) == 0 {
+				return nil, err
+			}
+			return rows[0], nil
+		}, nil
+	}
+}
+
+func getSourceValue(source interface{}, columnName string) interface{} {
+	row, ok := source.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return row[columnName]
+}
+
+// newRowsQuery returns the query of the table rows by the where, orderBy, desc, limit and offset arguments.
+func newRowsQuery(t *table, args map[string]interface{}) (*rowsQuery, error) {
+	query := &rowsQuery{table: t, limit: defaultLimit}
+	if limit, ok := args["limit"].(int); ok {
+		query.limit = limit
+	}
+	if query.limit < 1 || query.limit > maxLimit {
+		return nil, fmt.Errorf("limit should be in the scope [1, %d]", maxLimit)
+	}
+	if offset, ok := args["offset"].(int); ok {
+		query.offset = offset
+	}
+	if query.offset < 0 || query.offset > maxOffset {
+		return nil, fmt.Errorf("offset should be in the scope [0, %d]", maxOffset)
+	}
+	if orderBy, ok := args["orderBy"].(string); ok {
+		query.orderBy = orderBy
+	}
+	if desc, ok := args["desc"].(bool); ok {
+		query.desc = desc
+	}
+
+	where, _ := args["where"].(map[string]interface{})
+	for _, c := range t.columns {
+		comparison, ok := where[c.name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := query.addComparison(c, comparison); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+// addComparison adds the conditions of the comparison on the column, the operators are applied in a fixed order.
+func (q *rowsQuery) addComparison(c *column, comparison map[string]interface{}) error {
+	if isNull, ok := comparison["isNull"].(bool); ok {
+		if isNull {
+			q.conditions = append(q.conditions, fmt.Sprintf("%s IS NULL", columnIdentifier(c)))
+		} else {
+			q.conditions = append(q.conditions, fmt.Sprintf("%s IS NOT NULL", columnIdentifier(c)))
+		}
+	}
+
+	if c.scalar == jsonScalar {
+		if value, found := comparison["eq"]; found && value != nil {
+			if err := q.addJSONCondition("to_jsonb(%s) = $%d::jsonb", c, value); err != nil {
+				return err
+			}
+		}
+		if value, found := comparison["contains"]; found && value != nil {
+			if err := q.addJSONCondition("to_jsonb(%s) @> $%d::jsonb", c, value); err != nil {
+				return err
+			}
+		}
+		if key, ok := comparison["hasKey"].(string); ok {
+			q.args = append(q.args, key)
+			q.conditions = append(q.conditions, fmt.Sprintf("to_jsonb(%s) ? $%d", columnIdentifier(c), len(q.args)))
+		}
+		return nil
+	}
+
+	for _, operator := range comparisonOperators {
+		if value, found := comparison[operator.name]; found && value != nil {
+			if err := q.addCondition(c, operator.name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addCondition adds the condition of the operator on the scalar column
+func (q *rowsQuery) addCondition(c *column, operatorName string, value interface{}) error {
+	var arg interface{}
+	switch operatorName {
+	case "in":
+		values, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("the in value of %s should be a list", c.name)
+		}
+		texts := make([]string, 0, len(values))
+		for _, v := range values {
+			text, err := toText(v)
+			if err != nil {
+				return err
+			}
+			texts = append(texts, text)
+		}
+		arg = texts
+	default:
+		text, err := toText(value)
+		if err != nil {
+			return err
+		}
+		arg = text
+	}
+
+	for _, operator := range comparisonOperators {
+		if operator.name != operatorName {
+			continue
+		}
+		q.args = append(q.args, arg)
+		if operatorName == "like" {
+			q.conditions = append(q.conditions, fmt.Sprintf(operator.condition, columnIdentifier(c), len(q.args)))
+		} else {
+			q.conditions = append(q.conditions, fmt.Sprintf(operator.condition, columnIdentifier(c), len(q.args),
+				c.sqlType))
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown operator %s", operatorName)
+}
+
+func (q *rowsQuery) addJSONCondition(condition string, c *column, value interface{}) error {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %w", c.name, err)
+	}
+	q.args = append(q.args, string(valueBytes))
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, columnIdentifier(c), len(q.args)))
+	return nil
+}
+
+// sql returns the query of the rows as json objects of the exposed columns with its positional arguments
+func (q *rowsQuery) sql() (string, []interface{}) {
+	fields := make([]string, 0, len(q.table.columns))
+	for _, c := range q.table.columns {
+		fields = append(fields, fmt.Sprintf("'%s', %s", c.name, columnIdentifier(c)))
+	}
+	return q.selectSQL("json_build_object(" + strings.Join(fields, ", ") + ")"), q.args
+}
+
+// selectSQL returns the query selecting the expression of the rows, the table is aliased as t
+func (q *rowsQuery) selectSQL(selection string) string {
+	var builder strings.Builder
+	builder.WriteString("SELECT ")
+	builder.WriteString(selection)
+	builder.WriteString(" FROM ")
+	builder.WriteString(pgx.Identifier{q.table.schema, q.table.name}.Sanitize())
+	builder.WriteString(" AS t")
+	if len(q.conditions) > 0 {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(strings.Join(q.conditions, " AND "))
+	}
+	builder.WriteString(" ORDER BY ")
+	builder.WriteString(q.orderBySQL())
+	builder.WriteString(fmt.Sprintf(" LIMIT %d OFFSET %d", q.limit, q.offset))
+
+	return builder.String()
+}
+
+// orderBySQL returns the order of the rows, by the first column by default to have stable pages
+func (q *rowsQuery) orderBySQL() string {
+	orderBy := q.table.columns[0]
+	if c := q.table.column(q.orderBy); c != nil {
+		orderBy = c
+	}
+	if q.desc {
+		return columnIdentifier(orderBy) + " DESC"
+	}
+	return columnIdentifier(orderBy)
+}
+
+func queryRows(ctx context.Context, query *rowsQuery) ([]interface{}, error) {
+	queryCtx, err := getQueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args := query.sql()
+	rows, err := queryCtx.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", query.table.typeName(), err)
+	}
+	defer rows.Close()
+
+	result := []interface{}{}
+	for rows.Next() {
+		var row map[string]interface{}
+		if err := rows.Scan(&row); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", query.table.typeName(), err)
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// columnsSQL returns the exposed columns of the table
+func columnsSQL(t *table) string {
+	identifiers := make([]string, 0, len(t.columns))
+	for _, c := range t.columns {
+		identifiers = append(identifiers, columnIdentifier(c))
+	}
+	return strings.Join(identifiers, ", ")
+}
+
+func columnIdentifier(c *column) string {
+	return "t." + pgx.Identifier{c.name}.Sanitize()
+}
@@ -0,0 +1,495 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// resourceColumns are the columns of the spec resources and their history without the payloads, since the payloads
+// might hold the secrets distributed to the leaf hubs
+var resourceColumns = []string{"id", "api_group", "api_version", "kind", "created_at", "updated_at", "deleted"}
+
+// exposedTables is the allow-list of the tables exposed by the graphql endpoint, keyed by "<schema>.<table>", with
+// the allow-list of their columns, all the columns of a table are exposed if its columns aren't listed. the tables
+// and the columns which aren't listed, e.g. the payloads of the spec resources, can't be queried. the partitions are
+// queried through their parents.
+var exposedTables = map[string][]string{
+	"event.applications":                 nil,
+	"event.local_policies":               nil,
+	"event.local_root_policies":          nil,
+	"event.managed_clusters":             nil,
+	"event.placements":                   nil,
+	"history.applications":               nil,
+	"history.audit_log":                  nil,
+	"history.channels":                   nil,
+	"history.compliance":                 nil,
+	"history.configs":                    nil,
+	"history.leaf_hub_archives":          nil,
+	"history.leaf_hub_lifecycle_actions": nil,
+	"history.local_compliance":           nil,
+	"history.local_compliance_job_log":   nil,
+	"history.managedclustersetbindings":  nil,
+	"history.managedclustersets":         nil,
+	"history.placementbindings":          nil,
+	"history.placementrules":             nil,
+	"history.placements":                 nil,
+	"history.policies":                   nil,
+	"history.resources":                  resourceColumns,
+	"history.subscriptions":              nil,
+	"local_spec.placementrules":          nil,
+	"local_spec.policies":                nil,
+	"local_status.compliance":            nil,
+	"spec.applications":                  nil,
+	"spec.channels":                      nil,
+	"spec.configs":                       nil,
+	"spec.leaf_hub_agent_configs":        nil,
+	"spec.leaf_hub_placement_decisions":  nil,
+	"spec.leaf_hub_rollouts":             nil,
+	"spec.managed_cluster_sets_tracking": nil,
+	"spec.managed_clusters_labels":       nil,
+	"spec.managedclustersetbindings":     nil,
+	"spec.managedclustersets":            nil,
+	"spec.placementbindings":             nil,
+	"spec.placementrules":                nil,
+	"spec.placements":                    nil,
+	"spec.policies":                      nil,
+	"spec.resources":                     resourceColumns,
+	"spec.subscriptions":                 nil,
+	"status.aggregated_compliance":       nil,
+	"status.applied_statuses":            nil,
+	"status.argocd_applications":         nil,
+	"status.argocd_applicationsets":      nil,
+	"status.compliance":                  nil,
+	"status.leaf_hub_capabilities":       nil,
+	"status.leaf_hub_heartbeats":         nil,
+	"status.leaf_hub_lifecycles":         nil,
+	"status.leaf_hubs":                   nil,
+	"status.managed_cluster_addons":      nil,
+	"status.managed_clusters":            nil,
+	"status.placementdecisions":          nil,
+	"status.placementrules":              nil,
+	"status.placements":                  nil,
+	"status.policy_reports":              nil,
+	"status.policy_violations":           nil,
+	"status.subscription_reports":        nil,
+	"status.subscription_statuses":       nil,
+	// the payloads of the rollouts are the payloads of the rolled out resources
+	"spec.rollouts": {
+		"resource_id", "table_name", "rollout_policy", "resource_updated_at", "wave", "phase", "message", "baseline",
+		"wave_started_at", "wave_applied_at", "last_transition_at", "updated_at",
+	},
+}
+
+const tablesQuery = `SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, NULL), t.typname, t.typcategory
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid
+	JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+	WHERE n.nspname || '.' || c.relname = ANY($1) AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY n.nspname, c.relname, a.attnum`
+
+var nameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// jsonScalar is the json, jsonb and array columns, they're returned as they're stored.
+var jsonScalar = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "The json, jsonb and array columns",
+	Serialize:   func(value interface{}) interface{} { return value },
+	ParseValue:  func(value interface{}) interface{} { return value },
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return parseLiteral(valueAST)
+	},
+})
+
+// column is a column of the exposed tables, the sqlType is used to cast the filter values to the column type.
+type column struct {
+	name    string
+	sqlType string
+	scalar  *gql.Scalar
+}
+
+// table is an exposed table, it's exposed as the "<schema>_<table>" type.
+type table struct {
+	schema  string
+	name    string
+	columns []*column
+}
+
+// reference is the relationship, by the column naming convention, from the tables having the column to the target
+// table, the referenced row is exposed as the field of the referencing tables and the referencing rows are exposed as
+// the "<schema>_<table>" list field of the target table.
+type reference struct {
+	fieldName    string
+	column       string
+	targetSchema string
+	targetTable  string
+	targetColumn string
+	// matches returns whether the table refers to the target table by the column
+	matches func(t *table) bool
+}
+
+// relation is the resolved reference between two exposed tables
+type relation struct {
+	fieldName    string
+	column       string
+	target       *table
+	targetColumn string
+	many         bool
+}
+
+var references = []reference{
+	{
+		fieldName: "leaf_hub", column: "leaf_hub_name",
+		targetSchema: "status", targetTable: "leaf_hubs", targetColumn: "leaf_hub_name",
+		matches: func(t *table) bool { return true },
+	},
+	{
+		fieldName: "managed_cluster", column: "cluster_id",
+		targetSchema: "status", targetTable: "managed_clusters", targetColumn: "cluster_id",
+		matches: func(t *table) bool { return true },
+	},
+	{
+		fieldName: "policy", column: "policy_id",
+		targetSchema: "spec", targetTable: "policies", targetColumn: "id",
+		matches: func(t *table) bool { return !t.isLocal() },
+	},
+	{
+		fieldName: "policy", column: "policy_id",
+		targetSchema: "local_spec", targetTable: "policies", targetColumn: "policy_id",
+		matches: func(t *table) bool { return t.isLocal() },
+	},
+}
+
+func (t *table) typeName() string {
+	return t.schema + "_" + t.name
+}
+
+// isLocal returns whether the table is about the local resources of the leaf hubs
+func (t *table) isLocal() bool {
+	return strings.HasPrefix(t.schema, "local_") || strings.HasPrefix(t.name, "local_")
+}
+
+func (t *table) column(name string) *column {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// loadTables returns the exposed tables of the database, ordered by their schemas and names.
+func loadTables(ctx context.Context, dbConnectionPool *pgxpool.Pool) ([]*table, error) {
+	tableNames := make([]string, 0, len(exposedTables))
+	for tableName := range exposedTables {
+		tableNames = append(tableNames, tableName)
+	}
+	rows, err := dbConnectionPool.Query(ctx, tablesQuery, tableNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []*table{}
+	var current *table
+	for rows.Next() {
+		var schemaName, tableName, columnName, sqlType, typeName, typeCategory string
+		if err := rows.Scan(&schemaName, &tableName, &columnName, &sqlType, &typeName,
+			&typeCategory); err != nil {
+			return nil, fmt.Errorf("failed to scan the table column: %w", err)
+		}
+		if !nameRegexp.MatchString(tableName) || !nameRegexp.MatchString(columnName) ||
+			!isColumnExposed(schemaName, tableName, columnName) {
+			continue
+		}
+		if current == nil || current.schema != schemaName || current.name != tableName {
+			current = &table{schema: schemaName, name: tableName}
+			tables = append(tables, current)
+		}
+		current.columns = append(current.columns, &column{
+			name:    columnName,
+			sqlType: sqlType,
+			scalar:  toScalar(typeName, typeCategory),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list the tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// isColumnExposed returns whether the column of the table is in the allow-list of the exposed tables
+func isColumnExposed(schemaName, tableName, columnName string) bool {
+	columns, found := exposedTables[schemaName+"."+tableName]
+	if !found {
+		return false
+	}
+	if columns == nil {
+		return true
+	}
+	for _, c := range columns {
+		if c == columnName {
+			return true
+		}
+	}
+	return false
+}
+
+// toScalar returns the graphql scalar of the postgres type, the uuid, enum and time types are strings.
+func toScalar(typeName, typeCategory string) *gql.Scalar {
+	// the array types
+	if typeCategory == "A" {
+		return jsonScalar
+	}
+	switch typeName {
+	case "int2", "int4":
+		return gql.Int
+	case "int8", "float4", "float8", "numeric":
+		return gql.Float
+	case "bool":
+		return gql.Boolean
+	case "json", "jsonb":
+		return jsonScalar
+	default:
+		return gql.String
+	}
+}
+
+// getRelations returns the relations of the tables keyed by the table type names
+func getRelations(tables []*table) map[string][]*relation {
+	tablesByName := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		tablesByName[t.typeName()] = t
+	}
+
+	relations := make(map[string][]*relation)
+	for _, ref := range references {
+		target, found := tablesByName[ref.targetSchema+"_"+ref.targetTable]
+		if !found || target.column(ref.targetColumn) == nil {
+			continue
+		}
+		for _, t := range tables {
+			if t == target || t.column(ref.column) == nil || !ref.matches(t) {
+				continue
+			}
+			// the columns win over the relations with the same names
+			if t.column(ref.fieldName) == nil {
+				relations[t.typeName()] = append(relations[t.typeName()], &relation{
+					fieldName:    ref.fieldName,
+					column:       ref.column,
+					target:       target,
+					targetColumn: ref.targetColumn,
+				})
+			}
+			if target.column(t.typeName()) == nil {
+				relations[target.typeName()] = append(relations[target.typeName()], &relation{
+					fieldName:    t.typeName(),
+					column:       ref.targetColumn,
+					target:       t,
+					targetColumn: ref.column,
+					many:         true,
+				})
+			}
+		}
+	}
+
+	return relations
+}
+
+// buildSchema returns the read only graphql schema of the tables, each table is exposed as the "<schema>_<table>"
+// list field of the query.
+func buildSchema(tables []*table) (gql.Schema, error) {
+	if len(tables) == 0 {
+		return gql.Schema{}, fmt.Errorf("none of the exposed tables is found")
+	}
+
+	relations := getRelations(tables)
+	objects := make(map[string]*gql.Object, len(tables))
+	listArgs := make(map[string]gql.FieldConfigArgument, len(tables))
+	for _, t := range tables {
+		listArgs[t.typeName()] = getListArgs(t)
+	}
+
+	for _, t := range tables {
+		t := t
+		objects[t.typeName()] = gql.NewObject(gql.ObjectConfig{
+			Name:        t.typeName(),
+			Description: fmt.Sprintf("The rows of the %s.%s table", t.schema, t.name),
+			Fields: gql.FieldsThunk(func() gql.Fields {
+				fields := gql.Fields{}
+				for _, c := range t.columns {
+					fields[c.name] = &gql.Field{Type: c.scalar}
+				}
+				for _, r := range relations[t.typeName()] {
+					if r.many {
+						fields[r.fieldName] = &gql.Field{
+							Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(objects[r.target.typeName()]))),
+							Args:    listArgs[r.target.typeName()],
+							Resolve: resolveRelatedRows(r),
+						}
+						continue
+					}
+					fields[r.fieldName] = &gql.Field{
+						Type:    objects[r.target.typeName()],
+						Resolve: resolveRelatedRow(r),
+					}
+				}
+				return fields
+			}),
+		})
+	}
+
+	queryFields := gql.Fields{}
+	for _, t := range tables {
+		queryFields[t.typeName()] = &gql.Field{
+			Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(objects[t.typeName()]))),
+			Args:    listArgs[t.typeName()],
+			Resolve: resolveRows(t),
+		}
+	}
+
+	return gql.NewSchema(gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{Name: "Query", Fields: queryFields}),
+	})
+}
+
+// getListArgs returns the filter, order and pagination arguments of the list fields of the table
+func getListArgs(t *table) gql.FieldConfigArgument {
+	whereFields := gql.InputObjectConfigFieldMap{}
+	columnValues := gql.EnumValueConfigMap{}
+	for _, c := range t.columns {
+		whereFields[c.name] = &gql.InputObjectFieldConfig{Type: comparisonInputs[c.scalar.Name()]}
+		columnValues[c.name] = &gql.EnumValueConfig{Value: c.name}
+	}
+
+	return gql.FieldConfigArgument{
+		"where": &gql.ArgumentConfig{
+			Type: gql.NewInputObject(gql.InputObjectConfig{
+				Name:   t.typeName() + "_where",
+				Fields: whereFields,
+			}),
+			Description: "the conditions of the columns, the rows matching all of them are returned",
+		},
+		"orderBy": &gql.ArgumentConfig{
+			Type: gql.NewEnum(gql.EnumConfig{
+				Name:   t.typeName() + "_column",
+				Values: columnValues,
+			}),
+			Description: "the column to order the rows by, the first column by default",
+		},
+		"desc": &gql.ArgumentConfig{
+			Type:         gql.Boolean,
+			DefaultValue: false,
+			Description:  "order the rows in descending order",
+		},
+		"limit": &gql.ArgumentConfig{
+			Type:         gql.Int,
+			DefaultValue: defaultLimit,
+			Description:  fmt.Sprintf("maximum row number to receive, up to %d", maxLimit),
+		},
+		"offset": &gql.ArgumentConfig{
+			Type:         gql.Int,
+			DefaultValue: 0,
+			Description:  "row number to skip",
+		},
+	}
+}
+
+// comparisonInputs is the comparison input types keyed by the names of the column scalars
+var comparisonInputs = map[string]*gql.InputObject{
+	gql.String.Name():  newComparisonInput(gql.String, "eq", "neq", "in", "like", "gt", "gte", "lt", "lte"),
+	gql.Int.Name():     newComparisonInput(gql.Int, "eq", "neq", "in", "gt", "gte", "lt", "lte"),
+	gql.Float.Name():   newComparisonInput(gql.Float, "eq", "neq", "in", "gt", "gte", "lt", "lte"),
+	gql.Boolean.Name(): newComparisonInput(gql.Boolean, "eq", "neq"),
+	jsonScalar.Name():  newComparisonInput(jsonScalar, "eq", "contains", "hasKey"),
+}
+
+func newComparisonInput(scalar *gql.Scalar, operators ...string) *gql.InputObject {
+	fields := gql.InputObjectConfigFieldMap{
+		"isNull": &gql.InputObjectFieldConfig{Type: gql.Boolean},
+	}
+	for _, operator := range operators {
+		switch operator {
+		case "in":
+			fields[operator] = &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(scalar))}
+		case "like", "hasKey":
+			fields[operator] = &gql.InputObjectFieldConfig{Type: gql.String}
+		default:
+			fields[operator] = &gql.InputObjectFieldConfig{Type: scalar}
+		}
+	}
+
+	return gql.NewInputObject(gql.InputObjectConfig{
+		Name:   scalar.Name() + "_comparison",
+		Fields: fields,
+	})
+}
+
+// parseLiteral returns the go value of the graphql literal
+func parseLiteral(valueAST ast.Value) interface{} {
+	switch value := valueAST.(type) {
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.IntValue:
+		number, err := strconv.ParseInt(value.Value, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return number
+	case *ast.FloatValue:
+		number, err := strconv.ParseFloat(value.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return number
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			list = append(list, parseLiteral(item))
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(value.Fields))
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseLiteral(field.Value)
+		}
+		return object
+	default:
+		return nil
+	}
+}
+
+// toText returns the text of the value to be cast to the column type
+func toText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int, int32, int64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid value %v: %w", v, err)
+		}
+		return string(valueBytes), nil
+	}
+}
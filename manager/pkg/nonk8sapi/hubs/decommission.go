@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// DecommissionLeafHub godoc
+// @summary decommission leaf hub
+// @description decommission the leaf hub, its data in the global hub database is archived or deleted by the retention,
+// @description and its status is discarded until it's detached and attached back. only the users in the admin groups
+// @description are allowed to decommission the leaf hub, and the decommission is recorded in the audit log.
+// @param        hubName    path     string  true   "Name of the leaf hub"
+// @param        retention  query    string  false  "Retention of the data, 'archive' or 'delete'"
+// @produce json
+// @success      202
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /hub/{hubName}/decommission [post]
+func DecommissionLeafHub(adminGroups []string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		leafHubName := ginCtx.Param("hubName")
+		retention := ginCtx.Query("retention")
+		fmt.Fprintf(gin.DefaultWriter, "decommission leaf hub: %s, retention: %s\n", leafHubName, retention)
+
+		if !isInAdminGroups(ginCtx, adminGroups) {
+			fmt.Fprintf(gin.DefaultWriter, "user %q isn't allowed to decommission leaf hub: %s\n",
+				ginCtx.GetString(authentication.UserKey), leafHubName)
+			ginCtx.String(http.StatusForbidden, "only the users in the admin groups %v are allowed to decommission "+
+				"the leaf hubs", adminGroups)
+			return
+		}
+
+		if retention != "" && retention != database.RetentionArchive && retention != database.RetentionDelete {
+			ginCtx.String(http.StatusBadRequest, "retention must be %q or %q", database.RetentionArchive,
+				database.RetentionDelete)
+			return
+		}
+
+		auditLog, err := newDecommissionAuditLog(ginCtx, leafHubName, retention)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in decommissioning leaf hub: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		// the decommission is recorded in the audit log within the transaction of the request
+		requested, err := hubmanagement.RequestOffboarding(ginCtx.Request.Context(), leafHubName,
+			database.OffboardingReasonDecommissioned, retention, auditLog)
+		if errors.Is(err, hubmanagement.ErrLeafHubNotFound) {
+			ginCtx.String(http.StatusNotFound, "leaf hub %s not found", leafHubName)
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in decommissioning leaf hub: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if !requested {
+			ginCtx.String(http.StatusConflict, "leaf hub %s is already offboarding or offboarded", leafHubName)
+			return
+		}
+
+		ginCtx.Status(http.StatusAccepted)
+	}
+}
+
+// isInAdminGroups returns true if the authenticated user is in any of the admin groups.
+func isInAdminGroups(ginCtx *gin.Context, adminGroups []string) bool {
+	for _, group := range ginCtx.GetStringSlice(authentication.GroupsKey) {
+		for _, adminGroup := range adminGroups {
+			if group == adminGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newDecommissionAuditLog returns the audit log of the decommission of the leaf hub by the authenticated user.
+func newDecommissionAuditLog(ginCtx *gin.Context, leafHubName, retention string) (*models.AuditLog, error) {
+	targetHubs, err := json.Marshal([]string{leafHubName})
+	if err != nil {
+		return nil, err
+	}
+	details, err := json.Marshal(map[string]string{"retention": retention})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLog{
+		UserName:   ginCtx.GetString(authentication.UserKey),
+		Source:     database.AuditSourceAPI,
+		Operation:  database.AuditOperationDecommission,
+		Kind:       "LeafHub",
+		Name:       leafHubName,
+		TargetHubs: targetHubs,
+		Details:    details,
+	}, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+)
+
+func TestIsInAdminGroups(t *testing.T) {
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.False(t, isInAdminGroups(ginCtx, []string{"system:cluster-admins"}))
+
+	ginCtx.Set(authentication.GroupsKey, []string{"system:authenticated", "developers"})
+	assert.False(t, isInAdminGroups(ginCtx, []string{"system:cluster-admins"}))
+	assert.False(t, isInAdminGroups(ginCtx, nil))
+
+	ginCtx.Set(authentication.GroupsKey, []string{"system:authenticated", "system:cluster-admins"})
+	assert.True(t, isInAdminGroups(ginCtx, []string{"system:cluster-admins"}))
+}
+
+func TestDecommissionLeafHubForbidden(t *testing.T) {
+	router := gin.New()
+	router.Use(func(ginCtx *gin.Context) {
+		ginCtx.Set(authentication.UserKey, "developer")
+		ginCtx.Set(authentication.GroupsKey, []string{"system:authenticated"})
+	})
+	router.POST("/hub/:hubName/decommission", DecommissionLeafHub([]string{"system:cluster-admins"}))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/hub/hub1/decommission", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	leafHubsQuery          = `SELECT hb.leaf_hub_name, hb.last_timestamp, lh.console_url, c.source, c.payload,
+		c.updated_at, cap.payload, lc.state FROM status.leaf_hub_heartbeats hb
+		LEFT JOIN status.leaf_hubs lh ON hb.leaf_hub_name = lh.leaf_hub_name AND lh.deleted_at IS NULL
+		LEFT JOIN spec.leaf_hub_agent_configs c ON hb.leaf_hub_name = c.leaf_hub_name
+		LEFT JOIN status.leaf_hub_capabilities cap ON hb.leaf_hub_name = cap.leaf_hub_name
+		LEFT JOIN status.leaf_hub_lifecycles lc ON hb.leaf_hub_name = lc.leaf_hub_name
+		ORDER BY hb.leaf_hub_name`
+)
+
+// LeafHubAgentConfig is the effective agent config of a leaf hub resolved by the manager.
+type LeafHubAgentConfig struct {
+	// Source is the name of the override the config is resolved from, or "default" if no override matches
+	Source    string                             `json:"source"`
+	Spec      *globalhubv1alpha3.AgentConfigSpec `json:"spec"`
+	UpdatedAt *metav1.Time                       `json:"updatedAt,omitempty"`
+}
+
+// LeafHub is a leaf hub in the inventory of the global hub.
+type LeafHub struct {
+	Name          string              `json:"name"`
+	ConsoleURL    string              `json:"consoleURL,omitempty"`
+	LastHeartbeat *metav1.Time        `json:"lastHeartbeat,omitempty"`
+	AgentConfig   *LeafHubAgentConfig `json:"agentConfig,omitempty"`
+	// Capabilities are advertised by the agent, they're empty if the agent is released before the handshake
+	Capabilities *status.Capabilities `json:"capabilities,omitempty"`
+	// State is the lifecycle state, "onboarded" or "offboarding", it's empty if the lifecycle isn't recorded yet
+	State string `json:"state,omitempty"`
+}
+
+// LeafHubList is the inventory of the leaf hubs.
+type LeafHubList struct {
+	Items []LeafHub `json:"items"`
+}
+
+// ListLeafHubs godoc
+// @summary list leaf hubs
+// @description list the leaf hubs connected to the global hub with their effective agent config and capabilities
+// @accept json
+// @produce json
+// @success      200  {object}  LeafHubList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /hubs [get]
+func ListLeafHubs(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		fmt.Fprintf(gin.DefaultWriter, "leaf hubs query: %s\n", leafHubsQuery)
+
+		leafHubList, err := listLeafHubs(dbConnectionPool)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying leaf hubs: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, leafHubList)
+	}
+}
+
+func listLeafHubs(dbConnectionPool *pgxpool.Pool) (*LeafHubList, error) {
+	rows, err := dbConnectionPool.Query(context.TODO(), leafHubsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error in querying leaf hubs: %w", err)
+	}
+	defer rows.Close()
+
+	leafHubList := &LeafHubList{Items: []LeafHub{}}
+	for rows.Next() {
+		var leafHubName string
+		var lastHeartbeat time.Time
+		var consoleURL, source *string
+		var agentConfig *globalhubv1alpha3.AgentConfigSpec
+		var agentConfigUpdatedAt *time.Time
+		var capabilities *status.Capabilities
+		var state *string
+		if err := rows.Scan(&leafHubName, &lastHeartbeat, &consoleURL, &source, &agentConfig,
+			&agentConfigUpdatedAt, &capabilities, &state); err != nil {
+			return nil, fmt.Errorf("error in scanning leaf hub: %w", err)
+		}
+
+		leafHub := LeafHub{
+			Name:          leafHubName,
+			LastHeartbeat: &metav1.Time{Time: lastHeartbeat},
+			Capabilities:  capabilities,
+		}
+		if consoleURL != nil {
+			leafHub.ConsoleURL = *consoleURL
+		}
+		if state != nil {
+			leafHub.State = *state
+		}
+		// the agent config isn't resolved yet, e.g. the leaf hub isn't a managed cluster of the global hub
+		if source != nil {
+			leafHub.AgentConfig = &LeafHubAgentConfig{
+				Source: *source,
+				Spec:   agentConfig,
+			}
+			if agentConfigUpdatedAt != nil {
+				leafHub.AgentConfig.UpdatedAt = &metav1.Time{Time: *agentConfigUpdatedAt}
+			}
+		}
+		leafHubList.Items = append(leafHubList.Items, leafHub)
+	}
+
+	return leafHubList, nil
+}
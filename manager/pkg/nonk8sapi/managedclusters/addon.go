@@ -0,0 +1,49 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// the conditions of the addon health, the addon conditions are stored as True, False or Unknown
+var addonStatusConditions = map[string]string{
+	"available":   "available = 'True'",
+	"unavailable": "available <> 'True'",
+	"degraded":    "degraded = 'True'",
+	"progressing": "progressing = 'True'",
+	"healthy":     "available = 'True' AND degraded <> 'True'",
+}
+
+// parseAddonFilter returns the sql condition to select the managed clusters by the health of their addons. if only
+// the addon name is set the clusters with the addon are selected, if only the addon status is set the clusters with
+// any addon of the status are selected.
+func parseAddonFilter(addonName, addonStatus string) (string, error) {
+	if addonName == "" && addonStatus == "" {
+		return "", nil
+	}
+
+	conditions := []string{}
+	if addonName != "" {
+		// the addon name is a resource name, so it's safe to be put into the query once validated
+		if errs := validation.IsDNS1123Subdomain(addonName); len(errs) > 0 {
+			return "", fmt.Errorf("invalid addon name %s: %s", addonName, strings.Join(errs, ", "))
+		}
+		conditions = append(conditions, fmt.Sprintf("addon_name = '%s'", addonName))
+	}
+	if addonStatus != "" {
+		condition, found := addonStatusConditions[strings.ToLower(addonStatus)]
+		if !found {
+			return "", fmt.Errorf("invalid addon status %s, should be one of: available, unavailable, degraded, "+
+				"progressing or healthy", addonStatus)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return fmt.Sprintf(" AND cluster_id IN (SELECT cluster_id FROM status.managed_cluster_addons WHERE %s)",
+		strings.Join(conditions, " AND ")), nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddonFilter(t *testing.T) {
+	cases := []struct {
+		name        string
+		addonName   string
+		addonStatus string
+		expected    string
+		expectErr   bool
+	}{
+		{
+			name:     "no filter",
+			expected: "",
+		},
+		{
+			name:      "only addon name",
+			addonName: "observability-controller",
+			expected: " AND cluster_id IN (SELECT cluster_id FROM status.managed_cluster_addons WHERE " +
+				"addon_name = 'observability-controller')",
+		},
+		{
+			name:        "only addon status",
+			addonStatus: "Degraded",
+			expected: " AND cluster_id IN (SELECT cluster_id FROM status.managed_cluster_addons WHERE " +
+				"degraded = 'True')",
+		},
+		{
+			name:        "addon name and status",
+			addonName:   "governance-policy-framework",
+			addonStatus: "unavailable",
+			expected: " AND cluster_id IN (SELECT cluster_id FROM status.managed_cluster_addons WHERE " +
+				"addon_name = 'governance-policy-framework' AND available <> 'True')",
+		},
+		{
+			name:      "invalid addon name",
+			addonName: "observability' OR '1'='1",
+			expectErr: true,
+		},
+		{
+			name:        "invalid addon status",
+			addonStatus: "broken",
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := parseAddonFilter(tc.addonName, tc.addonStatus)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, filter)
+		})
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+)
+
+// the fields of the managed clusters that can be selected by the field selector
+var fieldSelectorColumns = map[string]string{
+	"metadata.name": "cluster_name",
+	"leafHubName":   "leaf_hub_name",
+	"clusterID":     "cluster_id::text",
+}
+
+// the number of managed clusters that can be patched in bulk unless maxClusters is set by the request, so that the
+// managed clusters of a selector matching more than expected aren't patched without previewing them
+const defaultBulkPatchMaxClusters = 100
+
+var errTooManyClustersMatched = errors.New("too many managed clusters match the selectors")
+
+// BulkPatchResult is the result of patching the labels, annotations and taints of the managed clusters in bulk
+type BulkPatchResult struct {
+	DryRun   bool                 `json:"dryRun"`
+	Matched  int                  `json:"matched"`
+	Changed  int                  `json:"changed"`
+	Clusters []ClusterPatchResult `json:"clusters"`
+}
+
+// ClusterPatchResult is the result of patching a managed cluster, the labels, annotations and taints are the ones of
+// the managed cluster once the patch is applied on the leaf hub.
+type ClusterPatchResult struct {
+	ClusterID   string            `json:"clusterID"`
+	Name        string            `json:"name"`
+	LeafHubName string            `json:"leafHubName"`
+	Changed     bool              `json:"changed"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Taints      []clusterv1.Taint `json:"taints,omitempty"`
+}
+
+// bulkPatchCluster is a managed cluster matching the selectors with its labels, annotations and taints, and the ones
+// pending to be sent to its leaf hub
+type bulkPatchCluster struct {
+	clusterID   string
+	name        string
+	leafHubName string
+	metadata    clusterMetadata
+	taints      []clusterv1.Taint
+	pending     *pendingMetadata
+}
+
+// PatchManagedClusters godoc
+// @summary patch labels, annotations and taints of managed clusters
+// @description patch labels, annotations and taints for all the managed clusters matching the selectors across the
+// @description leaf hubs in a single transaction, with dryRun the result of each managed cluster is returned without
+// @description being applied. the patch is rejected if more managed clusters than maxClusters match the selectors.
+// @accept json
+// @produce json
+// @param        labelSelector    query   string    false   "patch managed clusters by label selector"
+// @param        fieldSelector    query   string    false   "by metadata.name, leafHubName or clusterID"
+// @param        dryRun           query   bool      false   "preview the result without applying the patch"
+// @param        maxClusters      query   int       false   "the most managed clusters to patch, 100 by default"
+// @param        patch            body    patch     true    "JSON patch that operators on managed cluster metadata"
+// @success      200  {object}    BulkPatchResult
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedclusters [patch]
+func PatchManagedClusters(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		labelSelector := ginCtx.Query("labelSelector")
+		fieldSelector := ginCtx.Query("fieldSelector")
+		fmt.Fprintf(gin.DefaultWriter, "bulk patch for managed clusters by label selector: %s, field selector: %s\n",
+			labelSelector, fieldSelector)
+
+		selectorInSql, args, err := parseBulkSelectors(labelSelector, fieldSelector)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse selectors: %s\n", err.Error())
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		dryRun := false
+		if dryRunStr := ginCtx.Query("dryRun"); dryRunStr != "" {
+			dryRun, err = strconv.ParseBool(dryRunStr)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid dryRun %s: %s", dryRunStr, err.Error())
+				return
+			}
+		}
+
+		maxClusters := defaultBulkPatchMaxClusters
+		if maxClustersStr := ginCtx.Query("maxClusters"); maxClustersStr != "" {
+			maxClusters, err = strconv.Atoi(maxClustersStr)
+			if err != nil || maxClusters <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid maxClusters %s: should be a positive integer",
+					maxClustersStr)
+				return
+			}
+		}
+
+		var patches []patch
+		if err := ginCtx.BindJSON(&patches); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to bind: %s\n", err.Error())
+			return
+		}
+
+		metadataPatch, err := getMetadataPatch(ginCtx, patches)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to get labels, annotations and taints: %s\n", err.Error())
+			return
+		}
+		if metadataPatch.isEmpty() {
+			ginCtx.String(http.StatusBadRequest, "no label, annotation or taint to add or remove")
+			return
+		}
+
+		details := metadataPatch.details()
+		details["labelSelector"] = labelSelector
+		details["fieldSelector"] = fieldSelector
+		bulkPatch := &bulkPatchRequest{
+			userName:      ginCtx.GetString(authentication.UserKey),
+			selectorInSql: selectorInSql,
+			args:          args,
+			metadataPatch: metadataPatch,
+			details:       details,
+			dryRun:        dryRun,
+			maxClusters:   maxClusters,
+		}
+
+		var result *BulkPatchResult
+		for retryAttempts := optimisticConcurrencyRetryAttempts; retryAttempts > 0; retryAttempts-- {
+			result, err = patchMetadataInBulk(ginCtx.Request.Context(), dbConnectionPool, bulkPatch)
+			if err == nil || !isWriteConflict(err) {
+				break
+			}
+		}
+
+		if errors.Is(err, errTooManyClustersMatched) {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			fmt.Fprintf(gin.DefaultWriter, "error in patching managed cluster metadata in bulk: %v\n", err)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, result)
+	}
+}
+
+// parseBulkSelectors returns the sql condition and its arguments to select the managed clusters by the label and
+// field selectors, at least one of the selectors is required so that all the managed clusters aren't patched by
+// mistake.
+func parseBulkSelectors(labelSelector, fieldSelector string) (string, []interface{}, error) {
+	if labelSelector == "" && fieldSelector == "" {
+		return "", nil, fmt.Errorf("labelSelector or fieldSelector is required")
+	}
+
+	selectorInSql := ""
+	if labelSelector != "" {
+		// validate the label selector before it's put into the query, only the equality based requirements are
+		// supported by the label selector in sql
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid label selector %s: %w", labelSelector, err)
+		}
+		requirements, _ := selector.Requirements()
+		for _, requirement := range requirements {
+			switch requirement.Operator() {
+			case selection.Equals, selection.DoubleEquals, selection.NotEquals, selection.Exists,
+				selection.DoesNotExist:
+			default:
+				return "", nil, fmt.Errorf("unsupported label selector %s, only equality based selector is supported",
+					requirement.String())
+			}
+		}
+		selectorInSql, err = util.ParseLabelSelector(labelSelector)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	args := []interface{}{}
+	if fieldSelector != "" {
+		selector, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid field selector %s: %w", fieldSelector, err)
+		}
+		for _, requirement := range selector.Requirements() {
+			column, found := fieldSelectorColumns[requirement.Field]
+			if !found {
+				return "", nil, fmt.Errorf("unsupported field %s, should be one of: metadata.name, leafHubName or "+
+					"clusterID", requirement.Field)
+			}
+			operator := "="
+			if requirement.Operator == selection.NotEquals {
+				operator = "<>"
+			}
+			args = append(args, requirement.Value)
+			selectorInSql += fmt.Sprintf(" AND %s %s $%d", column, operator, len(args))
+		}
+	}
+
+	return selectorInSql, args, nil
+}
+
+// bulkPatchRequest is the patch of the labels, annotations and taints of the managed clusters matching the selectors
+// requested by the user, the details of the patch are recorded in the audit log of each patched managed cluster
+type bulkPatchRequest struct {
+	userName      string
+	selectorInSql string
+	args          []interface{}
+	metadataPatch *metadataPatch
+	details       map[string]interface{}
+	dryRun        bool
+	maxClusters   int
+}
+
+// patchMetadataInBulk patches the labels, annotations and taints of the managed clusters matching the selectors in a
+// single transaction, the pending ones of the managed clusters are locked so that they aren't patched by others in
+// the meantime. the patch of each managed cluster is recorded in the audit log within the same transaction.
+func patchMetadataInBulk(ctx context.Context, dbConnectionPool *pgxpool.Pool, bulkPatch *bulkPatchRequest,
+) (*BulkPatchResult, error) {
+	tx, err := dbConnectionPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// the rollback is a no-op once the transaction is committed
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	clusters, err := getBulkPatchClusters(ctx, tx, bulkPatch.selectorInSql, bulkPatch.args)
+	if err != nil {
+		return nil, err
+	}
+	if !bulkPatch.dryRun && len(clusters) > bulkPatch.maxClusters {
+		return nil, fmt.Errorf("%w: %d managed clusters match, more than maxClusters %d, preview them with dryRun "+
+			"and set maxClusters to patch them", errTooManyClustersMatched, len(clusters), bulkPatch.maxClusters)
+	}
+
+	result := &BulkPatchResult{DryRun: bulkPatch.dryRun, Matched: len(clusters), Clusters: []ClusterPatchResult{}}
+	batch := &pgx.Batch{}
+	changedClusters := []ClusterPatchResult{}
+	for _, cluster := range clusters {
+		current := cluster.metadata.withPending(cluster.pending)
+		desired := bulkPatch.metadataPatch.applyTo(current)
+		changed := !reflect.DeepEqual(current, desired)
+		clusterResult := ClusterPatchResult{
+			ClusterID:   cluster.clusterID,
+			Name:        cluster.name,
+			LeafHubName: cluster.leafHubName,
+			Changed:     changed,
+			Labels:      desired.labels,
+			Annotations: desired.annotations,
+			Taints:      desiredTaints(cluster.taints, desired.taints),
+		}
+		result.Clusters = append(result.Clusters, clusterResult)
+		if !changed {
+			continue
+		}
+		result.Changed++
+		changedClusters = append(changedClusters, clusterResult)
+
+		if cluster.pending != nil {
+			batch.Queue(updatePendingMetadataQuery+" RETURNING version",
+				append(bulkPatch.metadataPatch.mergeInto(cluster.pending).args(), cluster.clusterID)...)
+		} else {
+			batch.Queue(insertPendingMetadataQuery+" RETURNING version", append([]interface{}{
+				cluster.clusterID, cluster.leafHubName, cluster.name,
+			}, bulkPatch.metadataPatch.mergeInto(&pendingMetadata{}).args()...)...)
+		}
+	}
+
+	if bulkPatch.dryRun || batch.Len() == 0 {
+		return result, nil
+	}
+
+	versions, err := sendBulkPatchBatch(ctx, tx, batch)
+	if err != nil {
+		return nil, err
+	}
+	for i, cluster := range changedClusters {
+		auditLog, err := newPatchAuditLog(bulkPatch.userName, cluster.ClusterID, cluster.LeafHubName, cluster.Name,
+			bulkPatch.details)
+		if err != nil {
+			return nil, err
+		}
+		auditLog.SpecVersion = strconv.FormatInt(versions[i], 10)
+		if err := audit.Record(ctx, tx, auditLog); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// sendBulkPatchBatch writes the pending labels, annotations and taints of the managed clusters, it returns the new
+// version of each of them in the order of the batch.
+func sendBulkPatchBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch) ([]int64, error) {
+	results := tx.SendBatch(ctx, batch)
+	versions := make([]int64, batch.Len())
+	for i := range versions {
+		if err := results.QueryRow().Scan(&versions[i]); err != nil {
+			_ = results.Close()
+			return nil, fmt.Errorf("failed to update managed_clusters_labels table: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to update managed_clusters_labels table: %w", err)
+	}
+	return versions, nil
+}
+
+// getBulkPatchClusters returns the managed clusters matching the selectors with their pending labels, annotations and
+// taints, which are locked for update until the end of the transaction.
+func getBulkPatchClusters(ctx context.Context, tx pgx.Tx, selectorInSql string, args []interface{},
+) ([]*bulkPatchCluster, error) {
+	rows, err := tx.Query(ctx, "SELECT cluster_id, cluster_name, leaf_hub_name, payload -> 'metadata' -> 'labels', "+
+		"payload -> 'metadata' -> 'annotations', payload -> 'spec' -> 'taints' FROM status.managed_clusters "+
+		"WHERE deleted_at is NULL"+selectorInSql+" ORDER BY (cluster_name, cluster_id)", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from managed_clusters: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := []*bulkPatchCluster{}
+	clusterMap := map[string]*bulkPatchCluster{}
+	for rows.Next() {
+		cluster := &bulkPatchCluster{}
+		if err := rows.Scan(&cluster.clusterID, &cluster.name, &cluster.leafHubName, &cluster.metadata.labels,
+			&cluster.metadata.annotations, &cluster.taints); err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+		cluster.metadata.taints = taintsToMap(cluster.taints)
+		// the metadata is patched by the cluster id, so the cluster reported by more than one leaf hub is patched once
+		if _, found := clusterMap[cluster.clusterID]; found {
+			continue
+		}
+		clusters = append(clusters, cluster)
+		clusterMap[cluster.clusterID] = cluster
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read from managed_clusters: %w", err)
+	}
+
+	if len(clusters) == 0 {
+		return clusters, nil
+	}
+
+	clusterIDs := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterIDs = append(clusterIDs, cluster.clusterID)
+	}
+
+	pendingRows, err := tx.Query(ctx, "SELECT id, "+pendingMetadataColumns+" FROM spec.managed_clusters_labels "+
+		"WHERE id = ANY($1::uuid[]) FOR UPDATE", clusterIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from managed_clusters_labels: %w", err)
+	}
+	defer pendingRows.Close()
+
+	for pendingRows.Next() {
+		var clusterID string
+		pending := &pendingMetadata{}
+		if err := pendingRows.Scan(append([]interface{}{&clusterID}, pending.scanArgs()...)...); err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+		if cluster, found := clusterMap[clusterID]; found {
+			cluster.pending = pending
+		}
+	}
+
+	return clusters, pendingRows.Err()
+}
+
+// desiredTaints returns the desired taints, the time the taints were added on the leaf hub is kept for the ones that
+// aren't changed.
+func desiredTaints(currentTaints []clusterv1.Taint, desired map[string]string) []clusterv1.Taint {
+	taints := mapToTaints(desired)
+	for i, taint := range taints {
+		for _, current := range currentTaints {
+			if current.Key == taint.Key && current.Value == taint.Value && current.Effect == taint.Effect {
+				taints[i].TimeAdded = current.TimeAdded
+			}
+		}
+	}
+	return taints
+}
+
+// applyLabels returns a copy of the labels with the labels to add and the keys to remove applied
+func applyLabels(labels, labelsToAdd map[string]string, keysToRemove []string) map[string]string {
+	applied := make(map[string]string, len(labels)+len(labelsToAdd))
+	for key, value := range labels {
+		applied[key] = value
+	}
+	for _, key := range keysToRemove {
+		delete(applied, key)
+	}
+	for key, value := range labelsToAdd {
+		applied[key] = value
+	}
+	return applied
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBulkSelectors(t *testing.T) {
+	cases := []struct {
+		name          string
+		labelSelector string
+		fieldSelector string
+		expectedSql   string
+		expectedArgs  []interface{}
+		expectErr     bool
+	}{
+		{
+			name:      "no selector",
+			expectErr: true,
+		},
+		{
+			name:          "label selector",
+			labelSelector: "environment=dev",
+			expectedSql:   " AND payload -> 'metadata' -> 'labels' @> '{\"environment\": \"dev\"}'",
+			expectedArgs:  []interface{}{},
+		},
+		{
+			name:          "label and field selectors",
+			labelSelector: "!vendor",
+			fieldSelector: "leafHubName=hub1,metadata.name!=cluster1",
+			expectedSql: " AND NOT (payload -> 'metadata' -> 'labels' ? 'vendor') AND leaf_hub_name = $1" +
+				" AND cluster_name <> $2",
+			expectedArgs: []interface{}{"hub1", "cluster1"},
+		},
+		{
+			name:          "set based label selector",
+			labelSelector: "environment in (dev,qa)",
+			expectErr:     true,
+		},
+		{
+			name:          "invalid label selector",
+			labelSelector: "environment='dev'",
+			expectErr:     true,
+		},
+		{
+			name:          "unsupported field",
+			fieldSelector: "status.version=4.12",
+			expectErr:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			selectorInSql, args, err := parseBulkSelectors(c.labelSelector, c.fieldSelector)
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedSql, selectorInSql)
+			assert.Equal(t, c.expectedArgs, args)
+		})
+	}
+}
+
+func TestApplyLabels(t *testing.T) {
+	labels := map[string]string{"environment": "dev", "vendor": "OpenShift"}
+	applied := applyLabels(labels, map[string]string{"environment": "prod"}, []string{"vendor"})
+	assert.Equal(t, map[string]string{"environment": "prod"}, applied)
+	// the labels aren't changed
+	assert.Equal(t, map[string]string{"environment": "dev", "vendor": "OpenShift"}, labels)
+
+	assert.Equal(t, map[string]string{}, applyLabels(nil, nil, []string{"vendor"}))
+}
+
+func TestMergeLabels(t *testing.T) {
+	labelsToAdd, labelsToRemove := mergeLabels(
+		map[string]string{"environment": "prod"}, map[string]string{"environment": "dev", "tier": "1"},
+		map[string]struct{}{"tier": {}}, map[string]struct{}{"environment": {}, "vendor": {}})
+	assert.Equal(t, map[string]string{"environment": "prod"}, labelsToAdd)
+	assert.Equal(t, map[string]struct{}{"tier": {}, "vendor": {}}, labelsToRemove)
+}
+
+func TestIsWriteConflict(t *testing.T) {
+	assert.True(t, isWriteConflict(fmt.Errorf("failed to update a row: %w", errOptimisticConcurrencyWriteFailed)))
+	assert.True(t, isWriteConflict(fmt.Errorf("failed to commit transaction: %w", &pgconn.PgError{Code: "40001"})))
+	assert.True(t, isWriteConflict(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, isWriteConflict(&pgconn.PgError{Code: "42P01"}))
+	assert.False(t, isWriteConflict(errors.New("connection refused")))
+	assert.False(t, isWriteConflict(fmt.Errorf("%w: 200 managed clusters match", errTooManyClustersMatched)))
+}
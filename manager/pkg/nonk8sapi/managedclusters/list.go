@@ -31,7 +31,7 @@ import (
 const (
 	serverInternalErrorMsg                      = "internal error"
 	syncIntervalInSeconds                       = 4
-	onlyPatchOfLabelsIsImplemented              = "only patch of labels is currently implemented"
+	onlyPatchOfMetadataIsImplemented            = "only patch of labels, annotations or taints is implemented"
 	onlyAddOrRemoveAreImplemented               = "only add or remove operations are currently implemented"
 	noRowsAffectedByOptimisticConcurrencyUpdate = "no rows were affected by an optimistic-concurrency update query"
 	optimisticConcurrencyRetryAttempts          = 5
@@ -44,6 +44,8 @@ const (
 // @accept json
 // @produce json
 // @param        labelSelector    query     string  false  "list managed clusters by label selector"
+// @param        addon            query     string  false  "list managed clusters with the addon"
+// @param        addonStatus      query     string  false  "available, unavailable, degraded, progressing or healthy"
 // @param        limit            query     int     false  "maximum managed cluster number to receive"
 // @param        continue         query     string  false  "continue token to request next request"
 // @success      200  {object}    clusterv1.ManagedClusterList
@@ -75,6 +77,13 @@ func ListManagedClusters(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
 
 		fmt.Fprintf(gin.DefaultWriter, "parsed selector: %s\n", selectorInSql)
 
+		addonFilterInSql, err := parseAddonFilter(ginCtx.Query("addon"), ginCtx.Query("addonStatus"))
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to parse addon filter: %s\n", err.Error())
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
 		limit := ginCtx.Query("limit")
 		fmt.Fprintf(gin.DefaultWriter, "limit: %v\n", limit)
 
@@ -111,6 +120,7 @@ func ListManagedClusters(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
 		managedClusterListQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL AND " +
 			LastResourceCompareCondition +
 			selectorInSql +
+			addonFilterInSql +
 			" ORDER BY (payload -> 'metadata' ->> 'name', cluster_id)"
 
 		// add limit
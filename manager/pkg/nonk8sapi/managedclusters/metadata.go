@@ -0,0 +1,295 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+const (
+	labelsPathPrefix      = "/metadata/labels/"
+	annotationsPathPrefix = "/metadata/annotations/"
+	taintsPathPrefix      = "/spec/taints/"
+
+	// the columns of the labels, annotations and taints pending to be sent to the leaf hub
+	pendingMetadataColumns = `labels, deleted_label_keys, annotations, deleted_annotation_keys, taints,
+		deleted_taint_keys`
+	insertPendingMetadataQuery = `INSERT INTO spec.managed_clusters_labels (id, leaf_hub_name, managed_cluster_name,
+		labels, deleted_label_keys, annotations, deleted_annotation_keys, taints, deleted_taint_keys, version,
+		updated_at) values($1, $2, $3, $4::jsonb, $5::jsonb, $6::jsonb, $7::jsonb, $8::jsonb, $9::jsonb, 0, now())`
+	updatePendingMetadataQuery = `UPDATE spec.managed_clusters_labels SET
+		labels = $1::jsonb,
+		deleted_label_keys = $2::jsonb,
+		annotations = $3::jsonb,
+		deleted_annotation_keys = $4::jsonb,
+		taints = $5::jsonb,
+		deleted_taint_keys = $6::jsonb,
+		version = version + 1,
+		updated_at = now()
+		WHERE id=$7`
+)
+
+// the taints managed by the hub of the leaf hub, they can't be added or removed from the global hub
+var reservedTaintKeys = map[string]struct{}{
+	clusterv1.ManagedClusterTaintUnavailable: {},
+	clusterv1.ManagedClusterTaintUnreachable: {},
+}
+
+// metadataPatch is the labels, annotations and taints to add and remove by the patches, the taints are keyed by
+// "<key>:<effect>" with their values, since the taints of a managed cluster are unique by the key and the effect.
+type metadataPatch struct {
+	labelsToAdd         map[string]string
+	labelsToRemove      map[string]struct{}
+	annotationsToAdd    map[string]string
+	annotationsToRemove map[string]struct{}
+	taintsToAdd         map[string]string
+	taintsToRemove      map[string]struct{}
+}
+
+// pendingMetadata is the labels, annotations and taints pending to be sent to the leaf hub
+type pendingMetadata struct {
+	labels                map[string]string
+	deletedLabelKeys      []string
+	annotations           map[string]string
+	deletedAnnotationKeys []string
+	taints                []clusterv1.Taint
+	deletedTaintKeys      []string
+}
+
+// clusterMetadata is the labels, annotations and taints of a managed cluster, the taints are keyed by
+// "<key>:<effect>" with their values.
+type clusterMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+	taints      map[string]string
+}
+
+func (p *metadataPatch) isEmpty() bool {
+	return len(p.labelsToAdd) == 0 && len(p.labelsToRemove) == 0 &&
+		len(p.annotationsToAdd) == 0 && len(p.annotationsToRemove) == 0 &&
+		len(p.taintsToAdd) == 0 && len(p.taintsToRemove) == 0
+}
+
+// mergeInto merges the patch into the pending labels, annotations and taints, the patch wins over the pending ones.
+func (p *metadataPatch) mergeInto(pending *pendingMetadata) *pendingMetadata {
+	labels, deletedLabelKeys := mergeLabels(p.labelsToAdd, pending.labels, p.labelsToRemove,
+		getMap(pending.deletedLabelKeys))
+	annotations, deletedAnnotationKeys := mergeLabels(p.annotationsToAdd, pending.annotations,
+		p.annotationsToRemove, getMap(pending.deletedAnnotationKeys))
+	taints, deletedTaintKeys := mergeLabels(p.taintsToAdd, taintsToMap(pending.taints), p.taintsToRemove,
+		getMap(pending.deletedTaintKeys))
+
+	return &pendingMetadata{
+		labels:                labels,
+		deletedLabelKeys:      getKeys(deletedLabelKeys),
+		annotations:           annotations,
+		deletedAnnotationKeys: getKeys(deletedAnnotationKeys),
+		taints:                mapToTaints(taints),
+		deletedTaintKeys:      getKeys(deletedTaintKeys),
+	}
+}
+
+// applyTo returns a copy of the labels, annotations and taints of the managed cluster with the patch applied
+func (p *metadataPatch) applyTo(metadata *clusterMetadata) *clusterMetadata {
+	return &clusterMetadata{
+		labels:      applyLabels(metadata.labels, p.labelsToAdd, getKeys(p.labelsToRemove)),
+		annotations: applyLabels(metadata.annotations, p.annotationsToAdd, getKeys(p.annotationsToRemove)),
+		taints:      applyLabels(metadata.taints, p.taintsToAdd, getKeys(p.taintsToRemove)),
+	}
+}
+
+// details returns the patch to be recorded in the audit log
+func (p *metadataPatch) details() map[string]interface{} {
+	return map[string]interface{}{
+		"labelsToAdd":         p.labelsToAdd,
+		"labelsToRemove":      getKeys(p.labelsToRemove),
+		"annotationsToAdd":    p.annotationsToAdd,
+		"annotationsToRemove": getKeys(p.annotationsToRemove),
+		"taintsToAdd":         p.taintsToAdd,
+		"taintsToRemove":      getKeys(p.taintsToRemove),
+	}
+}
+
+// scanArgs returns the destinations to scan the pending metadata columns into
+func (m *pendingMetadata) scanArgs() []interface{} {
+	return []interface{}{
+		&m.labels, &m.deletedLabelKeys, &m.annotations, &m.deletedAnnotationKeys, &m.taints, &m.deletedTaintKeys,
+	}
+}
+
+// args returns the arguments to write the pending metadata columns
+func (m *pendingMetadata) args() []interface{} {
+	return []interface{}{
+		m.labels, m.deletedLabelKeys, m.annotations, m.deletedAnnotationKeys, m.taints, m.deletedTaintKeys,
+	}
+}
+
+// withPending returns a copy of the labels, annotations and taints of the managed cluster with the pending ones
+// applied, which is the state of the managed cluster once they're sent to the leaf hub.
+func (m *clusterMetadata) withPending(pending *pendingMetadata) *clusterMetadata {
+	if pending == nil {
+		pending = &pendingMetadata{}
+	}
+	return &clusterMetadata{
+		labels:      applyLabels(m.labels, pending.labels, pending.deletedLabelKeys),
+		annotations: applyLabels(m.annotations, pending.annotations, pending.deletedAnnotationKeys),
+		taints:      applyLabels(m.taints, taintsToMap(pending.taints), pending.deletedTaintKeys),
+	}
+}
+
+// getMetadataPatch returns the labels, annotations and taints to add and remove by the patches, the response is
+// written if the patches are invalid.
+func getMetadataPatch(ginCtx *gin.Context, patches []patch) (*metadataPatch, error) {
+	metadataPatch := &metadataPatch{
+		labelsToAdd:         make(map[string]string),
+		labelsToRemove:      make(map[string]struct{}),
+		annotationsToAdd:    make(map[string]string),
+		annotationsToRemove: make(map[string]struct{}),
+		taintsToAdd:         make(map[string]string),
+		taintsToRemove:      make(map[string]struct{}),
+	}
+
+	// from https://datatracker.ietf.org/doc/html/rfc6902:
+	// Evaluation of a JSON Patch document begins against a target JSON
+	// document.  Operations are applied sequentially in the order they
+	// appear in the array.  Each operation in the sequence is applied to
+	// the target document; the resulting document becomes the target of the
+	// next operation.  Evaluation continues until all operations are
+	// successfully applied or until an error condition is encountered.
+
+	for _, aPatch := range patches {
+		var (
+			toAdd    map[string]string
+			toRemove map[string]struct{}
+			rawKey   string
+		)
+
+		switch {
+		case strings.HasPrefix(aPatch.Path, labelsPathPrefix):
+			toAdd, toRemove = metadataPatch.labelsToAdd, metadataPatch.labelsToRemove
+			rawKey = strings.TrimPrefix(aPatch.Path, labelsPathPrefix)
+		case strings.HasPrefix(aPatch.Path, annotationsPathPrefix):
+			toAdd, toRemove = metadataPatch.annotationsToAdd, metadataPatch.annotationsToRemove
+			rawKey = strings.TrimPrefix(aPatch.Path, annotationsPathPrefix)
+		case strings.HasPrefix(aPatch.Path, taintsPathPrefix):
+			toAdd, toRemove = metadataPatch.taintsToAdd, metadataPatch.taintsToRemove
+			rawKey = strings.TrimPrefix(aPatch.Path, taintsPathPrefix)
+		default:
+			ginCtx.JSON(http.StatusNotImplemented, gin.H{
+				"status": onlyPatchOfMetadataIsImplemented,
+			})
+
+			return nil, errOnlyPatchOfMetadataIsImplemented
+		}
+
+		key, value := strings.Replace(rawKey, "~1", "/", 1), aPatch.Value
+		if aPatch.Op != "add" && aPatch.Op != "remove" {
+			ginCtx.JSON(http.StatusNotImplemented, gin.H{
+				"status": onlyAddOrRemoveAreImplemented,
+			})
+
+			return nil, errOnlyAddOrRemoveAreImplemented
+		}
+
+		if strings.HasPrefix(aPatch.Path, taintsPathPrefix) {
+			if err := validateTaint(key, aPatch); err != nil {
+				ginCtx.String(http.StatusBadRequest, err.Error())
+				return nil, err
+			}
+			taint := toTaint(key, aPatch.Value)
+			key, value = spec.TaintKey(taint.Key, taint.Effect), taint.Value
+		}
+
+		if aPatch.Op == "add" {
+			delete(toRemove, key)
+
+			toAdd[key] = value
+
+			continue
+		}
+
+		delete(toAdd, key)
+
+		toRemove[key] = struct{}{}
+	}
+
+	return metadataPatch, nil
+}
+
+// validateTaint validates the taint patch, the value of the taint to add is "<value>:<effect>", and the value of the
+// taint to remove is "<effect>" since the taints of the same key with the different effects are removed on their own.
+func validateTaint(key string, aPatch patch) error {
+	if _, reserved := reservedTaintKeys[key]; reserved {
+		return fmt.Errorf("taint %s is managed by the leaf hub", key)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid taint key %s: %s", key, strings.Join(errs, ", "))
+	}
+
+	taint := toTaint(key, aPatch.Value)
+	switch taint.Effect {
+	case clusterv1.TaintEffectNoSelect, clusterv1.TaintEffectPreferNoSelect, clusterv1.TaintEffectNoSelectIfNew:
+	default:
+		format := "<value>:<effect>"
+		if aPatch.Op != "add" {
+			format = "<effect>"
+		}
+		return fmt.Errorf("invalid taint %s=%s, should be %s, the effect is one of: %s, %s or %s", key,
+			aPatch.Value, format, clusterv1.TaintEffectNoSelect, clusterv1.TaintEffectPreferNoSelect,
+			clusterv1.TaintEffectNoSelectIfNew)
+	}
+	if aPatch.Op == "add" && taint.Value != "" {
+		if errs := validation.IsValidLabelValue(taint.Value); len(errs) > 0 {
+			return fmt.Errorf("invalid taint value %s: %s", taint.Value, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// toTaint returns the taint of the key and the "<value>:<effect>" value
+func toTaint(key, value string) clusterv1.Taint {
+	taint := clusterv1.Taint{Key: key}
+	if index := strings.LastIndex(value, ":"); index >= 0 {
+		taint.Value = value[:index]
+		taint.Effect = clusterv1.TaintEffect(value[index+1:])
+	} else {
+		taint.Effect = clusterv1.TaintEffect(value)
+	}
+	return taint
+}
+
+// taintsToMap returns the taints keyed by "<key>:<effect>" with their values
+func taintsToMap(taints []clusterv1.Taint) map[string]string {
+	taintMap := make(map[string]string, len(taints))
+	for _, taint := range taints {
+		taintMap[spec.TaintKey(taint.Key, taint.Effect)] = taint.Value
+	}
+	return taintMap
+}
+
+// mapToTaints returns the taints, ordered by their keys and effects, of the taints keyed by "<key>:<effect>"
+func mapToTaints(taintMap map[string]string) []clusterv1.Taint {
+	taints := make([]clusterv1.Taint, 0, len(taintMap))
+	for taintKey, value := range taintMap {
+		key, effect := spec.ParseTaintKey(taintKey)
+		taints = append(taints, clusterv1.Taint{Key: key, Value: value, Effect: effect})
+	}
+	sort.Slice(taints, func(i, j int) bool {
+		if taints[i].Key != taints[j].Key {
+			return taints[i].Key < taints[j].Key
+		}
+		return taints[i].Effect < taints[j].Effect
+	})
+	return taints
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestGetMetadataPatch(t *testing.T) {
+	cases := []struct {
+		name           string
+		patches        []patch
+		expected       *metadataPatch
+		expectedStatus int
+	}{
+		{
+			name: "labels, annotations and taints",
+			patches: []patch{
+				{Op: "add", Path: "/metadata/labels/environment", Value: "dev"},
+				{Op: "remove", Path: "/metadata/labels/vendor"},
+				{Op: "add", Path: "/metadata/annotations/owner", Value: "team-a"},
+				{Op: "add", Path: "/spec/taints/maintenance", Value: "true:NoSelect"},
+				{Op: "remove", Path: "/spec/taints/example.com~1gpu", Value: "PreferNoSelect"},
+			},
+			expected: &metadataPatch{
+				labelsToAdd:         map[string]string{"environment": "dev"},
+				labelsToRemove:      map[string]struct{}{"vendor": {}},
+				annotationsToAdd:    map[string]string{"owner": "team-a"},
+				annotationsToRemove: map[string]struct{}{},
+				taintsToAdd:         map[string]string{"maintenance:NoSelect": "true"},
+				taintsToRemove:      map[string]struct{}{"example.com/gpu:PreferNoSelect": {}},
+			},
+		},
+		{
+			name: "taints of the same key",
+			patches: []patch{
+				{Op: "add", Path: "/spec/taints/maintenance", Value: "true:NoSelect"},
+				{Op: "add", Path: "/spec/taints/maintenance", Value: "false:PreferNoSelect"},
+				{Op: "remove", Path: "/spec/taints/maintenance", Value: "NoSelectIfNew"},
+			},
+			expected: &metadataPatch{
+				labelsToAdd:         map[string]string{},
+				labelsToRemove:      map[string]struct{}{},
+				annotationsToAdd:    map[string]string{},
+				annotationsToRemove: map[string]struct{}{},
+				taintsToAdd: map[string]string{
+					"maintenance:NoSelect":       "true",
+					"maintenance:PreferNoSelect": "false",
+				},
+				taintsToRemove: map[string]struct{}{"maintenance:NoSelectIfNew": {}},
+			},
+		},
+		{
+			name:           "unsupported path",
+			patches:        []patch{{Op: "add", Path: "/spec/hubAcceptsClient", Value: "false"}},
+			expectedStatus: http.StatusNotImplemented,
+		},
+		{
+			name:           "unsupported operation",
+			patches:        []patch{{Op: "replace", Path: "/metadata/annotations/owner", Value: "team-b"}},
+			expectedStatus: http.StatusNotImplemented,
+		},
+		{
+			name:           "invalid taint effect",
+			patches:        []patch{{Op: "add", Path: "/spec/taints/maintenance", Value: "true:NoSchedule"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "taint to remove without effect",
+			patches:        []patch{{Op: "remove", Path: "/spec/taints/maintenance"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "reserved taint",
+			patches: []patch{
+				{Op: "remove", Path: "/spec/taints/cluster.open-cluster-management.io~1unavailable"},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ginCtx, _ := gin.CreateTestContext(recorder)
+
+			metadataPatch, err := getMetadataPatch(ginCtx, c.patches)
+			if c.expectedStatus != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, c.expectedStatus, recorder.Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, metadataPatch)
+		})
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	metadataPatch := &metadataPatch{
+		labelsToAdd:         map[string]string{"environment": "prod"},
+		annotationsToRemove: map[string]struct{}{"owner": {}},
+		taintsToAdd:         map[string]string{"maintenance:NoSelect": ""},
+		taintsToRemove:      map[string]struct{}{"gpu:NoSelect": {}},
+	}
+	pending := metadataPatch.mergeInto(&pendingMetadata{
+		labels:      map[string]string{"environment": "dev"},
+		annotations: map[string]string{"owner": "team-a"},
+		taints: []clusterv1.Taint{
+			{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+			{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+		},
+		deletedTaintKeys: []string{"maintenance:NoSelect", "maintenance:PreferNoSelect"},
+	})
+
+	assert.Equal(t, map[string]string{"environment": "prod"}, pending.labels)
+	assert.Equal(t, []string{}, pending.deletedLabelKeys)
+	assert.Equal(t, map[string]string{}, pending.annotations)
+	assert.Equal(t, []string{"owner"}, pending.deletedAnnotationKeys)
+	assert.Equal(t, []clusterv1.Taint{
+		{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+		{Key: "maintenance", Effect: clusterv1.TaintEffectNoSelect},
+	}, pending.taints)
+	assert.ElementsMatch(t, []string{"gpu:NoSelect", "maintenance:PreferNoSelect"}, pending.deletedTaintKeys)
+}
+
+func TestApplyTo(t *testing.T) {
+	metadata := (&clusterMetadata{
+		labels: map[string]string{"environment": "dev"},
+		taints: taintsToMap([]clusterv1.Taint{
+			{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectNoSelect},
+			{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+		}),
+	}).withPending(&pendingMetadata{deletedTaintKeys: []string{"maintenance:NoSelect"}})
+	assert.Equal(t, map[string]string{"maintenance:PreferNoSelect": "true"}, metadata.taints)
+
+	desired := (&metadataPatch{
+		annotationsToAdd: map[string]string{"owner": "team-a"},
+		taintsToAdd:      map[string]string{"gpu:PreferNoSelect": "true"},
+	}).applyTo(metadata)
+	assert.Equal(t, map[string]string{"environment": "dev"}, desired.labels)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, desired.annotations)
+	assert.Equal(t, map[string]string{"maintenance:PreferNoSelect": "true", "gpu:PreferNoSelect": "true"},
+		desired.taints)
+	assert.Equal(t, []clusterv1.Taint{
+		{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+		{Key: "maintenance", Value: "true", Effect: clusterv1.TaintEffectPreferNoSelect},
+	}, mapToTaints(desired.taints))
+}
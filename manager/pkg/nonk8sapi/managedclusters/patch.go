@@ -5,17 +5,25 @@ package managedclusters
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 var (
-	errOnlyPatchOfLabelsIsImplemented   = errors.New(onlyPatchOfLabelsIsImplemented)
+	errOnlyPatchOfMetadataIsImplemented = errors.New(onlyPatchOfMetadataIsImplemented)
 	errOnlyAddOrRemoveAreImplemented    = errors.New(onlyAddOrRemoveAreImplemented)
 	errOptimisticConcurrencyWriteFailed = errors.New(noRowsAffectedByOptimisticConcurrencyUpdate)
 )
@@ -27,12 +35,13 @@ type patch struct {
 }
 
 // PatchManagedCluster godoc
-// @summary patch managed cluster label
-// @description patch label for a given managed cluster
+// @summary patch managed cluster labels, annotations and taints
+// @description patch labels, annotations and taints for a given managed cluster, the value of the taint to add in the
+// @description path /spec/taints/{key} is "<value>:<effect>", and the value of the taint to remove is "<effect>".
 // @accept json
 // @produce json
 // @param        clusterID    path    string    true    "Managed Cluster ID"
-// @param        patch        body    patch     true    "JSON patch that operators on managed cluster label"
+// @param        patch        body    patch     true    "JSON patch that operators on managed cluster metadata"
 // @success      200
 // @failure      400
 // @failure      401
@@ -67,21 +76,33 @@ func PatchManagedCluster(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		labelsToAdd, labelsToRemove, err := getLabels(ginCtx, patches)
+		metadataPatch, err := getMetadataPatch(ginCtx, patches)
 		if err != nil {
-			fmt.Fprintf(gin.DefaultWriter, "failed to get labels: %s\n", err.Error())
+			fmt.Fprintf(gin.DefaultWriter, "failed to get labels, annotations and taints: %s\n", err.Error())
 			return
 		}
 
-		fmt.Fprintf(gin.DefaultWriter, "labels to add: %v\n", labelsToAdd)
-		fmt.Fprintf(gin.DefaultWriter, "labels to remove: %v\n", labelsToRemove)
+		fmt.Fprintf(gin.DefaultWriter, "labels to add: %v\n", metadataPatch.labelsToAdd)
+		fmt.Fprintf(gin.DefaultWriter, "labels to remove: %v\n", metadataPatch.labelsToRemove)
+		fmt.Fprintf(gin.DefaultWriter, "annotations to add: %v\n", metadataPatch.annotationsToAdd)
+		fmt.Fprintf(gin.DefaultWriter, "annotations to remove: %v\n", metadataPatch.annotationsToRemove)
+		fmt.Fprintf(gin.DefaultWriter, "taints to add: %v\n", metadataPatch.taintsToAdd)
+		fmt.Fprintf(gin.DefaultWriter, "taints to remove: %v\n", metadataPatch.taintsToRemove)
+
+		auditLog, err := newPatchAuditLog(ginCtx.GetString(authentication.UserKey), clusterID, leafHubName,
+			managedClusterName, metadataPatch.details())
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, "internal error")
+			fmt.Fprintf(gin.DefaultWriter, "error in updating managed cluster metadata: %v\n", err)
+			return
+		}
 
 		retryAttempts := optimisticConcurrencyRetryAttempts
 
 		for retryAttempts > 0 {
-			err = updateLabels(clusterID, leafHubName, managedClusterName, labelsToAdd,
-				labelsToRemove, dbConnectionPool)
-			if err == nil {
+			err = updateMetadata(ginCtx.Request.Context(), clusterID, leafHubName, managedClusterName,
+				metadataPatch, auditLog, dbConnectionPool)
+			if err == nil || !isWriteConflict(err) {
 				break
 			}
 
@@ -90,68 +111,111 @@ func PatchManagedCluster(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
 
 		if err != nil {
 			ginCtx.String(http.StatusInternalServerError, "internal error")
-			fmt.Fprintf(gin.DefaultWriter, "error in updating managed cluster labels: %v\n", err)
+			fmt.Fprintf(gin.DefaultWriter, "error in updating managed cluster metadata: %v\n", err)
+			return
 		}
 
-		ginCtx.String(http.StatusOK, "managed cluster label patched")
+		ginCtx.String(http.StatusOK, "managed cluster metadata patched")
 	}
 }
 
-func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd map[string]string,
-	labelsToRemove map[string]struct{}, dbConnectionPool *pgxpool.Pool,
+// updateMetadata merges the patch into the labels, annotations and taints pending to be sent to the leaf hub, and
+// records the patch in the audit log within the same transaction. the spec version of the audit log is the version
+// of the pending labels, annotations and taints.
+func updateMetadata(ctx context.Context, clusterID, leafHubName, managedClusterName string,
+	metadataPatch *metadataPatch, auditLog *models.AuditLog, dbConnectionPool *pgxpool.Pool,
 ) error {
-	if len(labelsToAdd) == 0 && len(labelsToRemove) == 0 {
+	if metadataPatch.isEmpty() {
 		return nil
 	}
 
-	rows, err := dbConnectionPool.Query(context.TODO(),
-		"SELECT labels, deleted_label_keys, version from spec.managed_clusters_labels WHERE id = $1", clusterID)
-	if err != nil {
-		return fmt.Errorf("failed to read from managed_clusters_labels: %w", err)
-	}
-	defer rows.Close()
-
-	if !rows.Next() { // insert the labels
-		_, err := dbConnectionPool.Exec(context.TODO(),
-			`INSERT INTO spec.managed_clusters_labels (id, leaf_hub_name, managed_cluster_name, labels,
-			deleted_label_keys, version, updated_at) values($1, $2, $3, $4::jsonb, $5::jsonb, 0, now())`,
-			clusterID, leafHubName, managedClusterName, labelsToAdd, getKeys(labelsToRemove))
+	return dbConnectionPool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			"SELECT "+pendingMetadataColumns+", version from spec.managed_clusters_labels WHERE id = $1", clusterID)
 		if err != nil {
-			return fmt.Errorf("failed to insert into the managed_clusters_labels table: %w", err)
+			return fmt.Errorf("failed to read from managed_clusters_labels: %w", err)
 		}
 
-		return nil
-	}
+		var (
+			currentPending pendingMetadata
+			version        int64
+		)
+		found := rows.Next()
+		if found {
+			if err := rows.Scan(append(currentPending.scanArgs(), &version)...); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan a row: %w", err)
+			}
+			// assumimg there is a single row
+			if rows.Next() {
+				fmt.Fprintf(gin.DefaultWriter, "Warning: more than one row for cluster with ID %s\n", clusterID)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read from managed_clusters_labels: %w", err)
+		}
 
-	var (
-		currentLabelsToAdd         map[string]string
-		currentLabelsToRemoveSlice []string
-		version                    int64
-	)
+		if !found { // insert the labels, annotations and taints
+			pending := metadataPatch.mergeInto(&pendingMetadata{})
+			if err := tx.QueryRow(ctx, insertPendingMetadataQuery+" RETURNING version",
+				append([]interface{}{clusterID, leafHubName, managedClusterName}, pending.args()...)...).
+				Scan(&version); err != nil {
+				return fmt.Errorf("failed to insert into the managed_clusters_labels table: %w", err)
+			}
+		} else if version, err = updateRow(ctx, tx, clusterID, metadataPatch, &currentPending, version); err != nil {
+			return fmt.Errorf("failed to update managed_clusters_labels table: %w", err)
+		}
 
-	err = rows.Scan(&currentLabelsToAdd, &currentLabelsToRemoveSlice, &version)
-	if err != nil {
-		return fmt.Errorf("failed to scan a row: %w", err)
-	}
+		auditLog.SpecVersion = strconv.FormatInt(version, 10)
+		return audit.Record(ctx, tx, auditLog)
+	})
+}
 
-	err = updateRow(clusterID, labelsToAdd, currentLabelsToAdd, labelsToRemove,
-		getMap(currentLabelsToRemoveSlice), version, dbConnectionPool)
+// updateRow updates the pending labels, annotations and taints under optimistic concurrency, it returns the new
+// version of the row.
+func updateRow(ctx context.Context, tx pgx.Tx, clusterID string, metadataPatch *metadataPatch,
+	currentPending *pendingMetadata, version int64,
+) (int64, error) {
+	pending := metadataPatch.mergeInto(currentPending)
+
+	var newVersion int64
+	err := tx.QueryRow(ctx, updatePendingMetadataQuery+" AND version=$8 RETURNING version",
+		append(pending.args(), clusterID, version)...).Scan(&newVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to update a row: %w", errOptimisticConcurrencyWriteFailed)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update managed_clusters_labels table: %w", err)
+		return 0, fmt.Errorf("failed to update a row: %w", err)
 	}
 
-	// assumimg there is a single row
-	if rows.Next() {
-		fmt.Fprintf(gin.DefaultWriter, "Warning: more than one row for cluster with ID %s\n", clusterID)
-	}
+	return newVersion, nil
+}
 
-	return nil
+// isWriteConflict returns true if the write failed because of a concurrent write of the same managed clusters, only
+// these writes are retried: the optimistic-concurrency update, the serialization failure, the deadlock and the unique
+// violation of the pending row inserted by a concurrent patch.
+func isWriteConflict(err error) bool {
+	if errors.Is(err, errOptimisticConcurrencyWriteFailed) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "40001", "40P01", "23505":
+		return true
+	default:
+		return false
+	}
 }
 
-func updateRow(clusterID string, labelsToAdd, currentLabelsToAdd map[string]string,
+// mergeLabels merges the labels to add and remove of the patch into the labels to add and remove that are pending
+// to be sent to the leaf hub, the patch wins over the pending labels.
+func mergeLabels(labelsToAdd, currentLabelsToAdd map[string]string,
 	labelsToRemove, currentLabelsToRemove map[string]struct{},
-	version int64, dbConnectionPool *pgxpool.Pool,
-) error {
+) (map[string]string, map[string]struct{}) {
 	newLabelsToAdd := make(map[string]string)
 	newLabelsToRemove := make(map[string]struct{})
 
@@ -175,23 +239,32 @@ func updateRow(clusterID string, labelsToAdd, currentLabelsToAdd map[string]stri
 		newLabelsToAdd[key] = value
 	}
 
-	commandTag, err := dbConnectionPool.Exec(context.TODO(),
-		`UPDATE spec.managed_clusters_labels SET
-		labels = $1::jsonb,
-		deleted_label_keys = $2::jsonb,
-		version = version + 1,
-		updated_at = now()
-		WHERE id=$3 AND version=$4`,
-		newLabelsToAdd, getKeys(newLabelsToRemove), clusterID, version)
+	return newLabelsToAdd, newLabelsToRemove
+}
+
+// newPatchAuditLog returns the audit log of the patch of the managed cluster by the authenticated user.
+func newPatchAuditLog(userName, clusterID, leafHubName, managedClusterName string,
+	patchDetails map[string]interface{},
+) (*models.AuditLog, error) {
+	targetHubs, err := json.Marshal([]string{leafHubName})
 	if err != nil {
-		return fmt.Errorf("failed to update a row: %w", err)
+		return nil, err
 	}
-
-	if commandTag.RowsAffected() == 0 {
-		return fmt.Errorf("failed to update a row: %w", errOptimisticConcurrencyWriteFailed)
+	details, err := json.Marshal(patchDetails)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &models.AuditLog{
+		UserName:   userName,
+		Source:     database.AuditSourceAPI,
+		Operation:  database.AuditOperationPatchLabels,
+		Kind:       "ManagedCluster",
+		Name:       managedClusterName,
+		ResourceID: clusterID,
+		TargetHubs: targetHubs,
+		Details:    details,
+	}, nil
 }
 
 func getMap(aSlice []string) map[string]struct{} {
@@ -216,53 +289,3 @@ func getKeys(aMap map[string]struct{}) []string {
 
 	return keys
 }
-
-func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[string]struct{}, error) {
-	labelsToAdd := make(map[string]string)
-	labelsToRemove := make(map[string]struct{})
-
-	// from https://datatracker.ietf.org/doc/html/rfc6902:
-	// Evaluation of a JSON Patch document begins against a target JSON
-	// document.  Operations are applied sequentially in the order they
-	// appear in the array.  Each operation in the sequence is applied to
-	// the target document; the resulting document becomes the target of the
-	// next operation.  Evaluation continues until all operations are
-	// successfully applied or until an error condition is encountered.
-
-	for _, aPatch := range patches {
-		rawLabel := strings.TrimPrefix(aPatch.Path, "/metadata/labels/")
-
-		if rawLabel == aPatch.Path {
-			ginCtx.JSON(http.StatusNotImplemented, gin.H{
-				"status": onlyPatchOfLabelsIsImplemented,
-			})
-
-			return nil, nil, errOnlyPatchOfLabelsIsImplemented
-		}
-
-		label := strings.Replace(rawLabel, "~1", "/", 1)
-		if aPatch.Op == "add" {
-			delete(labelsToRemove, label)
-
-			labelsToAdd[label] = aPatch.Value
-
-			continue
-		}
-
-		if aPatch.Op == "remove" {
-			delete(labelsToAdd, label)
-
-			labelsToRemove[label] = struct{}{}
-
-			continue
-		}
-
-		ginCtx.JSON(http.StatusNotImplemented, gin.H{
-			"status": onlyAddOrRemoveAreImplemented,
-		})
-
-		return nil, nil, errOnlyAddOrRemoveAreImplemented
-	}
-
-	return labelsToAdd, labelsToRemove, nil
-}
@@ -15,9 +15,17 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/appliedstatus"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/argocd"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/auditlogs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/compliancereports"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/events"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/graphql"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/hubs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/managedclusters"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/policyviolations"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/subscriptions"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 )
@@ -30,6 +38,8 @@ type NonK8sAPIServerConfig struct {
 	ClusterAPIURL          string
 	ClusterAPICABundlePath string
 	ServerBasePath         string
+	// AdminGroups are the groups of the users allowed to decommission the leaf hubs
+	AdminGroups []string
 }
 
 // nonK8sApiServer defines the non-k8s-api-server
@@ -106,6 +116,7 @@ func SetupRouter(database db.DB, nonK8sAPIServerConfig *NonK8sAPIServerConfig) (
 
 	routerGroup := router.Group(nonK8sAPIServerConfig.ServerBasePath)
 	routerGroup.GET("/managedclusters", managedclusters.ListManagedClusters(database.GetConn()))
+	routerGroup.PATCH("/managedclusters", managedclusters.PatchManagedClusters(database.GetConn()))
 	routerGroup.PATCH("/managedcluster/:clusterID",
 		managedclusters.PatchManagedCluster(database.GetConn()))
 	routerGroup.GET("/policies", policies.ListPolicies(database.GetConn()))
@@ -113,6 +124,24 @@ func SetupRouter(database db.DB, nonK8sAPIServerConfig *NonK8sAPIServerConfig) (
 	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions(database.GetConn()))
 	routerGroup.GET("/subscriptionreport/:subscriptionID",
 		subscriptions.GetSubscriptionReport(database.GetConn()))
+	routerGroup.GET("/subscription/:subscriptionID/status",
+		subscriptions.GetSubscriptionStatus(database.GetConn()))
+	routerGroup.GET("/appliedstatus/:resourceID", appliedstatus.GetAppliedStatus(database.GetConn()))
+	routerGroup.GET("/events", events.ListEvents(database.GetConn()))
+	routerGroup.GET("/hubs", hubs.ListLeafHubs(database.GetConn()))
+	routerGroup.POST("/hub/:hubName/decommission", hubs.DecommissionLeafHub(nonK8sAPIServerConfig.AdminGroups))
+	routerGroup.GET("/argocdapplications", argocd.ListApplications(database.GetConn()))
+	routerGroup.GET("/argocdapplicationsets", argocd.ListApplicationSets(database.GetConn()))
+	routerGroup.GET("/policyviolations", policyviolations.ListPolicyViolations(database.GetConn()))
+	routerGroup.GET("/auditlogs", auditlogs.ListAuditLogs(database.GetConn()))
+	routerGroup.GET("/auditlogs/export", auditlogs.ExportAuditLogs(database.GetConn()))
+	routerGroup.POST("/graphql", graphql.Query(database.GetConn()))
+	routerGroup.GET("/compliancereports", compliancereports.ListComplianceReports(database.GetConn()))
+	routerGroup.POST("/compliancereports", compliancereports.SaveComplianceReport(database.GetConn()))
+	routerGroup.GET("/compliancereports/export", compliancereports.ExportComplianceReports(database.GetConn()))
+	routerGroup.DELETE("/compliancereport/:name", compliancereports.DeleteComplianceReport(database.GetConn()))
+	routerGroup.GET("/compliancereport/:name/export",
+		compliancereports.ExportComplianceReport(database.GetConn()))
 
 	return router, nil
 }
@@ -6,6 +6,7 @@ package nonk8sapi_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -15,11 +16,17 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/argocd"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/hubs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/policyviolations"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db/postgresql"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 )
 
 type TestResponseRecorder struct {
@@ -436,6 +443,53 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		}, 10*time.Second, 2*time.Second).Should(BeTrue())
 	})
 
+	It("Should be able to list managed clusters by addon health", func() {
+		By("Create managed cluster addons table in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+				cluster_id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				cluster_name character varying(63) NOT NULL,
+				addon_name character varying(253) NOT NULL,
+				available character varying(15) NOT NULL,
+				degraded character varying(15) NOT NULL,
+				progressing character varying(15) NOT NULL,
+				message text,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Insert the addons of the managed clusters")
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.managed_cluster_addons (cluster_id,
+			leaf_hub_name, cluster_name, addon_name, available, degraded, progressing) VALUES
+			('2aa5547c-c172-47ed-b70b-db468c84d327', 'hub1', 'mc1', 'observability-controller', 'True', 'True',
+				'False'),
+			('18c9e13c-4488-4dcd-a5ac-1196093abbc0', 'hub1', 'mc2', 'observability-controller', 'True', 'False',
+				'False')`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the managed clusters with degraded addon can be listed")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET",
+			"/global-hub-api/v1/managedclusters?addon=observability-controller&addonStatus=degraded", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		managedClusterList := &clusterv1.ManagedClusterList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), managedClusterList)).To(Succeed())
+		Expect(managedClusterList.Items).To(HaveLen(1))
+		Expect(managedClusterList.Items[0].Name).To(Equal("mc1"))
+
+		By("Check the invalid addon status is rejected")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/managedclusters?addonStatus=broken", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+	})
+
 	It("Should be able to list policies", func() {
 		plc1ID = uuid.New().String()
 		pr1ID, pb1ID := uuid.New().String(), uuid.New().String()
@@ -1478,6 +1532,393 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		Expect(w1.Body.String()).Should(MatchJSON(subscriptionReportStr))
 	})
 
+	It("Should be able to get the status of a subscription", func() {
+		leafhub1, leafhub2 := "hub1", "hub2"
+		By("Create subscription status table in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS status.subscription_statuses (
+				id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				payload jsonb NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Insert the subscription statuses of the leaf hubs")
+		subStatusHub1 := `{
+			"kind": "SubscriptionStatus",
+			"apiVersion": "apps.open-cluster-management.io/v1alpha1",
+			"metadata": {
+				"name": "foo-appsub",
+				"namespace": "foo",
+				"labels": {"apps.open-cluster-management.io/cluster": "mc1"}
+			},
+			"statuses": {
+				"packages": [
+					{"name": "foo-app-deploy", "kind": "Deployment", "namespace": "foo", "apiVersion": "apps/v1",
+						"phase": "Deployed", "lastUpdateTime": "2022-10-13T05:58:33Z"}
+				]
+			}
+		}`
+		subStatusHub2 := `{
+			"kind": "SubscriptionStatus",
+			"apiVersion": "apps.open-cluster-management.io/v1alpha1",
+			"metadata": {
+				"name": "foo-appsub",
+				"namespace": "foo",
+				"labels": {"apps.open-cluster-management.io/cluster": "mc2"}
+			},
+			"statuses": {
+				"packages": [
+					{"name": "foo-app-deploy", "kind": "Deployment", "namespace": "foo", "apiVersion": "apps/v1",
+						"phase": "Failed", "message": "quota exceeded", "lastUpdateTime": "2022-10-13T05:58:33Z"}
+				]
+			}
+		}`
+		_, err = postgresSQL.GetConn().Exec(ctx,
+			`INSERT INTO status.subscription_statuses (id,leaf_hub_name,payload) VALUES($1, $2, $3), ($1, $4, $5);`,
+			sub2ID, leafhub1, subStatusHub1, leafhub2, subStatusHub2)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the subscription status can be retrieved")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", fmt.Sprintf("/global-hub-api/v1/subscription/%s/status", sub2ID), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+		Expect(w.Body.String()).Should(MatchJSON(fmt.Sprintf(`{
+			"subscriptionId": "%s",
+			"name": "foo-appsub",
+			"namespace": "foo",
+			"summary": {"deployed": 1, "inProgress": 0, "failed": 1, "propagationFailed": 0, "clusters": 2},
+			"clusters": [
+				{
+					"leafHubName": "%s",
+					"clusterName": "mc1",
+					"packages": [{"name": "foo-app-deploy", "kind": "Deployment", "namespace": "foo",
+						"apiVersion": "apps/v1", "phase": "Deployed", "lastUpdateTime": "2022-10-13T05:58:33Z"}]
+				},
+				{
+					"leafHubName": "%s",
+					"clusterName": "mc2",
+					"packages": [{"name": "foo-app-deploy", "kind": "Deployment", "namespace": "foo",
+						"apiVersion": "apps/v1", "phase": "Failed", "message": "quota exceeded",
+						"lastUpdateTime": "2022-10-13T05:58:33Z"}]
+				}
+			]
+		}`, sub2ID, leafhub1, leafhub2)))
+
+		By("Check the status of an unknown subscription is not found")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/subscription/"+
+			"0a6cba32-3c6e-4c39-9d04-7a8e61e7d54a/status", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(404))
+	})
+
+	It("Should be able to list leaf hubs with the effective agent config", func() {
+		By("Create leaf hub tables in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS status.leaf_hub_heartbeats (
+				leaf_hub_name character varying(63) NOT NULL,
+				last_timestamp timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS status.leaf_hubs (
+				leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+				payload jsonb NOT NULL,
+				console_url text generated always as (payload ->> 'consoleURL') stored,
+				deleted_at timestamp without time zone
+			);
+			CREATE TABLE IF NOT EXISTS spec.leaf_hub_agent_configs (
+				leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+				payload jsonb NOT NULL,
+				source text NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS status.leaf_hub_capabilities (
+				leaf_hub_name character varying(63) NOT NULL,
+				schema_version integer NOT NULL,
+				payload jsonb NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Insert the heartbeats, the capabilities and the agent config of the leaf hubs")
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name)
+			VALUES ('hub1'), ('hub2')`)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.leaf_hubs (leaf_hub_name, payload)
+			VALUES ('hub1', '{"consoleURL": "https://console.hub1.example.com"}')`)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.leaf_hub_capabilities (leaf_hub_name,
+			schema_version, payload) VALUES ('hub1', 1, '{"schemaVersion": 1, "minSchemaVersion": 1}')`)
+		Expect(err).ToNot(HaveOccurred())
+		enableLocalPolicies := true
+		Expect(postgresSQL.UpsertLeafHubAgentConfig(ctx, &spec.AgentConfigSpecBundle{
+			LeafHubName: "hub1",
+			Source:      "edge",
+			AgentConfig: &globalhubv1alpha3.AgentConfigSpec{
+				AggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+				EnableLocalPolicies: &enableLocalPolicies,
+			},
+		})).To(Succeed())
+
+		By("Check the leaf hubs can be listed")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/hubs", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		leafHubList := &hubs.LeafHubList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), leafHubList)).To(Succeed())
+		Expect(leafHubList.Items).To(HaveLen(2))
+		Expect(leafHubList.Items[0].Name).To(Equal("hub1"))
+		Expect(leafHubList.Items[0].ConsoleURL).To(Equal("https://console.hub1.example.com"))
+		Expect(leafHubList.Items[0].AgentConfig).NotTo(BeNil())
+		Expect(leafHubList.Items[0].AgentConfig.Source).To(Equal("edge"))
+		Expect(leafHubList.Items[0].AgentConfig.Spec.AggregationLevel).To(Equal(
+			globalhubv1alpha3.MinimalAggregation))
+		Expect(leafHubList.Items[0].Capabilities).NotTo(BeNil())
+		Expect(leafHubList.Items[0].Capabilities.SchemaVersion).To(Equal(1))
+		Expect(leafHubList.Items[1].Name).To(Equal("hub2"))
+		Expect(leafHubList.Items[1].AgentConfig).To(BeNil())
+		Expect(leafHubList.Items[1].Capabilities).To(BeNil())
+	})
+
+	It("Should be able to list the argocd applications and applicationsets", func() {
+		By("Create argocd tables in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE TABLE IF NOT EXISTS status.argocd_applications (
+				id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63) NOT NULL,
+				project character varying(253),
+				repo_url text,
+				target_revision character varying(253),
+				revision character varying(253),
+				destination_server text,
+				destination_name character varying(253),
+				destination_namespace character varying(63),
+				sync_status character varying(63),
+				health_status character varying(63),
+				health_message text,
+				operation_phase character varying(63),
+				reconciled_at character varying(63),
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS status.argocd_applicationsets (
+				id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63) NOT NULL,
+				applications jsonb NOT NULL,
+				resources_up_to_date boolean DEFAULT false NOT NULL,
+				error_message text,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Insert the applications and applicationsets of the leaf hubs")
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.argocd_applications (id, leaf_hub_name, name,
+			namespace, project, sync_status, health_status) VALUES
+			($1, 'hub1', 'cluster1-guestbook', 'openshift-gitops', 'default', 'Synced', 'Healthy'),
+			($2, 'hub2', 'cluster2-guestbook', 'openshift-gitops', 'default', 'OutOfSync', 'Degraded'),
+			($3, 'hub2', 'bgd', 'openshift-gitops', 'demo', 'Synced', 'Healthy')`,
+			uuid.New().String(), uuid.New().String(), uuid.New().String())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.argocd_applicationsets (id, leaf_hub_name, name,
+			namespace, applications, resources_up_to_date) VALUES
+			($1, 'hub1', 'guestbook', 'openshift-gitops', '["cluster1-guestbook"]', true),
+			($2, 'hub2', 'guestbook', 'openshift-gitops', '["cluster2-guestbook"]', true)`,
+			uuid.New().String(), uuid.New().String())
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the applications can be listed")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/argocdapplications", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		applicationList := &argocd.ApplicationList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), applicationList)).To(Succeed())
+		Expect(applicationList.Items).To(HaveLen(3))
+		Expect(applicationList.Summary.Applications).To(Equal(3))
+		Expect(applicationList.Summary.Health).To(Equal(map[string]int{"Healthy": 2, "Degraded": 1}))
+		Expect(applicationList.Items[0].Name).To(Equal("cluster1-guestbook"))
+		Expect(applicationList.Items[0].ApplicationSet).To(Equal("guestbook"))
+
+		By("Check the applications can be filtered by applicationset and health status")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET",
+			"/global-hub-api/v1/argocdapplications?applicationSet=guestbook&healthStatus=Degraded", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		applicationList = &argocd.ApplicationList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), applicationList)).To(Succeed())
+		Expect(applicationList.Items).To(HaveLen(1))
+		Expect(applicationList.Items[0].LeafHubName).To(Equal("hub2"))
+		Expect(applicationList.Items[0].SyncStatus).To(Equal("OutOfSync"))
+
+		By("Check the applicationsets are aggregated across the leaf hubs")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/argocdapplicationsets", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		applicationSetList := &argocd.ApplicationSetList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), applicationSetList)).To(Succeed())
+		Expect(applicationSetList.Items).To(HaveLen(1))
+		Expect(applicationSetList.Items[0].Name).To(Equal("guestbook"))
+		Expect(applicationSetList.Items[0].LeafHubs).To(HaveLen(2))
+		Expect(applicationSetList.Items[0].Summary.Applications).To(Equal(2))
+		Expect(applicationSetList.Items[0].Summary.Sync).To(Equal(map[string]int{"Synced": 1, "OutOfSync": 1}))
+	})
+
+	It("Should be able to list the policy violations of the acm policies and native policy engines", func() {
+		By("Create the policy violations tables and view in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS local_spec;
+			CREATE SCHEMA IF NOT EXISTS local_status;
+			DO $$ BEGIN
+				CREATE TYPE local_status.compliance_type AS ENUM (
+					'compliant',
+					'non_compliant',
+					'unknown'
+				);
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			CREATE TABLE IF NOT EXISTS local_spec.policies (
+				leaf_hub_name character varying(63) NOT NULL,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted_at timestamp without time zone,
+				policy_id uuid PRIMARY KEY generated always as (uuid(payload->'metadata'->>'uid')) stored,
+				policy_name character varying(255) generated always as (payload -> 'metadata' ->> 'name') stored,
+				policy_category character varying(255) generated always as (payload -> 'metadata' -> 'annotations'
+					->> 'policy.open-cluster-management.io/categories') stored
+			);
+			CREATE TABLE IF NOT EXISTS local_status.compliance (
+				policy_id uuid NOT NULL,
+				cluster_name character varying(63) NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				error status.error_type NOT NULL,
+				compliance local_status.compliance_type NOT NULL,
+				cluster_id uuid
+			);
+			CREATE TABLE IF NOT EXISTS status.policy_violations (
+				report_id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				engine character varying(63) NOT NULL,
+				cluster_name character varying(63),
+				policy_name character varying(253) NOT NULL,
+				rule_name character varying(253),
+				severity character varying(63),
+				category character varying(253),
+				result character varying(15) NOT NULL,
+				message text,
+				resource_kind character varying(253),
+				resource_name character varying(253),
+				resource_namespace character varying(63),
+				created_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE OR REPLACE VIEW status.unified_policy_violations AS
+				SELECT 'acm' AS engine, c.leaf_hub_name, c.cluster_name,
+					p.payload -> 'metadata' ->> 'name' AS policy_name, NULL AS rule_name, NULL AS severity,
+					p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/categories'
+					AS category, 'fail' AS result, NULL AS message, 'ManagedCluster' AS resource_kind,
+					c.cluster_name AS resource_name, NULL AS resource_namespace
+				FROM status.compliance c
+				INNER JOIN spec.policies p ON c.policy_id = p.id
+				WHERE c.compliance = 'non_compliant' AND p.deleted = false
+				UNION ALL
+				SELECT 'acm' AS engine, lc.leaf_hub_name, lc.cluster_name, lp.policy_name, NULL AS rule_name,
+					NULL AS severity, lp.policy_category AS category, 'fail' AS result, NULL AS message,
+					'ManagedCluster' AS resource_kind, lc.cluster_name AS resource_name, NULL AS resource_namespace
+				FROM local_status.compliance lc
+				INNER JOIN local_spec.policies lp ON lc.policy_id = lp.policy_id
+				WHERE lc.compliance = 'non_compliant' AND lp.deleted_at IS NULL
+				UNION ALL
+				SELECT v.engine, v.leaf_hub_name, v.cluster_name, v.policy_name, v.rule_name, v.severity,
+					v.category, v.result, v.message, v.resource_kind, v.resource_name, v.resource_namespace
+				FROM status.policy_violations v;
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Insert the acm policies and the native policy engine violations of the leaf hub")
+		policyID := uuid.New().String()
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO spec.policies (id, payload) VALUES ($1, $2)`,
+			policyID, `{"metadata": {"name": "policy-etcd-encryption", "namespace": "default"}}`)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.compliance (policy_id, cluster_name,
+			leaf_hub_name, error, compliance) VALUES ($1, 'mc3', 'hub3', 'none', 'non_compliant'),
+			($1, 'mc4', 'hub3', 'none', 'compliant')`, policyID)
+		Expect(err).ToNot(HaveOccurred())
+
+		localPolicyID := uuid.New().String()
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO local_spec.policies (leaf_hub_name, payload)
+			VALUES ('hub3', $1)`, fmt.Sprintf(`{"metadata": {"name": "policy-namespace", "uid": "%s"}}`,
+			localPolicyID))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO local_status.compliance (policy_id, cluster_name,
+			leaf_hub_name, error, compliance) VALUES ($1, 'mc4', 'hub3', 'none', 'non_compliant')`, localPolicyID)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = postgresSQL.GetConn().Exec(ctx, `INSERT INTO status.policy_violations (report_id, leaf_hub_name,
+			engine, cluster_name, policy_name, rule_name, severity, result, message, resource_kind, resource_name,
+			resource_namespace) VALUES
+			($1, 'hub3', 'gatekeeper', NULL, 'ns-must-have-owner', 'K8sRequiredLabels', NULL, 'fail',
+				'you must provide owner', 'Namespace', 'dev', NULL),
+			($2, 'hub3', 'kyverno', 'mc3', 'require-labels', 'check-team', 'high', 'fail',
+				'label team is required', 'Pod', 'nginx', 'default')`,
+			uuid.New().String(), uuid.New().String())
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the violations of all the engines can be listed")
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/policyviolations?leafHubName=hub3", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		violationList := &policyviolations.PolicyViolationList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), violationList)).To(Succeed())
+		Expect(violationList.Items).To(HaveLen(4))
+		Expect(violationList.Summary.Violations).To(Equal(4))
+		Expect(violationList.Summary.Engines).To(Equal(map[string]int{"acm": 2, "gatekeeper": 1, "kyverno": 1}))
+		Expect(violationList.Items[0].PolicyName).To(Equal("policy-etcd-encryption"))
+		Expect(violationList.Items[0].ClusterName).To(Equal("mc3"))
+
+		By("Check the violations can be filtered by engine and severity")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/policyviolations?engine=kyverno&severity=high", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(200))
+
+		violationList = &policyviolations.PolicyViolationList{}
+		Expect(json.Unmarshal(w.Body.Bytes(), violationList)).To(Succeed())
+		Expect(violationList.Items).To(HaveLen(1))
+		Expect(violationList.Items[0].ResourceName).To(Equal("nginx"))
+
+		By("Check the unknown engine is rejected")
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/global-hub-api/v1/policyviolations?engine=opa", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(400))
+	})
+
 	AfterAll(func() {
 		postgresSQL.Stop()
 	})
@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policyviolations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+
+	// policyEngineACM is the engine of the acm policies, the non compliant clusters are the violations of the policy
+	policyEngineACM = "acm"
+
+	violationsQuery = `SELECT engine, leaf_hub_name, cluster_name, policy_name, rule_name, severity, category, result,
+		message, resource_kind, resource_name, resource_namespace FROM status.unified_policy_violations`
+	violationsOrder = " ORDER BY engine, leaf_hub_name, policy_name, cluster_name"
+)
+
+// PolicyViolation is a violation of an acm policy or a native policy engine on a leaf hub.
+type PolicyViolation struct {
+	Engine            string `json:"engine"`
+	LeafHubName       string `json:"leafHubName"`
+	ClusterName       string `json:"clusterName,omitempty"`
+	PolicyName        string `json:"policyName"`
+	RuleName          string `json:"ruleName,omitempty"`
+	Severity          string `json:"severity,omitempty"`
+	Category          string `json:"category,omitempty"`
+	Result            string `json:"result"`
+	Message           string `json:"message,omitempty"`
+	ResourceKind      string `json:"resourceKind,omitempty"`
+	ResourceName      string `json:"resourceName,omitempty"`
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+}
+
+// PolicyViolationSummary counts the violations by their engine and result.
+type PolicyViolationSummary struct {
+	Violations int            `json:"violations"`
+	Engines    map[string]int `json:"engines"`
+	Results    map[string]int `json:"results"`
+}
+
+// PolicyViolationList is the policy violations of all the leaf hubs.
+type PolicyViolationList struct {
+	Summary PolicyViolationSummary `json:"summary"`
+	Items   []PolicyViolation      `json:"items"`
+}
+
+// violationFilter holds the filters of the violations query.
+type violationFilter struct {
+	engine      string
+	leafHubName string
+	clusterName string
+	policyName  string
+	severity    string
+}
+
+// ListPolicyViolations godoc
+// @summary list policy violations
+// @description list the violations of the acm policies, the gatekeeper constraints and the kyverno policy reports
+// @accept json
+// @produce json
+// @param        engine           query     string  false  "policy engine: acm, gatekeeper or kyverno"
+// @param        leafHubName      query     string  false  "list violations of the leaf hub"
+// @param        clusterName      query     string  false  "list violations of the managed cluster"
+// @param        policy           query     string  false  "list violations of the policy"
+// @param        severity         query     string  false  "list violations of the severity"
+// @success      200  {object}    PolicyViolationList
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /policyviolations [get]
+func ListPolicyViolations(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		filter := &violationFilter{
+			engine:      ginCtx.Query("engine"),
+			leafHubName: ginCtx.Query("leafHubName"),
+			clusterName: ginCtx.Query("clusterName"),
+			policyName:  ginCtx.Query("policy"),
+			severity:    ginCtx.Query("severity"),
+		}
+		if filter.engine != "" && filter.engine != policyEngineACM &&
+			filter.engine != status.PolicyEngineGatekeeper && filter.engine != status.PolicyEngineKyverno {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid engine %q, the engine is one of %s, %s or %s",
+				filter.engine, policyEngineACM, status.PolicyEngineGatekeeper, status.PolicyEngineKyverno))
+			return
+		}
+
+		violations, err := queryViolations(ginCtx.Request.Context(), dbConnectionPool, filter)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying policy violations: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		violationList := &PolicyViolationList{
+			Summary: PolicyViolationSummary{Engines: map[string]int{}, Results: map[string]int{}},
+			Items:   violations,
+		}
+		for _, violation := range violations {
+			violationList.Summary.Violations++
+			violationList.Summary.Engines[violation.Engine]++
+			violationList.Summary.Results[violation.Result]++
+		}
+
+		ginCtx.JSON(http.StatusOK, violationList)
+	}
+}
+
+func queryViolations(ctx context.Context, dbConnectionPool *pgxpool.Pool, filter *violationFilter,
+) ([]PolicyViolation, error) {
+	query, args := buildViolationsQuery(filter)
+	fmt.Fprintf(gin.DefaultWriter, "policy violations query: %s\n", query)
+
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query policy violations: %w", err)
+	}
+	defer rows.Close()
+
+	violations := []PolicyViolation{}
+	for rows.Next() {
+		var violation PolicyViolation
+		var clusterName, ruleName, severity, category, message, resourceKind, resourceName,
+			resourceNamespace *string
+		if err := rows.Scan(&violation.Engine, &violation.LeafHubName, &clusterName, &violation.PolicyName,
+			&ruleName, &severity, &category, &violation.Result, &message, &resourceKind, &resourceName,
+			&resourceNamespace); err != nil {
+			return nil, fmt.Errorf("failed to scan policy violation: %w", err)
+		}
+
+		violation.ClusterName = stringValue(clusterName)
+		violation.RuleName = stringValue(ruleName)
+		violation.Severity = stringValue(severity)
+		violation.Category = stringValue(category)
+		violation.Message = stringValue(message)
+		violation.ResourceKind = stringValue(resourceKind)
+		violation.ResourceName = stringValue(resourceName)
+		violation.ResourceNamespace = stringValue(resourceNamespace)
+		violations = append(violations, violation)
+	}
+
+	return violations, rows.Err()
+}
+
+// buildViolationsQuery returns the violations query with the positional arguments of the filter.
+func buildViolationsQuery(filter *violationFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.engine != "" {
+		addCondition("engine = $%d", filter.engine)
+	}
+	if filter.leafHubName != "" {
+		addCondition("leaf_hub_name = $%d", filter.leafHubName)
+	}
+	if filter.clusterName != "" {
+		addCondition("cluster_name = $%d", filter.clusterName)
+	}
+	if filter.policyName != "" {
+		addCondition("policy_name = $%d", filter.policyName)
+	}
+	if filter.severity != "" {
+		addCondition("severity = $%d", filter.severity)
+	}
+
+	query := violationsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query + violationsOrder, args
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
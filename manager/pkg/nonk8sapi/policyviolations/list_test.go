@@ -0,0 +1,25 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policyviolations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildViolationsQuery(t *testing.T) {
+	query, args := buildViolationsQuery(&violationFilter{})
+	assert.Equal(t, violationsQuery+violationsOrder, query)
+	assert.Empty(t, args)
+
+	query, args = buildViolationsQuery(&violationFilter{
+		engine:      "kyverno",
+		clusterName: "cluster1",
+		severity:    "high",
+	})
+	assert.Equal(t, violationsQuery+
+		" WHERE engine = $1 AND cluster_name = $2 AND severity = $3"+violationsOrder, query)
+	assert.Equal(t, []interface{}{"kyverno", "cluster1", "high"}, args)
+}
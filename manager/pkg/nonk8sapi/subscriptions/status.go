@@ -0,0 +1,154 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// clusterLabel is set on the subscription status by the subscription controller, it is the deploying cluster
+	clusterLabel = "apps.open-cluster-management.io/cluster"
+	// the subscription statuses are stored with the id of their global subscription
+	subscriptionStatusQuery = `SELECT leaf_hub_name, payload FROM status.subscription_statuses
+		WHERE id=$1 ORDER BY leaf_hub_name, payload->'metadata'->'labels'->>'apps.open-cluster-management.io/cluster'`
+)
+
+// ClusterSubscriptionStatus is the deployment status of a subscription on a single cluster.
+type ClusterSubscriptionStatus struct {
+	LeafHubName string                                `json:"leafHubName"`
+	ClusterName string                                `json:"clusterName,omitempty"`
+	Packages    []appsv1alpha1.SubscriptionUnitStatus `json:"packages"`
+}
+
+// SubscriptionStatusSummary counts the clusters by the deployment phase of the subscription packages.
+type SubscriptionStatusSummary struct {
+	Deployed          int `json:"deployed"`
+	InProgress        int `json:"inProgress"`
+	Failed            int `json:"failed"`
+	PropagationFailed int `json:"propagationFailed"`
+	Clusters          int `json:"clusters"`
+}
+
+// SubscriptionStatus is the per-cluster deployment status of a global subscription across all the leaf hubs.
+type SubscriptionStatus struct {
+	SubscriptionID string                      `json:"subscriptionId"`
+	Name           string                      `json:"name"`
+	Namespace      string                      `json:"namespace"`
+	Summary        SubscriptionStatusSummary   `json:"summary"`
+	Clusters       []ClusterSubscriptionStatus `json:"clusters"`
+}
+
+// GetSubscriptionStatus godoc
+// @summary get application subscription status
+// @description get the per-cluster deployment status of a given global application subscription
+// @accept json
+// @produce json
+// @param        subscriptionID    path    string    true    "Subscription ID"
+// @success      200  {object}     SubscriptionStatus
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /subscription/{subscriptionID}/status [get]
+func GetSubscriptionStatus(dbConnectionPool *pgxpool.Pool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		subscriptionID := ginCtx.Param("subscriptionID")
+		fmt.Fprintf(gin.DefaultWriter, "getting subscription status for subscription: %s\n", subscriptionID)
+		fmt.Fprintf(gin.DefaultWriter, "subscription status query: %s\n", subscriptionStatusQuery)
+
+		subscriptionStatus, err := getSubscriptionStatus(dbConnectionPool, subscriptionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			ginCtx.String(http.StatusNotFound, "subscription not found")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "error in querying subscription status: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, subscriptionStatus)
+	}
+}
+
+func getSubscriptionStatus(dbConnectionPool *pgxpool.Pool, subscriptionID string) (*SubscriptionStatus, error) {
+	subscriptionStatus := &SubscriptionStatus{
+		SubscriptionID: subscriptionID,
+		Clusters:       []ClusterSubscriptionStatus{},
+	}
+	if err := dbConnectionPool.QueryRow(context.TODO(), subscriptionQuery, subscriptionID).Scan(
+		&subscriptionStatus.Name, &subscriptionStatus.Namespace); err != nil {
+		return nil, fmt.Errorf("error in querying subscription with subscription ID(%s): %w", subscriptionID, err)
+	}
+
+	rows, err := dbConnectionPool.Query(context.TODO(), subscriptionStatusQuery, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error in querying subscription-status for subscription(%s): %w", subscriptionID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var leafHubName string
+		var appsubStatus appsv1alpha1.SubscriptionStatus
+		if err := rows.Scan(&leafHubName, &appsubStatus); err != nil {
+			return nil, fmt.Errorf("error getting subscription status for leaf hub: %w", err)
+		}
+
+		clusterStatus := ClusterSubscriptionStatus{
+			LeafHubName: leafHubName,
+			ClusterName: appsubStatus.GetLabels()[clusterLabel],
+			Packages:    appsubStatus.Statuses.SubscriptionStatus,
+		}
+		if clusterStatus.Packages == nil {
+			clusterStatus.Packages = []appsv1alpha1.SubscriptionUnitStatus{}
+		}
+		subscriptionStatus.Clusters = append(subscriptionStatus.Clusters, clusterStatus)
+		updateSubscriptionStatusSummary(&subscriptionStatus.Summary, clusterStatus.Packages)
+	}
+
+	return subscriptionStatus, nil
+}
+
+// updateSubscriptionStatusSummary counts the cluster by the worst phase of its packages, a cluster without packages
+// or with packages of unknown phase is still in progress.
+func updateSubscriptionStatusSummary(summary *SubscriptionStatusSummary,
+	packages []appsv1alpha1.SubscriptionUnitStatus,
+) {
+	summary.Clusters++
+
+	deployed, failed, propagationFailed := 0, 0, 0
+	for _, unitStatus := range packages {
+		switch unitStatus.Phase {
+		case appsv1alpha1.PackageDeployed:
+			deployed++
+		case appsv1alpha1.PackageDeployFailed:
+			failed++
+		case appsv1alpha1.PackagePropagationFailed:
+			propagationFailed++
+		}
+	}
+
+	switch {
+	case propagationFailed > 0:
+		summary.PropagationFailed++
+	case failed > 0:
+		summary.Failed++
+	case deployed > 0 && deployed == len(packages):
+		summary.Deployed++
+	default:
+		summary.InProgress++
+	}
+}
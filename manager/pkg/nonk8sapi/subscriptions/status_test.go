@@ -0,0 +1,41 @@
+// Copyright (c) 2022 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestUpdateSubscriptionStatusSummary(t *testing.T) {
+	summary := &SubscriptionStatusSummary{}
+
+	updateSubscriptionStatusSummary(summary, []appsv1alpha1.SubscriptionUnitStatus{
+		{Name: "nginx", Phase: appsv1alpha1.PackageDeployed},
+		{Name: "nginx-svc", Phase: appsv1alpha1.PackageDeployed},
+	})
+	updateSubscriptionStatusSummary(summary, []appsv1alpha1.SubscriptionUnitStatus{
+		{Name: "nginx", Phase: appsv1alpha1.PackageDeployed},
+		{Name: "nginx-svc", Phase: appsv1alpha1.PackageDeployFailed},
+	})
+	updateSubscriptionStatusSummary(summary, []appsv1alpha1.SubscriptionUnitStatus{
+		{Name: "nginx", Phase: appsv1alpha1.PackageDeployFailed},
+		{Name: "nginx-svc", Phase: appsv1alpha1.PackagePropagationFailed},
+	})
+	updateSubscriptionStatusSummary(summary, []appsv1alpha1.SubscriptionUnitStatus{
+		{Name: "nginx", Phase: appsv1alpha1.PackageDeployed},
+		{Name: "nginx-svc", Phase: appsv1alpha1.PackageUnknown},
+	})
+	updateSubscriptionStatusSummary(summary, []appsv1alpha1.SubscriptionUnitStatus{})
+
+	assert.Equal(t, SubscriptionStatusSummary{
+		Deployed:          1,
+		InProgress:        2,
+		Failed:            1,
+		PropagationFailed: 1,
+		Clusters:          5,
+	}, *summary)
+}
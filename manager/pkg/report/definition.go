@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// FormatCSV exports the report as csv with a header line.
+	FormatCSV = "csv"
+	// FormatJSONLines exports the report as json lines, one json object per line.
+	FormatJSONLines = "jsonl"
+
+	// ViewDetail exports the compliance of each policy on each cluster per day.
+	ViewDetail = "detail"
+	// ViewSummary exports the number of the compliant, non compliant and unknown policy-cluster pairs per day.
+	ViewSummary = "summary"
+
+	// ScheduleDaily exports the report every day.
+	ScheduleDaily = "daily"
+	// ScheduleWeekly exports the report every Monday.
+	ScheduleWeekly = "weekly"
+	// ScheduleMonthly exports the report on the first day of every month.
+	ScheduleMonthly = "monthly"
+
+	// DefaultDays is the number of the days covered by the reports by default
+	DefaultDays = 30
+	// MaxDays is the maximum number of the days covered by a report
+	MaxDays = 366
+
+	dateFormat = "2006-01-02"
+)
+
+// Definition is a parameterized compliance report, the policies are selected by their standards, categories and
+// controls, and the clusters are selected by their leaf hubs and labels.
+type Definition struct {
+	Name         string   `json:"name"`
+	Standards    []string `json:"standards,omitempty"`
+	Categories   []string `json:"categories,omitempty"`
+	Controls     []string `json:"controls,omitempty"`
+	LeafHubNames []string `json:"leafHubNames,omitempty"`
+	// LabelSelector selects the clusters by their current labels in all the days of the report
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Days is the number of the days up to the day of the export covered by the report, 30 by default
+	Days int `json:"days,omitempty"`
+	// Format is csv or jsonl, csv by default
+	Format string `json:"format,omitempty"`
+	// View is detail or summary, detail by default
+	View string `json:"view,omitempty"`
+	// Schedule is daily, weekly or monthly, the report is written to the object storage by the schedule, it isn't
+	// scheduled if it's empty
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// Default sets the defaults of the unset fields
+func (d *Definition) Default() {
+	if d.Days == 0 {
+		d.Days = DefaultDays
+	}
+	if d.Format == "" {
+		d.Format = FormatCSV
+	}
+	if d.View == "" {
+		d.View = ViewDetail
+	}
+}
+
+// Validate returns an error if the definition is invalid, the name is only required for the stored definitions.
+func (d *Definition) Validate(requireName bool) error {
+	if requireName || d.Name != "" {
+		if errs := validation.IsDNS1123Label(d.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid name %s: %s", d.Name, strings.Join(errs, ", "))
+		}
+	}
+	if d.Days < 1 || d.Days > MaxDays {
+		return fmt.Errorf("days should be in the scope [1, %d]", MaxDays)
+	}
+	if d.Format != FormatCSV && d.Format != FormatJSONLines {
+		return fmt.Errorf("invalid format %s, should be %s or %s", d.Format, FormatCSV, FormatJSONLines)
+	}
+	if d.View != ViewDetail && d.View != ViewSummary {
+		return fmt.Errorf("invalid view %s, should be %s or %s", d.View, ViewDetail, ViewSummary)
+	}
+	switch d.Schedule {
+	case "", ScheduleDaily, ScheduleWeekly, ScheduleMonthly:
+	default:
+		return fmt.Errorf("invalid schedule %s, should be %s, %s or %s", d.Schedule, ScheduleDaily, ScheduleWeekly,
+			ScheduleMonthly)
+	}
+	if _, _, err := labelSelectorConditions(d.LabelSelector, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsDue returns whether the scheduled report should be exported on the day.
+func (d *Definition) IsDue(day time.Time) bool {
+	switch d.Schedule {
+	case ScheduleDaily:
+		return true
+	case ScheduleWeekly:
+		return day.Weekday() == time.Monday
+	case ScheduleMonthly:
+		return day.Day() == 1
+	default:
+		return false
+	}
+}
+
+// DateRange returns the first and the last days covered by the report exported on the day
+func (d *Definition) DateRange(day time.Time) (time.Time, time.Time) {
+	until := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return until.AddDate(0, 0, 1-d.Days), until
+}
+
+// FileName returns the name of the report file covering the days
+func (d *Definition) FileName(since, until time.Time) string {
+	name := d.Name
+	if name == "" {
+		name = "compliance-report"
+	}
+	return fmt.Sprintf("%s-%s-%s.%s", name, since.Format(dateFormat), until.Format(dateFormat), d.Format)
+}
+
+// ParseDate parses the date in the "2006-01-02" format
+func ParseDate(date string) (time.Time, error) {
+	day, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s, should be in the %s format", date, dateFormat)
+	}
+	return day, nil
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// the daily compliance of the local policies and the global policies from the history, and the current
+	// compliance of the global policies on today, the clusters are selected by their current labels rather than the
+	// labels in the days of the report, the $1 and $2 are the first and the last days of the report
+	reportQuery = `WITH report AS (
+		SELECT h.compliance_date, 'local' AS policy_type, h.leaf_hub_name, mc.cluster_name, h.cluster_id,
+			h.policy_id, p.policy_name, p.policy_standard, p.policy_category, p.policy_control,
+			h.compliance::text AS compliance, mc.payload -> 'metadata' -> 'labels' AS cluster_labels
+		FROM history.local_compliance h
+		JOIN local_spec.policies p ON p.policy_id = h.policy_id
+		LEFT JOIN status.managed_clusters mc ON mc.leaf_hub_name = h.leaf_hub_name AND mc.cluster_id = h.cluster_id
+		WHERE h.compliance_date BETWEEN $1 AND $2
+		UNION ALL
+		SELECT h.compliance_date, 'global', h.leaf_hub_name, h.cluster_name, h.cluster_id, h.policy_id,
+			p.payload -> 'metadata' ->> 'name',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/standards',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/categories',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/controls',
+			h.compliance::text, mc.payload -> 'metadata' -> 'labels'
+		FROM history.compliance h
+		JOIN spec.policies p ON p.id = h.policy_id
+		LEFT JOIN status.managed_clusters mc ON mc.leaf_hub_name = h.leaf_hub_name AND mc.cluster_name = h.cluster_name
+		WHERE h.compliance_date BETWEEN $1 AND $2 AND h.compliance_date < CURRENT_DATE
+		UNION ALL
+		SELECT CURRENT_DATE, 'global', c.leaf_hub_name, c.cluster_name, c.cluster_id, c.policy_id,
+			p.payload -> 'metadata' ->> 'name',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/standards',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/categories',
+			p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/controls',
+			c.compliance::text, mc.payload -> 'metadata' -> 'labels'
+		FROM status.compliance c
+		JOIN spec.policies p ON p.id = c.policy_id AND NOT p.deleted
+		LEFT JOIN status.managed_clusters mc ON mc.leaf_hub_name = c.leaf_hub_name AND mc.cluster_id = c.cluster_id
+		WHERE CURRENT_DATE BETWEEN $1 AND $2
+	)`
+	detailQuery = ` SELECT compliance_date, policy_type, leaf_hub_name, cluster_name, cluster_id::text,
+		policy_id::text, policy_name, policy_standard, policy_category, policy_control, compliance FROM report`
+	detailOrder  = " ORDER BY compliance_date, policy_name, policy_id, leaf_hub_name, cluster_name"
+	summaryQuery = ` SELECT compliance_date,
+		count(*) FILTER (WHERE compliance = 'compliant'),
+		count(*) FILTER (WHERE compliance = 'non_compliant'),
+		count(*) FILTER (WHERE compliance = 'unknown'),
+		count(*) FROM report`
+	summaryOrder = " GROUP BY compliance_date ORDER BY compliance_date"
+
+	// the standards, categories and controls annotations of the policies are comma separated lists
+	listContainsCondition = `regexp_split_to_array(%s, '\s*,\s*') && $%d::text[]`
+)
+
+// ComplianceRecord is the compliance of a policy on a cluster in a day.
+type ComplianceRecord struct {
+	Date string `json:"date"`
+	// PolicyType is local for the policies created on the leaf hubs, or global for the policies created on the
+	// global hub, the compliance of the global policies on today is the current compliance
+	PolicyType  string `json:"policyType"`
+	LeafHubName string `json:"leafHubName"`
+	ClusterName string `json:"clusterName,omitempty"`
+	ClusterID   string `json:"clusterId,omitempty"`
+	PolicyID    string `json:"policyId"`
+	PolicyName  string `json:"policyName,omitempty"`
+	Standards   string `json:"standards,omitempty"`
+	Categories  string `json:"categories,omitempty"`
+	Controls    string `json:"controls,omitempty"`
+	Compliance  string `json:"compliance"`
+}
+
+// ComplianceSummary is the number of the policy-cluster pairs in each compliance state in a day.
+type ComplianceSummary struct {
+	Date         string `json:"date"`
+	Compliant    int64  `json:"compliant"`
+	NonCompliant int64  `json:"nonCompliant"`
+	Unknown      int64  `json:"unknown"`
+	Total        int64  `json:"total"`
+}
+
+var (
+	detailHeader = []string{
+		"date", "policyType", "leafHubName", "clusterName", "clusterId", "policyId", "policyName", "standards",
+		"categories", "controls", "compliance",
+	}
+	summaryHeader = []string{"date", "compliant", "nonCompliant", "unknown", "total"}
+)
+
+func (r *ComplianceRecord) csvRecord() []string {
+	return []string{
+		r.Date, r.PolicyType, r.LeafHubName, r.ClusterName, r.ClusterID, r.PolicyID, r.PolicyName, r.Standards,
+		r.Categories, r.Controls, r.Compliance,
+	}
+}
+
+func (s *ComplianceSummary) csvRecord() []string {
+	return []string{
+		s.Date, fmt.Sprint(s.Compliant), fmt.Sprint(s.NonCompliant), fmt.Sprint(s.Unknown), fmt.Sprint(s.Total),
+	}
+}
+
+// Export writes the report of the days from since to until to the writer, the rows are streamed from the database
+// into the writer, so that the report isn't loaded into the memory at once.
+func Export(ctx context.Context, dbConnectionPool *pgxpool.Pool, definition *Definition, since, until time.Time,
+	writer io.Writer,
+) error {
+	if until.Before(since) {
+		return fmt.Errorf("the first day %s is after the last day %s", since.Format(dateFormat),
+			until.Format(dateFormat))
+	}
+	query, args, err := buildQuery(definition, since, until)
+	if err != nil {
+		return err
+	}
+
+	header := detailHeader
+	if definition.View == ViewSummary {
+		header = summaryHeader
+	}
+	recordWriter, err := newRecordWriter(definition.Format, writer, header)
+	if err != nil {
+		return err
+	}
+
+	rows, err := dbConnectionPool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query the compliance report: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec record
+		if definition.View == ViewSummary {
+			rec, err = scanSummary(rows)
+		} else {
+			rec, err = scanRecord(rows)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to scan the compliance report: %w", err)
+		}
+		if err := recordWriter.write(rec); err != nil {
+			return fmt.Errorf("failed to write the compliance report: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query the compliance report: %w", err)
+	}
+
+	return recordWriter.flush()
+}
+
+func scanRecord(rows pgx.Rows) (*ComplianceRecord, error) {
+	var date time.Time
+	var clusterName, clusterID, policyName, standards, categories, controls *string
+	rec := &ComplianceRecord{}
+	if err := rows.Scan(&date, &rec.PolicyType, &rec.LeafHubName, &clusterName, &clusterID, &rec.PolicyID,
+		&policyName, &standards, &categories, &controls, &rec.Compliance); err != nil {
+		return nil, err
+	}
+	rec.Date = date.Format(dateFormat)
+	rec.ClusterName, rec.ClusterID, rec.PolicyName = deref(clusterName), deref(clusterID), deref(policyName)
+	rec.Standards, rec.Categories, rec.Controls = deref(standards), deref(categories), deref(controls)
+	return rec, nil
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func scanSummary(rows pgx.Rows) (*ComplianceSummary, error) {
+	var date time.Time
+	summary := &ComplianceSummary{}
+	if err := rows.Scan(&date, &summary.Compliant, &summary.NonCompliant, &summary.Unknown,
+		&summary.Total); err != nil {
+		return nil, err
+	}
+	summary.Date = date.Format(dateFormat)
+	return summary, nil
+}
+
+// buildQuery returns the report query of the definition with its positional arguments
+func buildQuery(definition *Definition, since, until time.Time) (string, []interface{}, error) {
+	conditions := []string{}
+	args := []interface{}{since, until}
+
+	for _, list := range []struct {
+		column string
+		values []string
+	}{
+		{column: "policy_standard", values: definition.Standards},
+		{column: "policy_category", values: definition.Categories},
+		{column: "policy_control", values: definition.Controls},
+	} {
+		if len(list.values) == 0 {
+			continue
+		}
+		args = append(args, list.values)
+		conditions = append(conditions, fmt.Sprintf(listContainsCondition, list.column, len(args)))
+	}
+
+	if len(definition.LeafHubNames) > 0 {
+		args = append(args, definition.LeafHubNames)
+		conditions = append(conditions, fmt.Sprintf("leaf_hub_name = ANY($%d::text[])", len(args)))
+	}
+
+	labelConditions, labelArgs, err := labelSelectorConditions(definition.LabelSelector, len(args))
+	if err != nil {
+		return "", nil, err
+	}
+	conditions = append(conditions, labelConditions...)
+	args = append(args, labelArgs...)
+
+	query, order := detailQuery, detailOrder
+	if definition.View == ViewSummary {
+		query, order = summaryQuery, summaryOrder
+	}
+
+	query = reportQuery + query
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + order, args, nil
+}
+
+// labelSelectorConditions returns the conditions of the label selector on the labels of the clusters, the positional
+// arguments of the conditions start after the given number of the arguments.
+func labelSelectorConditions(labelSelector string, argCount int) ([]string, []interface{}, error) {
+	if labelSelector == "" {
+		return nil, nil, nil
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid label selector %s: %w", labelSelector, err)
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	addArg := func(arg interface{}) int {
+		args = append(args, arg)
+		return argCount + len(args)
+	}
+
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		key, values := requirement.Key(), requirement.Values().List()
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.NotEquals:
+			label, err := json.Marshal(map[string]string{key: values[0]})
+			if err != nil {
+				return nil, nil, err
+			}
+			condition := fmt.Sprintf("cluster_labels @> $%d::jsonb", addArg(string(label)))
+			if requirement.Operator() == selection.NotEquals {
+				// the clusters without the label match the not equals requirement
+				condition = fmt.Sprintf("NOT COALESCE(%s, false)", condition)
+			}
+			conditions = append(conditions, condition)
+		case selection.In:
+			conditions = append(conditions, fmt.Sprintf("cluster_labels ->> $%d = ANY($%d::text[])", addArg(key),
+				addArg(values)))
+		case selection.NotIn:
+			conditions = append(conditions, fmt.Sprintf(
+				"NOT COALESCE(cluster_labels ->> $%d = ANY($%d::text[]), false)", addArg(key), addArg(values)))
+		case selection.Exists:
+			conditions = append(conditions, fmt.Sprintf("cluster_labels ? $%d", addArg(key)))
+		case selection.DoesNotExist:
+			conditions = append(conditions, fmt.Sprintf("NOT COALESCE(cluster_labels ? $%d, false)", addArg(key)))
+		default:
+			return nil, nil, fmt.Errorf("unsupported operator %s of the label selector %s", requirement.Operator(),
+				labelSelector)
+		}
+	}
+
+	return conditions, args, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionValidate(t *testing.T) {
+	definition := &Definition{Name: "production-ac-controls", Controls: []string{"AC-3"}, Schedule: ScheduleWeekly}
+	definition.Default()
+	assert.Equal(t, DefaultDays, definition.Days)
+	assert.Equal(t, FormatCSV, definition.Format)
+	assert.Equal(t, ViewDetail, definition.View)
+	assert.NoError(t, definition.Validate(true))
+
+	cases := []struct {
+		name   string
+		modify func(d *Definition)
+	}{
+		{name: "invalid name", modify: func(d *Definition) { d.Name = "Production_Report" }},
+		{name: "too many days", modify: func(d *Definition) { d.Days = MaxDays + 1 }},
+		{name: "invalid format", modify: func(d *Definition) { d.Format = "pdf" }},
+		{name: "invalid view", modify: func(d *Definition) { d.View = "chart" }},
+		{name: "invalid schedule", modify: func(d *Definition) { d.Schedule = "hourly" }},
+		{name: "invalid label selector", modify: func(d *Definition) { d.LabelSelector = "env in (prod" }},
+		{name: "unsupported label selector", modify: func(d *Definition) { d.LabelSelector = "version>1" }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			invalid := *definition
+			c.modify(&invalid)
+			assert.Error(t, invalid.Validate(true))
+		})
+	}
+
+	// the name is only required by the stored definitions
+	adhoc := &Definition{}
+	adhoc.Default()
+	assert.NoError(t, adhoc.Validate(false))
+	assert.Error(t, adhoc.Validate(true))
+}
+
+func TestDefinitionSchedule(t *testing.T) {
+	monday := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	assert.True(t, (&Definition{Schedule: ScheduleDaily}).IsDue(tuesday))
+	assert.True(t, (&Definition{Schedule: ScheduleWeekly}).IsDue(monday))
+	assert.False(t, (&Definition{Schedule: ScheduleWeekly}).IsDue(tuesday))
+	assert.True(t, (&Definition{Schedule: ScheduleMonthly}).IsDue(monday))
+	assert.False(t, (&Definition{Schedule: ScheduleMonthly}).IsDue(tuesday))
+	assert.False(t, (&Definition{}).IsDue(monday))
+
+	definition := &Definition{Name: "production", Days: 30, Format: FormatJSONLines}
+	since, until := definition.DateRange(monday)
+	assert.Equal(t, time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), until)
+	assert.Equal(t, "production-2023-04-02-2023-05-01.jsonl", definition.FileName(since, until))
+	assert.Equal(t, "reports/production/production-2023-04-02-2023-05-01.jsonl",
+		ObjectKey("reports", definition, definition.FileName(since, until)))
+}
+
+func TestBuildQuery(t *testing.T) {
+	since := time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	query, args, err := buildQuery(&Definition{View: ViewDetail}, since, until)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(query, "FROM report"+detailOrder))
+	assert.Equal(t, []interface{}{since, until}, args)
+
+	query, args, err = buildQuery(&Definition{
+		Standards:     []string{"NIST SP 800-53"},
+		Controls:      []string{"AC-3", "CM-2"},
+		LeafHubNames:  []string{"hub1"},
+		LabelSelector: "environment=production,tier!=db,region in (us-east,us-west),!deprecated",
+		View:          ViewSummary,
+	}, since, until)
+	require.NoError(t, err)
+	assert.Contains(t, query, `FROM report WHERE `+
+		`regexp_split_to_array(policy_standard, '\s*,\s*') && $3::text[] AND `+
+		`regexp_split_to_array(policy_control, '\s*,\s*') && $4::text[] AND `+
+		`leaf_hub_name = ANY($5::text[]) AND `+
+		`NOT COALESCE(cluster_labels ? $6, false) AND `+
+		`cluster_labels @> $7::jsonb AND `+
+		`cluster_labels ->> $8 = ANY($9::text[]) AND `+
+		`NOT COALESCE(cluster_labels @> $10::jsonb, false)`+summaryOrder)
+	assert.Equal(t, []interface{}{
+		since, until, []string{"NIST SP 800-53"}, []string{"AC-3", "CM-2"}, []string{"hub1"},
+		"deprecated", `{"environment":"production"}`, "region", []string{"us-east", "us-west"}, `{"tier":"db"}`,
+	}, args)
+}
+
+func TestRecordWriter(t *testing.T) {
+	records := []record{
+		&ComplianceRecord{
+			Date: "2023-04-30", PolicyType: "local", LeafHubName: "hub1", ClusterName: "cluster1",
+			ClusterID: "0a4b1c8e-7cb4-4cd2-8bd9-9b2e8d3a6a11", PolicyID: "8c5f3e2a-2f1d-4a4c-9d52-3c6f0f0b7e21",
+			PolicyName: "policy-config", Standards: "NIST SP 800-53", Categories: "CM Configuration Management",
+			Controls: "CM-2, AC-3", Compliance: "non_compliant",
+		},
+	}
+
+	var csvBuffer bytes.Buffer
+	writer, err := newRecordWriter(FormatCSV, &csvBuffer, detailHeader)
+	require.NoError(t, err)
+	for _, rec := range records {
+		require.NoError(t, writer.write(rec))
+	}
+	require.NoError(t, writer.flush())
+	assert.Equal(t, "date,policyType,leafHubName,clusterName,clusterId,policyId,policyName,standards,categories,"+
+		"controls,compliance\n"+
+		"2023-04-30,local,hub1,cluster1,0a4b1c8e-7cb4-4cd2-8bd9-9b2e8d3a6a11,8c5f3e2a-2f1d-4a4c-9d52-3c6f0f0b7e21,"+
+		"policy-config,NIST SP 800-53,CM Configuration Management,\"CM-2, AC-3\",non_compliant\n", csvBuffer.String())
+
+	var jsonLinesBuffer bytes.Buffer
+	writer, err = newRecordWriter(FormatJSONLines, &jsonLinesBuffer, summaryHeader)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&ComplianceSummary{Date: "2023-04-30", Compliant: 8, NonCompliant: 2, Total: 10}))
+	require.NoError(t, writer.write(&ComplianceSummary{Date: "2023-05-01", Compliant: 9, Unknown: 1, Total: 10}))
+	require.NoError(t, writer.flush())
+	assert.Equal(t,
+		`{"date":"2023-04-30","compliant":8,"nonCompliant":2,"unknown":0,"total":10}`+"\n"+
+			`{"date":"2023-05-01","compliant":9,"nonCompliant":0,"unknown":1,"total":10}`+"\n",
+		jsonLinesBuffer.String())
+
+	_, err = newRecordWriter("pdf", &jsonLinesBuffer, summaryHeader)
+	assert.Error(t, err)
+}
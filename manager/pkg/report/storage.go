@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// StorageConfig is the object storage the scheduled reports are written to, it's any storage compatible with the s3
+// API, the credentials are taken from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+type StorageConfig struct {
+	// Endpoint is the url of the object storage, the AWS S3 endpoint of the region is used if it's empty
+	Endpoint string
+	Region   string
+	// Bucket is the bucket the reports are written to, the scheduled reports are disabled if it's empty
+	Bucket string
+	// Prefix is the prefix of the object keys, the reports are written as "<prefix>/<name>/<file name>"
+	Prefix string
+}
+
+// Enabled returns whether the scheduled reports are written to the object storage
+func (c *StorageConfig) Enabled() bool {
+	return c != nil && c.Bucket != ""
+}
+
+// Uploader writes the reports to the object storage
+type Uploader interface {
+	Upload(ctx context.Context, definition *Definition, fileName string, body io.Reader) error
+}
+
+type s3Uploader struct {
+	uploader *s3manager.Uploader
+	bucket   string
+	prefix   string
+}
+
+// NewUploader returns the uploader of the object storage, the objects are addressed by paths rather than virtual hosts
+// to be compatible with the storages other than AWS S3.
+func NewUploader(config *StorageConfig) (Uploader, error) {
+	awsConfig := &aws.Config{
+		Region:           aws.String(config.Region),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+	}
+	awsSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the object storage session: %w", err)
+	}
+
+	return &s3Uploader{
+		uploader: s3manager.NewUploader(awsSession),
+		bucket:   config.Bucket,
+		prefix:   config.Prefix,
+	}, nil
+}
+
+// Upload streams the report to the object storage, it's uploaded in parts if it's large.
+func (u *s3Uploader) Upload(ctx context.Context, definition *Definition, fileName string, body io.Reader) error {
+	key := ObjectKey(u.prefix, definition, fileName)
+	if _, err := u.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}); err != nil {
+		return fmt.Errorf("failed to upload the report %s to the bucket %s: %w", key, u.bucket, err)
+	}
+	return nil
+}
+
+// ObjectKey returns the object key of the report file
+func ObjectKey(prefix string, definition *Definition, fileName string) string {
+	return path.Join(prefix, definition.Name, fileName)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	definitionsQuery = `SELECT definition, created_by, created_at, updated_at, last_exported_at
+		FROM report.compliance_reports`
+	saveDefinitionQuery = `INSERT INTO report.compliance_reports (name, definition, created_by) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET definition = EXCLUDED.definition, updated_at = now()`
+	scheduledCondition    = " WHERE COALESCE(definition ->> 'schedule', '') <> ''"
+	deleteDefinitionQuery = `DELETE FROM report.compliance_reports WHERE name = $1`
+	markExportedQuery     = `UPDATE report.compliance_reports SET last_exported_at = $2 WHERE name = $1`
+)
+
+// StoredDefinition is a report definition stored in the database.
+type StoredDefinition struct {
+	Definition
+	CreatedBy      string     `json:"createdBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	LastExportedAt *time.Time `json:"lastExportedAt,omitempty"`
+}
+
+// ListDefinitions returns the stored definitions ordered by their names, only the scheduled ones are returned if
+// scheduled is true.
+func ListDefinitions(ctx context.Context, dbConnectionPool *pgxpool.Pool, scheduled bool) ([]StoredDefinition, error) {
+	query := definitionsQuery
+	if scheduled {
+		query += scheduledCondition
+	}
+	rows, err := dbConnectionPool.Query(ctx, query+" ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the report definitions: %w", err)
+	}
+	defer rows.Close()
+
+	definitions := []StoredDefinition{}
+	for rows.Next() {
+		definition, err := scanDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, *definition)
+	}
+	return definitions, rows.Err()
+}
+
+// GetDefinition returns the stored definition of the name, or nil if it's not found.
+func GetDefinition(ctx context.Context, dbConnectionPool *pgxpool.Pool, name string) (*StoredDefinition, error) {
+	definition, err := scanDefinition(dbConnectionPool.QueryRow(ctx, definitionsQuery+" WHERE name = $1", name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return definition, err
+}
+
+// SaveDefinition creates the definition or replaces the stored one of the same name
+func SaveDefinition(ctx context.Context, dbConnectionPool *pgxpool.Pool, definition *Definition,
+	userName string,
+) error {
+	if _, err := dbConnectionPool.Exec(ctx, saveDefinitionQuery, definition.Name, definition,
+		userName); err != nil {
+		return fmt.Errorf("failed to save the report definition %s: %w", definition.Name, err)
+	}
+	return nil
+}
+
+// DeleteDefinition deletes the stored definition, it returns false if the definition isn't found.
+func DeleteDefinition(ctx context.Context, dbConnectionPool *pgxpool.Pool, name string) (bool, error) {
+	result, err := dbConnectionPool.Exec(ctx, deleteDefinitionQuery, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete the report definition %s: %w", name, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// MarkExported records the time the scheduled report is written to the object storage
+func MarkExported(ctx context.Context, dbConnectionPool *pgxpool.Pool, name string, exportedAt time.Time) error {
+	if _, err := dbConnectionPool.Exec(ctx, markExportedQuery, name, exportedAt); err != nil {
+		return fmt.Errorf("failed to update the export time of the report %s: %w", name, err)
+	}
+	return nil
+}
+
+func scanDefinition(row pgx.Row) (*StoredDefinition, error) {
+	definition := &StoredDefinition{}
+	var createdBy *string
+	if err := row.Scan(&definition.Definition, &createdBy, &definition.CreatedAt, &definition.UpdatedAt,
+		&definition.LastExportedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan the report definition: %w", err)
+	}
+	definition.CreatedBy = deref(createdBy)
+	definition.Definition.Default()
+	return definition, nil
+}
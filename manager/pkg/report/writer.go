@@ -0,0 +1,64 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// record is a line of the report
+type record interface {
+	csvRecord() []string
+}
+
+// recordWriter writes the records of the report in a format
+type recordWriter interface {
+	write(rec record) error
+	flush() error
+}
+
+type csvRecordWriter struct {
+	writer *csv.Writer
+}
+
+type jsonLinesRecordWriter struct {
+	encoder *json.Encoder
+}
+
+// newRecordWriter returns the writer of the format, the csv header is written at once.
+func newRecordWriter(format string, writer io.Writer, header []string) (recordWriter, error) {
+	switch format {
+	case FormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to write the csv header: %w", err)
+		}
+		return &csvRecordWriter{writer: csvWriter}, nil
+	case FormatJSONLines:
+		return &jsonLinesRecordWriter{encoder: json.NewEncoder(writer)}, nil
+	default:
+		return nil, fmt.Errorf("invalid format %s, should be %s or %s", format, FormatCSV, FormatJSONLines)
+	}
+}
+
+func (w *csvRecordWriter) write(rec record) error {
+	return w.writer.Write(rec.csvRecord())
+}
+
+func (w *csvRecordWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// write encodes the record as a json object followed by a newline
+func (w *jsonLinesRecordWriter) write(rec record) error {
+	return w.encoder.Encode(rec)
+}
+
+func (w *jsonLinesRecordWriter) flush() error {
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
@@ -17,11 +19,15 @@ import (
 	appsubv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	appv1beta1 "sigs.k8s.io/application/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 )
 
 // AddToScheme adds all the resources to be processed to the Scheme.
 func AddToScheme(runtimeScheme *runtime.Scheme) error {
 	schemeInstallFuncs := []func(scheme *runtime.Scheme) error{
+		addonv1alpha1.Install,
+		clusterv1.Install,
 		clusterv1alpha1.Install,
 		clusterv1beta1.Install,
 		clusterv1beta2.Install,
@@ -34,6 +40,7 @@ func AddToScheme(runtimeScheme *runtime.Scheme) error {
 		channelv1.SchemeBuilder,
 		appsubv1.SchemeBuilder,
 		appv1beta1.SchemeBuilder,
+		globalhubv1alpha3.SchemeBuilder,
 	}
 
 	for _, schemeInstallFunc := range schemeInstallFuncs {
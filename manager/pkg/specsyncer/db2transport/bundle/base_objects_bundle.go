@@ -17,6 +17,7 @@ func NewBaseObjectsBundle() ObjectsBundle {
 type baseObjectsBundle struct {
 	Objects        []metav1.Object `json:"objects"`
 	DeletedObjects []metav1.Object `json:"deletedObjects"`
+	BaseVersion    string          `json:"baseVersion,omitempty"`
 }
 
 // AddObject adds an object to the bundle.
@@ -30,6 +31,11 @@ func (b *baseObjectsBundle) AddDeletedObject(object metav1.Object) {
 	b.DeletedObjects = append(b.DeletedObjects, object)
 }
 
+// SetBaseVersion marks the bundle as a delta bundle, holding only the changes on top of the given bundle version.
+func (b *baseObjectsBundle) SetBaseVersion(version string) {
+	b.BaseVersion = version
+}
+
 // setMetaDataAnnotation sets metadata annotation on the given object.
 func setMetaDataAnnotation(object metav1.Object, key string, value string) {
 	annotations := object.GetAnnotations()
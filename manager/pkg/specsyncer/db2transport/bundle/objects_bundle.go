@@ -17,4 +17,6 @@ type ObjectsBundle interface {
 	AddObject(object metav1.Object, objectUID string)
 	// AddDeletedObject adds a deleted object to the bundle.
 	AddDeletedObject(object metav1.Object)
+	// SetBaseVersion marks the bundle as a delta bundle, holding only the changes on top of the given bundle version.
+	SetBaseVersion(version string)
 }
@@ -8,10 +8,13 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 // DB is the needed interface for the spec/status syncer and spec/status transport
@@ -32,19 +35,29 @@ type SpecDB interface {
 
 	ObjectsSpecDB
 	ManagedClusterLabelsSpecDB
+	LeafHubAgentConfigsSpecDB
+	LeafHubPlacementDecisionsSpecDB
+	LeafHubRolloutsSpecDB
+	GlobalResourcesSpecDB
 }
 
 // ObjectsSpecDB is the interface needed by the spec syncer and spec transport bridge to and from sync objects tables.
 type ObjectsSpecDB interface {
-	// CURD operations
+	// CURD operations, the changes are recorded with the given audit log, if any, within the same transaction
 	QuerySpecObject(ctx context.Context, tableName, objUID string, object *client.Object) error
-	InsertSpecObject(ctx context.Context, tableName, objUID string, object *client.Object) error
-	UpdateSpecObject(ctx context.Context, tableName, objUID string, object *client.Object) error
-	DeleteSpecObject(ctx context.Context, tableName, name, namespace string) error
+	InsertSpecObject(ctx context.Context, tableName, objUID string, object *client.Object,
+		auditLog *models.AuditLog) error
+	UpdateSpecObject(ctx context.Context, tableName, objUID string, object *client.Object,
+		auditLog *models.AuditLog) error
+	DeleteSpecObject(ctx context.Context, tableName, name, namespace string, auditLog *models.AuditLog) error
 
 	// GetObjectsBundle returns a bundle of objects from a specific table.
 	GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 		intoBundle bundle.ObjectsBundle) (*time.Time, error)
+	// GetUpdatedObjectsBundle returns a bundle of the objects that were updated or deleted after the given timestamp
+	// from a specific table.
+	GetUpdatedObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
+		intoBundle bundle.ObjectsBundle, timestamp *time.Time) (*time.Time, error)
 }
 
 // ManagedClusterLabelsSpecDB is the interface needed by the spec transport bridge to sync managed-cluster labels table.
@@ -54,15 +67,66 @@ type ManagedClusterLabelsSpecDB interface {
 	GetUpdatedManagedClusterLabelsBundles(ctx context.Context, tableName string,
 		timestamp *time.Time) (map[string]*spec.ManagedClusterLabelsSpecBundle, error)
 	// GetEntriesWithDeletedLabels returns a map of leaf-hub -> ManagedClusterLabelsSpecBundle of objects that have a
-	// none-empty deleted-label-keys column.
+	// none-empty deleted-label-keys, deleted-annotation-keys or deleted-taint-keys column.
 	GetEntriesWithDeletedLabels(ctx context.Context,
 		tableName string) (map[string]*spec.ManagedClusterLabelsSpecBundle, error)
-	// UpdateDeletedLabelKeys updates
-	UpdateDeletedLabelKeys(ctx context.Context, tableName string, readVersion int64, leafHubName string,
-		managedClusterName string, deletedLabelKeys []string) error
+	// UpdateDeletedKeys updates the deleted label, annotation and taint keys of a managed cluster entry.
+	UpdateDeletedKeys(ctx context.Context, tableName string, readVersion int64, leafHubName string,
+		managedClusterName string, deletedLabelKeys, deletedAnnotationKeys, deletedTaintKeys []string) error
 	TempManagedClusterLabelsSpecDB
 }
 
+// LeafHubAgentConfigsSpecDB is the interface needed by the spec syncer and spec transport bridge to sync the
+// effective agent configs of the leaf hubs.
+type LeafHubAgentConfigsSpecDB interface {
+	// GetUpdatedLeafHubAgentConfigs returns a map of leaf-hub -> AgentConfigSpecBundle of the agent configs that were
+	// updated after the given timestamp.
+	GetUpdatedLeafHubAgentConfigs(ctx context.Context, timestamp *time.Time) (
+		map[string]*spec.AgentConfigSpecBundle, error)
+	// UpsertLeafHubAgentConfig inserts or updates the effective agent config of a leaf hub, the row is only touched if
+	// the config or the source of it has changed.
+	UpsertLeafHubAgentConfig(ctx context.Context, agentConfigBundle *spec.AgentConfigSpecBundle) error
+	// DeleteLeafHubAgentConfigs deletes the agent configs of the leaf hubs which aren't in the given leaf hub names.
+	DeleteLeafHubAgentConfigs(ctx context.Context, leafHubNamesToKeep []string) error
+}
+
+// LeafHubPlacementDecisionsSpecDB is the interface needed by the global scheduler and spec transport bridge to sync
+// the decisions of the global placements on the leaf hubs.
+type LeafHubPlacementDecisionsSpecDB interface {
+	// GetUpdatedLeafHubPlacementDecisions returns a map of leaf-hub -> PlacementDecisionsSpecBundle of the decisions
+	// that were updated after the given timestamp.
+	GetUpdatedLeafHubPlacementDecisions(ctx context.Context, timestamp *time.Time) (
+		map[string]*spec.PlacementDecisionsSpecBundle, error)
+	// UpsertLeafHubPlacementDecisions inserts or updates the decisions of a leaf hub, the row is only touched if the
+	// decisions have changed, and the change is recorded in the audit log.
+	UpsertLeafHubPlacementDecisions(ctx context.Context, decisionsBundle *spec.PlacementDecisionsSpecBundle) error
+	// DeleteLeafHubPlacementDecisions deletes the decisions of the leaf hubs which aren't in the given leaf hub names.
+	DeleteLeafHubPlacementDecisions(ctx context.Context, leafHubNamesToKeep []string) error
+}
+
+// LeafHubRolloutsSpecDB is the interface needed by the rollout controller and spec transport bridge to sync the
+// global resources rolled out to the leaf hubs in waves.
+type LeafHubRolloutsSpecDB interface {
+	// GetUpdatedLeafHubRollouts returns a map of leaf-hub -> RolloutsSpecBundle of the rolled out resources that were
+	// updated after the given timestamp.
+	GetUpdatedLeafHubRollouts(ctx context.Context, timestamp *time.Time) (map[string]*spec.RolloutsSpecBundle, error)
+	// UpsertLeafHubRollouts inserts or updates the rolled out resources of a leaf hub, the row is only touched if the
+	// resources have changed, and the change is recorded in the audit log.
+	UpsertLeafHubRollouts(ctx context.Context, rolloutsBundle *spec.RolloutsSpecBundle) error
+	// DeleteLeafHubRollouts deletes the rolled out resources of the leaf hubs which aren't in the given leaf hub names.
+	DeleteLeafHubRollouts(ctx context.Context, leafHubNamesToKeep []string) error
+}
+
+// GlobalResourcesSpecDB is the interface needed by the spec syncer and spec transport bridge to sync the objects of
+// the registered global resource types, the objects of all the types are kept in the resources table by their GVK.
+type GlobalResourcesSpecDB interface {
+	// GetGlobalResourceTypes returns the group version kinds of the objects in the resources table.
+	GetGlobalResourceTypes(ctx context.Context) ([]schema.GroupVersionKind, error)
+	// GlobalResourceSpecDB returns the spec db of the objects of the given group version kind, the objects are
+	// read from and written into the resources table regardless of the table name passed to its methods.
+	GlobalResourceSpecDB(gvk schema.GroupVersionKind) SpecDB
+}
+
 // TempManagedClusterLabelsSpecDB appends ManagedClusterLabelsSpecDB interface with temporary functionality that should
 // be removed after it is satisfied by a different component.
 // TODO: once non-k8s-restapi exposes hub names, delete interface.
@@ -77,9 +141,10 @@ type TempManagedClusterLabelsSpecDB interface {
 
 // StatusDB is the needed interface for the db transport bridge to fetch information from status DB.
 type StatusDB interface {
-	// GetManagedClusterLabelsStatus gets the labels present in managed-cluster CR metadata from a specific table.
-	GetManagedClusterLabelsStatus(ctx context.Context, tableName string, leafHubName string,
-		managedClusterName string) (map[string]string, error)
+	// GetManagedClusterStatus gets the managed-cluster CR, with the labels, annotations and taints present on the
+	// leaf hub, from a specific table.
+	GetManagedClusterStatus(ctx context.Context, tableName string, leafHubName string,
+		managedClusterName string) (*clusterv1.ManagedCluster, error)
 	TempStatusDB
 }
 
@@ -12,16 +12,26 @@ import (
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
 	managerconfig "github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 var errOptimisticConcurrencyUpdateFailed = errors.New("zero rows were affected by an optimistic concurrency update")
 
+// rolloutFilter filters out the objects rolled out in waves, they're sent to the leaf hubs by the rollout syncer, the
+// deleted objects are still broadcast so that they're removed from all the leaf hubs at once.
+const rolloutFilter = `(deleted OR
+	payload->'metadata'->'annotations'->'global-hub.open-cluster-management.io/rollout-policy' IS NULL)`
+
 // PostgreSQL abstracts PostgreSQL client.
 type PostgreSQL struct {
 	conn *pgxpool.Pool
@@ -80,34 +90,40 @@ func (p *PostgreSQL) QuerySpecObject(ctx context.Context, tableName, objUID stri
 }
 
 // InsertSpecObject insets new object to given table with object UID and payload
-func (p *PostgreSQL) InsertSpecObject(ctx context.Context, tableName, objUID string, object *client.Object) error {
+func (p *PostgreSQL) InsertSpecObject(ctx context.Context, tableName, objUID string, object *client.Object,
+	auditLog *models.AuditLog,
+) error {
 	query := fmt.Sprintf("INSERT INTO spec.%s (id,payload) values($1, $2::jsonb)", tableName)
-	if _, err := p.conn.Exec(ctx, query, objUID, object); err != nil {
+	if err := p.writeSpecObjects(ctx, tableName, auditLog, query, objUID, object); err != nil {
 		return fmt.Errorf("insert into database failed: %w", err)
 	}
 	return nil
 }
 
 // UpdateSpecObject updates object payload in given table with object UID
-func (p *PostgreSQL) UpdateSpecObject(ctx context.Context, tableName, objUID string, object *client.Object) error {
+func (p *PostgreSQL) UpdateSpecObject(ctx context.Context, tableName, objUID string, object *client.Object,
+	auditLog *models.AuditLog,
+) error {
 	query := fmt.Sprintf("UPDATE spec.%s SET payload = $1 WHERE id = $2", tableName)
-	if _, err := p.conn.Exec(ctx, query, object, objUID); err != nil {
+	if err := p.writeSpecObjects(ctx, tableName, auditLog, query, object, objUID); err != nil {
 		return fmt.Errorf("failed to update the database with new value: %w", err)
 	}
 	return nil
 }
 
 // DeleteSpecObject deletes object with name and namespace from given table
-func (p *PostgreSQL) DeleteSpecObject(ctx context.Context, tableName, name, namespace string) error {
+func (p *PostgreSQL) DeleteSpecObject(ctx context.Context, tableName, name, namespace string,
+	auditLog *models.AuditLog,
+) error {
 	var err error
 	if namespace != "" {
 		query := fmt.Sprintf(`UPDATE spec.%s SET deleted = true WHERE payload -> 'metadata' ->> 'name' = $1 AND
 			payload -> 'metadata' ->> 'namespace' = $2 AND deleted = false`, tableName)
-		_, err = p.conn.Exec(ctx, query, name, namespace)
+		err = p.writeSpecObjects(ctx, tableName, auditLog, query, name, namespace)
 	} else {
 		query := fmt.Sprintf(`UPDATE spec.%s SET deleted = true WHERE payload -> 'metadata' ->> 'name' = $1 AND
 			payload -> 'metadata' ->> 'namespace' IS NULL AND deleted = false`, tableName)
-		_, err = p.conn.Exec(ctx, query, name)
+		err = p.writeSpecObjects(ctx, tableName, auditLog, query, name)
 	}
 
 	if err != nil {
@@ -117,6 +133,63 @@ func (p *PostgreSQL) DeleteSpecObject(ctx context.Context, tableName, name, name
 	return nil
 }
 
+// specObjectRow is a row of a spec table written by a change.
+type specObjectRow struct {
+	id        string
+	payload   []byte
+	updatedAt time.Time
+}
+
+// writeSpecObjects runs the insert, update or delete of the spec objects, and records the change of every written
+// object in the audit log within the same transaction, if an audit log is given. the fields of the audit log which
+// aren't set are taken from the written object, like the kind and the id of the deleted objects.
+func (p *PostgreSQL) writeSpecObjects(ctx context.Context, tableName string, auditLog *models.AuditLog,
+	query string, args ...interface{},
+) error {
+	return p.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query+" RETURNING id, payload, updated_at", args...)
+		if err != nil {
+			return err
+		}
+		var writtenRows []specObjectRow
+		for rows.Next() {
+			row := specObjectRow{}
+			if err := rows.Scan(&row.id, &row.payload, &row.updatedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			writtenRows = append(writtenRows, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil || auditLog == nil {
+			return err
+		}
+
+		for _, row := range writtenRows {
+			object := &metav1.PartialObjectMetadata{}
+			if err := json.Unmarshal(row.payload, object); err != nil {
+				return fmt.Errorf("failed to unmarshal the object %s - %w", row.id, err)
+			}
+			objectAuditLog := *auditLog
+			if objectAuditLog.ResourceID == "" {
+				objectAuditLog.ResourceID = row.id
+			}
+			if objectAuditLog.Kind == "" {
+				objectAuditLog.Kind = object.Kind
+			}
+			if objectAuditLog.Name == "" {
+				objectAuditLog.Name, objectAuditLog.Namespace = object.GetName(), object.GetNamespace()
+			}
+			_, rolledOut := object.GetAnnotations()[constants.RolloutPolicyAnnotation]
+			if err := audit.RecordSpecChange(ctx, tx, tableName, rolledOut, row.updatedAt,
+				&objectAuditLog); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // GetObjectsBundle returns a bundle of objects from a specific table.
 func (p *PostgreSQL) GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 	intoBundle bundle.ObjectsBundle,
@@ -126,11 +199,42 @@ func (p *PostgreSQL) GetObjectsBundle(ctx context.Context, tableName string, cre
 		return nil, err
 	}
 
-	rows, err := p.conn.Query(ctx, fmt.Sprintf(`SELECT id,payload,deleted FROM spec.%s WHERE
-		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL`,
-		tableName))
+	if err := p.fillObjectsBundle(ctx, tableName, createObjFunc, intoBundle, fmt.Sprintf(
+		`SELECT id,payload,deleted FROM spec.%s WHERE
+		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL AND %s`,
+		tableName, rolloutFilter)); err != nil {
+		return nil, err
+	}
+
+	return timestamp, nil
+}
+
+// GetUpdatedObjectsBundle returns a bundle of the objects that were updated or deleted after the given timestamp
+// from a specific table.
+func (p *PostgreSQL) GetUpdatedObjectsBundle(ctx context.Context, tableName string,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle, timestamp *time.Time,
+) (*time.Time, error) {
+	lastUpdateTimestamp, err := p.GetLastUpdateTimestamp(ctx, tableName, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query table spec.%s - %w", tableName, err)
+		return nil, err
+	}
+
+	if err := p.fillObjectsBundle(ctx, tableName, createObjFunc, intoBundle, fmt.Sprintf(
+		`SELECT id,payload,deleted FROM spec.%s WHERE
+		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL AND
+		%s AND updated_at > $1`, tableName, rolloutFilter), timestamp); err != nil {
+		return nil, err
+	}
+
+	return lastUpdateTimestamp, nil
+}
+
+func (p *PostgreSQL) fillObjectsBundle(ctx context.Context, tableName string,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle, query string, args ...interface{},
+) error {
+	rows, err := p.conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query table spec.%s - %w", tableName, err)
 	}
 
 	defer rows.Close()
@@ -143,7 +247,7 @@ func (p *PostgreSQL) GetObjectsBundle(ctx context.Context, tableName string, cre
 
 		object := createObjFunc()
 		if err := rows.Scan(&objID, &object, &deleted); err != nil {
-			return nil, fmt.Errorf("error reading from table spec.%s - %w", tableName, err)
+			return fmt.Errorf("error reading from table spec.%s - %w", tableName, err)
 		}
 
 		if deleted {
@@ -154,7 +258,7 @@ func (p *PostgreSQL) GetObjectsBundle(ctx context.Context, tableName string, cre
 		}
 	}
 
-	return timestamp, nil
+	return nil
 }
 
 // GetUpdatedManagedClusterLabelsBundles returns a map of leaf-hub -> ManagedClusterLabelsSpecBundle of objects
@@ -164,7 +268,8 @@ func (p *PostgreSQL) GetUpdatedManagedClusterLabelsBundles(ctx context.Context,
 ) (map[string]*spec.ManagedClusterLabelsSpecBundle, error) {
 	// select ManagedClusterLabelsSpec entries information from DB
 	rows, err := p.conn.Query(ctx, fmt.Sprintf(`SELECT leaf_hub_name,managed_cluster_name,labels,
-		deleted_label_keys,updated_at,version FROM spec.%[1]s WHERE leaf_hub_name IN (SELECT DISTINCT(leaf_hub_name) 
+		deleted_label_keys,annotations,deleted_annotation_keys,taints,deleted_taint_keys,updated_at,version
+		FROM spec.%[1]s WHERE leaf_hub_name IN (SELECT DISTINCT(leaf_hub_name) 
 		from spec.%[1]s WHERE updated_at::timestamp > timestamp '%[2]s') AND leaf_hub_name <> ''`, tableName,
 		timestamp.Format(time.RFC3339Nano)))
 	if err != nil {
@@ -182,7 +287,9 @@ func (p *PostgreSQL) GetUpdatedManagedClusterLabelsBundles(ctx context.Context,
 		)
 
 		if err := rows.Scan(&leafHubName, &managedClusterLabelsSpec.ClusterName, &managedClusterLabelsSpec.Labels,
-			&managedClusterLabelsSpec.DeletedLabelKeys,
+			&managedClusterLabelsSpec.DeletedLabelKeys, &managedClusterLabelsSpec.Annotations,
+			&managedClusterLabelsSpec.DeletedAnnotationKeys, &managedClusterLabelsSpec.Taints,
+			&managedClusterLabelsSpec.DeletedTaintKeys,
 			&managedClusterLabelsSpec.UpdateTimestamp,
 			&managedClusterLabelsSpec.Version); err != nil {
 			return nil, fmt.Errorf("error reading from table - %w", err)
@@ -208,12 +315,13 @@ func (p *PostgreSQL) GetUpdatedManagedClusterLabelsBundles(ctx context.Context,
 }
 
 // GetEntriesWithDeletedLabels returns a map of leaf-hub -> ManagedClusterLabelsSpecBundle of objects that have a
-// none-empty deleted-label-keys column.
+// none-empty deleted-label-keys, deleted-annotation-keys or deleted-taint-keys column.
 func (p *PostgreSQL) GetEntriesWithDeletedLabels(ctx context.Context,
 	tableName string,
 ) (map[string]*spec.ManagedClusterLabelsSpecBundle, error) {
-	rows, err := p.conn.Query(ctx, fmt.Sprintf(`SELECT leaf_hub_name,managed_cluster_name,deleted_label_keys,version 
-		FROM spec.%s WHERE deleted_label_keys != '[]' AND leaf_hub_name <> ''`, tableName))
+	rows, err := p.conn.Query(ctx, fmt.Sprintf(`SELECT leaf_hub_name,managed_cluster_name,deleted_label_keys,
+		deleted_annotation_keys,deleted_taint_keys,version FROM spec.%s WHERE (deleted_label_keys != '[]' OR
+		deleted_annotation_keys != '[]' OR deleted_taint_keys != '[]') AND leaf_hub_name <> ''`, tableName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query table spec.%s - %w", tableName, err)
 	}
@@ -229,7 +337,8 @@ func (p *PostgreSQL) GetEntriesWithDeletedLabels(ctx context.Context,
 		)
 
 		if err := rows.Scan(&leafHubName, &managedClusterLabelsSpec.ClusterName,
-			&managedClusterLabelsSpec.DeletedLabelKeys, &managedClusterLabelsSpec.Version); err != nil {
+			&managedClusterLabelsSpec.DeletedLabelKeys, &managedClusterLabelsSpec.DeletedAnnotationKeys,
+			&managedClusterLabelsSpec.DeletedTaintKeys, &managedClusterLabelsSpec.Version); err != nil {
 			return nil, fmt.Errorf("error reading from table - %w", err)
 		}
 
@@ -252,19 +361,28 @@ func (p *PostgreSQL) GetEntriesWithDeletedLabels(ctx context.Context,
 	return leafHubToLabelsSpecBundleMap, nil
 }
 
-// UpdateDeletedLabelKeys updates deleted_label_keys value for a managed cluster entry under
-// optimistic concurrency approach.
-func (p *PostgreSQL) UpdateDeletedLabelKeys(ctx context.Context, tableName string, readVersion int64,
-	leafHubName string, managedClusterName string, deletedLabelKeys []string,
+// UpdateDeletedKeys updates deleted_label_keys, deleted_annotation_keys and deleted_taint_keys values for a managed
+// cluster entry under optimistic concurrency approach.
+func (p *PostgreSQL) UpdateDeletedKeys(ctx context.Context, tableName string, readVersion int64,
+	leafHubName string, managedClusterName string, deletedLabelKeys, deletedAnnotationKeys, deletedTaintKeys []string,
 ) error {
 	deletedLabelsJSON, err := json.Marshal(deletedLabelKeys)
 	if err != nil {
 		return fmt.Errorf("failed to marshal deleted labels - %w", err)
 	}
+	deletedAnnotationsJSON, err := json.Marshal(deletedAnnotationKeys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deleted annotations - %w", err)
+	}
+	deletedTaintsJSON, err := json.Marshal(deletedTaintKeys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deleted taints - %w", err)
+	}
 
 	if commandTag, err := p.conn.Exec(ctx, fmt.Sprintf(`UPDATE spec.%s SET updated_at=now(),deleted_label_keys=$1,
-		version=$2 WHERE leaf_hub_name=$3 AND managed_cluster_name=$4 AND version=$5`, tableName), deletedLabelsJSON,
-		readVersion+1, leafHubName, managedClusterName, readVersion); err != nil {
+		deleted_annotation_keys=$2,deleted_taint_keys=$3,version=$4 WHERE leaf_hub_name=$5 AND
+		managed_cluster_name=$6 AND version=$7`, tableName), deletedLabelsJSON, deletedAnnotationsJSON,
+		deletedTaintsJSON, readVersion+1, leafHubName, managedClusterName, readVersion); err != nil {
 		return fmt.Errorf("failed to update managed cluster labels row in spec.%s - %w", tableName, err)
 	} else if commandTag.RowsAffected() == 0 {
 		return errOptimisticConcurrencyUpdateFailed
@@ -273,6 +391,225 @@ func (p *PostgreSQL) UpdateDeletedLabelKeys(ctx context.Context, tableName strin
 	return nil
 }
 
+// GetUpdatedLeafHubAgentConfigs returns a map of leaf-hub -> AgentConfigSpecBundle of the agent configs that were
+// updated after the given timestamp.
+func (p *PostgreSQL) GetUpdatedLeafHubAgentConfigs(ctx context.Context, timestamp *time.Time) (
+	map[string]*spec.AgentConfigSpecBundle, error,
+) {
+	rows, err := p.conn.Query(ctx, `SELECT leaf_hub_name, source, payload FROM spec.leaf_hub_agent_configs WHERE
+		updated_at > $1`, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from spec.leaf_hub_agent_configs - %w", err)
+	}
+
+	defer rows.Close()
+
+	leafHubToAgentConfigBundleMap := make(map[string]*spec.AgentConfigSpecBundle)
+
+	for rows.Next() {
+		agentConfigBundle := &spec.AgentConfigSpecBundle{}
+		if err := rows.Scan(&agentConfigBundle.LeafHubName, &agentConfigBundle.Source,
+			&agentConfigBundle.AgentConfig); err != nil {
+			return nil, fmt.Errorf("error reading from spec.leaf_hub_agent_configs - %w", err)
+		}
+
+		leafHubToAgentConfigBundleMap[agentConfigBundle.LeafHubName] = agentConfigBundle
+	}
+
+	return leafHubToAgentConfigBundleMap, nil
+}
+
+// UpsertLeafHubAgentConfig inserts or updates the effective agent config of a leaf hub, the row is only touched if
+// the config or the source of it has changed.
+func (p *PostgreSQL) UpsertLeafHubAgentConfig(ctx context.Context,
+	agentConfigBundle *spec.AgentConfigSpecBundle,
+) error {
+	payloadBytes, err := json.Marshal(agentConfigBundle.AgentConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent config of leaf hub %s - %w", agentConfigBundle.LeafHubName, err)
+	}
+
+	if _, err := p.conn.Exec(ctx, `INSERT INTO spec.leaf_hub_agent_configs (leaf_hub_name, source, payload)
+		VALUES ($1, $2, $3) ON CONFLICT (leaf_hub_name) DO UPDATE SET source=EXCLUDED.source,
+		payload=EXCLUDED.payload, updated_at=now() WHERE spec.leaf_hub_agent_configs.source <> EXCLUDED.source OR
+		spec.leaf_hub_agent_configs.payload <> EXCLUDED.payload`, agentConfigBundle.LeafHubName,
+		agentConfigBundle.Source, payloadBytes); err != nil {
+		return fmt.Errorf("failed to upsert agent config of leaf hub %s - %w", agentConfigBundle.LeafHubName, err)
+	}
+
+	return nil
+}
+
+// DeleteLeafHubAgentConfigs deletes the agent configs of the leaf hubs which aren't in the given leaf hub names.
+func (p *PostgreSQL) DeleteLeafHubAgentConfigs(ctx context.Context, leafHubNamesToKeep []string) error {
+	if _, err := p.conn.Exec(ctx, `DELETE FROM spec.leaf_hub_agent_configs WHERE NOT (leaf_hub_name = ANY($1))`,
+		leafHubNamesToKeep); err != nil {
+		return fmt.Errorf("failed to delete agent configs from spec.leaf_hub_agent_configs - %w", err)
+	}
+
+	return nil
+}
+
+// GetUpdatedLeafHubPlacementDecisions returns a map of leaf-hub -> PlacementDecisionsSpecBundle of the decisions that
+// were updated after the given timestamp.
+func (p *PostgreSQL) GetUpdatedLeafHubPlacementDecisions(ctx context.Context, timestamp *time.Time) (
+	map[string]*spec.PlacementDecisionsSpecBundle, error,
+) {
+	rows, err := p.conn.Query(ctx, `SELECT leaf_hub_name, payload FROM spec.leaf_hub_placement_decisions WHERE
+		updated_at > $1`, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from spec.leaf_hub_placement_decisions - %w", err)
+	}
+
+	defer rows.Close()
+
+	leafHubToDecisionsBundleMap := make(map[string]*spec.PlacementDecisionsSpecBundle)
+
+	for rows.Next() {
+		decisionsBundle := &spec.PlacementDecisionsSpecBundle{}
+		if err := rows.Scan(&decisionsBundle.LeafHubName, &decisionsBundle.Decisions); err != nil {
+			return nil, fmt.Errorf("error reading from spec.leaf_hub_placement_decisions - %w", err)
+		}
+
+		leafHubToDecisionsBundleMap[decisionsBundle.LeafHubName] = decisionsBundle
+	}
+
+	return leafHubToDecisionsBundleMap, nil
+}
+
+// UpsertLeafHubPlacementDecisions inserts or updates the decisions of a leaf hub, the row is only touched if the
+// decisions have changed, and the change is recorded in the audit log.
+func (p *PostgreSQL) UpsertLeafHubPlacementDecisions(ctx context.Context,
+	decisionsBundle *spec.PlacementDecisionsSpecBundle,
+) error {
+	payloadBytes, err := json.Marshal(decisionsBundle.Decisions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decisions of leaf hub %s - %w", decisionsBundle.LeafHubName, err)
+	}
+
+	details, err := json.Marshal(map[string]interface{}{"decisions": decisionsBundle.Decisions})
+	if err != nil {
+		return err
+	}
+	if err := p.upsertLeafHubPayload(ctx, "leaf_hub_placement_decisions", decisionsBundle.LeafHubName,
+		payloadBytes, &models.AuditLog{
+			UserName:  audit.SystemUser,
+			Source:    database.AuditSourceScheduler,
+			Operation: database.AuditOperationSchedule,
+			Details:   details,
+		}); err != nil {
+		return fmt.Errorf("failed to upsert decisions of leaf hub %s - %w", decisionsBundle.LeafHubName, err)
+	}
+
+	return nil
+}
+
+// upsertLeafHubPayload inserts or updates the payload of a leaf hub in the given spec table, the row is only touched
+// if the payload has changed, and the change is recorded in the audit log within the same transaction.
+func (p *PostgreSQL) upsertLeafHubPayload(ctx context.Context, tableName, leafHubName string, payload []byte,
+	auditLog *models.AuditLog,
+) error {
+	return p.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		var updatedAt time.Time
+		err := tx.QueryRow(ctx, fmt.Sprintf(`INSERT INTO spec.%[1]s (leaf_hub_name, payload) VALUES ($1, $2)
+			ON CONFLICT (leaf_hub_name) DO UPDATE SET payload=EXCLUDED.payload, updated_at=now()
+			WHERE spec.%[1]s.payload <> EXCLUDED.payload RETURNING updated_at`, tableName), leafHubName,
+			payload).Scan(&updatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // the payload isn't changed
+		}
+		if err != nil {
+			return err
+		}
+
+		targetHubs, err := json.Marshal([]string{leafHubName})
+		if err != nil {
+			return err
+		}
+		auditLog.Kind, auditLog.Name = "LeafHub", leafHubName
+		auditLog.TargetHubs = targetHubs
+		auditLog.SpecVersion = audit.FormatSpecVersion(updatedAt)
+		return audit.Record(ctx, tx, auditLog)
+	})
+}
+
+// DeleteLeafHubPlacementDecisions deletes the decisions of the leaf hubs which aren't in the given leaf hub names.
+func (p *PostgreSQL) DeleteLeafHubPlacementDecisions(ctx context.Context, leafHubNamesToKeep []string) error {
+	if _, err := p.conn.Exec(ctx, `DELETE FROM spec.leaf_hub_placement_decisions WHERE
+		NOT (leaf_hub_name = ANY($1))`, leafHubNamesToKeep); err != nil {
+		return fmt.Errorf("failed to delete decisions from spec.leaf_hub_placement_decisions - %w", err)
+	}
+
+	return nil
+}
+
+// GetUpdatedLeafHubRollouts returns a map of leaf-hub -> RolloutsSpecBundle of the rolled out resources that were
+// updated after the given timestamp.
+func (p *PostgreSQL) GetUpdatedLeafHubRollouts(ctx context.Context, timestamp *time.Time) (
+	map[string]*spec.RolloutsSpecBundle, error,
+) {
+	rows, err := p.conn.Query(ctx, `SELECT leaf_hub_name, payload FROM spec.leaf_hub_rollouts WHERE updated_at > $1`,
+		timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from spec.leaf_hub_rollouts - %w", err)
+	}
+
+	defer rows.Close()
+
+	leafHubToRolloutsBundleMap := make(map[string]*spec.RolloutsSpecBundle)
+
+	for rows.Next() {
+		rolloutsBundle := &spec.RolloutsSpecBundle{}
+		if err := rows.Scan(&rolloutsBundle.LeafHubName, &rolloutsBundle.Objects); err != nil {
+			return nil, fmt.Errorf("error reading from spec.leaf_hub_rollouts - %w", err)
+		}
+
+		leafHubToRolloutsBundleMap[rolloutsBundle.LeafHubName] = rolloutsBundle
+	}
+
+	return leafHubToRolloutsBundleMap, nil
+}
+
+// UpsertLeafHubRollouts inserts or updates the rolled out resources of a leaf hub, the row is only touched if the
+// resources have changed, and the change is recorded in the audit log.
+func (p *PostgreSQL) UpsertLeafHubRollouts(ctx context.Context, rolloutsBundle *spec.RolloutsSpecBundle) error {
+	payloadBytes, err := json.Marshal(rolloutsBundle.Objects)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollouts of leaf hub %s - %w", rolloutsBundle.LeafHubName, err)
+	}
+
+	// the resources are recorded by their ids, the versions of the resources are in the bundle
+	resourceIDs := make([]string, 0, len(rolloutsBundle.Objects))
+	for _, object := range rolloutsBundle.Objects {
+		resourceIDs = append(resourceIDs, object.GetAnnotations()[constants.OriginOwnerReferenceAnnotation])
+	}
+	details, err := json.Marshal(map[string]interface{}{"resources": resourceIDs})
+	if err != nil {
+		return err
+	}
+	if err := p.upsertLeafHubPayload(ctx, "leaf_hub_rollouts", rolloutsBundle.LeafHubName, payloadBytes,
+		&models.AuditLog{
+			UserName:  audit.SystemUser,
+			Source:    database.AuditSourceRollout,
+			Operation: database.AuditOperationRollout,
+			Details:   details,
+		}); err != nil {
+		return fmt.Errorf("failed to upsert rollouts of leaf hub %s - %w", rolloutsBundle.LeafHubName, err)
+	}
+
+	return nil
+}
+
+// DeleteLeafHubRollouts deletes the rolled out resources of the leaf hubs which aren't in the given leaf hub names.
+func (p *PostgreSQL) DeleteLeafHubRollouts(ctx context.Context, leafHubNamesToKeep []string) error {
+	if _, err := p.conn.Exec(ctx, `DELETE FROM spec.leaf_hub_rollouts WHERE NOT (leaf_hub_name = ANY($1))`,
+		leafHubNamesToKeep); err != nil {
+		return fmt.Errorf("failed to delete rollouts from spec.leaf_hub_rollouts - %w", err)
+	}
+
+	return nil
+}
+
 // GetEntriesWithoutLeafHubName returns a slice of ManagedClusterLabelsSpec that are missing leaf hub name.
 func (p *PostgreSQL) GetEntriesWithoutLeafHubName(ctx context.Context,
 	tableName string,
@@ -321,19 +658,20 @@ func (p *PostgreSQL) UpdateLeafHubName(ctx context.Context, tableName string, re
 	return nil
 }
 
-// GetManagedClusterLabelsStatus gets the labels present in managed-cluster CR metadata from a specific table.
-func (p *PostgreSQL) GetManagedClusterLabelsStatus(ctx context.Context, tableName string, leafHubName string,
+// GetManagedClusterStatus gets the managed-cluster CR, with the labels, annotations and taints present on the leaf
+// hub, from a specific table.
+func (p *PostgreSQL) GetManagedClusterStatus(ctx context.Context, tableName string, leafHubName string,
 	managedClusterName string,
-) (map[string]string, error) {
-	labels := make(map[string]string)
+) (*clusterv1.ManagedCluster, error) {
+	managedCluster := &clusterv1.ManagedCluster{}
 
-	if err := p.conn.QueryRow(ctx, fmt.Sprintf(`SELECT payload->'metadata'->'labels' FROM status.%s WHERE 
+	if err := p.conn.QueryRow(ctx, fmt.Sprintf(`SELECT payload FROM status.%s WHERE 
 		leaf_hub_name=$1 AND payload->'metadata'->>'name'=$2`, tableName), leafHubName,
-		managedClusterName).Scan(&labels); err != nil {
+		managedClusterName).Scan(managedCluster); err != nil {
 		return nil, fmt.Errorf("error reading from table status.%s - %w", tableName, err)
 	}
 
-	return labels, nil
+	return managedCluster, nil
 }
 
 // GetManagedClusterLeafHubName returns leaf-hub name for a given managed cluster from a specific table.
@@ -0,0 +1,175 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	resourcesTableName = "resources"
+	gvkCondition       = "api_group = $1 AND api_version = $2 AND kind = $3"
+	globalLabelFilter  = `payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource'
+		IS NOT NULL`
+)
+
+// GetGlobalResourceTypes returns the group version kinds of the objects in the resources table.
+func (p *PostgreSQL) GetGlobalResourceTypes(ctx context.Context) ([]schema.GroupVersionKind, error) {
+	rows, err := p.conn.Query(ctx, fmt.Sprintf("SELECT DISTINCT api_group, api_version, kind FROM spec.%s",
+		resourcesTableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table spec.%s - %w", resourcesTableName, err)
+	}
+	defer rows.Close()
+
+	var gvks []schema.GroupVersionKind
+	for rows.Next() {
+		gvk := schema.GroupVersionKind{}
+		if err := rows.Scan(&gvk.Group, &gvk.Version, &gvk.Kind); err != nil {
+			return nil, fmt.Errorf("error reading from table spec.%s - %w", resourcesTableName, err)
+		}
+		gvks = append(gvks, gvk)
+	}
+	return gvks, nil
+}
+
+// GlobalResourceSpecDB returns the spec db of the objects of the given group version kind.
+func (p *PostgreSQL) GlobalResourceSpecDB(gvk schema.GroupVersionKind) db.SpecDB {
+	return &globalResourcePostgreSQL{PostgreSQL: p, gvk: gvk}
+}
+
+// globalResourcePostgreSQL keeps the objects of a global resource type in the resources table, the table name passed
+// to the objects functions is ignored, and the rows are filtered by the group version kind of the type.
+type globalResourcePostgreSQL struct {
+	*PostgreSQL
+	gvk schema.GroupVersionKind
+}
+
+// GetLastUpdateTimestamp returns the last update timestamp of the objects of the type.
+func (p *globalResourcePostgreSQL) GetLastUpdateTimestamp(ctx context.Context, _ string,
+	filterLocalResources bool,
+) (*time.Time, error) {
+	var lastTimestamp time.Time
+
+	query := fmt.Sprintf("SELECT MAX(updated_at) FROM spec.%s WHERE %s", resourcesTableName, gvkCondition)
+	if filterLocalResources {
+		query = fmt.Sprintf("%s AND %s", query, globalLabelFilter)
+	}
+
+	err := p.conn.QueryRow(ctx, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind).Scan(&lastTimestamp)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("no objects of %s in the table spec.%s - %w", p.gvk, resourcesTableName, err)
+	}
+
+	return &lastTimestamp, nil
+}
+
+// QuerySpecObject gets the object of the type with object UID
+func (p *globalResourcePostgreSQL) QuerySpecObject(ctx context.Context, _, objUID string,
+	object *client.Object,
+) error {
+	query := fmt.Sprintf("SELECT payload FROM spec.%s WHERE id = $4 AND %s", resourcesTableName, gvkCondition)
+	if err := p.conn.QueryRow(ctx, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+		objUID).Scan(&object); err != nil {
+		return fmt.Errorf("failed to get the instance in the database: %w", err)
+	}
+	return nil
+}
+
+// InsertSpecObject insets new object of the type with object UID and payload
+func (p *globalResourcePostgreSQL) InsertSpecObject(ctx context.Context, _, objUID string,
+	object *client.Object, auditLog *models.AuditLog,
+) error {
+	query := fmt.Sprintf(`INSERT INTO spec.%s (api_group,api_version,kind,id,payload)
+		values($1, $2, $3, $4, $5::jsonb)`, resourcesTableName)
+	if err := p.writeSpecObjects(ctx, resourcesTableName, auditLog, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+		objUID, object); err != nil {
+		return fmt.Errorf("insert into database failed: %w", err)
+	}
+	return nil
+}
+
+// UpdateSpecObject updates the payload of the object of the type with object UID
+func (p *globalResourcePostgreSQL) UpdateSpecObject(ctx context.Context, _, objUID string,
+	object *client.Object, auditLog *models.AuditLog,
+) error {
+	query := fmt.Sprintf("UPDATE spec.%s SET payload = $5 WHERE id = $4 AND %s", resourcesTableName, gvkCondition)
+	if err := p.writeSpecObjects(ctx, resourcesTableName, auditLog, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+		objUID, object); err != nil {
+		return fmt.Errorf("failed to update the database with new value: %w", err)
+	}
+	return nil
+}
+
+// DeleteSpecObject deletes the object of the type with name and namespace
+func (p *globalResourcePostgreSQL) DeleteSpecObject(ctx context.Context, _, name, namespace string,
+	auditLog *models.AuditLog,
+) error {
+	var err error
+	if namespace != "" {
+		query := fmt.Sprintf(`UPDATE spec.%s SET deleted = true WHERE %s AND
+			payload -> 'metadata' ->> 'name' = $4 AND payload -> 'metadata' ->> 'namespace' = $5 AND
+			deleted = false`, resourcesTableName, gvkCondition)
+		err = p.writeSpecObjects(ctx, resourcesTableName, auditLog, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+			name, namespace)
+	} else {
+		query := fmt.Sprintf(`UPDATE spec.%s SET deleted = true WHERE %s AND
+			payload -> 'metadata' ->> 'name' = $4 AND payload -> 'metadata' ->> 'namespace' IS NULL AND
+			deleted = false`, resourcesTableName, gvkCondition)
+		err = p.writeSpecObjects(ctx, resourcesTableName, auditLog, query, p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+			name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to delete instance from the database: %w", err)
+	}
+
+	return nil
+}
+
+// GetObjectsBundle returns a bundle of the objects of the type.
+func (p *globalResourcePostgreSQL) GetObjectsBundle(ctx context.Context, _ string,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	timestamp, err := p.GetLastUpdateTimestamp(ctx, resourcesTableName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.fillObjectsBundle(ctx, resourcesTableName, createObjFunc, intoBundle, fmt.Sprintf(
+		"SELECT id,payload,deleted FROM spec.%s WHERE %s AND %s AND %s", resourcesTableName, gvkCondition,
+		globalLabelFilter, rolloutFilter), p.gvk.Group, p.gvk.Version, p.gvk.Kind); err != nil {
+		return nil, err
+	}
+
+	return timestamp, nil
+}
+
+// GetUpdatedObjectsBundle returns a bundle of the objects of the type that were updated or deleted after the given
+// timestamp.
+func (p *globalResourcePostgreSQL) GetUpdatedObjectsBundle(ctx context.Context, _ string,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle, timestamp *time.Time,
+) (*time.Time, error) {
+	lastUpdateTimestamp, err := p.GetLastUpdateTimestamp(ctx, resourcesTableName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.fillObjectsBundle(ctx, resourcesTableName, createObjFunc, intoBundle, fmt.Sprintf(
+		"SELECT id,payload,deleted FROM spec.%s WHERE %s AND %s AND %s AND updated_at > $4", resourcesTableName,
+		gvkCondition, globalLabelFilter, rolloutFilter), p.gvk.Group, p.gvk.Version, p.gvk.Kind,
+		timestamp); err != nil {
+		return nil, err
+	}
+
+	return lastUpdateTimestamp, nil
+}
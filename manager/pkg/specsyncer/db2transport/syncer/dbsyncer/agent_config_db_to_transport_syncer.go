@@ -0,0 +1,89 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const leafHubAgentConfigsDBTableName = "leaf_hub_agent_configs"
+
+// AddAgentConfigDBToTransportSyncer adds the effective agent configs db to transport syncer to the manager.
+func AddAgentConfigDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
+	specSyncInterval, fullResyncInterval time.Duration,
+) error {
+	syncState := newBundleSyncState(fullResyncInterval)
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-agentconfig"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncAgentConfigBundles(ctx, producer, constants.AgentConfigMsgKey, specDB, syncState)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add agent config db to transport syncer - %w", err)
+	}
+
+	return nil
+}
+
+// syncAgentConfigBundles sends the agent config of every leaf hub whose config has changed to the leaf hub, the configs
+// of all the leaf hubs are resent periodically so that the restarted agents will converge. it returns true if any
+// bundle was committed to transport, otherwise false.
+func syncAgentConfigBundles(ctx context.Context, producer transport.Producer, transportBundleKey string,
+	specDB db.SpecDB, syncState *bundleSyncState,
+) (bool, error) {
+	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, leafHubAgentConfigsDBTableName, false)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	fullResync := time.Since(syncState.lastFullSyncTime) >= syncState.fullResyncInterval
+
+	// sync only if something has changed or a complete resync is due
+	if !lastUpdateTimestamp.After(syncState.lastSyncTimestamp) && !fullResync {
+		return false, nil
+	}
+
+	updatedAfter := &syncState.lastSyncTimestamp
+	if fullResync {
+		updatedAfter = &time.Time{}
+	}
+	leafHubToAgentConfigBundleMap, err := specDB.GetUpdatedLeafHubAgentConfigs(ctx, updatedAfter)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	// sync bundle per leaf hub
+	for leafHubName, agentConfigBundle := range leafHubToAgentConfigBundleMap {
+		payloadBytes, err := json.Marshal(agentConfigBundle)
+		if err != nil {
+			return false, fmt.Errorf("failed to sync marshal bundle(%s)", transportBundleKey)
+		}
+		if err := producer.Send(ctx, &transport.Message{
+			Destination: leafHubName,
+			ID:          transportBundleKey,
+			MsgType:     constants.SpecBundle,
+			Version:     lastUpdateTimestamp.Format(timeFormat),
+			Payload:     payloadBytes,
+		}); err != nil {
+			return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				transportBundleKey, leafHubAgentConfigsDBTableName, leafHubName, err)
+		}
+	}
+
+	syncState.lastSyncTimestamp = *lastUpdateTimestamp
+	if fullResync {
+		syncState.lastFullSyncTime = time.Now()
+	}
+
+	return len(leafHubToAgentConfigBundleMap) > 0, nil
+}
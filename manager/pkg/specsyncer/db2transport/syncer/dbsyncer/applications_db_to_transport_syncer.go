@@ -22,17 +22,17 @@ const (
 
 // AddApplicationsDBToTransportSyncer adds applications db to transport syncer to the manager.
 func AddApplicationsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
+	specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &applicationv1beta1.Application{} }
-	lastSyncTimestampPtr := &time.Time{}
+	syncState := newBundleSyncState(fullResyncInterval)
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-application"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, applicationsMsgKey, specDB, applicationsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, syncState)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add applications db to transport syncer - %w", err)
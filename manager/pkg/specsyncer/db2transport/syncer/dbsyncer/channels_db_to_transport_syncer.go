@@ -22,17 +22,17 @@ const (
 
 // AddChannelsDBToTransportSyncer adds channels db to transport syncer to the manager.
 func AddChannelsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
+	specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &channelv1.Channel{} }
-	lastSyncTimestampPtr := &time.Time{}
+	syncState := newBundleSyncState(fullResyncInterval)
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-channels"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, channelsMsgKey, specDB, channelsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, syncState)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add channels db to transport syncer - %w", err)
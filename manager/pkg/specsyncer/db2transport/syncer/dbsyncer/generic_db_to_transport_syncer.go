@@ -78,28 +78,68 @@ func (syncer *genericDBToTransportSyncer) periodicSync(ctx context.Context) {
 	}
 }
 
-const timeFormat = "2006-01-02_15-04-05.000000"
+const (
+	timeFormat = "2006-01-02_15-04-05.000000"
+	// deltaBundleKeySuffix is appended to the transport key of the delta bundles, so that the compaction of the topic
+	// never replaces the complete bundle with a delta bundle.
+	deltaBundleKeySuffix = "@delta"
+	// defaultFullResyncInterval is the default interval of sending the complete bundles.
+	defaultFullResyncInterval = 5 * time.Minute
+)
+
+// bundleSyncState holds the state of the bundles sent to transport by an objects syncer.
+type bundleSyncState struct {
+	// lastSyncTimestamp is the version of the last bundle committed to transport.
+	lastSyncTimestamp time.Time
+	// lastFullSyncTime is the time the last complete bundle was committed to transport.
+	lastFullSyncTime time.Time
+	// deltaSentSinceFullSync is set once a delta bundle was sent after the last complete bundle.
+	deltaSentSinceFullSync bool
+	// fullResyncInterval is the interval to send a complete bundle after delta bundles were sent. the leaf hubs
+	// drop the delta bundles which aren't based on the last bundle they synced, so they converge on the next
+	// complete bundle.
+	fullResyncInterval time.Duration
+}
+
+// newBundleSyncState returns the sync state of a syncer, the default full resync interval is used if the interval
+// isn't positive.
+func newBundleSyncState(fullResyncInterval time.Duration) *bundleSyncState {
+	if fullResyncInterval <= 0 {
+		fullResyncInterval = defaultFullResyncInterval
+	}
+	return &bundleSyncState{fullResyncInterval: fullResyncInterval}
+}
 
 // syncObjectsBundle performs the actual sync logic and returns true if bundle was committed to transport,
-// otherwise false.
+// otherwise false. the first bundle and the periodic resyncs are complete bundles, in between only the objects
+// which were updated or deleted since the last bundle are sent as delta bundles.
 func syncObjectsBundle(ctx context.Context, producer transport.Producer, transportBundleKey string,
 	specDB db.SpecDB, dbTableName string, createObjFunc bundle.CreateObjectFunction,
-	createBundleFunc bundle.CreateBundleFunction, lastSyncTimestampPtr *time.Time,
+	createBundleFunc bundle.CreateBundleFunction, syncState *bundleSyncState,
 ) (bool, error) {
 	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, dbTableName, true) // filter local resources
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
-	if !lastUpdateTimestamp.After(*lastSyncTimestampPtr) { // sync only if something has changed
+	fullResync := syncState.lastFullSyncTime.IsZero() ||
+		(syncState.deltaSentSinceFullSync && time.Since(syncState.lastFullSyncTime) >= syncState.fullResyncInterval)
+
+	// sync only if something has changed or a complete bundle is due
+	if !lastUpdateTimestamp.After(syncState.lastSyncTimestamp) && !fullResync {
 		return false, nil
 	}
 
-	// if we got here, then the last update timestamp from db is after what we have in memory.
-	// this means something has changed in db, syncing all the objects to transport.
 	bundleResult := createBundleFunc()
-	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
-
+	if fullResync {
+		lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	} else {
+		// if we got here, then the last update timestamp from db is after what we have in memory.
+		// this means something has changed in db, syncing only the changed objects to transport.
+		bundleResult.SetBaseVersion(syncState.lastSyncTimestamp.Format(timeFormat))
+		lastUpdateTimestamp, err = specDB.GetUpdatedObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult,
+			&syncState.lastSyncTimestamp)
+	}
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
@@ -109,8 +149,13 @@ func syncObjectsBundle(ctx context.Context, producer transport.Producer, transpo
 	if err != nil {
 		return false, fmt.Errorf("failed to sync marshal bundle(%s)", transportBundleKey)
 	}
+	messageKey := transportBundleKey
+	if !fullResync {
+		messageKey = transportBundleKey + deltaBundleKeySuffix
+	}
 	if err := producer.Send(ctx, &transport.Message{
 		Destination: transport.Broadcast,
+		Key:         messageKey,
 		ID:          transportBundleKey,
 		MsgType:     constants.SpecBundle,
 		Version:     lastUpdateTimestamp.Format(timeFormat),
@@ -120,7 +165,12 @@ func syncObjectsBundle(ctx context.Context, producer transport.Producer, transpo
 			transportBundleKey, dbTableName, transport.Broadcast, err)
 	}
 
-	// updating value to retain same ptr between calls
-	*lastSyncTimestampPtr = *lastUpdateTimestamp
+	syncState.lastSyncTimestamp = *lastUpdateTimestamp
+	if fullResync {
+		syncState.lastFullSyncTime = time.Now()
+		syncState.deltaSentSinceFullSync = false
+	} else {
+		syncState.deltaSentSinceFullSync = true
+	}
 	return true, nil
 }
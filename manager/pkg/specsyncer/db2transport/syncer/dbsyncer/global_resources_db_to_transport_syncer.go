@@ -0,0 +1,89 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	globalResourcesTableName = "resources"
+	globalResourcesMsgKey    = "GlobalResources"
+)
+
+// AddGlobalResourcesDBToTransportSyncer adds the db to transport syncer of the resources registered by the
+// GlobalResourceTypes to the manager, the resources of each kind are sent in a bundle of their own.
+func AddGlobalResourcesDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
+	specSyncInterval, fullResyncInterval time.Duration,
+) error {
+	syncStates := map[schema.GroupVersionKind]*bundleSyncState{}
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-globalresources"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncGlobalResourcesBundles(ctx, producer, specDB, syncStates, fullResyncInterval)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add global resources db to transport syncer - %w", err)
+	}
+
+	return nil
+}
+
+// syncGlobalResourcesBundles syncs the bundle of every kind in the resources table, it returns true if any bundle
+// was committed to transport, otherwise false.
+func syncGlobalResourcesBundles(ctx context.Context, producer transport.Producer, specDB db.SpecDB,
+	syncStates map[schema.GroupVersionKind]*bundleSyncState, fullResyncInterval time.Duration,
+) (bool, error) {
+	gvks, err := specDB.GetGlobalResourceTypes(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundles - %w", err)
+	}
+
+	synced := false
+	var errs []error
+	for _, gvk := range gvks {
+		syncState, found := syncStates[gvk]
+		if !found {
+			syncState = newBundleSyncState(fullResyncInterval)
+			syncStates[gvk] = syncState
+		}
+
+		kind := gvk
+		createObjFunc := func() metav1.Object {
+			object := &unstructured.Unstructured{}
+			object.SetGroupVersionKind(kind)
+			return object
+		}
+
+		bundleSynced, err := syncObjectsBundle(ctx, producer, globalResourcesMsgKeyOf(gvk),
+			specDB.GlobalResourceSpecDB(gvk), globalResourcesTableName, createObjFunc, bundle.NewBaseObjectsBundle,
+			syncState)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		synced = synced || bundleSynced
+	}
+
+	return synced, utilerrors.NewAggregate(errs)
+}
+
+// globalResourcesMsgKeyOf returns the message key of the bundle of the given kind, e.g. GlobalResources.ConfigMap.v1
+// or GlobalResources.Route.v1.route.openshift.io.
+func globalResourcesMsgKeyOf(gvk schema.GroupVersionKind) string {
+	return strings.TrimSuffix(fmt.Sprintf("%s.%s.%s.%s", globalResourcesMsgKey, gvk.Kind, gvk.Version, gvk.Group),
+		".")
+}
@@ -23,17 +23,17 @@ const (
 // AddHoHConfigDBToTransportSyncer adds hub-of-hubs config db to transport syncer to the manager.
 // the config is synced by addon manifests
 func AddHoHConfigDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
+	specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &corev1.ConfigMap{} }
-	lastSyncTimestampPtr := &time.Time{}
+	syncState := newBundleSyncState(fullResyncInterval)
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-configmap"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, configMsgKey, specDB, configTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, syncState)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add config db to transport syncer - %w", err)
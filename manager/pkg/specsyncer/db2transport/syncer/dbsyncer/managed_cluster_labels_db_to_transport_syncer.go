@@ -18,7 +18,7 @@ const managedClusterLabelsDBTableName = "managed_clusters_labels"
 
 // AddManagedClusterLabelsDBToTransportSyncer adds managed-cluster labels db to transport syncer to the manager.
 func AddManagedClusterLabelsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
+	specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	lastSyncTimestampPtr := &time.Time{}
 
@@ -23,19 +23,19 @@ const (
 // AddManagedClusterSetBindingsDBToTransportSyncer adds managed-cluster-set-bindings db to transport syncer to the
 // manager.
 func AddManagedClusterSetBindingsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB,
-	producer transport.Producer, specSyncInterval time.Duration,
+	producer transport.Producer, specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object {
 		return &clusterv1beta2.ManagedClusterSetBinding{}
 	}
-	lastSyncTimestampPtr := &time.Time{}
+	syncState := newBundleSyncState(fullResyncInterval)
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-managedclustersetbinding"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, managedClusterSetBindingsMsgKey, specDB,
-				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				managedClusterSetBindingsTableName, createObjFunc, bundle.NewBaseObjectsBundle, syncState)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add managed-cluster-set-bindings db to transport syncer - %w", err)
@@ -22,17 +22,17 @@ const (
 
 // AddManagedClusterSetsDBToTransportSyncer adds managed-cluster-sets db to transport syncer to the manager.
 func AddManagedClusterSetsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
+	specSyncInterval, fullResyncInterval time.Duration,
 ) error {
 	createObjFunc := func() metav1.Object { return &clusterv1beta2.ManagedClusterSet{} }
-	lastSyncTimestampPtr := &time.Time{}
+	syncState := newBundleSyncState(fullResyncInterval)
 
 	if err := mgr.Add(&genericDBToTransportSyncer{
 		log:            ctrl.Log.WithName("db-to-transport-syncer-managedclusterset"),
 		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
 		syncBundleFunc: func(ctx context.Context) (bool, error) {
 			return syncObjectsBundle(ctx, producer, managedClusterSetsMsgKey, specDB, managedClusterSetsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+				createObjFunc, bundle.NewBaseObjectsBundle, syncState)
 		},
 	}); err != nil {
 		return fmt.Errorf("failed to add managed-cluster-sets db to transport syncer - %w", err)
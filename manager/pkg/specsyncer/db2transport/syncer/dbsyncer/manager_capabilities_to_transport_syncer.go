@@ -0,0 +1,65 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// AddManagerCapabilitiesToTransportSyncer adds the syncer which broadcasts the capabilities of the manager to the leaf
+// hubs, the agents send the status bundles with the newest schema version the manager decodes.
+func AddManagerCapabilitiesToTransportSyncer(mgr ctrl.Manager, _ db.SpecDB, producer transport.Producer,
+	specSyncInterval, fullResyncInterval time.Duration,
+) error {
+	syncState := newBundleSyncState(fullResyncInterval)
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-managercapabilities"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncManagerCapabilities(ctx, producer, constants.ManagerCapabilitiesMsgKey, syncState)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add manager capabilities to transport syncer - %w", err)
+	}
+
+	return nil
+}
+
+// syncManagerCapabilities broadcasts the capabilities of the manager once the manager starts, and resends them
+// periodically so that the restarted and the joined agents will learn them. it returns true if the capabilities were
+// committed to transport, otherwise false.
+func syncManagerCapabilities(ctx context.Context, producer transport.Producer, transportBundleKey string,
+	syncState *bundleSyncState,
+) (bool, error) {
+	if time.Since(syncState.lastFullSyncTime) < syncState.fullResyncInterval {
+		return false, nil
+	}
+
+	payloadBytes, err := json.Marshal(status.NewCapabilities())
+	if err != nil {
+		return false, fmt.Errorf("failed to sync marshal bundle(%s)", transportBundleKey)
+	}
+
+	now := time.Now()
+	if err := producer.Send(ctx, &transport.Message{
+		ID:      transportBundleKey,
+		MsgType: constants.SpecBundle,
+		Version: now.Format(timeFormat),
+		Payload: payloadBytes,
+	}); err != nil {
+		return false, fmt.Errorf("failed to sync message(%s) - %w", transportBundleKey, err)
+	}
+
+	syncState.lastFullSyncTime = now
+	return true, nil
+}
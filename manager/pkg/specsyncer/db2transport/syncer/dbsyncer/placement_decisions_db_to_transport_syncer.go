@@ -0,0 +1,90 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const leafHubPlacementDecisionsDBTableName = "leaf_hub_placement_decisions"
+
+// AddPlacementDecisionsDBToTransportSyncer adds the global placement decisions db to transport syncer to the manager.
+func AddPlacementDecisionsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
+	specSyncInterval, fullResyncInterval time.Duration,
+) error {
+	syncState := newBundleSyncState(fullResyncInterval)
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-placementdecisions"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncPlacementDecisionsBundles(ctx, producer, constants.GlobalPlacementDecisionsMsgKey, specDB,
+				syncState)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add placement decisions db to transport syncer - %w", err)
+	}
+
+	return nil
+}
+
+// syncPlacementDecisionsBundles sends the global decisions of every leaf hub whose decisions have changed to the leaf
+// hub, the decisions of all the leaf hubs are resent periodically so that the placements created on the leaf hubs
+// after the decisions will get them. it returns true if any bundle was committed to transport, otherwise false.
+func syncPlacementDecisionsBundles(ctx context.Context, producer transport.Producer, transportBundleKey string,
+	specDB db.SpecDB, syncState *bundleSyncState,
+) (bool, error) {
+	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, leafHubPlacementDecisionsDBTableName, false)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	fullResync := time.Since(syncState.lastFullSyncTime) >= syncState.fullResyncInterval
+
+	// sync only if something has changed or a complete resync is due
+	if !lastUpdateTimestamp.After(syncState.lastSyncTimestamp) && !fullResync {
+		return false, nil
+	}
+
+	updatedAfter := &syncState.lastSyncTimestamp
+	if fullResync {
+		updatedAfter = &time.Time{}
+	}
+	leafHubToDecisionsBundleMap, err := specDB.GetUpdatedLeafHubPlacementDecisions(ctx, updatedAfter)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	// sync bundle per leaf hub
+	for leafHubName, decisionsBundle := range leafHubToDecisionsBundleMap {
+		payloadBytes, err := json.Marshal(decisionsBundle)
+		if err != nil {
+			return false, fmt.Errorf("failed to sync marshal bundle(%s)", transportBundleKey)
+		}
+		if err := producer.Send(ctx, &transport.Message{
+			Destination: leafHubName,
+			ID:          transportBundleKey,
+			MsgType:     constants.SpecBundle,
+			Version:     lastUpdateTimestamp.Format(timeFormat),
+			Payload:     payloadBytes,
+		}); err != nil {
+			return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				transportBundleKey, leafHubPlacementDecisionsDBTableName, leafHubName, err)
+		}
+	}
+
+	syncState.lastSyncTimestamp = *lastUpdateTimestamp
+	if fullResync {
+		syncState.lastFullSyncTime = time.Now()
+	}
+
+	return len(leafHubToDecisionsBundleMap) > 0, nil
+}
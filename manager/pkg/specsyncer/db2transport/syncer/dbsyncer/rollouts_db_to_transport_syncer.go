@@ -0,0 +1,90 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const leafHubRolloutsDBTableName = "leaf_hub_rollouts"
+
+// AddRolloutsDBToTransportSyncer adds the db to transport syncer of the global resources rolled out in waves to the
+// manager.
+func AddRolloutsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
+	specSyncInterval, fullResyncInterval time.Duration,
+) error {
+	syncState := newBundleSyncState(fullResyncInterval)
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-rollouts"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncRolloutsBundles(ctx, producer, constants.RolloutsMsgKey, specDB, syncState)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add rollouts db to transport syncer - %w", err)
+	}
+
+	return nil
+}
+
+// syncRolloutsBundles sends the resources rolled out to every leaf hub whose resources have changed to the leaf hub,
+// the bundles of all the leaf hubs are resent periodically so that the leaf hubs which missed a bundle will converge.
+// it returns true if any bundle was committed to transport, otherwise false.
+func syncRolloutsBundles(ctx context.Context, producer transport.Producer, transportBundleKey string,
+	specDB db.SpecDB, syncState *bundleSyncState,
+) (bool, error) {
+	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, leafHubRolloutsDBTableName, false)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	fullResync := time.Since(syncState.lastFullSyncTime) >= syncState.fullResyncInterval
+
+	// sync only if something has changed or a complete resync is due
+	if !lastUpdateTimestamp.After(syncState.lastSyncTimestamp) && !fullResync {
+		return false, nil
+	}
+
+	updatedAfter := &syncState.lastSyncTimestamp
+	if fullResync {
+		updatedAfter = &time.Time{}
+	}
+	leafHubToRolloutsBundleMap, err := specDB.GetUpdatedLeafHubRollouts(ctx, updatedAfter)
+	if err != nil {
+		return false, fmt.Errorf("unable to sync bundle - %w", err)
+	}
+
+	// sync bundle per leaf hub
+	for leafHubName, rolloutsBundle := range leafHubToRolloutsBundleMap {
+		payloadBytes, err := json.Marshal(rolloutsBundle)
+		if err != nil {
+			return false, fmt.Errorf("failed to sync marshal bundle(%s)", transportBundleKey)
+		}
+		if err := producer.Send(ctx, &transport.Message{
+			Destination: leafHubName,
+			ID:          transportBundleKey,
+			MsgType:     constants.SpecBundle,
+			Version:     lastUpdateTimestamp.Format(timeFormat),
+			Payload:     payloadBytes,
+		}); err != nil {
+			return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				transportBundleKey, leafHubRolloutsDBTableName, leafHubName, err)
+		}
+	}
+
+	syncState.lastSyncTimestamp = *lastUpdateTimestamp
+	if fullResync {
+		syncState.lastFullSyncTime = time.Now()
+	}
+
+	return len(leafHubToRolloutsBundleMap) > 0, nil
+}
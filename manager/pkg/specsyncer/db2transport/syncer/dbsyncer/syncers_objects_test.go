@@ -104,6 +104,30 @@ var (
 }`, policyUID))
 )
 
+var (
+	deltaPolicyUID       = uuid.New().String()
+	deltaPolicyJSONBytes = []byte(fmt.Sprintf(`{
+"apiVersion": "policy.open-cluster-management.io/v1",
+"kind": "Policy",
+"metadata": {
+"name": "test-policy-2",
+"namespace": "default",
+"creationTimestamp": null,
+"annotations": {
+"global-hub.open-cluster-management.io/origin-ownerreference-uid": "%s"
+},
+"labels": {
+"global-hub.open-cluster-management.io/global-resource": ""
+}
+},
+"spec": {
+"disabled": false,
+"policy-templates": []
+},
+"status": {}
+}`, deltaPolicyUID))
+)
+
 var (
 	placementruleUID       = uuid.New().String()
 	placementruleJSONBytes = []byte(fmt.Sprintf(`{
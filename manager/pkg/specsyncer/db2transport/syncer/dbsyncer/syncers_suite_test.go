@@ -112,7 +112,8 @@ var _ = BeforeSuite(func() {
 
 	managerConfig := &config.ManagerConfig{
 		SyncerConfig: &config.SyncerConfig{
-			SpecSyncInterval: 1 * time.Second,
+			SpecSyncInterval:       1 * time.Second,
+			SpecFullResyncInterval: 3 * time.Second,
 		},
 		DatabaseConfig: dataConfig,
 		TransportConfig: &transport.TransportConfig{
@@ -4,16 +4,21 @@
 package dbsyncer_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 var _ = Describe("Database to Transport Syncer", Ordered, func() {
+	// policiesVersion is the version of the last policies bundle
+	var policiesVersion string
+
 	BeforeAll(func() {
 		By("Create config table in spec schema")
 		_, err := transportPostgreSQL.GetConn().Exec(ctx, `
@@ -192,7 +197,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received ManagedClustersLabels: %s\n", message)
+		fmt.Printf("========== received ManagedClustersLabels: %s\n", message.Payload)
 		Expect(message.ID).Should(Equal("ManagedClustersLabels"))
 	})
 
@@ -204,7 +209,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received ManagedClusterSets: %s\n", message)
+		fmt.Printf("========== received ManagedClusterSets: %s\n", message.Payload)
 		Expect(message.ID).Should(Equal("ManagedClusterSets"))
 		Expect(message.Payload).Should(ContainSubstring(managedclustersetUID))
 	})
@@ -217,7 +222,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received ManagedClusterSetBindings: %s\n", message)
+		fmt.Printf("========== received ManagedClusterSetBindings: %s\n", message.Payload)
 		Expect(message.ID).Should(Equal("ManagedClusterSetBindings"))
 		Expect(message.Payload).Should(ContainSubstring(managedclustersetbindingUID))
 	})
@@ -230,9 +235,43 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received policy: %s\n", message)
+		fmt.Printf("========== received policy: %s\n", message.Payload)
 		Expect(message.ID).Should(Equal("Policies"))
 		Expect(message.Payload).Should(ContainSubstring(policyUID))
+		policiesVersion = message.Version
+	})
+
+	It("Test policy delta bundle is based on the last bundle and followed by a complete bundle", func() {
+		By("create another policy")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx,
+			"INSERT INTO spec.policies (id,payload) VALUES($1, $2)",
+			deltaPolicyUID, &deltaPolicyJSONBytes)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("receive the delta bundle keyed apart from the complete bundle")
+		message := waitForChannel(genericConsumer.MessageChan())
+		Expect(message).NotTo(BeNil())
+		Expect(message.ID).Should(Equal("Policies"))
+		Expect(message.Key).Should(Equal("Policies@delta"))
+		deltaBundle := &bundle.GenericBundle{}
+		Expect(json.Unmarshal(message.Payload, deltaBundle)).Should(Succeed())
+		Expect(deltaBundle.IsDelta()).Should(BeTrue())
+		Expect(deltaBundle.BaseVersion).Should(Equal(policiesVersion))
+		Expect(message.Version > policiesVersion).Should(BeTrue())
+		Expect(deltaBundle.Objects).Should(HaveLen(1))
+		Expect(deltaBundle.Objects[0].GetName()).Should(Equal("test-policy-2"))
+		deltaVersion := message.Version
+
+		By("receive the complete bundle after the full resync interval")
+		message = waitForChannel(genericConsumer.MessageChan())
+		Expect(message).NotTo(BeNil())
+		Expect(message.ID).Should(Equal("Policies"))
+		Expect(message.Key).Should(Equal("Policies"))
+		completeBundle := &bundle.GenericBundle{}
+		Expect(json.Unmarshal(message.Payload, completeBundle)).Should(Succeed())
+		Expect(completeBundle.IsDelta()).Should(BeFalse())
+		Expect(message.Version).Should(Equal(deltaVersion))
+		Expect(completeBundle.Objects).Should(HaveLen(2))
 	})
 
 	It("Test placementrule can be synced through transport", func() {
@@ -243,7 +282,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received placementrule: %s\n", message)
+		fmt.Printf("========== received placementrule: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(placementruleUID))
 	})
 
@@ -255,7 +294,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received placementbinding: %s\n", message)
+		fmt.Printf("========== received placementbinding: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(placementbindingUID))
 	})
 
@@ -267,7 +306,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received placement: %s\n", message)
+		fmt.Printf("========== received placement: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(placementUID))
 	})
 
@@ -279,7 +318,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received application: %s\n", message)
+		fmt.Printf("========== received application: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(applicationUID))
 	})
 
@@ -291,7 +330,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received subscription: %s\n", message)
+		fmt.Printf("========== received subscription: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(subscriptionUID))
 	})
 
@@ -303,7 +342,7 @@ var _ = Describe("Database to Transport Syncer", Ordered, func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		message := waitForChannel(genericConsumer.MessageChan())
-		fmt.Printf("========== received channel: %s\n", message)
+		fmt.Printf("========== received channel: %s\n", message.Payload)
 		Expect(message.Payload).Should(ContainSubstring(channelUID))
 	})
 })
@@ -0,0 +1,314 @@
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+)
+
+// remainingWaveName is the name of the last wave, which holds the leaf hubs matching no wave of the policy.
+const remainingWaveName = "remaining"
+
+// wave is the leaf hubs of a wave of a rollout policy.
+type wave struct {
+	name         string
+	leafHubNames []string
+}
+
+// rolloutState is the progress of the rollout of a global resource, it's kept in the spec.rollouts table.
+type rolloutState struct {
+	ResourceID        string                         `gorm:"column:resource_id;primaryKey"`
+	SpecTable         string                         `gorm:"column:table_name"`
+	RolloutPolicy     string                         `gorm:"column:rollout_policy"`
+	ResourceUpdatedAt time.Time                      `gorm:"column:resource_updated_at"`
+	Wave              int                            `gorm:"column:wave"`
+	Phase             globalhubv1alpha3.RolloutPhase `gorm:"column:phase"`
+	Message           string                         `gorm:"column:message"`
+	// Baseline is the non-compliant clusters of the policy per leaf hub of the wave, before the wave started
+	Baseline         datatypes.JSON `gorm:"column:baseline"`
+	WaveStartedAt    time.Time      `gorm:"column:wave_started_at"`
+	WaveAppliedAt    *time.Time     `gorm:"column:wave_applied_at"`
+	LastTransitionAt time.Time      `gorm:"column:last_transition_at"`
+	// Payload is the version of the resource being rolled out
+	Payload datatypes.JSON `gorm:"column:payload"`
+	// StablePayload is the version of the resource rolled out to all the hubs last, it's sent to the hubs the
+	// rollout hasn't reached yet, it's empty if no version was rolled out completely
+	StablePayload datatypes.JSON `gorm:"column:stable_payload"`
+}
+
+func (rolloutState) TableName() string {
+	return "spec.rollouts"
+}
+
+// waveHealth is the health of the global resource on the leaf hubs, it's checked against the health gates.
+type waveHealth struct {
+	// applied are the leaf hubs which applied the resource since the wave started
+	applied map[string]bool
+	// applyErrors are the errors of the leaf hubs which failed to apply the resource since the wave started
+	applyErrors map[string]string
+	// nonCompliantClusters are the non-compliant clusters of the policy per leaf hub
+	nonCompliantClusters map[string]int
+	// failedDeployments are the clusters the subscription failed to deploy to per leaf hub
+	failedDeployments map[string]int
+}
+
+// resolveWaves returns the leaf hubs of every wave of the policy, a leaf hub is in the first wave it matches, and the
+// leaf hubs matching no wave are in the remaining wave after all the waves. the leaf hubs are given with the labels
+// of their managed clusters.
+func resolveWaves(policySpec *globalhubv1alpha3.RolloutPolicySpec, leafHubs map[string]map[string]string,
+) ([]*wave, error) {
+	leafHubNames := make([]string, 0, len(leafHubs))
+	for leafHubName := range leafHubs {
+		leafHubNames = append(leafHubNames, leafHubName)
+	}
+	sort.Strings(leafHubNames)
+
+	assigned := map[string]bool{}
+	waves := make([]*wave, 0, len(policySpec.Waves)+1)
+	for i := range policySpec.Waves {
+		rolloutWave := &policySpec.Waves[i]
+		current := &wave{name: rolloutWave.Name}
+		for _, leafHubName := range leafHubNames {
+			if assigned[leafHubName] {
+				continue
+			}
+			matched, err := matchWave(rolloutWave, leafHubName, leafHubs[leafHubName])
+			if err != nil {
+				return nil, fmt.Errorf("invalid wave %s: %w", rolloutWave.Name, err)
+			}
+			if matched {
+				assigned[leafHubName] = true
+				current.leafHubNames = append(current.leafHubNames, leafHubName)
+			}
+		}
+		waves = append(waves, current)
+	}
+
+	remaining := &wave{name: remainingWaveName}
+	for _, leafHubName := range leafHubNames {
+		if !assigned[leafHubName] {
+			remaining.leafHubNames = append(remaining.leafHubNames, leafHubName)
+		}
+	}
+	if len(remaining.leafHubNames) > 0 {
+		waves = append(waves, remaining)
+	}
+	return waves, nil
+}
+
+func matchWave(rolloutWave *globalhubv1alpha3.RolloutWave, leafHubName string, leafHubLabels map[string]string,
+) (bool, error) {
+	for _, name := range rolloutWave.LeafHubNames {
+		if name == leafHubName {
+			return true, nil
+		}
+	}
+	// an empty selector would match all the hubs, so only the selectors with requirements are considered
+	if rolloutWave.LeafHubSelector == nil || (len(rolloutWave.LeafHubSelector.MatchLabels) == 0 &&
+		len(rolloutWave.LeafHubSelector.MatchExpressions) == 0) {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(rolloutWave.LeafHubSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(leafHubLabels)), nil
+}
+
+// newRolloutState returns the state of the rollout of a new version of the resource. the stable version is the
+// version of the previous rollout if it's completed, otherwise the stable version of the previous rollout is kept, so
+// the hubs reached by an unfinished rollout go back to the stable version until the new rollout reaches them.
+func newRolloutState(object *rolloutObject, policyName string, previous *rolloutState) *rolloutState {
+	state := &rolloutState{
+		ResourceID:        object.ID,
+		SpecTable:         object.tableName,
+		RolloutPolicy:     policyName,
+		ResourceUpdatedAt: object.UpdatedAt,
+		Payload:           object.Payload,
+	}
+	if previous != nil {
+		state.StablePayload = previous.StablePayload
+		if previous.Phase == globalhubv1alpha3.RolloutCompleted {
+			state.StablePayload = previous.Payload
+		}
+	}
+	return state
+}
+
+// failRollout stops the rollout before it starts, the hubs keep the stable version. it returns true if the state has
+// changed.
+func failRollout(state *rolloutState, message string, now time.Time) bool {
+	if state.Phase == globalhubv1alpha3.RolloutFailed && state.Message == message {
+		return false
+	}
+	state.Wave = -1
+	state.WaveStartedAt = now
+	state.WaveAppliedAt = nil
+	setPhase(state, globalhubv1alpha3.RolloutFailed, message, now)
+	return true
+}
+
+// startRollout starts the rollout of the current version of the resource from the first wave.
+func startRollout(state *rolloutState, waves []*wave, health *waveHealth, now time.Time) {
+	state.Wave = -1
+	state.Phase = ""
+	startWave(state, 0, waves, health, now)
+}
+
+// startWave moves the rollout to the given wave, the empty waves are skipped, and the rollout is completed after the
+// last wave.
+func startWave(state *rolloutState, index int, waves []*wave, health *waveHealth, now time.Time) {
+	for index < len(waves) && len(waves[index].leafHubNames) == 0 {
+		index++
+	}
+	if index >= len(waves) {
+		setPhase(state, globalhubv1alpha3.RolloutCompleted, "rolled out to all the hubs", now)
+		return
+	}
+
+	baseline := map[string]int{}
+	for _, leafHubName := range waves[index].leafHubNames {
+		baseline[leafHubName] = health.nonCompliantClusters[leafHubName]
+	}
+	state.Baseline, _ = json.Marshal(baseline)
+	state.Wave = index
+	state.WaveStartedAt = now
+	state.WaveAppliedAt = nil
+	state.LastTransitionAt = now
+	setPhase(state, globalhubv1alpha3.RolloutProgressing, waitingMessage(waves[index].leafHubNames), now)
+}
+
+// advanceRollout moves the rollout through the waves according to the health of the resource on the leaf hubs of the
+// current wave. it returns true if the state has changed.
+func advanceRollout(state *rolloutState, policySpec *globalhubv1alpha3.RolloutPolicySpec, waves []*wave,
+	health *waveHealth, now time.Time,
+) bool {
+	switch state.Phase {
+	case globalhubv1alpha3.RolloutCompleted, globalhubv1alpha3.RolloutHalted, globalhubv1alpha3.RolloutFailed:
+		// the halted rollout is restarted once the resource is changed
+		return false
+	}
+
+	// the waves may have changed since the wave started, e.g. the hubs matching no wave are gone
+	if state.Wave >= len(waves) {
+		startWave(state, len(waves), waves, health, now)
+		return true
+	}
+	current := waves[state.Wave]
+	if len(current.leafHubNames) == 0 {
+		startWave(state, state.Wave+1, waves, health, now)
+		return true
+	}
+
+	for _, leafHubName := range current.leafHubNames {
+		if applyError, found := health.applyErrors[leafHubName]; found {
+			setPhase(state, globalhubv1alpha3.RolloutHalted,
+				fmt.Sprintf("failed to apply on hub %s: %s", leafHubName, applyError), now)
+			return true
+		}
+	}
+
+	switch state.Phase {
+	case globalhubv1alpha3.RolloutProgressing:
+		var waitingLeafHubs []string
+		for _, leafHubName := range current.leafHubNames {
+			if !health.applied[leafHubName] {
+				waitingLeafHubs = append(waitingLeafHubs, leafHubName)
+			}
+		}
+		if len(waitingLeafHubs) > 0 {
+			if now.Sub(state.WaveStartedAt) >= policySpec.HealthGates.GetTimeout() {
+				setPhase(state, globalhubv1alpha3.RolloutHalted,
+					fmt.Sprintf("timed out waiting for hubs: %s", strings.Join(waitingLeafHubs, ", ")), now)
+				return true
+			}
+			message := waitingMessage(waitingLeafHubs)
+			if state.Message == message {
+				return false
+			}
+			state.Message = message
+			return true
+		}
+
+		// the health gates of the last wave are checked during the pause too, before the rollout is completed
+		message := fmt.Sprintf("applied on %d hubs, pausing before completing", len(current.leafHubNames))
+		if state.Wave < len(waves)-1 {
+			message = fmt.Sprintf("applied on %d hubs, pausing before wave %s", len(current.leafHubNames),
+				waves[state.Wave+1].name)
+		}
+		appliedAt := now
+		state.WaveAppliedAt = &appliedAt
+		setPhase(state, globalhubv1alpha3.RolloutWaiting, message, now)
+		return true
+
+	case globalhubv1alpha3.RolloutWaiting:
+		if message := checkHealthGates(state, policySpec, current, health); message != "" {
+			setPhase(state, globalhubv1alpha3.RolloutHalted, message, now)
+			return true
+		}
+		if state.WaveAppliedAt == nil || now.Sub(*state.WaveAppliedAt) >= policySpec.GetPauseBetweenWaves() {
+			startWave(state, state.Wave+1, waves, health, now)
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkHealthGates returns the reason of halting the rollout if a health gate fails on a leaf hub of the wave,
+// otherwise an empty string.
+func checkHealthGates(state *rolloutState, policySpec *globalhubv1alpha3.RolloutPolicySpec, current *wave,
+	health *waveHealth,
+) string {
+	baseline := map[string]int{}
+	_ = json.Unmarshal(state.Baseline, &baseline)
+
+	for _, leafHubName := range current.leafHubNames {
+		if policySpec.HealthGates.NoComplianceRegression && state.SpecTable == policiesTableName &&
+			health.nonCompliantClusters[leafHubName] > baseline[leafHubName] {
+			return fmt.Sprintf("non-compliant clusters on hub %s increased from %d to %d", leafHubName,
+				baseline[leafHubName], health.nonCompliantClusters[leafHubName])
+		}
+		if policySpec.HealthGates.NoSubscriptionFailures && state.SpecTable == subscriptionsTableName &&
+			health.failedDeployments[leafHubName] > 0 {
+			return fmt.Sprintf("deployments failed on %d clusters of hub %s", health.failedDeployments[leafHubName],
+				leafHubName)
+		}
+	}
+	return ""
+}
+
+// splitLeafHubs returns the leaf hubs the current version of the resource is sent to, which are the leaf hubs of the
+// waves up to the current one, or all the leaf hubs once the rollout is completed, and the other leaf hubs, which get
+// the stable version.
+func splitLeafHubs(state *rolloutState, waves []*wave) ([]string, []string) {
+	var rolledOut, pending []string
+	for i, rolloutWave := range waves {
+		if i > state.Wave && state.Phase != globalhubv1alpha3.RolloutCompleted {
+			pending = append(pending, rolloutWave.leafHubNames...)
+			continue
+		}
+		rolledOut = append(rolledOut, rolloutWave.leafHubNames...)
+	}
+	return rolledOut, pending
+}
+
+func setPhase(state *rolloutState, phase globalhubv1alpha3.RolloutPhase, message string, now time.Time) {
+	if state.Phase != phase {
+		state.LastTransitionAt = now
+	}
+	state.Phase = phase
+	state.Message = message
+}
+
+func waitingMessage(leafHubNames []string) string {
+	return fmt.Sprintf("waiting for hubs: %s", strings.Join(leafHubNames, ", "))
+}
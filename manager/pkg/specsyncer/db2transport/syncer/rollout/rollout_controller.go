@@ -0,0 +1,447 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	policiesTableName      = "policies"
+	subscriptionsTableName = "subscriptions"
+
+	rolloutObjectsQuery = `SELECT id, payload, updated_at FROM spec.%s WHERE deleted = FALSE AND
+		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL AND
+		payload->'metadata'->'annotations'->'global-hub.open-cluster-management.io/rollout-policy' IS NOT NULL
+		ORDER BY id`
+	appliedStatusesQuery = `SELECT leaf_hub_name, applied, error FROM status.applied_statuses
+		WHERE resource_id = ? AND dry_run = FALSE AND updated_at >= ?`
+	nonCompliantClustersQuery = `SELECT leaf_hub_name, COUNT(*) AS count FROM status.compliance
+		WHERE policy_id = ? AND compliance = 'non_compliant' GROUP BY leaf_hub_name
+		UNION ALL SELECT leaf_hub_name, non_compliant_clusters AS count FROM status.aggregated_compliance
+		WHERE policy_id = ?`
+	// the subscription statuses are stored by the leaf hubs with the id of their global subscription
+	subscriptionStatusesQuery = `SELECT leaf_hub_name, payload FROM status.subscription_statuses WHERE id = ?`
+)
+
+// rolloutTableNames are the spec tables of the global resources which can be rolled out in waves.
+var rolloutTableNames = []string{
+	policiesTableName, "placementrules", "placementbindings", "applications", subscriptionsTableName, "channels",
+	"placements", "managedclustersets", "managedclustersetbindings", "resources",
+}
+
+// AddRolloutController adds the rollout controller to the manager, the global resources annotated with a rollout
+// policy are moved through the waves of the policy every interval.
+func AddRolloutController(mgr ctrl.Manager, specDB db.SpecDB, interval time.Duration) error {
+	if err := mgr.Add(&rolloutController{
+		log:      ctrl.Log.WithName("rollout-controller"),
+		client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("global-hub-rollout-controller"),
+		specDB:   specDB,
+		interval: interval,
+	}); err != nil {
+		return fmt.Errorf("failed to add rollout controller - %w", err)
+	}
+
+	return nil
+}
+
+// rolloutController sends the annotated global resources to the leaf hubs wave by wave, the progress is kept in the
+// spec.rollouts table and reported in the status of the rollout policies. the leaf hubs of the waves not reached yet
+// get the version rolled out to all the hubs last, a new resource isn't sent to them at all.
+type rolloutController struct {
+	log      logr.Logger
+	client   client.Client
+	recorder record.EventRecorder
+	specDB   db.SpecDB
+	interval time.Duration
+}
+
+// rolloutObject is an annotated global resource of a spec table.
+type rolloutObject struct {
+	ID        string
+	Payload   []byte
+	UpdatedAt time.Time
+	tableName string
+	object    *unstructured.Unstructured
+}
+
+// appliedStatus is the applied status of a global resource on a leaf hub.
+type appliedStatus struct {
+	LeafHubName string
+	Applied     bool
+	Error       *string
+}
+
+// leafHubCount is a count of a leaf hub.
+type leafHubCount struct {
+	LeafHubName string
+	Count       int
+}
+
+// subscriptionStatus is the subscription status of a cluster of a leaf hub.
+type subscriptionStatus struct {
+	LeafHubName string
+	Payload     []byte
+}
+
+func (c *rolloutController) Start(ctx context.Context) error {
+	c.log.Info("started rollout controller", "interval", c.interval)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Info("stopped rollout controller")
+			return nil
+		case <-ticker.C:
+			if err := c.rollout(ctx); err != nil {
+				c.log.Error(err, "failed to roll out the global resources")
+			}
+		}
+	}
+}
+
+func (c *rolloutController) rollout(ctx context.Context) error {
+	gormDB := database.GetGorm().WithContext(ctx)
+
+	rolloutPolicyList := &globalhubv1alpha3.RolloutPolicyList{}
+	if err := c.client.List(ctx, rolloutPolicyList); err != nil {
+		return fmt.Errorf("failed to list the rollout policies - %w", err)
+	}
+	leafHubs, leafHubNames, err := c.listLeafHubs(ctx)
+	if err != nil {
+		return err
+	}
+
+	policies := map[string]*globalhubv1alpha3.RolloutPolicy{}
+	policyWaves := map[string][]*wave{}
+	for i := range rolloutPolicyList.Items {
+		policy := &rolloutPolicyList.Items[i]
+		waves, err := resolveWaves(&policy.Spec, leafHubs)
+		if err != nil {
+			c.log.Error(err, "failed to resolve the waves of the rollout policy", "name", policy.GetName())
+			continue
+		}
+		policies[policy.GetName()] = policy
+		policyWaves[policy.GetName()] = waves
+	}
+
+	objects, err := listRolloutObjects(gormDB)
+	if err != nil {
+		return err
+	}
+
+	var states []*rolloutState
+	if err := gormDB.Find(&states).Error; err != nil {
+		return fmt.Errorf("failed to list the rollout states - %w", err)
+	}
+	stateMap := make(map[string]*rolloutState, len(states))
+	for _, state := range states {
+		stateMap[state.ResourceID] = state
+	}
+
+	now := time.Now().UTC()
+	leafHubObjects := map[string][]*unstructured.Unstructured{}
+	policyRollouts := map[string][]globalhubv1alpha3.ResourceRollout{}
+	objectIDs := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		objectIDs[object.ID] = true
+		policyName := object.object.GetAnnotations()[constants.RolloutPolicyAnnotation]
+		state, found := stateMap[object.ID]
+		policy, policyFound := policies[policyName]
+		if !policyFound {
+			state, err := c.reportMissingPolicy(gormDB, object, policyName, state, now)
+			if err != nil {
+				return err
+			}
+			if err := addStableObject(leafHubObjects, leafHubNames, state); err != nil {
+				return err
+			}
+			continue
+		}
+		waves := policyWaves[policyName]
+
+		health, err := getWaveHealth(gormDB, object, state)
+		if err != nil {
+			return err
+		}
+
+		if !found || !state.ResourceUpdatedAt.Equal(object.UpdatedAt) || state.RolloutPolicy != policyName ||
+			state.Phase == globalhubv1alpha3.RolloutFailed {
+			state = newRolloutState(object, policyName, state)
+			// the applied statuses of the previous version don't count for the new rollout
+			health.applied, health.applyErrors = map[string]bool{}, map[string]string{}
+			startRollout(state, waves, health, now)
+			if err := gormDB.Save(state).Error; err != nil {
+				return fmt.Errorf("failed to save the rollout state of resource %s - %w", object.ID, err)
+			}
+		} else if advanceRollout(state, &policy.Spec, waves, health, now) {
+			if err := gormDB.Save(state).Error; err != nil {
+				return fmt.Errorf("failed to save the rollout state of resource %s - %w", object.ID, err)
+			}
+		}
+
+		rolledOut, pending := splitLeafHubs(state, waves)
+		for _, leafHubName := range rolledOut {
+			leafHubObjects[leafHubName] = append(leafHubObjects[leafHubName], object.object)
+		}
+		if err := addStableObject(leafHubObjects, pending, state); err != nil {
+			return err
+		}
+
+		resourceRollout := globalhubv1alpha3.ResourceRollout{
+			Kind:               object.object.GetKind(),
+			Name:               object.object.GetName(),
+			Namespace:          object.object.GetNamespace(),
+			Phase:              state.Phase,
+			Message:            state.Message,
+			LastTransitionTime: metav1.NewTime(state.LastTransitionAt.Truncate(time.Second)),
+		}
+		if state.Wave >= 0 && state.Wave < len(waves) {
+			resourceRollout.Wave = waves[state.Wave].name
+		}
+		policyRollouts[policyName] = append(policyRollouts[policyName], resourceRollout)
+	}
+
+	// the states of the resources which aren't rolled out anymore are removed
+	for _, state := range states {
+		if objectIDs[state.ResourceID] {
+			continue
+		}
+		if err := gormDB.Delete(state).Error; err != nil {
+			return fmt.Errorf("failed to delete the rollout state of resource %s - %w", state.ResourceID, err)
+		}
+	}
+
+	// every leaf hub gets its resources, the objects are already sorted by id
+	for _, leafHubName := range leafHubNames {
+		if err := c.specDB.UpsertLeafHubRollouts(ctx, &spec.RolloutsSpecBundle{
+			LeafHubName: leafHubName,
+			Objects:     leafHubObjects[leafHubName],
+		}); err != nil {
+			return err
+		}
+	}
+	if err := c.specDB.DeleteLeafHubRollouts(ctx, leafHubNames); err != nil {
+		return err
+	}
+
+	for name, policy := range policies {
+		if err := c.updatePolicyStatus(ctx, policy, len(policyWaves[name]), policyRollouts[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportMissingPolicy fails the rollout of the resource since its rollout policy isn't found, the error is reported
+// as a warning event of the resource once the rollout fails. it returns the state of the failed rollout.
+func (c *rolloutController) reportMissingPolicy(gormDB *gorm.DB, object *rolloutObject, policyName string,
+	state *rolloutState, now time.Time,
+) (*rolloutState, error) {
+	if state == nil || !state.ResourceUpdatedAt.Equal(object.UpdatedAt) || state.RolloutPolicy != policyName {
+		state = newRolloutState(object, policyName, state)
+	}
+	message := fmt.Sprintf("rollout policy %s isn't found", policyName)
+	if !failRollout(state, message, now) {
+		return state, nil
+	}
+	if err := gormDB.Save(state).Error; err != nil {
+		return nil, fmt.Errorf("failed to save the rollout state of resource %s - %w", object.ID, err)
+	}
+
+	// the event refers to the resource on the global hub, whose uid is the id of the resource
+	eventObject := object.object.DeepCopy()
+	eventObject.SetUID(types.UID(object.ID))
+	c.recorder.Event(eventObject, corev1.EventTypeWarning, "RolloutFailed", message)
+	c.log.Info("failed to roll out the global resource", "reason", message, "table", object.tableName,
+		"namespace", object.object.GetNamespace(), "name", object.object.GetName())
+	return state, nil
+}
+
+// addStableObject adds the stable version of the resource to the objects of the given leaf hubs, if a version was
+// rolled out to all the hubs before.
+func addStableObject(leafHubObjects map[string][]*unstructured.Unstructured,
+	leafHubNames []string, state *rolloutState,
+) error {
+	if state == nil || len(state.StablePayload) == 0 || len(leafHubNames) == 0 {
+		return nil
+	}
+	stableObject, err := unmarshalRolloutObject(state.ResourceID, state.StablePayload)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal the stable version of resource %s - %w", state.ResourceID, err)
+	}
+	for _, leafHubName := range leafHubNames {
+		leafHubObjects[leafHubName] = append(leafHubObjects[leafHubName], stableObject)
+	}
+	return nil
+}
+
+// listLeafHubs returns the labels of the leaf hubs, which are the managed clusters of the global hub except the local
+// cluster, and the sorted names of the leaf hubs.
+func (c *rolloutController) listLeafHubs(ctx context.Context) (map[string]map[string]string, []string, error) {
+	managedClusterList := &clusterv1.ManagedClusterList{}
+	if err := c.client.List(ctx, managedClusterList); err != nil {
+		return nil, nil, fmt.Errorf("failed to list managed clusters - %w", err)
+	}
+
+	leafHubs := make(map[string]map[string]string, len(managedClusterList.Items))
+	leafHubNames := make([]string, 0, len(managedClusterList.Items))
+	for _, managedCluster := range managedClusterList.Items {
+		if managedCluster.GetName() == constants.LocalClusterName {
+			continue
+		}
+		leafHubs[managedCluster.GetName()] = managedCluster.GetLabels()
+		leafHubNames = append(leafHubNames, managedCluster.GetName())
+	}
+	sort.Strings(leafHubNames)
+	return leafHubs, leafHubNames, nil
+}
+
+func (c *rolloutController) updatePolicyStatus(ctx context.Context, policy *globalhubv1alpha3.RolloutPolicy,
+	waves int, rollouts []globalhubv1alpha3.ResourceRollout,
+) error {
+	sort.Slice(rollouts, func(i, j int) bool {
+		if rollouts[i].Kind != rollouts[j].Kind {
+			return rollouts[i].Kind < rollouts[j].Kind
+		}
+		if rollouts[i].Namespace != rollouts[j].Namespace {
+			return rollouts[i].Namespace < rollouts[j].Namespace
+		}
+		return rollouts[i].Name < rollouts[j].Name
+	})
+	status := globalhubv1alpha3.RolloutPolicyStatus{Waves: waves, Rollouts: rollouts}
+	if equality.Semantic.DeepEqual(policy.Status, status) {
+		return nil
+	}
+
+	policy.Status = status
+	if err := c.client.Status().Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to update the status of rollout policy %s - %w", policy.GetName(), err)
+	}
+	return nil
+}
+
+// listRolloutObjects returns the global resources annotated with a rollout policy from all the spec tables.
+func listRolloutObjects(gormDB *gorm.DB) ([]*rolloutObject, error) {
+	var objects []*rolloutObject
+	for _, tableName := range rolloutTableNames {
+		var tableObjects []*rolloutObject
+		if err := gormDB.Raw(fmt.Sprintf(rolloutObjectsQuery, tableName)).Scan(&tableObjects).Error; err != nil {
+			return nil, fmt.Errorf("failed to list the objects from spec.%s - %w", tableName, err)
+		}
+		for _, object := range tableObjects {
+			object.tableName = tableName
+			var err error
+			if object.object, err = unmarshalRolloutObject(object.ID, object.Payload); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the object %s from spec.%s - %w", object.ID, tableName,
+					err)
+			}
+		}
+		objects = append(objects, tableObjects...)
+	}
+	return objects, nil
+}
+
+// unmarshalRolloutObject returns the object of a version of the resource the way it's sent to the leaf hubs.
+func unmarshalRolloutObject(id string, payload []byte) (*unstructured.Unstructured, error) {
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal(payload, object); err != nil {
+		return nil, err
+	}
+	object.SetUID("") // cleanup UID to avoid apply conflict in regional hub
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.OriginOwnerReferenceAnnotation] = id
+	object.SetAnnotations(annotations)
+	return object, nil
+}
+
+// getWaveHealth returns the health of the resource on the leaf hubs, the applied statuses are only considered since
+// the current wave started.
+func getWaveHealth(gormDB *gorm.DB, object *rolloutObject, state *rolloutState) (*waveHealth, error) {
+	health := &waveHealth{
+		applied:              map[string]bool{},
+		applyErrors:          map[string]string{},
+		nonCompliantClusters: map[string]int{},
+		failedDeployments:    map[string]int{},
+	}
+
+	if state != nil {
+		var appliedStatuses []appliedStatus
+		if err := gormDB.Raw(appliedStatusesQuery, object.ID, state.WaveStartedAt).
+			Scan(&appliedStatuses).Error; err != nil {
+			return nil, fmt.Errorf("failed to get the applied statuses of resource %s - %w", object.ID, err)
+		}
+		for _, status := range appliedStatuses {
+			if status.Applied {
+				health.applied[status.LeafHubName] = true
+			} else if status.Error != nil {
+				health.applyErrors[status.LeafHubName] = *status.Error
+			}
+		}
+	}
+
+	switch object.tableName {
+	case policiesTableName:
+		var counts []leafHubCount
+		if err := gormDB.Raw(nonCompliantClustersQuery, object.ID, object.ID).Scan(&counts).Error; err != nil {
+			return nil, fmt.Errorf("failed to get the compliance of policy %s - %w", object.ID, err)
+		}
+		for _, count := range counts {
+			health.nonCompliantClusters[count.LeafHubName] += count.Count
+		}
+	case subscriptionsTableName:
+		var statuses []subscriptionStatus
+		if err := gormDB.Raw(subscriptionStatusesQuery, object.ID).Scan(&statuses).Error; err != nil {
+			return nil, fmt.Errorf("failed to get the statuses of subscription %s - %w", object.ID, err)
+		}
+		for _, status := range statuses {
+			clusterStatus := &appsv1alpha1.SubscriptionStatus{}
+			if err := json.Unmarshal(status.Payload, clusterStatus); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the status of subscription %s - %w", object.ID, err)
+			}
+			if hasFailedDeployment(clusterStatus.Statuses.SubscriptionStatus) {
+				health.failedDeployments[status.LeafHubName]++
+			}
+		}
+	}
+	return health, nil
+}
+
+// hasFailedDeployment returns true if a package of the subscription failed to be deployed or propagated to the
+// cluster.
+func hasFailedDeployment(packages []appsv1alpha1.SubscriptionUnitStatus) bool {
+	for _, unitStatus := range packages {
+		if unitStatus.Phase == appsv1alpha1.PackageDeployFailed ||
+			unitStatus.Phase == appsv1alpha1.PackagePropagationFailed {
+			return true
+		}
+	}
+	return false
+}
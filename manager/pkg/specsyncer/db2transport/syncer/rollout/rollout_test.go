@@ -0,0 +1,247 @@
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func newHealth() *waveHealth {
+	return &waveHealth{
+		applied:              map[string]bool{},
+		applyErrors:          map[string]string{},
+		nonCompliantClusters: map[string]int{},
+		failedDeployments:    map[string]int{},
+	}
+}
+
+func TestResolveWaves(t *testing.T) {
+	policySpec := &globalhubv1alpha3.RolloutPolicySpec{
+		Waves: []globalhubv1alpha3.RolloutWave{
+			{Name: "canary", LeafHubNames: []string{"hub2"}},
+			{Name: "staging", LeafHubSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"env": "staging"},
+			}},
+			{Name: "empty", LeafHubSelector: &metav1.LabelSelector{}},
+		},
+	}
+	leafHubs := map[string]map[string]string{
+		"hub1": {"env": "staging"},
+		"hub2": {"env": "staging"},
+		"hub3": {"env": "prod"},
+		"hub4": {},
+	}
+
+	waves, err := resolveWaves(policySpec, leafHubs)
+	require.NoError(t, err)
+	assert.Equal(t, []*wave{
+		{name: "canary", leafHubNames: []string{"hub2"}},
+		{name: "staging", leafHubNames: []string{"hub1"}},
+		{name: "empty"},
+		{name: remainingWaveName, leafHubNames: []string{"hub3", "hub4"}},
+	}, waves)
+
+	policySpec.Waves[1].LeafHubSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{Key: "env", Operator: "Invalid"},
+	}
+	_, err = resolveWaves(policySpec, leafHubs)
+	assert.Error(t, err)
+}
+
+func TestAdvanceRollout(t *testing.T) {
+	policySpec := &globalhubv1alpha3.RolloutPolicySpec{
+		Waves:             []globalhubv1alpha3.RolloutWave{{Name: "canary"}, {Name: "prod"}},
+		PauseBetweenWaves: &metav1.Duration{Duration: 10 * time.Minute},
+		HealthGates: globalhubv1alpha3.RolloutHealthGates{
+			Timeout:                &metav1.Duration{Duration: 30 * time.Minute},
+			NoComplianceRegression: true,
+			NoSubscriptionFailures: true,
+		},
+	}
+	waves := []*wave{
+		{name: "canary", leafHubNames: []string{"hub1"}},
+		{name: "empty"},
+		{name: "prod", leafHubNames: []string{"hub2", "hub3"}},
+	}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rolls out wave by wave", func(t *testing.T) {
+		state := &rolloutState{SpecTable: policiesTableName}
+		health := newHealth()
+		startRollout(state, waves, health, now)
+		assert.Equal(t, 0, state.Wave)
+		assert.Equal(t, globalhubv1alpha3.RolloutProgressing, state.Phase)
+		rolledOut, pending := splitLeafHubs(state, waves)
+		assert.Equal(t, []string{"hub1"}, rolledOut)
+		assert.Equal(t, []string{"hub2", "hub3"}, pending)
+
+		assert.False(t, advanceRollout(state, policySpec, waves, health, now.Add(time.Minute)))
+
+		health.applied["hub1"] = true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(2*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutWaiting, state.Phase)
+
+		// the pause between the waves isn't over
+		assert.False(t, advanceRollout(state, policySpec, waves, health, now.Add(5*time.Minute)))
+
+		// the empty wave is skipped
+		health.nonCompliantClusters["hub2"] = 1
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(12*time.Minute)))
+		assert.Equal(t, 2, state.Wave)
+		assert.Equal(t, globalhubv1alpha3.RolloutProgressing, state.Phase)
+		assert.JSONEq(t, `{"hub2": 1, "hub3": 0}`, string(state.Baseline))
+		rolledOut, pending = splitLeafHubs(state, waves)
+		assert.Equal(t, []string{"hub1", "hub2", "hub3"}, rolledOut)
+		assert.Empty(t, pending)
+
+		health.applied["hub2"] = true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(13*time.Minute)))
+		assert.Equal(t, "waiting for hubs: hub3", state.Message)
+
+		// the health gates of the last wave are checked before the rollout is completed
+		health.applied["hub3"] = true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(14*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutWaiting, state.Phase)
+		assert.Equal(t, "applied on 2 hubs, pausing before completing", state.Message)
+		assert.False(t, advanceRollout(state, policySpec, waves, health, now.Add(20*time.Minute)))
+
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(24*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutCompleted, state.Phase)
+		assert.False(t, advanceRollout(state, policySpec, waves, health, now.Add(25*time.Minute)))
+	})
+
+	t.Run("halts on the health gates of the last wave", func(t *testing.T) {
+		state := &rolloutState{SpecTable: policiesTableName}
+		health := newHealth()
+		startRollout(state, waves, health, now)
+		health.applied["hub1"] = true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(time.Minute)))
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(12*time.Minute)))
+		assert.Equal(t, 2, state.Wave)
+
+		health.applied["hub2"], health.applied["hub3"] = true, true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(13*time.Minute)))
+		health.nonCompliantClusters["hub3"] = 1
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(14*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutHalted, state.Phase)
+		assert.Equal(t, "non-compliant clusters on hub hub3 increased from 0 to 1", state.Message)
+	})
+
+	t.Run("halts on compliance regression", func(t *testing.T) {
+		state := &rolloutState{SpecTable: policiesTableName}
+		health := newHealth()
+		health.nonCompliantClusters["hub1"] = 2
+		startRollout(state, waves, health, now)
+
+		health.applied["hub1"] = true
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(time.Minute)))
+		health.nonCompliantClusters["hub1"] = 3
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(2*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutHalted, state.Phase)
+		rolledOut, pending := splitLeafHubs(state, waves)
+		assert.Equal(t, []string{"hub1"}, rolledOut)
+		assert.Equal(t, []string{"hub2", "hub3"}, pending)
+
+		// the halted rollout stays halted
+		health.nonCompliantClusters["hub1"] = 0
+		assert.False(t, advanceRollout(state, policySpec, waves, health, now.Add(20*time.Minute)))
+	})
+
+	t.Run("halts on subscription failures", func(t *testing.T) {
+		state := &rolloutState{SpecTable: subscriptionsTableName}
+		health := newHealth()
+		startRollout(state, waves, health, now)
+
+		health.applied["hub1"] = true
+		health.failedDeployments["hub1"] = 1
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(time.Minute)))
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(2*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutHalted, state.Phase)
+	})
+
+	t.Run("halts on apply error", func(t *testing.T) {
+		state := &rolloutState{SpecTable: "placements"}
+		health := newHealth()
+		startRollout(state, waves, health, now)
+
+		health.applyErrors["hub1"] = "forbidden"
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutHalted, state.Phase)
+		assert.Equal(t, "failed to apply on hub hub1: forbidden", state.Message)
+	})
+
+	t.Run("halts on timeout", func(t *testing.T) {
+		state := &rolloutState{SpecTable: "placements"}
+		health := newHealth()
+		startRollout(state, waves, health, now)
+
+		assert.True(t, advanceRollout(state, policySpec, waves, health, now.Add(31*time.Minute)))
+		assert.Equal(t, globalhubv1alpha3.RolloutHalted, state.Phase)
+		assert.Equal(t, "timed out waiting for hubs: hub1", state.Message)
+	})
+}
+
+func TestRolloutStableVersion(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	waves := []*wave{
+		{name: "canary", leafHubNames: []string{"hub1"}},
+		{name: "prod", leafHubNames: []string{"hub2"}},
+	}
+	v1 := &rolloutObject{
+		ID: "9b5d4ea5-0e8b-4b4c-b2b5-3d7f4c5a2f9e", tableName: "placements", UpdatedAt: now,
+		Payload: []byte(`{"kind":"Placement","metadata":{"name":"p","uid":"9b5d4ea5","labels":{"v":"1"}}}`),
+	}
+	v2 := &rolloutObject{
+		ID: v1.ID, tableName: "placements", UpdatedAt: now.Add(time.Hour),
+		Payload: []byte(`{"kind":"Placement","metadata":{"name":"p","uid":"9b5d4ea5","labels":{"v":"2"}}}`),
+	}
+	leafHubVersions := func(state *rolloutState) map[string]string {
+		leafHubObjects := map[string][]*unstructured.Unstructured{}
+		_, pending := splitLeafHubs(state, waves)
+		require.NoError(t, addStableObject(leafHubObjects, pending, state))
+		versions := map[string]string{}
+		for leafHubName, objects := range leafHubObjects {
+			require.Len(t, objects, 1)
+			assert.Empty(t, objects[0].GetUID())
+			assert.Equal(t, v1.ID, objects[0].GetAnnotations()[constants.OriginOwnerReferenceAnnotation])
+			versions[leafHubName] = objects[0].GetLabels()["v"]
+		}
+		return versions
+	}
+
+	// the hubs the rollout of a new resource hasn't reached get nothing
+	state := newRolloutState(v1, "policy", nil)
+	startRollout(state, waves, newHealth(), now)
+	assert.Empty(t, leafHubVersions(state))
+	state.Phase = globalhubv1alpha3.RolloutCompleted
+
+	// the hubs the rollout of a new version hasn't reached get the version rolled out last
+	state = newRolloutState(v2, "policy", state)
+	startRollout(state, waves, newHealth(), now)
+	assert.Equal(t, map[string]string{"hub2": "1"}, leafHubVersions(state))
+
+	// the version of an unfinished rollout isn't stable, and the failed rollout sends the stable version to all
+	state = newRolloutState(v2, "missing", state)
+	assert.True(t, failRollout(state, "rollout policy missing isn't found", now))
+	assert.False(t, failRollout(state, "rollout policy missing isn't found", now))
+	assert.Equal(t, globalhubv1alpha3.RolloutFailed, state.Phase)
+	assert.Equal(t, map[string]string{"hub1": "1", "hub2": "1"}, leafHubVersions(state))
+}
+
+func TestHasFailedDeployment(t *testing.T) {
+	assert.False(t, hasFailedDeployment(nil))
+	assert.False(t, hasFailedDeployment([]appsv1alpha1.SubscriptionUnitStatus{
+		{Phase: appsv1alpha1.PackageDeployed}, {Phase: appsv1alpha1.PackageUnknown},
+	}))
+	assert.True(t, hasFailedDeployment([]appsv1alpha1.SubscriptionUnitStatus{
+		{Phase: appsv1alpha1.PackageDeployed}, {Phase: appsv1alpha1.PackagePropagationFailed},
+	}))
+}
@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	globalObjectsQuery = `SELECT payload FROM spec.%s WHERE deleted = FALSE AND
+		payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL
+		ORDER BY payload->'metadata'->>'namespace', payload->'metadata'->>'name'`
+	clusterSetBindingsQuery = `SELECT payload->'metadata'->>'namespace' AS namespace,
+		payload->'spec'->>'clusterSet' AS cluster_set FROM spec.managedclustersetbindings WHERE deleted = FALSE`
+)
+
+// AddGlobalScheduler adds the global scheduler to the manager, the global placements and placement rules are
+// scheduled against the managed clusters of all the leaf hubs every interval.
+func AddGlobalScheduler(mgr ctrl.Manager, specDB db.SpecDB, interval time.Duration) error {
+	if err := mgr.Add(&globalScheduler{
+		log:      ctrl.Log.WithName("global-scheduler"),
+		specDB:   specDB,
+		interval: interval,
+	}); err != nil {
+		return fmt.Errorf("failed to add global scheduler - %w", err)
+	}
+
+	return nil
+}
+
+// globalScheduler selects the managed clusters across all the leaf hubs for the global placements and placement rules,
+// the decisions are split per leaf hub and synced into the spec table, and then sent to the leaf hubs.
+type globalScheduler struct {
+	log      logr.Logger
+	specDB   db.SpecDB
+	interval time.Duration
+}
+
+// clusterSetBinding is the cluster set bound to a namespace.
+type clusterSetBinding struct {
+	Namespace  string
+	ClusterSet string
+}
+
+func (s *globalScheduler) Start(ctx context.Context) error {
+	s.log.Info("started global scheduler", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("stopped global scheduler")
+			return nil
+		case <-ticker.C:
+			if err := s.schedule(ctx); err != nil {
+				s.log.Error(err, "failed to schedule the global placements")
+			}
+		}
+	}
+}
+
+func (s *globalScheduler) schedule(ctx context.Context) error {
+	gormDB := database.GetGorm().WithContext(ctx)
+
+	var leafHubNames []string
+	if err := gormDB.Table(fmt.Sprintf("%s.%s", database.StatusSchema, database.LeafHubHeartbeatsTableName)).
+		Order("leaf_hub_name").Pluck("leaf_hub_name", &leafHubNames).Error; err != nil {
+		return fmt.Errorf("failed to list the leaf hubs - %w", err)
+	}
+
+	clusters, err := listLeafHubClusters(gormDB)
+	if err != nil {
+		return err
+	}
+
+	var bindings []clusterSetBinding
+	if err := gormDB.Raw(clusterSetBindingsQuery).Scan(&bindings).Error; err != nil {
+		return fmt.Errorf("failed to list the cluster set bindings - %w", err)
+	}
+	boundClusterSets := map[string][]string{}
+	for _, binding := range bindings {
+		boundClusterSets[binding.Namespace] = append(boundClusterSets[binding.Namespace], binding.ClusterSet)
+	}
+
+	clusterSetSelectors, err := listClusterSetSelectors(gormDB)
+	if err != nil {
+		return err
+	}
+
+	leafHubDecisions := map[string][]*spec.GlobalPlacementDecision{}
+	addDecisions := func(kind spec.PlacementKind, name, namespace string, selectedClusters []*leafHubCluster) {
+		decisions := map[string]*spec.GlobalPlacementDecision{}
+		for _, cluster := range selectedClusters {
+			decision, found := decisions[cluster.leafHubName]
+			if !found {
+				decision = &spec.GlobalPlacementDecision{Kind: kind, Name: name, Namespace: namespace}
+				decisions[cluster.leafHubName] = decision
+				leafHubDecisions[cluster.leafHubName] = append(leafHubDecisions[cluster.leafHubName], decision)
+			}
+			decision.Clusters = append(decision.Clusters, cluster.cluster.GetName())
+		}
+	}
+
+	var placements []*clusterv1beta1.Placement
+	if err := listGlobalObjects(gormDB, "placements", func(payload []byte) error {
+		placement := &clusterv1beta1.Placement{}
+		placements = append(placements, placement)
+		return json.Unmarshal(payload, placement)
+	}); err != nil {
+		return err
+	}
+	for _, placement := range placements {
+		selectedClusters, err := schedulePlacement(placement, boundClusterSets[placement.GetNamespace()],
+			clusterSetSelectors, clusters)
+		if err != nil {
+			s.log.Error(err, "failed to schedule the placement", "namespace", placement.GetNamespace(),
+				"name", placement.GetName())
+			continue
+		}
+		addDecisions(spec.PlacementKindPlacement, placement.GetName(), placement.GetNamespace(), selectedClusters)
+	}
+
+	var placementRules []*placementrulev1.PlacementRule
+	if err := listGlobalObjects(gormDB, "placementrules", func(payload []byte) error {
+		placementRule := &placementrulev1.PlacementRule{}
+		placementRules = append(placementRules, placementRule)
+		return json.Unmarshal(payload, placementRule)
+	}); err != nil {
+		return err
+	}
+	for _, placementRule := range placementRules {
+		selectedClusters, err := schedulePlacementRule(placementRule, clusters)
+		if err != nil {
+			s.log.Error(err, "failed to schedule the placement rule", "namespace", placementRule.GetNamespace(),
+				"name", placementRule.GetName())
+			continue
+		}
+		addDecisions(spec.PlacementKindPlacementRule, placementRule.GetName(), placementRule.GetNamespace(),
+			selectedClusters)
+	}
+
+	// every leaf hub gets its decisions, so that the clusters which aren't selected anymore are removed
+	for _, leafHubName := range leafHubNames {
+		if err := s.specDB.UpsertLeafHubPlacementDecisions(ctx, &spec.PlacementDecisionsSpecBundle{
+			LeafHubName: leafHubName,
+			Decisions:   leafHubDecisions[leafHubName],
+		}); err != nil {
+			return err
+		}
+	}
+
+	return s.specDB.DeleteLeafHubPlacementDecisions(ctx, leafHubNames)
+}
+
+// listLeafHubClusters returns the managed clusters of all the leaf hubs.
+func listLeafHubClusters(gormDB *gorm.DB) ([]*leafHubCluster, error) {
+	var managedClusters []models.ManagedCluster
+	if err := gormDB.Find(&managedClusters).Error; err != nil {
+		return nil, fmt.Errorf("failed to list the managed clusters - %w", err)
+	}
+
+	clusters := make([]*leafHubCluster, 0, len(managedClusters))
+	for _, managedCluster := range managedClusters {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(managedCluster.Payload, cluster); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the managed cluster of leaf hub %s - %w",
+				managedCluster.LeafHubName, err)
+		}
+		clusters = append(clusters, &leafHubCluster{leafHubName: managedCluster.LeafHubName, cluster: cluster})
+	}
+	return clusters, nil
+}
+
+// listClusterSetSelectors returns the selectors of the global cluster sets by their name.
+func listClusterSetSelectors(gormDB *gorm.DB) (map[string]labels.Selector, error) {
+	clusterSetSelectors := map[string]labels.Selector{}
+	if err := listGlobalObjects(gormDB, "managedclustersets", func(payload []byte) error {
+		clusterSet := &clusterv1beta2.ManagedClusterSet{}
+		if err := json.Unmarshal(payload, clusterSet); err != nil {
+			return err
+		}
+		selector, err := newClusterSetSelector(clusterSet)
+		if err != nil {
+			return err
+		}
+		clusterSetSelectors[clusterSet.GetName()] = selector
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return clusterSetSelectors, nil
+}
+
+// listGlobalObjects unmarshals the payload of every global object of the spec table with the unmarshal function.
+func listGlobalObjects(gormDB *gorm.DB, tableName string, unmarshal func(payload []byte) error) error {
+	rows, err := gormDB.Raw(fmt.Sprintf(globalObjectsQuery, tableName)).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to list the objects from spec.%s - %w", tableName, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return fmt.Errorf("failed to read the objects from spec.%s - %w", tableName, err)
+		}
+		if err := unmarshal(payload); err != nil {
+			return fmt.Errorf("failed to unmarshal the object from spec.%s - %w", tableName, err)
+		}
+	}
+	return nil
+}
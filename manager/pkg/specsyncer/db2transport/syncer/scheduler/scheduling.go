@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+)
+
+// globalClusterSetName is the default cluster set of OCM, it selects all the managed clusters.
+const globalClusterSetName = "global"
+
+// leafHubCluster is a managed cluster of a leaf hub.
+type leafHubCluster struct {
+	leafHubName string
+	cluster     *clusterv1.ManagedCluster
+}
+
+// schedulePlacement selects the clusters of the cluster sets bound to the namespace of the placement, which are in
+// the cluster sets of the placement and match any of its predicates. the members of the cluster sets are selected by
+// the cluster set selectors.
+func schedulePlacement(placement *clusterv1beta1.Placement, boundClusterSets []string,
+	clusterSetSelectors map[string]labels.Selector, clusters []*leafHubCluster,
+) ([]*leafHubCluster, error) {
+	clusterSets := boundClusterSets
+	if len(placement.Spec.ClusterSets) > 0 {
+		clusterSets = intersect(placement.Spec.ClusterSets, boundClusterSets)
+	}
+
+	selectors := make([]*clusterSelector, 0, len(placement.Spec.Predicates))
+	for _, predicate := range placement.Spec.Predicates {
+		selector, err := newClusterSelector(predicate.RequiredClusterSelector)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+
+	var feasibleClusters []*leafHubCluster
+	for _, cluster := range clusters {
+		if !inClusterSets(cluster.cluster, clusterSets, clusterSetSelectors) {
+			continue
+		}
+		if len(selectors) > 0 && !matchAny(selectors, cluster.cluster) {
+			continue
+		}
+		feasibleClusters = append(feasibleClusters, cluster)
+	}
+
+	return spreadAcrossLeafHubs(feasibleClusters, placement.Spec.NumberOfClusters), nil
+}
+
+// schedulePlacementRule selects the clusters listed by the placement rule, or the clusters matching its cluster
+// selector if no cluster is listed, the selected clusters must have all the conditions of the placement rule.
+func schedulePlacementRule(placementRule *placementrulev1.PlacementRule,
+	clusters []*leafHubCluster,
+) ([]*leafHubCluster, error) {
+	clusterNames := map[string]struct{}{}
+	for _, cluster := range placementRule.Spec.Clusters {
+		clusterNames[cluster.Name] = struct{}{}
+	}
+
+	selector := labels.Everything()
+	if placementRule.Spec.ClusterSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(placementRule.Spec.ClusterSelector); err != nil {
+			return nil, fmt.Errorf("invalid cluster selector: %w", err)
+		}
+	}
+
+	var feasibleClusters []*leafHubCluster
+	for _, cluster := range clusters {
+		if len(clusterNames) > 0 {
+			if _, found := clusterNames[cluster.cluster.GetName()]; !found {
+				continue
+			}
+		} else if !selector.Matches(labels.Set(cluster.cluster.GetLabels())) {
+			continue
+		}
+		if !hasConditions(cluster.cluster, placementRule.Spec.ClusterConditions) {
+			continue
+		}
+		feasibleClusters = append(feasibleClusters, cluster)
+	}
+
+	return spreadAcrossLeafHubs(feasibleClusters, placementRule.Spec.ClusterReplicas), nil
+}
+
+// spreadAcrossLeafHubs returns the given number of clusters, or all the clusters if the number isn't set. the
+// clusters are picked from the leaf hubs in turn so that they are spread evenly across the leaf hubs, the clusters of
+// a leaf hub are picked by name to keep the decisions stable.
+func spreadAcrossLeafHubs(clusters []*leafHubCluster, numberOfClusters *int32) []*leafHubCluster {
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].leafHubName != clusters[j].leafHubName {
+			return clusters[i].leafHubName < clusters[j].leafHubName
+		}
+		return clusters[i].cluster.GetName() < clusters[j].cluster.GetName()
+	})
+	if numberOfClusters == nil || int(*numberOfClusters) >= len(clusters) {
+		return clusters
+	}
+
+	var leafHubNames []string
+	leafHubClusters := map[string][]*leafHubCluster{}
+	for _, cluster := range clusters {
+		if _, found := leafHubClusters[cluster.leafHubName]; !found {
+			leafHubNames = append(leafHubNames, cluster.leafHubName)
+		}
+		leafHubClusters[cluster.leafHubName] = append(leafHubClusters[cluster.leafHubName], cluster)
+	}
+
+	selectedClusters := make([]*leafHubCluster, 0, *numberOfClusters)
+	for round := 0; len(selectedClusters) < int(*numberOfClusters); round++ {
+		for _, leafHubName := range leafHubNames {
+			if round < len(leafHubClusters[leafHubName]) && len(selectedClusters) < int(*numberOfClusters) {
+				selectedClusters = append(selectedClusters, leafHubClusters[leafHubName][round])
+			}
+		}
+	}
+	return selectedClusters
+}
+
+// clusterSelector matches the labels and the claims of the clusters.
+type clusterSelector struct {
+	labelSelector labels.Selector
+	claimSelector labels.Selector
+}
+
+func newClusterSelector(selector clusterv1beta1.ClusterSelector) (*clusterSelector, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	claimSelector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchExpressions: selector.ClaimSelector.MatchExpressions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim selector: %w", err)
+	}
+	return &clusterSelector{labelSelector: labelSelector, claimSelector: claimSelector}, nil
+}
+
+func (s *clusterSelector) matches(cluster *clusterv1.ManagedCluster) bool {
+	claims := labels.Set{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		claims[claim.Name] = claim.Value
+	}
+	return s.labelSelector.Matches(labels.Set(cluster.GetLabels())) && s.claimSelector.Matches(claims)
+}
+
+func matchAny(selectors []*clusterSelector, cluster *clusterv1.ManagedCluster) bool {
+	for _, selector := range selectors {
+		if selector.matches(cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+// newClusterSetSelector returns the selector of the members of the cluster set, the members of the cluster set of
+// the ExclusiveClusterSetLabel selector type have the cluster set label.
+func newClusterSetSelector(clusterSet *clusterv1beta2.ManagedClusterSet) (labels.Selector, error) {
+	if clusterSet.Spec.ClusterSelector.SelectorType != clusterv1beta2.LabelSelector {
+		return labels.SelectorFromSet(labels.Set{clusterv1beta2.ClusterSetLabel: clusterSet.GetName()}), nil
+	}
+	if clusterSet.Spec.ClusterSelector.LabelSelector == nil {
+		return labels.Everything(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(clusterSet.Spec.ClusterSelector.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector of cluster set %s: %w", clusterSet.GetName(), err)
+	}
+	return selector, nil
+}
+
+// inClusterSets returns true if the cluster is a member of any of the cluster sets. the cluster sets which aren't
+// global resources have no selector, they're resolved as the defaults of OCM, i.e. the global cluster set selects all
+// the clusters and the others select the clusters by the cluster set label.
+func inClusterSets(cluster *clusterv1.ManagedCluster, clusterSets []string,
+	clusterSetSelectors map[string]labels.Selector,
+) bool {
+	for _, clusterSet := range clusterSets {
+		selector, found := clusterSetSelectors[clusterSet]
+		switch {
+		case found:
+		case clusterSet == globalClusterSetName:
+			selector = labels.Everything()
+		default:
+			selector = labels.SelectorFromSet(labels.Set{clusterv1beta2.ClusterSetLabel: clusterSet})
+		}
+		if selector.Matches(labels.Set(cluster.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasConditions(cluster *clusterv1.ManagedCluster, conditions []placementrulev1.ClusterConditionFilter) bool {
+	for _, condition := range conditions {
+		if !meta.IsStatusConditionPresentAndEqual(cluster.Status.Conditions, condition.Type, condition.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+func intersect(values, others []string) []string {
+	var intersection []string
+	for _, value := range values {
+		for _, other := range others {
+			if value == other {
+				intersection = append(intersection, value)
+				break
+			}
+		}
+	}
+	return intersection
+}
@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+)
+
+func newLeafHubCluster(leafHubName, name string, labels map[string]string,
+	claims ...clusterv1.ManagedClusterClaim,
+) *leafHubCluster {
+	return &leafHubCluster{
+		leafHubName: leafHubName,
+		cluster: &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: claims,
+				Conditions: []metav1.Condition{
+					{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionTrue},
+				},
+			},
+		},
+	}
+}
+
+func clusterNames(clusters []*leafHubCluster) []string {
+	names := []string{}
+	for _, cluster := range clusters {
+		names = append(names, cluster.leafHubName+"/"+cluster.cluster.GetName())
+	}
+	return names
+}
+
+func testClusters() []*leafHubCluster {
+	return []*leafHubCluster{
+		newLeafHubCluster("hub2", "cluster3", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"}),
+		newLeafHubCluster("hub1", "cluster2", map[string]string{"env": "dev", clusterv1beta2.ClusterSetLabel: "set2"},
+			clusterv1.ManagedClusterClaim{Name: "region.open-cluster-management.io", Value: "us-east-1"}),
+		newLeafHubCluster("hub1", "cluster1", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"},
+			clusterv1.ManagedClusterClaim{Name: "region.open-cluster-management.io", Value: "us-east-1"}),
+		newLeafHubCluster("hub2", "cluster4", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"}),
+		newLeafHubCluster("hub3", "cluster5", map[string]string{"env": "prod"}),
+	}
+}
+
+func TestSchedulePlacement(t *testing.T) {
+	numberOfClusters := func(n int32) *int32 { return &n }
+
+	cases := []struct {
+		name             string
+		spec             clusterv1beta1.PlacementSpec
+		boundClusterSets []string
+		clusterSets      []*clusterv1beta2.ManagedClusterSet
+		expected         []string
+	}{
+		{
+			name:             "no bound cluster sets",
+			spec:             clusterv1beta1.PlacementSpec{},
+			boundClusterSets: nil,
+			expected:         []string{},
+		},
+		{
+			name:             "global cluster set",
+			spec:             clusterv1beta1.PlacementSpec{},
+			boundClusterSets: []string{"global"},
+			expected: []string{
+				"hub1/cluster1", "hub1/cluster2", "hub2/cluster3", "hub2/cluster4", "hub3/cluster5",
+			},
+		},
+		{
+			name:             "cluster sets of the placement",
+			spec:             clusterv1beta1.PlacementSpec{ClusterSets: []string{"set1", "set3"}},
+			boundClusterSets: []string{"set1", "set2"},
+			expected:         []string{"hub1/cluster1", "hub2/cluster3", "hub2/cluster4"},
+		},
+		{
+			name:             "cluster set of the label selector type",
+			spec:             clusterv1beta1.PlacementSpec{},
+			boundClusterSets: []string{"prod", "global"},
+			clusterSets: []*clusterv1beta2.ManagedClusterSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "prod"},
+					Spec: clusterv1beta2.ManagedClusterSetSpec{ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+						SelectorType:  clusterv1beta2.LabelSelector,
+						LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "global"},
+					Spec: clusterv1beta2.ManagedClusterSetSpec{ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+						SelectorType: clusterv1beta2.ExclusiveClusterSetLabel,
+					}},
+				},
+			},
+			expected: []string{"hub1/cluster1", "hub2/cluster3", "hub2/cluster4", "hub3/cluster5"},
+		},
+		{
+			name: "label and claim predicates",
+			spec: clusterv1beta1.PlacementSpec{Predicates: []clusterv1beta1.ClusterPredicate{
+				{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				}},
+				{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+					ClaimSelector: clusterv1beta1.ClusterClaimSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      "region.open-cluster-management.io",
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"us-east-1"},
+						}},
+					},
+				}},
+			}},
+			boundClusterSets: []string{"global"},
+			expected:         []string{"hub1/cluster1", "hub1/cluster2"},
+		},
+		{
+			name:             "number of clusters spread across the leaf hubs",
+			spec:             clusterv1beta1.PlacementSpec{NumberOfClusters: numberOfClusters(4)},
+			boundClusterSets: []string{"global"},
+			expected:         []string{"hub1/cluster1", "hub2/cluster3", "hub3/cluster5", "hub1/cluster2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterSetSelectors := map[string]labels.Selector{}
+			for _, clusterSet := range c.clusterSets {
+				selector, err := newClusterSetSelector(clusterSet)
+				assert.NoError(t, err)
+				clusterSetSelectors[clusterSet.GetName()] = selector
+			}
+			clusters, err := schedulePlacement(&clusterv1beta1.Placement{Spec: c.spec}, c.boundClusterSets,
+				clusterSetSelectors, testClusters())
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, clusterNames(clusters))
+		})
+	}
+
+	_, err := schedulePlacement(&clusterv1beta1.Placement{Spec: clusterv1beta1.PlacementSpec{
+		Predicates: []clusterv1beta1.ClusterPredicate{{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+			LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: "Unknown"},
+			}},
+		}}},
+	}}, []string{"global"}, nil, testClusters())
+	assert.Error(t, err)
+
+	_, err = newClusterSetSelector(&clusterv1beta2.ManagedClusterSet{
+		Spec: clusterv1beta2.ManagedClusterSetSpec{ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+			SelectorType: clusterv1beta2.LabelSelector,
+			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: "Unknown"},
+			}},
+		}},
+	})
+	assert.Error(t, err)
+}
+
+func TestSchedulePlacementRule(t *testing.T) {
+	clusterReplicas := func(n int32) *int32 { return &n }
+
+	cases := []struct {
+		name     string
+		spec     placementrulev1.PlacementRuleSpec
+		expected []string
+	}{
+		{
+			name:     "all the clusters",
+			spec:     placementrulev1.PlacementRuleSpec{},
+			expected: []string{"hub1/cluster1", "hub1/cluster2", "hub2/cluster3", "hub2/cluster4", "hub3/cluster5"},
+		},
+		{
+			name: "listed clusters",
+			spec: placementrulev1.PlacementRuleSpec{GenericPlacementFields: placementrulev1.GenericPlacementFields{
+				Clusters:        []placementrulev1.GenericClusterReference{{Name: "cluster2"}, {Name: "cluster4"}},
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			}},
+			expected: []string{"hub1/cluster2", "hub2/cluster4"},
+		},
+		{
+			name: "cluster selector and replicas",
+			spec: placementrulev1.PlacementRuleSpec{
+				ClusterReplicas: clusterReplicas(2),
+				GenericPlacementFields: placementrulev1.GenericPlacementFields{
+					ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				},
+			},
+			expected: []string{"hub1/cluster1", "hub2/cluster3"},
+		},
+		{
+			name: "cluster conditions",
+			spec: placementrulev1.PlacementRuleSpec{ClusterConditions: []placementrulev1.ClusterConditionFilter{
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionFalse},
+			}},
+			expected: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusters, err := schedulePlacementRule(&placementrulev1.PlacementRule{Spec: c.spec}, testClusters())
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, clusterNames(clusters))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 )
 
 const (
@@ -125,7 +126,7 @@ func (watcher *managedClusterLabelsStatusWatcher) trimDeletedLabelsByStatus(ctx
 	for _, managedClusterLabelsSpecBundle := range leafHubToLabelsSpecBundleMap {
 		// fetch actual labels status reflected in status DB
 		for _, managedClusterLabelsSpec := range managedClusterLabelsSpecBundle.Objects {
-			labelsStatus, err := watcher.statusDB.GetManagedClusterLabelsStatus(ctx, watcher.labelsStatusTableName,
+			managedClusterStatus, err := watcher.statusDB.GetManagedClusterStatus(ctx, watcher.labelsStatusTableName,
 				managedClusterLabelsSpecBundle.LeafHubName, managedClusterLabelsSpec.ClusterName)
 			if err != nil {
 				watcher.log.Error(err, "skipped trimming managed cluster labels spec",
@@ -138,25 +139,31 @@ func (watcher *managedClusterLabelsStatusWatcher) trimDeletedLabelsByStatus(ctx
 				continue
 			}
 
-			// check which deleted label keys still appear in status
-			deletedLabelKeysStillInStatus := make([]string, 0)
-
-			for _, key := range managedClusterLabelsSpec.DeletedLabelKeys {
-				if _, found := labelsStatus[key]; found {
-					deletedLabelKeysStillInStatus = append(deletedLabelKeysStillInStatus, key)
-				}
+			taintsStatus := make(map[string]string, len(managedClusterStatus.Spec.Taints))
+			for _, taint := range managedClusterStatus.Spec.Taints {
+				taintsStatus[spec.TaintKey(taint.Key, taint.Effect)] = taint.Value
 			}
 
-			// if deleted labels did not change then skip
-			if len(deletedLabelKeysStillInStatus) ==
-				len(managedClusterLabelsSpec.DeletedLabelKeys) {
+			// check which deleted label, annotation and taint keys still appear in status
+			deletedLabelKeysStillInStatus := keysStillInStatus(managedClusterLabelsSpec.DeletedLabelKeys,
+				managedClusterStatus.GetLabels())
+			deletedAnnotationKeysStillInStatus := keysStillInStatus(managedClusterLabelsSpec.DeletedAnnotationKeys,
+				managedClusterStatus.GetAnnotations())
+			deletedTaintKeysStillInStatus := keysStillInStatus(managedClusterLabelsSpec.DeletedTaintKeys,
+				taintsStatus)
+
+			// if deleted keys did not change then skip
+			if len(deletedLabelKeysStillInStatus) == len(managedClusterLabelsSpec.DeletedLabelKeys) &&
+				len(deletedAnnotationKeysStillInStatus) == len(managedClusterLabelsSpec.DeletedAnnotationKeys) &&
+				len(deletedTaintKeysStillInStatus) == len(managedClusterLabelsSpec.DeletedTaintKeys) {
 				continue
 			}
 
-			if err := watcher.specDB.UpdateDeletedLabelKeys(ctx, watcher.labelsSpecTableName,
+			if err := watcher.specDB.UpdateDeletedKeys(ctx, watcher.labelsSpecTableName,
 				managedClusterLabelsSpec.Version, managedClusterLabelsSpecBundle.LeafHubName,
-				managedClusterLabelsSpec.ClusterName, deletedLabelKeysStillInStatus); err != nil {
-				watcher.log.Error(err, "failed to trim deleted_label_keys",
+				managedClusterLabelsSpec.ClusterName, deletedLabelKeysStillInStatus,
+				deletedAnnotationKeysStillInStatus, deletedTaintKeysStillInStatus); err != nil {
+				watcher.log.Error(err, "failed to trim deleted keys",
 					"leafHub", managedClusterLabelsSpecBundle.LeafHubName,
 					"managedCluster", managedClusterLabelsSpec.ClusterName,
 					"version", managedClusterLabelsSpec.Version)
@@ -175,6 +182,19 @@ func (watcher *managedClusterLabelsStatusWatcher) trimDeletedLabelsByStatus(ctx
 	return result
 }
 
+// keysStillInStatus returns the deleted keys which still appear in the status.
+func keysStillInStatus(deletedKeys []string, status map[string]string) []string {
+	keys := make([]string, 0)
+
+	for _, key := range deletedKeys {
+		if _, found := status[key]; found {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
 // TODO: once non-k8s-restapi exposes hub names, remove line.
 func (watcher *managedClusterLabelsStatusWatcher) fillMissingLeafHubNames(ctx context.Context) {
 	entries, err := watcher.specDB.GetEntriesWithoutLeafHubName(ctx, watcher.labelsSpecTableName)
@@ -21,8 +21,9 @@ func AddDB2TransportSyncers(mgr ctrl.Manager, specDB db.SpecDB, managerConfig *c
 		return fmt.Errorf("failed to init spec transport bridge: %w", err)
 	}
 	specSyncInterval := managerConfig.SyncerConfig.SpecSyncInterval
+	fullResyncInterval := managerConfig.SyncerConfig.SpecFullResyncInterval
 
-	addDBSyncerFunctions := []func(ctrl.Manager, db.SpecDB, transport.Producer, time.Duration) error{
+	addDBSyncerFunctions := []func(ctrl.Manager, db.SpecDB, transport.Producer, time.Duration, time.Duration) error{
 		// dbsyncer.AddHoHConfigDBToTransportSyncer,
 		dbsyncer.AddPoliciesDBToTransportSyncer,
 		dbsyncer.AddPlacementRulesDBToTransportSyncer,
@@ -34,9 +35,14 @@ func AddDB2TransportSyncers(mgr ctrl.Manager, specDB db.SpecDB, managerConfig *c
 		dbsyncer.AddPlacementsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetBindingsDBToTransportSyncer,
+		dbsyncer.AddAgentConfigDBToTransportSyncer,
+		dbsyncer.AddPlacementDecisionsDBToTransportSyncer,
+		dbsyncer.AddGlobalResourcesDBToTransportSyncer,
+		dbsyncer.AddRolloutsDBToTransportSyncer,
+		dbsyncer.AddManagerCapabilitiesToTransportSyncer,
 	}
 	for _, addDBSyncerFunction := range addDBSyncerFunctions {
-		if err := addDBSyncerFunction(mgr, specDB, producer, specSyncInterval); err != nil {
+		if err := addDBSyncerFunction(mgr, specDB, producer, specSyncInterval, fullResyncInterval); err != nil {
 			return fmt.Errorf("failed to add DB Syncer: %w", err)
 		}
 	}
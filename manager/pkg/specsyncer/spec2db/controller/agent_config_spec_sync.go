@@ -0,0 +1,150 @@
+// Copyright (c) 2020 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// all the events are mapped into the same request, the agent configs of all the leaf hubs are resolved together
+var agentConfigRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "leaf-hub-agent-configs"}}
+
+// the MulticlusterGlobalHub is owned by the operator, the manager reads it as unstructured and only decodes the
+// agent configs of its spec
+var mghGVK = globalhubv1alpha3.GroupVersion.WithKind("MulticlusterGlobalHub")
+
+type agentConfigController struct {
+	client client.Client
+	log    logr.Logger
+	specDB db.SpecDB
+}
+
+// AddAgentConfigController resolves the effective agent config of every leaf hub from the agent config and the
+// overrides of the MulticlusterGlobalHub, and syncs them into the database.
+func AddAgentConfigController(mgr ctrl.Manager, specDB db.SpecDB) error {
+	enqueueAll := handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{agentConfigRequest}
+	})
+	// only the leaf hubs joining or leaving and the changes of their labels affect the resolved configs
+	managedClusterPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+	}
+
+	mgh := &unstructured.Unstructured{}
+	mgh.SetGroupVersionKind(mghGVK)
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("agent-config-controller").
+		Watches(&source.Kind{Type: mgh}, enqueueAll,
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &clusterv1.ManagedCluster{}}, enqueueAll,
+			builder.WithPredicates(managedClusterPredicate)).
+		Complete(&agentConfigController{
+			client: mgr.GetClient(),
+			log:    ctrl.Log.WithName("agent-config-spec-syncer"),
+			specDB: specDB,
+		}); err != nil {
+		return fmt.Errorf("failed to add agent config controller to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func (r *agentConfigController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	mghList := &unstructured.UnstructuredList{}
+	mghList.SetGroupVersionKind(mghGVK.GroupVersion().WithKind(mghGVK.Kind + "List"))
+	if err := r.client.List(ctx, mghList); err != nil {
+		r.log.Error(err, "Reconciliation failed")
+		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
+	}
+	if len(mghList.Items) == 0 {
+		r.log.Info("MulticlusterGlobalHub not found, keep the agent configs of the leaf hubs")
+		return ctrl.Result{}, nil
+	}
+
+	agentConfigs := &globalhubv1alpha3.AgentConfigs{}
+	mghSpec, _, err := unstructured.NestedMap(mghList.Items[0].Object, "spec")
+	if err == nil {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(mghSpec, agentConfigs)
+	}
+	if err != nil {
+		r.log.Error(err, "failed to decode the agent configs of the MulticlusterGlobalHub")
+		return ctrl.Result{}, nil
+	}
+
+	agentConfigBundles, err := r.resolveAgentConfigs(ctx, agentConfigs)
+	if err != nil {
+		r.log.Error(err, "Reconciliation failed")
+		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
+	}
+
+	leafHubNames := make([]string, 0, len(agentConfigBundles))
+	for _, agentConfigBundle := range agentConfigBundles {
+		if err := r.specDB.UpsertLeafHubAgentConfig(ctx, agentConfigBundle); err != nil {
+			r.log.Error(err, "Reconciliation failed")
+			return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
+		}
+		leafHubNames = append(leafHubNames, agentConfigBundle.LeafHubName)
+	}
+
+	if err := r.specDB.DeleteLeafHubAgentConfigs(ctx, leafHubNames); err != nil {
+		r.log.Error(err, "Reconciliation failed")
+		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
+	}
+
+	r.log.Info("Reconciliation complete.", "leafHubs", len(leafHubNames))
+	return ctrl.Result{}, nil
+}
+
+// resolveAgentConfigs returns the effective agent configs of the leaf hubs, which are the managed clusters of the
+// global hub except the local cluster.
+func (r *agentConfigController) resolveAgentConfigs(ctx context.Context,
+	agentConfigs *globalhubv1alpha3.AgentConfigs,
+) ([]*spec.AgentConfigSpecBundle, error) {
+	managedClusterList := &clusterv1.ManagedClusterList{}
+	if err := r.client.List(ctx, managedClusterList); err != nil {
+		return nil, fmt.Errorf("failed to list managed clusters: %w", err)
+	}
+
+	agentConfigBundles := make([]*spec.AgentConfigSpecBundle, 0, len(managedClusterList.Items))
+	for _, managedCluster := range managedClusterList.Items {
+		if managedCluster.GetName() == constants.LocalClusterName {
+			continue
+		}
+		agentConfig, configSource, err := agentConfigs.GetLeafHubAgentConfig(managedCluster.GetName(),
+			managedCluster.GetLabels())
+		if err != nil {
+			return nil, err
+		}
+		agentConfigBundles = append(agentConfigBundles, &spec.AgentConfigSpecBundle{
+			LeafHubName: managedCluster.GetName(),
+			Source:      configSource,
+			AgentConfig: agentConfig,
+		})
+	}
+	return agentConfigBundles, nil
+}
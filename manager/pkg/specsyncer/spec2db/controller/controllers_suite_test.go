@@ -93,7 +93,7 @@ var _ = BeforeSuite(func() {
 	Expect(postgresSQL).NotTo(BeNil())
 
 	By("Adding the controllers to the manager")
-	Expect(spec2db.AddSpec2DBControllers(mgr, postgresSQL)).Should(Succeed())
+	Expect(spec2db.AddSpec2DBControllers(mgr, postgresSQL, true)).Should(Succeed())
 	go func() {
 		defer GinkgoRecover()
 		err = mgr.Start(ctx)
@@ -5,6 +5,7 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -18,8 +19,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/audit"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 func GlobalResourcePredicate() predicate.Predicate {
@@ -51,7 +55,7 @@ const (
 
 func (r *genericSpecToDBReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
-	instanceUID, instance, err := r.processCR(ctx, request, reqLogger)
+	instanceUID, instance, requester, err := r.processCR(ctx, request, reqLogger)
 	if err != nil {
 		reqLogger.Error(err, "Reconciliation failed")
 		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
@@ -61,7 +65,7 @@ func (r *genericSpecToDBReconciler) Reconcile(ctx context.Context, request ctrl.
 		return ctrl.Result{}, nil
 	}
 
-	instanceInTheDatabase, err := r.processInstanceInTheDatabase(ctx, instance, instanceUID, reqLogger)
+	instanceInTheDatabase, err := r.processInstanceInTheDatabase(ctx, instance, instanceUID, requester, reqLogger)
 	if err != nil {
 		reqLogger.Error(err, "Reconciliation failed")
 		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
@@ -70,7 +74,8 @@ func (r *genericSpecToDBReconciler) Reconcile(ctx context.Context, request ctrl.
 	if !r.areEqual(instance, instanceInTheDatabase) {
 		reqLogger.Info("Mismatch between hub and the database, updating the database")
 
-		if err := r.specDB.UpdateSpecObject(ctx, r.tableName, instanceUID, &instance); err != nil {
+		if err := r.specDB.UpdateSpecObject(ctx, r.tableName, instanceUID, &instance,
+			audit.NewSpecChange(database.AuditOperationUpdate, requester, instance)); err != nil {
 			reqLogger.Error(err, "Reconciliation failed")
 
 			return ctrl.Result{}, err
@@ -82,26 +87,31 @@ func (r *genericSpecToDBReconciler) Reconcile(ctx context.Context, request ctrl.
 
 func (r *genericSpecToDBReconciler) processCR(ctx context.Context, request ctrl.Request,
 	log logr.Logger,
-) (string, client.Object, error) {
+) (string, client.Object, audit.Requester, error) {
 	instance := r.createInstance()
 
 	err := r.client.Get(ctx, request.NamespacedName, instance)
 	if apierrors.IsNotFound(err) {
-		// the instance on hub was deleted, update all the matching instances in the database as deleted
-		return "", nil, r.deleteFromTheDatabase(ctx, request.Name, request.Namespace, log)
+		// the instance on hub was deleted without the finalizer, update all the matching instances in the database
+		// as deleted, the deleter isn't known anymore
+		return "", nil, audit.Requester{}, r.deleteFromTheDatabase(ctx, request.Name, request.Namespace,
+			audit.Requester{User: audit.UnknownUser}, log)
 	}
 
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get the instance from hub: %w", err)
+		return "", nil, audit.Requester{}, fmt.Errorf("failed to get the instance from hub: %w", err)
 	}
 
 	if isInstanceBeingDeleted(instance) {
-		return "", nil, r.removeFinalizerAndDelete(ctx, instance, log)
+		return "", nil, audit.Requester{}, r.removeFinalizerAndDelete(ctx, instance, audit.DeleterOf(instance), log)
 	}
 
+	// the requester is resolved before the managed fields are cleaned up
+	requester := audit.RequesterOf(instance)
+
 	err = r.addFinalizer(ctx, instance, log)
 
-	return string(instance.GetUID()), r.cleanInstance(instance), err
+	return string(instance.GetUID()), r.cleanInstance(instance), requester, err
 }
 
 func isInstanceBeingDeleted(instance client.Object) bool {
@@ -109,7 +119,7 @@ func isInstanceBeingDeleted(instance client.Object) bool {
 }
 
 func (r *genericSpecToDBReconciler) removeFinalizerAndDelete(ctx context.Context, instance client.Object,
-	log logr.Logger,
+	requester audit.Requester, log logr.Logger,
 ) error {
 	if !controllerutil.ContainsFinalizer(instance, r.finalizerName) {
 		return nil
@@ -118,7 +128,8 @@ func (r *genericSpecToDBReconciler) removeFinalizerAndDelete(ctx context.Context
 	log.Info("Removing an instance from the database")
 
 	// the policy is being deleted, update all the matching policies in the database as deleted
-	if err := r.deleteFromTheDatabase(ctx, instance.GetName(), instance.GetNamespace(), log); err != nil {
+	if err := r.deleteFromTheDatabase(ctx, instance.GetName(), instance.GetNamespace(), requester,
+		log); err != nil {
 		return fmt.Errorf("failed to delete an instance from the database: %w", err)
 	}
 
@@ -148,7 +159,7 @@ func (r *genericSpecToDBReconciler) addFinalizer(ctx context.Context, instance c
 }
 
 func (r *genericSpecToDBReconciler) processInstanceInTheDatabase(ctx context.Context, instance client.Object,
-	instanceUID string, log logr.Logger,
+	instanceUID string, requester audit.Requester, log logr.Logger,
 ) (client.Object, error) {
 	instanceInTheDatabase := r.createInstance()
 	err := r.specDB.QuerySpecObject(ctx, r.tableName, instanceUID, &instanceInTheDatabase)
@@ -156,7 +167,8 @@ func (r *genericSpecToDBReconciler) processInstanceInTheDatabase(ctx context.Con
 	if errors.Is(err, pgx.ErrNoRows) {
 		log.Info("The instance with the current UID does not exist in the database, inserting...")
 
-		if err := r.specDB.InsertSpecObject(ctx, r.tableName, instanceUID, &instance); err != nil {
+		if err := r.specDB.InsertSpecObject(ctx, r.tableName, instanceUID, &instance,
+			audit.NewSpecChange(database.AuditOperationCreate, requester, instance)); err != nil {
 			return nil, err
 		}
 
@@ -182,18 +194,31 @@ func (r *genericSpecToDBReconciler) cleanInstance(instance client.Object) client
 	// instance.SetClusterName("")
 
 	delete(instance.GetAnnotations(), "kubectl.kubernetes.io/last-applied-configuration")
+	// the applied conditions are written by the global hub, they aren't part of the spec
+	delete(instance.GetAnnotations(), constants.AppliedConditionsAnnotation)
+	// the users of the changes are recorded in the audit log, they aren't part of the spec either
+	delete(instance.GetAnnotations(), constants.AuditRequestedByAnnotation)
+	delete(instance.GetAnnotations(), constants.AuditDeletedByAnnotation)
 
 	r.cleanObject(instance)
 
 	return instance
 }
 
+// deleteFromTheDatabase marks the instances with the name and namespace as deleted, the deletion of every instance is
+// recorded in the audit log with the given requester.
 func (r *genericSpecToDBReconciler) deleteFromTheDatabase(ctx context.Context, name, namespace string,
-	log logr.Logger,
+	requester audit.Requester, log logr.Logger,
 ) error {
 	log.Info("Instance was deleted, update the deleted field in the database")
 
-	if err := r.specDB.DeleteSpecObject(ctx, r.tableName, name, namespace); err != nil {
+	details, _ := json.Marshal(map[string]string{"userSource": requester.Source})
+	if err := r.specDB.DeleteSpecObject(ctx, r.tableName, name, namespace, &models.AuditLog{
+		UserName:  requester.User,
+		Source:    database.AuditSourceController,
+		Operation: database.AuditOperationDelete,
+		Details:   details,
+	}); err != nil {
 		return err
 	}
 
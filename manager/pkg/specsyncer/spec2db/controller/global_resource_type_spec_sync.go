@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	channelv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appv1beta1 "sigs.k8s.io/application/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const globalResourcesTableName = "resources"
+
+// builtInGroupKinds are synced by their own controllers, so they can't be registered as global resource types.
+var builtInGroupKinds = map[schema.GroupKind]bool{
+	{Group: policyv1.GroupVersion.Group, Kind: "Policy"}:                         true,
+	{Group: policyv1.GroupVersion.Group, Kind: "PlacementBinding"}:               true,
+	{Group: placementrulev1.SchemeGroupVersion.Group, Kind: "PlacementRule"}:     true,
+	{Group: appsubv1.SchemeGroupVersion.Group, Kind: "Subscription"}:             true,
+	{Group: channelv1.SchemeGroupVersion.Group, Kind: "Channel"}:                 true,
+	{Group: appv1beta1.GroupVersion.Group, Kind: "Application"}:                  true,
+	{Group: clusterv1beta1.GroupName, Kind: "Placement"}:                         true,
+	{Group: clusterv1beta2.GroupName, Kind: "ManagedClusterSet"}:                 true,
+	{Group: clusterv1beta2.GroupName, Kind: "ManagedClusterSetBinding"}:          true,
+	{Group: globalhubv1alpha3.GroupVersion.Group, Kind: "GlobalResourceType"}:    true,
+	{Group: globalhubv1alpha3.GroupVersion.Group, Kind: "MulticlusterGlobalHub"}: true,
+	{Group: globalhubv1alpha3.GroupVersion.Group, Kind: "GlobalHubAgentConfig"}:  true,
+}
+
+// all the events are mapped into the same request, the global resource types are registered together
+var globalResourceTypesRequest = reconcile.Request{NamespacedName: types.NamespacedName{
+	Name: "global-resource-types",
+}}
+
+// globalResourceTypeController registers the kinds declared by the GlobalResourceTypes as global resources, the
+// objects of a registered kind are synced into the resources table by a generic spec-to-db controller, which is
+// started when the kind is registered and stopped when it isn't registered anymore.
+type globalResourceTypeController struct {
+	mgr    ctrl.Manager
+	client client.Client
+	log    logr.Logger
+	specDB db.SpecDB
+	// resourceTypes holds the specs of the registered kinds
+	resourceTypes map[schema.GroupVersionKind]*globalhubv1alpha3.GlobalResourceTypeSpec
+	// stopControllers stops the controllers of the registered kinds
+	stopControllers map[schema.GroupVersionKind]context.CancelFunc
+	lock            sync.RWMutex
+}
+
+// AddGlobalResourceTypeController adds the controller registering the kinds of the GlobalResourceTypes as global
+// resources to the manager.
+func AddGlobalResourceTypeController(mgr ctrl.Manager, specDB db.SpecDB) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("global-resource-type-controller").
+		Watches(&source.Kind{Type: &globalhubv1alpha3.GlobalResourceType{}},
+			handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+				return []reconcile.Request{globalResourceTypesRequest}
+			})).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(&globalResourceTypeController{
+			mgr:             mgr,
+			client:          mgr.GetClient(),
+			log:             ctrl.Log.WithName("global-resource-types-spec-syncer"),
+			specDB:          specDB,
+			resourceTypes:   map[schema.GroupVersionKind]*globalhubv1alpha3.GlobalResourceTypeSpec{},
+			stopControllers: map[schema.GroupVersionKind]context.CancelFunc{},
+		}); err != nil {
+		return fmt.Errorf("failed to add global resource type controller to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func (r *globalResourceTypeController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	resourceTypeList := &globalhubv1alpha3.GlobalResourceTypeList{}
+	if err := r.client.List(ctx, resourceTypeList); err != nil {
+		r.log.Error(err, "Reconciliation failed")
+		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, err
+	}
+	// the first one by name wins if a kind is declared more than once
+	sort.Slice(resourceTypeList.Items, func(i, j int) bool {
+		return resourceTypeList.Items[i].GetName() < resourceTypeList.Items[j].GetName()
+	})
+
+	reasons := make([]string, len(resourceTypeList.Items))
+	errs := make([]error, len(resourceTypeList.Items))
+	resourceTypes := map[schema.GroupVersionKind]*globalhubv1alpha3.GlobalResourceTypeSpec{}
+	for i := range resourceTypeList.Items {
+		resourceType := &resourceTypeList.Items[i]
+		if !resourceType.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		gvk := resourceType.Spec.GroupVersionKind()
+		if reasons[i], errs[i] = r.validate(ctx, gvk, resourceTypes); errs[i] == nil {
+			resourceTypes[gvk] = resourceType.Spec.DeepCopy()
+		}
+	}
+
+	// the kinds are registered before their controllers start, otherwise the initial events would be filtered out
+	r.lock.Lock()
+	r.resourceTypes = resourceTypes
+	r.lock.Unlock()
+
+	requeue := false
+	for gvk := range r.stopControllers {
+		if _, found := resourceTypes[gvk]; found {
+			continue
+		}
+		if err := r.stopController(ctx, gvk); err != nil {
+			r.log.Error(err, "failed to stop the controller of the global resource type", "gvk", gvk)
+			requeue = true
+		}
+	}
+
+	for i := range resourceTypeList.Items {
+		resourceType := &resourceTypeList.Items[i]
+		if !resourceType.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if errs[i] == nil {
+			if errs[i] = r.startController(ctx, resourceType.Spec.GroupVersionKind()); errs[i] != nil {
+				reasons[i] = "ControllerFailed"
+			}
+		}
+		// the kinds which can't be registered yet are retried, like the CRDs which aren't installed
+		if errs[i] != nil && reasons[i] != "BuiltInKind" && reasons[i] != "DuplicateKind" {
+			requeue = true
+		}
+
+		if err := r.updateStatus(ctx, resourceType, reasons[i], errs[i]); err != nil {
+			r.log.Error(err, "failed to update the status", "name", resourceType.GetName())
+			requeue = true
+		}
+	}
+
+	r.log.Info("Reconciliation complete.", "resourceTypes", len(resourceTypes))
+	if requeue {
+		return ctrl.Result{Requeue: true, RequeueAfter: requeuePeriodSeconds * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// validate returns the reason and the error if the kind can't be registered.
+func (r *globalResourceTypeController) validate(ctx context.Context, gvk schema.GroupVersionKind,
+	resourceTypes map[schema.GroupVersionKind]*globalhubv1alpha3.GlobalResourceTypeSpec,
+) (string, error) {
+	if builtInGroupKinds[gvk.GroupKind()] {
+		return "BuiltInKind", fmt.Errorf("%s is synced as a global resource already", gvk.GroupKind())
+	}
+	if _, found := resourceTypes[gvk]; found {
+		return "DuplicateKind", fmt.Errorf("%s is declared by another global resource type", gvk)
+	}
+	if _, err := r.mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		return "KindNotFound", fmt.Errorf("failed to find %s: %w", gvk, err)
+	}
+
+	objectList := &unstructured.UnstructuredList{}
+	objectList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.mgr.GetAPIReader().List(ctx, objectList, client.Limit(1)); apierrors.IsForbidden(err) {
+		return "Forbidden", fmt.Errorf("the manager isn't allowed to list %s: %w", gvk, err)
+	} else if err != nil {
+		return "ListFailed", fmt.Errorf("failed to list %s: %w", gvk, err)
+	}
+	return "", nil
+}
+
+// startController starts the spec-to-db controller of the kind if it isn't started yet, the controller isn't added to
+// the manager, so that it's stopped once the kind isn't registered anymore. it runs until the context of the
+// reconciliation, which is the context of the manager, is done.
+func (r *globalResourceTypeController) startController(ctx context.Context, gvk schema.GroupVersionKind) error {
+	if _, found := r.stopControllers[gvk]; found {
+		return nil
+	}
+
+	instance := &unstructured.Unstructured{}
+	instance.SetGroupVersionKind(gvk)
+	name := strings.ToLower(strings.TrimSuffix(fmt.Sprintf("global-%s-%s-%s", gvk.Kind, gvk.Version, gvk.Group),
+		"-"))
+
+	resourceController, err := controller.NewUnmanaged(name, r.mgr, controller.Options{
+		Reconciler: &genericSpecToDBReconciler{
+			client:        r.mgr.GetClient(),
+			specDB:        r.specDB.GlobalResourceSpecDB(gvk),
+			log:           ctrl.Log.WithName(fmt.Sprintf("%s-spec-syncer", name)),
+			tableName:     globalResourcesTableName,
+			finalizerName: constants.GlobalHubCleanupFinalizer,
+			createInstance: func() client.Object {
+				object := &unstructured.Unstructured{}
+				object.SetGroupVersionKind(gvk)
+				return object
+			},
+			cleanObject: func(instance client.Object) {
+				cleanGlobalResource(instance, r.getResourceType(gvk))
+			},
+			areEqual: func(instance1, instance2 client.Object) bool {
+				return areGlobalResourcesEqual(instance1, instance2, r.getResourceType(gvk))
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s controller: %w", gvk, err)
+	}
+	if err := resourceController.Watch(&source.Kind{Type: instance}, &handler.EnqueueRequestForObject{},
+		GlobalResourcePredicate()); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", gvk, err)
+	}
+
+	controllerCtx, stop := context.WithCancel(ctx)
+	go func() {
+		if err := resourceController.Start(controllerCtx); err != nil {
+			r.log.Error(err, "failed to run the controller of the global resource type", "gvk", gvk)
+		}
+	}()
+
+	r.stopControllers[gvk] = stop
+	r.log.Info("started the controller of the global resource type", "gvk", gvk)
+	return nil
+}
+
+// stopController stops the spec-to-db controller of the kind which isn't registered anymore, the cleanup finalizers
+// are removed from its objects, so that they can be deleted without the controller. the objects synced into the
+// resources table stay on the managed hubs.
+func (r *globalResourceTypeController) stopController(ctx context.Context, gvk schema.GroupVersionKind) error {
+	objectList := &unstructured.UnstructuredList{}
+	objectList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.mgr.GetAPIReader().List(ctx, objectList); err != nil && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to list %s: %w", gvk, err)
+	}
+	for i := range objectList.Items {
+		object := &objectList.Items[i]
+		if !controllerutil.RemoveFinalizer(object, constants.GlobalHubCleanupFinalizer) {
+			continue
+		}
+		if err := r.client.Update(ctx, object); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove the finalizer from %s %s/%s: %w", gvk.Kind,
+				object.GetNamespace(), object.GetName(), err)
+		}
+	}
+
+	r.stopControllers[gvk]()
+	delete(r.stopControllers, gvk)
+	r.log.Info("stopped the controller of the global resource type", "gvk", gvk)
+	return nil
+}
+
+// getResourceType returns the spec of the registered kind, or the default spec if the kind isn't registered.
+func (r *globalResourceTypeController) getResourceType(
+	gvk schema.GroupVersionKind,
+) *globalhubv1alpha3.GlobalResourceTypeSpec {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if resourceType, found := r.resourceTypes[gvk]; found {
+		return resourceType
+	}
+	return &globalhubv1alpha3.GlobalResourceTypeSpec{}
+}
+
+func (r *globalResourceTypeController) updateStatus(ctx context.Context,
+	resourceType *globalhubv1alpha3.GlobalResourceType, reason string, registerErr error,
+) error {
+	condition := metav1.Condition{
+		Type:               globalhubv1alpha3.GlobalResourceTypeRegistered,
+		Status:             metav1.ConditionTrue,
+		Reason:             "KindRegistered",
+		Message:            fmt.Sprintf("the %s resources are synced to the managed hubs", resourceType.Spec.Kind),
+		ObservedGeneration: resourceType.GetGeneration(),
+	}
+	if registerErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = registerErr.Error()
+	}
+
+	existingCondition := meta.FindStatusCondition(resourceType.Status.Conditions, condition.Type)
+	if existingCondition != nil && existingCondition.Status == condition.Status &&
+		existingCondition.Message == condition.Message &&
+		existingCondition.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+
+	meta.SetStatusCondition(&resourceType.Status.Conditions, condition)
+	return r.client.Status().Update(ctx, resourceType)
+}
+
+// cleanGlobalResource removes the cleanup fields of the resource type from the object, the status is removed if the
+// resource type has no cleanup fields.
+func cleanGlobalResource(instance client.Object, resourceType *globalhubv1alpha3.GlobalResourceTypeSpec) {
+	object, ok := instance.(*unstructured.Unstructured)
+	if !ok {
+		panic("wrong instance passed to cleanGlobalResource: not an Unstructured")
+	}
+
+	cleanupFields := resourceType.CleanupFields
+	if len(cleanupFields) == 0 {
+		cleanupFields = []string{"status"}
+	}
+	for _, field := range cleanupFields {
+		unstructured.RemoveNestedField(object.Object, strings.Split(field, ".")...)
+	}
+}
+
+// areGlobalResourcesEqual compares the objects with the equality strategy of the resource type.
+func areGlobalResourcesEqual(instance1, instance2 client.Object,
+	resourceType *globalhubv1alpha3.GlobalResourceTypeSpec,
+) bool {
+	object1, ok1 := instance1.(*unstructured.Unstructured)
+	object2, ok2 := instance2.(*unstructured.Unstructured)
+
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	var contentMatch bool
+	if resourceType.EqualityStrategy == globalhubv1alpha3.ContentEquality {
+		content1, content2 := object1.DeepCopy().Object, object2.DeepCopy().Object
+		delete(content1, "metadata")
+		delete(content2, "metadata")
+		contentMatch = equality.Semantic.DeepEqual(content1, content2)
+	} else {
+		contentMatch = equality.Semantic.DeepEqual(object1.Object["spec"], object2.Object["spec"])
+	}
+	annotationsMatch := equality.Semantic.DeepEqual(instance1.GetAnnotations(), instance2.GetAnnotations())
+	labelsMatch := equality.Semantic.DeepEqual(instance1.GetLabels(), instance2.GetLabels())
+
+	return contentMatch && annotationsMatch && labelsMatch
+}
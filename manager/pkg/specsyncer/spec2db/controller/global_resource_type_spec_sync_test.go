@@ -0,0 +1,135 @@
+package controller_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+var _ = Describe("global resource types to database controller", func() {
+	const testSchema = "spec"
+	const testTable = "resources"
+
+	BeforeEach(func() {
+		By("Creating test table in the database")
+		_, err := postgresSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS spec;
+			CREATE TABLE IF NOT EXISTS  spec.resources (
+				id uuid NOT NULL,
+				api_group text NOT NULL,
+				api_version text NOT NULL,
+				kind text NOT NULL,
+				payload jsonb NOT NULL,
+				created_at timestamp without time zone DEFAULT now() NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL,
+				deleted boolean DEFAULT false NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("sync the resources of the registered kind", func() {
+		By("Create the global resource type of configmaps")
+		resourceType := &globalhubv1alpha3.GlobalResourceType{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmaps"},
+			Spec: globalhubv1alpha3.GlobalResourceTypeSpec{
+				Version:          "v1",
+				Kind:             "ConfigMap",
+				EqualityStrategy: globalhubv1alpha3.ContentEquality,
+			},
+		}
+		Expect(kubeClient.Create(ctx, resourceType)).Should(Succeed())
+
+		By("Create the global configmap")
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "global-config",
+				Namespace: config.GetDefaultNamespace(),
+				Labels:    map[string]string{constants.GlobalHubGlobalResourceLabel: ""},
+			},
+			Data: map[string]string{"foo": "bar"},
+		}
+		Expect(kubeClient.Create(ctx, configMap)).Should(Succeed())
+
+		By("Check the global resource type is registered")
+		Eventually(func() error {
+			if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(resourceType), resourceType); err != nil {
+				return err
+			}
+			if !meta.IsStatusConditionTrue(resourceType.Status.Conditions,
+				globalhubv1alpha3.GlobalResourceTypeRegistered) {
+				return fmt.Errorf("global resource type %s isn't registered", resourceType.GetName())
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+
+		By("Check the configmap is synced to the database")
+		Eventually(func() error {
+			rows, err := postgresSQL.GetConn().Query(ctx, fmt.Sprintf(
+				"SELECT payload FROM %s.%s WHERE api_group = '' AND api_version = 'v1' AND kind = 'ConfigMap'",
+				testSchema, testTable))
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				syncedConfigMap := &corev1.ConfigMap{}
+				if err := rows.Scan(syncedConfigMap); err != nil {
+					return err
+				}
+				if syncedConfigMap.GetNamespace() == configMap.GetNamespace() &&
+					syncedConfigMap.GetName() == configMap.GetName() && syncedConfigMap.Data["foo"] == "bar" {
+					return nil
+				}
+			}
+			return fmt.Errorf("not find configmap(%s) in database", configMap.GetName())
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+
+		By("Delete the global resource type and check the configmap is released")
+		Expect(kubeClient.Delete(ctx, resourceType)).Should(Succeed())
+		Eventually(func() error {
+			if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap); err != nil {
+				return err
+			}
+			if controllerutil.ContainsFinalizer(configMap, constants.GlobalHubCleanupFinalizer) {
+				return fmt.Errorf("configmap(%s) isn't released", configMap.GetName())
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("reject the built-in kind", func() {
+		resourceType := &globalhubv1alpha3.GlobalResourceType{
+			ObjectMeta: metav1.ObjectMeta{Name: "policies"},
+			Spec: globalhubv1alpha3.GlobalResourceTypeSpec{
+				Group:   "policy.open-cluster-management.io",
+				Version: "v1",
+				Kind:    "Policy",
+			},
+		}
+		Expect(kubeClient.Create(ctx, resourceType)).Should(Succeed())
+
+		Eventually(func() error {
+			if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(resourceType), resourceType); err != nil {
+				return err
+			}
+			condition := meta.FindStatusCondition(resourceType.Status.Conditions,
+				globalhubv1alpha3.GlobalResourceTypeRegistered)
+			if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "BuiltInKind" {
+				return fmt.Errorf("global resource type %s should be rejected", resourceType.GetName())
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -15,7 +15,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
-func AddPlacementController(mgr ctrl.Manager, specDB db.SpecDB) error {
+func AddPlacementController(mgr ctrl.Manager, specDB db.SpecDB, globalScheduling bool) error {
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1beta1.Placement{}).
 		WithEventFilter(GlobalResourcePredicate()).
@@ -26,7 +26,7 @@ func AddPlacementController(mgr ctrl.Manager, specDB db.SpecDB) error {
 			tableName:      "placements",
 			finalizerName:  constants.GlobalHubCleanupFinalizer,
 			createInstance: func() client.Object { return &clusterv1beta1.Placement{} },
-			cleanObject:    cleanPlacementObject,
+			cleanObject:    cleanPlacementObjectFunc(globalScheduling),
 			areEqual:       arePlacementsEqual,
 		}); err != nil {
 		return fmt.Errorf("failed to add placement controller to the manager: %w", err)
@@ -35,19 +35,27 @@ func AddPlacementController(mgr ctrl.Manager, specDB db.SpecDB) error {
 	return nil
 }
 
-func cleanPlacementObject(instance client.Object) {
-	placement, ok := instance.(*clusterv1beta1.Placement)
+// cleanPlacementObjectFunc keeps the placement controller of the leaf hub disabled if the placements are scheduled by
+// the global scheduler, otherwise the annotation is removed so that the placement controller can take over.
+func cleanPlacementObjectFunc(globalScheduling bool) func(instance client.Object) {
+	return func(instance client.Object) {
+		placement, ok := instance.(*clusterv1beta1.Placement)
 
-	if !ok {
-		panic("wrong instance passed to cleanPlacementStatus: not a Placement")
-	}
+		if !ok {
+			panic("wrong instance passed to cleanPlacementStatus: not a Placement")
+		}
 
-	if placement.Annotations != nil {
-		// remove the annotation so that the placement controller can take over
-		delete(placement.Annotations, clusterv1beta1.PlacementDisableAnnotation)
-	}
+		if globalScheduling {
+			if placement.Annotations == nil {
+				placement.Annotations = map[string]string{}
+			}
+			placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] = "true"
+		} else if placement.Annotations != nil {
+			delete(placement.Annotations, clusterv1beta1.PlacementDisableAnnotation)
+		}
 
-	placement.Status = clusterv1beta1.PlacementStatus{}
+		placement.Status = clusterv1beta1.PlacementStatus{}
+	}
 }
 
 func arePlacementsEqual(instance1, instance2 client.Object) bool {
@@ -62,7 +62,9 @@ var _ = Describe("placement controller", Ordered, func() {
 				if err := rows.Scan(placement); err != nil {
 					return err
 				}
-				if placement.Name == "test-placement-1" && placement.Annotations == nil {
+				// the placement controller of the leaf hub stays disabled, the placement is globally scheduled
+				if placement.Name == "test-placement-1" &&
+					placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] == "true" {
 					return nil
 				}
 			}
@@ -15,7 +15,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
-func AddPlacementRuleController(mgr ctrl.Manager, specDB db.SpecDB) error {
+func AddPlacementRuleController(mgr ctrl.Manager, specDB db.SpecDB, globalScheduling bool) error {
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&placementrulev1.PlacementRule{}).
 		WithEventFilter(GlobalResourcePredicate()).
@@ -26,7 +26,7 @@ func AddPlacementRuleController(mgr ctrl.Manager, specDB db.SpecDB) error {
 			tableName:      "placementrules",
 			finalizerName:  constants.GlobalHubCleanupFinalizer,
 			createInstance: func() client.Object { return &placementrulev1.PlacementRule{} },
-			cleanObject:    cleanPlacementRuleObject,
+			cleanObject:    cleanPlacementRuleObjectFunc(globalScheduling),
 			areEqual:       arePlacementRulesEqual,
 		}); err != nil {
 		return fmt.Errorf("failed to add placement rule controller to the manager: %w", err)
@@ -35,16 +35,25 @@ func AddPlacementRuleController(mgr ctrl.Manager, specDB db.SpecDB) error {
 	return nil
 }
 
-func cleanPlacementRuleObject(instance client.Object) {
-	placementRule, ok := instance.(*placementrulev1.PlacementRule)
+// cleanPlacementRuleObjectFunc keeps the scheduler name which the placementrule scheduler of the leaf hub ignores if
+// the placement rules are scheduled by the global scheduler, otherwise it's reset so that the placementrule scheduler
+// can take over.
+func cleanPlacementRuleObjectFunc(globalScheduling bool) func(instance client.Object) {
+	return func(instance client.Object) {
+		placementRule, ok := instance.(*placementrulev1.PlacementRule)
 
-	if !ok {
-		panic("wrong instance passed to cleanPlacementRuleStatus: not a PlacementRule")
-	}
-	// reset to empty so that the placementrule scheduler can take over
-	placementRule.Spec.SchedulerName = ""
+		if !ok {
+			panic("wrong instance passed to cleanPlacementRuleStatus: not a PlacementRule")
+		}
+
+		if globalScheduling {
+			placementRule.Spec.SchedulerName = constants.GlobalHubSchedulerName
+		} else {
+			placementRule.Spec.SchedulerName = ""
+		}
 
-	placementRule.Status = placementrulev1.PlacementRuleStatus{}
+		placementRule.Status = placementrulev1.PlacementRuleStatus{}
+	}
 }
 
 func arePlacementRulesEqual(instance1, instance2 client.Object) bool {
@@ -61,8 +61,9 @@ var _ = Describe("placementrules controller", Ordered, func() {
 				if err := rows.Scan(placementrule); err != nil {
 					return err
 				}
+				// the placementrule scheduler of the leaf hub ignores the global hub scheduler name
 				if placementrule.Name == "test-placementrule-1" &&
-					placementrule.Spec.SchedulerName == "" {
+					placementrule.Spec.SchedulerName == constants.GlobalHubSchedulerName {
 					return nil
 				}
 			}
@@ -4,9 +4,14 @@
 package controller
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	"open-cluster-management.io/governance-policy-propagator/controllers/common"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -54,7 +59,6 @@ func arePoliciesEqual(instance1, instance2 client.Object) bool {
 		return false
 	}
 
-	// TODO handle Template comparison later
 	policy1WithoutTemplates := policy1.DeepCopy()
 	policy1WithoutTemplates.Spec.PolicyTemplates = nil
 
@@ -63,5 +67,109 @@ func arePoliciesEqual(instance1, instance2 client.Object) bool {
 
 	labelsMatch := equality.Semantic.DeepEqual(instance1.GetLabels(), instance2.GetLabels())
 
-	return common.CompareSpecAndAnnotation(policy1WithoutTemplates, policy2WithoutTemplates) && labelsMatch
+	return common.CompareSpecAndAnnotation(policy1WithoutTemplates, policy2WithoutTemplates) && labelsMatch &&
+		arePolicyTemplatesEqual(policy1.Spec.PolicyTemplates, policy2.Spec.PolicyTemplates)
+}
+
+// policyTemplateDefaults are the fields defaulted by the CRDs of the policy templates, a field set to its default
+// value is equal to the field which isn't set. the nested fields are normalized before their parents, so the parent
+// left empty by its defaulted fields is equal to the parent which isn't set.
+var policyTemplateDefaults = map[string]map[string]interface{}{
+	"ConfigurationPolicy": {
+		"spec.pruneObjectBehavior":       "None",
+		"spec.namespaceSelector":         map[string]interface{}{},
+		"spec.namespaceSelector.include": []interface{}{},
+		"spec.namespaceSelector.exclude": []interface{}{},
+	},
+	"OperatorPolicy": {
+		"spec.complianceConfig":                          map[string]interface{}{},
+		"spec.complianceConfig.catalogSourceUnhealthy":   "Compliant",
+		"spec.complianceConfig.deploymentsUnavailable":   "NonCompliant",
+		"spec.complianceConfig.upgradesAvailable":        "Compliant",
+		"spec.removalBehavior":                           map[string]interface{}{},
+		"spec.removalBehavior.operatorGroups":            "DeleteIfUnused",
+		"spec.removalBehavior.subscriptions":             "Delete",
+		"spec.removalBehavior.clusterServiceVersions":    "Delete",
+		"spec.removalBehavior.customResourceDefinitions": "Keep",
+	},
+}
+
+// arePolicyTemplatesEqual compares the normalized object definitions of the policy templates, so that the fields
+// populated by the api server or defaulted by the CRDs don't make the templates differ.
+func arePolicyTemplatesEqual(templates1, templates2 []*policyv1.PolicyTemplate) bool {
+	if len(templates1) != len(templates2) {
+		return false
+	}
+
+	for i := range templates1 {
+		if (templates1[i] == nil) != (templates2[i] == nil) {
+			return false
+		}
+		if templates1[i] == nil {
+			continue
+		}
+
+		object1, err1 := normalizePolicyTemplate(templates1[i].ObjectDefinition)
+		object2, err2 := normalizePolicyTemplate(templates2[i].ObjectDefinition)
+		if err1 != nil || err2 != nil || !equality.Semantic.DeepEqual(object1, object2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizePolicyTemplate returns the object definition of the template without the status, the metadata populated
+// by the api server and the fields set to their defaults. the other fields are kept as they are, e.g. an empty field
+// of the objectDefinition of a ConfigurationPolicy isn't equal to the field which isn't set.
+func normalizePolicyTemplate(objectDefinition runtime.RawExtension) (map[string]interface{}, error) {
+	raw := objectDefinition.Raw
+	if raw == nil && objectDefinition.Object != nil {
+		var err error
+		if raw, err = json.Marshal(objectDefinition.Object); err != nil {
+			return nil, fmt.Errorf("failed to marshal the policy template: %w", err)
+		}
+	}
+
+	object := map[string]interface{}{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the policy template: %w", err)
+		}
+	}
+
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for key, value := range metadata {
+			switch key {
+			case "name", "namespace":
+			case "labels", "annotations":
+				if labels, ok := value.(map[string]interface{}); !ok || len(labels) == 0 {
+					delete(metadata, key)
+				}
+			default:
+				delete(metadata, key)
+			}
+		}
+	}
+
+	kind, _ := object["kind"].(string)
+	defaults := policyTemplateDefaults[kind]
+	paths := make([]string, 0, len(defaults))
+	for path := range defaults {
+		paths = append(paths, path)
+	}
+	// the nested fields first
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Count(paths[i], ".") > strings.Count(paths[j], ".")
+	})
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		value, found, err := unstructured.NestedFieldNoCopy(object, fields...)
+		if err == nil && found && equality.Semantic.DeepEqual(value, defaults[path]) {
+			unstructured.RemoveNestedField(object, fields...)
+		}
+	}
+
+	return object, nil
 }
@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+)
+
+func newTestPolicy(templates ...string) *policyv1.Policy {
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "default"},
+		Spec: policyv1.PolicySpec{
+			RemediationAction: policyv1.Inform,
+		},
+	}
+	for _, template := range templates {
+		policy.Spec.PolicyTemplates = append(policy.Spec.PolicyTemplates, &policyv1.PolicyTemplate{
+			ObjectDefinition: runtime.RawExtension{Raw: []byte(template)},
+		})
+	}
+	return policy
+}
+
+const configurationPolicyTemplate = `{
+	"apiVersion": "policy.open-cluster-management.io/v1",
+	"kind": "ConfigurationPolicy",
+	"metadata": {"name": "config-policy1"},
+	"spec": {
+		"remediationAction": "inform",
+		"severity": "low",
+		"object-templates": [{
+			"complianceType": "musthave",
+			"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}
+		}]
+	}
+}`
+
+func TestArePoliciesEqual(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy1  *policyv1.Policy
+		policy2  *policyv1.Policy
+		expected bool
+	}{
+		{
+			name:     "same templates",
+			policy1:  newTestPolicy(configurationPolicyTemplate),
+			policy2:  newTestPolicy(configurationPolicyTemplate),
+			expected: true,
+		},
+		{
+			name:    "template content changed",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1"},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "low",
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "dev"}}
+					}]
+				}
+			}`),
+			expected: false,
+		},
+		{
+			name:    "template severity changed",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1"},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "high",
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}
+					}]
+				}
+			}`),
+			expected: false,
+		},
+		{
+			name:     "template added",
+			policy1:  newTestPolicy(configurationPolicyTemplate),
+			policy2:  newTestPolicy(configurationPolicyTemplate, configurationPolicyTemplate),
+			expected: false,
+		},
+		{
+			name:    "defaulted and server populated fields",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1", "creationTimestamp": null, "labels": {}},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "low",
+					"pruneObjectBehavior": "None",
+					"namespaceSelector": {"include": [], "exclude": []},
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}
+					}]
+				},
+				"status": {"compliant": "Compliant"}
+			}`),
+			expected: true,
+		},
+		{
+			name: "operator policy defaults",
+			policy1: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1beta1",
+				"kind": "OperatorPolicy",
+				"metadata": {"name": "operator-policy1"},
+				"spec": {"remediationAction": "enforce", "subscription": {"name": "quay-operator"}}
+			}`),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1beta1",
+				"kind": "OperatorPolicy",
+				"metadata": {"name": "operator-policy1"},
+				"spec": {
+					"remediationAction": "enforce",
+					"subscription": {"name": "quay-operator"},
+					"removalBehavior": {"operatorGroups": "DeleteIfUnused", "subscriptions": "Delete"}
+				}
+			}`),
+			expected: true,
+		},
+		{
+			name:    "empty field of the object definition",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1"},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "low",
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"},
+							"spec": {}}
+					}]
+				}
+			}`),
+			expected: false,
+		},
+		{
+			name:    "empty string of the object definition",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1"},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "low",
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace",
+							"metadata": {"name": "prod", "labels": {"owner": ""}}}
+					}]
+				}
+			}`),
+			expected: false,
+		},
+		{
+			name:    "pruneObjectBehavior changed from the default",
+			policy1: newTestPolicy(configurationPolicyTemplate),
+			policy2: newTestPolicy(`{
+				"apiVersion": "policy.open-cluster-management.io/v1",
+				"kind": "ConfigurationPolicy",
+				"metadata": {"name": "config-policy1"},
+				"spec": {
+					"remediationAction": "inform",
+					"severity": "low",
+					"pruneObjectBehavior": "DeleteAll",
+					"object-templates": [{
+						"complianceType": "musthave",
+						"objectDefinition": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}
+					}]
+				}
+			}`),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, arePoliciesEqual(c.policy1, c.policy2))
+		})
+	}
+}
@@ -12,11 +12,14 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/spec2db/controller"
 )
 
-// AddSpec2DBControllers adds all the spec-to-db controllers to the Manager.
-func AddSpec2DBControllers(mgr ctrl.Manager, specDB db.SpecDB) error {
+// AddSpec2DBControllers adds all the spec-to-db controllers to the Manager, the global placements and placement rules
+// are kept away from the schedulers of the leaf hubs if they're scheduled by the global scheduler.
+func AddSpec2DBControllers(mgr ctrl.Manager, specDB db.SpecDB, globalScheduling bool) error {
 	addControllerFunctions := []func(ctrl.Manager, db.SpecDB) error{
 		controller.AddPolicyController,
-		controller.AddPlacementRuleController,
+		func(mgr ctrl.Manager, specDB db.SpecDB) error {
+			return controller.AddPlacementRuleController(mgr, specDB, globalScheduling)
+		},
 		controller.AddPlacementBindingController,
 		controller.AddHubOfHubsConfigController,
 		controller.AddApplicationController,
@@ -24,7 +27,11 @@ func AddSpec2DBControllers(mgr ctrl.Manager, specDB db.SpecDB) error {
 		controller.AddChannelController,
 		controller.AddManagedClusterSetController,
 		controller.AddManagedClusterSetBindingController,
-		controller.AddPlacementController,
+		func(mgr ctrl.Manager, specDB db.SpecDB) error {
+			return controller.AddPlacementController(mgr, specDB, globalScheduling)
+		},
+		controller.AddAgentConfigController,
+		controller.AddGlobalResourceTypeController,
 	}
 
 	for _, addControllerFunction := range addControllerFunctions {
@@ -8,13 +8,16 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db/postgresql"
 	specsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/syncer"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/syncer/rollout"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/syncer/scheduler"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/spec2db"
 )
 
 func AddSpecSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig,
 	processPostgreSQL *postgresql.PostgreSQL,
 ) error {
-	if err := spec2db.AddSpec2DBControllers(mgr, processPostgreSQL); err != nil {
+	if err := spec2db.AddSpec2DBControllers(mgr, processPostgreSQL,
+		managerConfig.SyncerConfig.GlobalSchedulingInterval > 0); err != nil {
 		return fmt.Errorf("failed to add spec-to-db controllers: %w", err)
 	}
 
@@ -26,5 +29,18 @@ func AddSpecSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig,
 		managerConfig.SyncerConfig.DeletedLabelsTrimmingInterval); err != nil {
 		return fmt.Errorf("failed to add status db watchers: %w", err)
 	}
+
+	// the global placements and placement rules aren't scheduled if the global scheduler is disabled
+	if managerConfig.SyncerConfig.GlobalSchedulingInterval > 0 {
+		if err := scheduler.AddGlobalScheduler(mgr, processPostgreSQL,
+			managerConfig.SyncerConfig.GlobalSchedulingInterval); err != nil {
+			return fmt.Errorf("failed to add global scheduler: %w", err)
+		}
+	}
+
+	if err := rollout.AddRolloutController(mgr, processPostgreSQL,
+		managerConfig.SyncerConfig.RolloutInterval); err != nil {
+		return fmt.Errorf("failed to add rollout controller: %w", err)
+	}
 	return nil
 }
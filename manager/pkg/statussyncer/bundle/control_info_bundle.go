@@ -1,15 +1,39 @@
 package bundle
 
-import "github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+import (
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
 
 // NewControlInfoBundle creates a new instance of ControlInfoBundle.
 func NewControlInfoBundle() status.Bundle {
 	return &ControlInfoBundle{}
 }
 
-// ControlInfoBundle abstracts management of control info bundle.
+// NewControlInfoSchemaDecoders returns the decoders of the control info bundles of the other schema versions. the
+// legacy bundle has no capabilities, and the bundle of the next schema version is decoded by the known fields, so the
+// manager always learns the capabilities of the agents which are upgraded before it.
+func NewControlInfoSchemaDecoders() map[int]registration.BundleDecodeFunc {
+	decode := registration.NewDecoder(NewControlInfoBundle)
+	return map[int]registration.BundleDecodeFunc{
+		status.LegacySchemaVersion: func(contentType string, payload []byte) (status.Bundle, error) {
+			receivedBundle, err := decode(contentType, payload)
+			if err != nil {
+				return nil, err
+			}
+			if controlInfoBundle := receivedBundle.(*ControlInfoBundle); controlInfoBundle.Capabilities == nil {
+				controlInfoBundle.Capabilities = status.NewLegacyCapabilities()
+			}
+			return receivedBundle, nil
+		},
+		status.SchemaVersion + 1: decode,
+	}
+}
+
+// ControlInfoBundle abstracts management of control info bundle, it holds the capabilities advertised by the agent.
 type ControlInfoBundle struct {
 	baseBundle
+	Capabilities *status.Capabilities `json:"capabilities,omitempty"`
 }
 
 // GetObjects returns the objects in the bundle.
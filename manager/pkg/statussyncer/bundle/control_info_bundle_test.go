@@ -0,0 +1,35 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+func TestControlInfoSchemaDecoders(t *testing.T) {
+	bundleRegistration := &registration.BundleRegistration{
+		MsgID:            "ControlInfo",
+		CreateBundleFunc: NewControlInfoBundle,
+		SchemaDecoders:   NewControlInfoSchemaDecoders(),
+	}
+
+	// the legacy agent doesn't advertise the capabilities
+	receivedBundle, err := bundleRegistration.Decode(0, encoding.ContentTypeJSON,
+		[]byte(`{"leafHubName":"hub1","bundleVersion":{"incarnation":1,"generation":2}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, status.NewLegacyCapabilities(), receivedBundle.(*ControlInfoBundle).Capabilities)
+
+	// the newer agent adds the fields unknown to the manager
+	receivedBundle, err = bundleRegistration.Decode(status.SchemaVersion+1, encoding.ContentTypeJSON,
+		[]byte(`{"leafHubName":"hub1","capabilities":{"schemaVersion":3,"minSchemaVersion":2},"extra":{}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &status.Capabilities{SchemaVersion: 3, MinSchemaVersion: 2},
+		receivedBundle.(*ControlInfoBundle).Capabilities)
+
+	_, err = bundleRegistration.Decode(status.SchemaVersion+2, encoding.ContentTypeJSON, []byte(`{}`))
+	assert.ErrorIs(t, err, registration.ErrUnsupportedSchemaVersion)
+}
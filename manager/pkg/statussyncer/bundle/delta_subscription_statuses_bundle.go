@@ -0,0 +1,110 @@
+package bundle
+
+import (
+	"fmt"
+
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// NewDeltaSubscriptionStatusesBundle creates a new instance of DeltaSubscriptionStatusesBundle.
+func NewDeltaSubscriptionStatusesBundle() status.Bundle {
+	return &DeltaSubscriptionStatusesBundle{}
+}
+
+// DeltaSubscriptionStatusesBundle abstracts management of delta subscription-statuses bundle.
+type DeltaSubscriptionStatusesBundle struct {
+	status.BaseDeltaSubscriptionStatusesBundle
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (bundle *DeltaSubscriptionStatusesBundle) GetLeafHubName() string {
+	return bundle.LeafHubName
+}
+
+// GetObjects returns the updated objects in the bundle.
+func (bundle *DeltaSubscriptionStatusesBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(bundle.Objects))
+	for i, obj := range bundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetDeletedObjectIDs returns the ids of the objects that were deleted.
+func (bundle *DeltaSubscriptionStatusesBundle) GetDeletedObjectIDs() []string {
+	return bundle.DeletedObjectIDs
+}
+
+// GetVersion returns the bundle version.
+func (bundle *DeltaSubscriptionStatusesBundle) GetVersion() *status.BundleVersion {
+	return bundle.BundleVersion
+}
+
+// GetDependencyVersion returns the bundle dependency required version.
+func (bundle *DeltaSubscriptionStatusesBundle) GetDependencyVersion() *status.BundleVersion {
+	return bundle.BaseBundleVersion
+}
+
+// InheritEvents updates the content of this bundle with that of another older one (this bundle is the source of truth).
+func (bundle *DeltaSubscriptionStatusesBundle) InheritEvents(olderBundle status.Bundle) error {
+	if olderBundle == nil {
+		return nil
+	}
+
+	oldDeltaBundle, ok := olderBundle.(*DeltaSubscriptionStatusesBundle)
+	if !ok {
+		return fmt.Errorf("%w - expecting %s", errWrongType, "DeltaSubscriptionStatusesBundle")
+	}
+
+	if !oldDeltaBundle.GetDependencyVersion().Equals(bundle.GetDependencyVersion()) {
+		// if old bundle's dependency version is not equal then its content is covered by a complete-state baseline.
+		return nil
+	}
+
+	bundle.inheritObjects(oldDeltaBundle.Objects, oldDeltaBundle.DeletedObjectIDs)
+
+	return nil
+}
+
+// inheritObjects keeps the updates and the deletions of the older bundle that are not overridden by this bundle.
+func (bundle *DeltaSubscriptionStatusesBundle) inheritObjects(oldObjects []*appsv1alpha1.SubscriptionStatus,
+	oldDeletedObjectIDs []string,
+) {
+	handledIDs := make(map[string]struct{}, len(bundle.Objects)+len(bundle.DeletedObjectIDs))
+	for _, obj := range bundle.Objects {
+		handledIDs[getSubscriptionStatusID(obj)] = struct{}{}
+	}
+
+	for _, objID := range bundle.DeletedObjectIDs {
+		handledIDs[objID] = struct{}{}
+	}
+
+	survivingOldObjects := make([]*appsv1alpha1.SubscriptionStatus, 0, len(oldObjects))
+	for _, obj := range oldObjects {
+		if _, found := handledIDs[getSubscriptionStatusID(obj)]; !found {
+			survivingOldObjects = append(survivingOldObjects, obj)
+		}
+	}
+
+	survivingOldDeletedObjectIDs := make([]string, 0, len(oldDeletedObjectIDs))
+	for _, objID := range oldDeletedObjectIDs {
+		if _, found := handledIDs[objID]; !found {
+			survivingOldDeletedObjectIDs = append(survivingOldDeletedObjectIDs, objID)
+		}
+	}
+
+	bundle.Objects = append(survivingOldObjects, bundle.Objects...)
+	bundle.DeletedObjectIDs = append(survivingOldDeletedObjectIDs, bundle.DeletedObjectIDs...)
+}
+
+func getSubscriptionStatusID(obj *appsv1alpha1.SubscriptionStatus) string {
+	if originOwnerReference, found := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]; found {
+		return originOwnerReference
+	}
+
+	return string(obj.GetUID())
+}
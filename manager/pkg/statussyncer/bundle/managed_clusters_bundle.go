@@ -26,3 +26,13 @@ func (bundle *ManagedClustersStatusBundle) GetObjects() []interface{} {
 
 	return result
 }
+
+// UnmarshalProto decodes the ManagedClustersBundle message into the bundle.
+func (bundle *ManagedClustersStatusBundle) UnmarshalProto(payload []byte) error {
+	leafHubName, version, clusters, err := status.UnmarshalManagedClustersProto(payload)
+	if err != nil {
+		return err
+	}
+	bundle.LeafHubName, bundle.BundleVersion, bundle.Objects = leafHubName, version, clusters
+	return nil
+}
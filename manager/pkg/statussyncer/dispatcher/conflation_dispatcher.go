@@ -48,15 +48,17 @@ func (dispatcher *ConflationDispatcher) dispatch(ctx context.Context) {
 
 		default: // as long as context wasn't cancelled, continue and try to read bundles to process
 			conflationUnit := dispatcher.conflationReadyQueue.BlockingDequeue() // blocking if no CU has ready bundle
-			dbWorker, err := dispatcher.dbWorkerPool.Acquire()
+			// get the bundle before acquiring the worker, so the CU removed in the meantime doesn't hold a worker
+			bundle, bundleMetadata, handlerFunction, err := conflationUnit.GetNext()
 			if err != nil {
-				dispatcher.log.Error(err, "failed to get dbWorker")
+				dispatcher.log.Error(err, "failed to get next bundle")
 				continue
 			}
 
-			bundle, bundleMetadata, handlerFunction, err := conflationUnit.GetNext()
+			dbWorker, err := dispatcher.dbWorkerPool.Acquire()
 			if err != nil {
-				dispatcher.log.Error(err, "failed to get next bundle")
+				dispatcher.log.Error(err, "failed to get dbWorker")
+				conflationUnit.ReportResult(bundleMetadata, err) // the bundle is ready to be processed again
 				continue
 			}
 
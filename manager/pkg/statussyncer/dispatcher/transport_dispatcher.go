@@ -2,7 +2,6 @@ package dispatcher
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"strings"
 
@@ -10,6 +9,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
 	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -64,29 +64,40 @@ func (d *TransportDispatcher) dispatch(ctx context.Context) {
 			if len(msgIDTokens) != 2 {
 				d.log.Error(errors.New("message ID format is bad"),
 					"expecting MsgID format LH_ID.MSG_ID", "message", message)
+				registration.RecordRejectedBundle("", "", registration.RejectReasonBadMessageID)
 				continue
 			}
 
-			msgID := msgIDTokens[1]
-			if _, found := d.bundleRegistrations[msgID]; !found {
+			leafHubName, msgID := msgIDTokens[0], msgIDTokens[1]
+			bundleRegistration, found := d.bundleRegistrations[msgID]
+			if !found {
 				// no one registered for this msg id
 				d.log.Error(errors.New("msgID not found"),
 					"no bundle-registration available", "message", message)
+				registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonUnregistered)
 				continue
 			}
 
-			if !d.bundleRegistrations[msgID].Predicate() {
+			if !bundleRegistration.Predicate() {
 				d.log.Error(errors.New("predicate with false"),
 					"bundle with false predicate", "message", message)
 				continue // bundle-registration predicate is false, do not send the update in the channel
 			}
 
-			receivedBundle := d.bundleRegistrations[msgID].CreateBundleFunc()
-			if err := json.Unmarshal(message.Payload, receivedBundle); err != nil {
-				d.log.Error(errors.New("unmarshal error"),
-					"parse message.payload error", "message", message)
+			schemaVersion := status.ResolveSchemaVersion(message.SchemaVersion)
+			receivedBundle, err := bundleRegistration.Decode(schemaVersion, message.ContentType, message.Payload)
+			if errors.Is(err, registration.ErrUnsupportedSchemaVersion) {
+				d.log.Error(err, "reject the bundle of the unsupported schema version", "messageID", message.ID,
+					"version", message.Version)
+				registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonUnsupportedSchemaVersion)
+				continue
+			} else if err != nil {
+				d.log.Error(err, "parse message.payload error", "messageID", message.ID, "version", message.Version,
+					"schemaVersion", schemaVersion)
+				registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonDecodeError)
 				continue
 			}
+			registration.RecordReceivedBundle(msgID, schemaVersion)
 
 			d.statistics.IncrementNumberOfReceivedBundles(receivedBundle)
 			// d.conflationManager.Insert(receivedBundle, NewBundleMetadata(message.TopicPartition.Partition,
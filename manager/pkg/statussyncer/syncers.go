@@ -7,6 +7,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
 	statusbundle "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/bundle"
 	configctl "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dispatcher"
@@ -39,6 +40,15 @@ func AddStatusSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig) (
 	conflationManager := conflator.NewConflationManager(conflationReadyQueue,
 		requireInitialDependencyChecks(managerConfig.TransportConfig.TransportType), stats)
 
+	// the offboarded leaf hubs are discarded by the conflation manager until they're onboarded again
+	if managerConfig.HubManagementConfig != nil {
+		if err := hubmanagement.AddHubManagement(mgr, conflationManager,
+			managerConfig.HubManagementConfig.OffboardingRetention,
+			managerConfig.HubManagementConfig.OffboardingInterval); err != nil {
+			return nil, fmt.Errorf("failed to add hub management: %w", err)
+		}
+	}
+
 	// database layer initialization - worker pool + connection pool
 	dbWorkerPool, err := workerpool.NewDBWorkerPool(managerConfig.DatabaseConfig, stats)
 	if err != nil {
@@ -69,6 +79,7 @@ func AddStatusSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig) (
 	// register db syncers create bundle functions within transport and handler functions within dispatcher
 	dbSyncers := []dbsyncer.DBSyncer{
 		dbsyncer.NewManagedClustersDBSyncer(ctrl.Log.WithName("managed-clusters-db-syncer")),
+		dbsyncer.NewManagedClusterAddOnsDBSyncer(ctrl.Log.WithName("managed-cluster-addons-db-syncer")),
 		dbsyncer.NewPoliciesDBSyncer(ctrl.Log.WithName("policies-db-syncer"), config),
 		dbsyncer.NewPlacementRulesDBSyncer(ctrl.Log.WithName("placement-rules-db-syncer")),
 		dbsyncer.NewPlacementsDBSyncer(ctrl.Log.WithName("placements-db-syncer")),
@@ -82,6 +93,9 @@ func AddStatusSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig) (
 		dbsyncer.NewControlInfoDBSyncer(ctrl.Log.WithName("control-info-db-syncer")),
 		dbsyncer.NewLocalPoliciesStatusEventSyncer(
 			ctrl.Log.WithName("local-policies-status-event-syncer"), config),
+		dbsyncer.NewAppliedStatusDBSyncer(ctrl.Log.WithName("applied-status-db-syncer")),
+		dbsyncer.NewArgoCDApplicationDBSyncer(ctrl.Log.WithName("argocd-application-db-syncer")),
+		dbsyncer.NewPolicyReportDBSyncer(ctrl.Log.WithName("policy-report-db-syncer")),
 	}
 
 	for _, dbsyncerObj := range dbSyncers {
@@ -165,6 +179,7 @@ func addStatisticController(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 			helpers.GetBundleType(&statusbundle.PlacementsBundle{}),
 			helpers.GetBundleType(&statusbundle.PlacementDecisionsBundle{}),
 			helpers.GetBundleType(&statusbundle.SubscriptionStatusesBundle{}),
+			helpers.GetBundleType(&statusbundle.DeltaSubscriptionStatusesBundle{}),
 			helpers.GetBundleType(&statusbundle.SubscriptionReportsBundle{}),
 			helpers.GetBundleType(&statusbundle.ControlInfoBundle{}),
 			helpers.GetBundleType(&statusbundle.LocalPolicySpecBundle{}),
@@ -173,6 +188,11 @@ func addStatisticController(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 			helpers.GetBundleType(&statusbundle.LocalPlacementRulesBundle{}),
 			helpers.GetBundleType(&status.BaseLeafHubClusterInfoStatusBundle{}),
 			helpers.GetBundleType(&status.BaseClusterPolicyStatusEventBundle{}),
+			helpers.GetBundleType(&status.BaseAppliedStatusBundle{}),
+			helpers.GetBundleType(&status.BaseArgoCDApplicationStatusBundle{}),
+			helpers.GetBundleType(&status.BaseArgoCDApplicationSetStatusBundle{}),
+			helpers.GetBundleType(&status.BaseManagedClusterAddOnStatusBundle{}),
+			helpers.GetBundleType(&status.BasePolicyReportBundle{}),
 		})
 	if err := mgr.Add(stats); err != nil {
 		return nil, fmt.Errorf("failed to add statistics to manager - %w", err)
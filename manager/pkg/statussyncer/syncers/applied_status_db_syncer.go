@@ -0,0 +1,115 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// NewAppliedStatusDBSyncer creates a new instance of AppliedStatusDBSyncer.
+func NewAppliedStatusDBSyncer(log logr.Logger) DBSyncer {
+	dbSyncer := &AppliedStatusDBSyncer{
+		log:              log,
+		createBundleFunc: status.NewAppliedStatusBundle,
+	}
+
+	log.Info("initialized applied status db syncer")
+
+	return dbSyncer
+}
+
+// AppliedStatusDBSyncer implements the applied status of global resources transport to db sync.
+type AppliedStatusDBSyncer struct {
+	log              logr.Logger
+	createBundleFunc status.CreateBundleFunction
+}
+
+// RegisterCreateBundleFunctions registers create bundle functions within the transport instance.
+func (syncer *AppliedStatusDBSyncer) RegisterCreateBundleFunctions(transportDispatcher BundleRegisterable) {
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.AppliedStatusMsgKey,
+		CreateBundleFunc: syncer.createBundleFunc,
+		Predicate:        func() bool { return true }, // always get applied status bundles
+	})
+}
+
+// RegisterBundleHandlerFunctions registers bundle handler functions within the conflation manager.
+// the leaf hub sends the applied status of all the global resources it received, so whatever is in the db for the
+// leaf hub and cannot be found in the bundle has to be deleted from the database.
+func (syncer *AppliedStatusDBSyncer) RegisterBundleHandlerFunctions(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.AppliedStatusPriority,
+		bundle.CompleteStateMode,
+		helpers.GetBundleType(syncer.createBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handleAppliedStatusBundle(ctx, bundle)
+		},
+	))
+}
+
+func (syncer *AppliedStatusDBSyncer) handleAppliedStatusBundle(ctx context.Context, bundle status.Bundle) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	db := database.GetGorm()
+	// https://gorm.io/docs/transactions.html
+	err := db.Transaction(func(tx *gorm.DB) error {
+		resourceIDs := make([]string, 0, len(bundle.GetObjects()))
+		for _, object := range bundle.GetObjects() {
+			appliedStatus, ok := object.(*status.AppliedStatus)
+			if !ok {
+				continue
+			}
+			resourceIDs = append(resourceIDs, appliedStatus.ResourceID)
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "resource_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"kind", "name", "namespace", "bundle_version", "applied", "error", "dry_run", "drifted", "diff",
+				}),
+			}).Create(&models.AppliedStatus{
+				ResourceID:    appliedStatus.ResourceID,
+				LeafHubName:   leafHubName,
+				Kind:          appliedStatus.Kind,
+				Name:          appliedStatus.Name,
+				Namespace:     appliedStatus.Namespace,
+				BundleVersion: appliedStatus.BundleVersion,
+				Applied:       appliedStatus.Applied,
+				Error:         appliedStatus.Error,
+				DryRun:        appliedStatus.DryRun,
+				Drifted:       appliedStatus.Drifted,
+				Diff:          appliedStatus.Diff,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the statuses of the resources that are no longer on the leaf hub
+		deleteTx := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(resourceIDs) > 0 {
+			deleteTx = deleteTx.Where("resource_id NOT IN ?", resourceIDs)
+		}
+		return deleteTx.Delete(&models.AppliedStatus{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub '%s' applied status bundle - %w", leafHubName, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/appliedstatus"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var _ = Describe("AppliedStatusDbSyncer", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		resourceID  = "a789c8c1-137b-4b78-9412-9f101b08cc91"
+		testSchema  = database.StatusSchema
+		testTable   = database.AppliedStatusesTableName
+		messageKey  = constants.AppliedStatusMsgKey
+	)
+
+	BeforeAll(func() {
+		By("Create applied_statuses table in database")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS status;
+			CREATE TABLE IF NOT EXISTS status.applied_statuses (
+				resource_id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				kind character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63),
+				bundle_version character varying(63) NOT NULL,
+				applied boolean NOT NULL,
+				error text,
+				dry_run boolean DEFAULT false NOT NULL,
+				drifted boolean DEFAULT false NOT NULL,
+				diff text,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS applied_statuses_leaf_hub_name_resource_id_idx ON
+				status.applied_statuses USING btree (leaf_hub_name, resource_id);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check whether the tables are created")
+		Eventually(func() error {
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, "SELECT * FROM pg_tables")
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				columnValues, _ := rows.Values()
+				schema := columnValues[0]
+				table := columnValues[1]
+				if schema == testSchema && table == testTable {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to create table %s.%s", testSchema, testTable)
+		}, 10*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("sync the applied status bundle", func() {
+		By("Create applied status bundle")
+		statusBundle := appliedstatus.NewBundle(leafHubName, 0)
+		statusBundle.UpdateAppliedStatus(&status.AppliedStatus{
+			ResourceID:    resourceID,
+			Kind:          "Policy",
+			Name:          "policy-limitrange",
+			Namespace:     "default",
+			BundleVersion: "2022-10-26_08-32-00.739891",
+			Applied:       false,
+			Error:         "admission webhook denied the request",
+		})
+
+		By("Create transport message")
+		payloadBytes, err := json.Marshal(statusBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, messageKey)
+		transportMessage := &transport.Message{
+			Key:     transportMessageKey,
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: statusBundle.GetBundleVersion().String(),
+			Payload: payloadBytes,
+		}
+
+		By("Sync message with transport")
+		err = producer.Send(ctx, transportMessage)
+		Expect(err).Should(Succeed())
+
+		By("Check the applied statuses table")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT leaf_hub_name,resource_id,applied,error FROM %s.%s",
+				testSchema, testTable)
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, querySql)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var hubName, id, applyError string
+				var applied bool
+				if err := rows.Scan(&hubName, &id, &applied, &applyError); err != nil {
+					return err
+				}
+				if hubName == leafHubName && id == resourceID && !applied && applyError != "" {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,199 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// NewArgoCDApplicationDBSyncer creates a new instance of ArgoCDApplicationDBSyncer.
+func NewArgoCDApplicationDBSyncer(log logr.Logger) DBSyncer {
+	dbSyncer := &ArgoCDApplicationDBSyncer{
+		log:                            log,
+		createApplicationBundleFunc:    status.NewArgoCDApplicationStatusBundle,
+		createApplicationSetBundleFunc: status.NewArgoCDApplicationSetStatusBundle,
+	}
+
+	log.Info("initialized argocd application db syncer")
+
+	return dbSyncer
+}
+
+// ArgoCDApplicationDBSyncer implements the Argo CD Application and ApplicationSet status transport to db sync.
+type ArgoCDApplicationDBSyncer struct {
+	log                            logr.Logger
+	createApplicationBundleFunc    status.CreateBundleFunction
+	createApplicationSetBundleFunc status.CreateBundleFunction
+}
+
+// RegisterCreateBundleFunctions registers create bundle functions within the transport instance.
+func (syncer *ArgoCDApplicationDBSyncer) RegisterCreateBundleFunctions(transportDispatcher BundleRegisterable) {
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.ArgoCDApplicationMsgKey,
+		CreateBundleFunc: syncer.createApplicationBundleFunc,
+		Predicate:        func() bool { return true }, // always get argocd application bundles
+	})
+
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.ArgoCDApplicationSetMsgKey,
+		CreateBundleFunc: syncer.createApplicationSetBundleFunc,
+		Predicate:        func() bool { return true }, // always get argocd applicationset bundles
+	})
+}
+
+// RegisterBundleHandlerFunctions registers bundle handler functions within the conflation manager.
+// the leaf hub sends all of its applications (applicationsets), so whatever is in the db for the leaf hub and cannot
+// be found in the bundle has to be deleted from the database.
+func (syncer *ArgoCDApplicationDBSyncer) RegisterBundleHandlerFunctions(
+	conflationManager *conflator.ConflationManager,
+) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.ArgoCDApplicationPriority,
+		bundle.CompleteStateMode,
+		helpers.GetBundleType(syncer.createApplicationBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handleApplicationBundle(ctx, bundle)
+		},
+	))
+
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.ArgoCDApplicationSetPriority,
+		bundle.CompleteStateMode,
+		helpers.GetBundleType(syncer.createApplicationSetBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handleApplicationSetBundle(ctx, bundle)
+		},
+	))
+}
+
+func (syncer *ArgoCDApplicationDBSyncer) handleApplicationBundle(ctx context.Context, bundle status.Bundle) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		applicationIDs := make([]string, 0, len(bundle.GetObjects()))
+		for _, object := range bundle.GetObjects() {
+			application, ok := object.(*status.ArgoCDApplicationStatus)
+			if !ok {
+				continue
+			}
+			applicationIDs = append(applicationIDs, application.ApplicationID)
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"name", "namespace", "project", "repo_url", "target_revision", "revision", "destination_server",
+					"destination_name", "destination_namespace", "sync_status", "health_status", "health_message",
+					"operation_phase", "reconciled_at",
+				}),
+			}).Create(&models.ArgoCDApplication{
+				ID:                   application.ApplicationID,
+				LeafHubName:          leafHubName,
+				Name:                 application.Name,
+				Namespace:            application.Namespace,
+				Project:              application.Project,
+				RepoURL:              application.RepoURL,
+				TargetRevision:       application.TargetRevision,
+				Revision:             application.Revision,
+				DestinationServer:    application.DestinationServer,
+				DestinationName:      application.DestinationName,
+				DestinationNamespace: application.DestinationNamespace,
+				SyncStatus:           application.SyncStatus,
+				HealthStatus:         application.HealthStatus,
+				HealthMessage:        application.HealthMessage,
+				OperationPhase:       application.OperationPhase,
+				ReconciledAt:         application.ReconciledAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the applications that are no longer on the leaf hub
+		deleteTx := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(applicationIDs) > 0 {
+			deleteTx = deleteTx.Where("id NOT IN ?", applicationIDs)
+		}
+		return deleteTx.Delete(&models.ArgoCDApplication{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub '%s' argocd application bundle - %w", leafHubName, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
+
+func (syncer *ArgoCDApplicationDBSyncer) handleApplicationSetBundle(ctx context.Context, bundle status.Bundle) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		applicationSetIDs := make([]string, 0, len(bundle.GetObjects()))
+		for _, object := range bundle.GetObjects() {
+			applicationSet, ok := object.(*status.ArgoCDApplicationSetStatus)
+			if !ok {
+				continue
+			}
+			applicationSetIDs = append(applicationSetIDs, applicationSet.ApplicationSetID)
+
+			applications := applicationSet.Applications
+			if applications == nil {
+				applications = []string{}
+			}
+			applicationsBytes, err := json.Marshal(applications)
+			if err != nil {
+				return err
+			}
+
+			err = tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"name", "namespace", "applications", "resources_up_to_date", "error_message",
+				}),
+			}).Create(&models.ArgoCDApplicationSet{
+				ID:                applicationSet.ApplicationSetID,
+				LeafHubName:       leafHubName,
+				Name:              applicationSet.Name,
+				Namespace:         applicationSet.Namespace,
+				Applications:      applicationsBytes,
+				ResourcesUpToDate: applicationSet.ResourcesUpToDate,
+				ErrorMessage:      applicationSet.ErrorMessage,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the applicationsets that are no longer on the leaf hub
+		deleteTx := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(applicationSetIDs) > 0 {
+			deleteTx = deleteTx.Where("id NOT IN ?", applicationSetIDs)
+		}
+		return deleteTx.Delete(&models.ArgoCDApplicationSet{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub '%s' argocd applicationset bundle - %w", leafHubName, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
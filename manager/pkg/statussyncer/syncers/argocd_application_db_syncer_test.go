@@ -0,0 +1,134 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/argocd"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var _ = Describe("ArgoCDApplicationDbSyncer", Ordered, func() {
+	const (
+		leafHubName   = "hub1"
+		applicationID = "6f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11"
+		testSchema    = database.StatusSchema
+		testTable     = database.ArgoCDApplicationsTableName
+		messageKey    = constants.ArgoCDApplicationMsgKey
+	)
+
+	BeforeAll(func() {
+		By("Create argocd_applications table in database")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS status;
+			CREATE TABLE IF NOT EXISTS status.argocd_applications (
+				id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63) NOT NULL,
+				project character varying(253),
+				repo_url text,
+				target_revision character varying(253),
+				revision character varying(253),
+				destination_server text,
+				destination_name character varying(253),
+				destination_namespace character varying(63),
+				sync_status character varying(63),
+				health_status character varying(63),
+				health_message text,
+				operation_phase character varying(63),
+				reconciled_at character varying(63),
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS argocd_applications_leaf_hub_name_id_idx ON
+				status.argocd_applications USING btree (leaf_hub_name, id);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check whether the tables are created")
+		Eventually(func() error {
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, "SELECT * FROM pg_tables")
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				columnValues, _ := rows.Values()
+				schema := columnValues[0]
+				table := columnValues[1]
+				if schema == testSchema && table == testTable {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to create table %s.%s", testSchema, testTable)
+		}, 10*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("sync the argocd application bundle", func() {
+		By("Create argocd application bundle")
+		statusBundle := argocd.NewApplicationStatusBundle(leafHubName, 0)
+		statusBundle.UpdateObject(&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":            "guestbook",
+				"namespace":       "openshift-gitops",
+				"uid":             applicationID,
+				"resourceVersion": "1",
+			},
+			"spec": map[string]interface{}{
+				"project": "default",
+			},
+			"status": map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "OutOfSync"},
+				"health": map[string]interface{}{"status": "Progressing"},
+			},
+		}})
+
+		By("Create transport message")
+		payloadBytes, err := json.Marshal(statusBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, messageKey)
+		transportMessage := &transport.Message{
+			Key:     transportMessageKey,
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: statusBundle.GetBundleVersion().String(),
+			Payload: payloadBytes,
+		}
+
+		By("Sync message with transport")
+		err = producer.Send(ctx, transportMessage)
+		Expect(err).Should(Succeed())
+
+		By("Check the argocd applications table")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT leaf_hub_name,id,sync_status,health_status FROM %s.%s",
+				testSchema, testTable)
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, querySql)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var hubName, id, syncStatus, healthStatus string
+				if err := rows.Scan(&hubName, &id, &syncStatus, &healthStatus); err != nil {
+					return err
+				}
+				if hubName == leafHubName && id == applicationID && syncStatus == "OutOfSync" &&
+					healthStatus == "Progressing" {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+})
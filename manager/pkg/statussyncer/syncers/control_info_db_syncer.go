@@ -2,9 +2,11 @@ package dbsyncer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"gorm.io/gorm/clause"
 
 	statusbundle "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
@@ -15,6 +17,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 // NewControlInfoDBSyncer creates a new instance of ControlInfoDBSyncer.
@@ -41,6 +44,7 @@ func (syncer *ControlInfoDBSyncer) RegisterCreateBundleFunctions(transportDispat
 		MsgID:            constants.ControlInfoMsgKey,
 		CreateBundleFunc: syncer.createBundleFunc,
 		Predicate:        func() bool { return true }, // always get control info bundles
+		SchemaDecoders:   statusbundle.NewControlInfoSchemaDecoders(),
 	})
 }
 
@@ -69,7 +73,42 @@ func (syncer *ControlInfoDBSyncer) handleControlInfoBundle(ctx context.Context,
 		return fmt.Errorf("failed handling control info bundle of leaf hub '%s' - %w", leafHubName, err)
 	}
 
+	if controlInfoBundle, ok := bundle.(*statusbundle.ControlInfoBundle); ok && controlInfoBundle.Capabilities != nil {
+		if err := syncer.updateCapabilities(leafHubName, controlInfoBundle.Capabilities); err != nil {
+			return fmt.Errorf("failed updating capabilities of leaf hub '%s' - %w", leafHubName, err)
+		}
+	}
+
 	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
 
 	return nil
 }
+
+// updateCapabilities records the capabilities advertised by the agent, the agent speaking a schema version the manager
+// doesn't know is reported, so the manager can be upgraded before its bundles are rejected.
+func (syncer *ControlInfoDBSyncer) updateCapabilities(leafHubName string, capabilities *status.Capabilities) error {
+	if capabilities.SchemaVersion > status.SchemaVersion {
+		syncer.log.Info("the agent sends a newer bundle schema version than the manager supports",
+			"leafHubName", leafHubName, "agentSchemaVersion", capabilities.SchemaVersion,
+			"managerSchemaVersion", status.SchemaVersion)
+	}
+	if capabilities.MinSchemaVersion > status.SchemaVersion {
+		syncer.log.Info("the agent doesn't support the bundle schema version of the manager",
+			"leafHubName", leafHubName, "agentMinSchemaVersion", capabilities.MinSchemaVersion,
+			"managerSchemaVersion", status.SchemaVersion)
+	}
+
+	payload, err := json.Marshal(capabilities)
+	if err != nil {
+		return err
+	}
+
+	return database.GetGorm().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leaf_hub_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schema_version", "payload"}),
+	}).Create(&models.LeafHubCapability{
+		LeafHubName:   leafHubName,
+		SchemaVersion: capabilities.SchemaVersion,
+		Payload:       payload,
+	}).Error
+}
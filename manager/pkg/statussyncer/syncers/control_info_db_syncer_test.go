@@ -31,6 +31,13 @@ var _ = Describe("leaf hubs heartbeats", Ordered, func() {
 				last_timestamp timestamp without time zone DEFAULT now() NOT NULL
 			);
 			CREATE UNIQUE INDEX IF NOT EXISTS leaf_hub_heartbeats_leaf_hub_idx ON status.leaf_hub_heartbeats USING btree (leaf_hub_name);
+			CREATE TABLE IF NOT EXISTS status.leaf_hub_capabilities (
+				leaf_hub_name character varying(63) NOT NULL,
+				schema_version integer NOT NULL,
+				payload jsonb NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS leaf_hub_capabilities_leaf_hub_idx ON status.leaf_hub_capabilities (leaf_hub_name);
 		`)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -58,6 +65,7 @@ var _ = Describe("leaf hubs heartbeats", Ordered, func() {
 		controlInfoBundle := &Bundle{
 			LeafHubName:   leafHubName,
 			BundleVersion: status.NewBundleVersion(0, 0),
+			Capabilities:  status.NewCapabilities(),
 			lock:          sync.Mutex{},
 		}
 
@@ -69,11 +77,12 @@ var _ = Describe("leaf hubs heartbeats", Ordered, func() {
 
 		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, constants.ControlInfoMsgKey)
 		transportMessage := &transport.Message{
-			Key:     transportMessageKey,
-			ID:      transportMessageKey,
-			MsgType: constants.StatusBundle,
-			Version: controlInfoBundle.BundleVersion.String(),
-			Payload: payloadBytes,
+			Key:           transportMessageKey,
+			ID:            transportMessageKey,
+			MsgType:       constants.StatusBundle,
+			Version:       controlInfoBundle.BundleVersion.String(),
+			SchemaVersion: status.SchemaVersion,
+			Payload:       payloadBytes,
 		}
 
 		By("Sync message with transport")
@@ -100,6 +109,20 @@ var _ = Describe("leaf hubs heartbeats", Ordered, func() {
 			}
 			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
 		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+
+		By("Check the capabilities table")
+		Eventually(func() error {
+			var schemaVersion int
+			err := transportPostgreSQL.GetConn().QueryRow(ctx, `SELECT schema_version FROM status.leaf_hub_capabilities
+				WHERE leaf_hub_name = $1`, leafHubName).Scan(&schemaVersion)
+			if err != nil {
+				return err
+			}
+			if schemaVersion != status.SchemaVersion {
+				return fmt.Errorf("the schema version %d of the leaf hub isn't synced", schemaVersion)
+			}
+			return nil
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
 	})
 })
 
@@ -107,5 +130,6 @@ var _ = Describe("leaf hubs heartbeats", Ordered, func() {
 type Bundle struct {
 	LeafHubName   string                `json:"leafHubName"`
 	BundleVersion *status.BundleVersion `json:"bundleVersion"`
+	Capabilities  *status.Capabilities  `json:"capabilities"`
 	lock          sync.Mutex
 }
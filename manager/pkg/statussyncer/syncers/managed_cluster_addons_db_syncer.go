@@ -0,0 +1,145 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// NewManagedClusterAddOnsDBSyncer creates a new instance of ManagedClusterAddOnsDBSyncer.
+func NewManagedClusterAddOnsDBSyncer(log logr.Logger) DBSyncer {
+	dbSyncer := &ManagedClusterAddOnsDBSyncer{
+		log:              log,
+		createBundleFunc: status.NewManagedClusterAddOnStatusBundle,
+	}
+
+	log.Info("initialized managed cluster addons db syncer")
+
+	return dbSyncer
+}
+
+// ManagedClusterAddOnsDBSyncer implements the addon status of the managed clusters transport to db sync.
+type ManagedClusterAddOnsDBSyncer struct {
+	log              logr.Logger
+	createBundleFunc status.CreateBundleFunction
+}
+
+// RegisterCreateBundleFunctions registers create bundle functions within the transport instance.
+func (syncer *ManagedClusterAddOnsDBSyncer) RegisterCreateBundleFunctions(transportDispatcher BundleRegisterable) {
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.ManagedClusterAddOnsMsgKey,
+		CreateBundleFunc: syncer.createBundleFunc,
+		Predicate:        func() bool { return true }, // always get managed cluster addons bundles
+	})
+}
+
+// RegisterBundleHandlerFunctions registers bundle handler functions within the conflation manager.
+// the leaf hub sends the addons of all its managed clusters, so whatever is in the db for the leaf hub and cannot be
+// found in the bundle has to be deleted from the database.
+func (syncer *ManagedClusterAddOnsDBSyncer) RegisterBundleHandlerFunctions(
+	conflationManager *conflator.ConflationManager,
+) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.ManagedClusterAddOnsPriority,
+		bundle.CompleteStateMode,
+		helpers.GetBundleType(syncer.createBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handleManagedClusterAddOnsBundle(ctx, bundle)
+		},
+	))
+}
+
+func (syncer *ManagedClusterAddOnsDBSyncer) handleManagedClusterAddOnsBundle(ctx context.Context,
+	bundle status.Bundle,
+) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		addonKeys := make([][]interface{}, 0, len(bundle.GetObjects()))
+		for _, object := range bundle.GetObjects() {
+			addon, ok := object.(*status.ManagedClusterAddOnStatus)
+			if !ok {
+				continue
+			}
+			clusterID, err := resolveAddOnClusterID(tx, leafHubName, addon)
+			if err != nil {
+				return err
+			}
+			if clusterID == "" {
+				// the cluster isn't synced yet, the addon is synced with the next bundle of the leaf hub
+				syncer.log.V(2).Info("skip the addon of the unknown cluster", "leafHub", leafHubName,
+					"cluster", addon.ClusterName, "addon", addon.AddonName)
+				continue
+			}
+			addonKeys = append(addonKeys, []interface{}{clusterID, addon.AddonName})
+
+			// the cluster might be moved from another leaf hub, so the leaf hub name is updated as well
+			err = tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "cluster_id"}, {Name: "addon_name"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"leaf_hub_name", "cluster_name", "available", "degraded", "progressing", "message",
+				}),
+			}).Create(&models.ManagedClusterAddOn{
+				ClusterID:   clusterID,
+				LeafHubName: leafHubName,
+				ClusterName: addon.ClusterName,
+				AddonName:   addon.AddonName,
+				Available:   addon.Available,
+				Degraded:    addon.Degraded,
+				Progressing: addon.Progressing,
+				Message:     addon.Message,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// delete the addons that are no longer on the leaf hub
+		deleteTx := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(addonKeys) > 0 {
+			deleteTx = deleteTx.Where("(cluster_id, addon_name) NOT IN ?", addonKeys)
+		}
+		return deleteTx.Delete(&models.ManagedClusterAddOn{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub '%s' managed cluster addons bundle - %w", leafHubName, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
+
+// resolveAddOnClusterID returns the cluster id of the addon, the addon without the cluster id is reported when the
+// agent can't find its cluster, then the id is resolved from the managed clusters of the leaf hub by the cluster name.
+func resolveAddOnClusterID(tx *gorm.DB, leafHubName string, addon *status.ManagedClusterAddOnStatus) (string, error) {
+	if addon.ClusterID != "" {
+		return addon.ClusterID, nil
+	}
+
+	var clusterIDs []string
+	if err := tx.Model(&models.ManagedCluster{}).Where("leaf_hub_name = ? AND cluster_name = ?", leafHubName,
+		addon.ClusterName).Limit(1).Pluck("cluster_id", &clusterIDs).Error; err != nil {
+		return "", fmt.Errorf("failed to resolve the id of cluster %s - %w", addon.ClusterName, err)
+	}
+	if len(clusterIDs) == 0 {
+		return "", nil
+	}
+
+	return clusterIDs[0], nil
+}
@@ -0,0 +1,199 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var _ = Describe("ManagedClusterAddOnsDbSyncer", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		clusterID   = "3f2e5b8c-3f5d-4a3a-9b0a-8d8f3f0d1c11"
+		testSchema  = database.StatusSchema
+		testTable   = database.ManagedClusterAddOnsTableName
+		messageKey  = constants.ManagedClusterAddOnsMsgKey
+	)
+
+	BeforeAll(func() {
+		By("Create managed_cluster_addons table in database")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS status;
+			CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+				cluster_id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				cluster_name character varying(63) NOT NULL,
+				addon_name character varying(253) NOT NULL,
+				available character varying(15) NOT NULL,
+				degraded character varying(15) NOT NULL,
+				progressing character varying(15) NOT NULL,
+				message text,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_addons_cluster_id_addon_name_idx ON
+				status.managed_cluster_addons USING btree (cluster_id, addon_name);
+			DO $$ BEGIN
+				CREATE TYPE status.error_type AS ENUM (
+					'disconnected',
+					'none'
+				);
+			EXCEPTION
+				WHEN duplicate_object THEN null;
+			END $$;
+			CREATE TABLE IF NOT EXISTS status.managed_clusters (
+				leaf_hub_name character varying(63) NOT NULL,
+				cluster_name character varying(63) generated always as (payload -> 'metadata' ->> 'name') stored,
+				cluster_id uuid NOT NULL,
+				payload jsonb NOT NULL,
+				error status.error_type NOT NULL,
+				created_at timestamp without time zone,
+				updated_at timestamp without time zone,
+				deleted_at timestamp without time zone
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("sync the managed cluster addons bundle", func() {
+		By("Create managed cluster addons bundle")
+		statusBundle := &status.BaseManagedClusterAddOnStatusBundle{
+			Objects: []*status.ManagedClusterAddOnStatus{
+				{
+					ClusterID:   clusterID,
+					ClusterName: "cluster1",
+					AddonName:   "observability-controller",
+					Available:   "True",
+					Degraded:    "True",
+					Progressing: "Unknown",
+					Message:     "metrics collector failed",
+				},
+				{
+					ClusterID:   clusterID,
+					ClusterName: "cluster1",
+					AddonName:   "governance-policy-framework",
+					Available:   "True",
+					Degraded:    "False",
+					Progressing: "False",
+				},
+			},
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(0, 1),
+		}
+
+		By("Create transport message")
+		payloadBytes, err := json.Marshal(statusBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, messageKey)
+		transportMessage := &transport.Message{
+			Key:     transportMessageKey,
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: statusBundle.BundleVersion.String(),
+			Payload: payloadBytes,
+		}
+
+		By("Sync message with transport")
+		err = producer.Send(ctx, transportMessage)
+		Expect(err).Should(Succeed())
+
+		By("Check the managed cluster addons table")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT cluster_id,addon_name,degraded FROM %s.%s WHERE leaf_hub_name=$1",
+				testSchema, testTable)
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, querySql, leafHubName)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			degradedAddons := map[string]string{}
+			for rows.Next() {
+				var id, addonName, degraded string
+				if err := rows.Scan(&id, &addonName, &degraded); err != nil {
+					return err
+				}
+				if id == clusterID {
+					degradedAddons[addonName] = degraded
+				}
+			}
+			if degradedAddons["observability-controller"] == "True" &&
+				degradedAddons["governance-policy-framework"] == "False" {
+				return nil
+			}
+			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("resolve the cluster id of the addons by the cluster name", func() {
+		const cluster2ID = "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d"
+		By("Create the managed cluster of the leaf hub")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx, `INSERT INTO status.managed_clusters (leaf_hub_name,
+			cluster_id, payload, error) VALUES ($1, $2, '{"metadata": {"name": "cluster2"}}', 'none')`,
+			leafHubName, cluster2ID)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("Create managed cluster addons bundle without the cluster id")
+		statusBundle := &status.BaseManagedClusterAddOnStatusBundle{
+			Objects: []*status.ManagedClusterAddOnStatus{
+				{
+					ClusterName: "cluster2",
+					AddonName:   "work-manager",
+					Available:   "True",
+					Degraded:    "False",
+					Progressing: "False",
+				},
+				{
+					ClusterName: "cluster3",
+					AddonName:   "work-manager",
+					Available:   "True",
+					Degraded:    "False",
+					Progressing: "False",
+				},
+			},
+			LeafHubName:   leafHubName,
+			BundleVersion: status.NewBundleVersion(0, 2),
+		}
+		payloadBytes, err := json.Marshal(statusBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, messageKey)
+		Expect(producer.Send(ctx, &transport.Message{
+			Key:     transportMessageKey,
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: statusBundle.BundleVersion.String(),
+			Payload: payloadBytes,
+		})).Should(Succeed())
+
+		By("Check the addon of the known cluster is synced with its id")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT cluster_id,cluster_name FROM %s.%s WHERE leaf_hub_name=$1",
+				testSchema, testTable)
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, querySql, leafHubName)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			clusters := map[string]string{}
+			for rows.Next() {
+				var id, clusterName string
+				if err := rows.Scan(&id, &clusterName); err != nil {
+					return err
+				}
+				clusters[clusterName] = id
+			}
+			if len(clusters) == 1 && clusters["cluster2"] == cluster2ID {
+				return nil
+			}
+			return fmt.Errorf("unexpected addons of the clusters %v in table %s.%s", clusters, testSchema, testTable)
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,145 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// NewPolicyReportDBSyncer creates a new instance of PolicyReportDBSyncer.
+func NewPolicyReportDBSyncer(log logr.Logger) DBSyncer {
+	dbSyncer := &PolicyReportDBSyncer{
+		log:              log,
+		createBundleFunc: status.NewPolicyReportBundle,
+	}
+
+	log.Info("initialized policy report db syncer")
+
+	return dbSyncer
+}
+
+// PolicyReportDBSyncer implements the gatekeeper and kyverno policy reports transport to db sync.
+type PolicyReportDBSyncer struct {
+	log              logr.Logger
+	createBundleFunc status.CreateBundleFunction
+}
+
+// RegisterCreateBundleFunctions registers create bundle functions within the transport instance.
+func (syncer *PolicyReportDBSyncer) RegisterCreateBundleFunctions(transportDispatcher BundleRegisterable) {
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.PolicyReportMsgKey,
+		CreateBundleFunc: syncer.createBundleFunc,
+		Predicate:        func() bool { return true }, // always get policy report bundles
+	})
+}
+
+// RegisterBundleHandlerFunctions registers bundle handler functions within the conflation manager.
+// the leaf hub sends all its policy reports, so whatever is in the db for the leaf hub and cannot be found in the
+// bundle has to be deleted from the database. the violations of the reports are replaced by the bundle.
+func (syncer *PolicyReportDBSyncer) RegisterBundleHandlerFunctions(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.PolicyReportPriority,
+		bundle.CompleteStateMode,
+		helpers.GetBundleType(syncer.createBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handlePolicyReportBundle(ctx, bundle)
+		},
+	))
+}
+
+func (syncer *PolicyReportDBSyncer) handlePolicyReportBundle(ctx context.Context, bundle status.Bundle) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		reportIDs := make([]string, 0, len(bundle.GetObjects()))
+		violations := make([]*models.PolicyViolation, 0)
+		for _, object := range bundle.GetObjects() {
+			report, ok := object.(*status.PolicyReport)
+			if !ok {
+				continue
+			}
+			reportIDs = append(reportIDs, report.ReportID)
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"engine", "kind", "name", "namespace", "cluster_name", "pass", "fail", "warn", "error", "skip",
+				}),
+			}).Create(&models.PolicyReport{
+				ID:          report.ReportID,
+				LeafHubName: leafHubName,
+				Engine:      report.Engine,
+				Kind:        report.Kind,
+				Name:        report.Name,
+				Namespace:   report.Namespace,
+				ClusterName: report.ClusterName,
+				Pass:        report.Pass,
+				Fail:        report.Fail,
+				Warn:        report.Warn,
+				Error:       report.Error,
+				Skip:        report.Skip,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			for _, violation := range report.Violations {
+				violations = append(violations, &models.PolicyViolation{
+					ReportID:          report.ReportID,
+					LeafHubName:       leafHubName,
+					Engine:            report.Engine,
+					ClusterName:       report.ClusterName,
+					PolicyName:        violation.Policy,
+					RuleName:          violation.Rule,
+					Severity:          violation.Severity,
+					Category:          violation.Category,
+					Result:            violation.Result,
+					Message:           violation.Message,
+					ResourceKind:      violation.ResourceKind,
+					ResourceName:      violation.ResourceName,
+					ResourceNamespace: violation.ResourceNamespace,
+				})
+			}
+		}
+
+		// delete the reports that are no longer on the leaf hub
+		deleteTx := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(reportIDs) > 0 {
+			deleteTx = deleteTx.Where("id NOT IN ?", reportIDs)
+		}
+		if err := deleteTx.Delete(&models.PolicyReport{}).Error; err != nil {
+			return err
+		}
+
+		// the violations don't have an identity, so the violations of the leaf hub are replaced
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.PolicyViolation{}).Error; err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(violations, 100).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed handling leaf hub '%s' policy report bundle - %w", leafHubName, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/bundle/policyreports"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var _ = Describe("PolicyReportDbSyncer", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		reportID    = "7a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c22"
+		testSchema  = database.StatusSchema
+		testTable   = database.PolicyViolationsTableName
+		messageKey  = constants.PolicyReportMsgKey
+	)
+
+	BeforeAll(func() {
+		By("Create policy_reports and policy_violations tables in database")
+		_, err := transportPostgreSQL.GetConn().Exec(ctx, `
+			CREATE SCHEMA IF NOT EXISTS status;
+			CREATE TABLE IF NOT EXISTS status.policy_reports (
+				id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				engine character varying(63) NOT NULL,
+				kind character varying(253) NOT NULL,
+				name character varying(253) NOT NULL,
+				namespace character varying(63),
+				cluster_name character varying(63),
+				pass bigint DEFAULT 0 NOT NULL,
+				fail bigint DEFAULT 0 NOT NULL,
+				warn bigint DEFAULT 0 NOT NULL,
+				error bigint DEFAULT 0 NOT NULL,
+				skip bigint DEFAULT 0 NOT NULL,
+				updated_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS policy_reports_leaf_hub_name_id_idx ON
+				status.policy_reports USING btree (leaf_hub_name, id);
+			CREATE TABLE IF NOT EXISTS status.policy_violations (
+				report_id uuid NOT NULL,
+				leaf_hub_name character varying(63) NOT NULL,
+				engine character varying(63) NOT NULL,
+				cluster_name character varying(63),
+				policy_name character varying(253) NOT NULL,
+				rule_name character varying(253),
+				severity character varying(63),
+				category character varying(253),
+				result character varying(15) NOT NULL,
+				message text,
+				resource_kind character varying(253),
+				resource_name character varying(253),
+				resource_namespace character varying(63),
+				created_at timestamp without time zone DEFAULT now() NOT NULL
+			);
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check whether the tables are created")
+		Eventually(func() error {
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, "SELECT * FROM pg_tables")
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				columnValues, _ := rows.Values()
+				schema := columnValues[0]
+				table := columnValues[1]
+				if schema == testSchema && table == testTable {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to create table %s.%s", testSchema, testTable)
+		}, 10*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+
+	It("sync the policy report bundle", func() {
+		By("Create policy report bundle")
+		statusBundle := policyreports.NewPolicyReportBundle(leafHubName, 0,
+			func(namespace string) string { return "" }).(*policyreports.PolicyReportBundle)
+		statusBundle.SyncObjects([]*unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+			"kind":       "K8sRequiredLabels",
+			"metadata": map[string]interface{}{
+				"name":            "ns-must-have-owner",
+				"uid":             reportID,
+				"resourceVersion": "1",
+			},
+			"status": map[string]interface{}{
+				"totalViolations": int64(1),
+				"violations": []interface{}{
+					map[string]interface{}{"kind": "Namespace", "name": "dev", "message": "you must provide owner"},
+				},
+			},
+		}}})
+
+		By("Create transport message")
+		payloadBytes, err := json.Marshal(statusBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, messageKey)
+		transportMessage := &transport.Message{
+			Key:     transportMessageKey,
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: statusBundle.GetBundleVersion().String(),
+			Payload: payloadBytes,
+		}
+
+		By("Sync message with transport")
+		err = producer.Send(ctx, transportMessage)
+		Expect(err).Should(Succeed())
+
+		By("Check the policy violations table")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT leaf_hub_name,report_id,engine,policy_name,result,resource_name FROM %s.%s",
+				testSchema, testTable)
+			rows, err := transportPostgreSQL.GetConn().Query(ctx, querySql)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var hubName, id, engine, policyName, result, resourceName string
+				if err := rows.Scan(&hubName, &id, &engine, &policyName, &result, &resourceName); err != nil {
+					return err
+				}
+				if hubName == leafHubName && id == reportID && engine == "gatekeeper" &&
+					policyName == "ns-must-have-owner" && result == "fail" && resourceName == "dev" {
+					return nil
+				}
+			}
+			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -1,28 +1,133 @@
 package dbsyncer
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	statusbundle "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
 	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/db/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/conflator/dependency"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
-// NewSubscriptionStatusesDBSyncer creates a new instance of genericDBSyncer to sync subscription-statuses.
+// NewSubscriptionStatusesDBSyncer creates a new instance of SubscriptionStatusesDBSyncer.
 func NewSubscriptionStatusesDBSyncer(log logr.Logger) DBSyncer {
-	dbSyncer := &genericDBSyncer{
-		log:              log,
-		transportMsgKey:  constants.SubscriptionStatusMsgKey,
-		dbSchema:         database.StatusSchema,
-		dbTableName:      database.SubscriptionStatusesTableName,
-		createBundleFunc: statusbundle.NewSubscriptionStatusesBundle,
-		bundlePriority:   conflator.SubscriptionStatusPriority,
-		bundleSyncMode:   bundle.CompleteStateMode,
+	dbSyncer := &SubscriptionStatusesDBSyncer{
+		genericDBSyncer: &genericDBSyncer{
+			log:              log,
+			transportMsgKey:  constants.SubscriptionStatusMsgKey,
+			dbSchema:         database.StatusSchema,
+			dbTableName:      database.SubscriptionStatusesTableName,
+			createBundleFunc: statusbundle.NewSubscriptionStatusesBundle,
+			bundlePriority:   conflator.SubscriptionStatusPriority,
+			bundleSyncMode:   bundle.CompleteStateMode,
+		},
+		createDeltaBundleFunc: statusbundle.NewDeltaSubscriptionStatusesBundle,
 	}
 
 	log.Info("initialized subscription-statuses db syncer")
 
 	return dbSyncer
 }
+
+// SubscriptionStatusesDBSyncer implements subscription-statuses db sync business logic. the complete state bundles
+// are handled by the generic db syncer, the delta state bundles only apply the changes on top of them.
+type SubscriptionStatusesDBSyncer struct {
+	*genericDBSyncer
+	createDeltaBundleFunc status.CreateBundleFunction
+}
+
+// RegisterCreateBundleFunctions registers create bundle functions within the transport instance.
+func (syncer *SubscriptionStatusesDBSyncer) RegisterCreateBundleFunctions(transportDispatcher BundleRegisterable) {
+	syncer.genericDBSyncer.RegisterCreateBundleFunctions(transportDispatcher)
+
+	transportDispatcher.BundleRegister(&registration.BundleRegistration{
+		MsgID:            constants.SubscriptionDeltaStatusMsgKey,
+		CreateBundleFunc: syncer.createDeltaBundleFunc,
+		Predicate:        func() bool { return true }, // always get subscription statuses
+	})
+}
+
+// RegisterBundleHandlerFunctions registers bundle handler functions within the conflation manager.
+func (syncer *SubscriptionStatusesDBSyncer) RegisterBundleHandlerFunctions(
+	conflationManager *conflator.ConflationManager,
+) {
+	syncer.genericDBSyncer.RegisterBundleHandlerFunctions(conflationManager)
+
+	conflationManager.Register(conflator.NewConflationRegistration(
+		conflator.DeltaSubscriptionStatusPriority, bundle.DeltaStateMode,
+		helpers.GetBundleType(syncer.createDeltaBundleFunc()),
+		func(ctx context.Context, bundle status.Bundle, dbClient postgres.StatusTransportBridgeDB) error {
+			return syncer.handleDeltaSubscriptionStatusesBundle(ctx, bundle, dbClient)
+		}).WithDependency(dependency.NewDependency(helpers.GetBundleType(syncer.createBundleFunc()),
+		dependency.ExactMatch)))
+	// delta statuses depend on complete statuses. should be processed only when there is an exact match
+}
+
+// handleDeltaSubscriptionStatusesBundle applies the changes of the delta bundle, unlike the complete state bundle the
+// statuses that were not sent in the bundle are kept in the database.
+func (syncer *SubscriptionStatusesDBSyncer) handleDeltaSubscriptionStatusesBundle(ctx context.Context,
+	bundle status.Bundle, dbClient postgres.GenericStatusResourceDB,
+) error {
+	logBundleHandlingMessage(syncer.log, bundle, startBundleHandlingMessage)
+	leafHubName := bundle.GetLeafHubName()
+
+	deltaBundle, ok := bundle.(*statusbundle.DeltaSubscriptionStatusesBundle)
+	if !ok {
+		return fmt.Errorf("failed to handle bundle - expecting %s, got %s",
+			"DeltaSubscriptionStatusesBundle", helpers.GetBundleType(bundle))
+	}
+
+	idToVersionMapFromDB, err := dbClient.GetResourceIDToVersionByLeafHub(ctx, syncer.dbSchema,
+		syncer.dbTableName, leafHubName)
+	if err != nil {
+		return fmt.Errorf("failed fetching leaf hub '%s.%s' IDs from db - %w",
+			syncer.dbSchema, syncer.dbTableName, err)
+	}
+
+	batchBuilder := dbClient.NewGenericBatchBuilder(syncer.dbSchema, syncer.dbTableName, leafHubName)
+
+	for _, object := range deltaBundle.GetObjects() {
+		specificObj, ok := object.(metav1.Object)
+		if !ok {
+			continue
+		}
+
+		uid := getGenericResourceUID(specificObj)
+		resourceVersionFromDB, objExistsInDB := idToVersionMapFromDB[uid]
+
+		if !objExistsInDB { // object not found in the db table
+			batchBuilder.Insert(uid, object)
+			continue
+		}
+
+		if specificObj.GetResourceVersion() == resourceVersionFromDB {
+			continue // update object in db only if what we got is a different (newer) version of the resource.
+		}
+
+		batchBuilder.Update(uid, object)
+	}
+
+	for _, uid := range deltaBundle.GetDeletedObjectIDs() {
+		if _, objExistsInDB := idToVersionMapFromDB[uid]; objExistsInDB {
+			batchBuilder.Delete(uid)
+		}
+	}
+
+	if err := dbClient.SendBatch(ctx, batchBuilder.Build()); err != nil {
+		return fmt.Errorf(failedBatchFormat, err)
+	}
+
+	logBundleHandlingMessage(syncer.log, bundle, finishBundleHandlingMessage)
+
+	return nil
+}
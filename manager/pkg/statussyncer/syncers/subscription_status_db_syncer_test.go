@@ -117,4 +117,62 @@ var _ = Describe("SubscriptionStatusDbSyncer", Ordered, func() {
 			return fmt.Errorf("failed to sync content of table %s.%s", testSchema, testTable)
 		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
 	})
+
+	It("sync the SubscriptionStatus delta bundle on top of the complete bundle", func() {
+		By("Create SubscriptionStatus delta bundle")
+		obj := &appsv1alpha1.SubscriptionStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "testAppStatus",
+				Namespace:       "default",
+				ResourceVersion: "2",
+				Annotations: map[string]string{
+					constants.OriginOwnerReferenceAnnotation: "2aa5547c-c172-47ed-b70b-db468c84d327",
+				},
+			},
+			Statuses: appsv1alpha1.SubscriptionClusterStatusMap{
+				SubscriptionStatus: []appsv1alpha1.SubscriptionUnitStatus{
+					{Name: "nginx", Kind: "Deployment", Namespace: "default", Phase: appsv1alpha1.PackageDeployed},
+				},
+			},
+		}
+		deltaBundle := &status.BaseDeltaSubscriptionStatusesBundle{
+			Objects:           []*appsv1alpha1.SubscriptionStatus{obj},
+			LeafHubName:       leafHubName,
+			BaseBundleVersion: status.NewBundleVersion(0, 1), // the version of the complete bundle
+			// the generation of the delta bundle is counted apart from the complete bundle it's based on
+			BundleVersion: status.NewBundleVersion(0, 2),
+		}
+
+		By("Create transport message")
+		payloadBytes, err := json.Marshal(deltaBundle)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		transportMessageKey := fmt.Sprintf("%s.%s", leafHubName, constants.SubscriptionDeltaStatusMsgKey)
+		transportMessage := &transport.Message{
+			Key:     fmt.Sprintf("%s@%d", transportMessageKey, 0),
+			ID:      transportMessageKey,
+			MsgType: constants.StatusBundle,
+			Version: deltaBundle.BundleVersion.String(),
+			Payload: payloadBytes,
+		}
+
+		By("Sync message with transport")
+		err = producer.Send(ctx, transportMessage)
+		Expect(err).Should(Succeed())
+
+		By("Check the subscription status is updated")
+		Eventually(func() error {
+			querySql := fmt.Sprintf("SELECT payload FROM %s.%s WHERE leaf_hub_name=$1 AND id=$2",
+				testSchema, testTable)
+			appsubStatus := &appsv1alpha1.SubscriptionStatus{}
+			if err := transportPostgreSQL.GetConn().QueryRow(ctx, querySql, leafHubName,
+				"2aa5547c-c172-47ed-b70b-db468c84d327").Scan(appsubStatus); err != nil {
+				return err
+			}
+			if len(appsubStatus.Statuses.SubscriptionStatus) != 1 {
+				return fmt.Errorf("the subscription status is not updated by the delta bundle")
+			}
+			return nil
+		}, 30*time.Second, 2*time.Second).ShouldNot(HaveOccurred())
+	})
 })
@@ -0,0 +1,234 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/appliedstatus"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// the applied status of every global resource of the spec table on the leaf hubs it's sent to, the leaf hubs
+// without the applied status are pending
+var resourceAppliedStatusesQuery = `SELECT r.id AS resource_id, r.payload->>'apiVersion' AS api_version,
+	r.payload->>'kind' AS kind, r.payload->'metadata'->>'name' AS name,
+	COALESCE(r.payload->'metadata'->>'namespace', '') AS namespace, hb.leaf_hub_name, a.applied, a.error,
+	a.dry_run, a.drifted, a.updated_at
+	FROM spec.%s r CROSS JOIN (` + appliedstatus.LeafHubsQuery + `) hb
+	LEFT JOIN status.applied_statuses a ON a.resource_id = r.id AND a.leaf_hub_name = hb.leaf_hub_name
+	WHERE r.deleted = FALSE AND (a.resource_id IS NOT NULL OR ` +
+	fmt.Sprintf(appliedstatus.TargetedCondition, "r.id", "hb.leaf_hub_name") + `)
+	ORDER BY r.id, hb.leaf_hub_name`
+
+// resourceAppliedStatus is the applied status of a global resource on a leaf hub, the applied is null if the leaf hub
+// hasn't reported the resource yet.
+type resourceAppliedStatus struct {
+	ResourceID  string
+	APIVersion  string
+	Kind        string
+	Name        string
+	Namespace   string
+	LeafHubName string
+	Applied     *bool
+	Error       *string
+	DryRun      *bool
+	Drifted     *bool
+	UpdatedAt   *time.Time
+}
+
+// appliedConditionsWriter periodically writes the Applied and Drifted conditions aggregated from the applied status
+// of the leaf hubs onto the global resources. the conditions are set in the status of the kinds with status
+// conditions, and in the applied conditions annotation of the other kinds.
+type appliedConditionsWriter struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	interval time.Duration
+}
+
+// AddAppliedConditionsWriter adds the writer of the applied conditions of the global resources to the manager.
+func AddAppliedConditionsWriter(mgr ctrl.Manager, interval time.Duration) error {
+	if err := mgr.Add(&appliedConditionsWriter{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		log:      ctrl.Log.WithName("applied-conditions-writer"),
+		interval: interval,
+	}); err != nil {
+		return fmt.Errorf("failed to add applied conditions writer to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func (w *appliedConditionsWriter) Start(ctx context.Context) error {
+	w.log.Info("started applied conditions writer", "interval", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Info("stopped applied conditions writer")
+			return nil
+		case <-ticker.C:
+			db := database.GetGorm().WithContext(ctx)
+			for _, tableName := range appliedstatus.GlobalResourceTables {
+				if err := w.writeTableConditions(ctx, db, tableName); err != nil {
+					w.log.Error(err, "failed to write the applied conditions", "table",
+						fmt.Sprintf("spec.%s", tableName))
+				}
+			}
+		}
+	}
+}
+
+// writeTableConditions reads the applied status of all the global resources of the table at once, and writes the
+// conditions of each resource.
+func (w *appliedConditionsWriter) writeTableConditions(ctx context.Context, db *gorm.DB, tableName string) error {
+	var statuses []resourceAppliedStatus
+	if err := db.Raw(fmt.Sprintf(resourceAppliedStatusesQuery, tableName)).Scan(&statuses).Error; err != nil {
+		return fmt.Errorf("failed to list the applied statuses: %w", err)
+	}
+
+	for start := 0; start < len(statuses); {
+		end := start
+		leafHubs := []appliedstatus.LeafHubAppliedStatus{}
+		for ; end < len(statuses) && statuses[end].ResourceID == statuses[start].ResourceID; end++ {
+			leafHubs = append(leafHubs, toLeafHubAppliedStatus(&statuses[end]))
+		}
+		resource := &statuses[start]
+		if err := w.writeConditions(ctx, resource, appliedstatus.Conditions(leafHubs)); err != nil {
+			w.log.Error(err, "failed to write the applied conditions", "kind", resource.Kind,
+				"namespace", resource.Namespace, "name", resource.Name)
+		}
+		start = end
+	}
+	return nil
+}
+
+func (w *appliedConditionsWriter) writeConditions(ctx context.Context, resource *resourceAppliedStatus,
+	conditions []metav1.Condition,
+) error {
+	gvk := schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind)
+	// the typed objects are read from the cache, the other kinds are read from the api server
+	var instance client.Object
+	if object, err := w.scheme.New(gvk); err == nil {
+		instance = object.(client.Object)
+	} else {
+		unstructuredObject := &unstructured.Unstructured{}
+		unstructuredObject.SetGroupVersionKind(gvk)
+		instance = unstructuredObject
+	}
+	if err := w.client.Get(ctx, types.NamespacedName{
+		Namespace: resource.Namespace,
+		Name:      resource.Name,
+	}, instance); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// the resource is recreated and not synced to the database yet, its conditions are written once it's synced
+	if string(instance.GetUID()) != resource.ResourceID {
+		return nil
+	}
+
+	original := instance.DeepCopyObject().(client.Object)
+	if statusConditions := getStatusConditions(instance); statusConditions != nil {
+		if !setConditions(statusConditions, conditions) {
+			return nil
+		}
+		if err := w.client.Status().Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to patch the status conditions: %w", err)
+		}
+		return nil
+	}
+
+	annotations := instance.GetAnnotations()
+	annotatedConditions := []metav1.Condition{}
+	if value, found := annotations[constants.AppliedConditionsAnnotation]; found {
+		// the invalid annotation is overwritten
+		_ = json.Unmarshal([]byte(value), &annotatedConditions)
+	}
+	if !setConditions(&annotatedConditions, conditions) {
+		return nil
+	}
+	value, err := json.Marshal(annotatedConditions)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.AppliedConditionsAnnotation] = string(value)
+	instance.SetAnnotations(annotations)
+	if err := w.client.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch the applied conditions annotation: %w", err)
+	}
+	return nil
+}
+
+// getStatusConditions returns the status conditions of the kinds with status conditions, otherwise nil.
+func getStatusConditions(instance client.Object) *[]metav1.Condition {
+	switch object := instance.(type) {
+	case *clusterv1beta1.Placement:
+		return &object.Status.Conditions
+	case *clusterv1beta2.ManagedClusterSet:
+		return &object.Status.Conditions
+	case *clusterv1beta2.ManagedClusterSetBinding:
+		return &object.Status.Conditions
+	default:
+		return nil
+	}
+}
+
+// setConditions sets the conditions, the transition time is kept if the status of a condition isn't changed. it
+// returns true if the conditions are changed.
+func setConditions(conditions *[]metav1.Condition, newConditions []metav1.Condition) bool {
+	original := make([]metav1.Condition, len(*conditions))
+	copy(original, *conditions)
+	for _, condition := range newConditions {
+		meta.SetStatusCondition(conditions, condition)
+	}
+	return !equality.Semantic.DeepEqual(original, *conditions)
+}
+
+func toLeafHubAppliedStatus(status *resourceAppliedStatus) appliedstatus.LeafHubAppliedStatus {
+	leafHubStatus := appliedstatus.LeafHubAppliedStatus{
+		LeafHubName: status.LeafHubName,
+		Pending:     status.Applied == nil,
+	}
+	if status.Applied != nil {
+		leafHubStatus.Applied = *status.Applied
+	}
+	if status.Error != nil {
+		leafHubStatus.Error = *status.Error
+	}
+	if status.DryRun != nil {
+		leafHubStatus.DryRun = *status.DryRun
+	}
+	if status.Drifted != nil {
+		leafHubStatus.Drifted = *status.Drifted
+	}
+	if status.UpdatedAt != nil {
+		leafHubStatus.UpdatedAt = &metav1.Time{Time: *status.UpdatedAt}
+	}
+	return leafHubStatus
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const globalResourcesQuery = `SELECT id, payload->'metadata'->>'name' AS name,
+	COALESCE(payload->'metadata'->>'namespace', '') AS namespace FROM spec.%s WHERE deleted = FALSE`
+
+// globalResource is the global resource stored in the spec table.
+type globalResource struct {
+	ID        string
+	Name      string
+	Namespace string
+}
+
+// the status reported by the leaf hubs of all the global resources which aren't deleted, it's joined by the id of the
+// global resource
+const leafHubPayloadsQuery = `SELECT s.id, s.leaf_hub_name, s.payload FROM status.%s s JOIN spec.%s r ON r.id = s.id
+	WHERE r.deleted = FALSE ORDER BY s.id, s.leaf_hub_name`
+
+// leafHubPayload is the status of a global resource reported by a leaf hub.
+type leafHubPayload struct {
+	ID          string
+	LeafHubName string
+	Payload     datatypes.JSON
+}
+
+// setStatusFunc sets the status aggregated from the leaf hubs onto the instance of the global resource with the id.
+type setStatusFunc func(id string, instance client.Object) error
+
+// genericStatusWriter periodically patches the status of the global resources in the spec table with the status
+// aggregated from the leaf hubs in the database.
+type genericStatusWriter struct {
+	client         client.Client
+	log            logr.Logger
+	interval       time.Duration
+	tableName      string
+	createInstance func() client.Object
+	// loadStatuses loads the status reported by the leaf hubs of all the global resources by one query, and returns
+	// the function setting the aggregated status onto the instances
+	loadStatuses func(db *gorm.DB) (setStatusFunc, error)
+}
+
+func (w *genericStatusWriter) Start(ctx context.Context) error {
+	w.log.Info("started status writer", "table", fmt.Sprintf("spec.%s", w.tableName), "interval", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Info("stopped status writer", "table", fmt.Sprintf("spec.%s", w.tableName))
+			return nil
+		case <-ticker.C:
+			w.writeStatuses(ctx)
+		}
+	}
+}
+
+func (w *genericStatusWriter) writeStatuses(ctx context.Context) {
+	db := database.GetGorm().WithContext(ctx)
+
+	var resources []globalResource
+	if err := db.Raw(fmt.Sprintf(globalResourcesQuery, w.tableName)).Scan(&resources).Error; err != nil {
+		w.log.Error(err, "failed to list the global resources", "table", fmt.Sprintf("spec.%s", w.tableName))
+		return
+	}
+	if len(resources) == 0 {
+		return
+	}
+
+	setStatus, err := w.loadStatuses(db)
+	if err != nil {
+		w.log.Error(err, "failed to load the status of the global resources", "table",
+			fmt.Sprintf("spec.%s", w.tableName))
+		return
+	}
+
+	for _, resource := range resources {
+		if err := w.writeStatus(ctx, setStatus, resource); err != nil {
+			w.log.Error(err, "failed to write the status", "namespace", resource.Namespace, "name", resource.Name)
+		}
+	}
+}
+
+// writeStatus patches the status of the global resource only if the aggregated status is changed.
+func (w *genericStatusWriter) writeStatus(ctx context.Context, setStatus setStatusFunc,
+	resource globalResource,
+) error {
+	instance := w.createInstance()
+	if err := w.client.Get(ctx, types.NamespacedName{
+		Namespace: resource.Namespace,
+		Name:      resource.Name,
+	}, instance); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// the resource is recreated and not synced to the database yet, its status is written once it's synced
+	if string(instance.GetUID()) != resource.ID {
+		return nil
+	}
+
+	original := instance.DeepCopyObject().(client.Object)
+	if err := setStatus(resource.ID, instance); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(original, instance) {
+		return nil
+	}
+
+	if err := w.client.Status().Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch the status: %w", err)
+	}
+	w.log.V(2).Info("status is written", "namespace", resource.Namespace, "name", resource.Name)
+	return nil
+}
+
+// listLeafHubPayloads returns the status in the status table reported by the leaf hubs for the global resources of the
+// spec table, it's keyed by the id of the global resource.
+func listLeafHubPayloads(db *gorm.DB, statusTableName, specTableName string) (map[string][]leafHubPayload, error) {
+	var payloads []leafHubPayload
+	if err := db.Raw(fmt.Sprintf(leafHubPayloadsQuery, statusTableName, specTableName)).
+		Scan(&payloads).Error; err != nil {
+		return nil, err
+	}
+
+	idToPayloads := make(map[string][]leafHubPayload)
+	for _, payload := range payloads {
+		idToPayloads[payload.ID] = append(idToPayloads[payload.ID], payload)
+	}
+	return idToPayloads, nil
+}
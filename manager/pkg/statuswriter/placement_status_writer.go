@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	placementSatisfiedReason   = "AllLeafHubsSatisfied"
+	placementUnsatisfiedReason = "LeafHubsUnsatisfied"
+)
+
+// AddPlacementStatusWriter adds the writer of the selected clusters of the global placements to the manager.
+func AddPlacementStatusWriter(mgr ctrl.Manager, interval time.Duration) error {
+	if err := mgr.Add(&genericStatusWriter{
+		client:         mgr.GetClient(),
+		log:            ctrl.Log.WithName("placements-status-writer"),
+		interval:       interval,
+		tableName:      "placements",
+		createInstance: func() client.Object { return &clusterv1beta1.Placement{} },
+		loadStatuses:   loadPlacementStatuses,
+	}); err != nil {
+		return fmt.Errorf("failed to add placement status writer to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func loadPlacementStatuses(db *gorm.DB) (setStatusFunc, error) {
+	idToPayloads, err := listLeafHubPayloads(db, database.PlacementsTableName, "placements")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status of the placements: %w", err)
+	}
+
+	return func(id string, instance client.Object) error {
+		placement, ok := instance.(*clusterv1beta1.Placement)
+		if !ok {
+			panic("wrong instance passed to setPlacementStatus: not a Placement")
+		}
+
+		payloads := idToPayloads[id]
+		if len(payloads) == 0 {
+			return nil
+		}
+
+		leafHubPlacements := make(map[string]*clusterv1beta1.Placement, len(payloads))
+		for _, payload := range payloads {
+			leafHubPlacement := &clusterv1beta1.Placement{}
+			if err := json.Unmarshal(payload.Payload, leafHubPlacement); err != nil {
+				return fmt.Errorf("failed to unmarshal the placement of leaf hub %s: %w", payload.LeafHubName, err)
+			}
+			leafHubPlacements[payload.LeafHubName] = leafHubPlacement
+		}
+
+		aggregatePlacementStatus(&placement.Status, leafHubPlacements)
+		return nil
+	}, nil
+}
+
+// aggregatePlacementStatus sums the selected clusters of the leaf hubs, and the placement is satisfied only if it's
+// satisfied on all the leaf hubs.
+func aggregatePlacementStatus(status *clusterv1beta1.PlacementStatus,
+	leafHubPlacements map[string]*clusterv1beta1.Placement,
+) {
+	var selectedClusters int32
+	var unsatisfiedLeafHubs []string
+	for leafHubName, leafHubPlacement := range leafHubPlacements {
+		selectedClusters += leafHubPlacement.Status.NumberOfSelectedClusters
+		if !meta.IsStatusConditionTrue(leafHubPlacement.Status.Conditions,
+			clusterv1beta1.PlacementConditionSatisfied) {
+			unsatisfiedLeafHubs = append(unsatisfiedLeafHubs, leafHubName)
+		}
+	}
+	status.NumberOfSelectedClusters = selectedClusters
+
+	condition := metav1.Condition{
+		Type:    clusterv1beta1.PlacementConditionSatisfied,
+		Status:  metav1.ConditionTrue,
+		Reason:  placementSatisfiedReason,
+		Message: fmt.Sprintf("the placement is satisfied on %d leaf hubs", len(leafHubPlacements)),
+	}
+	if len(unsatisfiedLeafHubs) > 0 {
+		sort.Strings(unsatisfiedLeafHubs)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = placementUnsatisfiedReason
+		condition.Message = fmt.Sprintf("the placement isn't satisfied on the leaf hubs: %s",
+			strings.Join(unsatisfiedLeafHubs, ", "))
+	}
+	// the transition time is kept if the status of the condition isn't changed
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
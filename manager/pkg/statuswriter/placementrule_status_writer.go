@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// AddPlacementRuleStatusWriter adds the writer of the decisions of the global placement rules to the manager.
+func AddPlacementRuleStatusWriter(mgr ctrl.Manager, interval time.Duration) error {
+	if err := mgr.Add(&genericStatusWriter{
+		client:         mgr.GetClient(),
+		log:            ctrl.Log.WithName("placementrules-status-writer"),
+		interval:       interval,
+		tableName:      "placementrules",
+		createInstance: func() client.Object { return &placementrulev1.PlacementRule{} },
+		loadStatuses:   loadPlacementRuleStatuses,
+	}); err != nil {
+		return fmt.Errorf("failed to add placement rule status writer to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func loadPlacementRuleStatuses(db *gorm.DB) (setStatusFunc, error) {
+	idToPayloads, err := listLeafHubPayloads(db, database.PlacementRulesTableName, "placementrules")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status of the placement rules: %w", err)
+	}
+
+	return func(id string, instance client.Object) error {
+		placementRule, ok := instance.(*placementrulev1.PlacementRule)
+		if !ok {
+			panic("wrong instance passed to setPlacementRuleStatus: not a PlacementRule")
+		}
+
+		payloads := idToPayloads[id]
+		leafHubPlacementRules := make([]*placementrulev1.PlacementRule, 0, len(payloads))
+		for _, payload := range payloads {
+			leafHubPlacementRule := &placementrulev1.PlacementRule{}
+			if err := json.Unmarshal(payload.Payload, leafHubPlacementRule); err != nil {
+				return fmt.Errorf("failed to unmarshal the placement rule of leaf hub %s: %w", payload.LeafHubName,
+					err)
+			}
+			leafHubPlacementRules = append(leafHubPlacementRules, leafHubPlacementRule)
+		}
+
+		placementRule.Status.Decisions = aggregatePlacementRuleDecisions(leafHubPlacementRules)
+		return nil
+	}, nil
+}
+
+// aggregatePlacementRuleDecisions merges the decisions of the leaf hubs, the managed cluster names are unique across
+// the leaf hubs.
+func aggregatePlacementRuleDecisions(
+	leafHubPlacementRules []*placementrulev1.PlacementRule,
+) []placementrulev1.PlacementDecision {
+	var decisions []placementrulev1.PlacementDecision
+	for _, leafHubPlacementRule := range leafHubPlacementRules {
+		decisions = append(decisions, leafHubPlacementRule.Status.Decisions...)
+	}
+	return decisions
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// the compliance of all the global policies which aren't deleted
+const policyComplianceQuery = `SELECT c.policy_id, c.cluster_name, c.leaf_hub_name, c.compliance
+	FROM status.compliance c JOIN spec.policies p ON p.id = c.policy_id
+	WHERE p.deleted = FALSE ORDER BY c.policy_id, c.leaf_hub_name, c.cluster_name`
+
+// clusterCompliance is the compliance of the policy on a managed cluster of a leaf hub.
+type clusterCompliance struct {
+	PolicyID    string
+	ClusterName string
+	LeafHubName string
+	Compliance  string
+}
+
+// AddPolicyStatusWriter adds the writer of the per-cluster compliance of the global policies to the manager.
+func AddPolicyStatusWriter(mgr ctrl.Manager, interval time.Duration) error {
+	if err := mgr.Add(&genericStatusWriter{
+		client:         mgr.GetClient(),
+		log:            ctrl.Log.WithName("policies-status-writer"),
+		interval:       interval,
+		tableName:      "policies",
+		createInstance: func() client.Object { return &policyv1.Policy{} },
+		loadStatuses:   loadPolicyStatuses,
+	}); err != nil {
+		return fmt.Errorf("failed to add policy status writer to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func loadPolicyStatuses(db *gorm.DB) (setStatusFunc, error) {
+	var compliances []clusterCompliance
+	if err := db.Raw(policyComplianceQuery).Scan(&compliances).Error; err != nil {
+		return nil, fmt.Errorf("failed to query the compliance of the policies: %w", err)
+	}
+	policyIDToCompliances := make(map[string][]clusterCompliance)
+	for _, compliance := range compliances {
+		policyIDToCompliances[compliance.PolicyID] = append(policyIDToCompliances[compliance.PolicyID], compliance)
+	}
+
+	return func(id string, instance client.Object) error {
+		policy, ok := instance.(*policyv1.Policy)
+		if !ok {
+			panic("wrong instance passed to setPolicyStatus: not a Policy")
+		}
+
+		policy.Status.Status, policy.Status.ComplianceState = aggregatePolicyCompliance(policyIDToCompliances[id])
+		return nil
+	}, nil
+}
+
+// aggregatePolicyCompliance returns the compliance per cluster, and the policy is non compliant if any cluster is non
+// compliant, compliant if all the clusters are compliant, otherwise the compliance is unknown.
+func aggregatePolicyCompliance(compliances []clusterCompliance) (
+	[]*policyv1.CompliancePerClusterStatus, policyv1.ComplianceState,
+) {
+	if len(compliances) == 0 {
+		return nil, ""
+	}
+
+	compliancePerClusterStatuses := make([]*policyv1.CompliancePerClusterStatus, 0, len(compliances))
+	compliantClusters, nonCompliantClusters := 0, 0
+	for _, compliance := range compliances {
+		var complianceState policyv1.ComplianceState
+		switch database.ComplianceStatus(compliance.Compliance) {
+		case database.Compliant:
+			complianceState = policyv1.Compliant
+			compliantClusters++
+		case database.NonCompliant:
+			complianceState = policyv1.NonCompliant
+			nonCompliantClusters++
+		}
+		// the namespace of the replicated policy on the leaf hub is the name of the managed cluster
+		compliancePerClusterStatuses = append(compliancePerClusterStatuses, &policyv1.CompliancePerClusterStatus{
+			ComplianceState:  complianceState,
+			ClusterName:      compliance.ClusterName,
+			ClusterNamespace: compliance.ClusterName,
+		})
+	}
+
+	switch {
+	case nonCompliantClusters > 0:
+		return compliancePerClusterStatuses, policyv1.NonCompliant
+	case compliantClusters == len(compliances):
+		return compliancePerClusterStatuses, policyv1.Compliant
+	default:
+		return compliancePerClusterStatuses, ""
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const defaultStatusWriteInterval = 5 * time.Second
+
+// AddStatusWriters adds the writers of the aggregated status of the global resources to the manager, the status of
+// the leaf hubs in the database is written back onto the global resources every interval.
+func AddStatusWriters(mgr ctrl.Manager, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultStatusWriteInterval
+	}
+
+	addStatusWriterFunctions := []func(ctrl.Manager, time.Duration) error{
+		AddPolicyStatusWriter,
+		AddPlacementRuleStatusWriter,
+		AddPlacementStatusWriter,
+		AddSubscriptionStatusWriter,
+		AddAppliedConditionsWriter,
+	}
+
+	for _, addStatusWriterFunction := range addStatusWriterFunctions {
+		if err := addStatusWriterFunction(mgr, interval); err != nil {
+			return fmt.Errorf("failed to add status writer: %w", err)
+		}
+	}
+
+	return nil
+}
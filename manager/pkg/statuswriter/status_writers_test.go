@@ -0,0 +1,153 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/appliedstatus"
+)
+
+func TestAggregatePolicyCompliance(t *testing.T) {
+	statuses, complianceState := aggregatePolicyCompliance(nil)
+	assert.Nil(t, statuses)
+	assert.Equal(t, policyv1.ComplianceState(""), complianceState)
+
+	statuses, complianceState = aggregatePolicyCompliance([]clusterCompliance{
+		{ClusterName: "cluster1", LeafHubName: "hub1", Compliance: "compliant"},
+		{ClusterName: "cluster2", LeafHubName: "hub2", Compliance: "compliant"},
+	})
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, policyv1.Compliant, complianceState)
+
+	statuses, complianceState = aggregatePolicyCompliance([]clusterCompliance{
+		{ClusterName: "cluster1", LeafHubName: "hub1", Compliance: "compliant"},
+		{ClusterName: "cluster2", LeafHubName: "hub1", Compliance: "unknown"},
+	})
+	assert.Equal(t, policyv1.ComplianceState(""), statuses[1].ComplianceState)
+	assert.Equal(t, policyv1.ComplianceState(""), complianceState)
+
+	statuses, complianceState = aggregatePolicyCompliance([]clusterCompliance{
+		{ClusterName: "cluster1", LeafHubName: "hub1", Compliance: "unknown"},
+		{ClusterName: "cluster2", LeafHubName: "hub2", Compliance: "non_compliant"},
+	})
+	assert.Equal(t, &policyv1.CompliancePerClusterStatus{
+		ComplianceState:  policyv1.NonCompliant,
+		ClusterName:      "cluster2",
+		ClusterNamespace: "cluster2",
+	}, statuses[1])
+	assert.Equal(t, policyv1.NonCompliant, complianceState)
+}
+
+func TestAggregatePlacementRuleDecisions(t *testing.T) {
+	decisions := aggregatePlacementRuleDecisions([]*placementrulev1.PlacementRule{
+		{Status: placementrulev1.PlacementRuleStatus{Decisions: []placementrulev1.PlacementDecision{
+			{ClusterName: "cluster1", ClusterNamespace: "cluster1"},
+		}}},
+		{Status: placementrulev1.PlacementRuleStatus{}},
+		{Status: placementrulev1.PlacementRuleStatus{Decisions: []placementrulev1.PlacementDecision{
+			{ClusterName: "cluster2", ClusterNamespace: "cluster2"},
+			{ClusterName: "cluster3", ClusterNamespace: "cluster3"},
+		}}},
+	})
+	assert.Equal(t, []placementrulev1.PlacementDecision{
+		{ClusterName: "cluster1", ClusterNamespace: "cluster1"},
+		{ClusterName: "cluster2", ClusterNamespace: "cluster2"},
+		{ClusterName: "cluster3", ClusterNamespace: "cluster3"},
+	}, decisions)
+}
+
+func TestAggregatePlacementStatus(t *testing.T) {
+	leafHubPlacement := func(selectedClusters int32, satisfied metav1.ConditionStatus) *clusterv1beta1.Placement {
+		return &clusterv1beta1.Placement{Status: clusterv1beta1.PlacementStatus{
+			NumberOfSelectedClusters: selectedClusters,
+			Conditions: []metav1.Condition{
+				{Type: clusterv1beta1.PlacementConditionSatisfied, Status: satisfied},
+			},
+		}}
+	}
+
+	status := &clusterv1beta1.PlacementStatus{}
+	aggregatePlacementStatus(status, map[string]*clusterv1beta1.Placement{
+		"hub1": leafHubPlacement(2, metav1.ConditionTrue),
+		"hub2": leafHubPlacement(3, metav1.ConditionTrue),
+	})
+	assert.Equal(t, int32(5), status.NumberOfSelectedClusters)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, clusterv1beta1.PlacementConditionSatisfied))
+
+	aggregatePlacementStatus(status, map[string]*clusterv1beta1.Placement{
+		"hub1": leafHubPlacement(2, metav1.ConditionTrue),
+		"hub2": leafHubPlacement(0, metav1.ConditionFalse),
+		"hub3": {},
+	})
+	assert.Equal(t, int32(2), status.NumberOfSelectedClusters)
+	condition := meta.FindStatusCondition(status.Conditions, clusterv1beta1.PlacementConditionSatisfied)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, placementUnsatisfiedReason, condition.Reason)
+	assert.Equal(t, "the placement isn't satisfied on the leaf hubs: hub2, hub3", condition.Message)
+}
+
+func TestSubscriptionSummary(t *testing.T) {
+	deployed := appsv1alpha1.SubscriptionUnitStatus{Phase: appsv1alpha1.PackageDeployed}
+	failed := appsv1alpha1.SubscriptionUnitStatus{Phase: appsv1alpha1.PackageDeployFailed}
+	unknown := appsv1alpha1.SubscriptionUnitStatus{}
+
+	summary := subscriptionSummary{}
+	summary.add([]appsv1alpha1.SubscriptionUnitStatus{deployed, deployed})
+	summary.add([]appsv1alpha1.SubscriptionUnitStatus{deployed, unknown})
+	summary.add(nil)
+	summary.add([]appsv1alpha1.SubscriptionUnitStatus{deployed, failed})
+	assert.Equal(t, subscriptionSummary{Deployed: 1, InProgress: 2, Failed: 1, Clusters: 4}, summary)
+	assert.Equal(t, appsv1.SubscriptionFailed, summary.phase())
+	assert.Equal(t, "deployed: 1, inProgress: 2, failed: 1, propagationFailed: 0, clusters: 4", summary.String())
+
+	summary.add([]appsv1alpha1.SubscriptionUnitStatus{
+		failed, {Phase: appsv1alpha1.PackagePropagationFailed},
+	})
+	assert.Equal(t, 1, summary.PropagationFailed)
+	assert.Equal(t, appsv1.SubscriptionPropagationFailed, summary.phase())
+
+	assert.Equal(t, appsv1.SubscriptionPropagated, (&subscriptionSummary{Deployed: 1}).phase())
+}
+
+func TestSetAppliedConditions(t *testing.T) {
+	applied := true
+	updatedAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	leafHubs := []appliedstatus.LeafHubAppliedStatus{
+		toLeafHubAppliedStatus(&resourceAppliedStatus{LeafHubName: "hub1", Applied: &applied, UpdatedAt: &updatedAt}),
+		toLeafHubAppliedStatus(&resourceAppliedStatus{LeafHubName: "hub2"}),
+	}
+	assert.True(t, leafHubs[1].Pending)
+
+	placement := &clusterv1beta1.Placement{Status: clusterv1beta1.PlacementStatus{
+		Conditions: []metav1.Condition{{Type: "PlacementSatisfied", Status: metav1.ConditionTrue}},
+	}}
+	conditions := getStatusConditions(placement)
+	assert.NotNil(t, conditions)
+	assert.True(t, setConditions(conditions, appliedstatus.Conditions(leafHubs)))
+	assert.Len(t, placement.Status.Conditions, 3)
+	appliedCondition := meta.FindStatusCondition(placement.Status.Conditions, appliedstatus.AppliedConditionType)
+	assert.Equal(t, metav1.ConditionFalse, appliedCondition.Status)
+	// the same conditions aren't written again
+	assert.False(t, setConditions(conditions, appliedstatus.Conditions(leafHubs)))
+
+	leafHubs[1] = toLeafHubAppliedStatus(&resourceAppliedStatus{LeafHubName: "hub2", Applied: &applied,
+		UpdatedAt: &updatedAt})
+	assert.True(t, setConditions(conditions, appliedstatus.Conditions(leafHubs)))
+	appliedCondition = meta.FindStatusCondition(placement.Status.Conditions, appliedstatus.AppliedConditionType)
+	assert.Equal(t, metav1.ConditionTrue, appliedCondition.Status)
+
+	// the policy has no status conditions, the conditions are written in the annotation
+	assert.Nil(t, getStatusConditions(&policyv1.Policy{}))
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package statuswriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// subscriptionSummary counts the clusters by the deployment phase of the subscription across the leaf hubs.
+type subscriptionSummary struct {
+	Deployed          int
+	InProgress        int
+	Failed            int
+	PropagationFailed int
+	Clusters          int
+}
+
+// AddSubscriptionStatusWriter adds the writer of the deployment results of the global subscriptions to the manager.
+func AddSubscriptionStatusWriter(mgr ctrl.Manager, interval time.Duration) error {
+	if err := mgr.Add(&genericStatusWriter{
+		client:         mgr.GetClient(),
+		log:            ctrl.Log.WithName("subscriptions-status-writer"),
+		interval:       interval,
+		tableName:      "subscriptions",
+		createInstance: func() client.Object { return &appsv1.Subscription{} },
+		loadStatuses:   loadSubscriptionStatuses,
+	}); err != nil {
+		return fmt.Errorf("failed to add subscription status writer to the manager: %w", err)
+	}
+
+	return nil
+}
+
+// loadSubscriptionStatuses loads the subscription statuses of the clusters, they're stored by the leaf hubs with the
+// id of their global subscription.
+func loadSubscriptionStatuses(db *gorm.DB) (setStatusFunc, error) {
+	idToPayloads, err := listLeafHubPayloads(db, database.SubscriptionStatusesTableName, "subscriptions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status of the subscriptions: %w", err)
+	}
+
+	return func(id string, instance client.Object) error {
+		subscription, ok := instance.(*appsv1.Subscription)
+		if !ok {
+			panic("wrong instance passed to setSubscriptionStatus: not a Subscription")
+		}
+
+		payloads := idToPayloads[id]
+		if len(payloads) == 0 {
+			return nil
+		}
+
+		summary := subscriptionSummary{}
+		for _, payload := range payloads {
+			subscriptionStatus := &appsv1alpha1.SubscriptionStatus{}
+			if err := json.Unmarshal(payload.Payload, subscriptionStatus); err != nil {
+				return fmt.Errorf("failed to unmarshal the subscription status of leaf hub %s: %w",
+					payload.LeafHubName, err)
+			}
+			summary.add(subscriptionStatus.Statuses.SubscriptionStatus)
+		}
+
+		phase, message := summary.phase(), summary.String()
+		// the update time is kept if the aggregated status isn't changed
+		if subscription.Status.Phase != phase || subscription.Status.Message != message {
+			subscription.Status.Phase = phase
+			subscription.Status.Message = message
+			subscription.Status.LastUpdateTime = metav1.Now()
+		}
+		return nil
+	}, nil
+}
+
+// add counts the cluster by the worst phase of its packages, a cluster without packages or with packages of unknown
+// phase is still in progress.
+func (s *subscriptionSummary) add(packages []appsv1alpha1.SubscriptionUnitStatus) {
+	s.Clusters++
+
+	deployed, failed, propagationFailed := 0, 0, 0
+	for _, unitStatus := range packages {
+		switch unitStatus.Phase {
+		case appsv1alpha1.PackageDeployed:
+			deployed++
+		case appsv1alpha1.PackageDeployFailed:
+			failed++
+		case appsv1alpha1.PackagePropagationFailed:
+			propagationFailed++
+		}
+	}
+
+	switch {
+	case propagationFailed > 0:
+		s.PropagationFailed++
+	case failed > 0:
+		s.Failed++
+	case deployed > 0 && deployed == len(packages):
+		s.Deployed++
+	default:
+		s.InProgress++
+	}
+}
+
+// phase returns the phase of the subscription on the global hub, it's failed if it's failed on any cluster.
+func (s *subscriptionSummary) phase() appsv1.SubscriptionPhase {
+	switch {
+	case s.PropagationFailed > 0:
+		return appsv1.SubscriptionPropagationFailed
+	case s.Failed > 0:
+		return appsv1.SubscriptionFailed
+	default:
+		return appsv1.SubscriptionPropagated
+	}
+}
+
+func (s *subscriptionSummary) String() string {
+	return fmt.Sprintf("deployed: %d, inProgress: %d, failed: %d, propagationFailed: %d, clusters: %d",
+		s.Deployed, s.InProgress, s.Failed, s.PropagationFailed, s.Clusters)
+}
@@ -7,10 +7,14 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
-	placementrulesv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -19,57 +23,101 @@ import (
 
 var log = logf.Log.WithName("admission-handler")
 
-// AdmissionHandler is to handle the admission webhook for placementrule and placement
+// AdmissionHandler is to handle the admission webhook for placementrule and placement, and to record the users
+// changing the global resources for the audit log
 type AdmissionHandler struct {
-	Client  client.Client
-	decoder *admission.Decoder
+	Client client.Client
+	// ManagerNamespace is the namespace of the manager, the requests of its service accounts are the changes made by
+	// the global hub itself, so they don't replace the users recorded on the global resources
+	ManagerNamespace string
+	decoder          *admission.Decoder
 }
 
 func (a *AdmissionHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
 	log.Info("admission webhook is called", "name", req.Name, "namespace",
 		req.Namespace, "kind", req.Kind.Kind, "operation", req.Operation)
 
-	if req.Kind.Kind == "Placement" {
-		placement := &clusterv1beta1.Placement{}
-		err := a.decoder.Decode(req, placement)
-		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
-		}
+	if req.Operation == admissionv1.Delete {
+		return a.recordDeleter(ctx, req)
+	}
 
-		// don't schedule the policy/application for global hub resources
-		if _, found := placement.Labels[constants.GlobalHubGlobalResourceLabel]; found {
-			if placement.Annotations == nil {
-				placement.Annotations = map[string]string{}
-			}
-			placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] = "true"
-
-			marshaledPlacement, err := json.Marshal(placement)
-			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, err)
-			}
-			return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPlacement)
-		}
+	obj := &unstructured.Unstructured{}
+	if err := a.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// don't schedule the policy/application for global hub resources
+	if _, found := obj.GetLabels()[constants.GlobalHubGlobalResourceLabel]; !found {
+		return admission.Allowed("")
+	}
+
+	if req.Kind.Kind == "Placement" {
+		setAnnotation(obj, clusterv1beta1.PlacementDisableAnnotation, "true")
 	} else if req.Kind.Kind == "PlacementRule" {
-		placementrule := &placementrulesv1.PlacementRule{}
-		err := a.decoder.Decode(req, placementrule)
-		if err != nil {
+		if err := unstructured.SetNestedField(obj.Object, constants.GlobalHubSchedulerName, "spec",
+			"schedulerName"); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
+	}
 
-		if _, found := placementrule.Labels[constants.GlobalHubGlobalResourceLabel]; found {
-			placementrule.Spec.SchedulerName = constants.GlobalHubSchedulerName
+	// the user of the request is recorded on the global resource, so that its change is audited with the user
+	if !a.isManagerRequest(req) {
+		setAnnotation(obj, constants.AuditRequestedByAnnotation, req.UserInfo.Username)
+	}
 
-			marshaledPlacementRule, err := json.Marshal(placementrule)
-			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, err)
-			}
-			return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPlacementRule)
-		}
+	marshaledObj, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
 	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledObj)
+}
 
+// recordDeleter records the user deleting the global resource on it, the resource is kept by the cleanup finalizer
+// until it's removed from the database, then the deletion is audited with the user. the deletion isn't denied if the
+// user can't be recorded.
+func (a *AdmissionHandler) recordDeleter(ctx context.Context, req admission.Request) admission.Response {
+	if (req.DryRun != nil && *req.DryRun) || a.isManagerRequest(req) {
+		return admission.Allowed("")
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(req.OldObject.Raw, obj); err != nil {
+		return admission.Allowed("")
+	}
+	if _, found := obj.GetLabels()[constants.GlobalHubGlobalResourceLabel]; !found ||
+		!controllerutil.ContainsFinalizer(obj, constants.GlobalHubCleanupFinalizer) {
+		return admission.Allowed("")
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{constants.AuditDeletedByAnnotation: req.UserInfo.Username},
+		},
+	})
+	if err == nil {
+		err = a.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+	}
+	if err != nil {
+		log.Error(err, "failed to record the user deleting the global resource", "name", req.Name,
+			"namespace", req.Namespace, "kind", req.Kind.Kind)
+	}
 	return admission.Allowed("")
 }
 
+// isManagerRequest returns true if the request is made by a service account of the manager namespace.
+func (a *AdmissionHandler) isManagerRequest(req admission.Request) bool {
+	return a.ManagerNamespace != "" &&
+		strings.HasPrefix(req.UserInfo.Username, "system:serviceaccount:"+a.ManagerNamespace+":")
+}
+
+func setAnnotation(obj *unstructured.Unstructured, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
 // AdmissionHandler implements admission.DecoderInjector.
 // A decoder will be automatically injected.
 
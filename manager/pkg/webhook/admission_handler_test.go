@@ -74,7 +74,8 @@ var _ = Describe("Multicluster hub manager webhook", func() {
 				if err := c.Get(ctx, client.ObjectKeyFromObject(testPlacement), placement); err != nil {
 					return false
 				}
-				return placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] == "true"
+				return placement.Annotations[clusterv1beta1.PlacementDisableAnnotation] == "true" &&
+					placement.Annotations[constants.AuditRequestedByAnnotation] != ""
 			}, 5*time.Second).Should(BeTrue())
 		})
 
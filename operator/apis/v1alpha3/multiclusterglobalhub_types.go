@@ -19,6 +19,8 @@ package v1alpha3
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 )
 
 // DataLayerType specifies the type of data layer that global hub stores and transports the data.
@@ -41,6 +43,19 @@ const (
 	CloudEvents  TransportFormatType = "cloudEvents"
 )
 
+// SchedulerIntervalType specifies the interval of moving the policy compliance history.
+// +kubebuilder:validation:Enum:="month";"week";"day";"hour";"minute";"second"
+type SchedulerIntervalType string
+
+const (
+	EveryMonth  SchedulerIntervalType = "month"
+	EveryWeek   SchedulerIntervalType = "week"
+	EveryDay    SchedulerIntervalType = "day"
+	EveryHour   SchedulerIntervalType = "hour"
+	EveryMinute SchedulerIntervalType = "minute"
+	EverySecond SchedulerIntervalType = "second"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName={mgh,mcgh}
@@ -71,6 +86,16 @@ type MulticlusterGlobalHubSpec struct {
 	// largeScale: large scale data layer served by kafka and postgres.
 	// +kubebuilder:validation:Required
 	DataLayer *DataLayerConfig `json:"dataLayer"`
+	// ImageRepository overrides the repository of the multicluster global hub images
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// SchedulerInterval is the interval of moving the policy compliance history into the history tables.
+	// valid value can be "month, week, day, hour, minute, second"
+	// +kubebuilder:default:="day"
+	// +optional
+	SchedulerInterval SchedulerIntervalType `json:"schedulerInterval,omitempty"`
+	// AgentConfigs are the configuration of the agents running on the managed hubs, and the overrides of it
+	globalhubv1alpha3.AgentConfigs `json:",inline"`
 }
 
 // DataLayerConfig is a discriminated union of data layer specific configuration.
@@ -163,6 +163,7 @@ func (in *MulticlusterGlobalHubSpec) DeepCopyInto(out *MulticlusterGlobalHubSpec
 		*out = new(DataLayerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	in.AgentConfigs.DeepCopyInto(&out.AgentConfigs)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubSpec.
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+)
+
+// GlobalResourceRule is the resource of a kind registered by a GlobalResourceType, the manager and the agents are
+// granted the access to it so that they sync the kind as global resources.
+type GlobalResourceRule struct {
+	Group    string
+	Resource string
+}
+
+// GetGlobalResourceRules returns the resources of the kinds registered by the GlobalResourceTypes, sorted by the group
+// and the resource. the kinds which can't be mapped to resources yet, like the ones whose CRDs aren't installed, are
+// skipped, they're added once the GlobalResourceTypes are reconciled again.
+func GetGlobalResourceRules(ctx context.Context, c client.Client, mapper meta.RESTMapper) (
+	[]GlobalResourceRule, error,
+) {
+	resourceTypeList := &globalhubv1alpha3.GlobalResourceTypeList{}
+	if err := c.List(ctx, resourceTypeList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	found := map[GlobalResourceRule]bool{}
+	rules := []GlobalResourceRule{}
+	for _, resourceType := range resourceTypeList.Items {
+		if !resourceType.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		gvk := resourceType.Spec.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			continue
+		}
+		rule := GlobalResourceRule{Group: gvk.Group, Resource: mapping.Resource.Resource}
+		if !found[rule] {
+			found[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Group != rules[j].Group {
+			return rules[i].Group < rules[j].Group
+		}
+		return rules[i].Resource < rules[j].Resource
+	})
+	return rules, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+)
+
+func TestGetGlobalResourceRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := globalhubv1alpha3.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+		meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	newResourceType := func(name, group, kind string) *globalhubv1alpha3.GlobalResourceType {
+		return &globalhubv1alpha3.GlobalResourceType{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       globalhubv1alpha3.GlobalResourceTypeSpec{Group: group, Version: "v1", Kind: kind},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newResourceType("clusterroles", "rbac.authorization.k8s.io", "ClusterRole"),
+		newResourceType("configmaps", "", "ConfigMap"),
+		newResourceType("configmaps-duplicate", "", "ConfigMap"),
+		// the CRD of the kind isn't installed
+		newResourceType("unknown", "example.com", "Unknown"),
+	).Build()
+
+	rules, err := GetGlobalResourceRules(context.Background(), c, mapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []GlobalResourceRule{
+		{Group: "", Resource: "configmaps"},
+		{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected rules %v, but got %v", expected, rules)
+	}
+}
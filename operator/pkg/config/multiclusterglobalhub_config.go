@@ -28,6 +28,7 @@ import (
 
 	operatorv1alpha3 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha3"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
@@ -51,6 +52,9 @@ const (
 	GrafanaImageKey          = "grafana"
 )
 
+// DefaultAgentConfigSource is the source of the effective agent config of the leaf hubs without a matching override
+const DefaultAgentConfigSource = globalhubv1alpha3.DefaultAgentConfigSource
+
 var (
 	managedClusters      = []string{}
 	hohMGHNamespacedName = types.NamespacedName{}
@@ -124,11 +128,36 @@ func SkipDBInit(mgh *operatorv1alpha3.MulticlusterGlobalHub) bool {
 	return false
 }
 
-// GetSchedulerInterval returns the scheduler interval for moving policy compliance history
+// GetSchedulerInterval returns the scheduler interval for moving policy compliance history,
+// the deprecated annotation is only used if the interval isn't set in the spec
 func GetSchedulerInterval(mgh *operatorv1alpha3.MulticlusterGlobalHub) string {
+	if mgh.Spec.SchedulerInterval != "" {
+		return string(mgh.Spec.SchedulerInterval)
+	}
 	return getAnnotation(mgh, operatorconstants.AnnotationMGHSchedulerInterval)
 }
 
+// GetImageRepository returns the image repository override, the deprecated annotation is only used if the
+// repository isn't set in the spec
+func GetImageRepository(mgh *operatorv1alpha3.MulticlusterGlobalHub) string {
+	if mgh.Spec.ImageRepository != "" {
+		return mgh.Spec.ImageRepository
+	}
+	return getAnnotation(mgh, operatorconstants.AnnotationImageRepo)
+}
+
+// GetAgentConfig returns the agent config of the MulticlusterGlobalHub instance with the defaults applied
+func GetAgentConfig(mgh *operatorv1alpha3.MulticlusterGlobalHub) *globalhubv1alpha3.AgentConfigSpec {
+	return mgh.Spec.GetAgentConfig()
+}
+
+// GetLeafHubAgentConfig returns the effective agent config of a leaf hub and the source of it
+func GetLeafHubAgentConfig(mgh *operatorv1alpha3.MulticlusterGlobalHub, leafHubName string,
+	leafHubLabels map[string]string,
+) (*globalhubv1alpha3.AgentConfigSpec, string, error) {
+	return mgh.Spec.GetLeafHubAgentConfig(leafHubName, leafHubLabels)
+}
+
 // GetImageOverridesConfigmap returns the images override configmap annotation, or an empty string if not set
 func GetImageOverridesConfigmap(mgh *operatorv1alpha3.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationImageOverridesCM)
@@ -146,7 +175,7 @@ func SetImageOverrides(mgh *operatorv1alpha3.MulticlusterGlobalHub) error {
 	}
 
 	// second override image repo
-	imageRepoOverride := getAnnotation(mgh, operatorconstants.AnnotationImageRepo)
+	imageRepoOverride := GetImageRepository(mgh)
 	if imageRepoOverride != "" {
 		for imageKey, imageRef := range imageOverrides {
 			imageIndex := strings.LastIndex(imageRef, "/")
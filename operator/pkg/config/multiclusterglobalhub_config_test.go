@@ -19,11 +19,13 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	operatorv1alpha3 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha3"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
@@ -124,3 +126,194 @@ func TestGetOauthSessionSecret(t *testing.T) {
 		t.Errorf("oauth session secret is not consistent")
 	}
 }
+
+func TestGetSchedulerInterval(t *testing.T) {
+	mgh := &operatorv1alpha3.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{operatorconstants.AnnotationMGHSchedulerInterval: "hour"},
+		},
+	}
+	// the deprecated annotation is used if the spec doesn't set the interval
+	if got := GetSchedulerInterval(mgh); got != "hour" {
+		t.Errorf("GetSchedulerInterval() = %s, want hour", got)
+	}
+
+	mgh.Spec.SchedulerInterval = operatorv1alpha3.EveryWeek
+	if got := GetSchedulerInterval(mgh); got != "week" {
+		t.Errorf("GetSchedulerInterval() = %s, want week", got)
+	}
+}
+
+func TestGetAgentConfig(t *testing.T) {
+	mgh := &operatorv1alpha3.MulticlusterGlobalHub{}
+	agentConfig := GetAgentConfig(mgh)
+	if agentConfig.AggregationLevel != globalhubv1alpha3.FullAggregation || !agentConfig.LocalPoliciesEnabled() {
+		t.Errorf("GetAgentConfig() = %v, want the default agent config", agentConfig)
+	}
+	if agentConfig.SyncIntervals.GetControlInfoInterval() != globalhubv1alpha3.DefaultControlInfoSyncInterval {
+		t.Errorf("GetControlInfoInterval() = %s, want %s", agentConfig.SyncIntervals.GetControlInfoInterval(),
+			globalhubv1alpha3.DefaultControlInfoSyncInterval)
+	}
+
+	mgh.Spec.AgentConfig = &globalhubv1alpha3.AgentConfigSpec{
+		AggregationLevel: globalhubv1alpha3.MinimalAggregation,
+		SyncIntervals: &globalhubv1alpha3.SyncIntervalsConfig{
+			Policies: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	agentConfig = GetAgentConfig(mgh)
+	// the local policies keep the agent default if the config doesn't set them
+	if agentConfig.AggregationLevel != globalhubv1alpha3.MinimalAggregation || !agentConfig.LocalPoliciesEnabled() {
+		t.Errorf("GetAgentConfig() = %v, want the minimal aggregation with local policies", agentConfig)
+	}
+
+	enableLocalPolicies := false
+	mgh.Spec.AgentConfig.EnableLocalPolicies = &enableLocalPolicies
+	if agentConfig = GetAgentConfig(mgh); agentConfig.LocalPoliciesEnabled() {
+		t.Errorf("GetAgentConfig() = %v, want the local policies disabled", agentConfig)
+	}
+	if agentConfig.SyncIntervals.GetPoliciesInterval() != time.Minute {
+		t.Errorf("GetPoliciesInterval() = %s, want 1m", agentConfig.SyncIntervals.GetPoliciesInterval())
+	}
+}
+
+func TestGetLeafHubAgentConfig(t *testing.T) {
+	enableLocalPolicies, disableLocalPolicies := true, false
+	mgh := &operatorv1alpha3.MulticlusterGlobalHub{
+		Spec: operatorv1alpha3.MulticlusterGlobalHubSpec{
+			AgentConfigs: globalhubv1alpha3.AgentConfigs{
+				AgentConfig: &globalhubv1alpha3.AgentConfigSpec{
+					AggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+					EnableLocalPolicies: &enableLocalPolicies,
+					SyncIntervals: &globalhubv1alpha3.SyncIntervalsConfig{
+						Policies: &metav1.Duration{Duration: 10 * time.Second},
+					},
+				},
+				// the overrides are listed from the least specific, the precedence doesn't depend on the order
+				AgentConfigOverrides: []globalhubv1alpha3.AgentConfigOverride{
+					{
+						Name: "edge-west",
+						LeafHubSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"region": "west"},
+						},
+						AggregationLevel: globalhubv1alpha3.FullAggregation,
+					},
+					{
+						Name: "edge-gpu",
+						LeafHubSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"env": "edge", "gpu": "true"},
+						},
+						AggregationLevel: globalhubv1alpha3.FullAggregation,
+					},
+					{
+						Name:             "production",
+						LeafHubNames:     []string{"hub1"},
+						AggregationLevel: globalhubv1alpha3.FullAggregation,
+					},
+					{
+						Name: "edge",
+						LeafHubSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"env": "edge"},
+						},
+						EnableLocalPolicies: &disableLocalPolicies,
+						SyncIntervals: &globalhubv1alpha3.SyncIntervalsOverride{
+							ManagedClusters: &metav1.Duration{Duration: time.Minute},
+						},
+					},
+					{
+						Name:             "all",
+						LeafHubSelector:  &metav1.LabelSelector{},
+						AggregationLevel: globalhubv1alpha3.FullAggregation,
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                    string
+		leafHubName             string
+		labels                  map[string]string
+		wantSource              string
+		wantAggregationLevel    globalhubv1alpha3.AggregationLevelType
+		wantEnableLocalPolicies bool
+		wantManagedClusters     time.Duration
+	}{
+		{
+			name:                    "matched by name",
+			leafHubName:             "hub1",
+			labels:                  map[string]string{"env": "edge"},
+			wantSource:              "production",
+			wantAggregationLevel:    globalhubv1alpha3.FullAggregation,
+			wantEnableLocalPolicies: true,
+			wantManagedClusters:     globalhubv1alpha3.DefaultStatusSyncInterval,
+		},
+		{
+			name:                    "matched by selector",
+			leafHubName:             "hub2",
+			labels:                  map[string]string{"env": "edge"},
+			wantSource:              "edge",
+			wantAggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+			wantEnableLocalPolicies: false,
+			wantManagedClusters:     time.Minute,
+		},
+		{
+			name:                    "the selector with more requirements wins",
+			leafHubName:             "hub4",
+			labels:                  map[string]string{"env": "edge", "gpu": "true"},
+			wantSource:              "edge-gpu",
+			wantAggregationLevel:    globalhubv1alpha3.FullAggregation,
+			wantEnableLocalPolicies: true,
+			wantManagedClusters:     globalhubv1alpha3.DefaultStatusSyncInterval,
+		},
+		{
+			name:                    "the first name in the alphabetical order wins the ties",
+			leafHubName:             "hub5",
+			labels:                  map[string]string{"env": "edge", "region": "west"},
+			wantSource:              "edge",
+			wantAggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+			wantEnableLocalPolicies: false,
+			wantManagedClusters:     time.Minute,
+		},
+		{
+			name:                    "empty selector doesn't match",
+			leafHubName:             "hub3",
+			labels:                  map[string]string{"env": "lab"},
+			wantSource:              DefaultAgentConfigSource,
+			wantAggregationLevel:    globalhubv1alpha3.MinimalAggregation,
+			wantEnableLocalPolicies: true,
+			wantManagedClusters:     globalhubv1alpha3.DefaultStatusSyncInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agentConfig, source, err := GetLeafHubAgentConfig(mgh, tt.leafHubName, tt.labels)
+			if err != nil {
+				t.Fatalf("GetLeafHubAgentConfig() error = %v", err)
+			}
+			if source != tt.wantSource {
+				t.Errorf("GetLeafHubAgentConfig() source = %s, want %s", source, tt.wantSource)
+			}
+			if agentConfig.AggregationLevel != tt.wantAggregationLevel {
+				t.Errorf("AggregationLevel = %s, want %s", agentConfig.AggregationLevel, tt.wantAggregationLevel)
+			}
+			if agentConfig.LocalPoliciesEnabled() != tt.wantEnableLocalPolicies {
+				t.Errorf("EnableLocalPolicies = %t, want %t", agentConfig.LocalPoliciesEnabled(),
+					tt.wantEnableLocalPolicies)
+			}
+			if agentConfig.SyncIntervals.GetManagedClustersInterval() != tt.wantManagedClusters {
+				t.Errorf("GetManagedClustersInterval() = %s, want %s",
+					agentConfig.SyncIntervals.GetManagedClustersInterval(), tt.wantManagedClusters)
+			}
+			// the intervals which aren't overridden are inherited from the agent config
+			if agentConfig.SyncIntervals.GetPoliciesInterval() != 10*time.Second {
+				t.Errorf("GetPoliciesInterval() = %s, want 10s", agentConfig.SyncIntervals.GetPoliciesInterval())
+			}
+		})
+	}
+
+	// the overrides don't change the agent config of the MulticlusterGlobalHub
+	if mgh.Spec.AgentConfig.SyncIntervals.ManagedClusters != nil {
+		t.Errorf("the agent config of the MulticlusterGlobalHub is changed by the overrides")
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package constants
 
+import "github.com/stolostron/multicluster-global-hub/pkg/constants"
+
 const (
 	// ControllerLeaderElectionConfig allows customizing LeaseDuration, RenewDeadline and RetryPeriod
 	// for operator, manager and agent via the ConfigMap
@@ -31,6 +33,7 @@ const (
 	AnnotationMGHSkipDBInit = "mgh-skip-database-init"
 	// AnnotationImageRepo sits in MulticlusterGlobalHub annotations
 	// to identify a custom image repository to use
+	// Deprecated: use spec.imageRepository of the MulticlusterGlobalHub instead
 	AnnotationImageRepo = "mgh-image-repository"
 	// AnnotationImageOverridesCM sits in MulticlusterGlobalHub annotations
 	// to identify a custom configmap containing image overrides
@@ -38,6 +41,7 @@ const (
 	// AnnotationMGHSchedulerInterval sits in MulticlusterGlobalHub annotations
 	// to identify the scheduler interval for moving policy compliance history
 	// valid value can be "month, week, day, hour, minute, second"
+	// Deprecated: use spec.schedulerInterval of the MulticlusterGlobalHub instead
 	AnnotationMGHSchedulerInterval = "mgh-scheduler-interval"
 	// MGHOperandImagePrefix ...
 	MGHOperandImagePrefix = "RELATED_IMAGE_"
@@ -45,7 +49,7 @@ const (
 
 // hub installation constants
 const (
-	LocalClusterName = "local-cluster"
+	LocalClusterName = constants.LocalClusterName
 
 	OpenshiftMarketPlaceNamespace = "openshift-marketplace"
 	ACMSubscriptionPublicSource   = "redhat-operators"
@@ -57,7 +61,7 @@ const (
 // global hub agent constants
 const (
 	GHClusterManagementAddonName = "multicluster-global-hub-controller"
-	GHManagedClusterAddonName    = "multicluster-global-hub-controller"
+	GHManagedClusterAddonName    = constants.GHManagedClusterAddonName
 )
 
 // global hub names
@@ -66,10 +70,11 @@ const (
 	GHGrafanaDeploymentName = "multicluster-global-hub-grafana"
 )
 
-// global hub transport and storage secret names
+// global hub transport, storage and report storage secret names
 const (
-	GHTransportSecretName = "multicluster-global-hub-transport" // #nosec G101
-	GHStorageSecretName   = "multicluster-global-hub-storage"   // #nosec G101
+	GHTransportSecretName     = "multicluster-global-hub-transport"      // #nosec G101
+	GHStorageSecretName       = "multicluster-global-hub-storage"        // #nosec G101
+	GHReportStorageSecretName = "multicluster-global-hub-report-storage" // #nosec G101
 )
 
 const (
@@ -273,7 +273,7 @@ var _ = Describe("addon integration", Ordered, func() {
 				}, work)
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
-			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(9))
+			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(10))
 		})
 
 		It("Should create HoH agent and ACM when an OCP is imported", func() {
@@ -317,7 +317,7 @@ var _ = Describe("addon integration", Ordered, func() {
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
 			// contains both the ACM and the Global Hub manifests
-			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(18))
+			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(19))
 		})
 
 		It("Should create HoH addon when an OCP with deploy mode = default is imported in hosted mode", func() {
@@ -362,7 +362,7 @@ var _ = Describe("addon integration", Ordered, func() {
 				}, work)
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
-			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(9))
+			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(10))
 		})
 
 		It("Should create HoH addon when an OCP with deploy mode = Hosted is imported in hosted mode", func() {
@@ -413,7 +413,7 @@ var _ = Describe("addon integration", Ordered, func() {
 				}, work)
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
-			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(2))
+			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(3))
 			hostingWork := &workv1.ManifestWork{}
 			Eventually(func() error {
 				return k8sClient.Get(ctx, types.NamespacedName{
@@ -479,7 +479,7 @@ var _ = Describe("addon integration", Ordered, func() {
 				}, work)
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
-			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(11))
+			Expect(len(work.Spec.Workload.Manifests)).Should(Equal(12))
 			hostingWork := &workv1.ManifestWork{}
 			Eventually(func() error {
 				return k8sClient.Get(ctx, types.NamespacedName{
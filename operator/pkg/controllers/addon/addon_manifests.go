@@ -61,6 +61,13 @@ type ManifestsConfig struct {
 	Tolerations            []corev1.Toleration
 	AggregationLevel       string
 	EnableLocalPolicies    string
+	// sync intervals of the agent status syncers
+	ManagedClustersSyncInterval string
+	PoliciesSyncInterval        string
+	ControlInfoSyncInterval     string
+	AppliedStatusSyncInterval   string
+	// the agent is granted the access to the kinds registered by the GlobalResourceTypes
+	GlobalResourceRules []config.GlobalResourceRule
 }
 
 type HohAgentAddon struct {
@@ -191,10 +198,18 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 	log.Info("rendering manifests", "pullSecret", manifestsConfig.ImagePullSecretName,
 		"image", manifestsConfig.HoHAgentImage)
 
-	globalHubConfig := config.GetGlobalHubAgentConfig()
-	if globalHubConfig != nil {
-		manifestsConfig.AggregationLevel = globalHubConfig.Data["aggregationLevel"]
-		manifestsConfig.EnableLocalPolicies = globalHubConfig.Data["enableLocalPolicies"]
+	agentConfig := config.GetAgentConfig(mgh)
+	manifestsConfig.AggregationLevel = string(agentConfig.AggregationLevel)
+	manifestsConfig.EnableLocalPolicies = strconv.FormatBool(agentConfig.LocalPoliciesEnabled())
+	manifestsConfig.ManagedClustersSyncInterval = agentConfig.SyncIntervals.GetManagedClustersInterval().String()
+	manifestsConfig.PoliciesSyncInterval = agentConfig.SyncIntervals.GetPoliciesInterval().String()
+	manifestsConfig.ControlInfoSyncInterval = agentConfig.SyncIntervals.GetControlInfoInterval().String()
+	manifestsConfig.AppliedStatusSyncInterval = agentConfig.SyncIntervals.GetAppliedStatusInterval().String()
+
+	if manifestsConfig.GlobalResourceRules, err = config.GetGlobalResourceRules(a.ctx, a.client,
+		a.client.RESTMapper()); err != nil {
+		log.Error(err, "failed to get the rules of the global resource types")
+		return nil, err
 	}
 
 	if a.installACMHub(cluster) {
@@ -11,6 +11,8 @@ CREATE SCHEMA IF NOT EXISTS status;
 
 CREATE SCHEMA IF NOT EXISTS event;
 
+CREATE SCHEMA IF NOT EXISTS report;
+
 DO $$ BEGIN
   CREATE TYPE local_status.compliance_type AS ENUM (
     'compliant',
@@ -70,6 +70,17 @@ CREATE TABLE IF NOT EXISTS history.policies (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS history.resources (
+    id uuid PRIMARY KEY,
+    api_group text NOT NULL,
+    api_version text NOT NULL,
+    kind text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS history.subscriptions (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -88,6 +99,16 @@ CREATE TABLE IF NOT EXISTS history.local_compliance (
     CONSTRAINT local_policies_unique_constraint UNIQUE (policy_id, cluster_id, compliance_date)
 );
 
+CREATE TABLE IF NOT EXISTS history.compliance (
+    policy_id uuid NOT NULL,
+    cluster_id uuid,
+    cluster_name character varying(63) NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    compliance_date DATE DEFAULT CURRENT_DATE NOT NULL,
+    compliance status.compliance_type NOT NULL,
+    CONSTRAINT compliance_unique_constraint UNIQUE (policy_id, leaf_hub_name, cluster_name, compliance_date)
+);
+
 CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     name varchar(63) NOT NULL,
     start_at timestamp NOT NULL DEFAULT now(),
@@ -98,6 +119,37 @@ CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     error TEXT
 );
 
+CREATE TABLE IF NOT EXISTS history.leaf_hub_lifecycle_actions (
+    leaf_hub_name character varying(63) NOT NULL,
+    action character varying(63) NOT NULL,
+    reason text,
+    retention character varying(63),
+    affected_rows jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS history.leaf_hub_archives (
+    leaf_hub_name character varying(63) NOT NULL,
+    table_name character varying(127) NOT NULL,
+    payload jsonb NOT NULL,
+    archived_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS history.audit_log (
+    id bigserial PRIMARY KEY,
+    user_name character varying(253) NOT NULL,
+    source character varying(63) NOT NULL,
+    operation character varying(63) NOT NULL,
+    kind character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63),
+    resource_id character varying(63),
+    target_hubs jsonb DEFAULT '[]'::jsonb NOT NULL,
+    spec_version character varying(63),
+    details jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS local_spec.placementrules (
     leaf_hub_name character varying(63) NOT NULL,
     payload jsonb NOT NULL,
@@ -127,6 +179,15 @@ CREATE TABLE IF NOT EXISTS local_status.compliance (
     cluster_id uuid
 );
 
+CREATE TABLE IF NOT EXISTS report.compliance_reports (
+    name character varying(63) NOT NULL PRIMARY KEY,
+    definition jsonb NOT NULL,
+    created_by character varying(253),
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_exported_at timestamp without time zone
+);
+
 CREATE TABLE IF NOT EXISTS spec.applications (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -151,6 +212,25 @@ CREATE TABLE IF NOT EXISTS spec.configs (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS spec.leaf_hub_agent_configs (
+    leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+    payload jsonb NOT NULL,
+    source text NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS spec.leaf_hub_placement_decisions (
+    leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+    payload jsonb NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS spec.leaf_hub_rollouts (
+    leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+    payload jsonb NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS spec.managed_cluster_sets_tracking (
     cluster_set_name character varying(63) NOT NULL,
     leaf_hub_name character varying(63) NOT NULL,
@@ -164,6 +244,10 @@ CREATE TABLE IF NOT EXISTS spec.managed_clusters_labels (
     managed_cluster_name character varying(63) NOT NULL,
     labels jsonb DEFAULT '{}'::jsonb NOT NULL,
     deleted_label_keys jsonb DEFAULT '[]'::jsonb NOT NULL,
+    annotations jsonb DEFAULT '{}'::jsonb NOT NULL,
+    deleted_annotation_keys jsonb DEFAULT '[]'::jsonb NOT NULL,
+    taints jsonb DEFAULT '[]'::jsonb NOT NULL,
+    deleted_taint_keys jsonb DEFAULT '[]'::jsonb NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     version bigint DEFAULT 0 NOT NULL,
     CONSTRAINT managed_clusters_labels_version_check CHECK ((version >= 0))
@@ -217,6 +301,34 @@ CREATE TABLE IF NOT EXISTS spec.policies (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS spec.resources (
+    id uuid PRIMARY KEY,
+    api_group text NOT NULL,
+    api_version text NOT NULL,
+    kind text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS spec.rollouts (
+    resource_id uuid NOT NULL PRIMARY KEY,
+    table_name character varying(63) NOT NULL,
+    rollout_policy character varying(253) NOT NULL,
+    resource_updated_at timestamp without time zone NOT NULL,
+    wave integer NOT NULL,
+    phase character varying(63) NOT NULL,
+    message text,
+    baseline jsonb DEFAULT '{}'::jsonb NOT NULL,
+    wave_started_at timestamp without time zone NOT NULL,
+    wave_applied_at timestamp without time zone,
+    last_transition_at timestamp without time zone NOT NULL,
+    payload jsonb,
+    stable_payload jsonb,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS spec.subscriptions (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -246,6 +358,22 @@ CREATE TABLE IF NOT EXISTS status.leaf_hub_heartbeats (
     last_timestamp timestamp without time zone DEFAULT now() NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS status.leaf_hub_capabilities (
+    leaf_hub_name character varying(63) NOT NULL,
+    schema_version integer NOT NULL,
+    payload jsonb NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.leaf_hub_lifecycles (
+    leaf_hub_name character varying(63) NOT NULL PRIMARY KEY,
+    state character varying(63) NOT NULL,
+    reason text,
+    retention character varying(63),
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS status.managed_clusters (
     leaf_hub_name character varying(63) NOT NULL,
     cluster_name character varying(63) generated always as (payload -> 'metadata' ->> 'name') stored,
@@ -296,6 +424,97 @@ CREATE TABLE IF NOT EXISTS status.subscription_statuses (
     payload jsonb NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS status.applied_statuses (
+    resource_id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    kind character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63),
+    bundle_version character varying(63) NOT NULL,
+    applied boolean NOT NULL,
+    error text,
+    dry_run boolean DEFAULT false NOT NULL,
+    drifted boolean DEFAULT false NOT NULL,
+    diff text,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+    cluster_id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    cluster_name character varying(63) NOT NULL,
+    addon_name character varying(253) NOT NULL,
+    available character varying(15) NOT NULL,
+    degraded character varying(15) NOT NULL,
+    progressing character varying(15) NOT NULL,
+    message text,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.argocd_applications (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63) NOT NULL,
+    project character varying(253),
+    repo_url text,
+    target_revision character varying(253),
+    revision character varying(253),
+    destination_server text,
+    destination_name character varying(253),
+    destination_namespace character varying(63),
+    sync_status character varying(63),
+    health_status character varying(63),
+    health_message text,
+    operation_phase character varying(63),
+    reconciled_at character varying(63),
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.argocd_applicationsets (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63) NOT NULL,
+    applications jsonb NOT NULL,
+    resources_up_to_date boolean DEFAULT false NOT NULL,
+    error_message text,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.policy_reports (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    engine character varying(63) NOT NULL,
+    kind character varying(253) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63),
+    cluster_name character varying(63),
+    pass bigint DEFAULT 0 NOT NULL,
+    fail bigint DEFAULT 0 NOT NULL,
+    warn bigint DEFAULT 0 NOT NULL,
+    error bigint DEFAULT 0 NOT NULL,
+    skip bigint DEFAULT 0 NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.policy_violations (
+    report_id uuid NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    engine character varying(63) NOT NULL,
+    cluster_name character varying(63),
+    policy_name character varying(253) NOT NULL,
+    rule_name character varying(253),
+    severity character varying(63),
+    category character varying(253),
+    result character varying(15) NOT NULL,
+    message text,
+    resource_kind character varying(253),
+    resource_name character varying(253),
+    resource_namespace character varying(63),
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS event.local_policies (
     event_name character varying(63) NOT NULL,
     policy_id uuid NOT NULL,
@@ -323,10 +542,74 @@ CREATE TABLE IF NOT EXISTS event.local_root_policies (
     CONSTRAINT local_root_policies_unique_constraint UNIQUE (event_name, count)
 );
 
+CREATE TABLE IF NOT EXISTS event.managed_clusters (
+    event_name character varying(253) NOT NULL,
+    cluster_id uuid,
+    cluster_name character varying(256) NOT NULL,
+    leaf_hub_name character varying(63) NOT NULL,
+    message text,
+    reason text,
+    count integer NOT NULL DEFAULT 0,
+    source jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT managed_clusters_unique_constraint UNIQUE (leaf_hub_name, event_name, count)
+);
+
+CREATE TABLE IF NOT EXISTS event.placements (
+    event_name character varying(253) NOT NULL,
+    resource_id uuid,
+    kind character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63),
+    leaf_hub_name character varying(63) NOT NULL,
+    message text,
+    reason text,
+    count integer NOT NULL DEFAULT 0,
+    source jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT placements_unique_constraint UNIQUE (leaf_hub_name, event_name, count)
+);
+
+CREATE TABLE IF NOT EXISTS event.applications (
+    event_name character varying(253) NOT NULL,
+    resource_id uuid,
+    kind character varying(63) NOT NULL,
+    name character varying(253) NOT NULL,
+    namespace character varying(63),
+    leaf_hub_name character varying(63) NOT NULL,
+    message text,
+    reason text,
+    count integer NOT NULL DEFAULT 0,
+    source jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT applications_unique_constraint UNIQUE (leaf_hub_name, event_name, count)
+);
+
+-- the columns of the annotations and taints are added to the table created by the earlier releases
+ALTER TABLE spec.managed_clusters_labels ADD COLUMN IF NOT EXISTS annotations jsonb DEFAULT '{}'::jsonb NOT NULL;
+ALTER TABLE spec.managed_clusters_labels ADD COLUMN IF NOT EXISTS deleted_annotation_keys jsonb DEFAULT '[]'::jsonb NOT NULL;
+ALTER TABLE spec.managed_clusters_labels ADD COLUMN IF NOT EXISTS taints jsonb DEFAULT '[]'::jsonb NOT NULL;
+ALTER TABLE spec.managed_clusters_labels ADD COLUMN IF NOT EXISTS deleted_taint_keys jsonb DEFAULT '[]'::jsonb NOT NULL;
+
+-- the events of all the event tables are ordered by the sequence in which they're received
+CREATE SEQUENCE IF NOT EXISTS event.events_seq;
+
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS seq bigint DEFAULT nextval('event.events_seq') NOT NULL;
+
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS seq bigint DEFAULT nextval('event.events_seq') NOT NULL;
+
+ALTER TABLE event.managed_clusters ADD COLUMN IF NOT EXISTS seq bigint DEFAULT nextval('event.events_seq') NOT NULL;
+
+ALTER TABLE event.placements ADD COLUMN IF NOT EXISTS seq bigint DEFAULT nextval('event.events_seq') NOT NULL;
+
+ALTER TABLE event.applications ADD COLUMN IF NOT EXISTS seq bigint DEFAULT nextval('event.events_seq') NOT NULL;
+
 CREATE UNIQUE INDEX IF NOT EXISTS placementrules_leaf_hub_name_id_idx ON local_spec.placementrules (leaf_hub_name, (((payload -> 'metadata'::text) ->> 'uid'::text)));
 
 CREATE UNIQUE INDEX IF NOT EXISTS policies_leaf_hub_name_id_idx ON local_spec.policies (leaf_hub_name, (((payload -> 'metadata'::text) ->> 'uid'::text)));
 
+CREATE INDEX IF NOT EXISTS resources_api_group_api_version_kind_idx ON spec.resources (api_group, api_version, kind);
+
 CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_sets_tracking_cluster_set_name_and_leaf_hub_name_idx ON spec.managed_cluster_sets_tracking (cluster_set_name, leaf_hub_name);
 
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_cluster_idx ON status.compliance (leaf_hub_name, cluster_name);
@@ -337,6 +620,24 @@ CREATE UNIQUE INDEX IF NOT EXISTS compliance_leaf_hub_policy_cluster_idx ON stat
 
 CREATE UNIQUE INDEX IF NOT EXISTS leaf_hub_heartbeats_leaf_hub_idx ON status.leaf_hub_heartbeats (leaf_hub_name);
 
+CREATE UNIQUE INDEX IF NOT EXISTS leaf_hub_capabilities_leaf_hub_idx ON status.leaf_hub_capabilities (leaf_hub_name);
+
+CREATE INDEX IF NOT EXISTS leaf_hub_lifecycle_actions_leaf_hub_name_idx ON history.leaf_hub_lifecycle_actions (leaf_hub_name);
+
+CREATE INDEX IF NOT EXISTS leaf_hub_archives_leaf_hub_name_table_name_idx ON history.leaf_hub_archives (leaf_hub_name, table_name);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON history.audit_log (created_at);
+
+CREATE INDEX IF NOT EXISTS local_policies_seq_idx ON event.local_policies (seq);
+
+CREATE INDEX IF NOT EXISTS local_root_policies_seq_idx ON event.local_root_policies (seq);
+
+CREATE INDEX IF NOT EXISTS managed_clusters_seq_idx ON event.managed_clusters (seq);
+
+CREATE INDEX IF NOT EXISTS placements_seq_idx ON event.placements (seq);
+
+CREATE INDEX IF NOT EXISTS applications_seq_idx ON event.applications (seq);
+
 CREATE UNIQUE INDEX IF NOT EXISTS managed_clusters_leaf_hub_name_metadata_uid_idx ON status.managed_clusters (leaf_hub_name, cluster_id);
 
 CREATE INDEX IF NOT EXISTS managed_clusters_metadata_name_idx ON status.managed_clusters ((((payload -> 'metadata'::text) ->> 'name'::text)));
@@ -360,3 +661,44 @@ CREATE INDEX IF NOT EXISTS subscription_reports_payload_name_and_namespace_idx O
 CREATE UNIQUE INDEX IF NOT EXISTS subscription_statuses_leaf_hub_name_and_payload_id_namespace_idx ON status.subscription_statuses (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE INDEX IF NOT EXISTS subscription_statuses_payload_name_and_namespace_idx ON status.subscription_statuses ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
+
+CREATE UNIQUE INDEX IF NOT EXISTS applied_statuses_leaf_hub_name_resource_id_idx ON status.applied_statuses (leaf_hub_name, resource_id);
+
+CREATE INDEX IF NOT EXISTS applied_statuses_resource_id_idx ON status.applied_statuses (resource_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_addons_cluster_id_addon_name_idx ON status.managed_cluster_addons (cluster_id, addon_name);
+
+CREATE INDEX IF NOT EXISTS managed_cluster_addons_leaf_hub_name_idx ON status.managed_cluster_addons (leaf_hub_name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS argocd_applications_leaf_hub_name_id_idx ON status.argocd_applications (leaf_hub_name, id);
+
+CREATE INDEX IF NOT EXISTS argocd_applications_leaf_hub_name_namespace_name_idx ON status.argocd_applications (leaf_hub_name, namespace, name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS argocd_applicationsets_leaf_hub_name_id_idx ON status.argocd_applicationsets (leaf_hub_name, id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS policy_reports_leaf_hub_name_id_idx ON status.policy_reports (leaf_hub_name, id);
+
+CREATE INDEX IF NOT EXISTS policy_violations_leaf_hub_name_report_id_idx ON status.policy_violations (leaf_hub_name, report_id);
+
+-- the violations of the acm policies and the native policy engines, the non compliant clusters of an acm policy are
+-- the violations of the policy. it's the source of the policy violations api and dashboard.
+CREATE OR REPLACE VIEW status.unified_policy_violations AS
+    SELECT 'acm' AS engine, c.leaf_hub_name, c.cluster_name, p.payload -> 'metadata' ->> 'name' AS policy_name,
+        NULL AS rule_name, NULL AS severity,
+        p.payload -> 'metadata' -> 'annotations' ->> 'policy.open-cluster-management.io/categories' AS category,
+        'fail' AS result, NULL AS message, 'ManagedCluster' AS resource_kind, c.cluster_name AS resource_name,
+        NULL AS resource_namespace
+    FROM status.compliance c
+    INNER JOIN spec.policies p ON c.policy_id = p.id
+    WHERE c.compliance = 'non_compliant' AND p.deleted = false
+    UNION ALL
+    SELECT 'acm' AS engine, lc.leaf_hub_name, lc.cluster_name, lp.policy_name, NULL AS rule_name, NULL AS severity,
+        lp.policy_category AS category, 'fail' AS result, NULL AS message, 'ManagedCluster' AS resource_kind,
+        lc.cluster_name AS resource_name, NULL AS resource_namespace
+    FROM local_status.compliance lc
+    INNER JOIN local_spec.policies lp ON lc.policy_id = lp.policy_id
+    WHERE lc.compliance = 'non_compliant' AND lp.deleted_at IS NULL
+    UNION ALL
+    SELECT v.engine, v.leaf_hub_name, v.cluster_name, v.policy_name, v.rule_name, v.severity, v.category, v.result,
+        v.message, v.resource_kind, v.resource_name, v.resource_namespace
+    FROM status.policy_violations v;
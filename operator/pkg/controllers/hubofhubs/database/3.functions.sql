@@ -251,6 +251,36 @@ BEGIN
 END;
 $$;
 
+CREATE OR REPLACE FUNCTION public.move_resources_to_history() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO history.resources SELECT * FROM spec.resources
+  WHERE api_group = NEW.api_group AND api_version = NEW.api_version AND kind = NEW.kind AND
+  payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  DELETE FROM spec.resources
+  WHERE api_group = NEW.api_group AND api_version = NEW.api_version AND kind = NEW.kind AND
+  payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  RETURN NEW;
+END;
+$$;
+
 CREATE OR REPLACE FUNCTION public.move_subscriptions_to_history() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -334,4 +364,13 @@ BEGIN
         AND cluster_name = (NEW.payload -> 'metadata' ->> 'name');  
     RETURN NEW;
 END;
-$$;
\ No newline at end of file
+$$;
+
+CREATE OR REPLACE FUNCTION public.prevent_audit_log_change()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'history.audit_log is append-only';
+END;
+$$;
@@ -19,6 +19,8 @@ DROP TRIGGER IF EXISTS set_timestamp ON history.placements;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.placements FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.policies;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.policies FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON history.resources;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.resources FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.subscriptions;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.subscriptions FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON local_spec.placementrules;
@@ -42,6 +44,8 @@ DROP TRIGGER IF EXISTS move_to_history ON spec.placements;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.placements FOR EACH ROW EXECUTE FUNCTION public.move_placements_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.policies;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.policies FOR EACH ROW EXECUTE FUNCTION public.move_policies_to_history();
+DROP TRIGGER IF EXISTS move_to_history ON spec.resources;
+CREATE TRIGGER move_to_history BEFORE INSERT ON spec.resources FOR EACH ROW EXECUTE FUNCTION public.move_resources_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.subscriptions;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.subscriptions FOR EACH ROW EXECUTE FUNCTION public.move_subscriptions_to_history();
 
@@ -63,9 +67,34 @@ DROP TRIGGER IF EXISTS set_timestamp ON spec.placements;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.placements FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.policies;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.policies FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON spec.resources;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.resources FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON spec.rollouts;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.rollouts FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.subscriptions;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.subscriptions FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 
+DROP TRIGGER IF EXISTS set_timestamp ON status.applied_statuses;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.applied_statuses FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.managed_cluster_addons;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.managed_cluster_addons FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.leaf_hub_capabilities;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.leaf_hub_capabilities FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.leaf_hub_lifecycles;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.leaf_hub_lifecycles FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.policy_reports;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.policy_reports FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.argocd_applications;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.argocd_applications FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
+DROP TRIGGER IF EXISTS set_timestamp ON status.argocd_applicationsets;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON status.argocd_applicationsets FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+
 -- set the cluster_id to compliance table
 DROP TRIGGER IF EXISTS update_compliance_table ON local_status.compliance;
 CREATE TRIGGER update_compliance_table AFTER INSERT OR UPDATE ON local_status.compliance FOR EACH ROW WHEN (pg_trigger_depth() < 1) EXECUTE FUNCTION public.set_cluster_id_to_local_compliance();
@@ -85,3 +114,9 @@ CREATE TRIGGER update_compliance_cluster_id_trigger
 AFTER INSERT ON status.managed_clusters
 FOR EACH ROW
 EXECUTE FUNCTION public.update_compliance_cluster_id();
+
+DROP TRIGGER IF EXISTS prevent_audit_log_change ON history.audit_log;
+CREATE TRIGGER prevent_audit_log_change
+BEFORE UPDATE OR DELETE ON history.audit_log
+FOR EACH ROW
+EXECUTE FUNCTION public.prevent_audit_log_change();
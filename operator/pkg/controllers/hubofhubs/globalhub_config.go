@@ -12,7 +12,6 @@ import (
 
 	operatorv1alpha3 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
-	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
@@ -49,7 +48,9 @@ func (r *MulticlusterGlobalHubReconciler) reconcileSystemConfig(ctx context.Cont
 		}
 	}
 
-	// hoh configmap
+	// hoh configmap, it's rendered from the typed agent config for the agents which don't watch the
+	// GlobalHubAgentConfig yet
+	agentConfig := config.GetAgentConfig(mgh)
 	expectedHoHConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: constants.GHSystemNamespace,
@@ -59,8 +60,12 @@ func (r *MulticlusterGlobalHubReconciler) reconcileSystemConfig(ctx context.Cont
 			},
 		},
 		Data: map[string]string{
-			"aggregationLevel":    string(operatorconstants.FullAggregation),
-			"enableLocalPolicies": strconv.FormatBool(true),
+			"aggregationLevel":    string(agentConfig.AggregationLevel),
+			"enableLocalPolicies": strconv.FormatBool(agentConfig.LocalPoliciesEnabled()),
+			"managedClusters":     agentConfig.SyncIntervals.GetManagedClustersInterval().String(),
+			"policies":            agentConfig.SyncIntervals.GetPoliciesInterval().String(),
+			"controlInfo":         agentConfig.SyncIntervals.GetControlInfoInterval().String(),
+			"appliedStatus":       agentConfig.SyncIntervals.GetAppliedStatusInterval().String(),
 		},
 	}
 
@@ -50,6 +50,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 )
@@ -73,6 +74,7 @@ type MulticlusterGlobalHubReconciler struct {
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=globalresourcetypes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets/join,verbs=create;delete
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets/bind,verbs=create;delete
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=subscriptions,verbs=get;list;update;patch
@@ -93,7 +95,7 @@ type MulticlusterGlobalHubReconciler struct {
 // +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterroles,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterroles,verbs=get;list;watch;create;update;delete;escalate
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterrolebindings,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=mutatingwebhookconfigurations,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=clustermanagementaddons,verbs=create;delete;get;list;update;watch
@@ -295,14 +297,19 @@ func (r *MulticlusterGlobalHubReconciler) SetupWithManager(mgr ctrl.Manager) err
 				constants.GHOperatorOwnerLabelVal {
 				new := e.ObjectNew.(*admissionregistrationv1.MutatingWebhookConfiguration)
 				old := e.ObjectOld.(*admissionregistrationv1.MutatingWebhookConfiguration)
-				if len(new.Webhooks) != len(old.Webhooks) ||
-					new.Webhooks[0].Name != old.Webhooks[0].Name ||
-					!reflect.DeepEqual(new.Webhooks[0].AdmissionReviewVersions,
-						old.Webhooks[0].AdmissionReviewVersions) ||
-					!reflect.DeepEqual(new.Webhooks[0].Rules, old.Webhooks[0].Rules) ||
-					!reflect.DeepEqual(new.Webhooks[0].ClientConfig.Service, old.Webhooks[0].ClientConfig.Service) {
+				if len(new.Webhooks) != len(old.Webhooks) {
 					return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
 				}
+				for i := range new.Webhooks {
+					if new.Webhooks[i].Name != old.Webhooks[i].Name ||
+						!reflect.DeepEqual(new.Webhooks[i].AdmissionReviewVersions,
+							old.Webhooks[i].AdmissionReviewVersions) ||
+						!reflect.DeepEqual(new.Webhooks[i].Rules, old.Webhooks[i].Rules) ||
+						!reflect.DeepEqual(new.Webhooks[i].ObjectSelector, old.Webhooks[i].ObjectSelector) ||
+						!reflect.DeepEqual(new.Webhooks[i].ClientConfig.Service, old.Webhooks[i].ClientConfig.Service) {
+						return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+					}
+				}
 				return false
 			}
 			return false
@@ -369,5 +376,15 @@ func (r *MulticlusterGlobalHubReconciler) SetupWithManager(mgr ctrl.Manager) err
 					{NamespacedName: config.GetHoHMGHNamespacedName()},
 				}
 			}), builder.WithPredicates(resPred)).
+		// secondary watch for globalresourcetype, the manager and the agents are granted the access to its kind. the
+		// status changes are watched too, so the kinds whose CRDs are installed later are granted once the manager
+		// registers them
+		Watches(&source.Kind{Type: &globalhubv1alpha3.GlobalResourceType{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					// trigger MGH instance reconcile
+					{NamespacedName: config.GetHoHMGHNamespacedName()},
+				}
+			})).
 		Complete(r)
 }
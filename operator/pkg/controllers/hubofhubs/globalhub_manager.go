@@ -65,6 +65,19 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 		imagePullPolicy = mgh.Spec.ImagePullPolicy
 	}
 
+	// the manager is granted the access to the kinds registered by the GlobalResourceTypes
+	globalResourceRules, err := config.GetGlobalResourceRules(ctx, r.Client, mapper)
+	if err != nil {
+		return fmt.Errorf("failed to get the rules of the global resource types: %v", err)
+	}
+
+	// the scheduled compliance reports are written to the object storage of the optional report storage secret
+	reportStorage, err := utils.GetReportStorageConfig(ctx, r.KubeClient, mgh.Namespace,
+		operatorconstants.GHReportStorageSecretName)
+	if err != nil {
+		return fmt.Errorf("failed to get the report storage secret: %v", err)
+	}
+
 	managerObjects, err := hohRenderer.Render("manifests/manager", "", func(profile string) (interface{}, error) {
 		return struct {
 			Image                  string
@@ -87,6 +100,9 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 			SchedulerInterval      string
 			NodeSelector           map[string]string
 			Tolerations            []corev1.Toleration
+			GlobalResourceRules    []config.GlobalResourceRule
+			ReportStorage          *utils.ReportStorageConfig
+			ReportStorageSecret    string
 		}{
 			Image:                  config.GetImage(config.GlobalHubManagerImageKey),
 			ProxyImage:             config.GetImage(config.OauthProxyImageKey),
@@ -108,6 +124,9 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 			SchedulerInterval:      config.GetSchedulerInterval(mgh),
 			NodeSelector:           mgh.Spec.NodeSelector,
 			Tolerations:            mgh.Spec.Tolerations,
+			GlobalResourceRules:    globalResourceRules,
+			ReportStorage:          reportStorage,
+			ReportStorageSecret:    operatorconstants.GHReportStorageSecretName,
 		}, nil
 	})
 	if err != nil {
@@ -377,6 +377,7 @@ var _ = Describe("MulticlusterGlobalHub controller", Ordered, func() {
 					SchedulerInterval      string
 					NodeSelector           map[string]string
 					Tolerations            []corev1.Toleration
+					GlobalResourceRules    []config.GlobalResourceRule
 				}{
 					Image:                  config.GetImage(config.GlobalHubManagerImageKey),
 					ProxyImage:             config.GetImage(config.OauthProxyImageKey),
@@ -22,6 +22,7 @@ import (
 	"encoding/base64"
 	"path/filepath"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
@@ -99,6 +100,37 @@ func GetKafkaConfig(ctx context.Context, kubeClient kubernetes.Interface,
 		nil
 }
 
+// ReportStorageConfig is the object storage the manager writes the scheduled compliance reports to
+type ReportStorageConfig struct {
+	Endpoint string
+	Region   string
+	Bucket   string
+	Prefix   string
+}
+
+// GetReportStorageConfig returns the object storage of the scheduled compliance reports in the secret, it returns nil
+// if the secret isn't found or the bucket isn't set, then the reports aren't scheduled.
+func GetReportStorageConfig(ctx context.Context, kubeClient kubernetes.Interface,
+	namespace string, name string,
+) (*ReportStorageConfig, error) {
+	storageSecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(storageSecret.Data["bucket"]) == 0 {
+		return nil, nil
+	}
+	return &ReportStorageConfig{
+		Endpoint: string(storageSecret.Data["endpoint"]),
+		Region:   string(storageSecret.Data["region"]),
+		Bucket:   string(storageSecret.Data["bucket"]),
+		Prefix:   string(storageSecret.Data["prefix"]),
+	}, nil
+}
+
 func UpdateObject(ctx context.Context, runtimeClient client.Client, obj client.Object) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		return runtimeClient.Update(ctx, obj, &client.UpdateOptions{})
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetReportStorageConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []*corev1.Secret
+		want    *ReportStorageConfig
+	}{
+		{
+			name: "without the secret",
+			want: nil,
+		},
+		{
+			name: "without the bucket",
+			secrets: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "report-storage", Namespace: "default"},
+				Data:       map[string][]byte{"region": []byte("eu-west-1")},
+			}},
+			want: nil,
+		},
+		{
+			name: "with the bucket",
+			secrets: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "report-storage", Namespace: "default"},
+				Data: map[string][]byte{
+					"endpoint":              []byte("https://minio.example.com"),
+					"region":                []byte("eu-west-1"),
+					"bucket":                []byte("reports"),
+					"aws_secret_access_key": []byte("secret"),
+				},
+			}},
+			want: &ReportStorageConfig{
+				Endpoint: "https://minio.example.com",
+				Region:   "eu-west-1",
+				Bucket:   "reports",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			for _, secret := range tt.secrets {
+				if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret,
+					metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := GetReportStorageConfig(context.TODO(), kubeClient, "default", "report-storage")
+			if err != nil {
+				t.Fatalf("GetReportStorageConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetReportStorageConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AggregationLevelType specifies the level of aggregation the agent does before sending the policy status.
+// +kubebuilder:validation:Enum:="full";"minimal"
+type AggregationLevelType string
+
+const (
+	// FullAggregation sends the compliance status of the policies per managed cluster
+	FullAggregation AggregationLevelType = "full"
+	// MinimalAggregation only sends the compliance summary of the policies
+	MinimalAggregation AggregationLevelType = "minimal"
+)
+
+const (
+	// AgentConfigApplied means the agent has picked up the latest generation of the agent config
+	AgentConfigApplied = "Applied"
+)
+
+const (
+	DefaultStatusSyncInterval      = 5 * time.Second
+	DefaultControlInfoSyncInterval = 60 * time.Minute
+)
+
+// DefaultAgentConfigSource is the source of the effective agent config of the leaf hubs without a matching override
+const DefaultAgentConfigSource = "default"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName={ghac}
+// +kubebuilder:printcolumn:name="Aggregation",type="string",JSONPath=".spec.aggregationLevel"
+// +kubebuilder:printcolumn:name="LocalPolicies",type="boolean",JSONPath=".spec.enableLocalPolicies"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// GlobalHubAgentConfig is the Schema for the configuration of the agent running on a managed hub
+type GlobalHubAgentConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AgentConfigSpec            `json:"spec,omitempty"`
+	Status GlobalHubAgentConfigStatus `json:"status,omitempty"`
+}
+
+// AgentConfigSpec defines the desired configuration of the multicluster global hub agent
+type AgentConfigSpec struct {
+	// AggregationLevel is the level of aggregation the agent does before sending the policy status.
+	// full: send the compliance status per managed cluster, minimal: only send the compliance summary.
+	// +kubebuilder:default:="full"
+	// +optional
+	AggregationLevel AggregationLevelType `json:"aggregationLevel,omitempty"`
+	// EnableLocalPolicies is to sync the policies created on the managed hub to the global hub, the agent keeps its
+	// default, which is to sync them, if it isn't set
+	// +kubebuilder:default:=true
+	// +optional
+	EnableLocalPolicies *bool `json:"enableLocalPolicies,omitempty"`
+	// SyncIntervals are the intervals the agent sends the status bundles to the global hub
+	// +kubebuilder:default:={}
+	// +optional
+	SyncIntervals *SyncIntervalsConfig `json:"syncIntervals,omitempty"`
+}
+
+// SyncIntervalsConfig defines the intervals of the periodic status syncers on the agent.
+// the value is a duration string, like "5s", "1m" or "1h30m".
+type SyncIntervalsConfig struct {
+	// ManagedClusters is the interval of syncing the managed clusters status
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	ManagedClusters *metav1.Duration `json:"managedClusters,omitempty"`
+	// Policies is the interval of syncing the policies status
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	Policies *metav1.Duration `json:"policies,omitempty"`
+	// ControlInfo is the interval of sending the heartbeat of the agent
+	// +kubebuilder:default:="60m"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	ControlInfo *metav1.Duration `json:"controlInfo,omitempty"`
+	// AppliedStatus is the interval of syncing the applied status of the global resources
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	AppliedStatus *metav1.Duration `json:"appliedStatus,omitempty"`
+}
+
+// AgentConfigs are the agent config and the overrides of it, they are inlined into the spec of the
+// MulticlusterGlobalHub
+type AgentConfigs struct {
+	// AgentConfig is the configuration of the agents running on the managed hubs
+	// +kubebuilder:default:={}
+	// +optional
+	AgentConfig *AgentConfigSpec `json:"agentConfig,omitempty"`
+	// AgentConfigOverrides override the agent config for the managed hubs matching the leaf hub names or the
+	// selector. if several overrides match a hub, the override listing the hub by name wins, then the override whose
+	// selector has the most requirements, then the override whose name is first in the alphabetical order. the fields
+	// the override doesn't set are inherited from the agentConfig.
+	// +optional
+	AgentConfigOverrides []AgentConfigOverride `json:"agentConfigOverrides,omitempty"`
+}
+
+// AgentConfigOverride overrides the agent config for a set of managed hubs
+type AgentConfigOverride struct {
+	// Name identifies the override, it's reported as the source of the effective config of the matching hubs
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// LeafHubNames are the names of the managed hubs the override applies to
+	// +optional
+	LeafHubNames []string `json:"leafHubNames,omitempty"`
+	// LeafHubSelector selects the managed hubs the override applies to by the labels of their managed clusters
+	// +optional
+	LeafHubSelector *metav1.LabelSelector `json:"leafHubSelector,omitempty"`
+	// AggregationLevel overrides the aggregation level of the agent config
+	// +optional
+	AggregationLevel AggregationLevelType `json:"aggregationLevel,omitempty"`
+	// EnableLocalPolicies overrides the enableLocalPolicies of the agent config
+	// +optional
+	EnableLocalPolicies *bool `json:"enableLocalPolicies,omitempty"`
+	// SyncIntervals overrides the sync intervals of the agent config, the intervals which aren't set are inherited
+	// +optional
+	SyncIntervals *SyncIntervalsOverride `json:"syncIntervals,omitempty"`
+}
+
+// SyncIntervalsOverride overrides the intervals of the periodic status syncers on the agent.
+// unlike SyncIntervalsConfig, it has no defaults so that the intervals which aren't set are inherited.
+type SyncIntervalsOverride struct {
+	// ManagedClusters is the interval of syncing the managed clusters status
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	ManagedClusters *metav1.Duration `json:"managedClusters,omitempty"`
+	// Policies is the interval of syncing the policies status
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	Policies *metav1.Duration `json:"policies,omitempty"`
+	// ControlInfo is the interval of sending the heartbeat of the agent
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	ControlInfo *metav1.Duration `json:"controlInfo,omitempty"`
+	// AppliedStatus is the interval of syncing the applied status of the global resources
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	AppliedStatus *metav1.Duration `json:"appliedStatus,omitempty"`
+}
+
+// GlobalHubAgentConfigStatus defines the observed state of GlobalHubAgentConfig
+type GlobalHubAgentConfigStatus struct {
+	// ObservedGeneration is the latest generation of the config picked up by the agent
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions contains the different condition statuses for the agent config
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalHubAgentConfigList contains a list of GlobalHubAgentConfig
+type GlobalHubAgentConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalHubAgentConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalHubAgentConfig{}, &GlobalHubAgentConfigList{})
+}
+
+// GetManagedClustersInterval returns the managed clusters sync interval, or the default if it isn't set
+func (in *SyncIntervalsConfig) GetManagedClustersInterval() time.Duration {
+	if in == nil {
+		return DefaultStatusSyncInterval
+	}
+	return durationOrDefault(in.ManagedClusters, DefaultStatusSyncInterval)
+}
+
+// GetPoliciesInterval returns the policies sync interval, or the default if it isn't set
+func (in *SyncIntervalsConfig) GetPoliciesInterval() time.Duration {
+	if in == nil {
+		return DefaultStatusSyncInterval
+	}
+	return durationOrDefault(in.Policies, DefaultStatusSyncInterval)
+}
+
+// GetControlInfoInterval returns the control info sync interval, or the default if it isn't set
+func (in *SyncIntervalsConfig) GetControlInfoInterval() time.Duration {
+	if in == nil {
+		return DefaultControlInfoSyncInterval
+	}
+	return durationOrDefault(in.ControlInfo, DefaultControlInfoSyncInterval)
+}
+
+// GetAppliedStatusInterval returns the applied status sync interval, or the default if it isn't set
+func (in *SyncIntervalsConfig) GetAppliedStatusInterval() time.Duration {
+	if in == nil {
+		return DefaultStatusSyncInterval
+	}
+	return durationOrDefault(in.AppliedStatus, DefaultStatusSyncInterval)
+}
+
+// GetAgentConfig returns the agent config with the defaults applied, the api server defaults the fields on
+// admission, this covers the instances created before the fields existed
+func (in *AgentConfigs) GetAgentConfig() *AgentConfigSpec {
+	if in.AgentConfig == nil {
+		return &AgentConfigSpec{
+			AggregationLevel: FullAggregation,
+			SyncIntervals:    &SyncIntervalsConfig{},
+		}
+	}
+
+	agentConfig := in.AgentConfig.DeepCopy()
+	if agentConfig.AggregationLevel == "" {
+		agentConfig.AggregationLevel = FullAggregation
+	}
+	if agentConfig.SyncIntervals == nil {
+		agentConfig.SyncIntervals = &SyncIntervalsConfig{}
+	}
+	return agentConfig
+}
+
+// LocalPoliciesEnabled returns whether the policies created on the managed hub are synced, they are synced if
+// EnableLocalPolicies isn't set
+func (in *AgentConfigSpec) LocalPoliciesEnabled() bool {
+	return in.EnableLocalPolicies == nil || *in.EnableLocalPolicies
+}
+
+// GetLeafHubAgentConfig returns the effective agent config of a leaf hub and the source of it, which is the name of
+// the most specific override matching the leaf hub name or labels, or DefaultAgentConfigSource if no override
+// matches. an override listing the leaf hub by name is the most specific, then the override whose selector has the
+// most requirements, the override whose name is first in the alphabetical order wins the ties, so the result
+// doesn't depend on the order of the overrides.
+func (in *AgentConfigs) GetLeafHubAgentConfig(leafHubName string,
+	leafHubLabels map[string]string,
+) (*AgentConfigSpec, string, error) {
+	var matchedOverride *AgentConfigOverride
+	matchedSpecificity := 0
+	for i := range in.AgentConfigOverrides {
+		override := &in.AgentConfigOverrides[i]
+		specificity, err := override.matches(leafHubName, leafHubLabels)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid agent config override %s: %w", override.Name, err)
+		}
+		if specificity == 0 {
+			continue
+		}
+		if matchedOverride == nil || specificity > matchedSpecificity ||
+			(specificity == matchedSpecificity && override.Name < matchedOverride.Name) {
+			matchedOverride, matchedSpecificity = override, specificity
+		}
+	}
+
+	agentConfig := in.GetAgentConfig()
+	if matchedOverride == nil {
+		return agentConfig, DefaultAgentConfigSource, nil
+	}
+	matchedOverride.applyTo(agentConfig)
+	return agentConfig, matchedOverride.Name, nil
+}
+
+// nameMatchSpecificity is the specificity of the overrides listing the leaf hub by name, it's above the number of
+// the requirements of any selector
+const nameMatchSpecificity = math.MaxInt32
+
+// matches returns the specificity of the override for the leaf hub, which is nameMatchSpecificity if the override
+// lists the leaf hub by name, the number of the requirements if its selector matches the labels, or 0 if the
+// override doesn't match the leaf hub
+func (override *AgentConfigOverride) matches(leafHubName string, leafHubLabels map[string]string) (int, error) {
+	for _, name := range override.LeafHubNames {
+		if name == leafHubName {
+			return nameMatchSpecificity, nil
+		}
+	}
+	// an empty selector would match all the hubs, so only the selectors with requirements are considered
+	if override.LeafHubSelector == nil || (len(override.LeafHubSelector.MatchLabels) == 0 &&
+		len(override.LeafHubSelector.MatchExpressions) == 0) {
+		return 0, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(override.LeafHubSelector)
+	if err != nil {
+		return 0, err
+	}
+	if !selector.Matches(labels.Set(leafHubLabels)) {
+		return 0, nil
+	}
+	return len(override.LeafHubSelector.MatchLabels) + len(override.LeafHubSelector.MatchExpressions), nil
+}
+
+func (override *AgentConfigOverride) applyTo(agentConfig *AgentConfigSpec) {
+	if override.AggregationLevel != "" {
+		agentConfig.AggregationLevel = override.AggregationLevel
+	}
+	if override.EnableLocalPolicies != nil {
+		enableLocalPolicies := *override.EnableLocalPolicies
+		agentConfig.EnableLocalPolicies = &enableLocalPolicies
+	}
+	if override.SyncIntervals == nil {
+		return
+	}
+	if override.SyncIntervals.ManagedClusters != nil {
+		agentConfig.SyncIntervals.ManagedClusters = override.SyncIntervals.ManagedClusters.DeepCopy()
+	}
+	if override.SyncIntervals.Policies != nil {
+		agentConfig.SyncIntervals.Policies = override.SyncIntervals.Policies.DeepCopy()
+	}
+	if override.SyncIntervals.ControlInfo != nil {
+		agentConfig.SyncIntervals.ControlInfo = override.SyncIntervals.ControlInfo.DeepCopy()
+	}
+	if override.SyncIntervals.AppliedStatus != nil {
+		agentConfig.SyncIntervals.AppliedStatus = override.SyncIntervals.AppliedStatus.DeepCopy()
+	}
+}
+
+// a zero or negative interval would stop the periodic syncers, so it falls back to the default as well
+func durationOrDefault(duration *metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if duration == nil || duration.Duration <= 0 {
+		return defaultDuration
+	}
+	return duration.Duration
+}
+
+func (config *GlobalHubAgentConfig) GetConditions() []metav1.Condition {
+	return config.Status.Conditions
+}
+
+func (config *GlobalHubAgentConfig) SetConditions(conditions []metav1.Condition) {
+	config.Status.Conditions = conditions
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EqualityStrategyType specifies which fields are compared to decide whether a global resource has changed.
+// +kubebuilder:validation:Enum:="Spec";"Content"
+type EqualityStrategyType string
+
+const (
+	// SpecEquality compares the spec, the labels and the annotations of the resources
+	SpecEquality EqualityStrategyType = "Spec"
+	// ContentEquality compares all the fields except the metadata, and the labels and the annotations of the
+	// resources. it fits the kinds without a spec, like ConfigMaps or ClusterRoles
+	ContentEquality EqualityStrategyType = "Content"
+)
+
+const (
+	// GlobalResourceTypeRegistered means the resources of the type are synced to the managed hubs
+	GlobalResourceTypeRegistered = "Registered"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName={grt}
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=".spec.group"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version"
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.kind"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// GlobalResourceType is the Schema for registering a kind of resources to be propagated to the managed hubs
+type GlobalResourceType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GlobalResourceTypeSpec   `json:"spec,omitempty"`
+	Status GlobalResourceTypeStatus `json:"status,omitempty"`
+}
+
+// GlobalResourceTypeSpec defines the kind of the global resources and how they are propagated
+type GlobalResourceTypeSpec struct {
+	// Group is the API group of the resources, it's empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Version is the API version of the resources
+	// +kubebuilder:validation:Required
+	Version string `json:"version"`
+	// Kind is the kind of the resources
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+	// CleanupFields are the fields removed from the resources before they are propagated, the nested fields are
+	// separated by dots, like "metadata.annotations.foo"
+	// +kubebuilder:default:={"status"}
+	// +optional
+	CleanupFields []string `json:"cleanupFields,omitempty"`
+	// EqualityStrategy decides which fields are compared to detect the changes of the resources.
+	// Spec: compare the spec, the labels and the annotations, Content: compare all the fields except the metadata,
+	// and the labels and the annotations.
+	// +kubebuilder:default:="Spec"
+	// +optional
+	EqualityStrategy EqualityStrategyType `json:"equalityStrategy,omitempty"`
+}
+
+// GlobalResourceTypeStatus defines the observed state of GlobalResourceType
+type GlobalResourceTypeStatus struct {
+	// Conditions contains the different condition statuses for the global resource type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// GlobalResourceTypeList contains a list of GlobalResourceType
+type GlobalResourceTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalResourceType `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlobalResourceType{}, &GlobalResourceTypeList{})
+}
+
+// GroupVersionKind returns the group version kind of the resources
+func (in *GlobalResourceTypeSpec) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: in.Group, Version: in.Version, Kind: in.Kind}
+}
+
+func (resourceType *GlobalResourceType) GetConditions() []metav1.Condition {
+	return resourceType.Status.Conditions
+}
+
+func (resourceType *GlobalResourceType) SetConditions(conditions []metav1.Condition) {
+	resourceType.Status.Conditions = conditions
+}
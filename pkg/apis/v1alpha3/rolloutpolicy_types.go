@@ -0,0 +1,170 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutPhase is the phase of the rollout of a global resource.
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means the resource is sent to the hubs of the current wave, and the rollout is waiting for
+	// it to be applied on them
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutWaiting means the resource is applied on the hubs of the current wave, and the rollout is waiting for
+	// the pause between the waves while watching the health gates
+	RolloutWaiting RolloutPhase = "Waiting"
+	// RolloutHalted means a health gate failed, the rollout is stopped until the resource is changed
+	RolloutHalted RolloutPhase = "Halted"
+	// RolloutCompleted means the resource is rolled out to all the hubs
+	RolloutCompleted RolloutPhase = "Completed"
+	// RolloutFailed means the resource can't be rolled out, e.g. its rollout policy isn't found, the hubs keep the
+	// version rolled out last until the rollout is restarted
+	RolloutFailed RolloutPhase = "Failed"
+)
+
+const (
+	DefaultRolloutPauseBetweenWaves = 10 * time.Minute
+	DefaultRolloutTimeout           = 30 * time.Minute
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName={grp}
+// +kubebuilder:printcolumn:name="Waves",type="integer",JSONPath=".status.waves"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// RolloutPolicy is the Schema for rolling out the global resources to the managed hubs in waves, the global
+// resources opt in with the global-hub.open-cluster-management.io/rollout-policy annotation
+type RolloutPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RolloutPolicySpec   `json:"spec,omitempty"`
+	Status RolloutPolicyStatus `json:"status,omitempty"`
+}
+
+// RolloutPolicySpec defines the waves of the managed hubs and the health gates between them
+type RolloutPolicySpec struct {
+	// Waves are the managed hubs the resources are rolled out to, one wave after another. a hub matching several
+	// waves is in the first one, the hubs matching no wave are rolled out to after all the waves.
+	// +kubebuilder:validation:MinItems:=1
+	Waves []RolloutWave `json:"waves"`
+	// PauseBetweenWaves is the time the rollout waits after the resource is applied on the hubs of a wave before
+	// moving to the next wave, the health gates are checked during the pause
+	// +kubebuilder:default:="10m"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	PauseBetweenWaves *metav1.Duration `json:"pauseBetweenWaves,omitempty"`
+	// HealthGates are the checks on the hubs of a wave which must pass before moving to the next wave
+	// +kubebuilder:default:={}
+	// +optional
+	HealthGates RolloutHealthGates `json:"healthGates,omitempty"`
+}
+
+// RolloutWave selects the managed hubs of a wave
+type RolloutWave struct {
+	// Name identifies the wave, it's reported in the status of the rollouts
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// LeafHubNames are the names of the managed hubs of the wave
+	// +optional
+	LeafHubNames []string `json:"leafHubNames,omitempty"`
+	// LeafHubSelector selects the managed hubs of the wave by the labels of their managed clusters
+	// +optional
+	LeafHubSelector *metav1.LabelSelector `json:"leafHubSelector,omitempty"`
+}
+
+// RolloutHealthGates defines the checks on the hubs of a wave, the resource must be applied on all of them anyway
+type RolloutHealthGates struct {
+	// Timeout is the time the rollout waits for the resource to be applied on the hubs of a wave, the rollout is
+	// halted if it isn't applied on all of them in time
+	// +kubebuilder:default:="30m"
+	// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// NoComplianceRegression halts the rollout of a policy if its non-compliant clusters on a hub of the wave
+	// increased compared with before the wave
+	// +kubebuilder:default:=true
+	// +optional
+	NoComplianceRegression bool `json:"noComplianceRegression"`
+	// NoSubscriptionFailures halts the rollout of a subscription if its subscription report on a hub of the wave
+	// has failed deployments
+	// +kubebuilder:default:=true
+	// +optional
+	NoSubscriptionFailures bool `json:"noSubscriptionFailures"`
+}
+
+// RolloutPolicyStatus defines the observed state of RolloutPolicy
+type RolloutPolicyStatus struct {
+	// Waves is the number of the waves, including the wave of the hubs matching no wave
+	// +optional
+	Waves int `json:"waves,omitempty"`
+	// Rollouts are the rollouts of the global resources using the policy
+	// +optional
+	Rollouts []ResourceRollout `json:"rollouts,omitempty"`
+}
+
+// ResourceRollout is the progress of the rollout of a global resource
+type ResourceRollout struct {
+	// Kind is the kind of the global resource
+	Kind string `json:"kind"`
+	// Name is the name of the global resource
+	Name string `json:"name"`
+	// Namespace is the namespace of the global resource, it's empty for the cluster scoped resources
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Wave is the name of the current wave
+	// +optional
+	Wave string `json:"wave,omitempty"`
+	// Phase is the phase of the rollout in the current wave
+	Phase RolloutPhase `json:"phase"`
+	// Message is the detail of the phase, like the hubs the rollout is waiting for or the failed health gate
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the rollout moved to another wave or phase
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// RolloutPolicyList contains a list of RolloutPolicy
+type RolloutPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RolloutPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RolloutPolicy{}, &RolloutPolicyList{})
+}
+
+// GetPauseBetweenWaves returns the pause between the waves, or the default if it isn't set
+func (in *RolloutPolicySpec) GetPauseBetweenWaves() time.Duration {
+	if in.PauseBetweenWaves == nil || in.PauseBetweenWaves.Duration < 0 {
+		return DefaultRolloutPauseBetweenWaves
+	}
+	return in.PauseBetweenWaves.Duration
+}
+
+// GetTimeout returns the timeout of applying the resource on the hubs of a wave, or the default if it isn't set
+func (in *RolloutHealthGates) GetTimeout() time.Duration {
+	return durationOrDefault(in.Timeout, DefaultRolloutTimeout)
+}
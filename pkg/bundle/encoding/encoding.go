@@ -0,0 +1,73 @@
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// the content types of the bundle payloads, the message without the content type is encoded as json.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// the encodings of the bundles selected by the agent.
+const (
+	JSON     = "json"
+	Protobuf = "protobuf"
+)
+
+// ProtoMarshaler is implemented by the bundles which are able to be encoded as protobuf.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+// ProtoUnmarshaler is implemented by the bundles which are able to be decoded from protobuf.
+type ProtoUnmarshaler interface {
+	UnmarshalProto(payload []byte) error
+}
+
+// ContentType returns the content type of the encoding.
+func ContentType(encoding string) (string, error) {
+	switch encoding {
+	case "", JSON:
+		return ContentTypeJSON, nil
+	case Protobuf:
+		return ContentTypeProtobuf, nil
+	default:
+		return "", fmt.Errorf("the bundle encoding %s isn't supported, the supported encodings are %s and %s",
+			encoding, JSON, Protobuf)
+	}
+}
+
+// Marshal encodes the bundle with the content type and returns the content type of the payload, the bundle falls
+// back to json if it isn't able to be encoded as protobuf.
+func Marshal(bundle interface{}, contentType string) ([]byte, string, error) {
+	if marshaler, ok := bundle.(ProtoMarshaler); ok && contentType == ContentTypeProtobuf {
+		if payload, err := marshaler.MarshalProto(); err == nil {
+			return payload, ContentTypeProtobuf, nil
+		}
+	}
+
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, ContentTypeJSON, nil
+}
+
+// Unmarshal decodes the payload of the content type into the bundle.
+func Unmarshal(payload []byte, contentType string, bundle interface{}) error {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return json.Unmarshal(payload, bundle)
+	case ContentTypeProtobuf:
+		unmarshaler, ok := bundle.(ProtoUnmarshaler)
+		if !ok {
+			return fmt.Errorf("the bundle %T can't be decoded from protobuf", bundle)
+		}
+		return unmarshaler.UnmarshalProto(payload)
+	default:
+		return fmt.Errorf("the content type %s isn't supported", contentType)
+	}
+}
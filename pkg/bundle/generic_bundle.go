@@ -10,7 +10,14 @@ func NewGenericBundle() *GenericBundle {
 }
 
 // GenericBundle bundle received from transport containing Objects/DeletedObjects.
+// a delta bundle holds only the objects changed on top of the bundle version specified by BaseVersion.
 type GenericBundle struct {
 	Objects        []*unstructured.Unstructured `json:"objects"`
 	DeletedObjects []*unstructured.Unstructured `json:"deletedObjects"`
+	BaseVersion    string                       `json:"baseVersion,omitempty"`
+}
+
+// IsDelta returns true if the bundle holds only the changes since a previous bundle.
+func (genericBundle *GenericBundle) IsDelta() bool {
+	return genericBundle.BaseVersion != ""
 }
@@ -1,10 +1,55 @@
 package registration
 
-import "github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+// ErrUnsupportedSchemaVersion is returned when the bundle of the schema version can't be decoded by the manager.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported bundle schema version")
+
+// BundleDecodeFunc decodes the payload of a schema version into the bundle of the current schema version.
+type BundleDecodeFunc func(contentType string, payload []byte) (status.Bundle, error)
 
 // BundleRegistration abstract the registration for bundles according to bundle key in transport layer.
 type BundleRegistration struct {
 	MsgID            string
 	CreateBundleFunc func() status.Bundle
 	Predicate        func() bool
+	// SchemaDecoders decode the bundles of the schema versions whose shape differs from the current one, e.g. the N-1
+	// version sent by the agents which aren't upgraded yet, the other supported schema versions are decoded by the
+	// bundle of the CreateBundleFunc.
+	SchemaDecoders map[int]BundleDecodeFunc
+}
+
+// Decode decodes the payload of the schema version and the content type into a bundle, the message without the
+// schema version is decoded as the legacy schema version. the schema versions between the min schema version and the
+// current one are decoded by the bundle of the CreateBundleFunc unless a schema decoder is registered for them.
+func (r *BundleRegistration) Decode(schemaVersion int, contentType string, payload []byte) (status.Bundle, error) {
+	schemaVersion = status.ResolveSchemaVersion(schemaVersion)
+	if decode, found := r.SchemaDecoders[schemaVersion]; found {
+		return decode(contentType, payload)
+	}
+
+	if schemaVersion < status.MinSchemaVersion || schemaVersion > status.SchemaVersion {
+		return nil, fmt.Errorf("%w %d of bundle %s, the supported schema versions are [%d, %d]",
+			ErrUnsupportedSchemaVersion, schemaVersion, r.MsgID, status.MinSchemaVersion, status.SchemaVersion)
+	}
+
+	return NewDecoder(r.CreateBundleFunc)(contentType, payload)
+}
+
+// NewDecoder creates a decoder which decodes the payload into the bundle by the fields of the content type, it's
+// registered for the schema versions which only add the optional fields to the bundle.
+func NewDecoder(createBundleFunc func() status.Bundle) BundleDecodeFunc {
+	return func(contentType string, payload []byte) (status.Bundle, error) {
+		receivedBundle := createBundleFunc()
+		if err := encoding.Unmarshal(payload, contentType, receivedBundle); err != nil {
+			return nil, err
+		}
+		return receivedBundle, nil
+	}
 }
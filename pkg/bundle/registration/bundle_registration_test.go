@@ -0,0 +1,122 @@
+package registration
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+)
+
+type testBundle struct {
+	LeafHubName   string                `json:"leafHubName"`
+	BundleVersion *status.BundleVersion `json:"bundleVersion"`
+	Objects       []string              `json:"objects"`
+}
+
+func (b *testBundle) GetLeafHubName() string { return b.LeafHubName }
+
+func (b *testBundle) GetObjects() []interface{} {
+	objects := make([]interface{}, 0, len(b.Objects))
+	for _, obj := range b.Objects {
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+func (b *testBundle) GetVersion() *status.BundleVersion { return b.BundleVersion }
+
+func TestDecode(t *testing.T) {
+	// the next schema renames the objects to "items"
+	decodeNextSchema := func(contentType string, payload []byte) (status.Bundle, error) {
+		next := struct {
+			LeafHubName   string                `json:"leafHubName"`
+			BundleVersion *status.BundleVersion `json:"bundleVersion"`
+			Items         []string              `json:"items"`
+		}{}
+		if err := json.Unmarshal(payload, &next); err != nil {
+			return nil, err
+		}
+		return &testBundle{
+			LeafHubName:   next.LeafHubName,
+			BundleVersion: next.BundleVersion,
+			Objects:       next.Items,
+		}, nil
+	}
+	createBundleFunc := func() status.Bundle { return &testBundle{} }
+
+	registration := &BundleRegistration{
+		MsgID:            "Test",
+		CreateBundleFunc: createBundleFunc,
+		Predicate:        func() bool { return true },
+		SchemaDecoders: map[int]BundleDecodeFunc{
+			status.SchemaVersion + 1: decodeNextSchema,
+			status.SchemaVersion + 2: NewDecoder(createBundleFunc),
+		},
+	}
+
+	cases := []struct {
+		name          string
+		schemaVersion int
+		payload       string
+		expectObjects []string
+		expectErr     error
+	}{
+		{
+			name:          "legacy message without schema version",
+			schemaVersion: 0,
+			payload:       `{"leafHubName":"hub1","objects":["a"]}`,
+			expectObjects: []string{"a"},
+		},
+		{
+			name:          "min schema version",
+			schemaVersion: status.MinSchemaVersion,
+			payload:       `{"leafHubName":"hub1","objects":["a"]}`,
+			expectObjects: []string{"a"},
+		},
+		{
+			name:          "current schema version",
+			schemaVersion: status.SchemaVersion,
+			payload:       `{"leafHubName":"hub1","objects":["a","b"]}`,
+			expectObjects: []string{"a", "b"},
+		},
+		{
+			name:          "next schema version with renamed fields",
+			schemaVersion: status.SchemaVersion + 1,
+			payload:       `{"leafHubName":"hub1","items":["a"]}`,
+			expectObjects: []string{"a"},
+		},
+		{
+			name:          "next schema version with additional fields",
+			schemaVersion: status.SchemaVersion + 2,
+			payload:       `{"leafHubName":"hub1","objects":["a"],"labels":{"env":"dev"}}`,
+			expectObjects: []string{"a"},
+		},
+		{
+			name:          "unsupported schema version",
+			schemaVersion: status.SchemaVersion + 3,
+			payload:       `{"leafHubName":"hub1","objects":["a"]}`,
+			expectErr:     ErrUnsupportedSchemaVersion,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			receivedBundle, err := registration.Decode(tc.schemaVersion, encoding.ContentTypeJSON, []byte(tc.payload))
+			if tc.expectErr != nil {
+				assert.True(t, errors.Is(err, tc.expectErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "hub1", receivedBundle.GetLeafHubName())
+			assert.Equal(t, tc.expectObjects, receivedBundle.(*testBundle).Objects)
+		})
+	}
+
+	// the test bundle doesn't support the protobuf encoding
+	_, err := registration.Decode(status.SchemaVersion, encoding.ContentTypeProtobuf, []byte{})
+	assert.Error(t, err)
+}
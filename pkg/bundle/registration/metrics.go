@@ -0,0 +1,41 @@
+package registration
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// the reasons of the rejected bundles.
+const (
+	RejectReasonBadMessageID             = "bad_message_id"
+	RejectReasonUnregistered             = "unregistered"
+	RejectReasonUnsupportedSchemaVersion = "unsupported_schema_version"
+	RejectReasonDecodeError              = "decode_error"
+)
+
+var (
+	receivedBundles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_manager_received_bundles_total",
+		Help: "The number of the status bundles decoded by the manager by the bundle schema version.",
+	}, []string{"msg_id", "schema_version"})
+	rejectedBundles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "multicluster_global_hub_manager_rejected_bundles_total",
+		Help: "The number of the status bundles rejected by the manager, e.g. the schema version isn't supported.",
+	}, []string{"leaf_hub", "msg_id", "reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(receivedBundles, rejectedBundles)
+}
+
+// RecordReceivedBundle counts the decoded bundle of the schema version.
+func RecordReceivedBundle(msgID string, schemaVersion int) {
+	receivedBundles.WithLabelValues(msgID, strconv.Itoa(schemaVersion)).Inc()
+}
+
+// RecordRejectedBundle counts the bundle of the leaf hub rejected for the reason.
+func RecordRejectedBundle(leafHubName, msgID, reason string) {
+	rejectedBundles.WithLabelValues(leafHubName, msgID, reason).Inc()
+}
@@ -0,0 +1,13 @@
+package spec
+
+import (
+	globalhubv1alpha3 "github.com/stolostron/multicluster-global-hub/pkg/apis/v1alpha3"
+)
+
+// AgentConfigSpecBundle struct holds the effective agent config of a leaf hub.
+type AgentConfigSpecBundle struct {
+	LeafHubName string `json:"leafHubName"`
+	// Source is the name of the override the config is resolved from, or "default" if no override matches
+	Source      string                             `json:"source"`
+	AgentConfig *globalhubv1alpha3.AgentConfigSpec `json:"agentConfig"`
+}
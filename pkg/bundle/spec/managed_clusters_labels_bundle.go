@@ -1,14 +1,24 @@
 package spec
 
-import "time"
+import (
+	"strings"
+	"time"
 
-// ManagedClusterLabelsSpec struct holds information for managed cluster labels.
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// ManagedClusterLabelsSpec struct holds information for managed cluster labels, annotations and taints. the taints
+// of a managed cluster are unique by their key and effect, so the deleted taint keys are "<key>:<effect>".
 type ManagedClusterLabelsSpec struct {
-	ClusterName      string            `json:"clusterName"`
-	Labels           map[string]string `json:"labels"`
-	DeletedLabelKeys []string          `json:"deletedLabelKeys"`
-	UpdateTimestamp  time.Time         `json:"updateTimestamp"`
-	Version          int64             `json:"version"`
+	ClusterName           string            `json:"clusterName"`
+	Labels                map[string]string `json:"labels"`
+	DeletedLabelKeys      []string          `json:"deletedLabelKeys"`
+	Annotations           map[string]string `json:"annotations,omitempty"`
+	DeletedAnnotationKeys []string          `json:"deletedAnnotationKeys,omitempty"`
+	Taints                []clusterv1.Taint `json:"taints,omitempty"`
+	DeletedTaintKeys      []string          `json:"deletedTaintKeys,omitempty"`
+	UpdateTimestamp       time.Time         `json:"updateTimestamp"`
+	Version               int64             `json:"version"`
 }
 
 // ManagedClusterLabelsSpecBundle struct bundles ManagedClusterLabelsSpec objects.
@@ -16,3 +26,18 @@ type ManagedClusterLabelsSpecBundle struct {
 	Objects     []*ManagedClusterLabelsSpec `json:"objects"`
 	LeafHubName string                      `json:"leafHubName"`
 }
+
+// TaintKey returns the key of the taint in the deleted taint keys, "<key>:<effect>".
+func TaintKey(key string, effect clusterv1.TaintEffect) string {
+	return key + ":" + string(effect)
+}
+
+// ParseTaintKey returns the key and the effect of the taint key "<key>:<effect>", the taint key without the effect is
+// returned as the key since the key of a taint can't have a colon.
+func ParseTaintKey(taintKey string) (string, clusterv1.TaintEffect) {
+	index := strings.LastIndex(taintKey, ":")
+	if index < 0 {
+		return taintKey, ""
+	}
+	return taintKey[:index], clusterv1.TaintEffect(taintKey[index+1:])
+}
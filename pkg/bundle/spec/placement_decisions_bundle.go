@@ -0,0 +1,26 @@
+package spec
+
+// PlacementKind is the kind of the global placement scheduled by the global scheduler.
+type PlacementKind string
+
+const (
+	// PlacementKindPlacement the global placement is a Placement.
+	PlacementKindPlacement PlacementKind = "Placement"
+	// PlacementKindPlacementRule the global placement is a PlacementRule.
+	PlacementKindPlacementRule PlacementKind = "PlacementRule"
+)
+
+// GlobalPlacementDecision struct holds the managed clusters of a leaf hub selected for a global placement.
+type GlobalPlacementDecision struct {
+	Kind      PlacementKind `json:"kind"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Clusters  []string      `json:"clusters"`
+}
+
+// PlacementDecisionsSpecBundle struct holds the decisions of all the global placements on a leaf hub, the global
+// placements which aren't in the bundle select no cluster of the leaf hub.
+type PlacementDecisionsSpecBundle struct {
+	LeafHubName string                     `json:"leafHubName"`
+	Decisions   []*GlobalPlacementDecision `json:"decisions"`
+}
@@ -0,0 +1,15 @@
+package spec
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RolloutsSpecBundle struct holds the version of the global resources rolled out in waves the leaf hub should have,
+// which is the version being rolled out once the rollout reached the leaf hub, otherwise the version rolled out to
+// all the hubs last. the resources are applied by the generic syncer of the agent, so the bundle has the same objects
+// as the objects bundles.
+type RolloutsSpecBundle struct {
+	LeafHubName    string                       `json:"leafHubName"`
+	Objects        []*unstructured.Unstructured `json:"objects"`
+	DeletedObjects []*unstructured.Unstructured `json:"deletedObjects"`
+}
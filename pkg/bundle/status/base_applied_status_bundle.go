@@ -0,0 +1,49 @@
+package status
+
+// AppliedStatus holds the result of applying a global resource on the leaf hub.
+type AppliedStatus struct {
+	ResourceID    string `json:"resourceId"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace,omitempty"`
+	BundleVersion string `json:"bundleVersion"`
+	Applied       bool   `json:"applied"`
+	Error         string `json:"error,omitempty"`
+	// DryRun is set if the resource was only applied with server-side dry-run, Diff holds the would-be changes.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Drifted is set if the live resource was changed on the leaf hub, Diff holds the changes to restore the spec.
+	Drifted bool   `json:"drifted,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// BaseAppliedStatusBundle the bundle for the applied status of the global resources received by the leaf hub.
+type BaseAppliedStatusBundle struct {
+	Objects       []*AppliedStatus `json:"objects"`
+	LeafHubName   string           `json:"leafHubName"`
+	BundleVersion *BundleVersion   `json:"bundleVersion"`
+}
+
+// NewAppliedStatusBundle creates a new instance of BaseAppliedStatusBundle for the manager.
+func NewAppliedStatusBundle() Bundle {
+	return &BaseAppliedStatusBundle{}
+}
+
+// GetObjects return all the objects that the bundle holds.
+func (baseBundle *BaseAppliedStatusBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(baseBundle.Objects))
+	for i, obj := range baseBundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (baseBundle *BaseAppliedStatusBundle) GetLeafHubName() string {
+	return baseBundle.LeafHubName
+}
+
+// GetVersion returns the bundle version.
+func (baseBundle *BaseAppliedStatusBundle) GetVersion() *BundleVersion {
+	return baseBundle.BundleVersion
+}
@@ -0,0 +1,96 @@
+package status
+
+// ArgoCDApplicationStatus holds the sync and health status of an Argo CD Application on the leaf hub.
+type ArgoCDApplicationStatus struct {
+	ApplicationID        string `json:"applicationId"`
+	Name                 string `json:"name"`
+	Namespace            string `json:"namespace"`
+	Project              string `json:"project,omitempty"`
+	RepoURL              string `json:"repoURL,omitempty"`
+	TargetRevision       string `json:"targetRevision,omitempty"`
+	Revision             string `json:"revision,omitempty"` // the revision the application is synced to
+	DestinationServer    string `json:"destinationServer,omitempty"`
+	DestinationName      string `json:"destinationName,omitempty"`
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+	SyncStatus           string `json:"syncStatus,omitempty"`
+	HealthStatus         string `json:"healthStatus,omitempty"`
+	HealthMessage        string `json:"healthMessage,omitempty"`
+	OperationPhase       string `json:"operationPhase,omitempty"`
+	ReconciledAt         string `json:"reconciledAt,omitempty"`
+	ResourceVersion      string `json:"-"` // need it to skip the unchanged applications on the leaf hub.
+}
+
+// ArgoCDApplicationSetStatus holds the status of an Argo CD ApplicationSet and the applications it generated.
+type ArgoCDApplicationSetStatus struct {
+	ApplicationSetID  string   `json:"applicationSetId"`
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	Applications      []string `json:"applications"` // the names of the generated applications
+	ResourcesUpToDate bool     `json:"resourcesUpToDate"`
+	ErrorMessage      string   `json:"errorMessage,omitempty"`
+	ResourceVersion   string   `json:"-"` // need it to skip the unchanged applicationsets on the leaf hub.
+}
+
+// BaseArgoCDApplicationStatusBundle the bundle for the status of the Argo CD Applications on the leaf hub.
+type BaseArgoCDApplicationStatusBundle struct {
+	Objects       []*ArgoCDApplicationStatus `json:"objects"`
+	LeafHubName   string                     `json:"leafHubName"`
+	BundleVersion *BundleVersion             `json:"bundleVersion"`
+}
+
+// NewArgoCDApplicationStatusBundle creates a new instance of BaseArgoCDApplicationStatusBundle for the manager.
+func NewArgoCDApplicationStatusBundle() Bundle {
+	return &BaseArgoCDApplicationStatusBundle{}
+}
+
+// GetObjects return all the objects that the bundle holds.
+func (baseBundle *BaseArgoCDApplicationStatusBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(baseBundle.Objects))
+	for i, obj := range baseBundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (baseBundle *BaseArgoCDApplicationStatusBundle) GetLeafHubName() string {
+	return baseBundle.LeafHubName
+}
+
+// GetVersion returns the bundle version.
+func (baseBundle *BaseArgoCDApplicationStatusBundle) GetVersion() *BundleVersion {
+	return baseBundle.BundleVersion
+}
+
+// BaseArgoCDApplicationSetStatusBundle the bundle for the status of the Argo CD ApplicationSets on the leaf hub.
+type BaseArgoCDApplicationSetStatusBundle struct {
+	Objects       []*ArgoCDApplicationSetStatus `json:"objects"`
+	LeafHubName   string                        `json:"leafHubName"`
+	BundleVersion *BundleVersion                `json:"bundleVersion"`
+}
+
+// NewArgoCDApplicationSetStatusBundle creates a new instance of BaseArgoCDApplicationSetStatusBundle for the manager.
+func NewArgoCDApplicationSetStatusBundle() Bundle {
+	return &BaseArgoCDApplicationSetStatusBundle{}
+}
+
+// GetObjects return all the objects that the bundle holds.
+func (baseBundle *BaseArgoCDApplicationSetStatusBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(baseBundle.Objects))
+	for i, obj := range baseBundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (baseBundle *BaseArgoCDApplicationSetStatusBundle) GetLeafHubName() string {
+	return baseBundle.LeafHubName
+}
+
+// GetVersion returns the bundle version.
+func (baseBundle *BaseArgoCDApplicationSetStatusBundle) GetVersion() *BundleVersion {
+	return baseBundle.BundleVersion
+}
@@ -0,0 +1,47 @@
+package status
+
+// ManagedClusterAddOnStatus holds the health of an addon on a managed cluster of the leaf hub, the condition fields
+// are the status of the corresponding addon conditions: True, False or Unknown.
+type ManagedClusterAddOnStatus struct {
+	ClusterID       string `json:"clusterId"`
+	ClusterName     string `json:"clusterName"`
+	AddonName       string `json:"addonName"`
+	Available       string `json:"available"`
+	Degraded        string `json:"degraded"`
+	Progressing     string `json:"progressing"`
+	Message         string `json:"message,omitempty"`
+	AddonUID        string `json:"-"`
+	ResourceVersion string `json:"-"` // need it to skip the unchanged addons on the leaf hub.
+}
+
+// BaseManagedClusterAddOnStatusBundle the bundle for the addon status of the managed clusters on the leaf hub.
+type BaseManagedClusterAddOnStatusBundle struct {
+	Objects       []*ManagedClusterAddOnStatus `json:"objects"`
+	LeafHubName   string                       `json:"leafHubName"`
+	BundleVersion *BundleVersion               `json:"bundleVersion"`
+}
+
+// NewManagedClusterAddOnStatusBundle creates a new instance of BaseManagedClusterAddOnStatusBundle for the manager.
+func NewManagedClusterAddOnStatusBundle() Bundle {
+	return &BaseManagedClusterAddOnStatusBundle{}
+}
+
+// GetObjects return all the objects that the bundle holds.
+func (baseBundle *BaseManagedClusterAddOnStatusBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(baseBundle.Objects))
+	for i, obj := range baseBundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (baseBundle *BaseManagedClusterAddOnStatusBundle) GetLeafHubName() string {
+	return baseBundle.LeafHubName
+}
+
+// GetVersion returns the bundle version.
+func (baseBundle *BaseManagedClusterAddOnStatusBundle) GetVersion() *BundleVersion {
+	return baseBundle.BundleVersion
+}
@@ -0,0 +1,81 @@
+package status
+
+// the native policy engines whose reports are collected from the leaf hubs, the acm policies are reported by the
+// compliance bundles.
+const (
+	PolicyEngineGatekeeper = "gatekeeper"
+	PolicyEngineKyverno    = "kyverno"
+)
+
+// the normalized results of the policy reports, it follows the results of the kyverno policy reports.
+const (
+	PolicyResultPass  = "pass"
+	PolicyResultFail  = "fail"
+	PolicyResultWarn  = "warn"
+	PolicyResultError = "error"
+	PolicyResultSkip  = "skip"
+)
+
+// PolicyReport holds the results of a native policy engine on the leaf hub, it's either a kyverno
+// PolicyReport/ClusterPolicyReport or a gatekeeper constraint. the cluster name is set when the report is about a
+// managed cluster of the leaf hub, otherwise the report is about the leaf hub itself.
+type PolicyReport struct {
+	ReportID        string             `json:"reportId"`
+	Engine          string             `json:"engine"`
+	Kind            string             `json:"kind"`
+	Name            string             `json:"name"`
+	Namespace       string             `json:"namespace,omitempty"`
+	ClusterName     string             `json:"clusterName,omitempty"`
+	Pass            int64              `json:"pass"`
+	Fail            int64              `json:"fail"`
+	Warn            int64              `json:"warn"`
+	Error           int64              `json:"error"`
+	Skip            int64              `json:"skip"`
+	Violations      []*PolicyViolation `json:"violations,omitempty"`
+	ResourceVersion string             `json:"-"` // need it to skip the unchanged reports on the leaf hub.
+}
+
+// PolicyViolation is a normalized violation of a policy report, the result is fail, warn or error.
+type PolicyViolation struct {
+	Policy            string `json:"policy"`
+	Rule              string `json:"rule,omitempty"`
+	Severity          string `json:"severity,omitempty"`
+	Category          string `json:"category,omitempty"`
+	Result            string `json:"result"`
+	Message           string `json:"message,omitempty"`
+	ResourceKind      string `json:"resourceKind,omitempty"`
+	ResourceName      string `json:"resourceName,omitempty"`
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+}
+
+// BasePolicyReportBundle the bundle for the native policy engine reports on the leaf hub.
+type BasePolicyReportBundle struct {
+	Objects       []*PolicyReport `json:"objects"`
+	LeafHubName   string          `json:"leafHubName"`
+	BundleVersion *BundleVersion  `json:"bundleVersion"`
+}
+
+// NewPolicyReportBundle creates a new instance of BasePolicyReportBundle for the manager.
+func NewPolicyReportBundle() Bundle {
+	return &BasePolicyReportBundle{}
+}
+
+// GetObjects return all the objects that the bundle holds.
+func (baseBundle *BasePolicyReportBundle) GetObjects() []interface{} {
+	result := make([]interface{}, len(baseBundle.Objects))
+	for i, obj := range baseBundle.Objects {
+		result[i] = obj
+	}
+
+	return result
+}
+
+// GetLeafHubName returns the leaf hub name that sent the bundle.
+func (baseBundle *BasePolicyReportBundle) GetLeafHubName() string {
+	return baseBundle.LeafHubName
+}
+
+// GetVersion returns the bundle version.
+func (baseBundle *BasePolicyReportBundle) GetVersion() *BundleVersion {
+	return baseBundle.BundleVersion
+}
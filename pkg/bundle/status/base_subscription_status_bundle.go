@@ -0,0 +1,15 @@
+package status
+
+import (
+	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// BaseDeltaSubscriptionStatusesBundle the base struct for delta state subscription statuses bundle. it holds the
+// subscription statuses that were updated and the ids of the ones that were deleted since the last delta bundle.
+type BaseDeltaSubscriptionStatusesBundle struct {
+	Objects           []*appsv1alpha1.SubscriptionStatus `json:"objects"`
+	DeletedObjectIDs  []string                           `json:"deletedObjectIds"`
+	LeafHubName       string                             `json:"leafHubName"`
+	BaseBundleVersion *BundleVersion                     `json:"baseBundleVersion"`
+	BundleVersion     *BundleVersion                     `json:"bundleVersion"`
+}
@@ -0,0 +1,426 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// the bundles are encoded in the protobuf wire format by the protowire package, there is no generated code, the field
+// numbers used by the append and the consume functions are the schema of the messages. keep the field numbers stable
+// and only add the new fields with new numbers, the unknown fields are skipped on decoding, so a field is able to be
+// added to a message without breaking the older decoders.
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendRepeatedString appends the elements including the empty ones, so the length of the list is kept.
+func appendRepeatedString(b []byte, num protowire.Number, values []string) []byte {
+	for _, v := range values {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	}
+	return b
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// appendMessage appends the embedded message even if it's empty, it's used for the elements of the repeated fields.
+func appendMessage(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	return appendVarint(b, num, protowire.EncodeBool(v))
+}
+
+// consumeFields calls the handler with the number and the raw value of each field of the message.
+func consumeFields(b []byte, handle func(num protowire.Number, typ protowire.Type, v []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		if err := handle(num, typ, b[:m]); err != nil {
+			return fmt.Errorf("failed to decode field %d - %w", num, err)
+		}
+		b = b[m:]
+	}
+	return nil
+}
+
+func consumeBytes(typ protowire.Type, v []byte) ([]byte, error) {
+	if typ != protowire.BytesType {
+		return nil, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	value, n := protowire.ConsumeBytes(v)
+	if n < 0 {
+		return nil, protowire.ParseError(n)
+	}
+	return value, nil
+}
+
+func consumeString(typ protowire.Type, v []byte) (string, error) {
+	value, err := consumeBytes(typ, v)
+	return string(value), err
+}
+
+func consumeVarint(typ protowire.Type, v []byte) (uint64, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	value, n := protowire.ConsumeVarint(v)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return value, nil
+}
+
+func appendBundleVersion(b []byte, num protowire.Number, version *BundleVersion) []byte {
+	if version == nil {
+		return b
+	}
+	var v []byte
+	v = appendVarint(v, 1, version.Incarnation)
+	v = appendVarint(v, 2, version.Generation)
+	return appendMessage(b, num, v)
+}
+
+func consumeBundleVersion(typ protowire.Type, v []byte) (*BundleVersion, error) {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return nil, err
+	}
+	version := &BundleVersion{}
+	return version, consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) (err error) {
+		switch num {
+		case 1:
+			version.Incarnation, err = consumeVarint(typ, v)
+		case 2:
+			version.Generation, err = consumeVarint(typ, v)
+		}
+		return err
+	})
+}
+
+func appendTimestamp(b []byte, num protowire.Number, t time.Time) []byte {
+	var v []byte
+	v = appendVarint(v, 1, uint64(t.Unix()))
+	v = appendVarint(v, 2, uint64(t.Nanosecond()))
+	return appendMessage(b, num, v)
+}
+
+func consumeTimestamp(typ protowire.Type, v []byte) (time.Time, error) {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var seconds, nanos uint64
+	err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) (err error) {
+		switch num {
+		case 1:
+			seconds, err = consumeVarint(typ, v)
+		case 2:
+			nanos, err = consumeVarint(typ, v)
+		}
+		return err
+	})
+	return time.Unix(int64(seconds), int64(nanos)).UTC(), err
+}
+
+func appendGenericComplianceStatus(b []byte, num protowire.Number, status *PolicyGenericComplianceStatus) []byte {
+	var v []byte
+	v = appendString(v, 1, status.PolicyID)
+	v = appendRepeatedString(v, 2, status.CompliantClusters)
+	v = appendRepeatedString(v, 3, status.NonCompliantClusters)
+	v = appendRepeatedString(v, 4, status.UnknownComplianceClusters)
+	return appendMessage(b, num, v)
+}
+
+func consumeGenericComplianceStatus(typ protowire.Type, v []byte) (*PolicyGenericComplianceStatus, error) {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return nil, err
+	}
+	status := &PolicyGenericComplianceStatus{}
+	return status, consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var cluster string
+		var err error
+		switch num {
+		case 1:
+			status.PolicyID, err = consumeString(typ, v)
+		case 2:
+			cluster, err = consumeString(typ, v)
+			status.CompliantClusters = append(status.CompliantClusters, cluster)
+		case 3:
+			cluster, err = consumeString(typ, v)
+			status.NonCompliantClusters = append(status.NonCompliantClusters, cluster)
+		case 4:
+			cluster, err = consumeString(typ, v)
+			status.UnknownComplianceClusters = append(status.UnknownComplianceClusters, cluster)
+		}
+		return err
+	})
+}
+
+func appendCompleteComplianceStatus(b []byte, num protowire.Number, status *PolicyCompleteComplianceStatus) []byte {
+	var v []byte
+	v = appendString(v, 1, status.PolicyID)
+	v = appendRepeatedString(v, 2, status.NonCompliantClusters)
+	v = appendRepeatedString(v, 3, status.UnknownComplianceClusters)
+	return appendMessage(b, num, v)
+}
+
+func consumeCompleteComplianceStatus(typ protowire.Type, v []byte) (*PolicyCompleteComplianceStatus, error) {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return nil, err
+	}
+	status := &PolicyCompleteComplianceStatus{}
+	return status, consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var cluster string
+		var err error
+		switch num {
+		case 1:
+			status.PolicyID, err = consumeString(typ, v)
+		case 2:
+			cluster, err = consumeString(typ, v)
+			status.NonCompliantClusters = append(status.NonCompliantClusters, cluster)
+		case 3:
+			cluster, err = consumeString(typ, v)
+			status.UnknownComplianceClusters = append(status.UnknownComplianceClusters, cluster)
+		}
+		return err
+	})
+}
+
+// MarshalProto encodes the bundle as the ClustersPerPolicyBundle message.
+func (bundle *BaseClustersPerPolicyBundle) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, bundle.LeafHubName)
+	b = appendBundleVersion(b, 2, bundle.BundleVersion)
+	for _, status := range bundle.Objects {
+		b = appendGenericComplianceStatus(b, 3, status)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes the ClustersPerPolicyBundle message into the bundle.
+func (bundle *BaseClustersPerPolicyBundle) UnmarshalProto(payload []byte) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var status *PolicyGenericComplianceStatus
+		var err error
+		switch num {
+		case 1:
+			bundle.LeafHubName, err = consumeString(typ, v)
+		case 2:
+			bundle.BundleVersion, err = consumeBundleVersion(typ, v)
+		case 3:
+			status, err = consumeGenericComplianceStatus(typ, v)
+			bundle.Objects = append(bundle.Objects, status)
+		}
+		return err
+	})
+}
+
+// MarshalProto encodes the bundle as the CompleteComplianceStatusBundle message.
+func (bundle *BaseCompleteComplianceStatusBundle) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, bundle.LeafHubName)
+	b = appendBundleVersion(b, 2, bundle.BundleVersion)
+	b = appendBundleVersion(b, 3, bundle.BaseBundleVersion)
+	for _, status := range bundle.Objects {
+		b = appendCompleteComplianceStatus(b, 4, status)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes the CompleteComplianceStatusBundle message into the bundle.
+func (bundle *BaseCompleteComplianceStatusBundle) UnmarshalProto(payload []byte) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var status *PolicyCompleteComplianceStatus
+		var err error
+		switch num {
+		case 1:
+			bundle.LeafHubName, err = consumeString(typ, v)
+		case 2:
+			bundle.BundleVersion, err = consumeBundleVersion(typ, v)
+		case 3:
+			bundle.BaseBundleVersion, err = consumeBundleVersion(typ, v)
+		case 4:
+			status, err = consumeCompleteComplianceStatus(typ, v)
+			bundle.Objects = append(bundle.Objects, status)
+		}
+		return err
+	})
+}
+
+// MarshalProto encodes the bundle as the DeltaComplianceStatusBundle message.
+func (bundle *BaseDeltaComplianceStatusBundle) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, bundle.LeafHubName)
+	b = appendBundleVersion(b, 2, bundle.BundleVersion)
+	b = appendBundleVersion(b, 3, bundle.BaseBundleVersion)
+	for _, status := range bundle.Objects {
+		b = appendGenericComplianceStatus(b, 4, status)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes the DeltaComplianceStatusBundle message into the bundle.
+func (bundle *BaseDeltaComplianceStatusBundle) UnmarshalProto(payload []byte) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var status *PolicyGenericComplianceStatus
+		var err error
+		switch num {
+		case 1:
+			bundle.LeafHubName, err = consumeString(typ, v)
+		case 2:
+			bundle.BundleVersion, err = consumeBundleVersion(typ, v)
+		case 3:
+			bundle.BaseBundleVersion, err = consumeBundleVersion(typ, v)
+		case 4:
+			status, err = consumeGenericComplianceStatus(typ, v)
+			bundle.Objects = append(bundle.Objects, status)
+		}
+		return err
+	})
+}
+
+func appendClusterPolicyEvent(b []byte, num protowire.Number, event *models.LocalClusterPolicyEvent) []byte {
+	var v []byte
+	v = appendString(v, 1, event.EventName)
+	v = appendString(v, 2, event.PolicyID)
+	v = appendString(v, 3, event.Message)
+	v = appendString(v, 4, event.Reason)
+	v = appendVarint(v, 5, uint64(event.Count))
+	v = appendBytes(v, 6, event.Source)
+	v = appendTimestamp(v, 7, event.CreatedAt)
+	v = appendString(v, 8, event.Compliance)
+	v = appendString(v, 9, event.ClusterID)
+	return appendMessage(b, num, v)
+}
+
+func consumeClusterPolicyEvent(typ protowire.Type, v []byte) (*models.LocalClusterPolicyEvent, error) {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return nil, err
+	}
+	event := &models.LocalClusterPolicyEvent{}
+	return event, consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var count uint64
+		var err error
+		switch num {
+		case 1:
+			event.EventName, err = consumeString(typ, v)
+		case 2:
+			event.PolicyID, err = consumeString(typ, v)
+		case 3:
+			event.Message, err = consumeString(typ, v)
+		case 4:
+			event.Reason, err = consumeString(typ, v)
+		case 5:
+			count, err = consumeVarint(typ, v)
+			event.Count = int(count)
+		case 6:
+			event.Source, err = consumeBytes(typ, v)
+		case 7:
+			event.CreatedAt, err = consumeTimestamp(typ, v)
+		case 8:
+			event.Compliance, err = consumeString(typ, v)
+		case 9:
+			event.ClusterID, err = consumeString(typ, v)
+		}
+		return err
+	})
+}
+
+// MarshalProto encodes the bundle as the ClusterPolicyStatusEventBundle message, the events are ordered by the policy
+// so the payload is stable.
+func (bundle *BaseClusterPolicyStatusEventBundle) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, bundle.LeafHubName)
+	b = appendBundleVersion(b, 2, bundle.BundleVersion)
+
+	policyIDs := make([]string, 0, len(bundle.PolicyStatusEvents))
+	for policyID := range bundle.PolicyStatusEvents {
+		policyIDs = append(policyIDs, policyID)
+	}
+	sort.Strings(policyIDs)
+	for _, policyID := range policyIDs {
+		var v []byte
+		v = appendString(v, 1, policyID)
+		for _, event := range bundle.PolicyStatusEvents[policyID] {
+			v = appendClusterPolicyEvent(v, 2, event)
+		}
+		b = appendMessage(b, 3, v)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes the ClusterPolicyStatusEventBundle message into the bundle.
+func (bundle *BaseClusterPolicyStatusEventBundle) UnmarshalProto(payload []byte) error {
+	if bundle.PolicyStatusEvents == nil {
+		bundle.PolicyStatusEvents = make(map[string][]*models.LocalClusterPolicyEvent)
+	}
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var value []byte
+		var err error
+		switch num {
+		case 1:
+			bundle.LeafHubName, err = consumeString(typ, v)
+		case 2:
+			bundle.BundleVersion, err = consumeBundleVersion(typ, v)
+		case 3:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			var policyID string
+			var events []*models.LocalClusterPolicyEvent
+			err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) error {
+				var event *models.LocalClusterPolicyEvent
+				var err error
+				switch num {
+				case 1:
+					policyID, err = consumeString(typ, v)
+				case 2:
+					event, err = consumeClusterPolicyEvent(typ, v)
+					events = append(events, event)
+				}
+				return err
+			})
+			bundle.PolicyStatusEvents[policyID] = append(bundle.PolicyStatusEvents[policyID], events...)
+		}
+		return err
+	})
+}
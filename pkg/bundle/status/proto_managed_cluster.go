@@ -0,0 +1,293 @@
+package status
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// MarshalManagedClustersProto encodes the managed clusters as the ManagedClustersBundle message, the metadata and the
+// conditions are encoded by the kubernetes protobuf messages.
+func MarshalManagedClustersProto(leafHubName string, version *BundleVersion,
+	clusters []*clusterv1.ManagedCluster,
+) ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, leafHubName)
+	b = appendBundleVersion(b, 2, version)
+	for _, cluster := range clusters {
+		v, err := marshalManagedCluster(cluster)
+		if err != nil {
+			return nil, err
+		}
+		b = appendMessage(b, 3, v)
+	}
+	return b, nil
+}
+
+// UnmarshalManagedClustersProto decodes the ManagedClustersBundle message.
+func UnmarshalManagedClustersProto(payload []byte) (string, *BundleVersion, []*clusterv1.ManagedCluster, error) {
+	var leafHubName string
+	var version *BundleVersion
+	var clusters []*clusterv1.ManagedCluster
+	err := consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var value []byte
+		var err error
+		switch num {
+		case 1:
+			leafHubName, err = consumeString(typ, v)
+		case 2:
+			version, err = consumeBundleVersion(typ, v)
+		case 3:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			cluster := &clusterv1.ManagedCluster{}
+			err = unmarshalManagedCluster(value, cluster)
+			clusters = append(clusters, cluster)
+		}
+		return err
+	})
+	return leafHubName, version, clusters, err
+}
+
+func marshalManagedCluster(cluster *clusterv1.ManagedCluster) ([]byte, error) {
+	metadata, err := cluster.ObjectMeta.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	spec, err := marshalManagedClusterSpec(&cluster.Spec)
+	if err != nil {
+		return nil, err
+	}
+	status, err := marshalManagedClusterStatus(&cluster.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = appendString(b, 1, cluster.APIVersion)
+	b = appendString(b, 2, cluster.Kind)
+	b = appendMessage(b, 3, metadata)
+	b = appendMessage(b, 4, spec)
+	b = appendMessage(b, 5, status)
+	return b, nil
+}
+
+func unmarshalManagedCluster(payload []byte, cluster *clusterv1.ManagedCluster) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var value []byte
+		var err error
+		switch num {
+		case 1:
+			cluster.APIVersion, err = consumeString(typ, v)
+		case 2:
+			cluster.Kind, err = consumeString(typ, v)
+		case 3:
+			if value, err = consumeBytes(typ, v); err == nil {
+				err = cluster.ObjectMeta.Unmarshal(value)
+			}
+		case 4:
+			if value, err = consumeBytes(typ, v); err == nil {
+				err = unmarshalManagedClusterSpec(value, &cluster.Spec)
+			}
+		case 5:
+			if value, err = consumeBytes(typ, v); err == nil {
+				err = unmarshalManagedClusterStatus(value, &cluster.Status)
+			}
+		}
+		return err
+	})
+}
+
+func marshalManagedClusterSpec(spec *clusterv1.ManagedClusterSpec) ([]byte, error) {
+	var b []byte
+	for _, clientConfig := range spec.ManagedClusterClientConfigs {
+		var v []byte
+		v = appendString(v, 1, clientConfig.URL)
+		v = appendBytes(v, 2, clientConfig.CABundle)
+		b = appendMessage(b, 1, v)
+	}
+	b = appendBool(b, 2, spec.HubAcceptsClient)
+	b = appendVarint(b, 3, uint64(spec.LeaseDurationSeconds))
+	for i := range spec.Taints {
+		timeAdded, err := spec.Taints[i].TimeAdded.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		var v []byte
+		v = appendString(v, 1, spec.Taints[i].Key)
+		v = appendString(v, 2, spec.Taints[i].Value)
+		v = appendString(v, 3, string(spec.Taints[i].Effect))
+		v = appendBytes(v, 4, timeAdded)
+		b = appendMessage(b, 4, v)
+	}
+	return b, nil
+}
+
+func unmarshalManagedClusterSpec(payload []byte, spec *clusterv1.ManagedClusterSpec) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var value []byte
+		var varint uint64
+		var err error
+		switch num {
+		case 1:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			clientConfig := clusterv1.ClientConfig{}
+			err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) (err error) {
+				switch num {
+				case 1:
+					clientConfig.URL, err = consumeString(typ, v)
+				case 2:
+					clientConfig.CABundle, err = consumeBytes(typ, v)
+				}
+				return err
+			})
+			spec.ManagedClusterClientConfigs = append(spec.ManagedClusterClientConfigs, clientConfig)
+		case 2:
+			varint, err = consumeVarint(typ, v)
+			spec.HubAcceptsClient = protowire.DecodeBool(varint)
+		case 3:
+			varint, err = consumeVarint(typ, v)
+			spec.LeaseDurationSeconds = int32(varint)
+		case 4:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			taint := clusterv1.Taint{}
+			err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) error {
+				var value []byte
+				var err error
+				switch num {
+				case 1:
+					taint.Key, err = consumeString(typ, v)
+				case 2:
+					taint.Value, err = consumeString(typ, v)
+				case 3:
+					value, err = consumeBytes(typ, v)
+					taint.Effect = clusterv1.TaintEffect(value)
+				case 4:
+					if value, err = consumeBytes(typ, v); err == nil {
+						err = taint.TimeAdded.Unmarshal(value)
+					}
+				}
+				return err
+			})
+			spec.Taints = append(spec.Taints, taint)
+		}
+		return err
+	})
+}
+
+func marshalManagedClusterStatus(status *clusterv1.ManagedClusterStatus) ([]byte, error) {
+	var b []byte
+	for i := range status.Conditions {
+		condition, err := status.Conditions[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		b = appendMessage(b, 1, condition)
+	}
+	b = appendResourceList(b, 2, status.Capacity)
+	b = appendResourceList(b, 3, status.Allocatable)
+	b = appendString(b, 4, status.Version.Kubernetes)
+	for _, claim := range status.ClusterClaims {
+		var v []byte
+		v = appendString(v, 1, claim.Name)
+		v = appendString(v, 2, claim.Value)
+		b = appendMessage(b, 5, v)
+	}
+	return b, nil
+}
+
+func unmarshalManagedClusterStatus(payload []byte, status *clusterv1.ManagedClusterStatus) error {
+	return consumeFields(payload, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		var value []byte
+		var err error
+		switch num {
+		case 1:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			condition := metav1.Condition{}
+			err = condition.Unmarshal(value)
+			status.Conditions = append(status.Conditions, condition)
+		case 2:
+			if status.Capacity == nil {
+				status.Capacity = clusterv1.ResourceList{}
+			}
+			err = consumeResource(typ, v, status.Capacity)
+		case 3:
+			if status.Allocatable == nil {
+				status.Allocatable = clusterv1.ResourceList{}
+			}
+			err = consumeResource(typ, v, status.Allocatable)
+		case 4:
+			status.Version.Kubernetes, err = consumeString(typ, v)
+		case 5:
+			if value, err = consumeBytes(typ, v); err != nil {
+				return err
+			}
+			claim := clusterv1.ManagedClusterClaim{}
+			err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) (err error) {
+				switch num {
+				case 1:
+					claim.Name, err = consumeString(typ, v)
+				case 2:
+					claim.Value, err = consumeString(typ, v)
+				}
+				return err
+			})
+			status.ClusterClaims = append(status.ClusterClaims, claim)
+		}
+		return err
+	})
+}
+
+// appendResourceList appends the resources as the map entries ordered by the name, the quantity is encoded as the
+// string like the json encoding.
+func appendResourceList(b []byte, num protowire.Number, resources clusterv1.ResourceList) []byte {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		quantity := resources[clusterv1.ResourceName(name)]
+		var v []byte
+		v = appendString(v, 1, name)
+		v = appendString(v, 2, quantity.String())
+		b = appendMessage(b, num, v)
+	}
+	return b
+}
+
+func consumeResource(typ protowire.Type, v []byte, resources clusterv1.ResourceList) error {
+	value, err := consumeBytes(typ, v)
+	if err != nil {
+		return err
+	}
+	var name, quantity string
+	err = consumeFields(value, func(num protowire.Number, typ protowire.Type, v []byte) (err error) {
+		switch num {
+		case 1:
+			name, err = consumeString(typ, v)
+		case 2:
+			quantity, err = consumeString(typ, v)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	parsed, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return err
+	}
+	resources[clusterv1.ResourceName(name)] = parsed
+	return nil
+}
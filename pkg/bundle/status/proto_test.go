@@ -0,0 +1,326 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type protoBundle interface {
+	MarshalProto() ([]byte, error)
+	UnmarshalProto(payload []byte) error
+}
+
+// assertProtoRoundTrip checks the bundle decoded from protobuf has the same json as the original bundle.
+func assertProtoRoundTrip(t *testing.T, bundle protoBundle, decoded protoBundle) {
+	payload, err := bundle.MarshalProto()
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.UnmarshalProto(payload))
+
+	expected, err := json.Marshal(bundle)
+	assert.NoError(t, err)
+	actual, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestComplianceBundlesProto(t *testing.T) {
+	genericStatus := &PolicyGenericComplianceStatus{
+		PolicyID:                  "d9347b09-bb46-4e2b-91ea-513e83ab9ea7",
+		CompliantClusters:         []string{"cluster1", "cluster2"},
+		NonCompliantClusters:      []string{"cluster3"},
+		UnknownComplianceClusters: []string{"cluster4"},
+	}
+
+	assertProtoRoundTrip(t, &BaseClustersPerPolicyBundle{
+		Objects:       []*PolicyGenericComplianceStatus{genericStatus},
+		LeafHubName:   "hub1",
+		BundleVersion: NewBundleVersion(1, 2),
+	}, &BaseClustersPerPolicyBundle{})
+
+	assertProtoRoundTrip(t, &BaseDeltaComplianceStatusBundle{
+		Objects:           []*PolicyGenericComplianceStatus{genericStatus},
+		LeafHubName:       "hub1",
+		BaseBundleVersion: NewBundleVersion(1, 2),
+		BundleVersion:     NewBundleVersion(1, 3),
+	}, &BaseDeltaComplianceStatusBundle{})
+
+	assertProtoRoundTrip(t, &BaseCompleteComplianceStatusBundle{
+		Objects: []*PolicyCompleteComplianceStatus{{
+			PolicyID:                  "d9347b09-bb46-4e2b-91ea-513e83ab9ea7",
+			NonCompliantClusters:      []string{"cluster3", ""},
+			UnknownComplianceClusters: []string{"cluster4"},
+		}},
+		LeafHubName:       "hub1",
+		BaseBundleVersion: NewBundleVersion(1, 2),
+		BundleVersion:     NewBundleVersion(0, 0),
+	}, &BaseCompleteComplianceStatusBundle{})
+}
+
+func TestClusterPolicyStatusEventBundleProto(t *testing.T) {
+	event := func(name string) *models.LocalClusterPolicyEvent {
+		return &models.LocalClusterPolicyEvent{
+			BaseLocalPolicyEvent: models.BaseLocalPolicyEvent{
+				EventName:  name,
+				PolicyID:   "d9347b09-bb46-4e2b-91ea-513e83ab9ea7",
+				Message:    "NonCompliant; violation - pods not found",
+				Reason:     "PolicyStatusSync",
+				Count:      2,
+				Source:     []byte(`{"component":"policy-status-history-sync"}`),
+				CreatedAt:  time.Date(2023, 6, 1, 8, 30, 15, 123456789, time.UTC),
+				Compliance: "NonCompliant",
+			},
+			ClusterID: "0f1b6c5e-4d0a-4d3b-8d47-3e5c2f7a9b10",
+		}
+	}
+
+	assertProtoRoundTrip(t, &BaseClusterPolicyStatusEventBundle{
+		LeafHubName: "hub1",
+		PolicyStatusEvents: map[string][]*models.LocalClusterPolicyEvent{
+			"policy1": {event("policy1.cluster1.17b0db2427432200"), event("policy1.cluster2.17b0db2427432201")},
+			"policy2": {event("policy2.cluster1.17b0db2427432202")},
+		},
+		BundleVersion: NewBundleVersion(1, 2),
+	}, &BaseClusterPolicyStatusEventBundle{})
+}
+
+type managedClustersBundle struct {
+	Objects       []*clusterv1.ManagedCluster `json:"objects"`
+	LeafHubName   string                      `json:"leafHubName"`
+	BundleVersion *BundleVersion              `json:"bundleVersion"`
+}
+
+func (bundle *managedClustersBundle) MarshalProto() ([]byte, error) {
+	return MarshalManagedClustersProto(bundle.LeafHubName, bundle.BundleVersion, bundle.Objects)
+}
+
+func (bundle *managedClustersBundle) UnmarshalProto(payload []byte) (err error) {
+	bundle.LeafHubName, bundle.BundleVersion, bundle.Objects, err = UnmarshalManagedClustersProto(payload)
+	return err
+}
+
+func newManagedCluster(name string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		TypeMeta: metav1.TypeMeta{APIVersion: "cluster.open-cluster-management.io/v1", Kind: "ManagedCluster"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               "0f1b6c5e-4d0a-4d3b-8d47-3e5c2f7a9b10",
+			ResourceVersion:   "123456",
+			CreationTimestamp: metav1.Date(2023, 6, 1, 8, 30, 15, 0, time.UTC),
+			Labels: map[string]string{
+				"cloud": "Amazon", "vendor": "OpenShift", "name": name,
+				"feature.open-cluster-management.io/addon-work-manager": "available",
+			},
+			Annotations: map[string]string{"global-hub.open-cluster-management.io/managed-by": "hub1"},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			ManagedClusterClientConfigs: []clusterv1.ClientConfig{{
+				URL:      "https://api." + name + ".example.com:6443",
+				CABundle: []byte("-----BEGIN CERTIFICATE-----"),
+			}},
+			HubAcceptsClient:     true,
+			LeaseDurationSeconds: 60,
+			Taints: []clusterv1.Taint{{
+				Key:       clusterv1.ManagedClusterTaintUnreachable,
+				Effect:    clusterv1.TaintEffectNoSelect,
+				TimeAdded: metav1.Date(2023, 6, 2, 8, 30, 15, 0, time.UTC),
+			}},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{{
+				Type:               clusterv1.ManagedClusterConditionAvailable,
+				Status:             metav1.ConditionTrue,
+				Reason:             "ManagedClusterAvailable",
+				Message:            "Managed cluster is available",
+				LastTransitionTime: metav1.Date(2023, 6, 1, 8, 31, 0, 0, time.UTC),
+			}},
+			Capacity: clusterv1.ResourceList{
+				clusterv1.ResourceCPU:    resource.MustParse("24"),
+				clusterv1.ResourceMemory: resource.MustParse("96Gi"),
+			},
+			Allocatable: clusterv1.ResourceList{
+				clusterv1.ResourceCPU:    resource.MustParse("21500m"),
+				clusterv1.ResourceMemory: resource.MustParse("92Gi"),
+			},
+			Version: clusterv1.ManagedClusterVersion{Kubernetes: "v1.26.3+b404935"},
+			ClusterClaims: []clusterv1.ManagedClusterClaim{
+				{Name: "id.k8s.io", Value: name},
+				{Name: "platform.open-cluster-management.io", Value: "AWS"},
+			},
+		},
+	}
+}
+
+func TestManagedClustersProto(t *testing.T) {
+	assertProtoRoundTrip(t, &managedClustersBundle{
+		Objects:       []*clusterv1.ManagedCluster{newManagedCluster("cluster1"), {}},
+		LeafHubName:   "hub1",
+		BundleVersion: NewBundleVersion(1, 2),
+	}, &managedClustersBundle{})
+}
+
+func TestUnknownFieldsProto(t *testing.T) {
+	bundle := &BaseClustersPerPolicyBundle{LeafHubName: "hub1", BundleVersion: NewBundleVersion(1, 2)}
+	payload, err := bundle.MarshalProto()
+	assert.NoError(t, err)
+
+	// the field added by the newer agent is skipped
+	payload = appendString(payload, 100, "unknown")
+	decoded := &BaseClustersPerPolicyBundle{}
+	assert.NoError(t, decoded.UnmarshalProto(payload))
+	assert.Equal(t, "hub1", decoded.LeafHubName)
+	assert.Equal(t, uint64(2), decoded.BundleVersion.Generation)
+
+	// the truncated payload is rejected
+	assert.Error(t, decoded.UnmarshalProto(payload[:len(payload)-1]))
+}
+
+func newClustersPerPolicyBundle(policies, clusters int) *BaseClustersPerPolicyBundle {
+	bundle := &BaseClustersPerPolicyBundle{LeafHubName: "hub1", BundleVersion: NewBundleVersion(1, 2)}
+	for i := 0; i < policies; i++ {
+		status := &PolicyGenericComplianceStatus{PolicyID: fmt.Sprintf("d9347b09-bb46-4e2b-91ea-%012d", i)}
+		for j := 0; j < clusters; j++ {
+			cluster := fmt.Sprintf("managed-cluster-%d", j)
+			if j%10 == 0 {
+				status.NonCompliantClusters = append(status.NonCompliantClusters, cluster)
+			} else {
+				status.CompliantClusters = append(status.CompliantClusters, cluster)
+			}
+		}
+		bundle.Objects = append(bundle.Objects, status)
+	}
+	return bundle
+}
+
+func newManagedClustersBundle(clusters int) *managedClustersBundle {
+	bundle := &managedClustersBundle{LeafHubName: "hub1", BundleVersion: NewBundleVersion(1, 2)}
+	for i := 0; i < clusters; i++ {
+		bundle.Objects = append(bundle.Objects, newManagedCluster(fmt.Sprintf("managed-cluster-%d", i)))
+	}
+	return bundle
+}
+
+func benchmarkMarshal(b *testing.B, bundle protoBundle, marshal func(protoBundle) ([]byte, error)) {
+	b.ReportAllocs()
+	var size int
+	for i := 0; i < b.N; i++ {
+		payload, err := marshal(bundle)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(payload)
+	}
+	b.ReportMetric(float64(size), "payload-bytes")
+}
+
+func benchmarkUnmarshal(b *testing.B, payload []byte, unmarshal func([]byte) error) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := unmarshal(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func marshalJSON(bundle protoBundle) ([]byte, error) { return json.Marshal(bundle) }
+
+func marshalProto(bundle protoBundle) ([]byte, error) { return bundle.MarshalProto() }
+
+func BenchmarkClustersPerPolicyMarshal(b *testing.B) {
+	bundle := newClustersPerPolicyBundle(200, 500)
+	b.Run("json", func(b *testing.B) { benchmarkMarshal(b, bundle, marshalJSON) })
+	b.Run("protobuf", func(b *testing.B) { benchmarkMarshal(b, bundle, marshalProto) })
+}
+
+func BenchmarkClustersPerPolicyUnmarshal(b *testing.B) {
+	bundle := newClustersPerPolicyBundle(200, 500)
+	jsonPayload, _ := json.Marshal(bundle)
+	protoPayload, _ := bundle.MarshalProto()
+	b.Run("json", func(b *testing.B) {
+		benchmarkUnmarshal(b, jsonPayload, func(payload []byte) error {
+			return json.Unmarshal(payload, &BaseClustersPerPolicyBundle{})
+		})
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchmarkUnmarshal(b, protoPayload, func(payload []byte) error {
+			return (&BaseClustersPerPolicyBundle{}).UnmarshalProto(payload)
+		})
+	})
+}
+
+func BenchmarkManagedClustersMarshal(b *testing.B) {
+	bundle := newManagedClustersBundle(1000)
+	b.Run("json", func(b *testing.B) { benchmarkMarshal(b, bundle, marshalJSON) })
+	b.Run("protobuf", func(b *testing.B) { benchmarkMarshal(b, bundle, marshalProto) })
+}
+
+func BenchmarkManagedClustersUnmarshal(b *testing.B) {
+	bundle := newManagedClustersBundle(1000)
+	jsonPayload, _ := json.Marshal(bundle)
+	protoPayload, _ := bundle.MarshalProto()
+	b.Run("json", func(b *testing.B) {
+		benchmarkUnmarshal(b, jsonPayload, func(payload []byte) error {
+			return json.Unmarshal(payload, &managedClustersBundle{})
+		})
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchmarkUnmarshal(b, protoPayload, func(payload []byte) error {
+			return (&managedClustersBundle{}).UnmarshalProto(payload)
+		})
+	})
+}
+
+// benchmarkWire measures the bytes sent to kafka, the bundle is encoded into the transport message which is compressed
+// by gzip, so the protobuf is compared with the json baseline as it's transported.
+func benchmarkWire(b *testing.B, bundle protoBundle, contentType string) {
+	gzip, err := compressor.NewCompressor(compressor.GZip)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	var size int
+	for i := 0; i < b.N; i++ {
+		payload, payloadContentType, err := encoding.Marshal(bundle, contentType)
+		if err != nil {
+			b.Fatal(err)
+		}
+		messageBytes, err := transport.MarshalMessage(&transport.Message{
+			ID:          "hub1.Bundle",
+			Version:     "1.2",
+			ContentType: payloadContentType,
+			Payload:     payload,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		compressedBytes, err := gzip.Compress(messageBytes)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(compressedBytes)
+	}
+	b.ReportMetric(float64(size), "wire-bytes")
+}
+
+func BenchmarkClustersPerPolicyWire(b *testing.B) {
+	bundle := newClustersPerPolicyBundle(200, 500)
+	b.Run("json", func(b *testing.B) { benchmarkWire(b, bundle, encoding.ContentTypeJSON) })
+	b.Run("protobuf", func(b *testing.B) { benchmarkWire(b, bundle, encoding.ContentTypeProtobuf) })
+}
+
+func BenchmarkManagedClustersWire(b *testing.B) {
+	bundle := newManagedClustersBundle(1000)
+	b.Run("json", func(b *testing.B) { benchmarkWire(b, bundle, encoding.ContentTypeJSON) })
+	b.Run("protobuf", func(b *testing.B) { benchmarkWire(b, bundle, encoding.ContentTypeProtobuf) })
+}
@@ -0,0 +1,89 @@
+package status
+
+import (
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+)
+
+const (
+	// SchemaVersion is the schema version of the status bundles sent by this agent, it's increased whenever the
+	// shape of a bundle payload is changed in a way the older manager can't decode, e.g. a field is renamed.
+	// version 2 adds the capabilities to the control info bundle.
+	SchemaVersion = 2
+	// MinSchemaVersion is the oldest schema version the agent is able to send the bundles with, and the manager is
+	// able to decode.
+	MinSchemaVersion = LegacySchemaVersion
+	// LegacySchemaVersion is the schema version of the messages without the schema version header, they're sent by
+	// the agents released before the header was introduced.
+	LegacySchemaVersion = 1
+)
+
+// ResolveSchemaVersion returns the schema version of a message, the message without the header is a legacy one.
+func ResolveSchemaVersion(schemaVersion int) int {
+	if schemaVersion <= 0 {
+		return LegacySchemaVersion
+	}
+	return schemaVersion
+}
+
+// Capabilities are advertised by the agent in the control info bundle and by the manager in the manager capabilities
+// spec bundle, so each of them knows which schema the other speaks before they're upgraded to the same release.
+type Capabilities struct {
+	// SchemaVersion is the newest schema version of the status bundles the agent sends or the manager decodes.
+	SchemaVersion int `json:"schemaVersion"`
+	// MinSchemaVersion is the oldest schema version of the status bundles the agent sends or the manager decodes.
+	MinSchemaVersion int `json:"minSchemaVersion"`
+	// ContentTypes are the content types of the bundle payloads the manager decodes, the payloads are encoded as
+	// json if it's empty.
+	ContentTypes []string `json:"contentTypes,omitempty"`
+}
+
+// NewCapabilities returns the capabilities of this agent or manager.
+func NewCapabilities() *Capabilities {
+	return &Capabilities{
+		SchemaVersion:    SchemaVersion,
+		MinSchemaVersion: MinSchemaVersion,
+		ContentTypes:     []string{encoding.ContentTypeJSON, encoding.ContentTypeProtobuf},
+	}
+}
+
+// NewLegacyCapabilities returns the capabilities of the agent released before the capabilities handshake.
+func NewLegacyCapabilities() *Capabilities {
+	return &Capabilities{
+		SchemaVersion:    LegacySchemaVersion,
+		MinSchemaVersion: LegacySchemaVersion,
+	}
+}
+
+// NegotiateSchemaVersion returns the newest schema version supported by both of the agent and the manager, the
+// manager capabilities are nil if the manager doesn't advertise them, then the schema version of the agent is used.
+// an error is returned with the schema version of the agent if they don't share any schema version.
+func (c *Capabilities) NegotiateSchemaVersion(manager *Capabilities) (int, error) {
+	if manager == nil {
+		return c.SchemaVersion, nil
+	}
+
+	schemaVersion := c.SchemaVersion
+	if manager.SchemaVersion < schemaVersion {
+		schemaVersion = manager.SchemaVersion
+	}
+	if schemaVersion < c.MinSchemaVersion || schemaVersion < manager.MinSchemaVersion {
+		return c.SchemaVersion, fmt.Errorf("the schema versions [%d, %d] of the agent and [%d, %d] of the manager "+
+			"don't overlap", c.MinSchemaVersion, c.SchemaVersion, manager.MinSchemaVersion, manager.SchemaVersion)
+	}
+	return schemaVersion, nil
+}
+
+// SupportsContentType returns true if the bundles of the content type are decoded, json is always supported.
+func (c *Capabilities) SupportsContentType(contentType string) bool {
+	if contentType == "" || contentType == encoding.ContentTypeJSON {
+		return true
+	}
+	for _, supportedContentType := range c.ContentTypes {
+		if supportedContentType == contentType {
+			return true
+		}
+	}
+	return false
+}
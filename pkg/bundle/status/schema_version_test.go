@@ -0,0 +1,54 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateSchemaVersion(t *testing.T) {
+	agent := &Capabilities{SchemaVersion: 3, MinSchemaVersion: 2}
+
+	cases := []struct {
+		name          string
+		manager       *Capabilities
+		expectVersion int
+		expectErr     bool
+	}{
+		{
+			name:          "the manager doesn't advertise the capabilities",
+			manager:       nil,
+			expectVersion: 3,
+		},
+		{
+			name:          "the manager is upgraded",
+			manager:       &Capabilities{SchemaVersion: 4, MinSchemaVersion: 1},
+			expectVersion: 3,
+		},
+		{
+			name:          "the manager isn't upgraded yet",
+			manager:       &Capabilities{SchemaVersion: 2, MinSchemaVersion: 1},
+			expectVersion: 2,
+		},
+		{
+			name:          "the manager is too old",
+			manager:       &Capabilities{SchemaVersion: 1, MinSchemaVersion: 1},
+			expectVersion: 3,
+			expectErr:     true,
+		},
+		{
+			name:          "the manager dropped the schema version of the agent",
+			manager:       &Capabilities{SchemaVersion: 5, MinSchemaVersion: 4},
+			expectVersion: 3,
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			version, err := agent.NegotiateSchemaVersion(tc.manager)
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expectVersion, version)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package conflator
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/go-logr/logr"
@@ -18,6 +20,7 @@ func NewConflationManager(conflationUnitsReadyQueue *ConflationReadyQueue,
 	return &ConflationManager{
 		log:                            ctrl.Log.WithName("conflation-manager"),
 		conflationUnits:                make(map[string]*ConflationUnit), // map from leaf hub to conflation unit
+		offboardedLeafHubs:             make(map[string]struct{}),
 		requireInitialDependencyChecks: requireInitialDependencyChecks,
 		registrations:                  make([]*ConflationRegistration, 0),
 		readyQueue:                     conflationUnitsReadyQueue,
@@ -30,6 +33,7 @@ func NewConflationManager(conflationUnitsReadyQueue *ConflationReadyQueue,
 type ConflationManager struct {
 	log                            logr.Logger
 	conflationUnits                map[string]*ConflationUnit // map from leaf hub to conflation unit
+	offboardedLeafHubs             map[string]struct{}        // the bundles of the offboarded leaf hubs are discarded
 	requireInitialDependencyChecks bool
 	registrations                  []*ConflationRegistration
 	readyQueue                     *ConflationReadyQueue
@@ -44,11 +48,57 @@ func (cm *ConflationManager) Register(registration *ConflationRegistration) {
 
 // Insert function inserts the bundle to the appropriate conflation unit.
 func (cm *ConflationManager) Insert(bundle statusbundle.Bundle, metadata bundle.BundleMetadata) {
-	cm.getConflationUnit(bundle.GetLeafHubName()).insert(bundle, metadata)
+	conflationUnit := cm.getConflationUnit(bundle.GetLeafHubName())
+	if conflationUnit == nil {
+		return // the leaf hub is offboarded, discard the bundle
+	}
+	conflationUnit.insert(bundle, metadata)
+}
+
+// OffboardLeafHub drops the conflation unit of the leaf hub and discards its bundles until the leaf hub is onboarded
+// again. it waits until the bundles of the leaf hub in process are finished, so none of them is written to the
+// database after the leaf hub is offboarded.
+func (cm *ConflationManager) OffboardLeafHub(ctx context.Context, leafHubName string) error {
+	cm.lock.Lock()
+	conflationUnit, found := cm.conflationUnits[leafHubName]
+	cm.offboardedLeafHubs[leafHubName] = struct{}{}
+	cm.lock.Unlock()
+
+	if !found {
+		return nil
+	}
+	// the conflation unit is kept until it's drained, so offboarding the leaf hub again waits for it as well
+	conflationUnit.remove()
+	if err := conflationUnit.drain(ctx); err != nil {
+		return fmt.Errorf("failed to wait for the bundles in process of leaf hub %s: %w", leafHubName, err)
+	}
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if cm.conflationUnits[leafHubName] == conflationUnit {
+		delete(cm.conflationUnits, leafHubName)
+	}
+	return nil
+}
+
+// OnboardLeafHub accepts the bundles of the leaf hub again, its conflation unit is dropped if any, so the bundles of
+// the next incarnation of the leaf hub are processed from scratch, regardless of the versions processed before.
+func (cm *ConflationManager) OnboardLeafHub(leafHubName string) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	if conflationUnit, found := cm.conflationUnits[leafHubName]; found {
+		conflationUnit.remove()
+		delete(cm.conflationUnits, leafHubName)
+	}
+	delete(cm.offboardedLeafHubs, leafHubName)
 }
 
 // GetBundlesMetadata provides collections of the CU's bundle transport-metadata.
 func (cm *ConflationManager) GetBundlesMetadata() []bundle.BundleMetadata {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
 	metadata := make([]bundle.BundleMetadata, 0)
 
 	for _, cu := range cm.conflationUnits {
@@ -58,11 +108,14 @@ func (cm *ConflationManager) GetBundlesMetadata() []bundle.BundleMetadata {
 	return metadata
 }
 
-// if conflation unit doesn't exist for leaf hub, creates it.
+// if conflation unit doesn't exist for leaf hub, creates it. returns nil if the leaf hub is offboarded.
 func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUnit {
 	cm.lock.Lock() // use lock to find/create conflation units
 	defer cm.lock.Unlock()
 
+	if _, offboarded := cm.offboardedLeafHubs[leafHubName]; offboarded {
+		return nil
+	}
 	if conflationUnit, found := cm.conflationUnits[leafHubName]; found {
 		return conflationUnit
 	}
@@ -0,0 +1,89 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
+	statusbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+type testBundle struct {
+	leafHubName string
+	version     *statusbundle.BundleVersion
+}
+
+func (b *testBundle) GetLeafHubName() string                  { return b.leafHubName }
+func (b *testBundle) GetObjects() []interface{}               { return nil }
+func (b *testBundle) GetVersion() *statusbundle.BundleVersion { return b.version }
+
+type testBundleMetadata struct {
+	processed bool
+}
+
+func (m *testBundleMetadata) MarkAsProcessed() { m.processed = true }
+func (m *testBundleMetadata) Processed() bool  { return m.processed }
+
+func newTestConflationManager() *ConflationManager {
+	stats := statistics.NewStatistics(logr.Discard(), &statistics.StatisticsConfig{}, []string{"testBundle"})
+	cm := NewConflationManager(NewConflationReadyQueue(stats), false, stats)
+	cm.Register(NewConflationRegistration(0, bundle.CompleteStateMode, "testBundle", nil))
+	return cm
+}
+
+func TestOffboardLeafHub(t *testing.T) {
+	cm := newTestConflationManager()
+	cm.Insert(&testBundle{leafHubName: "hub1", version: statusbundle.NewBundleVersion(1, 1)}, &testBundleMetadata{})
+
+	conflationUnit := cm.readyQueue.BlockingDequeue()
+	_, metadata, _, err := conflationUnit.GetNext()
+	assert.NoError(t, err)
+
+	offboard := func(timeout time.Duration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return cm.OffboardLeafHub(ctx, "hub1")
+	}
+	// the bundle in process isn't finished
+	assert.Error(t, offboard(300*time.Millisecond))
+
+	// the bundle in process is finished in the meantime
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		conflationUnit.ReportResult(metadata, nil)
+	}()
+	assert.NoError(t, offboard(5*time.Second))
+	assert.Nil(t, cm.getConflationUnit("hub1"))
+	assert.Empty(t, cm.conflationUnits)
+	assert.False(t, conflationUnit.isInProcess())
+
+	// the removed conflation unit provides no bundle anymore
+	cm.readyQueue.Enqueue(conflationUnit)
+	_, _, _, err = cm.readyQueue.BlockingDequeue().GetNext()
+	assert.ErrorIs(t, err, errConflationUnitRemoved)
+}
+
+func TestOnboardLeafHub(t *testing.T) {
+	cm := newTestConflationManager()
+	cm.Insert(&testBundle{leafHubName: "hub1", version: statusbundle.NewBundleVersion(2, 5)}, &testBundleMetadata{})
+
+	conflationUnit := cm.readyQueue.BlockingDequeue()
+	_, metadata, _, err := conflationUnit.GetNext()
+	assert.NoError(t, err)
+	conflationUnit.ReportResult(metadata, nil)
+
+	// the bundle of the rejoined leaf hub is older than the processed one
+	cm.Insert(&testBundle{leafHubName: "hub1", version: statusbundle.NewBundleVersion(0, 1)}, &testBundleMetadata{})
+	assert.True(t, cm.readyQueue.isEmpty())
+
+	// the processed versions are reset once the leaf hub is onboarded again
+	cm.OnboardLeafHub("hub1")
+	cm.Insert(&testBundle{leafHubName: "hub1", version: statusbundle.NewBundleVersion(0, 1)}, &testBundleMetadata{})
+	assert.False(t, cm.readyQueue.isEmpty())
+	assert.NotSame(t, conflationUnit, cm.readyQueue.BlockingDequeue())
+}
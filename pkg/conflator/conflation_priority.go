@@ -14,6 +14,7 @@ const (
 	PlacementPriority                     ConflationPriority = iota
 	PlacementDecisionPriority             ConflationPriority = iota
 	SubscriptionStatusPriority            ConflationPriority = iota
+	DeltaSubscriptionStatusPriority       ConflationPriority = iota
 	SubscriptionReportPriority            ConflationPriority = iota
 	ControlInfoPriority                   ConflationPriority = iota
 	LocalPolicySpecPriority               ConflationPriority = iota
@@ -22,4 +23,9 @@ const (
 	LocalPlacementRulesSpecPriority       ConflationPriority = iota
 	HubClusterInfoStatusPriority          ConflationPriority = iota
 	LocalPolicyStatusEventPriority        ConflationPriority = iota
+	AppliedStatusPriority                 ConflationPriority = iota
+	ArgoCDApplicationPriority             ConflationPriority = iota
+	ArgoCDApplicationSetPriority          ConflationPriority = iota
+	ManagedClusterAddOnsPriority          ConflationPriority = iota
+	PolicyReportPriority                  ConflationPriority = iota
 )
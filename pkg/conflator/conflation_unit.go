@@ -1,10 +1,13 @@
 package conflator
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/helpers"
@@ -15,10 +18,12 @@ import (
 
 const (
 	invalidPriority = -1
+	drainInterval   = 100 * time.Millisecond
 )
 
 var (
 	errNoReadyBundle               = errors.New("no bundle is ready to be processed")
+	errConflationUnitRemoved       = errors.New("conflation unit is removed")
 	errDependencyCannotBeEvaluated = errors.New("bundles declares dependency in registration but doesn't " +
 		"implement DependantBundle interface")
 )
@@ -77,6 +82,7 @@ type ConflationUnit struct {
 	readyQueue                     *ConflationReadyQueue
 	requireInitialDependencyChecks bool
 	isInReadyQueue                 bool
+	isRemoved                      bool
 	lock                           sync.Mutex
 	statistics                     *statistics.Statistics
 }
@@ -119,6 +125,11 @@ func (cu *ConflationUnit) GetNext() (statusbundle.Bundle, *BundleMetadata, Bundl
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
+	if cu.isRemoved {
+		cu.isInReadyQueue = false
+		return nil, nil, nil, errConflationUnitRemoved
+	}
+
 	nextBundleToProcessPriority := cu.getNextReadyBundlePriority()
 	if nextBundleToProcessPriority == invalidPriority { // CU adds itself to RQ only when it has ready to process bundle
 		return nil, nil, nil, errNoReadyBundle // therefore this shouldn't happen
@@ -164,6 +175,23 @@ func (cu *ConflationUnit) ReportResult(metadata *BundleMetadata, err error) {
 	cu.addCUToReadyQueueIfNeeded()
 }
 
+// remove stops the CU from providing bundles, the bundles in process are still reported to it.
+func (cu *ConflationUnit) remove() {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	cu.isRemoved = true
+}
+
+// drain waits until the bundles in process of the removed CU are finished or the context is done.
+func (cu *ConflationUnit) drain(ctx context.Context) error {
+	return wait.PollImmediateUntilWithContext(ctx, drainInterval, func(ctx context.Context) (bool, error) {
+		cu.lock.Lock()
+		defer cu.lock.Unlock()
+		return !cu.isInProcess(), nil
+	})
+}
+
 func (cu *ConflationUnit) isInProcess() bool {
 	for _, conflationElement := range cu.priorityQueue {
 		if conflationElement.isInProcess {
@@ -175,8 +203,8 @@ func (cu *ConflationUnit) isInProcess() bool {
 }
 
 func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
-	if cu.isInReadyQueue || cu.isInProcess() {
-		return // allow CU to appear only once in RQ/processing
+	if cu.isRemoved || cu.isInReadyQueue || cu.isInProcess() {
+		return // allow CU to appear only once in RQ/processing, and the removed CU never appears again
 	}
 	// if we reached here, CU is not in RQ nor during processing
 	nextReadyBundlePriority := cu.getNextReadyBundlePriority()
@@ -9,6 +9,10 @@ const (
 	// GHAgentConfigCMName is the name of configmap that stores important global hub settings
 	// eg. aggregationLevel and enableLocalPolicy.
 	GHAgentConfigCMName = "multicluster-global-hub-agent-config"
+	// GHAgentConfigName is the name of the GlobalHubAgentConfig which holds the typed agent settings, it's created
+	// by the agent from the effective config sent by the manager and takes precedence over the GHAgentConfigCMName
+	// configmap.
+	GHAgentConfigName = "multicluster-global-hub-agent-config"
 	// GHAgentIncarnationCMName is the name of incarnation configmap for global hub agent
 	GHAgentIncarnationCMName = "incarnation-config"
 	// GHAgentIncarnationCMName is the key of incarnation configmap data for global hub agent
@@ -39,6 +43,19 @@ const (
 	ManagedClusterManagedByAnnotation = "global-hub.open-cluster-management.io/managed-by"
 	// identify the resource is from the global hub cluster
 	OriginOwnerReferenceAnnotation = "global-hub.open-cluster-management.io/origin-ownerreference-uid"
+	// the resource is applied on the regional hub with server-side dry-run only, to preview the changes
+	DryRunAnnotation = "global-hub.open-cluster-management.io/dry-run"
+	// the name of the rollout policy the global resource is rolled out to the regional hubs with, in waves
+	RolloutPolicyAnnotation = "global-hub.open-cluster-management.io/rollout-policy"
+	// the user requesting the change of the global resource, it's recorded in the audit log of the changes
+	AuditRequestedByAnnotation = "global-hub.open-cluster-management.io/requested-by"
+	// the user deleting the global resource, it's recorded in the audit log once the cleanup finalizer is removed
+	AuditDeletedByAnnotation = "global-hub.open-cluster-management.io/deleted-by"
+	// the source of the effective agent config, which is either the default config or the name of an override
+	AgentConfigSourceAnnotation = "global-hub.open-cluster-management.io/agent-config-source"
+	// the Applied and Drifted conditions of the global resources without status conditions, aggregated from the
+	// applied status of the regional hubs, it isn't synced to the regional hubs
+	AppliedConditionsAnnotation = "global-hub.open-cluster-management.io/applied-conditions"
 )
 
 // store all the finalizers
@@ -64,6 +81,11 @@ const (
 	HubNotInstalled         = "NotInstalled"
 	HubInstalledByUser      = "InstalledByUser"
 	HubInstalledByGlobalHub = "InstalledByGlobalHub"
+
+	// LocalClusterName is the name of the managed cluster of the hub itself
+	LocalClusterName = "local-cluster"
+	// GHManagedClusterAddonName is the name of the ManagedClusterAddOn of the global hub agent
+	GHManagedClusterAddonName = "multicluster-global-hub-controller"
 )
 
 // message types
@@ -76,11 +98,22 @@ const (
 	// ControlInfoMsgKey - control info message key.
 	ControlInfoMsgKey = "ControlInfo"
 
+	// AgentConfigMsgKey - the effective agent config of a leaf hub message key.
+	AgentConfigMsgKey = "AgentConfig"
+	// GlobalPlacementDecisionsMsgKey - the decisions of the global scheduler on a leaf hub message key.
+	GlobalPlacementDecisionsMsgKey = "GlobalPlacementDecisions"
+	// RolloutsMsgKey - the global resources rolled out to a leaf hub so far message key.
+	RolloutsMsgKey = "Rollouts"
+	// ManagerCapabilitiesMsgKey - the capabilities of the manager message key.
+	ManagerCapabilitiesMsgKey = "ManagerCapabilities"
+
 	// HubClusterInfoMsgKey - hub cluster info message key.
 	HubClusterInfoMsgKey = "HubClusterInfo"
 
 	// ManagedClustersMsgKey - managed clusters message key.
 	ManagedClustersMsgKey = "ManagedClusters"
+	// ManagedClusterAddOnsMsgKey - managed cluster addons status message key.
+	ManagedClusterAddOnsMsgKey = "ManagedClusterAddOns"
 	// ManagedClustersLabelsMsgKey - managed clusters labels message key.
 	ManagedClustersLabelsMsgKey = "ManagedClustersLabels"
 
@@ -106,6 +139,8 @@ const (
 
 	// SubscriptionStatusMsgKey - subscription-status message key.
 	SubscriptionStatusMsgKey = "SubscriptionStatus"
+	// SubscriptionDeltaStatusMsgKey - subscription delta state status message key.
+	SubscriptionDeltaStatusMsgKey = "SubscriptionDeltaStatus"
 	// SubscriptionReportMsgKey - subscription-report message key.
 	SubscriptionReportMsgKey = "SubscriptionReport"
 
@@ -115,6 +150,17 @@ const (
 	PlacementMsgKey = "Placement"
 	// PlacementDecisionMsgKey - placement-decision message key.
 	PlacementDecisionMsgKey = "PlacementDecision"
+
+	// AppliedStatusMsgKey - applied status of the global resources message key.
+	AppliedStatusMsgKey = "AppliedStatus"
+
+	// ArgoCDApplicationMsgKey - argo cd application status message key.
+	ArgoCDApplicationMsgKey = "ArgoCDApplication"
+	// ArgoCDApplicationSetMsgKey - argo cd applicationset status message key.
+	ArgoCDApplicationSetMsgKey = "ArgoCDApplicationSet"
+
+	// PolicyReportMsgKey - gatekeeper and kyverno policy reports message key.
+	PolicyReportMsgKey = "PolicyReport"
 )
 
 // event exporter reference object label keys
@@ -130,4 +176,24 @@ const (
 	PolicyEventRootPolicyNameLabelKey = "policy.open-cluster-management.io/root-policy"
 	// the label is from the reference object itself
 	PolicyEventClusterNameLabelKey = "policy.open-cluster-management.io/cluster-name"
+
+	// the label is added by the event exporter
+	ManagedClusterEventClusterIdLabelKey = "cluster.open-cluster-management.io/cluster-id"
+	// the label is added by the event exporter, the id of the resource on the global hub
+	EventGlobalResourceIdLabelKey = "global-hub.open-cluster-management.io/global-resource-id"
+
+	// the reason of the event of the managed cluster when the values set on the regional hub are overwritten by the
+	// global hub, the event is recorded in the audit log of the global hub
+	ManagedClusterMetadataConflictReason = "GlobalHubMetadataConflict"
+	// the annotation of the metadata conflict event, the overwritten values in json
+	ManagedClusterMetadataConflictsAnnotation = "global-hub.open-cluster-management.io/metadata-conflicts"
+)
+
+// the kinds of the event involved objects
+const (
+	ManagedClusterKind    = "ManagedCluster"
+	PlacementKind         = "Placement"
+	PlacementDecisionKind = "PlacementDecision"
+	SubscriptionKind      = "Subscription"
+	ApplicationKind       = "Application"
 )
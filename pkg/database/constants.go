@@ -16,6 +16,8 @@ const (
 const (
 	// ManagedClustersTableName table name of managed clusters.
 	ManagedClustersTableName = "managed_clusters"
+	// ManagedClusterAddOnsTableName table name of the addon status of managed clusters.
+	ManagedClusterAddOnsTableName = "managed_cluster_addons"
 
 	// ComplianceTableName table name of policy compliance status.
 	ComplianceTableName = "compliance"
@@ -38,10 +40,27 @@ const (
 
 	// LeafHubHeartbeatsTableName table name for LH heartbeats.
 	LeafHubHeartbeatsTableName = "leaf_hub_heartbeats"
+	// LeafHubCapabilitiesTableName table name of the capabilities advertised by the agents of the leaf hubs.
+	LeafHubCapabilitiesTableName = "leaf_hub_capabilities"
+	// LeafHubLifecyclesTableName table name of the onboarding and offboarding state of the leaf hubs.
+	LeafHubLifecyclesTableName = "leaf_hub_lifecycles"
 
 	// HubClusterInfo table name of leaf_hubs.
 	HubClusterInfoTableName = "leaf_hubs"
 
+	// AppliedStatusesTableName table name of the applied status of global resources on leaf hubs.
+	AppliedStatusesTableName = "applied_statuses"
+
+	// ArgoCDApplicationsTableName table name of the argo cd application status on leaf hubs.
+	ArgoCDApplicationsTableName = "argocd_applications"
+	// ArgoCDApplicationSetsTableName table name of the argo cd applicationset status on leaf hubs.
+	ArgoCDApplicationSetsTableName = "argocd_applicationsets"
+
+	// PolicyReportsTableName table name of the gatekeeper and kyverno policy reports on leaf hubs.
+	PolicyReportsTableName = "policy_reports"
+	// PolicyViolationsTableName table name of the normalized violations of the policy reports.
+	PolicyViolationsTableName = "policy_violations"
+
 	// PolicyEvent table name of leaf_hubs.
 	LocalPolicyEventTableName     = "local_policies"
 	LocalRootPolicyEventTableName = "local_root_policies"
@@ -66,6 +85,71 @@ const (
 	Unknown ComplianceStatus = "unknown"
 )
 
+// LeafHubLifecycleState represents the lifecycle state of a leaf hub in the global hub database.
+type LeafHubLifecycleState string
+
+// leaf hub lifecycle states.
+const (
+	// LeafHubOnboarded the leaf hub is attached, its status bundles are processed.
+	LeafHubOnboarded LeafHubLifecycleState = "onboarded"
+	// LeafHubOffboarding the leaf hub is detached or decommissioned, its data is waiting to be cleaned up.
+	LeafHubOffboarding LeafHubLifecycleState = "offboarding"
+	// LeafHubOffboarded the data of the leaf hub is cleaned up, its status bundles are discarded.
+	LeafHubOffboarded LeafHubLifecycleState = "offboarded"
+)
+
+// leaf hub offboarding reasons.
+const (
+	// OffboardingReasonClusterDeleted the managed cluster of the leaf hub is removed from the global hub.
+	OffboardingReasonClusterDeleted = "ManagedClusterDeleted"
+	// OffboardingReasonAddonDeleted the global hub addon is removed from the leaf hub.
+	OffboardingReasonAddonDeleted = "AddonDeleted"
+	// OffboardingReasonDecommissioned the leaf hub is decommissioned through the API.
+	OffboardingReasonDecommissioned = "Decommissioned"
+)
+
+// retention policies of the data of the offboarded leaf hubs.
+const (
+	// RetentionArchive moves the rows of the leaf hub into history.leaf_hub_archives.
+	RetentionArchive = "archive"
+	// RetentionDelete deletes the rows of the leaf hub.
+	RetentionDelete = "delete"
+)
+
+// audit log sources, which are the ways the global hub changes the leaf hubs.
+const (
+	// AuditSourceAPI the change is requested through the global hub API.
+	AuditSourceAPI = "api"
+	// AuditSourceController the change is synced from a global resource on the global hub cluster.
+	AuditSourceController = "controller"
+	// AuditSourceScheduler the change is made by the global scheduler of the global placements.
+	AuditSourceScheduler = "scheduler"
+	// AuditSourceRollout the change is made by the rollout of the global resources in waves.
+	AuditSourceRollout = "rollout"
+	// AuditSourceAgent the change is made by the global hub agent on the leaf hub.
+	AuditSourceAgent = "agent"
+)
+
+// audit log operations.
+const (
+	// AuditOperationCreate a global resource is created and sent to the leaf hubs.
+	AuditOperationCreate = "create"
+	// AuditOperationUpdate a global resource is updated and sent to the leaf hubs.
+	AuditOperationUpdate = "update"
+	// AuditOperationDelete a global resource is deleted by the cleanup finalizer and removed from the leaf hubs.
+	AuditOperationDelete = "delete"
+	// AuditOperationSchedule the decisions of the global placements on a leaf hub are changed by the scheduler.
+	AuditOperationSchedule = "schedule"
+	// AuditOperationRollout the global resources rolled out to a leaf hub are changed by a rollout wave.
+	AuditOperationRollout = "rollout"
+	// AuditOperationPatchLabels the labels of a managed cluster are patched through the API.
+	AuditOperationPatchLabels = "patch-labels"
+	// AuditOperationOverwrite the labels of a managed cluster set on the leaf hub are overwritten by the global hub.
+	AuditOperationOverwrite = "overwrite"
+	// AuditOperationDecommission a leaf hub is decommissioned through the API.
+	AuditOperationDecommission = "decommission"
+)
+
 // unique db types.
 const (
 	// UUID unique type.
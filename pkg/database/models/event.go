@@ -34,3 +34,51 @@ type LocalRootPolicyEvent struct {
 func (LocalRootPolicyEvent) TableName() string {
 	return "event.local_root_policies"
 }
+
+type ManagedClusterEvent struct {
+	EventName   string         `gorm:"column:event_name;type:varchar(253);not null" json:"eventName"`
+	ClusterID   *string        `gorm:"column:cluster_id;type:uuid" json:"clusterId,omitempty"`
+	ClusterName string         `gorm:"column:cluster_name;type:varchar(256);not null" json:"clusterName"`
+	LeafHubName string         `gorm:"size:63;not null" json:"-"`
+	Message     string         `gorm:"column:message;type:text" json:"message"`
+	Reason      string         `gorm:"column:reason;type:text" json:"reason"`
+	Count       int            `gorm:"column:count;type:integer;not null;default:0" json:"count"`
+	Source      datatypes.JSON `gorm:"column:source;type:jsonb" json:"source"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+func (ManagedClusterEvent) TableName() string {
+	return "event.managed_clusters"
+}
+
+// BaseResourceEvent is the event of a resource which might be propagated from the global hub,
+// the ResourceID is the id of the resource on the global hub.
+type BaseResourceEvent struct {
+	EventName   string         `gorm:"column:event_name;type:varchar(253);not null" json:"eventName"`
+	ResourceID  *string        `gorm:"column:resource_id;type:uuid" json:"resourceId,omitempty"`
+	Kind        string         `gorm:"column:kind;type:varchar(63);not null" json:"kind"`
+	Name        string         `gorm:"column:name;type:varchar(253);not null" json:"name"`
+	Namespace   string         `gorm:"column:namespace;type:varchar(63)" json:"namespace"`
+	LeafHubName string         `gorm:"size:63;not null" json:"-"`
+	Message     string         `gorm:"column:message;type:text" json:"message"`
+	Reason      string         `gorm:"column:reason;type:text" json:"reason"`
+	Count       int            `gorm:"column:count;type:integer;not null;default:0" json:"count"`
+	Source      datatypes.JSON `gorm:"column:source;type:jsonb" json:"source"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:now();not null" json:"createdAt"`
+}
+
+type PlacementEvent struct {
+	BaseResourceEvent
+}
+
+func (PlacementEvent) TableName() string {
+	return "event.placements"
+}
+
+type ApplicationEvent struct {
+	BaseResourceEvent
+}
+
+func (ApplicationEvent) TableName() string {
+	return "event.applications"
+}
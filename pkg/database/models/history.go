@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type LeafHubLifecycleAction struct {
+	LeafHubName  string         `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Action       string         `gorm:"column:action;not null" json:"action"`
+	Reason       string         `gorm:"column:reason" json:"reason,omitempty"`
+	Retention    string         `gorm:"column:retention" json:"retention,omitempty"`
+	AffectedRows datatypes.JSON `gorm:"column:affected_rows;type:jsonb" json:"affectedRows,omitempty"`
+	CreatedAt    time.Time      `gorm:"column:created_at;default:(-)" json:"createdAt"`
+}
+
+func (LeafHubLifecycleAction) TableName() string {
+	return "history.leaf_hub_lifecycle_actions"
+}
+
+type AuditLog struct {
+	ID          int64          `gorm:"column:id;primaryKey;default:(-)" json:"id"`
+	UserName    string         `gorm:"column:user_name;not null" json:"userName"`
+	Source      string         `gorm:"column:source;not null" json:"source"`
+	Operation   string         `gorm:"column:operation;not null" json:"operation"`
+	Kind        string         `gorm:"column:kind;not null" json:"kind"`
+	Name        string         `gorm:"column:name;not null" json:"name"`
+	Namespace   string         `gorm:"column:namespace" json:"namespace,omitempty"`
+	ResourceID  string         `gorm:"column:resource_id" json:"resourceId,omitempty"`
+	TargetHubs  datatypes.JSON `gorm:"column:target_hubs;type:jsonb" json:"targetHubs"`
+	SpecVersion string         `gorm:"column:spec_version" json:"specVersion,omitempty"`
+	Details     datatypes.JSON `gorm:"column:details;type:jsonb" json:"details,omitempty"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:(-)" json:"createdAt"`
+}
+
+func (AuditLog) TableName() string {
+	return "history.audit_log"
+}
@@ -22,6 +22,46 @@ func (ManagedCluster) TableName() string {
 	return "status.managed_clusters"
 }
 
+type LeafHubCapability struct {
+	LeafHubName   string         `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	SchemaVersion int            `gorm:"column:schema_version;not null" json:"schemaVersion"`
+	Payload       datatypes.JSON `gorm:"column:payload;type:jsonb;not null" json:"payload"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (LeafHubCapability) TableName() string {
+	return "status.leaf_hub_capabilities"
+}
+
+type LeafHubLifecycle struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey" json:"leafHubName"`
+	State       string    `gorm:"column:state;not null" json:"state"`
+	Reason      string    `gorm:"column:reason" json:"reason,omitempty"`
+	Retention   string    `gorm:"column:retention" json:"retention,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:(-)" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (LeafHubLifecycle) TableName() string {
+	return "status.leaf_hub_lifecycles"
+}
+
+type ManagedClusterAddOn struct {
+	ClusterID   string    `gorm:"column:cluster_id;type:uuid;not null" json:"clusterId"`
+	LeafHubName string    `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	ClusterName string    `gorm:"column:cluster_name;not null" json:"clusterName"`
+	AddonName   string    `gorm:"column:addon_name;not null" json:"addonName"`
+	Available   string    `gorm:"column:available;not null" json:"available"`
+	Degraded    string    `gorm:"column:degraded;not null" json:"degraded"`
+	Progressing string    `gorm:"column:progressing;not null" json:"progressing"`
+	Message     string    `gorm:"column:message" json:"message,omitempty"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (ManagedClusterAddOn) TableName() string {
+	return "status.managed_cluster_addons"
+}
+
 type LeafHub struct {
 	LeafHubName string         `gorm:"column:leaf_hub_name;not null"`
 	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
@@ -34,3 +74,102 @@ type LeafHub struct {
 func (LeafHub) TableName() string {
 	return "status.leaf_hubs"
 }
+
+type AppliedStatus struct {
+	ResourceID    string    `gorm:"column:resource_id;type:uuid;not null" json:"resourceId"`
+	LeafHubName   string    `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Kind          string    `gorm:"column:kind;not null" json:"kind"`
+	Name          string    `gorm:"column:name;not null" json:"name"`
+	Namespace     string    `gorm:"column:namespace" json:"namespace,omitempty"`
+	BundleVersion string    `gorm:"column:bundle_version;not null" json:"bundleVersion"`
+	Applied       bool      `gorm:"column:applied;not null" json:"applied"`
+	Error         string    `gorm:"column:error" json:"error,omitempty"`
+	DryRun        bool      `gorm:"column:dry_run;not null" json:"dryRun"`
+	Drifted       bool      `gorm:"column:drifted;not null" json:"drifted"`
+	Diff          string    `gorm:"column:diff" json:"diff,omitempty"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (AppliedStatus) TableName() string {
+	return "status.applied_statuses"
+}
+
+type ArgoCDApplication struct {
+	ID                   string    `gorm:"column:id;type:uuid;not null" json:"id"`
+	LeafHubName          string    `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Name                 string    `gorm:"column:name;not null" json:"name"`
+	Namespace            string    `gorm:"column:namespace;not null" json:"namespace"`
+	Project              string    `gorm:"column:project" json:"project,omitempty"`
+	RepoURL              string    `gorm:"column:repo_url" json:"repoURL,omitempty"`
+	TargetRevision       string    `gorm:"column:target_revision" json:"targetRevision,omitempty"`
+	Revision             string    `gorm:"column:revision" json:"revision,omitempty"`
+	DestinationServer    string    `gorm:"column:destination_server" json:"destinationServer,omitempty"`
+	DestinationName      string    `gorm:"column:destination_name" json:"destinationName,omitempty"`
+	DestinationNamespace string    `gorm:"column:destination_namespace" json:"destinationNamespace,omitempty"`
+	SyncStatus           string    `gorm:"column:sync_status" json:"syncStatus,omitempty"`
+	HealthStatus         string    `gorm:"column:health_status" json:"healthStatus,omitempty"`
+	HealthMessage        string    `gorm:"column:health_message" json:"healthMessage,omitempty"`
+	OperationPhase       string    `gorm:"column:operation_phase" json:"operationPhase,omitempty"`
+	ReconciledAt         string    `gorm:"column:reconciled_at" json:"reconciledAt,omitempty"`
+	UpdatedAt            time.Time `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (ArgoCDApplication) TableName() string {
+	return "status.argocd_applications"
+}
+
+type ArgoCDApplicationSet struct {
+	ID                string         `gorm:"column:id;type:uuid;not null" json:"id"`
+	LeafHubName       string         `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Name              string         `gorm:"column:name;not null" json:"name"`
+	Namespace         string         `gorm:"column:namespace;not null" json:"namespace"`
+	Applications      datatypes.JSON `gorm:"column:applications;type:jsonb;not null" json:"applications"`
+	ResourcesUpToDate bool           `gorm:"column:resources_up_to_date;not null" json:"resourcesUpToDate"`
+	ErrorMessage      string         `gorm:"column:error_message" json:"errorMessage,omitempty"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (ArgoCDApplicationSet) TableName() string {
+	return "status.argocd_applicationsets"
+}
+
+type PolicyReport struct {
+	ID          string    `gorm:"column:id;type:uuid;not null" json:"id"`
+	LeafHubName string    `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Engine      string    `gorm:"column:engine;not null" json:"engine"`
+	Kind        string    `gorm:"column:kind;not null" json:"kind"`
+	Name        string    `gorm:"column:name;not null" json:"name"`
+	Namespace   string    `gorm:"column:namespace" json:"namespace,omitempty"`
+	ClusterName string    `gorm:"column:cluster_name" json:"clusterName,omitempty"`
+	Pass        int64     `gorm:"column:pass;not null" json:"pass"`
+	Fail        int64     `gorm:"column:fail;not null" json:"fail"`
+	Warn        int64     `gorm:"column:warn;not null" json:"warn"`
+	Error       int64     `gorm:"column:error;not null" json:"error"`
+	Skip        int64     `gorm:"column:skip;not null" json:"skip"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;default:(-)" json:"updatedAt"`
+}
+
+func (PolicyReport) TableName() string {
+	return "status.policy_reports"
+}
+
+type PolicyViolation struct {
+	ReportID          string    `gorm:"column:report_id;type:uuid;not null" json:"reportId"`
+	LeafHubName       string    `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	Engine            string    `gorm:"column:engine;not null" json:"engine"`
+	ClusterName       string    `gorm:"column:cluster_name" json:"clusterName,omitempty"`
+	PolicyName        string    `gorm:"column:policy_name;not null" json:"policyName"`
+	RuleName          string    `gorm:"column:rule_name" json:"ruleName,omitempty"`
+	Severity          string    `gorm:"column:severity" json:"severity,omitempty"`
+	Category          string    `gorm:"column:category" json:"category,omitempty"`
+	Result            string    `gorm:"column:result;not null" json:"result"`
+	Message           string    `gorm:"column:message" json:"message,omitempty"`
+	ResourceKind      string    `gorm:"column:resource_kind" json:"resourceKind,omitempty"`
+	ResourceName      string    `gorm:"column:resource_name" json:"resourceName,omitempty"`
+	ResourceNamespace string    `gorm:"column:resource_namespace" json:"resourceNamespace,omitempty"`
+	CreatedAt         time.Time `gorm:"column:created_at;default:(-)" json:"createdAt"`
+}
+
+func (PolicyViolation) TableName() string {
+	return "status.policy_violations"
+}
@@ -79,7 +79,7 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
 			transportMessage := &transport.Message{}
-			if err := event.DataAs(transportMessage); err != nil {
+			if err := transport.UnmarshalMessage(event.Data(), transportMessage); err != nil {
 				c.log.Error(err, "get transport message error", "event.ID", event.ID())
 				return ceprotocol.ResultNACK
 			}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/registration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/status"
 	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/conflator"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -186,7 +187,7 @@ func (c *KafkaConsumer) processMessageWithConflation(message *kafka.Message) {
 	}
 
 	transportMessage := &transport.Message{}
-	if err := json.Unmarshal(decompressedPayload, transportMessage); err != nil {
+	if err := transport.UnmarshalMessage(decompressedPayload, transportMessage); err != nil {
 		c.logError(err, "failed to parse transport message", message)
 		return
 	}
@@ -196,29 +197,40 @@ func (c *KafkaConsumer) processMessageWithConflation(message *kafka.Message) {
 	if len(msgIDTokens) != 2 {
 		c.logError(errors.New("message ID format is bad"),
 			"expecting MessageID of format LH_ID.MSG_ID", message)
+		registration.RecordRejectedBundle("", "", registration.RejectReasonBadMessageID)
 		return
 	}
 
-	msgID := msgIDTokens[1]
-	if _, found := c.messageIDToRegistrationMap[msgID]; !found {
+	leafHubName, msgID := msgIDTokens[0], msgIDTokens[1]
+	bundleRegistration, found := c.messageIDToRegistrationMap[msgID]
+	if !found {
 		c.log.Info("no bundle-registration available, not sending bundle", "messageId", transportMessage.ID,
 			"messageType", transportMessage.MsgType, "version", transportMessage.Version)
 		// no one registered for this msg id
+		registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonUnregistered)
 		return
 	}
 
-	if !c.messageIDToRegistrationMap[msgID].Predicate() {
+	if !bundleRegistration.Predicate() {
 		c.log.Info("predicate is false, not sending bundle", "messageId", transportMessage.ID,
 			"messageType", transportMessage.MsgType, "version", transportMessage.Version)
 
 		return // bundle-registration predicate is false, do not send the update in the channel
 	}
 
-	receivedBundle := c.messageIDToRegistrationMap[msgID].CreateBundleFunc()
-	if err := json.Unmarshal(transportMessage.Payload, receivedBundle); err != nil {
+	schemaVersion := status.ResolveSchemaVersion(transportMessage.SchemaVersion)
+	receivedBundle, err := bundleRegistration.Decode(schemaVersion, transportMessage.ContentType,
+		transportMessage.Payload)
+	if errors.Is(err, registration.ErrUnsupportedSchemaVersion) {
+		c.logError(err, "reject the bundle of the unsupported schema version", message)
+		registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonUnsupportedSchemaVersion)
+		return
+	} else if err != nil {
 		c.logError(err, parseFail, message)
+		registration.RecordRejectedBundle(leafHubName, msgID, registration.RejectReasonDecodeError)
 		return
 	}
+	registration.RecordReceivedBundle(msgID, schemaVersion)
 
 	c.statistics.IncrementNumberOfReceivedBundles(receivedBundle)
 
@@ -247,7 +259,7 @@ func (c *KafkaConsumer) processMessage(message *kafka.Message) {
 	}
 
 	transportMessage := &transport.Message{}
-	if err := json.Unmarshal(decompressedPayload, transportMessage); err != nil {
+	if err := transport.UnmarshalMessage(decompressedPayload, transportMessage); err != nil {
 		c.logError(err, "failed to parse transport message", message)
 		return
 	}
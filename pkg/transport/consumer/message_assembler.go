@@ -4,7 +4,6 @@
 package consumer
 
 import (
-	"encoding/json"
 	"sync"
 	"time"
 
@@ -110,7 +109,7 @@ func (assembler *messageAssembler) assemble(chunk *messageChunk) *transport.Mess
 			"collection.size", chunkCollection.totalSize)
 
 		transportMessage := &transport.Message{}
-		if err := json.Unmarshal(transportMessageBytes, transportMessage); err != nil {
+		if err := transport.UnmarshalMessage(transportMessageBytes, transportMessage); err != nil {
 			assembler.log.Error(err, "unmarshal collection bytes to transport.Message error")
 			return nil
 		}
@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+)
+
+// binaryMessageMagic is the first byte of the message whose payload isn't json, it's never the first byte of a json
+// encoded message.
+const binaryMessageMagic byte = 0
+
+// MarshalMessage encodes the message as json. the payload of the binary content types, e.g. protobuf, isn't embedded
+// into the json as base64, the message is encoded as the magic byte, the length of the json encoded message without
+// the payload and the json, followed by the raw payload.
+func MarshalMessage(msg *Message) ([]byte, error) {
+	if msg.ContentType == "" || msg.ContentType == encoding.ContentTypeJSON {
+		return json.Marshal(msg)
+	}
+
+	header := *msg
+	header.Payload = nil
+	headerBytes, err := json.Marshal(&header)
+	if err != nil {
+		return nil, err
+	}
+
+	messageBytes := make([]byte, 0, 1+binary.MaxVarintLen64+len(headerBytes)+len(msg.Payload))
+	messageBytes = append(messageBytes, binaryMessageMagic)
+	messageBytes = binary.AppendUvarint(messageBytes, uint64(len(headerBytes)))
+	messageBytes = append(messageBytes, headerBytes...)
+	return append(messageBytes, msg.Payload...), nil
+}
+
+// UnmarshalMessage decodes the message encoded by MarshalMessage.
+func UnmarshalMessage(messageBytes []byte, msg *Message) error {
+	if len(messageBytes) == 0 || messageBytes[0] != binaryMessageMagic {
+		return json.Unmarshal(messageBytes, msg)
+	}
+
+	headerSize, n := binary.Uvarint(messageBytes[1:])
+	if n <= 0 || uint64(len(messageBytes)-1-n) < headerSize {
+		return errors.New("the binary message is truncated")
+	}
+	headerEnd := 1 + n + int(headerSize)
+	if err := json.Unmarshal(messageBytes[1+n:headerEnd], msg); err != nil {
+		return fmt.Errorf("failed to decode the header of the binary message - %w", err)
+	}
+	msg.Payload = messageBytes[headerEnd:]
+	return nil
+}
@@ -0,0 +1,39 @@
+package transport
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/encoding"
+)
+
+func TestMarshalMessage(t *testing.T) {
+	jsonMessage := &Message{ID: "hub1.ManagedClusters", Version: "1.2", Payload: []byte(`{"objects":[]}`)}
+	messageBytes, err := MarshalMessage(jsonMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('{'), messageBytes[0])
+	decoded := &Message{}
+	assert.NoError(t, UnmarshalMessage(messageBytes, decoded))
+	assert.Equal(t, jsonMessage, decoded)
+
+	// the protobuf payload is appended as it is instead of being embedded as base64
+	protoPayload := []byte{0x0a, 0x04, 'h', 'u', 'b', '1', 0x12, 0x00}
+	protoMessage := &Message{
+		ID:            "hub1.ManagedClusters",
+		Version:       "1.2",
+		SchemaVersion: 2,
+		ContentType:   encoding.ContentTypeProtobuf,
+		Payload:       protoPayload,
+	}
+	messageBytes, err = MarshalMessage(protoMessage)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(messageBytes, protoPayload))
+	decoded = &Message{}
+	assert.NoError(t, UnmarshalMessage(messageBytes, decoded))
+	assert.Equal(t, protoMessage, decoded)
+
+	// the truncated header is rejected
+	assert.Error(t, UnmarshalMessage(messageBytes[:5], &Message{}))
+}
@@ -5,7 +5,6 @@ package producer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -81,19 +80,23 @@ func (p *GenericProducer) Send(ctx context.Context, msg *transport.Message) erro
 	event.SetID(msg.ID)
 	event.SetType(msg.MsgType)
 	event.SetTime(time.Now())
-	messageBytes, err := json.Marshal(msg)
+	messageBytes, err := transport.MarshalMessage(msg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message to bytes: %s", messageBytes)
+		return transport.NewNonRetriableError(fmt.Errorf("failed to marshal message to bytes: %w", err))
 	}
 
+	dataContentType := cloudevents.ApplicationJSON
+	if msg.ContentType != "" && msg.ContentType != cloudevents.ApplicationJSON {
+		dataContentType = "application/octet-stream"
+	}
 	chunks := p.splitPayloadIntoChunks(messageBytes)
 	for index, chunk := range chunks {
 		event.SetExtension(transport.Size, len(messageBytes))
 		event.SetExtension(transport.Offset, index*p.messageSizeLimit)
-		if err := event.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
-			return fmt.Errorf("failed to set cloudevents data: %v", msg)
+		if err := event.SetData(dataContentType, chunk); err != nil {
+			return transport.NewNonRetriableError(fmt.Errorf("failed to set cloudevents data: %w", err))
 		}
-		if result := p.client.Send(kafka_sarama.WithMessageKey(ctx, sarama.StringEncoder(event.ID())),
+		if result := p.client.Send(kafka_sarama.WithMessageKey(ctx, sarama.StringEncoder(MessageKey(msg))),
 			event); cloudevents.IsUndelivered(result) {
 			return fmt.Errorf("failed to send generic message to transport: %s", result.Error())
 		}
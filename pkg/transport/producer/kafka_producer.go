@@ -3,7 +3,7 @@ package producer
 import (
 	"context"
 	"encoding/binary"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -97,14 +97,19 @@ func (p *KafkaProducer) deliveryReportHandler(ctx context.Context) {
 
 // handleDeliveryReport handles results of sent messages.
 func (p *KafkaProducer) handleDeliveryReport(kafkaMessage *kafka.Message) {
+	// the callbacks are subscribed by the message id, which differs from the kafka key of the keyed messages
+	messageID := string(kafkaMessage.Key)
+	if id, ok := kafkaMessage.Opaque.(string); ok {
+		messageID = id
+	}
 	if kafkaMessage.TopicPartition.Error != nil {
 		p.log.Error(kafkaMessage.TopicPartition.Error, "failed to deliver message",
-			"MessageId", string(kafkaMessage.Key), "TopicPartition", kafkaMessage.TopicPartition)
-		InvokeCallback(p.eventSubscriptionMap, string(kafkaMessage.Key), DeliveryFailure)
+			"MessageId", messageID, "TopicPartition", kafkaMessage.TopicPartition)
+		InvokeCallback(p.eventSubscriptionMap, messageID, DeliveryFailure)
 		return
 	}
 
-	InvokeCallback(p.eventSubscriptionMap, string(kafkaMessage.Key), DeliverySuccess)
+	InvokeCallback(p.eventSubscriptionMap, messageID, DeliverySuccess)
 }
 
 // Subscribe adds a callback to be delegated when a given event occurs for a message with the given ID.
@@ -125,7 +130,7 @@ func (p *KafkaProducer) Send(ctx context.Context, msg *transport.Message) error
 
 // SendAsync sends a message to the transport asynchronously.
 func (p *KafkaProducer) SendAsync(msg *transport.Message) {
-	msgBytes, err := json.Marshal(msg)
+	msgKey, messageHeaders, compressedBytes, err := p.encode(msg)
 	if err != nil {
 		p.log.Error(err, "failed to send message", "MessageId", msg.ID, "MessageType", msg.MsgType,
 			"Version", msg.Version)
@@ -133,21 +138,91 @@ func (p *KafkaProducer) SendAsync(msg *transport.Message) {
 		return
 	}
 
-	compressedBytes, err := p.compressor.Compress(msgBytes)
-	if err != nil {
-		p.log.Error(err, "failed to compress bundle", "CompressorType", p.compressor.GetType(),
-			"MessageId", msg.ID, "MessageType", msg.MsgType, "Version", msg.Version)
+	if err = p.produceAsync(msgKey, msg.ID, p.topic, partition, messageHeaders, compressedBytes); err != nil {
+		p.log.Error(err, "failed to send message", "MessageKey", msg.Key, "MessageId", msg.ID,
+			"MessageType", msg.MsgType, "Version", msg.Version)
+		InvokeCallback(p.eventSubscriptionMap, string(msg.ID), DeliveryFailure)
 
 		return
 	}
+	InvokeCallback(p.eventSubscriptionMap, string(msg.ID), DeliveryAttempt)
+	p.log.Info("Message sent successfully", "MessageId", msg.ID, "MessageType", msg.MsgType, "Version", msg.Version)
+}
+
+// SendSync sends a message to the transport and waits for the delivery reports of all its fragments, so the caller,
+// e.g. the outbox of the agent, keeps the message until it's delivered. the callbacks are invoked as SendAsync does.
+func (p *KafkaProducer) SendSync(ctx context.Context, msg *transport.Message) error {
+	msgKey, messageHeaders, compressedBytes, err := p.encode(msg)
+	if err != nil {
+		return transport.NewNonRetriableError(err)
+	}
+
+	topic := p.topic
+	messageFragments := p.getMessageFragments(msgKey, &topic, partition, messageHeaders, compressedBytes)
+	// the channel is buffered for all the fragments, the late reports don't block once the caller gives up
+	deliveryChan := make(chan kafka.Event, len(messageFragments))
+	for _, message := range messageFragments {
+		message.Opaque = msg.ID
+		if err := p.producer.Produce(message, deliveryChan); err != nil {
+			InvokeCallback(p.eventSubscriptionMap, msg.ID, DeliveryFailure)
+			return classifyKafkaError(fmt.Errorf("failed to produce message - %w", err))
+		}
+	}
+	InvokeCallback(p.eventSubscriptionMap, msg.ID, DeliveryAttempt)
+
+	for range messageFragments {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-deliveryChan:
+			if kafkaMessage, ok := event.(*kafka.Message); ok && kafkaMessage.TopicPartition.Error != nil {
+				InvokeCallback(p.eventSubscriptionMap, msg.ID, DeliveryFailure)
+				return classifyKafkaError(fmt.Errorf("failed to deliver message - %w",
+					kafkaMessage.TopicPartition.Error))
+			}
+		}
+	}
+	InvokeCallback(p.eventSubscriptionMap, msg.ID, DeliverySuccess)
+	p.log.Info("Message delivered successfully", "MessageId", msg.ID, "MessageType", msg.MsgType,
+		"Version", msg.Version)
+
+	return nil
+}
+
+// classifyKafkaError marks the error of the message rejected by kafka as non-retriable, e.g. the invalid or too large
+// message, the other errors, e.g. the brokers are unavailable, are retriable.
+func classifyKafkaError(err error) error {
+	var kafkaErr kafka.Error
+	if !errors.As(err, &kafkaErr) {
+		return err
+	}
+	switch kafkaErr.Code() {
+	case kafka.ErrInvalidArg, kafka.ErrInvalidMsg, kafka.ErrInvalidMsgSize, kafka.ErrMsgSizeTooLarge,
+		kafka.ErrRecordListTooLarge:
+		return transport.NewNonRetriableError(err)
+	}
+	return err
+}
+
+// encode returns the kafka key, the headers and the compressed payload of the message.
+func (p *KafkaProducer) encode(msg *transport.Message) (string, []kafka.Header, []byte, error) {
+	msgBytes, err := transport.MarshalMessage(msg)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to marshal message - %w", err)
+	}
+
+	compressedBytes, err := p.compressor.Compress(msgBytes)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to compress message with %s - %w", p.compressor.GetType(), err)
+	}
 
 	messageHeaders := []kafka.Header{
 		{Key: transport.CompressionType, Value: []byte(p.compressor.GetType())},
 	}
 
-	msgKey := msg.ID
+	msgKey := MessageKey(msg)
 	if msg.Destination != transport.Broadcast { // set destination if specified
-		msgKey = fmt.Sprintf("%s.%s", msg.Destination, msg.ID)
+		msgKey = fmt.Sprintf("%s.%s", msg.Destination, msgKey)
 
 		messageHeaders = append(messageHeaders, kafka.Header{
 			Key:   transport.DestinationHub,
@@ -155,15 +230,7 @@ func (p *KafkaProducer) SendAsync(msg *transport.Message) {
 		})
 	}
 
-	if err = p.produceAsync(msgKey, p.topic, partition, messageHeaders, compressedBytes); err != nil {
-		p.log.Error(err, "failed to send message", "MessageKey", msg.Key, "MessageId", msg.ID,
-			"MessageType", msg.MsgType, "Version", msg.Version)
-		InvokeCallback(p.eventSubscriptionMap, string(msg.ID), DeliveryFailure)
-
-		return
-	}
-	InvokeCallback(p.eventSubscriptionMap, string(msg.ID), DeliveryAttempt)
-	p.log.Info("Message sent successfully", "MessageId", msg.ID, "MessageType", msg.MsgType, "Version", msg.Version)
+	return msgKey, messageHeaders, compressedBytes, nil
 }
 
 // Close closes the KafkaProducer.
@@ -177,13 +244,15 @@ func (p *KafkaProducer) Producer() *kafka.Producer {
 	return p.producer
 }
 
-// ProduceAsync sends a message to the kafka brokers asynchronously.
-func (p *KafkaProducer) produceAsync(key string, topic string, partition int32, headers []kafka.Header,
+// ProduceAsync sends a message to the kafka brokers asynchronously, the message id is kept as the opaque of the kafka
+// messages to invoke the callbacks of the delivery reports.
+func (p *KafkaProducer) produceAsync(key, messageID string, topic string, partition int32, headers []kafka.Header,
 	payload []byte,
 ) error {
 	messageFragments := p.getMessageFragments(key, &topic, partition, headers, payload)
 
 	for _, message := range messageFragments {
+		message.Opaque = messageID
 		if err := p.producer.Produce(message, p.deliveryChan); err != nil {
 			return fmt.Errorf("failed to produce message - %w", err)
 		}
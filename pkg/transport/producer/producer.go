@@ -48,3 +48,13 @@ func InvokeCallback(eventSubscriptionMap map[string]map[EventType]EventCallback,
 		callback()
 	}
 }
+
+// MessageKey returns the transport key of the message, it's the message id unless the message sets its own key, e.g.
+// the delta bundles are keyed apart from the complete bundles, so that the compaction of the topic never replaces the
+// complete bundle with a delta bundle.
+func MessageKey(message *transport.Message) string {
+	if message.Key != "" {
+		return message.Key
+	}
+	return message.ID
+}
@@ -5,6 +5,7 @@ package transport
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -38,6 +39,31 @@ type Producer interface {
 	Send(ctx context.Context, msg *Message) error
 }
 
+// NonRetriableError is the error of sending a message which fails again however many times it's retried, e.g. the
+// message can't be encoded or it's rejected by the transport as too large.
+type NonRetriableError struct {
+	Err error
+}
+
+// NewNonRetriableError marks the error of sending a message as non-retriable.
+func NewNonRetriableError(err error) error {
+	return &NonRetriableError{Err: err}
+}
+
+func (e *NonRetriableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NonRetriableError) Unwrap() error {
+	return e.Err
+}
+
+// IsNonRetriable returns true if the error of sending a message is non-retriable.
+func IsNonRetriable(err error) bool {
+	var nonRetriableErr *NonRetriableError
+	return errors.As(err, &nonRetriableErr)
+}
+
 type Consumer interface {
 	// start the transport to consume message
 	Start(ctx context.Context) error
@@ -45,14 +71,27 @@ type Consumer interface {
 	MessageChan() chan *Message
 }
 
-// Message abstracts a message object to be used by different transport components.
+// Message abstracts a message object to be used by different transport components. the version is the generation of
+// the bundle, and the schema version is the version of the payload schema, it's empty for the legacy agents. the
+// content type is the encoding of the payload, the payload without the content type is encoded as json. the
+// dependency is only set for the delta state bundles.
 type Message struct {
-	Destination string `json:"destination"`
-	Key         string `json:"key"`
-	ID          string `json:"id"`
-	MsgType     string `json:"msgType"`
-	Version     string `json:"version"`
-	Payload     []byte `json:"payload"`
+	Destination   string             `json:"destination"`
+	Key           string             `json:"key"`
+	ID            string             `json:"id"`
+	MsgType       string             `json:"msgType"`
+	Version       string             `json:"version"`
+	SchemaVersion int                `json:"schemaVersion,omitempty"`
+	ContentType   string             `json:"contentType,omitempty"`
+	Dependency    *MessageDependency `json:"dependency,omitempty"`
+	Payload       []byte             `json:"payload"`
+}
+
+// MessageDependency is the complete state bundle a delta state bundle is based on, the delta state bundle is only
+// applied on top of the given version of the complete state bundle.
+type MessageDependency struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
 }
 
 type TransportConfig struct {